@@ -0,0 +1,132 @@
+package backtest
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"fmt"
+	"sort"
+)
+
+// bpsScale: returns are integer basis points (1% = 100), like all money math here.
+const bpsScale = 10_000
+
+// SymbolHODL is the buy-and-hold outcome for one instrument.
+type SymbolHODL struct {
+	Market     string            `json:"market"` // "EXCHANGE:SYMBOL"
+	FirstPrice quant.PriceMicros `json:"first_price"`
+	LastPrice  quant.PriceMicros `json:"last_price"`
+	FirstTs    quant.TimeStamp   `json:"first_ts"`
+	LastTs     quant.TimeStamp   `json:"last_ts"`
+	ReturnBps  int64             `json:"return_bps"`
+}
+
+// BenchmarkResult is the HODL baseline per market and for an equal-weight portfolio
+// (capital split evenly across markets at their first price, never rebalanced).
+type BenchmarkResult struct {
+	Markets            []SymbolHODL `json:"markets"`
+	PortfolioReturnBps int64        `json:"portfolio_return_bps"`
+}
+
+// HODLBenchmark records the first and last price of each market it observes.
+// Not goroutine-safe.
+type HODLBenchmark struct {
+	markets map[string]*SymbolHODL
+	only    map[string]bool // nil = every market
+}
+
+// NewHODLBenchmark creates a benchmark. If markets are given ("EXCHANGE:SYMBOL"
+// or bare symbols), only those are tracked.
+func NewHODLBenchmark(markets ...string) *HODLBenchmark {
+	b := &HODLBenchmark{markets: make(map[string]*SymbolHODL)}
+	if len(markets) > 0 {
+		b.only = make(map[string]bool, len(markets))
+		for _, m := range markets {
+			b.only[m] = true
+		}
+	}
+	return b
+}
+
+// Observe feeds one market update.
+func (b *HODLBenchmark) Observe(e *event.MarketUpdateEvent) {
+	if e.PriceMicros <= 0 {
+		return
+	}
+	key := marketKey(e.Exchange, e.Symbol)
+	if b.only != nil && !b.only[key] && !b.only[e.Symbol] {
+		return
+	}
+
+	m := b.markets[key]
+	if m == nil {
+		m = &SymbolHODL{Market: key, FirstPrice: e.PriceMicros, FirstTs: e.Ts}
+		b.markets[key] = m
+	}
+	m.LastPrice = e.PriceMicros
+	m.LastTs = e.Ts
+}
+
+// Result computes returns over the observed period.
+func (b *HODLBenchmark) Result() BenchmarkResult {
+	res := BenchmarkResult{Markets: make([]SymbolHODL, 0, len(b.markets))}
+	var sum int64
+	for _, m := range b.markets {
+		out := *m
+		out.ReturnBps = returnBps(int64(m.FirstPrice), int64(m.LastPrice))
+		sum = safe.SafeAdd(sum, out.ReturnBps)
+		res.Markets = append(res.Markets, out)
+	}
+	sort.Slice(res.Markets, func(i, j int) bool { return res.Markets[i].Market < res.Markets[j].Market })
+	if n := int64(len(res.Markets)); n > 0 {
+		res.PortfolioReturnBps = sum / n
+	}
+	return res
+}
+
+// HODLFromStore computes the benchmark over market updates in [from, to) from a WAL,
+// so live runs get the same baseline as backtests.
+func HODLFromStore(ctx context.Context, store *storage.EventStore, from, to quant.TimeStamp, markets ...string) (BenchmarkResult, error) {
+	b := NewHODLBenchmark(markets...)
+	rows, err := store.DB().QueryContext(ctx,
+		"SELECT payload FROM events WHERE type = ? AND ts >= ? AND ts < ? ORDER BY id ASC",
+		event.EvMarketUpdate, from, to,
+	)
+	if err != nil {
+		return BenchmarkResult{}, fmt.Errorf("failed to query market updates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return BenchmarkResult{}, fmt.Errorf("failed to scan event: %w", err)
+		}
+		ev, err := event.Decode(event.EvMarketUpdate, payload)
+		if err != nil {
+			return BenchmarkResult{}, fmt.Errorf("failed to unmarshal market update: %w", err)
+		}
+		b.Observe(ev.(*event.MarketUpdateEvent))
+	}
+	if err := rows.Err(); err != nil {
+		return BenchmarkResult{}, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return b.Result(), nil
+}
+
+// returnBps = (last - first) / first in basis points.
+func returnBps(first, last int64) int64 {
+	if first <= 0 {
+		return 0
+	}
+	return safe.SafeDiv(safe.SafeMul(safe.SafeSub(last, first), bpsScale), first)
+}
+
+func marketKey(exchange, symbol string) string {
+	if exchange == "" {
+		return symbol
+	}
+	return exchange + ":" + symbol
+}
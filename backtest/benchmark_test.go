@@ -0,0 +1,86 @@
+package backtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+func tick(seq uint64, ts quant.TimeStamp, exchange, symbol string, price quant.PriceMicros) *event.MarketUpdateEvent {
+	return &event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: ts}, Exchange: exchange, Symbol: symbol, PriceMicros: price}
+}
+
+func TestHODLBenchmark(t *testing.T) {
+	b := NewHODLBenchmark()
+	b.Observe(tick(1, 10, "UPBIT", "KRW-BTC", 100_000_000))
+	b.Observe(tick(2, 20, "UPBIT", "KRW-ETH", 50_000_000))
+	b.Observe(tick(3, 30, "UPBIT", "KRW-BTC", 120_000_000)) // +20%
+	b.Observe(tick(4, 40, "UPBIT", "KRW-ETH", 45_000_000))  // -10%
+
+	res := b.Result()
+	if len(res.Markets) != 2 || res.Markets[0].Market != "UPBIT:KRW-BTC" {
+		t.Fatalf("unexpected markets: %+v", res.Markets)
+	}
+	if res.Markets[0].ReturnBps != 2000 || res.Markets[1].ReturnBps != -1000 {
+		t.Errorf("unexpected returns: %+v", res.Markets)
+	}
+	if res.PortfolioReturnBps != 500 {
+		t.Errorf("equal-weight portfolio: want 500 bps, got %d", res.PortfolioReturnBps)
+	}
+
+	// Strategy made +3%: behind holding (+5%)
+	r := NewReport(1_000_000_000, 1_030_000_000, res)
+	if r.ReturnBps != 300 || r.ExcessBps != -200 || r.BeatsHODL {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	if out := r.String(); !strings.Contains(out, "-2.00%") || !strings.Contains(out, "underperformed") {
+		t.Errorf("unexpected rendering:\n%s", out)
+	}
+
+	only := NewHODLBenchmark("KRW-ETH")
+	only.Observe(tick(1, 10, "UPBIT", "KRW-BTC", 1))
+	only.Observe(tick(2, 20, "UPBIT", "KRW-ETH", 1))
+	if got := only.Result().Markets; len(got) != 1 || got[0].Market != "UPBIT:KRW-ETH" {
+		t.Errorf("filter by bare symbol failed: %+v", got)
+	}
+}
+
+func TestHODL_ReplayAndStoreAgree(t *testing.T) {
+	dbPath := t.TempDir() + "/bench.db"
+	store, err := storage.NewEventStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+	for i, price := range []quant.PriceMicros{100, 90, 150} {
+		if err := store.SaveEvent(ctx, tick(uint64(i+1), quant.TimeStamp(i+1), "BITGET_SPOT", "BTCUSDT", price)); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+	live, err := HODLFromStore(ctx, store, 0, 100)
+	store.Close()
+	if err != nil {
+		t.Fatalf("HODLFromStore failed: %v", err)
+	}
+
+	r, err := NewReplayer(dbPath)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	defer r.Close()
+	bench := NewHODLBenchmark()
+	r.SetBenchmark(bench)
+	if err := r.RunReplay(ctx, engine.NewSequencer(10, nil, nil, nil)); err != nil {
+		t.Fatalf("RunReplay failed: %v", err)
+	}
+
+	replayed := bench.Result()
+	if replayed.PortfolioReturnBps != 5000 || live.PortfolioReturnBps != replayed.PortfolioReturnBps {
+		t.Errorf("live %d bps vs replay %d bps, want both 5000", live.PortfolioReturnBps, replayed.PortfolioReturnBps)
+	}
+}
@@ -5,14 +5,14 @@ import (
 	"crypto_go/internal/engine"
 	"crypto_go/internal/event"
 	"crypto_go/internal/storage"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 )
 
 // Replayer reads event logs from SQLite and feeds them into the Sequencer.
 type Replayer struct {
-	store *storage.EventStore
+	store     *storage.EventStore
+	benchmark *HODLBenchmark
 }
 
 // NewReplayer creates a new replayer instance.
@@ -27,6 +27,11 @@ func NewReplayer(dbPath string) (*Replayer, error) {
 	}, nil
 }
 
+// SetBenchmark makes the replay feed every market update to b (HODL baseline).
+func (r *Replayer) SetBenchmark(b *HODLBenchmark) {
+	r.benchmark = b
+}
+
 // Close releases database resources.
 func (r *Replayer) Close() error {
 	if r.store != nil {
@@ -45,6 +50,7 @@ func (r *Replayer) RunReplay(ctx context.Context, seq *engine.Sequencer) error {
 	slog.Info("Starting replay", slog.Int("event_count", len(events)))
 
 	for _, ev := range events {
+		r.observe(ev)
 		seq.ReplayEvent(ev)
 	}
 
@@ -73,28 +79,25 @@ func (r *Replayer) RunReplayRaw(ctx context.Context, seq *engine.Sequencer) erro
 			return err
 		}
 
-		var ev event.Event
-		switch typ {
-		case event.EvMarketUpdate:
-			var m event.MarketUpdateEvent
-			if err := json.Unmarshal(payload, &m); err != nil {
-				return err
-			}
-			ev = &m
-		case event.EvOrderUpdate:
-			var o event.OrderUpdateEvent
-			if err := json.Unmarshal(payload, &o); err != nil {
-				return err
-			}
-			ev = &o
-		default:
+		ev, err := event.Decode(typ, payload)
+		if err != nil {
+			return fmt.Errorf("failed to decode event %d: %w", id, err)
+		}
+		if ev == nil {
 			slog.Warn("Unknown event type in log", slog.Any("type", typ))
 			continue
 		}
 
 		// Feed into sequencer synchronously for deterministic replay.
+		r.observe(ev)
 		seq.ReplayEvent(ev)
 	}
 
 	return nil
 }
+
+func (r *Replayer) observe(ev event.Event) {
+	if m, ok := ev.(*event.MarketUpdateEvent); ok && r.benchmark != nil {
+		r.benchmark.Observe(m)
+	}
+}
@@ -0,0 +1,57 @@
+package backtest
+
+import (
+	"crypto_go/pkg/safe"
+	"fmt"
+	"strings"
+)
+
+// Report compares a strategy's equity change with holding the same markets.
+type Report struct {
+	StartEquityMicros int64           `json:"start_equity"`
+	EndEquityMicros   int64           `json:"end_equity"`
+	ReturnBps         int64           `json:"return_bps"`
+	Benchmark         BenchmarkResult `json:"benchmark"`
+	ExcessBps         int64           `json:"excess_bps"` // Strategy minus portfolio HODL
+	BeatsHODL         bool            `json:"beats_hodl"`
+}
+
+// NewReport builds a report from start/end equity (quote micros) and the benchmark
+// observed over the same period.
+func NewReport(startEquity, endEquity int64, bench BenchmarkResult) Report {
+	ret := returnBps(startEquity, endEquity)
+	excess := safe.SafeSub(ret, bench.PortfolioReturnBps)
+	return Report{
+		StartEquityMicros: startEquity,
+		EndEquityMicros:   endEquity,
+		ReturnBps:         ret,
+		Benchmark:         bench,
+		ExcessBps:         excess,
+		BeatsHODL:         excess > 0,
+	}
+}
+
+// String renders the report as a plain text table.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Strategy        %s\n", formatBps(r.ReturnBps))
+	fmt.Fprintf(&b, "HODL portfolio  %s\n", formatBps(r.Benchmark.PortfolioReturnBps))
+	for _, m := range r.Benchmark.Markets {
+		fmt.Fprintf(&b, "  %-22s %s\n", m.Market, formatBps(m.ReturnBps))
+	}
+	verdict := "underperformed"
+	if r.BeatsHODL {
+		verdict = "outperformed"
+	}
+	fmt.Fprintf(&b, "Excess          %s (%s buy-and-hold)\n", formatBps(r.ExcessBps), verdict)
+	return b.String()
+}
+
+// formatBps renders basis points as a signed percentage, e.g. -1.25%.
+func formatBps(bps int64) string {
+	sign := "+"
+	if bps < 0 {
+		sign, bps = "-", -bps
+	}
+	return fmt.Sprintf("%s%d.%02d%%", sign, bps/100, bps%100)
+}
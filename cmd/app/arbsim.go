@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/app"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+const arbSimUsage = "usage: app arbsim [-days 30] [-entry 3] [-notional 1000] [-transfers BTC=30m:0.0005,XRP=2m:1] [-delay 30m] [-daily-withdraw 0] [-json] [-config path] [-mode paper|real] [-db path]"
+
+// runArbSimCommand replays the recorded premiums of the last -days through
+// the kimchi premium arbitrage lifecycle (see analytics.ArbSimulator) with the
+// config's premium formula and prints each trade's realized capture against
+// the spread it was opened on. Returns the exit code.
+func runArbSimCommand(args []string) int {
+	fs := flag.NewFlagSet("arbsim", flag.ContinueOnError)
+	days := fs.Int("days", 30, "days of history to simulate")
+	entry := fs.String("entry", "3", "premium that opens a trade (%)")
+	notional := fs.String("notional", "1000", "foreign quote spent per trade, fee included")
+	foreignFee := fs.Int64("foreign-fee-bps", 10, "taker fee of the foreign buy (bp)")
+	domesticFee := fs.Int64("domestic-fee-bps", 5, "taker fee of the domestic sell (bp)")
+	transfers := fs.String("transfers", "", "per-symbol transfer delay and withdrawal fee in the coin (SYMBOL=DELAY:FEE,...)")
+	delay := fs.Duration("delay", 30*time.Minute, "transfer delay of symbols missing from -transfers")
+	dailyWithdraw := fs.Int64("daily-withdraw", 0, "KRW withdrawal limit per day (0 = none)")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	configPath := fs.String("config", "", "config of the premium formula (default: configs/config.yaml)")
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, arbSimUsage)
+		return 2
+	}
+	cfg := analytics.ArbSimConfig{
+		EntryMicros:     int64(quant.ToPriceMicrosStr(*entry)) / 100,
+		NotionalMicros:  int64(quant.ToPriceMicrosStr(*notional)),
+		ForeignFeeBps:   *foreignFee,
+		DomesticFeeBps:  *domesticFee,
+		DefaultTransfer: analytics.TransferSpec{Delay: *delay},
+		KRW:             analytics.UpbitKRWTransferPolicy(),
+	}
+	cfg.KRW.DailyWithdrawKRW = *dailyWithdraw
+	specs, err := parseTransferSpecs(*transfers)
+	if err != nil || *days <= 0 || cfg.NotionalMicros <= 0 || *delay < 0 {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid -transfers:", err)
+		}
+		fmt.Fprintln(os.Stderr, arbSimUsage)
+		return 2
+	}
+	cfg.Transfers = specs
+
+	if *configPath == "" {
+		*configPath = infra.ResolveConfigPath()
+	}
+	conf, err := infra.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load config:", err)
+		return 1
+	}
+	formula, err := app.BuildPremiumFormula(conf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	tracker := analytics.NewPremiumTracker(0, 0)
+	tracker.SetFormula(formula)
+	for pair, maxAge := range app.FXMaxAges(conf) {
+		tracker.SetFXMaxAge(pair, maxAge)
+	}
+
+	path := resolveDBPath(*dbPath, *mode)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintln(os.Stderr, "event store not found:", path)
+		return 1
+	}
+	store, err := storage.OpenEventStoreReadOnly(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open event store:", err)
+		return 1
+	}
+	defer store.Close()
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -*days)
+	sim := analytics.NewArbSimulator(cfg, tracker)
+	if err := analytics.SimulateArb(context.Background(), store, quant.TimeStamp(from.UnixMicro()), quant.TimeStamp(to.UnixMicro()), sim); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	report := sim.Report()
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	printArbReport(report, formula)
+	return 0
+}
+
+// parseTransferSpecs parses "BTC=30m:0.0005,XRP=2m:1".
+func parseTransferSpecs(s string) (map[string]analytics.TransferSpec, error) {
+	specs := make(map[string]analytics.TransferSpec)
+	if s == "" {
+		return specs, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		symbol, spec, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || symbol == "" {
+			return nil, fmt.Errorf("%q: want SYMBOL=DELAY:FEE", item)
+		}
+		delayStr, fee, _ := strings.Cut(spec, ":")
+		delay, err := time.ParseDuration(delayStr)
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("%q: invalid delay", item)
+		}
+		specs[strings.ToUpper(symbol)] = analytics.TransferSpec{Delay: delay, FeeSats: int64(quant.ToQtySatsStr(fee))}
+	}
+	return specs, nil
+}
+
+func printArbReport(r analytics.ArbReport, f analytics.PremiumFormula) {
+	fmt.Printf("%s vs %s (%s): %d settled, %d open, %d failed\n", f.Domestic, f.Foreign, f.Price, r.Settled, r.Open, r.Failed)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "OPENED\tSYMBOL\tSTATE\tENTRY %\tEXIT %\tCAPTURE %\tPNL\tHOLD\t")
+	for _, t := range r.Trades {
+		exit, capture, pnl, hold := "-", "-", "-", "-"
+		if t.Sold != 0 {
+			exit = analytics.FormatPct(t.ExitPremiumMicros)
+		}
+		if t.State == analytics.ArbSettled {
+			capture = analytics.FormatPct(t.CaptureMicros)
+			pnl = quant.PriceMicros(t.ReturnMicros - t.CostMicros).String()
+			hold = (time.Duration(t.Settled-t.Opened) * time.Microsecond).Round(time.Minute).String()
+		}
+		state := t.State
+		if t.Error != "" {
+			state += " (" + t.Error + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", time.UnixMicro(int64(t.Opened)).Format(time.DateTime),
+			t.Symbol, state, analytics.FormatPct(t.EntryPremiumMicros), exit, capture, pnl, hold)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if r.Settled > 0 {
+		fmt.Printf("avg spread %s%%, avg capture %s%% (%s%% of theoretical), pnl %s, avg hold %s\n",
+			analytics.FormatPct(r.AvgEntryMicros), analytics.FormatPct(r.AvgCaptureMicros), analytics.FormatPct(r.CaptureRatioBps*100),
+			quant.PriceMicros(r.PnLMicros), (time.Duration(r.AvgHoldMicros) * time.Microsecond).Round(time.Minute))
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"crypto_go/internal/orderbook"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+const bookUsage = "usage: app book -symbol BTC [-exchange UPBIT] [-at RFC3339] [-depth 10] [-mode paper|real] [-db path]"
+
+// runBookCommand rebuilds a recorded L2 order book as of -at (default: now)
+// and prints the ladder. Requires engine.orderbook.enabled to have been on.
+// Returns the exit code.
+func runBookCommand(args []string) int {
+	fs := flag.NewFlagSet("book", flag.ContinueOnError)
+	exchange := fs.String("exchange", "UPBIT", "exchange of the recorded book")
+	symbol := fs.String("symbol", "", "unified symbol (e.g. BTC)")
+	at := fs.String("at", "", "point in time, RFC3339 (default: now)")
+	depth := fs.Int("depth", 10, "levels to print per side")
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *symbol == "" {
+		fmt.Fprintln(os.Stderr, bookUsage)
+		return 2
+	}
+
+	when := time.Now()
+	if *at != "" {
+		parsed, err := time.Parse(time.RFC3339, *at)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid -at:", err)
+			return 2
+		}
+		when = parsed
+	}
+
+	path := resolveDBPath(*dbPath, *mode)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintln(os.Stderr, "event store not found:", path)
+		return 1
+	}
+	store, err := storage.NewEventStore(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open event store:", err)
+		return 1
+	}
+	defer store.Close()
+
+	book, err := orderbook.Reconstruct(context.Background(), store, *exchange, *symbol, quant.TimeStamp(when.UnixMicro()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !book.Synced {
+		fmt.Fprintf(os.Stderr, "no %s %s order book recorded before %s\n", *exchange, *symbol, when.Format(time.RFC3339))
+		return 1
+	}
+
+	fmt.Printf("%s %s as of %s (last update %s)\n", *exchange, *symbol, when.Format(time.RFC3339), formatMicros(int64(book.Ts)))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "BID QTY\tBID\tASK\tASK QTY\t")
+	bids, asks := book.Bids(*depth), book.Asks(*depth)
+	for i := 0; i < max(len(bids), len(asks)); i++ {
+		var bidQty, bid, ask, askQty string
+		if i < len(bids) {
+			bidQty, bid = bids[i].QtySats.String(), bids[i].PriceMicros.String()
+		}
+		if i < len(asks) {
+			ask, askQty = asks[i].PriceMicros.String(), asks[i].QtySats.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", bidQty, bid, ask, askQty)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if spread, ok := book.SpreadMicros(); ok {
+		fmt.Printf("spread: %s\n", spread)
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"crypto_go/internal/app"
+)
+
+// adminAddr is the localhost-only listener shared by pprof and the control endpoint.
+const adminAddr = "localhost:6060"
+
+// runControlCommand handles `app control <COMMAND> [-target t] [-value v] [-reason r]`
+// by posting to the control endpoint of the running instance. Returns the exit code.
+func runControlCommand(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "usage: app control <PAUSE_STRATEGY|RESUME_STRATEGY|SET_RISK_LIMIT|TRIGGER_SNAPSHOT|HALT|RESUME_TRADING|HANDOVER|TAKEOVER|REDUCE_ONLY|LIFT_REDUCE_ONLY|SET_STRATEGY_PARAM|KILL> [-target t] [-value v] [-reason r]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("control", flag.ContinueOnError)
+	target := fs.String("target", "", "strategy name, risk limit key or strategy parameter")
+	value := fs.Int64("value", 0, "risk limit or parameter value")
+	reason := fs.String("reason", "", "audit note recorded in the WAL")
+	addr := fs.String("addr", adminAddr, "admin listener of the running instance")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	body, _ := json.Marshal(app.ControlRequest{
+		Command: strings.ToUpper(args[0]),
+		Target:  *target,
+		Value:   *value,
+		Reason:  *reason,
+	})
+	client := &http.Client{Timeout: 3 * time.Minute} // SET_STRATEGY_PARAM waits for its backfill
+	resp, err := client.Post("http://"+*addr+app.ControlPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to reach running instance:", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		fmt.Fprintf(os.Stderr, "control command rejected (%s): %s\n", resp.Status, strings.TrimSpace(string(msg)))
+		return 1
+	}
+	fmt.Println("accepted:", strings.ToUpper(args[0]))
+	return 0
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"crypto_go/internal/storage"
+)
+
+const deadLetterUsage = "usage: app deadletter <list|show <id>|requeue <id>|drop <id>> [-mode paper|real] [-db path]"
+
+// runDeadLetterCommand inspects the dead letter table of a mode's event store.
+// requeue only marks the letter; the engine reprocesses it at its next start.
+// Returns the exit code.
+func runDeadLetterCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, deadLetterUsage)
+		return 2
+	}
+
+	action, rest := args[0], args[1:]
+	var id int64
+	if action != "list" {
+		if len(rest) == 0 {
+			fmt.Fprintln(os.Stderr, deadLetterUsage)
+			return 2
+		}
+		parsed, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid dead letter id:", rest[0])
+			return 2
+		}
+		id, rest = parsed, rest[1:]
+	}
+
+	fs := flag.NewFlagSet("deadletter", flag.ContinueOnError)
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	if err := fs.Parse(rest); err != nil {
+		return 2
+	}
+	path := resolveDBPath(*dbPath, *mode)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintln(os.Stderr, "event store not found:", path)
+		return 1
+	}
+
+	store, err := storage.NewEventStore(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open event store:", err)
+		return 1
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	switch action {
+	case "list":
+		err = listDeadLetters(ctx, store)
+	case "show":
+		err = showDeadLetter(ctx, store, id)
+	case "requeue":
+		if err = store.RequeueDeadLetter(ctx, id); err == nil {
+			fmt.Printf("dead letter %d marked for requeue (applied at next start)\n", id)
+		}
+	case "drop":
+		if err = store.DeleteDeadLetter(ctx, id); err == nil {
+			fmt.Printf("dead letter %d dropped\n", id)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, deadLetterUsage)
+		return 2
+	}
+
+	if errors.Is(err, storage.ErrDeadLetterNotFound) {
+		fmt.Fprintf(os.Stderr, "dead letter %d not found\n", id)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func listDeadLetters(ctx context.Context, store *storage.EventStore) error {
+	letters, err := store.ListDeadLetters(ctx)
+	if err != nil {
+		return err
+	}
+	if len(letters) == 0 {
+		fmt.Println("no dead letters")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTAGE\tSEQ\tTYPE\tATTEMPTS\tREQUEUED\tCREATED\tERROR")
+	for _, d := range letters {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%t\t%s\t%s\n",
+			d.ID, d.Stage, d.Seq, d.Type, d.Attempts, d.Requeued, formatMicros(d.CreatedAt), d.Error)
+	}
+	return w.Flush()
+}
+
+func showDeadLetter(ctx context.Context, store *storage.EventStore, id int64) error {
+	d, err := store.GetDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("id:       %d\nstage:    %s\nseq:      %d\ntype:     %d\nattempts: %d\nrequeued: %t\ncreated:  %s\nerror:    %s\npayload:  %s\n",
+		d.ID, d.Stage, d.Seq, d.Type, d.Attempts, d.Requeued, formatMicros(d.CreatedAt), d.Error, d.Payload)
+	if d.Stack != "" {
+		fmt.Printf("stack:\n%s\n", d.Stack)
+	}
+	return nil
+}
+
+func formatMicros(us int64) string {
+	return time.UnixMicro(us).UTC().Format(time.RFC3339)
+}
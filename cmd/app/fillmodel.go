@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"crypto_go/internal/orderbook"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+const fillModelUsage = "usage: app fillmodel -symbol BTC [-exchange UPBIT] [-days 7] [-out model.json] [-mode paper|real] [-db path]"
+
+// runFillModelCommand estimates the passive fill probabilities of a venue and
+// symbol from the recorded order books and trades of the last -days, prints
+// them and optionally saves the model (trading.fill_model). Requires
+// engine.orderbook.enabled to have been on. Returns the exit code.
+func runFillModelCommand(args []string) int {
+	fs := flag.NewFlagSet("fillmodel", flag.ContinueOnError)
+	exchange := fs.String("exchange", "UPBIT", "exchange of the recorded book")
+	symbol := fs.String("symbol", "", "unified symbol (e.g. BTC)")
+	days := fs.Int("days", 7, "days of history to estimate from")
+	out := fs.String("out", "", "write the model as JSON to this path")
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *symbol == "" || *days <= 0 {
+		fmt.Fprintln(os.Stderr, fillModelUsage)
+		return 2
+	}
+
+	path := resolveDBPath(*dbPath, *mode)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintln(os.Stderr, "event store not found:", path)
+		return 1
+	}
+	store, err := storage.NewEventStore(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open event store:", err)
+		return 1
+	}
+	defer store.Close()
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -*days)
+	model, err := orderbook.EstimateFills(context.Background(), store, *exchange, *symbol,
+		quant.TimeStamp(from.UnixMicro()), quant.TimeStamp(to.UnixMicro()), orderbook.FillModelConfig{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if model.Samples == 0 {
+		fmt.Fprintf(os.Stderr, "no %s %s order book samples in the last %d days\n", *exchange, *symbol, *days)
+		return 1
+	}
+
+	fmt.Printf("%s %s fill probability (BUY / SELL), %d samples since %s\n", *exchange, *symbol, model.Samples, from.Format(time.RFC3339))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "DISTANCE\t")
+	for _, ms := range model.HorizonsMs {
+		fmt.Fprintf(w, "%s\t", time.Duration(ms)*time.Millisecond)
+	}
+	fmt.Fprintln(w)
+	for d, bps := range model.DistancesBps {
+		fmt.Fprintf(w, "%dbp\t", bps)
+		for h := range model.HorizonsMs {
+			fmt.Fprintf(w, "%s / %s\t", probPct(model.BuyFills[h][d], model.Samples), probPct(model.SellFills[h][d], model.Samples))
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *out != "" {
+		if err := model.Save(*out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println("saved to", *out)
+	}
+	return 0
+}
+
+func probPct(fills, samples int64) string {
+	return fmt.Sprintf("%.1f%%", float64(fills)*100/float64(samples))
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"crypto_go/internal/app"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/storage"
+)
+
+// runFollower is the body of follower mode (engine.follower.enabled): it tails
+// the leader's events.db read-only and serves the read endpoints from a
+// replicated sequencer, so dashboards and analytics never load the trading
+// instance. No gateway is connected and nothing is ever sent to an exchange.
+//
+// With engine.follower.takeover it then takes trading over from the leader
+// (blue/green deployment) and returns the sealed state it verified: bootstrap
+// then holds the trading lock and the leader's WAL, open for writing, and the
+// caller continues as the leader. Otherwise it returns nil when ctx is cancelled.
+func runFollower(ctx context.Context, bootstrap *app.Bootstrap) *engine.HandoverState {
+	cfg := bootstrap.Config
+	fc := cfg.Engine.Follower
+
+	// A follower never trades: leave the credential lock to the leader
+	bootstrap.TradingLock.Release()
+
+	leader, err := storage.OpenEventStoreReadOnly(fc.LeaderDB)
+	if err != nil {
+		slog.Error("❌ Failed to open leader WAL", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer leader.Close()
+
+	strat, err := app.BuildStrategy(cfg)
+	if err != nil {
+		slog.Error("❌ Invalid strategy config", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// No store and no Run loop: every event comes from the leader's WAL
+	seq := engine.NewSequencer(1, nil, strat, func(*domain.MarketState) {})
+	applyStateRules(seq, cfg)
+	if cfg.Engine.OMS.Enabled {
+		// Tracks the leader's orders; replayed submissions are never queued
+		seq.SetOrderManager(newOrderManager(cfg, seq))
+		if router := newRouter(cfg); router != nil {
+			seq.SetRouter(router)
+		}
+	}
+
+	follower := engine.NewFollower(seq, leader, engine.FollowerConfig{
+		PollInterval: time.Duration(fc.PollIntervalMS) * time.Millisecond,
+		BatchSize:    fc.BatchSize,
+	})
+	applied, err := follower.CatchUp(ctx)
+	if err != nil {
+		slog.Error("❌ Failed to catch up with leader WAL", slog.Any("error", err))
+		os.Exit(1)
+	}
+	slog.InfoContext(ctx, "✅ Caught up with leader", slog.String("db", fc.LeaderDB),
+		slog.Int("events", applied), slog.Uint64("next_seq", seq.GetNextSeq()))
+
+	if fc.Takeover {
+		sealed, err := takeOver(ctx, bootstrap, follower)
+		if err != nil {
+			slog.Error("❌ Takeover failed, leader keeps trading", slog.Any("error", err))
+			os.Exit(1)
+		}
+		return &sealed
+	}
+	go follower.Run(ctx)
+
+	// Read-only endpoints (journal notes and control are leader-only: they write)
+	addr := fc.ListenAddr
+	if addr == "" {
+		addr = "localhost:6061"
+	}
+	keyframe := time.Duration(cfg.UI.KeyframeSec) * time.Second
+	if keyframe == 0 {
+		keyframe = 10 * time.Second
+	}
+	mux := http.NewServeMux()
+	mux.Handle(app.MarketsPath, app.NewMarketsHandler(seq))
+	mux.Handle(app.FundingPath, app.NewFundingHandler(seq))
+	mux.Handle(app.RiskPath, app.NewRiskHandler(seq))
+	mux.Handle(app.StreamPath, app.NewStreamHandler(seq, time.Duration(cfg.UI.UpdateIntervalMS)*time.Millisecond, keyframe))
+	mux.Handle(app.JournalPath, app.NewJournalHandler(leader))
+	mux.Handle(app.BenchmarkPath, app.NewBenchmarkHandler(leader))
+	mux.Handle(app.PremiumHeatmapPath, app.NewPremiumHeatmapHandler(leader, bootstrap.PremiumFormula, app.FXMaxAges(cfg)))
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Follower server failed", slog.Any("error", err))
+		}
+	}()
+	defer server.Close()
+	slog.InfoContext(ctx, "👀 Follower mode: serving read endpoints", slog.String("addr", addr))
+
+	<-ctx.Done()
+	slog.InfoContext(ctx, "👋 Follower shutting down")
+	return nil
+}
+
+// takeOverTimeout bounds each takeover phase: the leader's drain, reaching its
+// sealed seq, and the leader's exit releasing the trading lock.
+const takeOverTimeout = 60 * time.Second
+
+// takeOver is the successor side of a handover: the leader drains and seals
+// its WAL, this follower proves it reached the same state, and only then the
+// leader is told to commit. Any failure before the commit aborts, and the
+// leader resumes trading.
+func takeOver(ctx context.Context, bootstrap *app.Bootstrap, follower *engine.Follower) (engine.HandoverState, error) {
+	fc := bootstrap.Config.Engine.Follower
+	addr := fc.LeaderAdmin
+	if addr == "" {
+		addr = adminAddr
+	}
+
+	sealed, err := postHandover(ctx, addr, app.HandoverRequest{Step: app.HandoverPrepare, Instance: bootstrap.InstanceID})
+	if err != nil {
+		return sealed, fmt.Errorf("prepare: %w", err)
+	}
+	slog.InfoContext(ctx, "🔁 Leader sealed its WAL", slog.Uint64("seq", sealed.Seq), slog.Int("in_flight", sealed.InFlight))
+
+	verifyCtx, cancel := context.WithTimeout(ctx, takeOverTimeout)
+	err = follower.Verify(verifyCtx, sealed)
+	cancel()
+	if err != nil {
+		if _, abortErr := postHandover(ctx, addr, app.HandoverRequest{Step: app.HandoverAbort, Reason: err.Error()}); abortErr != nil {
+			slog.Error("Failed to abort handover (leader resumes on its commit timeout)", slog.Any("error", abortErr))
+		}
+		return sealed, err
+	}
+	if _, err := postHandover(ctx, addr, app.HandoverRequest{Step: app.HandoverCommit}); err != nil {
+		return sealed, fmt.Errorf("commit: %w", err)
+	}
+
+	// The leader releases the lock on commit and exits; it writes nothing more
+	deadline := time.Now().Add(takeOverTimeout)
+	for {
+		err := bootstrap.TakeTradingLock()
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, infra.ErrTradingLockHeld) || time.Now().After(deadline) {
+			return sealed, fmt.Errorf("trading lock: %w", err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	wal, err := storage.NewEventStore(fc.LeaderDB)
+	if err != nil {
+		return sealed, fmt.Errorf("failed to open leader WAL for writing: %w", err)
+	}
+	bootstrap.EventStore.Close()
+	bootstrap.EventStore = wal
+	slog.InfoContext(ctx, "✅ Took over from leader", slog.Uint64("seq", sealed.Seq), slog.String("hash", sealed.Hash))
+	return sealed, nil
+}
+
+// postHandover sends one handover step to the leader's admin listener.
+func postHandover(ctx context.Context, addr string, req app.HandoverRequest) (engine.HandoverState, error) {
+	var state engine.HandoverState
+	body, _ := json.Marshal(req)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+addr+app.HandoverPath, bytes.NewReader(body))
+	if err != nil {
+		return state, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: takeOverTimeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return state, fmt.Errorf("failed to reach leader: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		err = json.NewDecoder(resp.Body).Decode(&state)
+	case http.StatusNoContent:
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		err = fmt.Errorf("leader refused %s (%s): %s", req.Step, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return state, err
+}
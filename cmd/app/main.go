@@ -2,116 +2,1030 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
+	"crypto_go/internal/alert"
+	"crypto_go/internal/analytics"
 	"crypto_go/internal/app"
 	"crypto_go/internal/domain"
 	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/execution"
 	"crypto_go/internal/infra"
 	"crypto_go/internal/infra/bitget"
+	"crypto_go/internal/infra/bithumb"
+	"crypto_go/internal/infra/bybit"
+	"crypto_go/internal/infra/coinone"
+	"crypto_go/internal/infra/mqtt"
+	"crypto_go/internal/infra/okx"
 	"crypto_go/internal/infra/upbit"
+	"crypto_go/internal/risk"
+	"crypto_go/internal/storage"
 	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
 
 	_ "net/http/pprof" // For pprof profiling
 )
 
 func main() {
-	// 1. Pprof Server (for performance profiling)
-	go func() {
-		// Localhost only for security
-		slog.Info("🕵️ Pprof server started on localhost:6060")
-		if err := http.ListenAndServe("localhost:6060", nil); err != nil {
-			slog.Error("Pprof server failed", slog.Any("error", err))
+	// Config profile for the run and every command below (app --profile live [command])
+	os.Args = selectProfile(os.Args)
+
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
+	// Graceful Shutdown Context
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	run(ctx)
+}
+
+// command is a subcommand of the binary (app <name> [args]).
+type command struct {
+	name, summary string
+	run           func(args []string) int
+}
+
+// commands are the subcommands, in the order the usage lists them.
+var commands = []command{
+	{"control", "control a running instance (app control HALT -reason ...)", runControlCommand},
+	{"order", "manual orders for a running instance (app order PLACE -symbol BTC -side BUY -qty 0.001)", runOrderCommand},
+	{"migrate", "schema migrations (app migrate status|up|down)", runMigrateCommand},
+	{"deadletter", "dead letter inspection (app deadletter list|show|requeue|drop)", runDeadLetterCommand},
+	{"outages", "engine outages from the WAL heartbeats (app outages -mode real)", runOutagesCommand},
+	{"replay", "deterministic replay of the WAL against recorded snapshots (app replay -template ...)", runReplayCommand},
+	{"book", "order book at a point in time from the WAL (app book -symbol BTC -at ...)", runBookCommand},
+	{"reconcile", "venue statement vs. journal and booked balances (app reconcile -statement file.csv -exchange UPBIT)", runReconcileCommand},
+	{"fillmodel", "passive fill probabilities from the recorded books (app fillmodel -symbol BTC -out ...)", runFillModelCommand},
+	{"arbsim", "kimchi premium round trips on the recorded premiums (app arbsim -days 30 -entry 3)", runArbSimCommand},
+	{"install", "install the OS service (-workdir dir)", serviceCommand("install")},
+	{"uninstall", "remove the OS service", serviceCommand("uninstall")},
+	{"start", "start the OS service", serviceCommand("start")},
+	{"stop", "stop the OS service", serviceCommand("stop")},
+	{"status", "show the OS service status", serviceCommand("status")},
+	{"run", "run as the OS service (used by the service manager)", serviceCommand("run")},
+}
+
+func serviceCommand(verb string) func([]string) int {
+	return func(args []string) int { return runServiceCommand(verb, args) }
+}
+
+// runCommand runs subcommand name. An unknown name prints the usage.
+func runCommand(name string, args []string) int {
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args)
 		}
-	}()
+	}
+	switch name {
+	case "help", "-h", "-help", "--help":
+		printUsage(os.Stdout)
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+	printUsage(os.Stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: app [--profile name] [command] (no command: run the monitor in the foreground)")
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, c.summary)
+	}
+}
+
+// selectProfile takes a leading --profile NAME (or -profile=NAME) off args
+// and makes LoadConfig apply that profile.
+func selectProfile(args []string) []string {
+	if len(args) < 2 {
+		return args
+	}
+	name, rest, ok := strings.Cut(strings.TrimLeft(args[1], "-"), "=")
+	if !strings.HasPrefix(args[1], "-") || name != "profile" {
+		return args
+	}
+	n := 2
+	if !ok {
+		if len(args) < 3 {
+			fmt.Fprintln(os.Stderr, "--profile needs a profile name")
+			os.Exit(2)
+		}
+		rest, n = args[2], 3
+	}
+	infra.SetConfigProfile(rest)
+	return append([]string{args[0]}, args[n:]...)
+}
+
+// run is the monitor body. It returns when ctx is cancelled (signal or service stop).
+func run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx) // Also cancelled after handing over to a successor
+	defer cancel()
 
-	// 2. System Bootstrapping
+	// 1. System Bootstrapping (config, logger, network, storage; see Bootstrap.Initialize)
 	bootstrap := app.NewBootstrap()
-	if err := bootstrap.Initialize(); err != nil {
+	if err := bootstrap.Initialize(ctx); err != nil {
 		slog.Error("❌ Bootstrapping failed", slog.Any("error", err))
 		os.Exit(1)
 	}
 
+	// Read-only replica of another instance: no gateways, no orders. A successor
+	// in a blue/green deployment continues below as the leader once it took over.
+	var tookOver *engine.HandoverState
+	if bootstrap.Config.Engine.Follower.Enabled {
+		if tookOver = runFollower(ctx, bootstrap); tookOver == nil {
+			return
+		}
+	}
+
+	// 2. Pprof Server (for performance profiling) + control endpoint (registered below)
+	go serveAdmin(ctx)
+
 	// 2.1 Display Safety UX (Banner)
 	infra.PrintBanner(bootstrap.Config)
+	if bootstrap.ReadOnly {
+		infra.PrintReadOnlyBanner(bootstrap.InstanceID)
+	}
+	defer bootstrap.TradingLock.Release()
 
-	// 3. Graceful Shutdown Context
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
-	// 4. Background Asset Sync (Simulating Loading Screen logic)
+	// 3. Background Asset Sync (Simulating Loading Screen logic)
 	go bootstrap.SyncAssets(ctx)
 
-	// 5. Initialize Strategy & Sequencer
+	// Symbols hash-routed to N sequencers (monitor-only, see runSharded)
+	if bootstrap.Config.Engine.Shards > 1 {
+		runSharded(ctx, bootstrap)
+		return
+	}
+
+	// 4. Initialize Strategy & Sequencer
 	evStore := bootstrap.EventStore
 
-	// Example Strategy: SMA Cross (3, 5) for BTC-USDT
-	strat := strategy.NewSMACrossStrategy("BTC-USDT", 3, 5)
+	cfg := bootstrap.Config
+	strat, err := app.BuildStrategy(cfg)
+	if err != nil {
+		slog.Error("❌ Invalid strategy config", slog.Any("error", err))
+		os.Exit(1)
+	}
+	inboxSize := cfg.Engine.InboxSize
+	if inboxSize <= 0 {
+		inboxSize = 1024
+	}
 
-	seq := engine.NewSequencer(1024, evStore, strat, func(state *domain.MarketState) {
+	seq := engine.NewSequencer(inboxSize, evStore, strat, func(state *domain.MarketState) {
 		// slog.Info("State changed", slog.String("symbol", state.Symbol), slog.String("price", state.PriceMicros.String()))
 	})
 
+	applyStateRules(seq, cfg)
+
+	// Event payload codec of new records; older JSON records stay readable
+	if err := evStore.SetCodec(cfg.Engine.WAL.Codec); err != nil {
+		slog.Error("❌ Invalid event codec", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// File WAL: durable event log, mirrored into events.db for analytics and followers
+	if w := cfg.Engine.WAL; w.Backend == "file" {
+		fileWAL, err := storage.OpenFileWAL(filepath.Join(bootstrap.DataDir, "wal"), storage.FileWALOptions{
+			SegmentBytes: w.SegmentMB << 20,
+			Sync:         w.Sync,
+			SyncInterval: time.Duration(w.SyncIntervalMS) * time.Millisecond,
+			Codec:        w.Codec,
+		})
+		if err != nil {
+			slog.Error("❌ Failed to open file WAL", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer fileWAL.Sync()
+		eventLog, err := storage.NewMirroredLog(ctx, fileWAL, evStore)
+		if err != nil {
+			slog.Error("❌ Failed to reconcile file WAL with events.db", slog.Any("error", err))
+			os.Exit(1)
+		}
+		seq.SetEventLog(eventLog)
+		slog.InfoContext(ctx, "✅ File WAL enabled", slog.String("sync", fileWAL.SyncPolicy()))
+	}
+
+	// Operator and automatic control commands share one CONTROL sequence
+	control := engine.NewControlClient(seq.CriticalInbox())
+	if h := cfg.Strategy.Backfill.Hours; h > 0 {
+		// Parameter changes are dry-run over recent data first; the report goes into the WAL entry
+		build := func() (strategy.Strategy, error) { return app.BuildStrategy(cfg) }
+		control.SetParamReview(engine.NewBackfiller(evStore, build, time.Duration(h)*time.Hour).Review)
+	}
+
+	// Runtime settings (UI, alerts, risk limits), persisted in the metadata table
+	settings := app.NewSettings(evStore, app.DefaultSettings(cfg))
+	if err := settings.Load(ctx); err != nil {
+		slog.Error("❌ Failed to load settings", slog.Any("error", err))
+		os.Exit(1)
+	}
+	if cfg.Engine.Risk.Enabled {
+		watchRiskSettings(ctx, settings, control)
+	}
+
+	// Strategy time budget: a slow strategy is flagged (and optionally paused via the WAL)
+	if b := cfg.Strategy.Budget; b.PerEventUS > 0 {
+		var onDisable func(string)
+		if b.AutoDisable {
+			onDisable = func(name string) {
+				// Off the hotpath: the inbox send may block
+				go func() {
+					if err := control.Send(ctx, event.CmdPauseStrategy, name, 0, "auto: strategy time budget exceeded"); err != nil {
+						slog.Error("Failed to pause slow strategy", slog.String("strategy", name), slog.Any("error", err))
+					}
+				}()
+			}
+		}
+		seq.SetStrategyBudget(engine.NewStrategyBudget(app.StrategyName(cfg), time.Duration(b.PerEventUS)*time.Microsecond, b.MaxStrikes, infra.GlobalMetrics, onDisable))
+	}
+
+	// Kill switch: automatic triggers go through the WAL like a manual KILL
+	kill := func(reason, detail string) {
+		// Off the hotpath: the inbox send may block
+		go func() {
+			if err := control.Kill(ctx, reason, detail); err != nil {
+				slog.Error("Failed to trigger kill switch", slog.String("reason", reason), slog.Any("error", err))
+			}
+		}()
+	}
+	if limit := cfg.Engine.KillSwitch.DrawdownLimit; limit > 0 {
+		seq.SetDrawdownHalt(limit, func(drawdown int64) {
+			kill(event.HaltDrawdown, fmt.Sprintf("drawdown %d reached limit %d", drawdown, limit))
+		})
+	}
+
+	// Monitor-only when another instance holds the trading lock
+	if bootstrap.ReadOnly {
+		seq.SetTradingEnabled(false)
+	}
+
+	// Callbacks sharing the sequencer's market observer (registered below)
+	var marketObservers []func(event.MarketUpdateEvent)
+
+	// Order management: accepted signals become client orders, placed off the hotpath
+	if o := cfg.Engine.OMS; o.Enabled && !bootstrap.ReadOnly {
+		orderSeq := new(uint64) // ORDER source: live order updates and dispatcher outcomes
+		factory := execution.NewExecutionFactory(cfg)
+		factory.SetEventSink(seq.CriticalInbox(), orderSeq)
+		exec, err := factory.CreateExecution()
+		if err != nil {
+			slog.Error("❌ Failed to create execution", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer exec.Close()
+
+		venue := "BITGET"
+		if execution.Mode(cfg.Trading.Mode) == execution.ModePaper {
+			venue = "PAPER"
+		}
+		oms := newOrderManager(cfg, seq)
+		oms.RecordRefusals(orderSeq) // Queue-full refusals go through the WAL
+		seq.SetOrderManager(oms)
+		dispatcher := execution.NewDispatcher(exec, venue, seq.CriticalInbox(), orderSeq)
+		var routedVenues []string
+		if router := newRouter(cfg); router != nil {
+			seq.SetRouter(router)
+			for _, v := range cfg.Engine.Router.Venues {
+				// Paper simulates every venue; live execution exists for Bitget only
+				if venue == "PAPER" || strings.HasPrefix(v.Exchange, "BITGET") {
+					dispatcher.AddVenue(v.Exchange, exec)
+					routedVenues = append(routedVenues, v.Exchange)
+				}
+			}
+			slog.InfoContext(ctx, "✅ Order router enabled", slog.Int("venues", len(cfg.Engine.Router.Venues)))
+		}
+		// Funding harvest legs and DCA buys name their venue; live execution exists for Bitget only
+		strategyVenues := app.DCAVenues(cfg)
+		if cfg.Strategy.FundingHarvest.Enabled {
+			spot, perp := app.FundingHarvestVenues(cfg)
+			strategyVenues = append(strategyVenues, spot, perp)
+		}
+		for _, v := range strategyVenues {
+			if venue == "PAPER" || strings.HasPrefix(v, "BITGET") {
+				dispatcher.AddVenue(v, exec)
+				routedVenues = append(routedVenues, v)
+			}
+		}
+		// Paper fills follow live prices, applied off the hotpath: fills are
+		// reported to the critical inbox with a blocking send
+		if paper, ok := exec.(*execution.PaperExecution); ok {
+			feed := newPaperFeed(cfg, paper, routedVenues)
+			marketObservers = append(marketObservers, feed.Observe)
+			go feed.Run(ctx)
+		}
+		// Live fills and venue-confirmed cancels are polled by clientOid
+		if live, ok := exec.(*execution.LiveExecution); ok && live.EmitsOrderUpdates() {
+			interval := execution.DefaultLivePollInterval
+			if o.FillPollMs > 0 {
+				interval = time.Duration(o.FillPollMs) * time.Millisecond
+			}
+			go live.Run(ctx, interval)
+		}
+		// Outcomes go straight to the sequencer (blocking send, never spilled or dropped)
+		go dispatcher.Run(ctx, oms.Requests())
+		go dispatcher.Run(ctx, oms.Cancels()) // Kill switch cancels never queue behind orders
+		slog.InfoContext(ctx, "✅ OrderManager started", slog.String("venue", venue))
+	}
+
+	// Sequence gap detection: each gateway owns its seq counter, so gaps are per source
+	gapReport, err := infra.NewGapReport(filepath.Join(bootstrap.LogDir, "gaps.jsonl"), infra.GlobalMetrics)
+	if err != nil {
+		slog.Error("❌ Failed to open gap report", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer gapReport.Close()
+	go gapReport.Run(ctx.Done())
+	seq.EnableSequenceValidation(gapReport)
+
+	seq.SetGapPolicy(bootstrap.GapPolicy)
+
+	// Daily exposure and VaR of the positions at every UTC midnight
+	if cfg.Engine.Risk.Enabled {
+		riskReport, err := infra.NewRiskReport(filepath.Join(bootstrap.LogDir, "risk.jsonl"), seq)
+		if err != nil {
+			slog.Error("❌ Failed to open risk report", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer riskReport.Close()
+		go riskReport.Run(ctx.Done())
+	}
+
+	// Poison events go to the dead letter table instead of halting the engine
+	if dl := cfg.Engine.DeadLetter; dl.MaxAttempts > 0 {
+		seq.SetDeadLetterPolicy(dl.MaxAttempts, time.Duration(dl.RetryBackoffMS)*time.Millisecond)
+	}
+
+	// GapResync: dispatch pauses until the offending gateway is reconnected and its
+	// REST snapshots are in (gateways are registered below as workers start)
+	resyncer := app.NewResyncer(ctx, engine.NewResyncClient(seq.Inbox()))
+	seq.SetResyncHandler(func(source string) {
+		resyncer.Resync(source)
+		if cfg.Engine.KillSwitch.OnGapResync {
+			kill(event.HaltSequenceGap, "resync of "+source)
+		}
+	})
+
+	// Control events: TriggerSnapshot writes here
+	snapshots := storage.NewSnapshotManager(filepath.Join(bootstrap.DataDir, "snapshots"))
+	snapshots.SetRetention(cfg.Engine.Snapshot.Keep)
+	seq.SetSnapshotManager(snapshots)
+	seq.SetFastRecovery(cfg.Engine.Snapshot.FastRecovery)
+	// Periodic snapshots are requested through the WAL like a manual TRIGGER_SNAPSHOT
+	if every := cfg.Engine.Snapshot.EveryEvents; every > 0 {
+		seq.SetSnapshotInterval(every, func() {
+			// Off the hotpath: the inbox send may block
+			go func() {
+				if err := control.Send(ctx, event.CmdTriggerSnapshot, "", 0, "auto: periodic snapshot"); err != nil {
+					slog.Error("Failed to request snapshot", slog.Any("error", err))
+				}
+			}()
+		})
+	}
+
+	// Systemd supervision (no-op when not started by systemd)
+	notifier := infra.NewSystemdNotifier()
+	if interval := notifier.WatchdogInterval(); interval > 0 {
+		seq.SetWatchdog(notifier, interval)
+		slog.Info("🐶 Systemd watchdog enabled", slog.Duration("interval", interval))
+	}
+
+	// MQTT mirror of prices, premiums and alerts for home dashboards (off the hotpath)
+	var mqttPub *app.MQTTPublisher
+	if cfg.UI.MQTT.Enabled {
+		mqttPub = newMQTTPublisher(bootstrap)
+		marketObservers = append(marketObservers, mqttPub.Observe)
+		go mqttPub.Run(ctx)
+		slog.Info("📡 MQTT publisher enabled", slog.String("broker", cfg.UI.MQTT.Broker))
+	}
+
+	// Alert rules: conditions over live prices and premiums, actions sent as control events
+	if len(cfg.Alerts.Rules) > 0 {
+		rules, err := app.BuildAlertRules(cfg)
+		if err != nil {
+			slog.Error("❌ Invalid alert rules", slog.Any("error", err))
+			os.Exit(1)
+		}
+		tracker := analytics.NewPremiumTracker(0, 0)
+		tracker.SetFormula(bootstrap.PremiumFormula)
+		for pair, maxAge := range app.FXMaxAges(cfg) {
+			tracker.SetFXMaxAge(pair, maxAge)
+		}
+		monitor := alert.NewMonitor(rules, control, app.StrategyName(cfg), tracker)
+		if mqttPub != nil {
+			monitor.SetNotifier(mqttPub.Alert)
+		}
+		marketObservers = append(marketObservers, monitor.Observe)
+		interval := time.Duration(cfg.Alerts.IntervalMS) * time.Millisecond
+		if interval <= 0 {
+			interval = time.Second
+		}
+		go monitor.Run(ctx, interval)
+		slog.InfoContext(ctx, "✅ Alert rules enabled", slog.Int("rules", len(rules)))
+	}
+	if len(marketObservers) > 0 {
+		seq.SetMarketObserver(func(e event.MarketUpdateEvent) {
+			for _, observe := range marketObservers {
+				observe(e)
+			}
+		})
+	}
+
+	// Signal-only mode: accepted strategy orders go to external executors instead
+	// of the OMS, on a listener of their own like the signal webhook
+	if ss := cfg.API.SignalStream; ss.Enabled {
+		addr := ss.ListenAddr
+		if addr == "" {
+			addr = "127.0.0.1:8091"
+		}
+		signals := app.NewSignalBroadcaster(ss.Buffer)
+		seq.SetOrderObserver(signals.Publish)
+		mux := http.NewServeMux()
+		mux.Handle(app.SignalStreamPath, app.NewSignalStreamHandler(signals, ss.Token))
+		streamServer := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := streamServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Signal stream server failed", slog.Any("error", err))
+			}
+		}()
+		defer streamServer.Close()
+		slog.InfoContext(ctx, "✅ Signal stream ready", slog.String("addr", addr+app.SignalStreamPath))
+	}
+
+	// Pre-funding warnings of perpetuals (engine.funding.warn_before_min)
+	if cfg.Engine.Funding.WarnBeforeMin > 0 {
+		seq.SetFundingObserver(func(f domain.FundingEpoch) {
+			in := time.Duration(f.Until(quant.TimeStamp(time.Now().UnixMicro()))) * time.Microsecond
+			slog.Warn("FUNDING_SOON", slog.String("exchange", f.Exchange), slog.String("symbol", f.Symbol),
+				slog.Int64("rate", f.RateMicros), slog.Duration("in", in.Round(time.Second)))
+			if mqttPub != nil {
+				mqttPub.Alert("FUNDING_SOON", fmt.Sprintf("[%s %s] funding %s%% in %s", f.Exchange, f.Symbol,
+					analytics.FormatPct(f.RateMicros), in.Round(time.Minute)))
+			}
+		})
+	}
+
 	// Recover sequence and state from the WAL
 	if err := seq.RecoverFromWAL(ctx); err != nil {
 		slog.Error("❌ Failed to recover from WAL", slog.Any("error", err))
 		os.Exit(1)
 	}
+	// A successor must have rebuilt exactly the state it verified as a follower
+	if tookOver != nil && seq.StateHash() != tookOver.Hash {
+		slog.Error("❌ Recovered state differs from the verified handover state",
+			slog.Uint64("seq", tookOver.Seq), slog.String("expected", tookOver.Hash), slog.String("got", seq.StateHash()))
+		os.Exit(1)
+	}
 
 	// Start Sequencer in its own goroutine (The Hotpath Loop)
 	go seq.Run(ctx)
 	slog.InfoContext(ctx, "✅ Sequencer (Hotpath) started")
+	startTimers(ctx, cfg, seq.Inbox())
+	startHeartbeat(ctx, cfg, seq.Inbox(), bootstrap.InstanceID)
 
-	cfg := bootstrap.Config
-	nextSeq := uint64(1)
+	if !bootstrap.ReadOnly {
+		// The WAL ends in a HANDOVER: the previous leader sealed it for us
+		if seq.HandoverPending() {
+			if err := control.Send(ctx, event.CmdTakeover, bootstrap.InstanceID, 0, "handover: new leader"); err != nil {
+				slog.Error("❌ Failed to record TAKEOVER", slog.Any("error", err))
+				os.Exit(1)
+			}
+			slog.InfoContext(ctx, "✅ Took over trading", slog.String("instance", bootstrap.InstanceID))
+		}
+
+		startEndOfDay(ctx, cfg, control)
+
+		// Blue/green handover: a successor follower seals this WAL and takes trading over
+		hc := cfg.Engine.Handover
+		handover := engine.NewHandover(seq, control, func() { go seq.Run(ctx) }, func() {
+			bootstrap.TradingLock.Release()
+			slog.Warn("👋 Handed over to successor, shutting down")
+			cancel()
+		}, engine.HandoverConfig{
+			DrainTimeout:  time.Duration(hc.DrainTimeoutSec) * time.Second,
+			CommitTimeout: time.Duration(hc.CommitTimeoutSec) * time.Second,
+		})
+		http.Handle(app.HandoverPath, app.NewHandoverHandler(handover))
+	}
+
+	// Operator commands: `app control <COMMAND>` posts here; events go straight to the
+	// sequencer inbox (blocking send, never spilled or dropped) and are recorded in the WAL.
+	http.Handle(app.ControlPath, app.NewControlHandler(control))
+	// Manual orders (`app order <ACTION>`): token-protected even on the local listener
+	if o := cfg.API.Orders; o.Enabled {
+		http.Handle(app.OrdersPath, app.NewOrderHandler(control, o.Token))
+	}
+	http.Handle(app.SettingsPath, app.NewSettingsHandler(settings))
+	http.Handle(app.FavoritesPath, app.NewFavoritesHandler(evStore))
+	// Journal notes and export (read/write the event store directly, off the hotpath)
+	http.Handle(app.AnnotationsPath, app.NewAnnotationsHandler(evStore))
+	http.Handle(app.JournalPath, app.NewJournalHandler(evStore))
+	http.Handle(app.BenchmarkPath, app.NewBenchmarkHandler(evStore))
+	http.Handle(app.PremiumHeatmapPath, app.NewPremiumHeatmapHandler(evStore, bootstrap.PremiumFormula, app.FXMaxAges(cfg)))
+	// External consumers page through the event log from a cursor kept in events.db (ack after processing)
+	events := app.NewEventsHandler(evStore)
+	http.Handle(app.EventsPath, events)
+	http.Handle(app.EventsAckPath, events)
+	// All market states from one sequence point (read lock only)
+	http.Handle(app.MarketsPath, app.NewMarketsHandler(seq))
+	http.Handle(app.FundingPath, app.NewFundingHandler(seq))
+	http.Handle(app.RiskPath, app.NewRiskHandler(seq))
+	keyframe := time.Duration(cfg.UI.KeyframeSec) * time.Second
+	if keyframe == 0 {
+		keyframe = 10 * time.Second
+	}
+	http.Handle(app.StreamPath, app.NewStreamHandler(seq, time.Duration(cfg.UI.UpdateIntervalMS)*time.Millisecond, keyframe))
+	slog.InfoContext(ctx, "✅ Control endpoint ready", slog.String("addr", adminAddr+app.ControlPath))
+
+	// Gateway inbox: either the sequencer directly (drop on overflow) or a disk-backed spillover
+	inbox := seq.Inbox()
+	if cfg.Engine.Spillover.Enabled {
+		spillQueue, err := storage.NewSpillQueue(filepath.Join(bootstrap.DataDir, "inbox.spill"), cfg.Engine.Spillover.MaxMB<<20)
+		if err != nil {
+			slog.Error("❌ Failed to open spill queue", slog.Any("error", err))
+			os.Exit(1)
+		}
+		spillover := engine.NewSpilloverInbox(inboxSize, seq.Inbox(), spillQueue)
+		go spillover.Run(ctx) // Owns and closes spillQueue
+		inbox = spillover.Inbox()
+		slog.InfoContext(ctx, "✅ Inbox spillover enabled", slog.Int64("max_mb", cfg.Engine.Spillover.MaxMB))
+	}
+
+	// Market data workers: a lock-free lane of their own instead of the shared channel
+	attachOutbox := func(w marketWorker) {
+		if n := cfg.Engine.InboxRing; n > 0 {
+			w.SetLane(seq.NewRingInbox(n))
+		}
+		w.SetBackpressure(backpressure(cfg))
+	}
 
 	// Exchange Rate Client (Gateway) - Uses config for URL and poll interval
 	exchangeRateClient := infra.NewExchangeRateClientWithConfig(
-		seq.Inbox(), &nextSeq,
+		inbox, new(uint64),
 		cfg.API.ExchangeRate.URL,
 		cfg.API.ExchangeRate.PollIntervalSec,
 	)
+	for _, p := range cfg.API.ExchangeRate.Pairs {
+		exchangeRateClient.AddPair(p.Symbol, p.URL, p.PollIntervalSec)
+	}
 	if err := exchangeRateClient.Start(ctx); err != nil {
 		slog.Error("Failed to start exchange rate client", slog.Any("error", err))
 	}
 	defer exchangeRateClient.Stop()
 
-	// 6. Upbit/Bitget Workers (Modular Gateways)
+	// On-chain context metrics (daily; own ONCHAIN sequence)
+	if oc := cfg.API.OnChain; oc.Enabled {
+		onChainClient := infra.NewOnChainClient(inbox, new(uint64), infra.OnChainConfig{
+			StablecoinURL: oc.StablecoinURL,
+			ExchangeURL:   oc.ExchangeURL,
+			Exchanges:     oc.Exchanges,
+			PollInterval:  time.Duration(oc.PollIntervalSec) * time.Second,
+		})
+		if err := onChainClient.Start(ctx); err != nil {
+			slog.Error("Failed to start on-chain client", slog.Any("error", err))
+		}
+		defer onChainClient.Stop()
+		slog.InfoContext(ctx, "✅ OnChainClient started", slog.Int("exchanges", len(oc.Exchanges)))
+	}
+
+	// Sentiment indices (Fear & Greed, funding; own SENTIMENT sequence)
+	if st := cfg.API.Sentiment; st.Enabled {
+		sentimentCfg := infra.SentimentConfig{
+			FearGreedURL: st.FearGreedURL,
+			FundingURL:   st.FundingURL,
+			PollInterval: time.Duration(st.PollIntervalSec) * time.Second,
+		}
+		if st.Funding {
+			for _, instID := range cfg.API.Bitget.Symbols {
+				sentimentCfg.FundingSymbols = append(sentimentCfg.FundingSymbols, instID)
+			}
+		}
+		sentimentClient := infra.NewSentimentClient(inbox, new(uint64), sentimentCfg)
+		if err := sentimentClient.Start(ctx); err != nil {
+			slog.Error("Failed to start sentiment client", slog.Any("error", err))
+		}
+		defer sentimentClient.Stop()
+		slog.InfoContext(ctx, "✅ SentimentClient started", slog.Bool("funding", st.Funding))
+	}
+
+	// Upbit announcements (listing/delisting/wallet; own UPBIT_NOTICE sequence)
+	if nt := cfg.API.Notices; nt.Enabled {
+		var webhook *infra.WebhookNotifier
+		if nt.WebhookURL != "" {
+			webhook = infra.NewWebhookNotifier(nt.WebhookURL)
+		}
+		noticeClient := upbit.NewNoticeClient(inbox, new(uint64), upbit.NoticeConfig{
+			URL:          nt.URL,
+			PollInterval: time.Duration(nt.PollIntervalSec) * time.Second,
+			Notify: func(n domain.Notice) {
+				slog.Warn("EXCHANGE_NOTICE", slog.String("exchange", n.Exchange), slog.String("kind", n.Kind),
+					slog.String("title", n.Title), slog.Any("symbols", n.Symbols))
+				if !settings.GetBool(app.SettingNoticeAlerts) {
+					return
+				}
+				if mqttPub != nil {
+					mqttPub.Alert("NOTICE_"+n.Kind, "["+n.Exchange+" "+n.Kind+"] "+n.Title)
+				}
+				if webhook != nil {
+					if err := webhook.Send(ctx, "["+n.Exchange+" "+n.Kind+"] "+n.Title); err != nil {
+						slog.Warn("Notice webhook failed", slog.Any("error", err))
+					}
+				}
+			},
+		})
+		if err := noticeClient.Start(ctx); err != nil {
+			slog.Error("Failed to start notice client", slog.Any("error", err))
+		}
+		defer noticeClient.Stop()
+		slog.InfoContext(ctx, "✅ NoticeClient started", slog.Bool("webhook", webhook != nil))
+	}
+
+	// External signal webhooks (own SIGNAL sequence). Served on a separate listener:
+	// the admin one exposes unauthenticated control and must stay local.
+	if sg := cfg.API.Signals; sg.Enabled {
+		addr := sg.ListenAddr
+		if addr == "" {
+			addr = "127.0.0.1:8090"
+		}
+		mux := http.NewServeMux()
+		mux.Handle(app.SignalsPath, app.NewSignalHandler(engine.NewSignalClient(seq.Inbox()), sg.Token))
+		signalServer := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := signalServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Signal webhook server failed", slog.Any("error", err))
+			}
+		}()
+		defer signalServer.Close()
+		slog.InfoContext(ctx, "✅ Signal webhook ready", slog.String("addr", addr+app.SignalsPath))
+	}
+
+	// 5. Exchange Workers (Modular Gateways)
+	disconnectWorkers := startMarketWorkers(ctx, cfg, inbox, attachOutbox, resyncer.Register)
+	defer disconnectWorkers()
+	// Resyncs cut short by the last shutdown were recovered as pausing dispatch
+	for _, source := range seq.Resyncing() {
+		resyncer.Resync(source)
+	}
+
+	slog.InfoContext(ctx, "✨ Quant System fully operational. Press Ctrl+C to exit.")
+	if err := notifier.Ready(); err != nil {
+		slog.Warn("Failed to notify systemd readiness", slog.Any("error", err))
+	}
+	// Like systemd's own watchdog, stall detection starts with READY=1: WAL
+	// recovery runs before the hotpath loop pings, however long it takes
+	go notifier.Supervise(ctx.Done(), notifier.StallThreshold())
+
+	// Wait for shutdown signal
+	<-ctx.Done()
+
+	slog.InfoContext(ctx, "👋 Shutting down gracefully...")
+	notifier.Stopping()
+}
+
+// marketWorker is the outbox setup every market data worker offers.
+type marketWorker interface {
+	SetLane(infra.EventLane)
+	SetBackpressure(infra.Backpressure)
+}
+
+// backpressure returns the gateway outbox settings of engine.backpressure.
+func backpressure(cfg *infra.Config) infra.Backpressure {
+	bp := cfg.Engine.Backpressure
+	return infra.Backpressure{HighPct: bp.HighPct, LowPct: bp.LowPct, Conflate: bp.Conflate}
+}
+
+// startMarketWorkers connects the exchange WebSocket gateways of api.* to
+// inbox (attachOutbox sets up each one's lane and backpressure) and registers
+// how each one resyncs. The returned func disconnects them.
+func startMarketWorkers(ctx context.Context, cfg *infra.Config, inbox chan<- event.Event, attachOutbox func(marketWorker), registerResync func(string, app.ResyncSource)) func() {
+	var disconnect []func()
 	if len(cfg.API.Upbit.Symbols) > 0 {
-		upbitWorker := upbit.NewWorker(cfg.API.Upbit.Symbols, seq.Inbox(), &nextSeq)
+		upbitWorker := upbit.NewWorker(cfg.API.Upbit.Symbols, inbox, new(uint64))
+		if cfg.Engine.OrderBook.Enabled {
+			upbitWorker.SetOrderBookDepth(cfg.Engine.OrderBook.Depth)
+		}
+		upbitWorker.SetTrades(cfg.Engine.Trades.Enabled)
+		upbitWorker.SetCandleInterval(cfg.Engine.Candles.Interval)
+		upbitWorker.SetRestURL(cfg.API.Upbit.RestURL)
+		attachOutbox(upbitWorker)
 		if err := upbitWorker.Connect(ctx); err != nil {
 			slog.Error("Failed to connect Upbit", slog.Any("error", err))
 		}
-		defer upbitWorker.Disconnect()
+		disconnect = append(disconnect, upbitWorker.Disconnect)
+		registerResync(upbitWorker.ID(), app.ResyncSource{Reconnect: upbitWorker.Resync, Snapshot: upbitWorker.Snapshot})
 		slog.InfoContext(ctx, "✅ UpbitWorker started", slog.Int("symbols", len(cfg.API.Upbit.Symbols)))
 	}
 
+	// Additional KRW venues (premium comparison across Korean exchanges)
+	if len(cfg.API.Bithumb.Symbols) > 0 {
+		bithumbWorker := bithumb.NewWorker(cfg.API.Bithumb.WSURL, cfg.API.Bithumb.Symbols, inbox, new(uint64))
+		attachOutbox(bithumbWorker)
+		if err := bithumbWorker.Connect(ctx); err != nil {
+			slog.Error("Failed to connect Bithumb", slog.Any("error", err))
+		}
+		disconnect = append(disconnect, bithumbWorker.Disconnect)
+		registerResync(bithumbWorker.ID(), app.ResyncSource{Reconnect: bithumbWorker.Resync})
+		slog.InfoContext(ctx, "✅ BithumbWorker started", slog.Int("symbols", len(cfg.API.Bithumb.Symbols)))
+	}
+
+	if len(cfg.API.Coinone.Symbols) > 0 {
+		coinoneWorker := coinone.NewWorker(cfg.API.Coinone.WSURL, cfg.API.Coinone.Symbols, inbox, new(uint64))
+		attachOutbox(coinoneWorker)
+		if err := coinoneWorker.Connect(ctx); err != nil {
+			slog.Error("Failed to connect Coinone", slog.Any("error", err))
+		}
+		disconnect = append(disconnect, coinoneWorker.Disconnect)
+		registerResync(coinoneWorker.ID(), app.ResyncSource{Reconnect: coinoneWorker.Resync})
+		slog.InfoContext(ctx, "✅ CoinoneWorker started", slog.Int("symbols", len(cfg.API.Coinone.Symbols)))
+	}
+
 	if len(cfg.API.Bitget.Symbols) > 0 {
 		// Spot
-		bitgetSpotWorker := bitget.NewSpotWorker(cfg.API.Bitget.Symbols, seq.Inbox(), &nextSeq)
+		bitgetSpotWorker := bitget.NewSpotWorker(cfg.API.Bitget.Symbols, inbox, new(uint64))
+		if cfg.Engine.OrderBook.Enabled {
+			bitgetSpotWorker.SetOrderBookDepth(cfg.Engine.OrderBook.Depth)
+		}
+		bitgetSpotWorker.SetTrades(cfg.Engine.Trades.Enabled)
+		bitgetSpotWorker.SetCandleInterval(cfg.Engine.Candles.Interval)
+		attachOutbox(bitgetSpotWorker)
 		if err := bitgetSpotWorker.Connect(ctx); err != nil {
 			slog.Error("Failed to connect Bitget Spot", slog.Any("error", err))
 		}
-		defer bitgetSpotWorker.Disconnect()
+		disconnect = append(disconnect, bitgetSpotWorker.Disconnect)
+		registerResync(bitgetSpotWorker.ID(), app.ResyncSource{Reconnect: bitgetSpotWorker.Resync})
 		slog.InfoContext(ctx, "✅ BitgetSpotWorker started")
 
 		// Futures
-		bitgetFuturesWorker := bitget.NewFuturesWorker(cfg.API.Bitget.Symbols, seq.Inbox(), &nextSeq)
+		bitgetFuturesWorker := bitget.NewFuturesWorker(cfg.API.Bitget.Symbols, inbox, new(uint64))
+		if cfg.Engine.OrderBook.Enabled {
+			bitgetFuturesWorker.SetOrderBookDepth(cfg.Engine.OrderBook.Depth)
+		}
+		bitgetFuturesWorker.SetTrades(cfg.Engine.Trades.Enabled)
+		bitgetFuturesWorker.SetCandleInterval(cfg.Engine.Candles.Interval)
+		attachOutbox(bitgetFuturesWorker)
 		if err := bitgetFuturesWorker.Connect(ctx); err != nil {
 			slog.Error("Failed to connect Bitget Futures", slog.Any("error", err))
 		}
-		defer bitgetFuturesWorker.Disconnect()
+		disconnect = append(disconnect, bitgetFuturesWorker.Disconnect)
+		registerResync(bitgetFuturesWorker.ID(), app.ResyncSource{Reconnect: bitgetFuturesWorker.Resync})
 		slog.InfoContext(ctx, "✅ BitgetFuturesWorker started")
 	}
 
-	slog.InfoContext(ctx, "✨ Quant System fully operational. Press Ctrl+C to exit.")
+	if len(cfg.API.OKX.Symbols) > 0 {
+		okxWorkers := []*okx.Worker{okx.NewSpotWorker(cfg.API.OKX.WSURL, cfg.API.OKX.Symbols, inbox, new(uint64))}
+		if cfg.API.OKX.Swap {
+			okxWorkers = append(okxWorkers, okx.NewSwapWorker(cfg.API.OKX.WSURL, cfg.API.OKX.Symbols, inbox, new(uint64)))
+		}
+		for _, w := range okxWorkers {
+			attachOutbox(w)
+			if err := w.Connect(ctx); err != nil {
+				slog.Error("Failed to connect OKX", slog.String("gateway", w.ID()), slog.Any("error", err))
+			}
+			disconnect = append(disconnect, w.Disconnect)
+			registerResync(w.ID(), app.ResyncSource{Reconnect: w.Resync})
+			slog.InfoContext(ctx, "✅ OKX worker started", slog.String("gateway", w.ID()), slog.Int("symbols", len(cfg.API.OKX.Symbols)))
+		}
+	}
 
-	// Wait for shutdown signal
-	<-ctx.Done()
+	if len(cfg.API.Bybit.Symbols) > 0 {
+		bybitWorkers := []*bybit.Worker{bybit.NewSpotWorker(cfg.API.Bybit.SpotWSURL, cfg.API.Bybit.Symbols, inbox, new(uint64))}
+		if cfg.API.Bybit.Linear {
+			bybitWorkers = append(bybitWorkers, bybit.NewLinearWorker(cfg.API.Bybit.LinearWSURL, cfg.API.Bybit.Symbols, inbox, new(uint64)))
+		}
+		for _, w := range bybitWorkers {
+			attachOutbox(w)
+			if err := w.Connect(ctx); err != nil {
+				slog.Error("Failed to connect Bybit", slog.String("gateway", w.ID()), slog.Any("error", err))
+			}
+			disconnect = append(disconnect, w.Disconnect)
+			registerResync(w.ID(), app.ResyncSource{Reconnect: w.Resync})
+			slog.InfoContext(ctx, "✅ Bybit worker started", slog.String("gateway", w.ID()), slog.Int("symbols", len(cfg.API.Bybit.Symbols)))
+		}
+	}
 
-	slog.InfoContext(ctx, "👋 Shutting down gracefully...")
+	return func() {
+		for i := len(disconnect) - 1; i >= 0; i-- {
+			disconnect[i]()
+		}
+	}
+}
+
+// newMQTTPublisher builds the MQTT mirror of ui.mqtt.
+func newMQTTPublisher(bootstrap *app.Bootstrap) *app.MQTTPublisher {
+	mq := bootstrap.Config.UI.MQTT
+	return app.NewMQTTPublisher(mqtt.NewClient(mqtt.Config{
+		Broker:   mq.Broker,
+		ClientID: mq.ClientID,
+		Username: mq.Username,
+		Password: mq.Password,
+	}), bootstrap.PremiumFormula, app.MQTTPublisherConfig{
+		PriceTopic:   mq.PriceTopic,
+		PremiumTopic: mq.PremiumTopic,
+		AlertTopic:   mq.AlertTopic,
+		Symbols:      mq.Symbols,
+		Interval:     time.Duration(mq.IntervalSec) * time.Second,
+		Retain:       mq.Retain,
+	})
+}
+
+// applyStateRules installs the sequencer settings that shape replicated state.
+// A follower must apply the same ones as its leader to stay identical.
+func applyStateRules(seq *engine.Sequencer, cfg *infra.Config) {
+	// Entry frequency limits: a misbehaving signal cannot churn fees all day
+	if limits := cfg.Strategy.Limits; limits.CooldownSec > 0 || limits.MaxEntriesPerDay > 0 {
+		seq.SetTradeGuard(engine.NewTradeGuard(limits.CooldownSec, limits.MaxEntriesPerDay))
+	}
+
+	// Noise filter in front of the strategy (event timestamps: replays filter identically)
+	if f := cfg.Strategy.Filter; len(f.Exchanges) > 0 || f.MinIntervalMS > 0 || f.MinChangeBps > 0 {
+		seq.SetMarketFilter(engine.NewMarketFilter(engine.MarketFilterConfig{
+			Exchanges:    f.Exchanges,
+			MinInterval:  quant.TimeStamp(f.MinIntervalMS * 1000),
+			MinChangeBps: f.MinChangeBps,
+		}))
+	}
+
+	// Unrealized PnL of positions is marked on one feed, like conditional-order triggers
+	seq.SetPositionMarkExchange(positionMarkExchange(cfg))
+
+	// Pre-funding warnings mark the epoch as warned and may move the strategy
+	seq.SetFundingWarning(time.Duration(cfg.Engine.Funding.WarnBeforeMin) * time.Minute)
+
+	// End-of-day reduce-only decides which orders pass: opted-out strategies keep trading
+	if eod := cfg.Engine.EndOfDay; eod.Enabled {
+		seq.SetReduceOnlyExempt(slices.Contains(eod.OptOut, app.StrategyName(cfg)))
+	}
+}
+
+// startTimers injects the engine.timers ticks into inbox. Like control
+// commands they bypass the spillover and are never dropped.
+func startTimers(ctx context.Context, cfg *infra.Config, inbox chan<- event.Event) {
+	if len(cfg.Engine.Timers) == 0 {
+		return
+	}
+	specs := make([]engine.TimerSpec, len(cfg.Engine.Timers))
+	for i, tm := range cfg.Engine.Timers {
+		specs[i] = engine.TimerSpec{Name: tm.Name, Interval: time.Duration(tm.IntervalSec) * time.Second}
+	}
+	go engine.NewTimerService(inbox, specs).Run(ctx)
+	slog.InfoContext(ctx, "✅ Timers started", slog.Int("timers", len(specs)))
+}
+
+// startHeartbeat records engine.heartbeat_sec liveness beats in the WAL.
+func startHeartbeat(ctx context.Context, cfg *infra.Config, inbox chan<- event.Event, instance string) {
+	if cfg.Engine.HeartbeatSec < 0 {
+		return
+	}
+	go engine.NewHeartbeatService(inbox, time.Duration(cfg.Engine.HeartbeatSec)*time.Second, instance).Run(ctx)
+}
+
+// startEndOfDay schedules the engine.end_of_day reduce-only windows. The
+// commands share the CONTROL sequence with operator commands.
+func startEndOfDay(ctx context.Context, cfg *infra.Config, control *engine.ControlClient) {
+	eod := cfg.Engine.EndOfDay
+	if !eod.Enabled || len(eod.Venues) == 0 {
+		return
+	}
+	specs := make([]engine.EndOfDaySpec, len(eod.Venues))
+	for i, v := range eod.Venues {
+		loc, _ := time.LoadLocation(v.Timezone) // Validated with the config
+		reduceAt, _ := infra.ParseTimeOfDay(v.ReduceAt)
+		resumeAt := time.Duration(-1)
+		if v.ResumeAt != "" {
+			resumeAt, _ = infra.ParseTimeOfDay(v.ResumeAt)
+		}
+		specs[i] = engine.EndOfDaySpec{Exchange: v.Exchange, Location: loc, ReduceAt: reduceAt, ResumeAt: resumeAt, Flatten: v.Flatten}
+	}
+	go engine.NewEndOfDay(control, specs).Run(ctx)
+	slog.InfoContext(ctx, "✅ End-of-day policy started", slog.Int("venues", len(specs)))
+}
+
+// positionMarkExchange returns the feed that marks default-venue positions:
+// engine.oms.mark_exchange, else the trigger exchange.
+func positionMarkExchange(cfg *infra.Config) string {
+	if mark := cfg.Engine.OMS.MarkExchange; mark != "" {
+		return mark
+	}
+	return cfg.Engine.OMS.TriggerExchange
+}
+
+// newPaperFeed prices paper orders from the venues they can be placed on:
+// default-venue orders from the position mark exchange, routed ones from their
+// venue. Without a mark exchange every venue's prices are used.
+func newPaperFeed(cfg *infra.Config, paper *execution.PaperExecution, routedVenues []string) *execution.PaperFeed {
+	mark := positionMarkExchange(cfg)
+	paper.SetDefaultExchange(mark)
+	if mark == "" {
+		return execution.NewPaperFeed(paper, cfg.Engine.InboxSize)
+	}
+	return execution.NewPaperFeed(paper, cfg.Engine.InboxSize, append(routedVenues, mark)...)
+}
+
+// serveAdmin serves pprof and the control endpoints on adminAddr (localhost
+// only for security). A successor starts serving while its predecessor may
+// still hold the port, so binding is retried until ctx ends.
+func serveAdmin(ctx context.Context) {
+	slog.Info("🕵️ Pprof server started on " + adminAddr)
+	for failed := false; ; failed = true {
+		err := http.ListenAndServe(adminAddr, nil)
+		if !failed {
+			slog.Error("Pprof server failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// newOrderManager builds the OMS of engine.oms, with the pre-trade checks of
+// engine.risk on seq's positions. Its settings shape replicated state, so a
+// follower builds it the same way.
+func newOrderManager(cfg *infra.Config, seq *engine.Sequencer) *engine.OrderManager {
+	oms := engine.NewOrderManager(cfg.Engine.OMS.IDPrefix, cfg.Engine.OMS.QueueSize)
+	oms.SetTriggerExchange(cfg.Engine.OMS.TriggerExchange)
+	oms.SetStalePolicy(time.Duration(cfg.Engine.OMS.OrderTTLSec)*time.Second, cfg.Engine.OMS.StaleDriftBps)
+	if r := cfg.Engine.Risk; r.Enabled {
+		eng := risk.NewEngine(risk.Limits{
+			MaxOrderNotionalMicros: r.MaxOrderNotional,
+			MaxPositionSats:        r.MaxPositionSats,
+			MaxOpenOrders:          r.MaxOpenOrders,
+			PriceCollarBps:         r.PriceCollarBps,
+			DailyLossMicros:        r.DailyLossLimit,
+			MaxGrossExposureMicros: r.MaxGrossExposure,
+			MaxVaRMicros:           r.MaxVaR,
+		}, seq.PositionBook())
+		window, confidence := risk.DefaultVaRWindowDays, int64(risk.DefaultVaRConfidenceBps)
+		if r.VaRWindowDays > 0 {
+			window = r.VaRWindowDays
+		}
+		if r.VaRConfidenceBps > 0 {
+			confidence = r.VaRConfidenceBps
+		}
+		eng.SetVaRModel(window, confidence)
+		// Default-venue orders are classified by the feed marking their positions
+		eng.SetDefaultVenue(positionMarkExchange(cfg))
+		oms.SetRisk(eng)
+	}
+	return oms
+}
+
+// watchRiskSettings applies risk limit settings changed at runtime as
+// SET_RISK_LIMIT control commands, so the change is in the WAL like an
+// operator's `app control SET_RISK_LIMIT`.
+func watchRiskSettings(ctx context.Context, settings *app.Settings, control *engine.ControlClient) {
+	limits := []struct {
+		setting, limit string
+		value          func(string) int64
+	}{
+		{app.SettingRiskMaxNotional, risk.LimitMaxOrderNotional, settings.GetDecimal},
+		{app.SettingRiskDailyLoss, risk.LimitDailyLoss, settings.GetDecimal},
+		{app.SettingRiskMaxOpenOrder, risk.LimitMaxOpenOrders, settings.GetInt},
+	}
+	for _, l := range limits {
+		settings.OnChange(l.setting, func() {
+			if err := control.Send(ctx, event.CmdSetRiskLimit, l.limit, l.value(l.setting), "setting: "+l.setting); err != nil {
+				slog.Error("Failed to apply risk setting", slog.String("key", l.setting), slog.Any("error", err))
+			}
+		})
+	}
+}
+
+// newRouter builds the order router of engine.router, or nil if disabled. It
+// decides which orders exist, so a follower builds it the same way.
+func newRouter(cfg *infra.Config) *engine.Router {
+	r := cfg.Engine.Router
+	if !r.Enabled {
+		return nil
+	}
+	venues := make([]engine.RouteVenue, 0, len(r.Venues))
+	for _, v := range r.Venues {
+		fee, maker := execution.TakerBps(cfg, v.Exchange), execution.MakerBps(cfg, v.Exchange)
+		if v.FeeBps != nil {
+			fee = *v.FeeBps
+		}
+		if v.MakerBps != nil {
+			maker = *v.MakerBps
+		}
+		venues = append(venues, engine.RouteVenue{Exchange: v.Exchange, Quote: v.Quote, Market: v.Market, FeeBps: fee, MakerBps: maker})
+	}
+	return engine.NewRouter(engine.RouterConfig{
+		Venues:       venues,
+		Base:         r.BaseCurrency,
+		FXMaxAge:     time.Duration(r.FXMaxAgeSec) * time.Second,
+		MakerTimeout: time.Duration(r.Maker.TimeoutSec) * time.Second,
+		MakerEdgeBps: r.Maker.EdgeBps,
+	})
 }
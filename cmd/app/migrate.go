@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"crypto_go/internal/infra"
+	"crypto_go/internal/storage"
+)
+
+const migrateUsage = "usage: app migrate <status|up|down> [-to version] [-mode paper|real] [-db path]"
+
+// runMigrateCommand shows or changes the event store schema version.
+// The engine migrates up automatically at startup; this is for inspection and rollback.
+// Returns the exit code.
+func runMigrateCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := fs.Int("to", -1, "target schema version (up: default latest, down: required)")
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	path := resolveDBPath(*dbPath, *mode)
+
+	store, err := storage.OpenEventStore(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open event store:", err)
+		return 1
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	var changed []storage.Migration
+	switch args[0] {
+	case "status":
+		current, err := store.SchemaVersion(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("%s: schema v%d (this build: v%d)\n", path, current, storage.LatestSchemaVersion())
+		return 0
+	case "up":
+		changed, err = store.MigrateUp(ctx, max(*to, 0))
+	case "down":
+		if *to < 0 {
+			fmt.Fprintln(os.Stderr, "migrate down requires -to <version>")
+			return 2
+		}
+		changed, err = store.MigrateDown(ctx, *to)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	for _, m := range changed {
+		fmt.Printf("%s %04d_%s\n", args[0], m.Version, m.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(changed) == 0 {
+		fmt.Println("schema already at requested version")
+	}
+	return 0
+}
+
+// resolveDBPath returns explicit, or the mode's events.db in the workspace.
+func resolveDBPath(explicit, mode string) string {
+	if explicit != "" {
+		return explicit
+	}
+	return filepath.Join(infra.GetWorkspaceDir(), "data", mode, "events.db")
+}
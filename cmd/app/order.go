@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"crypto_go/internal/app"
+)
+
+// runOrderCommand handles `app order <PLACE|CLOSE|CANCEL_ALL> [flags]` by
+// posting to the order endpoint of the running instance. Returns the exit code.
+func runOrderCommand(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "usage: app order <PLACE|CLOSE|CANCEL_ALL> [-exchange e] [-symbol s] [-side BUY|SELL] [-type MARKET|LIMIT] [-price p] [-qty q] [-reason r]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("order", flag.ContinueOnError)
+	exchange := fs.String("exchange", "", "venue (PLACE: empty = routed/default; CLOSE, CANCEL_ALL: empty = all)")
+	symbol := fs.String("symbol", "", "symbol (CLOSE, CANCEL_ALL: empty = all)")
+	side := fs.String("side", "", "BUY or SELL")
+	orderType := fs.String("type", "MARKET", "MARKET or LIMIT")
+	price := fs.String("price", "", "limit price (decimal)")
+	qty := fs.String("qty", "", "quantity (decimal, base currency)")
+	reason := fs.String("reason", "", "audit note recorded in the WAL")
+	token := fs.String("token", os.Getenv("CRYPTO_ORDER_TOKEN"), "API token (default $CRYPTO_ORDER_TOKEN)")
+	addr := fs.String("addr", adminAddr, "admin listener of the running instance")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	operator := os.Getenv("USER")
+	if operator == "" {
+		operator = "cli"
+	}
+	body, err := json.Marshal(app.OrderRequest{
+		Action:   strings.ToUpper(args[0]),
+		Exchange: *exchange,
+		Symbol:   *symbol,
+		Side:     *side,
+		Type:     *orderType,
+		Price:    json.Number(*price),
+		Qty:      json.Number(*qty),
+		Operator: operator,
+		Reason:   *reason,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid price or qty:", err)
+		return 2
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://"+*addr+app.OrdersPath, bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+*token)
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to reach running instance:", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		fmt.Fprintf(os.Stderr, "order rejected (%s): %s\n", resp.Status, strings.TrimSpace(string(msg)))
+		return 1
+	}
+	fmt.Println("accepted:", strings.ToUpper(args[0]))
+	return 0
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"crypto_go/internal/storage"
+)
+
+const outagesUsage = "usage: app outages [-mode paper|real] [-db path] [-slack 30s]"
+
+// runOutagesCommand lists the stretches of a mode's WAL the engine was down,
+// from the gaps and restarts in its heartbeats. The event store is opened
+// read-only, so it can run next to the engine. Returns the exit code.
+func runOutagesCommand(args []string) int {
+	fs := flag.NewFlagSet("outages", flag.ContinueOnError)
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	slack := fs.Duration("slack", 30*time.Second, "lateness tolerated beyond the heartbeat interval")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, outagesUsage)
+		return 2
+	}
+	path := resolveDBPath(*dbPath, *mode)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintln(os.Stderr, "event store not found:", path)
+		return 1
+	}
+
+	store, err := storage.OpenEventStoreReadOnly(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open event store:", err)
+		return 1
+	}
+	defer store.Close()
+
+	outages, err := store.Outages(context.Background(), *slack)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(outages) == 0 {
+		fmt.Println("no outages")
+		return 0
+	}
+
+	var total time.Duration
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FROM\tTO\tDURATION\tRESTART\tSEQ")
+	for _, o := range outages {
+		total += o.Duration()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%d-%d\n",
+			formatMicros(int64(o.From)), formatMicros(int64(o.To)), o.Duration().Round(time.Second), o.Restart, o.FromSeq, o.ToSeq)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("%d outages, %s down\n", len(outages), total.Round(time.Second))
+	return 0
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"crypto_go/internal/reconcile"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+const reconcileUsage = "usage: app reconcile -statement file.csv -exchange UPBIT [-tz Asia/Seoul] [-price-bps 5] [-window 1m] [-json] [-mode paper|real] [-db path]"
+
+// runReconcileCommand checks a venue's exported CSV statement against the
+// order updates and resync balances in a mode's event store, which is opened
+// read-only. Returns 0 if they match, 1 on discrepancies or errors.
+func runReconcileCommand(args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	path := fs.String("statement", "", "CSV statement exported by the venue")
+	exchange := fs.String("exchange", "", "venue of the statement (gateway resync source, e.g. UPBIT)")
+	tz := fs.String("tz", "UTC", "zone of statement times without one (e.g. Asia/Seoul)")
+	priceBps := fs.Int64("price-bps", 5, "average fill price difference tolerated (bp)")
+	qtySats := fs.Int64("qty-sats", 0, "quantity and coin balance difference tolerated (sats)")
+	cashMicros := fs.Int64("cash-micros", 1_000_000, "cash balance difference tolerated (micros)")
+	window := fs.Duration("window", time.Minute, "time distance for matching lines without an order ID")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, reconcileUsage)
+		return 2
+	}
+	if *path == "" || *exchange == "" {
+		fmt.Fprintln(os.Stderr, reconcileUsage)
+		return 2
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid -tz:", err)
+		return 2
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	stmt, err := reconcile.ParseStatement(f, loc)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid statement:", err)
+		return 1
+	}
+
+	db := resolveDBPath(*dbPath, *mode)
+	if _, err := os.Stat(db); err != nil {
+		fmt.Fprintln(os.Stderr, "event store not found:", db)
+		return 1
+	}
+	store, err := storage.OpenEventStoreReadOnly(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to open event store:", err)
+		return 1
+	}
+	defer store.Close()
+
+	from, to := stmt.Span()
+	slack := quant.TimeStamp(window.Microseconds())
+	local, err := reconcile.LoadLocal(context.Background(), store, *exchange, from-slack, to+slack)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	report := reconcile.Compare(stmt, local, reconcile.Options{
+		PriceToleranceBps: *priceBps,
+		QtyToleranceSats:  *qtySats,
+		CashToleranceSats: *cashMicros,
+		Window:            *window,
+	})
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		fmt.Printf("%s statement %s .. %s\n", *exchange, formatMicros(int64(from)), formatMicros(int64(to)))
+		fmt.Print(report)
+	}
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
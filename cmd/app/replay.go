@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"crypto_go/internal/app"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+const replayUsage = "usage: app replay [-mode paper|real] [-db path] [-snapshots dir] [-config path] [-template sma_cross -short 3 -long 5] [-to seq]"
+
+// runReplayCommand replays a recorded WAL through a fresh sequencer built like
+// the live one (or with the strategy of -template), verifies the state at every
+// recorded snapshot and prints where the replay diverged. The event store is
+// opened read-only. Returns the exit code: 1 on divergence.
+func runReplayCommand(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	mode := fs.String("mode", "paper", "trading mode whose data directory to use")
+	dbPath := fs.String("db", "", "event store path (overrides -mode)")
+	snapDir := fs.String("snapshots", "", "snapshot directory (default: snapshots next to the event store)")
+	configPath := fs.String("config", "", "config whose engine settings the live run used (default: configs/config.yaml)")
+	template := fs.String("template", "", "replay with this watchlist strategy template instead of the config's")
+	short := fs.Int("short", 0, "short period of -template")
+	long := fs.Int("long", 0, "long period of -template")
+	toSeq := fs.Uint64("to", 0, "stop before this seq (default: the whole WAL)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, replayUsage)
+		return 2
+	}
+
+	if *configPath == "" {
+		*configPath = infra.ResolveConfigPath()
+	}
+	cfg, err := infra.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load config:", err)
+		return 1
+	}
+	if *template != "" {
+		cfg.Strategy.Watchlist.Template = *template
+		cfg.Strategy.Watchlist.ShortPeriod = *short
+		cfg.Strategy.Watchlist.LongPeriod = *long
+	}
+	strat, err := app.BuildStrategy(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid strategy:", err)
+		return 2
+	}
+
+	path := resolveDBPath(*dbPath, *mode)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintln(os.Stderr, "event store not found:", path)
+		return 1
+	}
+	store, err := storage.OpenEventStoreReadOnly(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer store.Close()
+	if *snapDir == "" {
+		*snapDir = filepath.Join(filepath.Dir(path), "snapshots")
+	}
+
+	// Built like a follower: the same state rules, OMS and router as the live run
+	seq := engine.NewSequencer(1, nil, strat, func(*domain.MarketState) {})
+	seq.SetClock(quant.NewSimClock(0)) // Recorded time, not the time of this run
+	applyStateRules(seq, cfg)
+	if cfg.Engine.OMS.Enabled {
+		seq.SetOrderManager(newOrderManager(cfg, seq))
+		if router := newRouter(cfg); router != nil {
+			seq.SetRouter(router)
+		}
+	}
+
+	report, err := engine.Replay(context.Background(), seq, store, engine.ReplayOptions{
+		Snapshots: storage.NewSnapshotManager(*snapDir),
+		ToSeq:     *toSeq,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "replay failed:", err)
+		return 1
+	}
+
+	fmt.Printf("replayed %d events with %s, next seq %d\n", report.Events, app.StrategyName(cfg), report.NextSeq)
+	fmt.Printf("state hash: %s\n", report.StateHash)
+	if len(report.Checkpoints) == 0 {
+		fmt.Printf("no snapshot with a state hash in %s: nothing to verify\n", *snapDir)
+		return 0
+	}
+	// Checkpoints are in seq order: the divergence lies after the last good one
+	first, verified := report.FirstDivergence(), uint64(1)
+	for _, c := range report.Checkpoints {
+		if !c.Diverged() {
+			if first == nil || c.Seq < first.Seq {
+				verified = c.Seq
+			}
+			fmt.Printf("snapshot %d: ok\n", c.Seq)
+			continue
+		}
+		fmt.Printf("snapshot %d: DIVERGED (live %s, replay %s)\n", c.Seq, c.Live, c.Replay)
+		for _, section := range c.Sections {
+			fmt.Printf("  differs: %s\n", section)
+		}
+	}
+	if first != nil {
+		fmt.Printf("first divergence: an event in seq %d..%d was applied differently\n", verified, first.Seq-1)
+		return 1
+	}
+	return 0
+}
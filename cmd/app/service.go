@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"crypto_go/internal/infra"
+	"crypto_go/internal/infra/service"
+)
+
+const serviceName = "crypto-go"
+
+// runServiceCommand handles `app <command> [-workdir dir]` and returns the exit code.
+func runServiceCommand(cmd string, args []string) int {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	workDir := fs.String("workdir", "", "directory containing configs/ and _workspace/ (default: current directory)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	dir := *workDir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to resolve working directory:", err)
+			return 1
+		}
+		dir = wd
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid workdir:", err)
+		return 1
+	}
+
+	if cmd == "run" {
+		// Services start in an arbitrary cwd (/, C:\Windows\System32);
+		// chdir so config and workspace resolution match an interactive run.
+		if err := os.Chdir(dir); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to enter workdir:", err)
+			return 1
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := service.Run(ctx, serviceName, run); err != nil {
+			fmt.Fprintln(os.Stderr, "service run failed:", err)
+			return 1
+		}
+		return 0
+	}
+
+	// The service does not inherit this shell: pin the selected profile
+	svcArgs := []string{"run", "-workdir", dir}
+	if p := infra.ConfigProfile(); p != "" {
+		svcArgs = append([]string{"--profile", p}, svcArgs...)
+	}
+	mgr, err := service.New(service.Config{
+		Name:        serviceName,
+		DisplayName: "Crypto Go Monitor",
+		Description: "Kimchi premium monitor and quant engine",
+		Args:        svcArgs,
+		WorkingDir:  dir,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch cmd {
+	case "install":
+		err = mgr.Install()
+	case "uninstall":
+		err = mgr.Uninstall()
+	case "start":
+		err = mgr.Start()
+	case "stop":
+		err = mgr.Stop()
+	case "status":
+		var st service.Status
+		st, err = mgr.Status()
+		if err == nil {
+			fmt.Println(st)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q (want install|uninstall|start|stop|status|run)\n", cmd)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s failed: %v\n", cmd, err)
+		return 1
+	}
+	if cmd != "status" {
+		fmt.Printf("✅ %s: %s\n", serviceName, cmd)
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"crypto_go/internal/app"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/storage"
+	"crypto_go/internal/strategy"
+)
+
+// runSharded is the monitor body with engine.shards > 1: events are
+// hash-routed by symbol to that many sequencers, each with its own WAL and
+// snapshots, and the read endpoints and the MQTT mirror are fed from the
+// merged stream of all shards. Trading (OMS) is not available in this mode.
+// It returns when ctx is cancelled.
+func runSharded(ctx context.Context, bootstrap *app.Bootstrap) {
+	cfg := bootstrap.Config
+	n := cfg.Engine.Shards
+	inboxSize := cfg.Engine.InboxSize
+	if inboxSize <= 0 {
+		inboxSize = 1024
+	}
+
+	// One strategy instance per shard: each shard runs on its own goroutine
+	strats := make([]strategy.Strategy, n)
+	for i := range strats {
+		strat, err := app.BuildStrategy(cfg)
+		if err != nil {
+			slog.Error("❌ Invalid strategy config", slog.Any("error", err))
+			os.Exit(1)
+		}
+		strats[i] = strat
+	}
+	sharded := engine.NewShardedSequencer(n, inboxSize, func(i int) strategy.Strategy { return strats[i] })
+
+	// Operator and automatic control commands are copied to every shard
+	control := engine.NewControlClient(sharded.Inbox())
+
+	// The WAL directory is per shard count: routing changes with it
+	w := cfg.Engine.WAL
+	root := fmt.Sprintf("shards-%d", n)
+	for i := 0; i < n; i++ {
+		shard := sharded.Shard(i)
+		applyStateRules(shard, cfg)
+
+		fileWAL, err := storage.OpenFileWAL(filepath.Join(bootstrap.DataDir, "wal", root, fmt.Sprintf("shard-%d", i)), storage.FileWALOptions{
+			SegmentBytes: w.SegmentMB << 20,
+			Sync:         w.Sync,
+			SyncInterval: time.Duration(w.SyncIntervalMS) * time.Millisecond,
+			Codec:        w.Codec,
+		})
+		if err != nil {
+			slog.Error("❌ Failed to open shard WAL", slog.Int("shard", i), slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer fileWAL.Sync()
+		shard.SetEventLog(fileWAL)
+
+		snapshots := storage.NewSnapshotManager(filepath.Join(bootstrap.DataDir, "snapshots", root, fmt.Sprintf("shard-%d", i)))
+		snapshots.SetRetention(cfg.Engine.Snapshot.Keep)
+		shard.SetSnapshotManager(snapshots)
+		shard.SetFastRecovery(cfg.Engine.Snapshot.FastRecovery)
+		if every := cfg.Engine.Snapshot.EveryEvents; every > 0 {
+			shard.SetSnapshotInterval(every, func() {
+				// Off the hotpath: the inbox send may block
+				go func() {
+					if err := control.Send(ctx, event.CmdTriggerSnapshot, "", 0, fmt.Sprintf("auto: periodic snapshot (shard %d)", i)); err != nil {
+						slog.Error("Failed to request snapshot", slog.Any("error", err))
+					}
+				}()
+			})
+		}
+	}
+
+	// Cross-symbol consumers read the merged stream (premiums need the FX feed)
+	var mqttPub *app.MQTTPublisher
+	if cfg.UI.MQTT.Enabled {
+		mqttPub = newMQTTPublisher(bootstrap)
+		sharded.Subscribe(func(me engine.MergedEvent) {
+			if e, ok := me.Event.(*event.MarketUpdateEvent); ok {
+				mqttPub.Observe(*e)
+			}
+		})
+		go mqttPub.Run(ctx)
+		slog.Info("📡 MQTT publisher enabled", slog.String("broker", cfg.UI.MQTT.Broker))
+	}
+
+	if err := sharded.RecoverFromWAL(ctx); err != nil {
+		slog.Error("❌ Failed to recover shards from WAL", slog.Any("error", err))
+		os.Exit(1)
+	}
+	go sharded.Run(ctx)
+	slog.InfoContext(ctx, "✅ Sharded sequencer started", slog.Int("shards", n))
+	startTimers(ctx, cfg, sharded.Inbox()) // Ticks are copied to every shard
+	startHeartbeat(ctx, cfg, sharded.Inbox(), bootstrap.InstanceID)
+
+	for name, enabled := range map[string]bool{
+		"api.onchain":    cfg.API.OnChain.Enabled,
+		"api.sentiment":  cfg.API.Sentiment.Enabled,
+		"api.notices":    cfg.API.Notices.Enabled,
+		"api.signals":    cfg.API.Signals.Enabled,
+		"gap_validation": cfg.Engine.GapPolicy != nil,
+	} {
+		if enabled {
+			slog.Warn("Not available with engine.shards, ignored", slog.String("feature", name))
+		}
+	}
+
+	settings := app.NewSettings(bootstrap.EventStore, app.DefaultSettings(cfg))
+	if err := settings.Load(ctx); err != nil {
+		slog.Error("❌ Failed to load settings", slog.Any("error", err))
+		os.Exit(1)
+	}
+	http.Handle(app.ControlPath, app.NewControlHandler(control))
+	http.Handle(app.SettingsPath, app.NewSettingsHandler(settings))
+	http.Handle(app.FavoritesPath, app.NewFavoritesHandler(bootstrap.EventStore))
+	http.Handle(app.MarketsPath, app.NewMarketsHandler(sharded))
+	http.Handle(app.FundingPath, app.NewFundingHandler(sharded))
+	keyframe := time.Duration(cfg.UI.KeyframeSec) * time.Second
+	if keyframe == 0 {
+		keyframe = 10 * time.Second
+	}
+	http.Handle(app.StreamPath, app.NewStreamHandler(sharded, time.Duration(cfg.UI.UpdateIntervalMS)*time.Millisecond, keyframe))
+	slog.InfoContext(ctx, "✅ Control endpoint ready", slog.String("addr", adminAddr+app.ControlPath))
+
+	inbox := sharded.Inbox()
+	exchangeRateClient := infra.NewExchangeRateClientWithConfig(
+		inbox, new(uint64),
+		cfg.API.ExchangeRate.URL,
+		cfg.API.ExchangeRate.PollIntervalSec,
+	)
+	for _, p := range cfg.API.ExchangeRate.Pairs {
+		exchangeRateClient.AddPair(p.Symbol, p.URL, p.PollIntervalSec)
+	}
+	if err := exchangeRateClient.Start(ctx); err != nil {
+		slog.Error("Failed to start exchange rate client", slog.Any("error", err))
+	}
+	defer exchangeRateClient.Stop()
+
+	// engine.shards excludes engine.inbox_ring: every worker sends to the router
+	attachOutbox := func(w marketWorker) { w.SetBackpressure(backpressure(cfg)) }
+	noResync := func(string, app.ResyncSource) {}
+	disconnectWorkers := startMarketWorkers(ctx, cfg, inbox, attachOutbox, noResync)
+	defer disconnectWorkers()
+
+	notifier := infra.NewSystemdNotifier()
+	slog.InfoContext(ctx, "✨ Quant System fully operational (sharded). Press Ctrl+C to exit.")
+	if err := notifier.Ready(); err != nil {
+		slog.Warn("Failed to notify systemd readiness", slog.Any("error", err))
+	}
+
+	<-ctx.Done()
+
+	slog.InfoContext(ctx, "👋 Shutting down gracefully...")
+	notifier.Stopping()
+}
@@ -0,0 +1,180 @@
+// Command stress applies the configured shock scenarios (stress.scenarios) to
+// the positions of the latest snapshot and prints the resulting PnL, futures
+// margin usage and liquidation proximity per position. It only reads the data
+// directory, so it can run next to a live instance.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/app"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/risk"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+const defaultPremiumLookback = 60 * time.Minute
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run returns the exit code.
+func run(args []string) int {
+	fs := flag.NewFlagSet("stress", flag.ContinueOnError)
+	mode := fs.String("mode", "", "trading mode whose data directory to use (default: trading.mode)")
+	dataDir := fs.String("data", "", "data directory (overrides -mode)")
+	asJSON := fs.Bool("json", false, "print the reports as JSON")
+	profile := fs.String("profile", "", "config profile (default: CRYPTO_PROFILE)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *profile != "" {
+		infra.SetConfigProfile(*profile)
+	}
+
+	cfg, err := infra.LoadConfig(infra.ResolveConfigPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load config:", err)
+		return 1
+	}
+	dir := resolveDataDir(*dataDir, *mode, cfg)
+
+	snap, err := storage.NewSnapshotManager(filepath.Join(dir, "snapshots")).LoadLatest()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if snap == nil {
+		fmt.Fprintln(os.Stderr, "no snapshot in", filepath.Join(dir, "snapshots"))
+		return 1
+	}
+
+	market := risk.StressMarket{DefaultVenue: cfg.Engine.OMS.MarkExchange}
+	if market.DefaultVenue == "" {
+		market.DefaultVenue = cfg.Engine.OMS.TriggerExchange
+	}
+	market.PremiumMicros, err = currentPremiums(cfg, filepath.Join(dir, "events.db"), snap.TsUnix)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "premium shocks disabled:", err)
+	}
+	margin := risk.MarginModel{Leverage: cfg.Stress.Leverage, MaintenanceBps: cfg.Stress.MaintenanceBps}
+
+	var reports []risk.StressReport
+	for _, sc := range scenarios(cfg) {
+		reports = append(reports, risk.Stress(snap.Positions, market, margin, sc))
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Printf("Snapshot seq %d at %s, %d positions\n", snap.Seq, time.Unix(snap.TsUnix, 0).UTC().Format(time.RFC3339), len(snap.Positions))
+	for _, rep := range reports {
+		printReport(rep)
+	}
+	return 0
+}
+
+func resolveDataDir(explicit, mode string, cfg *infra.Config) string {
+	if explicit != "" {
+		return explicit
+	}
+	if mode == "" {
+		mode = strings.ToLower(cfg.Trading.Mode)
+	}
+	if mode == "" {
+		mode = "paper"
+	}
+	return filepath.Join(infra.GetWorkspaceDir(), "data", mode)
+}
+
+// scenarios converts stress.scenarios, or returns the defaults.
+func scenarios(cfg *infra.Config) []risk.Scenario {
+	if len(cfg.Stress.Scenarios) == 0 {
+		return risk.DefaultScenarios()
+	}
+	out := make([]risk.Scenario, 0, len(cfg.Stress.Scenarios))
+	for _, sc := range cfg.Stress.Scenarios {
+		out = append(out, risk.Scenario{Name: sc.Name, PriceBps: sc.PriceBps, FXBps: sc.FXBps, PremiumBps: sc.PremiumBps})
+	}
+	return out
+}
+
+// currentPremiums returns the last kimchi premium per symbol (premium formula
+// of the config) recorded in the WAL before the snapshot.
+func currentPremiums(cfg *infra.Config, dbPath string, snapUnix int64) (map[string]int64, error) {
+	formula, err := app.BuildPremiumFormula(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("event store not found: %s", dbPath)
+	}
+	store, err := storage.OpenEventStoreReadOnly(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	lookback := defaultPremiumLookback
+	if m := cfg.Stress.PremiumLookbackMin; m > 0 {
+		lookback = time.Duration(m) * time.Minute
+	}
+	to := time.Unix(snapUnix+1, 0)
+	tracker := analytics.NewPremiumTracker(0, 0)
+	tracker.SetFormula(formula)
+
+	premiums := make(map[string]int64)
+	err = analytics.ScanPremiums(context.Background(), store,
+		quant.TimeStamp(to.Add(-lookback).UnixMicro()), quant.TimeStamp(to.UnixMicro()), tracker,
+		func(s analytics.PremiumSample) { premiums[s.Symbol] = s.PremiumMicros })
+	return premiums, err
+}
+
+func printReport(rep risk.StressReport) {
+	fmt.Printf("\n== %s ==\n", rep.Scenario)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "EXCHANGE\tSYMBOL\tQTY\tMARK\tSHOCKED\tPNL\tMARGIN\tUSAGE\tLIQ PRICE\tLIQ DIST\t")
+	for _, p := range rep.Positions {
+		shocked := quant.PriceMicros(p.ShockedMicros).String()
+		if p.NoPremium {
+			shocked += " (no premium)"
+		}
+		margin, usage, liq, dist := "-", "-", "-", "-"
+		if p.LiquidationMicros > 0 || p.MarginMicros != 0 {
+			margin = quant.PriceMicros(p.MarginMicros).String()
+			usage = formatBps(p.MarginUsageBps)
+			liq = quant.PriceMicros(p.LiquidationMicros).String()
+			dist = formatBps(p.LiqDistanceBps)
+			if p.Liquidated() {
+				dist += " LIQUIDATED"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", p.Exchange, p.Symbol, quant.QtySats(p.QtySats).String(),
+			quant.PriceMicros(p.MarkMicros).String(), shocked, quant.PriceMicros(p.PnLMicros).String(), margin, usage, liq, dist)
+	}
+	w.Flush()
+	for _, q := range rep.PnL {
+		fmt.Printf("PnL %s: %s\n", q.Quote, quant.PriceMicros(q.PnLMicros).String())
+	}
+}
+
+func formatBps(bps int64) string {
+	return fmt.Sprintf("%.2f%%", float64(bps)/100)
+}
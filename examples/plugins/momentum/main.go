@@ -0,0 +1,68 @@
+// Command momentum is an example strategy plugin. Build it into the plugin
+// directory (strategy.plugins.dir) with the same Go toolchain as the app:
+//
+//	go build -buildmode=plugin -o plugins/momentum.so ./examples/plugins/momentum
+//
+// and use it from the strategies list with type: "momentum".
+package main
+
+import (
+	"fmt"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+)
+
+// Type is the strategy type name.
+var Type = "momentum"
+
+// Defaults lists the accepted parameters: run is the number of consecutive
+// rising (falling) ticks that triggers a BUY (SELL); qty is in Sats.
+var Defaults = map[string]int64{"run": 5, "qty": 10_000}
+
+// New builds a momentum strategy for symbol.
+func New(symbol string, params map[string]int64) (strategy.Strategy, error) {
+	if params["run"] <= 0 || params["qty"] <= 0 {
+		return nil, fmt.Errorf("momentum needs run > 0 and qty > 0")
+	}
+	return &momentum{symbol: symbol, run: params["run"], qty: params["qty"]}, nil
+}
+
+type momentum struct {
+	symbol string
+	run    int64
+	qty    int64
+	prev   int64
+	streak int64 // > 0 rising ticks, < 0 falling ticks
+}
+
+func (m *momentum) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol != m.symbol || len(out) == 0 {
+		return 0
+	}
+	price := int64(state.PriceMicros)
+	switch {
+	case m.prev == 0 || price == m.prev:
+	case price > m.prev:
+		m.streak = max(m.streak, 0) + 1
+	default:
+		m.streak = min(m.streak, 0) - 1
+	}
+	m.prev = price
+
+	side := ""
+	switch m.streak {
+	case m.run:
+		side = domain.SideBuy
+	case -m.run:
+		side = domain.SideSell
+	default:
+		return 0
+	}
+	out[0] = domain.Order{Symbol: m.symbol, Side: side, Type: domain.OrderTypeMarket, PriceMicros: price, QtySats: m.qty, Status: "NEW"}
+	return 1
+}
+
+func (m *momentum) OnOrderUpdate(domain.Order) {}
+
+func main() {}
@@ -0,0 +1,180 @@
+// Package alert evaluates declarative alert rules over live market data and
+// turns the ones that fire into control events.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/expr"
+)
+
+// Actions a Rule can take when it fires. Every rule also notifies.
+const (
+	ActionNotify        = "notify"         // Log and notifier only
+	ActionPauseStrategy = "pause_strategy" // CmdPauseStrategy
+	ActionReduceOnly    = "reduce_only"    // CmdReduceOnly on Venue, flattening if Flatten
+	ActionHedge         = "hedge"          // Manual PLACE of Hedge
+)
+
+// sendTimeout bounds a control event send when a rule fires.
+const sendTimeout = 5 * time.Second
+
+// Rule is an alert: when When turns true, Action is sent through the control
+// client, at most once per Cooldown.
+type Rule struct {
+	Name     string
+	When     *expr.Expr
+	Action   string
+	Venue    string                 // reduce_only: venue ("" = every venue)
+	Flatten  bool                   // reduce_only: also close the venue's positions
+	Hedge    event.ManualOrderEvent // hedge: the order to place
+	Cooldown time.Duration
+}
+
+// ValidateVar checks a variable name:
+//
+//	price.<EXCHANGE>.<SYMBOL>  last price on the venue (quote currency, e.g. price.UPBIT.BTC, price.FX.USD/KRW)
+//	premium.<SYMBOL>           kimchi premium of the configured formula, in % (e.g. premium.BTC)
+func ValidateVar(name string) error {
+	parts := strings.Split(name, ".")
+	switch {
+	case parts[0] == "price" && len(parts) == 3 && parts[1] != "" && parts[2] != "":
+		return nil
+	case parts[0] == "premium" && len(parts) == 2 && parts[1] != "":
+		return nil
+	}
+	return fmt.Errorf("unknown alert variable %q (want price.<EXCHANGE>.<SYMBOL> or premium.<SYMBOL>)", name)
+}
+
+type ruleState struct {
+	Rule
+	holding bool      // The condition held at the last check
+	fired   time.Time // Last time the rule fired
+}
+
+// Monitor evaluates rules over the live market updates. Observe only records
+// the values; Run checks the rules every interval off the hotpath. A rule
+// fires when its condition starts to hold (and again only after it stopped
+// holding), unless it fired less than Cooldown ago. Its action goes through
+// the control client, so it is WAL-logged and replayed like an operator
+// command; the rules themselves are not re-run on replay.
+type Monitor struct {
+	mu       sync.Mutex
+	rules    []*ruleState
+	values   expr.Map // Latest variable values
+	tracker  *analytics.PremiumTracker
+	changed  bool
+	control  *engine.ControlClient
+	strategy string
+	notify   func(kind, text string)
+}
+
+// NewMonitor creates a monitor sending actions through control. strategy is
+// the name recorded on CmdPauseStrategy; tracker computes premium.* values.
+func NewMonitor(rules []Rule, control *engine.ControlClient, strategy string, tracker *analytics.PremiumTracker) *Monitor {
+	m := &Monitor{values: make(expr.Map), tracker: tracker, control: control, strategy: strategy}
+	for _, r := range rules {
+		m.rules = append(m.rules, &ruleState{Rule: r})
+	}
+	return m
+}
+
+// SetNotifier registers a callback for every firing (e.g. an MQTT alert).
+// It must not block. Must be called before Run.
+func (m *Monitor) SetNotifier(fn func(kind, text string)) {
+	m.notify = fn
+}
+
+// Observe records a live market update. It runs on the hotpath and never blocks
+// for long.
+func (m *Monitor) Observe(e event.MarketUpdateEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values["price."+e.Exchange+"."+e.Symbol] = int64(e.PriceMicros)
+	if sample, ok := m.tracker.Observe(&e); ok {
+		m.values["premium."+sample.Symbol] = sample.PremiumMicros * 100 // 1% = 10,000 micros → percent with six decimals
+	}
+	m.changed = true
+}
+
+// Run checks the rules every interval until ctx ends.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Check(ctx, now)
+		}
+	}
+}
+
+// Check evaluates every rule against the latest values and sends the actions
+// of the ones firing at now. It returns the names of those rules.
+func (m *Monitor) Check(ctx context.Context, now time.Time) []string {
+	var firing []*ruleState
+	m.mu.Lock()
+	if !m.changed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.changed = false
+	for _, r := range m.rules {
+		holds := r.When.Eval(m.values)
+		if holds && !r.holding && (r.fired.IsZero() || now.Sub(r.fired) >= r.Cooldown) {
+			r.fired = now
+			firing = append(firing, r)
+		}
+		r.holding = holds
+	}
+	m.mu.Unlock()
+
+	names := make([]string, 0, len(firing))
+	for _, r := range firing {
+		m.fire(ctx, r)
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// fire notifies and sends r's action (blocking up to sendTimeout).
+func (m *Monitor) fire(ctx context.Context, r *ruleState) {
+	reason := fmt.Sprintf("alert %s: %s", r.Name, r.When)
+	slog.Warn("ALERT_FIRED", slog.String("rule", r.Name), slog.String("when", r.When.String()), slog.String("action", r.Action))
+	if m.notify != nil {
+		m.notify("ALERT", reason)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	var err error
+	switch r.Action {
+	case ActionPauseStrategy:
+		err = m.control.Send(ctx, event.CmdPauseStrategy, m.strategy, 0, reason)
+	case ActionReduceOnly:
+		var flatten int64
+		if r.Flatten {
+			flatten = 1
+		}
+		err = m.control.Send(ctx, event.CmdReduceOnly, r.Venue, flatten, reason)
+	case ActionHedge:
+		order := r.Hedge
+		order.Action = event.ManualPlace
+		order.Operator = "alert:" + r.Name
+		order.Reason = reason
+		err = m.control.SendManualOrder(ctx, order)
+	}
+	if err != nil {
+		slog.Error("Failed to send alert action", slog.String("rule", r.Name), slog.String("action", r.Action), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,77 @@
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/expr"
+	"crypto_go/pkg/quant"
+)
+
+func update(exchange, symbol string, price int64) event.MarketUpdateEvent {
+	return event.MarketUpdateEvent{
+		BaseEvent:   event.BaseEvent{Ts: quant.TimeStamp(time.Now().UnixMicro())},
+		Exchange:    exchange,
+		Symbol:      symbol,
+		PriceMicros: quant.PriceMicros(price * quant.PriceScale),
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	inbox := make(chan event.Event, 8)
+	premium, _ := expr.Compile("premium.BTC >= 5")
+	crash, _ := expr.Compile("price.UPBIT.BTC < 80000000")
+	m := NewMonitor([]Rule{
+		{Name: "kimchi", When: premium, Action: ActionPauseStrategy, Cooldown: time.Minute},
+		{Name: "crash", When: crash, Action: ActionHedge, Hedge: event.ManualOrderEvent{Symbol: "BTC", Side: "SELL", OrderType: "MARKET", QtySats: 1_000}},
+	}, engine.NewControlClient(inbox), "sma", analytics.NewPremiumTracker(0, 0))
+	var notified []string
+	m.SetNotifier(func(kind, text string) { notified = append(notified, text) })
+	ctx := context.Background()
+	now := time.Now()
+
+	m.Observe(update("FX", "USD/KRW", 1_400))
+	m.Observe(update("BITGET_SPOT", "BTC", 70_000))
+	m.Observe(update("UPBIT", "BTC", 103_000_000)) // 5.10%
+	if fired := m.Check(ctx, now); len(fired) != 1 || fired[0] != "kimchi" {
+		t.Fatalf("expected kimchi to fire, got %v", fired)
+	}
+	ev, ok := (<-inbox).(*event.ControlEvent)
+	if !ok || ev.Command != event.CmdPauseStrategy || ev.Target != "sma" || ev.Reason != "alert kimchi: premium.BTC >= 5" {
+		t.Fatalf("unexpected control event %+v", ev)
+	}
+
+	// Dropping and rising again within the cooldown: suppressed
+	m.Observe(update("UPBIT", "BTC", 103_500_000))
+	m.Observe(update("UPBIT", "BTC", 100_000_000))
+	if fired := m.Check(ctx, now.Add(time.Second)); len(fired) != 0 {
+		t.Fatalf("unexpected firing %v", fired)
+	}
+	m.Observe(update("UPBIT", "BTC", 103_000_000))
+	if fired := m.Check(ctx, now.Add(2*time.Second)); len(fired) != 0 {
+		t.Fatalf("fired within the cooldown: %v", fired)
+	}
+	m.Observe(update("UPBIT", "BTC", 100_000_000))
+	m.Check(ctx, now.Add(3*time.Second))
+	m.Observe(update("UPBIT", "BTC", 103_000_000))
+	if fired := m.Check(ctx, now.Add(2*time.Minute)); len(fired) != 1 {
+		t.Fatalf("expected kimchi again after the cooldown, got %v", fired)
+	}
+	<-inbox
+
+	m.Observe(update("UPBIT", "BTC", 79_000_000))
+	if fired := m.Check(ctx, now.Add(3*time.Minute)); len(fired) != 1 || fired[0] != "crash" {
+		t.Fatalf("expected crash to fire, got %v", fired)
+	}
+	order, ok := (<-inbox).(*event.ManualOrderEvent)
+	if !ok || order.Action != event.ManualPlace || order.Side != "SELL" || order.QtySats != 1_000 || order.Operator != "alert:crash" {
+		t.Fatalf("unexpected hedge %+v", order)
+	}
+	if len(notified) != 3 {
+		t.Errorf("expected 3 notifications, got %v", notified)
+	}
+}
@@ -0,0 +1,243 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// TransferSpec is how a coin moves from the foreign to the domestic wallet.
+type TransferSpec struct {
+	Delay   time.Duration // Withdrawal until the deposit is credited
+	FeeSats int64         // Withdrawal fee, in the coin
+}
+
+// ArbSimConfig configures an ArbSimulator. Amounts are micros of the foreign
+// quote (USDT) unless named KRW.
+type ArbSimConfig struct {
+	EntryMicros     int64                   // Premium that opens a trade (1% = 10,000)
+	NotionalMicros  int64                   // Spent offshore per trade, fee included
+	ForeignFeeBps   int64                   // Taker fee of the offshore buy
+	DomesticFeeBps  int64                   // Taker fee of the domestic sell
+	Transfers       map[string]TransferSpec // By symbol
+	DefaultTransfer TransferSpec            // Symbols missing from Transfers
+	KRW             KRWTransferPolicy       // Repatriation of the proceeds
+}
+
+// States of an ArbTrade.
+const (
+	ArbInTransit    = "in_transit"   // Coins on their way to the domestic venue
+	ArbRepatriating = "repatriating" // Sold, KRW leaving for the bank
+	ArbSettled      = "settled"      // KRW converted back at the FX rate
+	ArbFailed       = "failed"       // The KRW could not be withdrawn
+)
+
+// ArbTrade is one simulated kimchi premium round trip: buy offshore, move
+// the coins, sell them for KRW, withdraw the KRW and convert it back.
+type ArbTrade struct {
+	Symbol             string          `json:"symbol"`
+	State              string          `json:"state"`
+	Opened             quant.TimeStamp `json:"opened"`
+	Sold               quant.TimeStamp `json:"sold,omitempty"`
+	Settled            quant.TimeStamp `json:"settled,omitempty"` // KRW withdrawn (scheduled until State is settled)
+	EntryPremiumMicros int64           `json:"entry_premium"`     // The theoretical spread
+	ExitPremiumMicros  int64           `json:"exit_premium"`      // Last premium when the coins were sold
+	CostMicros         int64           `json:"cost"`
+	QtySats            int64           `json:"qty"`             // Bought
+	SoldSats           int64           `json:"sold_qty"`        // Credited after the transfer fee
+	ProceedsKRW        int64           `json:"proceeds_krw"`    // Domestic sale after its fee
+	KRWFee             int64           `json:"krw_fee"`         // Withdrawal fees
+	ReturnMicros       int64           `json:"return"`          // Converted back (settled only)
+	CaptureMicros      int64           `json:"capture"`         // Realized return on cost, 1% = 10,000 (settled only)
+	Error              string          `json:"error,omitempty"` // Why it failed
+}
+
+// ArbReport summarizes a simulation. Averages cover settled trades.
+type ArbReport struct {
+	Trades           []ArbTrade `json:"trades"`
+	Settled          int        `json:"settled"`
+	Open             int        `json:"open"`
+	Failed           int        `json:"failed"`
+	AvgEntryMicros   int64      `json:"avg_entry"`
+	AvgCaptureMicros int64      `json:"avg_capture"`
+	CaptureRatioBps  int64      `json:"capture_ratio_bps"` // Realized / theoretical, 10,000 = all of it
+	PnLMicros        int64      `json:"pnl"`
+	AvgHoldMicros    int64      `json:"avg_hold_us"` // Opened until settled
+}
+
+// ArbSimulator replays market data through the kimchi premium arbitrage
+// lifecycle. A trade opens when a symbol's premium (of the tracker's formula)
+// reaches EntryMicros and no trade of the symbol is open: it buys the coin at
+// the foreign leg price, credits it domestically after the transfer delay
+// less its fee, sells it at the domestic leg price of that moment, withdraws
+// the KRW under the KRW policy and converts it back at the FX rate once the
+// withdrawal is through. Comparing the capture with the entry premium shows
+// what fees, transfer time and premium decay leave of the quoted spread.
+// Not goroutine-safe.
+type ArbSimulator struct {
+	cfg       ArbSimConfig
+	tracker   *PremiumTracker
+	trades    []*ArbTrade
+	open      map[string]*ArbTrade
+	arrive    map[*ArbTrade]quant.TimeStamp
+	premium   map[string]int64 // Last sample by symbol
+	withdrawn map[string]int64 // KRW withdrawn per day of the KRW policy's zone
+}
+
+// NewArbSimulator creates a simulator reading premiums and legs from tracker.
+func NewArbSimulator(cfg ArbSimConfig, tracker *PremiumTracker) *ArbSimulator {
+	return &ArbSimulator{
+		cfg:       cfg,
+		tracker:   tracker,
+		open:      make(map[string]*ArbTrade),
+		arrive:    make(map[*ArbTrade]quant.TimeStamp),
+		premium:   make(map[string]int64),
+		withdrawn: make(map[string]int64),
+	}
+}
+
+// Observe feeds one market or order book update.
+func (s *ArbSimulator) Observe(ev event.Event) {
+	var sample PremiumSample
+	var ok bool
+	switch e := ev.(type) {
+	case *event.MarketUpdateEvent:
+		sample, ok = s.tracker.Observe(e)
+	case *event.OrderBookUpdateEvent:
+		sample, ok = s.tracker.ObserveBook(e)
+	default:
+		return
+	}
+	if ok {
+		s.premium[sample.Symbol] = sample.PremiumMicros
+	}
+	s.advance(ev.GetTs())
+	if ok {
+		s.enter(sample)
+	}
+}
+
+// enter opens a trade on sample if it qualifies.
+func (s *ArbSimulator) enter(sample PremiumSample) {
+	if sample.PremiumMicros < s.cfg.EntryMicros || s.open[sample.Symbol] != nil {
+		return
+	}
+	foreign, ok := s.tracker.foreign[sample.Symbol]
+	if !ok || foreign.price <= 0 {
+		return
+	}
+	spec, ok := s.cfg.Transfers[sample.Symbol]
+	if !ok {
+		spec = s.cfg.DefaultTransfer
+	}
+	fee := safe.SafeMulDiv(s.cfg.NotionalMicros, s.cfg.ForeignFeeBps, 10_000)
+	qty := safe.SafeMulDiv(safe.SafeSub(s.cfg.NotionalMicros, fee), 100_000_000, int64(foreign.price))
+	if qty <= spec.FeeSats {
+		return
+	}
+	t := &ArbTrade{
+		Symbol:             sample.Symbol,
+		State:              ArbInTransit,
+		Opened:             sample.Ts,
+		EntryPremiumMicros: sample.PremiumMicros,
+		CostMicros:         s.cfg.NotionalMicros,
+		QtySats:            qty,
+		SoldSats:           qty - spec.FeeSats,
+	}
+	s.trades = append(s.trades, t)
+	s.open[t.Symbol] = t
+	s.arrive[t] = sample.Ts + quant.TimeStamp(spec.Delay.Microseconds())
+}
+
+// advance moves the open trades along to ts.
+func (s *ArbSimulator) advance(ts quant.TimeStamp) {
+	for symbol, t := range s.open {
+		switch t.State {
+		case ArbInTransit:
+			if ts >= s.arrive[t] {
+				s.sell(t, ts)
+			}
+		case ArbRepatriating:
+			if ts >= t.Settled {
+				s.settle(t, ts)
+			}
+		}
+		if t.State == ArbSettled || t.State == ArbFailed {
+			delete(s.open, symbol)
+			delete(s.arrive, t)
+		}
+	}
+}
+
+// sell sells the credited coins at the domestic leg price and schedules the
+// KRW withdrawal.
+func (s *ArbSimulator) sell(t *ArbTrade, ts quant.TimeStamp) {
+	domestic, ok := s.tracker.domestic[t.Symbol]
+	if !ok || domestic.ts < s.arrive[t]-s.tracker.maxSkew {
+		return // No domestic price since the coins arrived yet
+	}
+	gross := safe.SafeMulDiv(t.SoldSats, int64(domestic.price), 100_000_000)
+	net := safe.SafeMulDiv(gross, 10_000-s.cfg.DomesticFeeBps, 10_000)
+	t.Sold, t.ExitPremiumMicros, t.ProceedsKRW = ts, s.premium[t.Symbol], net/quant.PriceScale
+
+	at := time.UnixMicro(int64(ts))
+	day := s.cfg.KRW.midnight(at).Format(time.DateOnly)
+	leg, err := s.cfg.KRW.Withdraw(t.ProceedsKRW, at, KRWUsage{WithdrawnKRW: s.withdrawn[day]})
+	if err != nil {
+		t.State, t.Error = ArbFailed, err.Error()
+		return
+	}
+	s.withdrawn[day] = safe.SafeAdd(s.withdrawn[day], t.ProceedsKRW)
+	t.State, t.Settled, t.KRWFee = ArbRepatriating, quant.TimeStamp(leg.Ready.UnixMicro()), leg.FeeKRW
+}
+
+// settle converts the withdrawn KRW back at the FX rate of ts.
+func (s *ArbSimulator) settle(t *ArbTrade, ts quant.TimeStamp) {
+	rate, ok := s.tracker.fx.Rate(ForeignQuotes[s.tracker.formula.Foreign], domesticFiat, ts)
+	if !ok {
+		return // Wait for a fresh FX rate
+	}
+	krw := safe.SafeMul(safe.SafeSub(t.ProceedsKRW, t.KRWFee), quant.PriceScale)
+	t.ReturnMicros = safe.SafeMulDiv(krw, quant.PriceScale, int64(rate))
+	t.CaptureMicros = safe.SafeMulDiv(safe.SafeSub(t.ReturnMicros, t.CostMicros), 1_000_000, t.CostMicros)
+	t.State, t.Settled = ArbSettled, ts
+}
+
+// Report summarizes the trades so far.
+func (s *ArbSimulator) Report() ArbReport {
+	r := ArbReport{Trades: make([]ArbTrade, 0, len(s.trades))}
+	var entry, capture, hold int64
+	for _, t := range s.trades {
+		r.Trades = append(r.Trades, *t)
+		switch t.State {
+		case ArbSettled:
+			r.Settled++
+			entry = safe.SafeAdd(entry, t.EntryPremiumMicros)
+			capture = safe.SafeAdd(capture, t.CaptureMicros)
+			hold = safe.SafeAdd(hold, int64(t.Settled-t.Opened))
+			r.PnLMicros = safe.SafeAdd(r.PnLMicros, safe.SafeSub(t.ReturnMicros, t.CostMicros))
+		case ArbFailed:
+			r.Failed++
+		default:
+			r.Open++
+		}
+	}
+	if r.Settled > 0 {
+		n := int64(r.Settled)
+		r.AvgEntryMicros, r.AvgCaptureMicros, r.AvgHoldMicros = entry/n, capture/n, hold/n
+	}
+	if entry != 0 {
+		r.CaptureRatioBps = safe.SafeMulDiv(capture, 10_000, entry)
+	}
+	return r
+}
+
+// SimulateArb replays the market (and, for the bid/ask formula, order book)
+// updates in [from, to) from the WAL through sim.
+func SimulateArb(ctx context.Context, store *storage.EventStore, from, to quant.TimeStamp, sim *ArbSimulator) error {
+	return scanMarket(ctx, store, from, to, sim.tracker.formula.Price == PriceBidAsk, sim.Observe)
+}
@@ -0,0 +1,83 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"crypto_go/pkg/quant"
+)
+
+func TestArbSimulator(t *testing.T) {
+	policy := UpbitKRWTransferPolicy()
+	sim := NewArbSimulator(ArbSimConfig{
+		EntryMicros:     30_000, // 3%
+		NotionalMicros:  1_000_000_000,
+		ForeignFeeBps:   10,
+		DomesticFeeBps:  5,
+		Transfers:       map[string]TransferSpec{"BTC": {Delay: 30 * time.Minute, FeeSats: 50_000}},
+		DefaultTransfer: TransferSpec{Delay: time.Hour},
+		KRW:             policy,
+	}, NewPremiumTracker(0, 0))
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, policy.Zone)
+
+	sim.Observe(upd(1, at, "FX", "USD/KRW", 1_400))
+	sim.Observe(upd(2, at, "BITGET_SPOT", "BTC", 70_000))
+	sim.Observe(upd(3, at, "UPBIT", "BTC", 103_000_000)) // 5.10%: buy
+	sim.Observe(upd(4, at.Add(time.Minute), "UPBIT", "BTC", 103_000_000))
+	if r := sim.Report(); len(r.Trades) != 1 || r.Open != 1 {
+		t.Fatalf("expected one open trade, got %+v", r)
+	}
+
+	// The premium fades while the coins are in transit
+	sim.Observe(upd(5, at.Add(29*time.Minute), "FX", "USD/KRW", 1_400))
+	sim.Observe(upd(6, at.Add(29*time.Minute), "BITGET_SPOT", "BTC", 70_000))
+	sim.Observe(upd(7, at.Add(29*time.Minute), "UPBIT", "BTC", 100_000_000))
+	sim.Observe(upd(8, at.Add(30*time.Minute), "UPBIT", "BTC", 100_000_000)) // Arrived: sold
+	sim.Observe(upd(9, at.Add(31*time.Minute), "FX", "USD/KRW", 1_400))      // KRW out: settled
+
+	r := sim.Report()
+	if r.Settled != 1 || r.Open != 0 {
+		t.Fatalf("expected a settled trade, got %+v", r)
+	}
+	tr := r.Trades[0]
+	// 999 USDT buys 1,427,142 sats; 1,377,142 arrive and sell for 1,377,142 KRW less 5bp
+	if tr.QtySats != 1_427_142 || tr.SoldSats != 1_377_142 || tr.ProceedsKRW != 1_376_453 || tr.KRWFee != 1_000 {
+		t.Errorf("unexpected trade %+v", tr)
+	}
+	if tr.EntryPremiumMicros != 51_020 || tr.ExitPremiumMicros != 20_408 {
+		t.Errorf("unexpected premiums %+v", tr)
+	}
+	// 1,375,453 KRW / 1,400 = 982.466428 USDT: the 5.1% spread ended a 1.75% loss
+	if tr.ReturnMicros != 982_466_428 || tr.CaptureMicros != -17_533 || r.PnLMicros != -17_533_572 {
+		t.Errorf("unexpected capture %+v", tr)
+	}
+	if r.CaptureRatioBps != -3_436 || r.AvgHoldMicros != int64(31*time.Minute/time.Microsecond) {
+		t.Errorf("unexpected report %+v", r)
+	}
+
+	// The next trade sells inside the bank maintenance window: the KRW waits
+	night := time.Date(2026, 3, 10, 23, 0, 0, 0, policy.Zone)
+	sim.Observe(upd(10, night, "FX", "USD/KRW", 1_400))
+	sim.Observe(upd(11, night, "BITGET_SPOT", "BTC", 70_000))
+	sim.Observe(upd(12, night, "UPBIT", "BTC", 103_000_000))
+	sim.Observe(upd(13, night.Add(45*time.Minute), "UPBIT", "BTC", 103_000_000))
+	if tr := sim.Report().Trades[1]; tr.State != ArbRepatriating || tr.Settled != quant.TimeStamp(night.Add(90*time.Minute).UnixMicro()) {
+		t.Errorf("expected the KRW out at 00:30, got %+v", tr)
+	}
+}
+
+func TestArbSimulator_Failed(t *testing.T) {
+	policy := UpbitKRWTransferPolicy()
+	policy.DailyWithdrawKRW = 1_000 // Below the minimum withdrawal
+	sim := NewArbSimulator(ArbSimConfig{EntryMicros: 10_000, NotionalMicros: 1_000_000_000, KRW: policy}, NewPremiumTracker(0, 0))
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, policy.Zone)
+
+	sim.Observe(upd(1, at, "FX", "USD/KRW", 1_400))
+	sim.Observe(upd(2, at, "BITGET_SPOT", "BTC", 70_000))
+	sim.Observe(upd(3, at, "UPBIT", "BTC", 103_000_000))
+	sim.Observe(upd(4, at.Add(time.Second), "UPBIT", "BTC", 103_000_000)) // No transfer delay: sold
+	// The failed trade frees the symbol for the next one
+	if r := sim.Report(); r.Failed != 1 || r.Trades[0].Error == "" || r.Open != 1 {
+		t.Fatalf("expected a failed and a new trade, got %+v", r)
+	}
+}
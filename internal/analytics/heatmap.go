@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"crypto_go/pkg/safe"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HeatmapCell aggregates premium samples of one symbol in one hour of the day.
+type HeatmapCell struct {
+	Samples   int64 `json:"samples"`
+	AvgMicros int64 `json:"avg"`
+	MinMicros int64 `json:"min"`
+	MaxMicros int64 `json:"max"`
+}
+
+// HeatmapRow is one symbol's 24 hourly cells (index = hour of day in the heatmap zone).
+type HeatmapRow struct {
+	Symbol string          `json:"symbol"`
+	Hours  [24]HeatmapCell `json:"hours"`
+}
+
+// PremiumHeatmap is premium by symbol x hour of day, pooled over many days, to
+// reveal recurring intraday patterns (e.g. the premium widening at the KST open).
+type PremiumHeatmap struct {
+	Zone string       `json:"zone"`
+	Rows []HeatmapRow `json:"rows"`
+}
+
+type cellAcc struct {
+	n, sum, lo, hi int64
+}
+
+// HeatmapBuilder accumulates samples. Not goroutine-safe.
+type HeatmapBuilder struct {
+	zone  *time.Location
+	cells map[string]*[24]cellAcc
+}
+
+// NewHeatmapBuilder buckets samples by the hour of day in zone.
+func NewHeatmapBuilder(zone *time.Location) *HeatmapBuilder {
+	return &HeatmapBuilder{zone: zone, cells: make(map[string]*[24]cellAcc)}
+}
+
+// Add records one sample.
+func (b *HeatmapBuilder) Add(s PremiumSample) {
+	row := b.cells[s.Symbol]
+	if row == nil {
+		row = new([24]cellAcc)
+		b.cells[s.Symbol] = row
+	}
+	c := &row[time.UnixMicro(int64(s.Ts)).In(b.zone).Hour()]
+	if c.n == 0 {
+		c.lo, c.hi = s.PremiumMicros, s.PremiumMicros
+	}
+	c.n++
+	c.sum = safe.SafeAdd(c.sum, s.PremiumMicros)
+	c.lo = min(c.lo, s.PremiumMicros)
+	c.hi = max(c.hi, s.PremiumMicros)
+}
+
+// Result returns the heatmap with rows sorted by symbol.
+func (b *HeatmapBuilder) Result() PremiumHeatmap {
+	out := PremiumHeatmap{Zone: b.zone.String(), Rows: make([]HeatmapRow, 0, len(b.cells))}
+	for symbol, row := range b.cells {
+		r := HeatmapRow{Symbol: symbol}
+		for h, c := range row {
+			if c.n > 0 {
+				r.Hours[h] = HeatmapCell{Samples: c.n, AvgMicros: c.sum / c.n, MinMicros: c.lo, MaxMicros: c.hi}
+			}
+		}
+		out.Rows = append(out.Rows, r)
+	}
+	sort.Slice(out.Rows, func(i, j int) bool { return out.Rows[i].Symbol < out.Rows[j].Symbol })
+	return out
+}
+
+// Text renders average premiums as a table of percentages (two decimals), one row
+// per symbol and one column per hour. Empty cells show "-".
+func (h PremiumHeatmap) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-6s", h.Zone)
+	for hour := 0; hour < 24; hour++ {
+		fmt.Fprintf(&b, " %6s", fmt.Sprintf("%02dh", hour))
+	}
+	b.WriteByte('\n')
+	for _, row := range h.Rows {
+		fmt.Fprintf(&b, "%-6s", row.Symbol)
+		for _, c := range row.Hours {
+			if c.Samples == 0 {
+				fmt.Fprintf(&b, " %6s", "-")
+				continue
+			}
+			fmt.Fprintf(&b, " %6s", FormatPct(c.AvgMicros))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// FormatPct renders premium Micros (1% = 10,000) as a percentage with two decimals.
+func FormatPct(micros int64) string {
+	sign := ""
+	if micros < 0 {
+		sign, micros = "-", -micros
+	}
+	bps := micros / 100
+	return fmt.Sprintf("%s%d.%02d", sign, bps/100, bps%100)
+}
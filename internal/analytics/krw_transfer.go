@@ -0,0 +1,222 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto_go/pkg/safe"
+)
+
+// ErrKRWTransfer is wrapped by the errors of KRWTransferPolicy.
+var ErrKRWTransfer = errors.New("krw transfer not feasible")
+
+// maxTransferDays bounds how far daily limits may push a transfer.
+const maxTransferDays = 366
+
+// ClockWindow is a daily span of wall-clock time in the policy's zone, as
+// offsets from midnight. A window with To before From wraps midnight.
+type ClockWindow struct {
+	From, To time.Duration
+}
+
+func (w ClockWindow) contains(offset time.Duration) bool {
+	if w.From <= w.To {
+		return offset >= w.From && offset < w.To
+	}
+	return offset >= w.From || offset < w.To
+}
+
+// KRWTransferPolicy models the KRW side of a kimchi premium trade on a
+// domestic exchange: moving KRW between the exchange and the bank. Amounts
+// are whole KRW; 0 disables a limit.
+//
+// A positive premium is taken by selling coins for KRW and withdrawing the
+// KRW; a negative one by depositing KRW and buying coins, which the exchange
+// only lets leave after DepositHold. Either way the trade is not closed until
+// the KRW leg settles, so the premium has to survive the wait.
+type KRWTransferPolicy struct {
+	WithdrawFeeKRW   int64         // Per withdrawal
+	MinWithdrawKRW   int64         // Smallest withdrawal
+	MaxWithdrawKRW   int64         // Largest single withdrawal (larger amounts are split)
+	DailyWithdrawKRW int64         // Withdrawals per day
+	DailyDepositKRW  int64         // Deposits per day
+	DepositHold      time.Duration // Coins bought with deposited KRW cannot be withdrawn before
+	Downtime         []ClockWindow // Bank maintenance: no KRW moves
+	Zone             *time.Location
+}
+
+// UpbitKRWTransferPolicy returns defaults modeled on Upbit's KRW rules: a
+// 1,000 KRW withdrawal fee, 5,000 KRW minimum, a 24h hold on coins bought
+// with a new deposit and a nightly bank maintenance window (23:30-00:30 KST).
+// The exchange changes these by notice; daily limits depend on the account's
+// verification level and are left to the caller.
+func UpbitKRWTransferPolicy() KRWTransferPolicy {
+	return KRWTransferPolicy{
+		WithdrawFeeKRW: 1_000,
+		MinWithdrawKRW: 5_000,
+		DepositHold:    24 * time.Hour,
+		Downtime:       []ClockWindow{{From: 23*time.Hour + 30*time.Minute, To: 30 * time.Minute}},
+		Zone:           time.FixedZone("KST", 9*60*60),
+	}
+}
+
+// KRWUsage is what was already moved on the current day of the policy's zone.
+type KRWUsage struct {
+	WithdrawnKRW int64
+	DepositedKRW int64
+}
+
+// KRWLeg is when and at what cost the KRW side of a trade settles.
+type KRWLeg struct {
+	Start     time.Time // First transfer (after any bank downtime)
+	Ready     time.Time // Last transfer, or the end of the deposit hold
+	Transfers int       // Withdrawals or deposits needed
+	FeeKRW    int64
+}
+
+// Withdraw schedules withdrawing amountKRW from at on: split by the per-
+// withdrawal limit, pushed to the following days by the daily limit and out
+// of bank downtime.
+func (p KRWTransferPolicy) Withdraw(amountKRW int64, at time.Time, used KRWUsage) (KRWLeg, error) {
+	if amountKRW < max(p.MinWithdrawKRW, 1) {
+		return KRWLeg{}, fmt.Errorf("%w: withdrawal of %d KRW is below the %d KRW minimum", ErrKRWTransfer, amountKRW, p.MinWithdrawKRW)
+	}
+	leg, err := p.schedule(amountKRW, used.WithdrawnKRW, p.DailyWithdrawKRW, p.MaxWithdrawKRW, max(p.MinWithdrawKRW, 1), at)
+	if err != nil {
+		return KRWLeg{}, err
+	}
+	leg.FeeKRW = safe.SafeMul(p.WithdrawFeeKRW, int64(leg.Transfers))
+	return leg, nil
+}
+
+// Deposit schedules depositing amountKRW from at on; the leg is ready when
+// the coins bought with the last deposit may be withdrawn.
+func (p KRWTransferPolicy) Deposit(amountKRW int64, at time.Time, used KRWUsage) (KRWLeg, error) {
+	if amountKRW <= 0 {
+		return KRWLeg{}, fmt.Errorf("%w: deposit of %d KRW", ErrKRWTransfer, amountKRW)
+	}
+	leg, err := p.schedule(amountKRW, used.DepositedKRW, p.DailyDepositKRW, 0, 1, at)
+	if err != nil {
+		return KRWLeg{}, err
+	}
+	leg.Ready = leg.Ready.Add(p.DepositHold)
+	return leg, nil
+}
+
+// schedule spreads amount over days of at most daily (after used today),
+// in transfers of minimum to perTransfer, each outside downtime.
+func (p KRWTransferPolicy) schedule(amount, used, daily, perTransfer, minimum int64, at time.Time) (KRWLeg, error) {
+	if daily > 0 && daily < minimum {
+		return KRWLeg{}, fmt.Errorf("%w: daily limit %d KRW is below the %d KRW minimum", ErrKRWTransfer, daily, minimum)
+	}
+	t := p.open(at)
+	leg := KRWLeg{Start: t}
+	for days := 0; amount > 0; {
+		room := amount
+		if daily > 0 {
+			room = min(room, daily-used)
+		}
+		// A day's leftover room too small for a withdrawal is skipped
+		if room <= 0 || (room < amount && room < minimum) {
+			if days++; days > maxTransferDays {
+				return KRWLeg{}, fmt.Errorf("%w: daily limits spread it over more than %d days", ErrKRWTransfer, maxTransferDays)
+			}
+			t, used = p.open(p.nextDay(t)), 0
+			if leg.Transfers == 0 {
+				leg.Start = t
+			}
+			continue
+		}
+		transfers := int64(1)
+		if perTransfer > 0 {
+			transfers = (room + perTransfer - 1) / perTransfer
+		}
+		leg.Transfers += int(transfers)
+		leg.Ready = t
+		amount -= room
+		used += room
+	}
+	return leg, nil
+}
+
+// open returns the first time at or after t outside every downtime window.
+func (p KRWTransferPolicy) open(t time.Time) time.Time {
+	t = t.In(p.zone())
+	for range len(p.Downtime) + 1 {
+		midnight := p.midnight(t)
+		moved := false
+		for _, w := range p.Downtime {
+			if !w.contains(t.Sub(midnight)) {
+				continue
+			}
+			end := midnight.Add(w.To)
+			if !end.After(t) {
+				end = p.midnight(midnight.Add(36 * time.Hour)).Add(w.To) // Wrapped window ends tomorrow
+			}
+			t, moved = end, true
+		}
+		if !moved {
+			break
+		}
+	}
+	return t
+}
+
+// nextDay returns the midnight after t (daily limits reset).
+func (p KRWTransferPolicy) nextDay(t time.Time) time.Time {
+	return p.midnight(p.midnight(t).Add(36 * time.Hour))
+}
+
+func (p KRWTransferPolicy) midnight(t time.Time) time.Time {
+	t = t.In(p.zone())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (p KRWTransferPolicy) zone() *time.Location {
+	if p.Zone == nil {
+		return time.UTC
+	}
+	return p.Zone
+}
+
+// Opportunity is a premium sample scored for execution: the premium net of
+// the KRW transfer fees, less the decay expected while the KRW leg settles.
+type Opportunity struct {
+	Symbol        string
+	PremiumMicros int64 // 1% = 10,000; negative = reverse premium
+	NetMicros     int64 // |premium| after KRW fees
+	ScoreMicros   int64 // NetMicros less decay over Wait (<= 0: not worth it)
+	Leg           KRWLeg
+	Wait          time.Duration // From the sample until the KRW leg settles
+}
+
+// ScoreOpportunity scores trading notionalKRW on sample s: a positive premium
+// needs the KRW withdrawn, a negative one deposited. decayPerHourMicros is
+// how much premium the caller expects to lose per hour of waiting (e.g. from
+// ScanPremiums history).
+func ScoreOpportunity(s PremiumSample, notionalKRW int64, p KRWTransferPolicy, used KRWUsage, decayPerHourMicros int64) (Opportunity, error) {
+	at := time.UnixMicro(int64(s.Ts))
+	var leg KRWLeg
+	var err error
+	if s.PremiumMicros >= 0 {
+		leg, err = p.Withdraw(notionalKRW, at, used)
+	} else {
+		leg, err = p.Deposit(notionalKRW, at, used)
+	}
+	if err != nil {
+		return Opportunity{}, err
+	}
+
+	o := Opportunity{Symbol: s.Symbol, PremiumMicros: s.PremiumMicros, Leg: leg, Wait: leg.Ready.Sub(at)}
+	o.NetMicros = safe.SafeSub(absMicros(s.PremiumMicros), safe.SafeMulDiv(leg.FeeKRW, 1_000_000, notionalKRW))
+	o.ScoreMicros = safe.SafeSub(o.NetMicros, safe.SafeMulDiv(decayPerHourMicros, int64(o.Wait), int64(time.Hour)))
+	return o, nil
+}
+
+func absMicros(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"crypto_go/pkg/quant"
+)
+
+func TestKRWTransferPolicy_Withdraw(t *testing.T) {
+	p := UpbitKRWTransferPolicy()
+	p.MaxWithdrawKRW = 50_000_000
+	p.DailyWithdrawKRW = 100_000_000
+	kst := p.Zone
+
+	// Inside the bank maintenance window: starts at 00:30
+	at := time.Date(2026, 3, 10, 23, 45, 0, 0, kst)
+	leg, err := p.Withdraw(30_000_000, at, KRWUsage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 11, 0, 30, 0, 0, kst); !leg.Start.Equal(want) || !leg.Ready.Equal(want) {
+		t.Errorf("expected the withdrawal at %v, got %+v", want, leg)
+	}
+	if leg.Transfers != 1 || leg.FeeKRW != 1_000 {
+		t.Errorf("unexpected transfers/fee: %+v", leg)
+	}
+
+	// 150M with 80M used today: 20M now (1 withdrawal), 100M tomorrow (2), 30M the day after (1)
+	at = time.Date(2026, 3, 10, 14, 0, 0, 0, kst)
+	leg, err = p.Withdraw(150_000_000, at, KRWUsage{WithdrawnKRW: 80_000_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !leg.Start.Equal(at) || !leg.Ready.Equal(time.Date(2026, 3, 12, 0, 30, 0, 0, kst)) {
+		t.Errorf("unexpected schedule %+v", leg)
+	}
+	if leg.Transfers != 4 || leg.FeeKRW != 4_000 {
+		t.Errorf("unexpected transfers/fee: %+v", leg)
+	}
+
+	// Leftover room below the minimum is skipped
+	leg, err = p.Withdraw(10_000, at, KRWUsage{WithdrawnKRW: 99_998_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leg.Transfers != 1 || !leg.Start.Equal(time.Date(2026, 3, 11, 0, 30, 0, 0, kst)) {
+		t.Errorf("expected one withdrawal tomorrow, got %+v", leg)
+	}
+
+	if _, err := p.Withdraw(1_000, at, KRWUsage{}); !errors.Is(err, ErrKRWTransfer) {
+		t.Errorf("below minimum: err = %v", err)
+	}
+	p.DailyWithdrawKRW = 1_000
+	if _, err := p.Withdraw(10_000, at, KRWUsage{}); !errors.Is(err, ErrKRWTransfer) {
+		t.Errorf("daily limit below minimum: err = %v", err)
+	}
+}
+
+func TestKRWTransferPolicy_Deposit(t *testing.T) {
+	p := UpbitKRWTransferPolicy()
+	p.DailyDepositKRW = 50_000_000
+	at := time.Date(2026, 3, 10, 10, 0, 0, 0, p.Zone)
+
+	leg, err := p.Deposit(80_000_000, at, KRWUsage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The rest goes in at 00:30 tomorrow, its coins are free 24h later
+	if want := time.Date(2026, 3, 12, 0, 30, 0, 0, p.Zone); leg.Transfers != 2 || !leg.Ready.Equal(want) || leg.FeeKRW != 0 {
+		t.Errorf("expected ready at %v, got %+v", want, leg)
+	}
+}
+
+func TestScoreOpportunity(t *testing.T) {
+	p := UpbitKRWTransferPolicy()
+	p.DailyWithdrawKRW = 10_000_000
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, p.Zone)
+	ts := quant.TimeStamp(at.UnixMicro())
+
+	// 3% premium on 10M KRW, withdrawn at once: 1,000 KRW fee = 100 micros
+	o, err := ScoreOpportunity(PremiumSample{Ts: ts, Symbol: "BTC", PremiumMicros: 30_000}, 10_000_000, p, KRWUsage{}, 1_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.NetMicros != 29_900 || o.Wait != 0 || o.ScoreMicros != 29_900 {
+		t.Errorf("unexpected opportunity %+v", o)
+	}
+
+	// Today's limit used up: the KRW leaves after 10.5h of decay
+	o, err = ScoreOpportunity(PremiumSample{Ts: ts, Symbol: "BTC", PremiumMicros: 30_000}, 10_000_000, p, KRWUsage{WithdrawnKRW: 10_000_000}, 1_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Wait != 10*time.Hour+30*time.Minute || o.ScoreMicros != 29_900-10_500 {
+		t.Errorf("unexpected delayed opportunity %+v", o)
+	}
+
+	// A reverse premium deposits KRW and waits out the 24h hold
+	o, err = ScoreOpportunity(PremiumSample{Ts: ts, Symbol: "BTC", PremiumMicros: -20_000}, 10_000_000, p, KRWUsage{}, 1_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.NetMicros != 20_000 || o.Wait != 24*time.Hour || o.ScoreMicros != -4_000 {
+		t.Errorf("unexpected reverse opportunity %+v", o)
+	}
+}
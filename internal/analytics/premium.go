@@ -0,0 +1,278 @@
+// Package analytics derives research views (premium history, heatmaps) from the WAL.
+// Everything here is read-only and off the hotpath.
+package analytics
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Sources of the kimchi premium legs, as stamped by the gateways.
+const (
+	defaultDomestic = "UPBIT"
+	defaultForeign  = "BITGET_SPOT"
+	fxExchange      = "FX"
+	domesticFiat    = "KRW"
+)
+
+// DomesticExchanges are the KRW venues a premium can be computed for.
+var DomesticExchanges = []string{"UPBIT", "BITHUMB", "COINONE"}
+
+// ForeignQuotes are the venues a premium can be referenced to, with the fiat
+// their prices are converted from (USDT counts as USD). A JPY or EUR venue only
+// needs an entry here and an FX pair that resolves to KRW (e.g. USD/JPY).
+var ForeignQuotes = map[string]string{
+	"BITGET_SPOT":    "USD",
+	"BITGET_FUTURES": "USD",
+	"OKX_SPOT":       "USD",
+	"OKX_SWAP":       "USD",
+	"BYBIT_SPOT":     "USD",
+	"BYBIT_LINEAR":   "USD",
+}
+
+// Price bases of a premium formula.
+const (
+	PriceLast   = "last"    // Last trade price on both legs
+	PriceBidAsk = "bid_ask" // Executable: domestic best bid vs foreign best ask (needs recorded order books)
+)
+
+// PremiumFormula selects what "the premium" is measured against. Users quote
+// different numbers depending on the reference, so it is configurable.
+type PremiumFormula struct {
+	Domestic string // KRW venue (DomesticExchanges)
+	Foreign  string // Reference venue (ForeignQuotes)
+	Price    string // PriceLast or PriceBidAsk
+}
+
+// DefaultPremiumFormula is Upbit last price vs Bitget spot last price.
+func DefaultPremiumFormula() PremiumFormula {
+	return PremiumFormula{Domestic: defaultDomestic, Foreign: defaultForeign, Price: PriceLast}
+}
+
+// Validate checks every field against the supported venues and price bases.
+func (f PremiumFormula) Validate() error {
+	if !slices.Contains(DomesticExchanges, f.Domestic) {
+		return fmt.Errorf("unknown domestic exchange %q", f.Domestic)
+	}
+	if _, ok := ForeignQuotes[f.Foreign]; !ok {
+		return fmt.Errorf("unknown foreign exchange %q", f.Foreign)
+	}
+	if f.Price != PriceLast && f.Price != PriceBidAsk {
+		return fmt.Errorf("unknown premium price basis %q (want %s or %s)", f.Price, PriceLast, PriceBidAsk)
+	}
+	return nil
+}
+
+// DefaultMaxSkew is how far apart the two legs (and the FX rate) may be in time
+// for a premium sample to count. The FX feed polls slowly, so it gets FXMaxAge.
+const (
+	DefaultMaxSkew  = 5 * time.Second
+	DefaultFXMaxAge = 10 * time.Minute
+)
+
+// PremiumSample is the kimchi premium of one symbol at one moment.
+type PremiumSample struct {
+	Ts            quant.TimeStamp
+	Symbol        string
+	PremiumMicros int64 // 1% = 10,000
+}
+
+type premiumBookKey struct{ exchange, symbol string }
+
+type leg struct {
+	price quant.PriceMicros
+	ts    quant.TimeStamp
+}
+
+// PremiumTracker turns a stream of market updates (and, for PriceBidAsk, order
+// book updates) into premium samples. Not goroutine-safe.
+type PremiumTracker struct {
+	formula PremiumFormula
+
+	maxSkew  quant.TimeStamp
+	domestic map[string]leg
+	foreign  map[string]leg
+	books    map[premiumBookKey]*domain.OrderBook // PriceBidAsk only
+	fx       *domain.FXRates
+}
+
+// NewPremiumTracker creates a tracker for the default formula. Zero durations use the defaults.
+func NewPremiumTracker(maxSkew, fxMaxAge time.Duration) *PremiumTracker {
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxSkew
+	}
+	if fxMaxAge <= 0 {
+		fxMaxAge = DefaultFXMaxAge
+	}
+	return &PremiumTracker{
+		formula:  DefaultPremiumFormula(),
+		maxSkew:  quant.TimeStamp(maxSkew.Microseconds()),
+		domestic: make(map[string]leg),
+		foreign:  make(map[string]leg),
+		books:    make(map[premiumBookKey]*domain.OrderBook),
+		fx:       domain.NewFXRates(quant.TimeStamp(fxMaxAge.Microseconds())),
+	}
+}
+
+// SetFXMaxAge overrides how old the rate of one FX pair (e.g. "USD/JPY") may be.
+func (t *PremiumTracker) SetFXMaxAge(pair string, maxAge time.Duration) {
+	t.fx.SetMaxAge(pair, quant.TimeStamp(maxAge.Microseconds()))
+}
+
+// SetDomesticExchange selects the KRW venue of the domestic leg (one of
+// DomesticExchanges), so premiums of different KRW exchanges can be compared.
+func (t *PremiumTracker) SetDomesticExchange(exchange string) {
+	f := t.formula
+	f.Domestic = exchange
+	t.SetFormula(f)
+}
+
+// SetFormula selects the legs and price basis (validate with f.Validate first).
+func (t *PremiumTracker) SetFormula(f PremiumFormula) {
+	t.formula = f
+	clear(t.domestic)
+	clear(t.foreign)
+	clear(t.books)
+}
+
+// Observe feeds one market update. It returns a sample when a price leg changed
+// and the other leg and the FX rate are recent enough. With PriceBidAsk only the
+// FX rate is taken from market updates; the legs come from ObserveBook.
+func (t *PremiumTracker) Observe(e *event.MarketUpdateEvent) (PremiumSample, bool) {
+	if e.Exchange == fxExchange {
+		t.fx.Update(e.Symbol, e.PriceMicros, e.Ts)
+		return PremiumSample{}, false
+	}
+	if t.formula.Price != PriceLast {
+		return PremiumSample{}, false
+	}
+	if !t.setLeg(e.Exchange, e.Symbol, leg{price: e.PriceMicros, ts: e.Ts}) {
+		return PremiumSample{}, false
+	}
+	return t.sample(e.Symbol, e.Ts)
+}
+
+// ObserveBook feeds one order book update (PriceBidAsk only). The domestic leg
+// is the best bid (where the coin can be sold in KRW) and the foreign leg the
+// best ask (where it can be bought), so the premium is what a trade would capture.
+func (t *PremiumTracker) ObserveBook(e *event.OrderBookUpdateEvent) (PremiumSample, bool) {
+	if t.formula.Price != PriceBidAsk || (e.Exchange != t.formula.Domestic && e.Exchange != t.formula.Foreign) {
+		return PremiumSample{}, false
+	}
+	key := premiumBookKey{e.Exchange, e.Symbol}
+	book, ok := t.books[key]
+	if !ok {
+		book = domain.NewOrderBook(e.Exchange, e.Symbol)
+		t.books[key] = book
+	}
+	if !book.Apply(e.Snapshot, e.Bids, e.Asks, e.Ts) {
+		return PremiumSample{}, false
+	}
+
+	best, ok := book.BestBid()
+	if e.Exchange == t.formula.Foreign {
+		best, ok = book.BestAsk()
+	}
+	if !ok || !t.setLeg(e.Exchange, e.Symbol, leg{price: best.PriceMicros, ts: e.Ts}) {
+		return PremiumSample{}, false
+	}
+	return t.sample(e.Symbol, e.Ts)
+}
+
+// setLeg records l as the domestic or foreign leg of symbol. Returns false if
+// exchange is neither.
+func (t *PremiumTracker) setLeg(exchange, symbol string, l leg) bool {
+	switch exchange {
+	case t.formula.Domestic:
+		t.domestic[symbol] = l
+	case t.formula.Foreign:
+		t.foreign[symbol] = l
+	default:
+		return false
+	}
+	return true
+}
+
+func (t *PremiumTracker) sample(symbol string, ts quant.TimeStamp) (PremiumSample, bool) {
+	dom, ok1 := t.domestic[symbol]
+	fgn, ok2 := t.foreign[symbol]
+	if !ok1 || !ok2 || absTs(dom.ts-fgn.ts) > t.maxSkew {
+		return PremiumSample{}, false
+	}
+	rate, ok := t.fx.Rate(ForeignQuotes[t.formula.Foreign], domesticFiat, ts)
+	if !ok {
+		return PremiumSample{}, false
+	}
+
+	premium, ok := domain.KimchiPremiumMicros(dom.price, fgn.price, rate)
+	if !ok {
+		return PremiumSample{}, false
+	}
+	return PremiumSample{Ts: ts, Symbol: symbol, PremiumMicros: premium}, true
+}
+
+// ScanPremiums replays market (and order book) updates in [from, to) from the
+// WAL through a tracker and calls fn for every premium sample. FX ticks from
+// before from are not seen, so the first minutes of a range may lack samples
+// until the next FX poll.
+func ScanPremiums(ctx context.Context, store *storage.EventStore, from, to quant.TimeStamp, tracker *PremiumTracker, fn func(PremiumSample)) error {
+	return scanMarket(ctx, store, from, to, tracker.formula.Price == PriceBidAsk, func(ev event.Event) {
+		var sample PremiumSample
+		var ok bool
+		switch e := ev.(type) {
+		case *event.OrderBookUpdateEvent:
+			sample, ok = tracker.ObserveBook(e)
+		case *event.MarketUpdateEvent:
+			sample, ok = tracker.Observe(e)
+		}
+		if ok {
+			fn(sample)
+		}
+	})
+}
+
+// scanMarket calls fn with the market updates in [from, to) from the WAL, in
+// order, and with the order book updates between them if books is set.
+func scanMarket(ctx context.Context, store *storage.EventStore, from, to quant.TimeStamp, books bool, fn func(event.Event)) error {
+	rows, err := store.DB().QueryContext(ctx,
+		"SELECT type, payload FROM events WHERE type IN (?, ?) AND ts >= ? AND ts < ? ORDER BY id ASC",
+		event.EvMarketUpdate, event.EvOrderBook, from, to,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query market updates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var typ event.Type
+		var payload []byte
+		if err := rows.Scan(&typ, &payload); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		if typ == event.EvOrderBook && !books {
+			continue
+		}
+		ev, err := event.Decode(typ, payload)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal %s: %w", typ, err)
+		}
+		fn(ev)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+	return nil
+}
+
+func absTs(v quant.TimeStamp) quant.TimeStamp {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
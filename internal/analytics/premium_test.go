@@ -0,0 +1,177 @@
+package analytics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+func upd(seq uint64, at time.Time, exchange, symbol string, price int64) *event.MarketUpdateEvent {
+	return &event.MarketUpdateEvent{
+		BaseEvent:   event.BaseEvent{Seq: seq, Ts: quant.TimeStamp(at.UnixMicro())},
+		Exchange:    exchange,
+		Symbol:      symbol,
+		PriceMicros: quant.PriceMicros(price * quant.PriceScale),
+	}
+}
+
+func TestPremiumTracker(t *testing.T) {
+	tr := NewPremiumTracker(0, 0)
+	at := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	if _, ok := tr.Observe(upd(1, at, "UPBIT", "BTC", 103_000_000)); ok {
+		t.Error("no sample without the foreign leg")
+	}
+	if _, ok := tr.Observe(upd(2, at, "BITGET_SPOT", "BTC", 70_000)); ok {
+		t.Error("no sample without an FX rate")
+	}
+	tr.Observe(upd(3, at, "FX", "USD/KRW", 1_400))
+
+	s, ok := tr.Observe(upd(4, at.Add(time.Second), "UPBIT", "BTC", 103_000_000))
+	if !ok || s.PremiumMicros != 51_020 || s.Symbol != "BTC" {
+		t.Errorf("unexpected sample %+v (%v)", s, ok)
+	}
+
+	// Foreign leg now 10s old: skewed beyond the default 5s
+	if _, ok := tr.Observe(upd(5, at.Add(10*time.Second), "UPBIT", "BTC", 103_000_000)); ok {
+		t.Error("stale leg must not produce a sample")
+	}
+}
+
+func TestPremiumTracker_DomesticExchange(t *testing.T) {
+	tr := NewPremiumTracker(0, 0)
+	tr.SetDomesticExchange("BITHUMB")
+	at := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tr.Observe(upd(1, at, "FX", "USD/KRW", 1_400))
+	tr.Observe(upd(2, at, "BITGET_SPOT", "BTC", 70_000))
+	if _, ok := tr.Observe(upd(3, at, "UPBIT", "BTC", 103_000_000)); ok {
+		t.Error("Upbit is not the domestic leg any more")
+	}
+	s, ok := tr.Observe(upd(4, at, "BITHUMB", "BTC", 98_000_000))
+	if !ok || s.PremiumMicros != 0 {
+		t.Errorf("unexpected sample %+v (%v)", s, ok)
+	}
+}
+
+func TestPremiumTracker_BidAskFormula(t *testing.T) {
+	tr := NewPremiumTracker(0, 0)
+	tr.SetFormula(PremiumFormula{Domestic: "UPBIT", Foreign: "BITGET_FUTURES", Price: PriceBidAsk})
+	at := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	ts := quant.TimeStamp(at.UnixMicro())
+	book := func(exchange string, bid, ask int64) *event.OrderBookUpdateEvent {
+		ev := &event.OrderBookUpdateEvent{Exchange: exchange, Symbol: "BTC", Snapshot: true,
+			Bids: []domain.BookLevel{{PriceMicros: quant.PriceMicros(bid * quant.PriceScale), QtySats: 1}},
+			Asks: []domain.BookLevel{{PriceMicros: quant.PriceMicros(ask * quant.PriceScale), QtySats: 1}}}
+		ev.Ts = ts
+		return ev
+	}
+
+	tr.Observe(upd(1, at, "FX", "USD/KRW", 1_000))
+	if _, ok := tr.Observe(upd(2, at, "UPBIT", "BTC", 200_000_000)); ok {
+		t.Error("last prices must be ignored in bid_ask mode")
+	}
+	tr.ObserveBook(book("BITGET_SPOT", 1, 2)) // Not the reference venue
+	tr.ObserveBook(book("BITGET_FUTURES", 99_000, 100_000))
+	// Sell on Upbit at the bid (103M) vs buy on Bitget futures at the ask (100k USDT = 100M KRW): +3%
+	s, ok := tr.ObserveBook(book("UPBIT", 103_000_000, 104_000_000))
+	if !ok || s.PremiumMicros != 30_000 {
+		t.Errorf("unexpected sample %+v (%v)", s, ok)
+	}
+}
+
+func TestPremiumTracker_CrossRateVenue(t *testing.T) {
+	ForeignQuotes["TEST_JPY"] = "JPY"
+	t.Cleanup(func() { delete(ForeignQuotes, "TEST_JPY") })
+
+	tr := NewPremiumTracker(0, 0)
+	tr.SetFormula(PremiumFormula{Domestic: "UPBIT", Foreign: "TEST_JPY", Price: PriceLast})
+	at := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tr.Observe(upd(1, at, "FX", "USD/KRW", 1_400))
+	tr.Observe(upd(2, at, "TEST_JPY", "BTC", 10_000_000)) // 10M JPY
+	if _, ok := tr.Observe(upd(3, at, "UPBIT", "BTC", 105_000_000)); ok {
+		t.Fatal("no JPY rate yet: no sample")
+	}
+	tr.Observe(upd(4, at, "FX", "USD/JPY", 140)) // JPY/KRW = 10
+	s, ok := tr.Observe(upd(5, at, "UPBIT", "BTC", 105_000_000))
+	if !ok || s.PremiumMicros != 50_000 {
+		t.Errorf("unexpected sample %+v (%v)", s, ok)
+	}
+}
+
+func TestPremiumFormula_Validate(t *testing.T) {
+	if err := DefaultPremiumFormula().Validate(); err != nil {
+		t.Errorf("default formula: %v", err)
+	}
+	bad := []PremiumFormula{
+		{Domestic: "KORBIT", Foreign: "BITGET_SPOT", Price: PriceLast},
+		{Domestic: "UPBIT", Foreign: "BINANCE_SPOT", Price: PriceLast},
+		{Domestic: "UPBIT", Foreign: "BITGET_SPOT", Price: "mid"},
+	}
+	for _, f := range bad {
+		if err := f.Validate(); err == nil {
+			t.Errorf("%+v must be rejected", f)
+		}
+	}
+}
+
+func TestPremiumHeatmap_FromStore(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/premium.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	// Two days; at 09:00 KST (00:00 UTC) the premium is +5%, at 15:00 KST it is 0%
+	var seq uint64
+	save := func(ev *event.MarketUpdateEvent) {
+		seq++
+		ev.Seq = seq
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+	for day := 0; day < 2; day++ {
+		open := time.Date(2026, 3, 10+day, 0, 0, 0, 0, time.UTC)
+		afternoon := open.Add(6 * time.Hour)
+		save(upd(0, open, "FX", "USD/KRW", 1_000))
+		save(upd(0, open, "BITGET_SPOT", "BTC", 100_000))
+		save(upd(0, open, "UPBIT", "BTC", 105_000_000))
+		save(upd(0, afternoon, "FX", "USD/KRW", 1_000))
+		save(upd(0, afternoon, "BITGET_SPOT", "BTC", 100_000))
+		save(upd(0, afternoon, "UPBIT", "BTC", 100_000_000))
+	}
+
+	b := NewHeatmapBuilder(domain.ZoneKST)
+	from := quant.TimeStamp(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC).UnixMicro())
+	to := quant.TimeStamp(time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC).UnixMicro())
+	if err := ScanPremiums(ctx, store, from, to, NewPremiumTracker(0, 0), b.Add); err != nil {
+		t.Fatalf("ScanPremiums failed: %v", err)
+	}
+
+	h := b.Result()
+	if len(h.Rows) != 1 || h.Rows[0].Symbol != "BTC" {
+		t.Fatalf("unexpected rows %+v", h.Rows)
+	}
+	open, afternoon := h.Rows[0].Hours[9], h.Rows[0].Hours[15]
+	if open.Samples != 2 || open.AvgMicros != 50_000 {
+		t.Errorf("09h cell: %+v", open)
+	}
+	if afternoon.Samples != 2 || afternoon.AvgMicros != 0 {
+		t.Errorf("15h cell: %+v", afternoon)
+	}
+	if h.Rows[0].Hours[3].Samples != 0 {
+		t.Error("hours without samples must stay empty")
+	}
+	if text := h.Text(); !strings.Contains(text, "5.00") || !strings.Contains(text, "BTC") {
+		t.Errorf("unexpected text rendering:\n%s", text)
+	}
+}
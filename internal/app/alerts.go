@@ -0,0 +1,80 @@
+package app
+
+import (
+	"crypto_go/internal/alert"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/expr"
+	"crypto_go/pkg/quant"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultAlertCooldown is how long a rule stays quiet after firing.
+const defaultAlertCooldown = 5 * time.Minute
+
+// BuildAlertRules compiles and validates the alerts section.
+func BuildAlertRules(cfg *infra.Config) ([]alert.Rule, error) {
+	rules := make([]alert.Rule, 0, len(cfg.Alerts.Rules))
+	seen := make(map[string]bool)
+	for i, rc := range cfg.Alerts.Rules {
+		name := rc.Name
+		if name == "" {
+			name = fmt.Sprintf("rule%d", i+1)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("alerts.rules[%d]: duplicate name %q", i, name)
+		}
+		seen[name] = true
+
+		when, err := expr.Compile(rc.When)
+		if err != nil {
+			return nil, fmt.Errorf("alerts.rules[%d] (%s): %w", i, name, err)
+		}
+		for _, v := range when.Vars() {
+			if err := alert.ValidateVar(v); err != nil {
+				return nil, fmt.Errorf("alerts.rules[%d] (%s): %w", i, name, err)
+			}
+		}
+		if rc.CooldownSec < 0 {
+			return nil, fmt.Errorf("alerts.rules[%d] (%s): cooldown_sec must not be negative", i, name)
+		}
+		r := alert.Rule{Name: name, When: when, Action: strings.ToLower(rc.Action), Venue: strings.ToUpper(rc.Venue), Flatten: rc.Flatten, Cooldown: defaultAlertCooldown}
+		if rc.CooldownSec > 0 {
+			r.Cooldown = time.Duration(rc.CooldownSec) * time.Second
+		}
+
+		switch r.Action {
+		case "":
+			r.Action = alert.ActionNotify
+		case alert.ActionNotify, alert.ActionPauseStrategy, alert.ActionReduceOnly:
+		case alert.ActionHedge:
+			if !cfg.Engine.OMS.Enabled {
+				return nil, fmt.Errorf("alerts.rules[%d] (%s): hedge needs engine.oms.enabled", i, name)
+			}
+			o := rc.Order
+			r.Hedge = event.ManualOrderEvent{
+				Action:      event.ManualPlace,
+				Exchange:    strings.ToUpper(o.Exchange),
+				Symbol:      strings.ToUpper(o.Symbol),
+				Side:        strings.ToUpper(o.Side),
+				OrderType:   strings.ToUpper(o.Type),
+				PriceMicros: quant.PriceMicros(o.Price),
+				QtySats:     quant.QtySats(o.Qty),
+			}
+			if r.Hedge.OrderType == "" {
+				r.Hedge.OrderType = domain.OrderTypeMarket
+			}
+			if err := engine.ValidateManualOrder(&r.Hedge); err != nil {
+				return nil, fmt.Errorf("alerts.rules[%d] (%s): %w", i, name, err)
+			}
+		default:
+			return nil, fmt.Errorf("alerts.rules[%d] (%s): unknown action %q (want notify, pause_strategy, reduce_only or hedge)", i, name, rc.Action)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
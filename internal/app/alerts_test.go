@@ -0,0 +1,57 @@
+package app
+
+import (
+	"crypto_go/internal/alert"
+	"crypto_go/internal/infra"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildAlertRules(t *testing.T) {
+	var cfg infra.Config
+	cfg.Alerts.Rules = []infra.AlertRuleConfig{
+		{When: "premium.BTC >= 5"},
+		{Name: "crash", When: "price.UPBIT.BTC < 80000000", Action: "reduce_only", Venue: "bitget", Flatten: true, CooldownSec: 60},
+	}
+	rules, err := BuildAlertRules(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].Name != "rule1" || rules[0].Action != alert.ActionNotify || rules[0].Cooldown != defaultAlertCooldown {
+		t.Errorf("unexpected defaults %+v", rules[0])
+	}
+	if r := rules[1]; r.Venue != "BITGET" || !r.Flatten || r.Cooldown != time.Minute {
+		t.Errorf("unexpected rule %+v", r)
+	}
+
+	hedge := infra.AlertRuleConfig{Name: "hedge", When: "premium.BTC > 5", Action: "hedge"}
+	hedge.Order.Symbol, hedge.Order.Side, hedge.Order.Qty = "btc", "sell", 100_000
+	for _, c := range []struct {
+		rule infra.AlertRuleConfig
+		oms  bool
+		want string
+	}{
+		{infra.AlertRuleConfig{When: "premium.BTC >"}, false, "invalid expression"},
+		{infra.AlertRuleConfig{When: "funding.BTC > 1"}, false, "unknown alert variable"},
+		{infra.AlertRuleConfig{When: "premium.BTC > 1", Action: "halt"}, false, "unknown action"},
+		{hedge, false, "engine.oms.enabled"},
+		{infra.AlertRuleConfig{When: "premium.BTC > 1", Action: "hedge"}, true, "invalid manual order"},
+	} {
+		cfg.Alerts.Rules = []infra.AlertRuleConfig{c.rule}
+		cfg.Engine.OMS.Enabled = c.oms
+		if _, err := BuildAlertRules(&cfg); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%+v: expected %q, got %v", c.rule, c.want, err)
+		}
+	}
+
+	cfg.Alerts.Rules = []infra.AlertRuleConfig{hedge}
+	cfg.Engine.OMS.Enabled = true
+	rules, err = BuildAlertRules(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := rules[0].Hedge; h.Symbol != "BTC" || h.Side != "SELL" || h.OrderType != "MARKET" || h.QtySats != 100_000 {
+		t.Errorf("unexpected hedge %+v", h)
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"crypto_go/backtest"
+	"crypto_go/internal/storage"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BenchmarkPath is the local HTTP path for the live buy-and-hold baseline.
+const BenchmarkPath = "/benchmark"
+
+// NewBenchmarkHandler reports what holding each market over a period would have returned,
+// computed from the same WAL a backtest replays:
+//
+//	GET /benchmark?from=2026-03-01&to=2026-03-31&session=kst&markets=UPBIT:KRW-BTC,KRW-ETH
+//
+// to defaults to from; markets defaults to every market seen.
+func NewBenchmarkHandler(store *storage.EventStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		fromDay, toDay := q.Get("from"), q.Get("to")
+		if toDay == "" {
+			toDay = fromDay
+		}
+		name := strings.ToLower(q.Get("session"))
+		if name == "" {
+			name = "kst"
+		}
+		session, ok := journalSessions[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown session %q", name), http.StatusBadRequest)
+			return
+		}
+		from, err := session.ParseDay(fromDay)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid from day: %v", err), http.StatusBadRequest)
+			return
+		}
+		last, err := session.ParseDay(toDay)
+		if err != nil || last < from {
+			http.Error(w, fmt.Sprintf("invalid to day %q", toDay), http.StatusBadRequest)
+			return
+		}
+
+		var markets []string
+		if m := q.Get("markets"); m != "" {
+			markets = strings.Split(m, ",")
+		}
+		res, err := backtest.HODLFromStore(r.Context(), store, from, session.NextBoundary(last), markets...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	})
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"crypto_go/backtest"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBenchmarkHandler(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/bench.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	day := time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC)
+	for i, price := range []quant.PriceMicros{200, 210, 250} {
+		ev := &event.MarketUpdateEvent{
+			BaseEvent:   event.BaseEvent{Seq: uint64(i + 1), Ts: quant.TimeStamp(day.Add(time.Duration(i) * time.Hour).UnixMicro())},
+			Exchange:    "UPBIT",
+			Symbol:      "KRW-BTC",
+			PriceMicros: price,
+		}
+		if err := store.SaveEvent(context.Background(), ev); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	h := NewBenchmarkHandler(store)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, BenchmarkPath+"?from=2026-03-10", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var res backtest.BenchmarkResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("bad response: %v", err)
+	}
+	if res.PortfolioReturnBps != 2500 || len(res.Markets) != 1 {
+		t.Errorf("want +25%% for one market, got %+v", res)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, BenchmarkPath+"?from=2026-03-10&to=2026-03-01", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for reversed range, got %d", rec.Code)
+	}
+}
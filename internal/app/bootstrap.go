@@ -4,15 +4,21 @@ import (
 	"context"
 	"log/slog"
 
+	"crypto_go/internal/analytics"
 	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
 	"crypto_go/internal/event"
 	"crypto_go/internal/infra"
 	"crypto_go/internal/storage"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,6 +27,19 @@ type Bootstrap struct {
 	Config     *infra.Config
 	EventStore *storage.EventStore
 	Downloader *infra.IconDownloader
+	DataDir    string // _workspace/data/{mode}
+	LogDir     string // _workspace/logs/{mode}
+
+	// InstanceID identifies this process in logs and in the trading lock.
+	InstanceID string
+	// ReadOnly is set when another instance already trades with the same API keys.
+	// The engine must then run in monitoring-only mode and never place orders.
+	ReadOnly    bool
+	TradingLock *infra.TradingLock
+	// GapPolicy is the validated engine.gap_policy section.
+	GapPolicy engine.GapPolicy
+	// PremiumFormula is the validated premium section.
+	PremiumFormula analytics.PremiumFormula
 }
 
 // NewBootstrap creates a new Bootstrap instance
@@ -28,74 +47,290 @@ func NewBootstrap() *Bootstrap {
 	return &Bootstrap{}
 }
 
-// Initialize performs core system initialization (DB, Dir, etc.)
-func (b *Bootstrap) Initialize() error {
+// Initialize performs core system initialization (DB, Dir, etc.) as a startup
+// graph: config → logger → workspace → storage, with the trading lock, the
+// network setup, the icon downloader and the event pool warmup running
+// alongside. Each step's timing is logged; a failure names the step.
+func (b *Bootstrap) Initialize(ctx context.Context) error {
 	slog.Info("🚀 Bootstrapping Crypto Go...")
+	start := time.Now()
 
-	// 0. Runtime Warmup (GC Optimization)
-	event.Warmup()
-	slog.Info("🔥 Event Pool Warmed up")
+	var mode, workDir string
+	err := RunSteps(ctx, []Step{
+		{
+			// Runtime Warmup (GC Optimization)
+			Name:  "warmup",
+			After: []string{"config"},
+			Run: func(context.Context) error {
+				if b.Config.Engine.Memory.SkipWarmup {
+					return nil
+				}
+				event.Warmup()
+				slog.Info("🔥 Event Pool Warmed up")
+				return nil
+			},
+		},
+		{
+			// Go runtime memory limits (low-memory deployments)
+			Name:  "runtime",
+			After: []string{"logger"},
+			Run: func(context.Context) error {
+				applyMemoryConfig(b.Config)
+				return nil
+			},
+		},
+		{
+			// Load Config (Dynamic Path Resolution)
+			Name: "config",
+			Run: func(context.Context) error {
+				cfg, err := infra.LoadConfig(infra.ResolveConfigPath())
+				if err != nil {
+					return err
+				}
+				// Gap policy is validated up front so a typo fails before any side effects
+				gapPolicy, err := BuildGapPolicy(cfg)
+				if err != nil {
+					return fmt.Errorf("invalid gap policy: %w", err)
+				}
+				premiumFormula, err := BuildPremiumFormula(cfg)
+				if err != nil {
+					return fmt.Errorf("invalid premium formula: %w", err)
+				}
+				b.Config, b.GapPolicy, b.PremiumFormula = cfg, gapPolicy, premiumFormula
+				return nil
+			},
+		},
+		{
+			Name:  "logger",
+			After: []string{"config"},
+			Run: func(context.Context) error {
+				slog.SetDefault(infra.NewLogger(b.Config))
+				return nil
+			},
+		},
+		{
+			// Shared network dialer and REST timeout for every gateway (WS + REST)
+			Name:  "network",
+			After: []string{"config"},
+			Run: func(context.Context) error {
+				netCfg := b.Config.API.Network
+				infra.SetSharedDialer(infra.NewDialer(infra.DialerConfig{
+					PreferIPv4: netCfg.PreferIPv4,
+					Resolver:   netCfg.DNSServer,
+					DNSTTL:     time.Duration(netCfg.DNSTTLSec) * time.Second,
+					Timeout:    time.Duration(netCfg.DialTimeoutSec) * time.Second,
+				}, infra.GlobalMetrics))
+				infra.SetHTTPTimeout(time.Duration(netCfg.HTTPTimeoutSec) * time.Second)
+				return nil
+			},
+		},
+		{
+			// STES: Data Isolation - _workspace/data/{mode}, _workspace/logs/{mode}
+			Name:  "workspace",
+			After: []string{"logger"},
+			Run: func(context.Context) error {
+				mode = strings.ToLower(b.Config.Trading.Mode)
+				if mode == "" {
+					mode = "paper" // Default to paper if not set
+				}
+				workDir = infra.GetWorkspaceDir()
+				b.DataDir = filepath.Join(workDir, "data", mode)
+				b.LogDir = filepath.Join(workDir, "logs", mode)
 
-	// 1. Load Config (Dynamic Path Resolution)
-	cfg, err := infra.LoadConfig(infra.ResolveConfigPath())
+				// Ensure directories exist (0755)
+				if err := infra.EnsureDir(b.DataDir); err != nil {
+					return fmt.Errorf("failed to create data dir: %w", err)
+				}
+				if err := infra.EnsureDir(b.LogDir); err != nil {
+					return fmt.Errorf("failed to create log dir: %w", err)
+				}
+
+				// Singleton Instance Lock (OS Security)
+				// Prevent DB corruption on Desktop environments by blocking multi-process access to same data.
+				unlock, err := infra.CreateLockFile(workDir)
+				if err != nil {
+					return err
+				}
+				// Note: In a real app, you might want to store 'unlock' in the Bootstrap struct to call on Exit.
+				// For now, we rely on os.Exit cleaning up or manual cleanup if crash occurs.
+				_ = unlock
+				return nil
+			},
+		},
+		{
+			// Trading Lock (Double-Trading Prevention)
+			// Keyed by API credentials and shared host-wide, so copies in other workspaces
+			// with the same keys cannot both trade. The loser degrades to monitoring only.
+			Name:  "trading_lock",
+			After: []string{"workspace"},
+			Run: func(context.Context) error {
+				b.InstanceID = infra.NewInstanceID()
+				b.acquireTradingLock()
+				return nil
+			},
+		},
+		{
+			// EventStore (Single-Writer WAL DB)
+			Name:  "storage",
+			After: []string{"workspace"},
+			Run: func(context.Context) error {
+				dbPath := filepath.Join(b.DataDir, "events.db")
+				evStore, err := storage.NewEventStore(dbPath)
+				if err != nil {
+					return err
+				}
+				b.EventStore = evStore
+				slog.Info("✅ EventStore initialized (WAL-mode)", "path", dbPath, "mode", mode)
+				return nil
+			},
+		},
+		{
+			Name:  "icons",
+			After: []string{"network"},
+			Run: func(context.Context) error {
+				downloader, err := infra.NewIconDownloader()
+				if err != nil {
+					return err
+				}
+				b.Downloader = downloader
+				slog.Info("✅ Icon downloader ready")
+				return nil
+			},
+		},
+	})
 	if err != nil {
-		return err // Let main handle the error
+		return err
 	}
-	b.Config = cfg
-
-	// 2. Setup Logger
-	logger := infra.NewLogger(cfg)
-	slog.SetDefault(logger)
+	slog.Info("✅ Bootstrap completed", "took", time.Since(start))
+	return nil
+}
 
-	// 3. Initialize EventStore (Single-Writer WAL DB)
-	// STES: Data Isolation - _workspace/data/{mode}/events.db
-	mode := strings.ToLower(cfg.Trading.Mode)
-	if mode == "" {
-		mode = "paper" // Default to paper if not set
+// acquireTradingLock takes the credential-scoped trading lock or switches to read-only mode.
+func (b *Bootstrap) acquireTradingLock() {
+	key := b.tradingLockKey()
+	if key == "" {
+		slog.Info("🔓 No API keys configured, trading lock skipped", "instance", b.InstanceID)
+		return
 	}
 
-	workDir := infra.GetWorkspaceDir()
-	dataDir := filepath.Join(workDir, "data", mode)
-	logDir := filepath.Join(workDir, "logs", mode)
-
-	// Ensure directories exist (0755)
-	if err := infra.EnsureDir(dataDir); err != nil {
-		return fmt.Errorf("failed to create data dir: %w", err)
+	lock, err := infra.AcquireTradingLock(infra.TradingLockDir(), key, b.InstanceID)
+	if errors.Is(err, infra.ErrTradingLockHeld) {
+		b.ReadOnly = true
+		slog.Warn("🚨🚨🚨 ANOTHER INSTANCE IS TRADING WITH THE SAME API KEYS 🚨🚨🚨")
+		slog.Warn("🚨 Starting in READ-ONLY monitoring mode. NO ORDERS WILL BE PLACED.",
+			"instance", b.InstanceID, "reason", err)
+		return
 	}
-	if err := infra.EnsureDir(logDir); err != nil {
-		return fmt.Errorf("failed to create log dir: %w", err)
+	if err != nil {
+		// Fail closed: exclusivity cannot be verified, so do not trade.
+		b.ReadOnly = true
+		slog.Error("❌ Trading lock unavailable (I/O error), starting in READ-ONLY mode",
+			"instance", b.InstanceID, "dir", infra.TradingLockDir(), "error", err)
+		return
 	}
+	b.TradingLock = lock
+	slog.Info("🔒 Trading lock acquired", "instance", b.InstanceID, "path", lock.Path)
+}
 
-	// 3.1 Singleton Instance Lock (OS Security)
-	// Prevent DB corruption on Desktop environments by blocking multi-process access to same data.
-	unlock, err := infra.CreateLockFile(workDir)
-	if err != nil {
-		return err
+// TakeTradingLock takes the trading lock released by a leader that handed
+// over, and leaves read-only mode. Unlike at startup a failure is returned:
+// the caller must not trade without the lock.
+func (b *Bootstrap) TakeTradingLock() error {
+	if key := b.tradingLockKey(); key != "" {
+		lock, err := infra.AcquireTradingLock(infra.TradingLockDir(), key, b.InstanceID)
+		if err != nil {
+			return err
+		}
+		b.TradingLock.Release()
+		b.TradingLock = lock
+		slog.Info("🔒 Trading lock taken over", "instance", b.InstanceID, "path", lock.Path)
 	}
-	// Note: In a real app, you might want to store 'unlock' in the Bootstrap struct to call on Exit.
-	// For now, we rely on os.Exit cleaning up or manual cleanup if crash occurs.
-	_ = unlock
+	b.ReadOnly = false
+	return nil
+}
 
-	dbPath := filepath.Join(dataDir, "events.db")
-	evStore, err := storage.NewEventStore(dbPath)
-	if err != nil {
-		return err
+func (b *Bootstrap) tradingLockKey() string {
+	return infra.TradingLockKey(
+		b.Config.API.Upbit.AccessKey,
+		b.Config.API.Bitget.AccessKey,
+	)
+}
+
+// Icon sync defaults (ui.icons)
+const (
+	defaultIconRefresh      = 7 * 24 * time.Hour
+	defaultIconMaxDownloads = 20
+	defaultIconBudget       = 30 * time.Second
+	defaultIconConcurrency  = 5
+)
+
+// applyMemoryConfig sets the Go runtime memory limit and GC target from
+// engine.memory. Unset values keep the runtime defaults (and GOMEMLIMIT/GOGC
+// from the environment).
+func applyMemoryConfig(cfg *infra.Config) {
+	m := cfg.Engine.Memory
+	if m.LimitMB > 0 {
+		debug.SetMemoryLimit(m.LimitMB << 20)
+	}
+	if m.GCPercent > 0 {
+		debug.SetGCPercent(m.GCPercent)
+	}
+	if m.LimitMB > 0 || m.GCPercent > 0 {
+		slog.Info("Runtime memory limits applied",
+			slog.Int64("limit_mb", m.LimitMB),
+			slog.Int("gc_percent", m.GCPercent),
+		)
 	}
-	b.EventStore = evStore
-	slog.Info("✅ EventStore initialized (WAL-mode)", "path", dbPath, "mode", mode)
+}
 
-	// 4. Initialize Icon Downloader
-	downloader, err := infra.NewIconDownloader()
-	if err != nil {
-		return err
+// iconSyncPolicy is the resolved ui.icons section.
+type iconSyncPolicy struct {
+	refresh      time.Duration // Icons checked more recently are reused without a request
+	maxDownloads int64         // Icon requests per run
+	budget       time.Duration // Total time for icon requests per run
+	concurrency  int           // Icon requests in flight
+}
+
+func newIconSyncPolicy(cfg *infra.Config) iconSyncPolicy {
+	ic := cfg.UI.Icons
+	p := iconSyncPolicy{
+		refresh:      time.Duration(ic.RefreshHours) * time.Hour,
+		maxDownloads: int64(ic.MaxDownloads),
+		budget:       time.Duration(ic.BudgetSec) * time.Second,
+		concurrency:  ic.Concurrency,
+	}
+	if p.refresh == 0 {
+		p.refresh = defaultIconRefresh
 	}
-	b.Downloader = downloader
-	slog.Info("✅ Icon downloader ready")
+	if p.maxDownloads == 0 {
+		p.maxDownloads = defaultIconMaxDownloads
+	}
+	if p.budget == 0 {
+		p.budget = defaultIconBudget
+	}
+	if p.concurrency == 0 {
+		p.concurrency = defaultIconConcurrency
+	}
+	return p
+}
 
-	return nil
+// fresh reports whether coin's icon was checked within the refresh period and
+// is still on disk, so it needs no request.
+func (p iconSyncPolicy) fresh(coin *domain.CoinInfo, nowUnixM int64) bool {
+	if coin.IconPath == "" || coin.LastSyncedUnixM == 0 {
+		return false
+	}
+	if _, err := os.Stat(coin.IconPath); err != nil {
+		return false
+	}
+	return time.Duration(nowUnixM-coin.LastSyncedUnixM)*time.Microsecond < p.refresh
 }
 
-// SyncAssets synchronizes symbols and icons in the background
+// SyncAssets synchronizes symbols and icons in the background. Icons checked
+// within ui.icons.refresh_hours are reused as is; older ones are revalidated
+// with their ETag/Last-Modified. Requests are capped per run by count and by
+// total time, and whatever does not fit waits for the next start, so a flaky
+// network cannot hold up the sync.
 func (b *Bootstrap) SyncAssets(ctx context.Context) {
 	slog.Info("🔄 Starting asset synchronization...")
 
@@ -107,8 +342,13 @@ func (b *Bootstrap) SyncAssets(ctx context.Context) {
 		uniqueSymbols[s] = true
 	}
 
+	policy := newIconSyncPolicy(b.Config)
+	fetchCtx, cancel := context.WithTimeout(ctx, policy.budget)
+	defer cancel()
+	var requests, fresh, downloaded, notModified, deferred, failed atomic.Int64
+
 	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, 5)
+	semaphore := make(chan struct{}, policy.concurrency)
 
 	for symbol := range uniqueSymbols {
 		wg.Add(1)
@@ -130,28 +370,67 @@ func (b *Bootstrap) SyncAssets(ctx context.Context) {
 			}
 
 			// Try to load existing
-			key := "coin:" + sym
+			key := storage.CoinMetadataKey(sym)
 			if val, _ := b.EventStore.GetMetadata(ctx, key); val != "" {
 				var existing domain.CoinInfo
 				if err := json.Unmarshal([]byte(val), &existing); err == nil {
 					coin.IsFavorite = existing.IsFavorite
 					coin.IconPath = existing.IconPath
+					coin.IconETag = existing.IconETag
+					coin.IconModified = existing.IconModified
 					coin.LastSyncedUnixM = existing.LastSyncedUnixM
 				}
 			}
 
-			// Download Icon if needed
-			if path, err := b.Downloader.DownloadIcon(sym); err == nil && path != "" {
+			// Refresh the icon if it is due and the run's budget allows
+			switch {
+			case policy.fresh(coin, nowUnixM):
+				fresh.Add(1)
+			case fetchCtx.Err() != nil || requests.Add(1) > policy.maxDownloads:
+				deferred.Add(1)
+			default:
+				path, validators, changed, err := b.Downloader.FetchIcon(fetchCtx, sym, infra.IconValidators{
+					ETag:         coin.IconETag,
+					LastModified: coin.IconModified,
+				})
+				if err != nil {
+					failed.Add(1)
+					slog.Debug("Icon sync failed", "symbol", sym, "error", err)
+					break
+				}
+				if changed {
+					downloaded.Add(1)
+				} else {
+					notModified.Add(1)
+				}
 				coin.IconPath = path
+				coin.IconETag = validators.ETag
+				coin.IconModified = validators.LastModified
 				coin.LastSyncedUnixM = nowUnixM
 			}
 
-			// Save back to metadata
-			data, _ := json.Marshal(coin)
-			b.EventStore.UpsertMetadata(ctx, key, string(data), nowUnixM)
+			// Save back to metadata, on top of the stored entry: a favorite
+			// toggled while the icon was fetched is kept
+			err := b.EventStore.UpdateMetadata(ctx, key, nowUnixM, func(current string) (string, error) {
+				var stored domain.CoinInfo
+				if current != "" && json.Unmarshal([]byte(current), &stored) == nil {
+					coin.IsFavorite = stored.IsFavorite
+					coin.CreatedAtUnixM = stored.CreatedAtUnixM
+				}
+				data, err := json.Marshal(coin)
+				return string(data), err
+			})
+			if err != nil {
+				slog.Warn("Failed to save coin info", "symbol", sym, "error", err)
+			}
 		}(symbol)
 	}
 
 	wg.Wait()
-	slog.Info("✨ Asset synchronization completed")
+	slog.Info("✨ Asset synchronization completed",
+		"fresh", fresh.Load(),
+		"downloaded", downloaded.Load(),
+		"not_modified", notModified.Load(),
+		"deferred", deferred.Load(),
+		"failed", failed.Load())
 }
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+)
+
+func TestIconSyncPolicy(t *testing.T) {
+	cfg := parseGapConfig(t, "ui:\n  icons:\n    refresh_hours: 24\n")
+	policy := newIconSyncPolicy(cfg)
+	if policy.refresh != 24*time.Hour || policy.maxDownloads != defaultIconMaxDownloads || policy.budget != defaultIconBudget || policy.concurrency != defaultIconConcurrency {
+		t.Fatalf("unexpected policy %+v", policy)
+	}
+
+	icon := filepath.Join(t.TempDir(), "btc.png")
+	os.WriteFile(icon, []byte("png"), 0o600)
+	now := time.Now().UnixMicro()
+	hour := time.Hour.Microseconds()
+
+	cases := []struct {
+		name string
+		coin domain.CoinInfo
+		want bool
+	}{
+		{"recent", domain.CoinInfo{IconPath: icon, LastSyncedUnixM: now - hour}, true},
+		{"expired", domain.CoinInfo{IconPath: icon, LastSyncedUnixM: now - 25*hour}, false},
+		{"never synced", domain.CoinInfo{IconPath: icon}, false},
+		{"file gone", domain.CoinInfo{IconPath: icon + ".gone", LastSyncedUnixM: now}, false},
+	}
+	for _, c := range cases {
+		if got := policy.fresh(&c.coin, now); got != c.want {
+			t.Errorf("%s: fresh = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
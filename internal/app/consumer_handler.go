@@ -0,0 +1,150 @@
+package app
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Consumer API paths: external processes page through the event log from a
+// cursor the store remembers for them.
+const (
+	EventsPath    = "/events"
+	EventsAckPath = "/events/ack"
+)
+
+const (
+	defaultEventsLimit = 500
+	maxEventsLimit     = 5000
+)
+
+var consumerNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ConsumerEvent is one event of an EventsPage.
+type ConsumerEvent struct {
+	Seq   uint64      `json:"seq"`
+	Type  string      `json:"type"`
+	Event event.Event `json:"event"`
+}
+
+// EventsPage is the reply of GET /events.
+type EventsPage struct {
+	Consumer string          `json:"consumer"`
+	Cursor   uint64          `json:"cursor"`   // Last acknowledged seq
+	Events   []ConsumerEvent `json:"events"`   // After Cursor, in seq order
+	NextSeq  uint64          `json:"next_seq"` // Ack this once Events are processed (= Cursor if none were read)
+	LastSeq  uint64          `json:"last_seq"` // Newest seq in the log
+}
+
+// AckRequest is the JSON body of POST /events/ack.
+type AckRequest struct {
+	Consumer string `json:"consumer"`
+	Seq      uint64 `json:"seq"`
+	Reset    bool   `json:"reset,omitempty"` // Move the cursor back (reprocess from seq+1)
+}
+
+// NewEventsHandler serves the event log to external consumers:
+//
+//	GET  /events?consumer=etl[&limit=500][&types=order_update,control]
+//	POST /events/ack  {"consumer":"etl","seq":1234}
+//
+// A read returns the events after the consumer's cursor without moving it;
+// the consumer acks next_seq once it has processed them. A crash before the
+// ack redelivers the page (at-least-once: dedupe by seq). With types, other
+// events are skipped but still counted in next_seq. Cursors live in the
+// events.db metadata, so they survive restarts.
+func NewEventsHandler(store *storage.EventStore) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(EventsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		name := q.Get("consumer")
+		if !consumerNameRe.MatchString(name) {
+			http.Error(w, "consumer must be 1-64 letters, digits, '_' or '-'", http.StatusBadRequest)
+			return
+		}
+		limit := defaultEventsLimit
+		if v := q.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = min(n, maxEventsLimit)
+		}
+		var types []string
+		if v := q.Get("types"); v != "" {
+			types = strings.Split(strings.ToLower(v), ",")
+		}
+
+		ctx := r.Context()
+		cursor, err := store.ConsumerCursor(ctx, name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		last, err := store.GetLastSeq(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		events, err := store.LoadEventsBatch(ctx, cursor+1, limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page := EventsPage{Consumer: name, Cursor: cursor, Events: []ConsumerEvent{}, NextSeq: cursor, LastSeq: last}
+		if len(events) > 0 {
+			page.NextSeq = events[len(events)-1].GetSeq()
+		}
+		for _, ev := range events {
+			typ := ev.GetType().String()
+			if types == nil || slices.Contains(types, typ) {
+				page.Events = append(page.Events, ConsumerEvent{Seq: ev.GetSeq(), Type: typ, Event: ev})
+			}
+		}
+		writeJSON(w, http.StatusOK, page)
+	})
+	mux.HandleFunc(EventsAckPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req AckRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if !consumerNameRe.MatchString(req.Consumer) {
+			http.Error(w, "consumer must be 1-64 letters, digits, '_' or '-'", http.StatusBadRequest)
+			return
+		}
+		ctx := r.Context()
+		last, err := store.GetLastSeq(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if req.Seq > last {
+			http.Error(w, fmt.Sprintf("seq %d is past the last event %d", req.Seq, last), http.StatusBadRequest)
+			return
+		}
+		cursor, err := store.AckConsumer(ctx, req.Consumer, req.Seq, req.Reset, time.Now().UnixMicro())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]uint64{"cursor": cursor})
+	})
+	return mux
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEventsHandler(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewEventStore(t.TempDir() + "/events.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	for seq := uint64(1); seq <= 5; seq++ {
+		var ev event.Event = &event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: 1}, Exchange: "UPBIT", Symbol: "BTC"}
+		if seq == 4 {
+			ev = &event.ControlEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: 1}, Command: event.CmdPauseStrategy}
+		}
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := NewEventsHandler(store)
+	// Events decode into this shape: event.Event is an interface
+	type page struct {
+		Cursor  uint64
+		NextSeq uint64 `json:"next_seq"`
+		LastSeq uint64 `json:"last_seq"`
+		Events  []struct {
+			Seq  uint64
+			Type string
+		}
+	}
+	read := func(query string) page {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EventsPath+"?"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", query, rec.Code, rec.Body)
+		}
+		var p page
+		if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	ack := func(body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, EventsAckPath, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	// Reads do not move the cursor: an unacked page is redelivered
+	if p := read("consumer=etl&limit=2"); p.Cursor != 0 || p.NextSeq != 2 || p.LastSeq != 5 || len(p.Events) != 2 {
+		t.Fatalf("unexpected first page %+v", p)
+	}
+	if p := read("consumer=etl&limit=2"); p.NextSeq != 2 {
+		t.Fatalf("unacked page must be redelivered, next %d", p.NextSeq)
+	}
+	if code := ack(`{"consumer":"etl","seq":2}`); code != http.StatusOK {
+		t.Fatalf("ack: %d", code)
+	}
+	if p := read("consumer=etl&types=control"); p.Cursor != 2 || p.NextSeq != 5 || len(p.Events) != 1 || p.Events[0].Seq != 4 || p.Events[0].Type != "control" {
+		t.Errorf("unexpected filtered page %+v", p)
+	}
+	if c, _ := store.ConsumerCursor(ctx, "etl"); c != 2 {
+		t.Errorf("cursor = %d, want 2", c)
+	}
+
+	for _, bad := range []string{`{"consumer":"etl","seq":9}`, `{"consumer":"a b","seq":1}`, `{`} {
+		if code := ack(bad); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", bad, code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EventsPath, nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without consumer, got %d", rec.Code)
+	}
+}
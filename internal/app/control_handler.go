@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ControlPath is the local HTTP path accepting control commands.
+const ControlPath = "/control"
+
+// KillCommand is the control command that triggers the kill switch (a
+// HaltEvent, not a ControlEvent).
+const KillCommand = "KILL"
+
+// controlSendTimeout bounds how long a request waits for a full sequencer inbox.
+const controlSendTimeout = 5 * time.Second
+
+// paramChangeTimeout bounds a SET_STRATEGY_PARAM request, dry-run backfill included.
+const paramChangeTimeout = 2 * time.Minute
+
+// ControlRequest is the JSON body of a control command.
+type ControlRequest struct {
+	Command string `json:"command"` // UPPER_SNAKE name, e.g. "HALT", or KillCommand
+	Target  string `json:"target,omitempty"`
+	Value   int64  `json:"value,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// NewControlHandler exposes client over HTTP. Mount it on a localhost-only listener:
+// there is no authentication, any caller can pause or halt trading. KillCommand
+// triggers the kill switch with the request's reason as detail. A
+// SET_STRATEGY_PARAM the strategy refuses is a 400.
+func NewControlHandler(client *engine.ControlClient) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req ControlRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		kill := req.Command == KillCommand
+		var cmd event.ControlCommand
+		if !kill {
+			var err error
+			if cmd, err = event.ParseControlCommand(req.Command); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		timeout := controlSendTimeout
+		if cmd == event.CmdSetStrategyParam {
+			timeout = paramChangeTimeout
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		var err error
+		if kill {
+			err = client.Kill(ctx, event.HaltManual, req.Reason)
+		} else {
+			err = client.Send(ctx, cmd, req.Target, req.Value, req.Reason)
+		}
+		if errors.Is(err, strategy.ErrInvalidParam) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("control command not delivered: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
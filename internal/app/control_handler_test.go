@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestControlHandler(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	client := engine.NewControlClient(inbox)
+	client.SetParamReview(func(_ context.Context, key string, value int64) (string, error) {
+		return "", strategy.NewSMACrossStrategy("BTC", 2, 4).SetParam(key, value)
+	})
+	h := NewControlHandler(client)
+
+	post := func(body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ControlPath, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	if code := post(`{"command":"SET_RISK_LIMIT","target":"max_order_qty_sats","value":100}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	ev := (<-inbox).(*event.ControlEvent)
+	if ev.Command != event.CmdSetRiskLimit || ev.Target != "max_order_qty_sats" || ev.Value != 100 || ev.Seq != 1 {
+		t.Errorf("unexpected control event: %+v", ev)
+	}
+
+	// KILL is a HaltEvent on the same CONTROL sequence
+	if code := post(`{"command":"KILL","reason":"runaway fills"}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202 for KILL, got %d", code)
+	}
+	halt := (<-inbox).(*event.HaltEvent)
+	if halt.Reason != event.HaltManual || halt.Detail != "runaway fills" || halt.Seq != 2 {
+		t.Errorf("unexpected halt event: %+v", halt)
+	}
+
+	if code := post(`{"command":"SELF_DESTRUCT"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown command, got %d", code)
+	}
+	if code := post(`{"command":"SET_STRATEGY_PARAM","target":"short_period","value":9}`); code != http.StatusBadRequest || len(inbox) != 0 {
+		t.Errorf("expected 400 and nothing sent for a refused parameter, got %d", code)
+	}
+	if code := post(`not json`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for bad body, got %d", code)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ControlPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+}
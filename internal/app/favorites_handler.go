@@ -0,0 +1,48 @@
+package app
+
+import (
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FavoritesPath is the local HTTP path toggling a coin's favorite flag.
+const FavoritesPath = "/favorites"
+
+// FavoriteRequest is the JSON body of a favorite toggle.
+type FavoriteRequest struct {
+	Symbol   string `json:"symbol"`
+	Favorite bool   `json:"favorite"`
+}
+
+// NewFavoritesHandler sets a coin's favorite flag:
+//
+//	POST /favorites  {"symbol":"BTC","favorite":true}
+//
+// The change goes through the store's user write queue, so it is never lost
+// to a concurrent asset sync rewriting the same coin.
+func NewFavoritesHandler(store *storage.EventStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req FavoriteRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		symbol := strings.ToUpper(strings.TrimSpace(req.Symbol))
+		if symbol == "" {
+			http.Error(w, "symbol is required", http.StatusBadRequest)
+			return
+		}
+		if err := store.SetFavorite(r.Context(), symbol, req.Favorite); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFavoritesHandler(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/favorites.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	store.UpsertMetadata(context.Background(), storage.CoinMetadataKey("BTC"), `{"symbol":"BTC","icon_path":"icons/btc.png"}`, 0)
+
+	h := NewFavoritesHandler(store)
+	post := func(body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, FavoritesPath, strings.NewReader(body)))
+		return rec.Code
+	}
+	if code := post(`{"symbol":"btc","favorite":true}`); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	raw, _ := store.GetMetadata(context.Background(), storage.CoinMetadataKey("BTC"))
+	var coin domain.CoinInfo
+	if err := json.Unmarshal([]byte(raw), &coin); err != nil || !coin.IsFavorite || coin.IconPath != "icons/btc.png" {
+		t.Errorf("favorite must be set on the existing coin: %s", raw)
+	}
+	if code := post(`{"favorite":true}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 without symbol, got %d", code)
+	}
+}
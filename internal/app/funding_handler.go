@@ -0,0 +1,41 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"net/http"
+)
+
+// FundingPath is the local HTTP path for the funding calendar of perpetuals.
+const FundingPath = "/funding"
+
+// FundingSource provides the known funding epochs.
+type FundingSource interface {
+	GetFundingCalendar() []domain.FundingEpoch
+}
+
+// NewFundingHandler serves the next funding epoch of every perpetual the
+// workers stream, soonest first:
+//
+//	GET /funding?symbol=BTC
+//
+// symbol (optional) narrows the result to one symbol across venues.
+func NewFundingHandler(src FundingSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		epochs := src.GetFundingCalendar()
+		if symbol := r.URL.Query().Get("symbol"); symbol != "" {
+			kept := epochs[:0]
+			for _, f := range epochs {
+				if f.Symbol == symbol {
+					kept = append(kept, f)
+				}
+			}
+			epochs = kept
+		}
+		writeJSON(w, http.StatusOK, epochs)
+	})
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFundingHandler(t *testing.T) {
+	seq := engine.NewSequencer(10, nil, nil, nil)
+	for i, symbol := range []string{"BTC", "ETH"} {
+		seq.ProcessEventForTest(&event.FundingEvent{Exchange: "BITGET_FUTURES", Symbol: symbol, NextFundingTs: quant.TimeStamp(2_000_000 - 1_000_000*i), RateMicros: 100})
+	}
+	seq.ProcessEventForTest(&event.FundingEvent{Exchange: "BYBIT_LINEAR", Symbol: "BTC", NextFundingTs: 1_500_000, RateMicros: -50})
+	h := NewFundingHandler(seq)
+
+	get := func(query string) []domain.FundingEpoch {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FundingPath+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+		var epochs []domain.FundingEpoch
+		if err := json.Unmarshal(rec.Body.Bytes(), &epochs); err != nil {
+			t.Fatalf("invalid JSON %s: %v", rec.Body, err)
+		}
+		return epochs
+	}
+	if all := get(""); len(all) != 3 || all[0].Symbol != "ETH" || all[1].Exchange != "BYBIT_LINEAR" {
+		t.Errorf("expected the calendar soonest first, got %+v", all)
+	}
+	if btc := get("?symbol=BTC"); len(btc) != 2 || btc[0].RateMicros != -50 {
+		t.Errorf("unexpected BTC epochs %+v", btc)
+	}
+}
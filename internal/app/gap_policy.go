@@ -0,0 +1,96 @@
+package app
+
+import (
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"fmt"
+)
+
+// BuildGapPolicy converts and validates the engine.gap_policy config section.
+// It is the only place gap actions and per_type event names are checked.
+//
+// An absent section keeps the observe-only default. Once the section is present,
+// every field is taken literally: an explicit `tolerance: 0` means strict, and an
+// omitted action means halt. Per-type rules inherit omitted fields from the top level.
+// The per_mode entry of cfg.Trading.Mode, if any, is merged over the section first.
+func BuildGapPolicy(cfg *infra.Config) (engine.GapPolicy, error) {
+	if cfg.Engine.GapPolicy == nil {
+		return engine.DefaultGapPolicy(), nil
+	}
+	gc, err := gapConfigForMode(cfg.Engine.GapPolicy, cfg.Trading.Mode)
+	if err != nil {
+		return engine.GapPolicy{}, err
+	}
+
+	def := engine.GapRule{Tolerance: engine.DefaultGapPolicy().Default.Tolerance, Action: engine.GapHalt}
+	if gc.Tolerance != nil {
+		def.Tolerance = *gc.Tolerance
+	}
+	if gc.Action != "" {
+		action, err := engine.ParseGapAction(gc.Action)
+		if err != nil {
+			return engine.GapPolicy{}, fmt.Errorf("gap_policy.action: %w", err)
+		}
+		def.Action = action
+	}
+
+	policy := engine.GapPolicy{
+		Default: def,
+		ByType:  make(map[event.Type]engine.GapRule, len(gc.PerType)),
+	}
+	for name, rc := range gc.PerType {
+		evType, err := event.ParseType(name)
+		if err != nil {
+			return engine.GapPolicy{}, fmt.Errorf("gap_policy.per_type: %w", err)
+		}
+		rule := def
+		if rc.Tolerance != nil {
+			rule.Tolerance = *rc.Tolerance
+		}
+		if rc.Action != "" {
+			action, err := engine.ParseGapAction(rc.Action)
+			if err != nil {
+				return engine.GapPolicy{}, fmt.Errorf("gap_policy.per_type.%s: %w", name, err)
+			}
+			rule.Action = action
+		}
+		policy.ByType[evType] = rule
+	}
+	return policy, nil
+}
+
+// gapConfigForMode returns gc with its per_mode entry for mode merged in: set
+// fields replace the top level, and per_type rules replace those of the same type.
+func gapConfigForMode(gc *infra.GapPolicyConfig, mode string) (*infra.GapPolicyConfig, error) {
+	for name, mc := range gc.PerMode {
+		switch name {
+		case "PAPER", "DEMO", "REAL":
+		default:
+			return nil, fmt.Errorf("gap_policy.per_mode: unknown mode %q", name)
+		}
+		if len(mc.PerMode) > 0 {
+			return nil, fmt.Errorf("gap_policy.per_mode.%s: per_mode cannot be nested", name)
+		}
+	}
+	mc, ok := gc.PerMode[mode]
+	if !ok {
+		return gc, nil
+	}
+
+	merged := *gc
+	if mc.Tolerance != nil {
+		merged.Tolerance = mc.Tolerance
+	}
+	if mc.Action != "" {
+		merged.Action = mc.Action
+	}
+	merged.PerType = make(map[string]infra.GapRuleConfig, len(gc.PerType)+len(mc.PerType))
+	for name, rc := range gc.PerType {
+		merged.PerType[name] = rc
+	}
+	for name, rc := range mc.PerType {
+		merged.PerType[name] = rc
+	}
+	return &merged, nil
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseGapConfig(t *testing.T, doc string) *infra.Config {
+	t.Helper()
+	var cfg infra.Config
+	if err := yaml.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	return &cfg
+}
+
+func TestBuildGapPolicy_AbsentIsObserveOnly(t *testing.T) {
+	policy, err := BuildGapPolicy(parseGapConfig(t, "engine:\n  inbox_size: 10\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Default != engine.DefaultGapPolicy().Default {
+		t.Errorf("expected default policy, got %+v", policy.Default)
+	}
+}
+
+func TestBuildGapPolicy_ExplicitZeroToleranceIsStrict(t *testing.T) {
+	policy, err := BuildGapPolicy(parseGapConfig(t, "engine:\n  gap_policy:\n    tolerance: 0\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := engine.GapRule{Tolerance: 0, Action: engine.GapHalt}
+	if policy.Default != want {
+		t.Errorf("expected %+v, got %+v", want, policy.Default)
+	}
+}
+
+func TestBuildGapPolicy_PerTypeInherits(t *testing.T) {
+	doc := `
+engine:
+  gap_policy:
+    tolerance: 5
+    action: tolerate
+    per_type:
+      market_update: { action: resync }
+      order_update: { tolerance: 0 }
+`
+	policy, err := BuildGapPolicy(parseGapConfig(t, doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := policy.RuleFor(event.EvMarketUpdate); got != (engine.GapRule{Tolerance: 5, Action: engine.GapResync}) {
+		t.Errorf("market_update: got %+v", got)
+	}
+	if got := policy.RuleFor(event.EvOrderUpdate); got != (engine.GapRule{Tolerance: 0, Action: engine.GapTolerate}) {
+		t.Errorf("order_update: got %+v", got)
+	}
+}
+
+func TestBuildGapPolicy_PerMode(t *testing.T) {
+	doc := `
+trading:
+  mode: REAL
+engine:
+  gap_policy:
+    tolerance: 5
+    action: tolerate
+    per_type:
+      market_update: { action: resync }
+      order_update: { tolerance: 0 }
+    per_mode:
+      REAL:
+        action: resync
+        per_type:
+          order_update: { tolerance: 0, action: halt }
+      PAPER: { action: tolerate }
+`
+	cfg := parseGapConfig(t, doc)
+	policy, err := BuildGapPolicy(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Default != (engine.GapRule{Tolerance: 5, Action: engine.GapResync}) {
+		t.Errorf("default: got %+v", policy.Default)
+	}
+	if got := policy.RuleFor(event.EvOrderUpdate); got != (engine.GapRule{Tolerance: 0, Action: engine.GapHalt}) {
+		t.Errorf("order_update: got %+v", got)
+	}
+	if got := policy.RuleFor(event.EvMarketUpdate); got != (engine.GapRule{Tolerance: 5, Action: engine.GapResync}) {
+		t.Errorf("market_update: got %+v", got)
+	}
+
+	// Another mode keeps the top level; the config itself is not modified
+	cfg.Trading.Mode = "DEMO"
+	if policy, err = BuildGapPolicy(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := policy.RuleFor(event.EvOrderUpdate); got != (engine.GapRule{Tolerance: 0, Action: engine.GapTolerate}) {
+		t.Errorf("DEMO order_update: got %+v", got)
+	}
+}
+
+func TestBuildGapPolicy_RejectsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"action":      "engine:\n  gap_policy:\n    action: panic\n",
+		"type_action": "engine:\n  gap_policy:\n    per_type:\n      market_update: { action: stop }\n",
+		"type_name":   "engine:\n  gap_policy:\n    per_type:\n      market_updates: { tolerance: 1 }\n",
+		"mode_name":   "engine:\n  gap_policy:\n    per_mode:\n      LIVE: { action: halt }\n",
+		"mode_action": "trading:\n  mode: PAPER\nengine:\n  gap_policy:\n    per_mode:\n      PAPER: { action: stop }\n",
+	}
+	for name, doc := range cases {
+		if _, err := BuildGapPolicy(parseGapConfig(t, doc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"crypto_go/internal/engine"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HandoverPath is the local HTTP path of the blue/green handover protocol.
+const HandoverPath = "/handover"
+
+// Handover steps.
+const (
+	HandoverPrepare = "prepare"
+	HandoverCommit  = "commit"
+	HandoverAbort   = "abort"
+)
+
+// HandoverRequest is the JSON body of a handover step.
+type HandoverRequest struct {
+	Step     string `json:"step"`               // prepare | commit | abort
+	Instance string `json:"instance,omitempty"` // Successor's instance ID (recorded in HANDOVER)
+	Reason   string `json:"reason,omitempty"`   // Why the successor aborts
+}
+
+// NewHandoverHandler exposes the leader side of a handover. prepare answers
+// with the sealed engine.HandoverState; commit and abort with 204. Mount it on
+// a localhost-only listener: any caller can stop trading.
+func NewHandoverHandler(h *engine.Handover) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req HandoverRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		var err error
+		switch req.Step {
+		case HandoverPrepare:
+			var state engine.HandoverState
+			if state, err = h.Prepare(r.Context(), req.Instance); err == nil {
+				writeJSON(w, http.StatusOK, state)
+				return
+			}
+		case HandoverCommit:
+			err = h.Commit()
+		case HandoverAbort:
+			err = h.Abort(req.Reason)
+		default:
+			http.Error(w, fmt.Sprintf("unknown step %q", req.Step), http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusNoContent)
+		case errors.Is(err, engine.ErrNoHandover), errors.Is(err, engine.ErrHandoverCommitted):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+	})
+}
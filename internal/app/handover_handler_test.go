@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/engine"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandoverHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seq := engine.NewSequencer(8, nil, nil, nil)
+	go seq.Run(ctx)
+	h := NewHandoverHandler(engine.NewHandover(seq, engine.NewControlClient(seq.Inbox()), func() { go seq.Run(ctx) }, func() {},
+		engine.HandoverConfig{DrainTimeout: time.Second, CommitTimeout: time.Hour}))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, HandoverPath, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := post(`{"step":"commit"}`); rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for commit without prepare, got %d", rec.Code)
+	}
+	if rec := post(`{"step":"swap"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown step, got %d", rec.Code)
+	}
+
+	rec := post(`{"step":"prepare","instance":"green"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var state engine.HandoverState
+	if err := json.NewDecoder(rec.Body).Decode(&state); err != nil || state.Seq != 1 || state.Hash != seq.StateHash() {
+		t.Fatalf("unexpected sealed state %+v (%v)", state, err)
+	}
+
+	if rec := post(`{"step":"abort","reason":"test"}`); rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for abort, got %d", rec.Code)
+	}
+	if rec := post(`{"step":"abort"}`); rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for a second abort, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HandoverPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Local HTTP paths for journal notes and the journal export.
+const (
+	AnnotationsPath = "/annotations"
+	JournalPath     = "/journal"
+)
+
+// journalSessions are the day definitions a journal can be cut by (?session=).
+var journalSessions = map[string]domain.Session{
+	"kst":    domain.SessionKST,
+	"upbit":  domain.SessionUpbit,
+	"bitget": domain.SessionBitget,
+}
+
+// NewAnnotationsHandler manages trade/day notes:
+//
+//	GET    /annotations?kind=trade&target=<order id>  list (filters optional)
+//	POST   /annotations  {"kind":"day","target":"2026-03-10","note":"...","tags":["news"]}
+//	DELETE /annotations?id=<id>
+//
+// Mount it on the localhost-only admin listener.
+func NewAnnotationsHandler(store *storage.EventStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			q := r.URL.Query()
+			notes, err := store.ListAnnotations(r.Context(), strings.ToLower(q.Get("kind")), q.Get("target"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, http.StatusOK, notes)
+
+		case http.MethodPost:
+			var a domain.Annotation
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&a); err != nil {
+				http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			a.ID = 0
+			a.CreatedAt = time.Now().UnixMicro()
+			saved, err := store.SaveAnnotation(r.Context(), a)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, http.StatusCreated, saved)
+
+		case http.MethodDelete:
+			id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+			if err != nil {
+				http.Error(w, "invalid id", http.StatusBadRequest)
+				return
+			}
+			err = store.DeleteAnnotation(r.Context(), id)
+			if errors.Is(err, storage.ErrAnnotationNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// NewJournalHandler exports the trade journal with notes as JSON:
+//
+//	GET /journal?from=2026-03-01&to=2026-03-31&session=kst
+//
+// to defaults to from; session (kst | upbit | bitget) defaults to kst.
+func NewJournalHandler(store *storage.EventStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		from, to := q.Get("from"), q.Get("to")
+		if to == "" {
+			to = from
+		}
+		name := strings.ToLower(q.Get("session"))
+		if name == "" {
+			name = "kst"
+		}
+		session, ok := journalSessions[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown session %q", name), http.StatusBadRequest)
+			return
+		}
+
+		journal, err := store.BuildJournal(r.Context(), session, from, to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="journal_%s_%s.json"`, from, to))
+		writeJSON(w, http.StatusOK, journal)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Failed to write JSON response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnnotationsAndJournalHandlers(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/journal.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	fillTs := quant.TimeStamp(time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC).UnixMicro())
+	if err := store.SaveEvent(context.Background(), &event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 1, Ts: fillTs}, OrderID: "o1", Status: "FILLED"}); err != nil {
+		t.Fatalf("SaveEvent failed: %v", err)
+	}
+
+	notes := NewAnnotationsHandler(store)
+	do := func(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(notes, http.MethodPost, AnnotationsPath, `{"kind":"trade","target":"o1","note":"news-driven spike","tags":["news"]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var created domain.Annotation
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || created.ID == 0 || created.CreatedAt == 0 {
+		t.Fatalf("bad created annotation %s (%v)", rec.Body, err)
+	}
+	if rec := do(notes, http.MethodPost, AnnotationsPath, `{"kind":"day","target":"tomorrow"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid annotation, got %d", rec.Code)
+	}
+
+	rec = do(NewJournalHandler(store), http.MethodGet, JournalPath+"?from=2026-03-10", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var journal []storage.JournalDay
+	if err := json.Unmarshal(rec.Body.Bytes(), &journal); err != nil {
+		t.Fatalf("bad journal: %v", err)
+	}
+	if len(journal) != 1 || len(journal[0].Trades) != 1 || len(journal[0].Trades[0].Notes) != 1 {
+		t.Fatalf("journal missing annotated trade: %s", rec.Body)
+	}
+	if rec := do(NewJournalHandler(store), http.MethodGet, JournalPath+"?from=2026-03-10&session=mars", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown session, got %d", rec.Code)
+	}
+
+	if rec := do(notes, http.MethodDelete, AnnotationsPath+"?id=999", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+	if rec := do(notes, http.MethodDelete, AnnotationsPath+"?id=1", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+}
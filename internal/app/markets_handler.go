@@ -0,0 +1,44 @@
+package app
+
+import (
+	"crypto_go/internal/engine"
+	"net/http"
+	"strings"
+)
+
+// MarketsPath is the local HTTP path for the consistent all-markets snapshot.
+const MarketsPath = "/markets"
+
+// MarketsSource provides a snapshot of every market at one sequence point.
+type MarketsSource interface {
+	GetMarketsSnapshot() engine.MarketsSnapshot
+}
+
+// NewMarketsHandler serves the market states of one sequencer sequence point,
+// so dashboards and arbitrage checks never mix states from different moments:
+//
+//	GET /markets?symbols=BTC,ETH
+//
+// symbols (optional) narrows the result; seq identifies the point in the WAL.
+func NewMarketsHandler(src MarketsSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		snap := src.GetMarketsSnapshot()
+		if list := r.URL.Query().Get("symbols"); list != "" {
+			want := make(map[string]bool)
+			for _, s := range strings.Split(list, ",") {
+				want[strings.TrimSpace(s)] = true
+			}
+			for symbol := range snap.Markets {
+				if !want[symbol] {
+					delete(snap.Markets, symbol)
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, snap)
+	})
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMarketsHandler(t *testing.T) {
+	seq := engine.NewSequencer(10, nil, nil, nil)
+	for i, symbol := range []string{"BTC", "ETH", "XRP"} {
+		seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: symbol, PriceMicros: quant.PriceMicros(100 * (i + 1))})
+	}
+	h := NewMarketsHandler(seq)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MarketsPath+"?symbols=BTC,ETH", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var snap engine.MarketsSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("invalid JSON %s: %v", rec.Body, err)
+	}
+	if snap.Seq != 3 {
+		t.Errorf("seq = %d, want 3", snap.Seq)
+	}
+	if len(snap.Markets) != 2 || snap.Markets["ETH"].Symbol != "ETH" {
+		t.Errorf("unexpected markets %+v", snap.Markets)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, MarketsPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,235 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default MQTT topics. {exchange} and {symbol} are replaced per message.
+const (
+	DefaultMQTTPriceTopic   = "cryptogo/{exchange}/{symbol}/price"
+	DefaultMQTTPremiumTopic = "cryptogo/{symbol}/premium"
+	DefaultMQTTAlertTopic   = "cryptogo/alert"
+)
+
+// mqttAlertQueue bounds alerts waiting for the broker; older ones are dropped first.
+const mqttAlertQueue = 64
+
+// MQTTClient is the part of mqtt.Client used by the publisher.
+type MQTTClient interface {
+	Connect(ctx context.Context) error
+	Connected() bool
+	Publish(topic string, payload []byte, retain bool) error
+	Ping() error
+	Close() error
+}
+
+// MQTTPublisherConfig configures an MQTTPublisher. Zero values use the defaults.
+type MQTTPublisherConfig struct {
+	PriceTopic   string        // {exchange}, {symbol}; "" = DefaultMQTTPriceTopic
+	PremiumTopic string        // {symbol}; "" = DefaultMQTTPremiumTopic
+	AlertTopic   string        // "" = DefaultMQTTAlertTopic
+	Symbols      []string      // Published symbols (empty = all)
+	Interval     time.Duration // Each topic gets at most one message per interval (0 = 5s)
+	Retain       bool          // Prices and premiums are retained (last value for new subscribers)
+}
+
+// MQTTAlert is the JSON payload published on the alert topic.
+type MQTTAlert struct {
+	Kind string          `json:"kind"` // e.g. NOTICE_LISTING
+	Text string          `json:"text"`
+	Ts   quant.TimeStamp `json:"ts"`
+}
+
+// MQTTPublisher mirrors selected prices, kimchi premiums and alerts to an MQTT
+// broker for home dashboards and IoT displays. Values are plain decimal strings
+// (price in quote currency, premium in percent) so a display can show the
+// payload as-is. Market updates arrive through Observe on the hotpath and only
+// replace the pending value of their topic; Run publishes the latest values
+// once per interval, so a busy market cannot flood a small device.
+type MQTTPublisher struct {
+	client  MQTTClient
+	cfg     MQTTPublisherConfig
+	symbols map[string]bool // nil = all
+
+	mu      sync.Mutex
+	tracker *analytics.PremiumTracker
+	pending map[string][]byte // Topic → latest unpublished payload
+	alerts  chan MQTTAlert
+}
+
+// NewMQTTPublisher creates a publisher; premiums follow formula.
+func NewMQTTPublisher(client MQTTClient, formula analytics.PremiumFormula, cfg MQTTPublisherConfig) *MQTTPublisher {
+	if cfg.PriceTopic == "" {
+		cfg.PriceTopic = DefaultMQTTPriceTopic
+	}
+	if cfg.PremiumTopic == "" {
+		cfg.PremiumTopic = DefaultMQTTPremiumTopic
+	}
+	if cfg.AlertTopic == "" {
+		cfg.AlertTopic = DefaultMQTTAlertTopic
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	p := &MQTTPublisher{
+		client:  client,
+		cfg:     cfg,
+		tracker: analytics.NewPremiumTracker(0, 0),
+		pending: make(map[string][]byte),
+		alerts:  make(chan MQTTAlert, mqttAlertQueue),
+	}
+	p.tracker.SetFormula(formula)
+	if len(cfg.Symbols) > 0 {
+		p.symbols = make(map[string]bool, len(cfg.Symbols))
+		for _, s := range cfg.Symbols {
+			p.symbols[s] = true
+		}
+	}
+	return p
+}
+
+// Observe takes one market update (Sequencer.SetMarketObserver). Never blocks on the broker.
+func (p *MQTTPublisher) Observe(e event.MarketUpdateEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sample, ok := p.tracker.Observe(&e) // Also takes FX rates
+	if p.symbols != nil && !p.symbols[e.Symbol] {
+		return
+	}
+	p.pending[mqttTopic(p.cfg.PriceTopic, e.Exchange, e.Symbol)] = []byte(e.PriceMicros.String())
+	if ok {
+		p.pending[mqttTopic(p.cfg.PremiumTopic, "", sample.Symbol)] = []byte(analytics.FormatPct(sample.PremiumMicros))
+	}
+}
+
+// Alert queues a message for the alert topic. Never blocks: when the broker is
+// unreachable and the queue is full, the oldest alert is dropped.
+func (p *MQTTPublisher) Alert(kind, text string) {
+	a := MQTTAlert{Kind: kind, Text: text, Ts: quant.TimeStamp(time.Now().UnixMicro())}
+	for {
+		select {
+		case p.alerts <- a:
+			return
+		default:
+		}
+		select {
+		case <-p.alerts:
+			slog.Warn("MQTT_ALERT_DROPPED")
+		default:
+		}
+	}
+}
+
+// Run connects and publishes until ctx ends, reconnecting with backoff.
+func (p *MQTTPublisher) Run(ctx context.Context) {
+	defer p.client.Close()
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	retries := 0
+	var nextDial time.Time
+	for {
+		if !p.client.Connected() && !time.Now().Before(nextDial) {
+			if err := p.client.Connect(ctx); err != nil {
+				retries++
+				nextDial = time.Now().Add(infra.CalculateBackoff(retries))
+				slog.Warn("MQTT_CONNECT_FAILED", slog.Int("retry", retries), slog.Any("error", err))
+			} else {
+				if retries > 0 {
+					slog.Info("MQTT_RECONNECTED", slog.Int("retries", retries))
+				}
+				retries = 0
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case a := <-p.alerts:
+			if !p.publishAlert(a) {
+				p.requeue(a)
+				p.wait(ctx, ticker.C) // Broker down: hold alerts until the next tick
+			}
+		case <-ticker.C:
+			if p.client.Connected() {
+				p.flush()
+				if err := p.client.Ping(); err != nil {
+					slog.Warn("MQTT_PING_FAILED", slog.Any("error", err))
+				}
+			}
+		}
+	}
+}
+
+// wait blocks until the next tick or ctx end.
+func (p *MQTTPublisher) wait(ctx context.Context, tick <-chan time.Time) {
+	select {
+	case <-ctx.Done():
+	case <-tick:
+	}
+}
+
+// requeue puts an undelivered alert back, unless newer alerts filled the queue.
+func (p *MQTTPublisher) requeue(a MQTTAlert) {
+	select {
+	case p.alerts <- a:
+	default:
+		slog.Warn("MQTT_ALERT_DROPPED", slog.String("kind", a.Kind))
+	}
+}
+
+func (p *MQTTPublisher) publishAlert(a MQTTAlert) bool {
+	if !p.client.Connected() {
+		return false
+	}
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return true // Not retriable
+	}
+	if err := p.client.Publish(p.cfg.AlertTopic, payload, false); err != nil {
+		slog.Warn("MQTT_PUBLISH_FAILED", slog.String("topic", p.cfg.AlertTopic), slog.Any("error", err))
+		return false
+	}
+	return true
+}
+
+// flush publishes every pending value. Values that fail stay pending unless a
+// newer one replaced them meanwhile.
+func (p *MQTTPublisher) flush() {
+	p.mu.Lock()
+	batch := p.pending
+	p.pending = make(map[string][]byte, len(batch))
+	p.mu.Unlock()
+
+	for topic, payload := range batch {
+		if err := p.client.Publish(topic, payload, p.cfg.Retain); err != nil {
+			slog.Warn("MQTT_PUBLISH_FAILED", slog.String("topic", topic), slog.Any("error", err))
+			p.mu.Lock()
+			for t, v := range batch {
+				if _, newer := p.pending[t]; !newer {
+					p.pending[t] = v
+				}
+			}
+			p.mu.Unlock()
+			return
+		}
+		delete(batch, topic)
+	}
+}
+
+// mqttTopic fills a topic template. Symbols like "USD/KRW" lose the slash so they
+// stay one topic level.
+func mqttTopic(template, exchange, symbol string) string {
+	return strings.NewReplacer("{exchange}", exchange, "{symbol}", strings.ReplaceAll(symbol, "/", "")).Replace(template)
+}
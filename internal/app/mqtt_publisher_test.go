@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMQTT is an in-memory broker connection.
+type recordingMQTT struct {
+	mu        sync.Mutex
+	connected bool
+	fail      bool
+	published map[string]string
+	order     []string
+}
+
+func (c *recordingMQTT) Connect(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connected = true
+	return nil
+}
+
+func (c *recordingMQTT) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+func (c *recordingMQTT) Publish(topic string, payload []byte, _ bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fail {
+		c.connected = false
+		return errors.New("broken pipe")
+	}
+	c.published[topic] = string(payload)
+	c.order = append(c.order, topic)
+	return nil
+}
+
+func (c *recordingMQTT) Ping() error  { return nil }
+func (c *recordingMQTT) Close() error { return nil }
+
+func TestMQTTPublisher_PricesAndPremium(t *testing.T) {
+	client := &recordingMQTT{published: make(map[string]string)}
+	p := NewMQTTPublisher(client, analytics.DefaultPremiumFormula(), MQTTPublisherConfig{Symbols: []string{"BTC"}})
+	client.connected = true
+
+	upd := func(exchange, symbol string, price int64) event.MarketUpdateEvent {
+		return event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Ts: quant.TimeStamp(time.Now().UnixMicro())},
+			Exchange: exchange, Symbol: symbol, PriceMicros: quant.PriceMicros(price * quant.PriceScale)}
+	}
+	p.Observe(upd("FX", "USD/KRW", 1_370))
+	p.Observe(upd("BITGET_SPOT", "BTC", 100_000))
+	p.Observe(upd("UPBIT", "BTC", 139_000_000))
+	p.Observe(upd("UPBIT", "BTC", 139_740_000)) // Only the latest value per topic is sent
+	p.Observe(upd("UPBIT", "ETH", 5_000_000))   // Not selected
+	p.flush()
+
+	want := map[string]string{
+		"cryptogo/BITGET_SPOT/BTC/price": "100000.000000",
+		"cryptogo/UPBIT/BTC/price":       "139740000.000000",
+		"cryptogo/BTC/premium":           "2.00",
+	}
+	if len(client.published) != len(want) || len(client.order) != len(want) {
+		t.Fatalf("unexpected messages: %v", client.published)
+	}
+	for topic, payload := range want {
+		if client.published[topic] != payload {
+			t.Errorf("%s: expected %q, got %q", topic, payload, client.published[topic])
+		}
+	}
+
+	// A failed flush keeps the values for the next one
+	client.fail = true
+	p.Observe(upd("UPBIT", "BTC", 140_000_000))
+	p.flush()
+	client.fail, client.connected = false, true
+	p.flush()
+	if got := client.published["cryptogo/UPBIT/BTC/price"]; got != "140000000.000000" {
+		t.Errorf("value lost after a failed publish: %q", got)
+	}
+}
+
+func TestMQTTPublisher_Alerts(t *testing.T) {
+	client := &recordingMQTT{published: make(map[string]string)}
+	p := NewMQTTPublisher(client, analytics.DefaultPremiumFormula(), MQTTPublisherConfig{AlertTopic: "home/crypto/alert", Interval: time.Hour})
+
+	for i := 0; i < mqttAlertQueue+1; i++ {
+		p.Alert("NOTICE_LISTING", "old")
+	}
+	p.Alert("NOTICE_DELISTING", "[UPBIT DELISTING] XYZ")
+	if len(p.alerts) != mqttAlertQueue {
+		t.Fatalf("queue must stay bounded, got %d", len(p.alerts))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for len(p.alerts) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	var last MQTTAlert
+	client.mu.Lock()
+	payload := client.published["home/crypto/alert"]
+	client.mu.Unlock()
+	if err := json.Unmarshal([]byte(payload), &last); err != nil || last.Kind != "NOTICE_DELISTING" || last.Ts == 0 {
+		t.Errorf("expected the newest alert last, got %q (%v)", payload, err)
+	}
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OrdersPath is the HTTP path accepting manual order actions.
+const OrdersPath = "/orders"
+
+// OrderRequest is the JSON body of a manual order action, e.g.
+// {"action":"PLACE","symbol":"BTC","side":"BUY","type":"LIMIT","price":"95000000","qty":"0.001"}.
+type OrderRequest struct {
+	Action   string      `json:"action"`             // PLACE | CLOSE | CANCEL_ALL, any case
+	Exchange string      `json:"exchange,omitempty"` // PLACE: venue ("" = routed / default); CLOSE, CANCEL_ALL: "" = all venues
+	Symbol   string      `json:"symbol,omitempty"`   // CLOSE, CANCEL_ALL: "" = every symbol
+	Side     string      `json:"side,omitempty"`
+	Type     string      `json:"type,omitempty"`  // MARKET (default) | LIMIT
+	Price    json.Number `json:"price,omitempty"` // Decimal quote price
+	Qty      json.Number `json:"qty,omitempty"`   // Decimal base quantity
+	Operator string      `json:"operator,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+}
+
+// NewOrderHandler turns authenticated requests into ManualOrderEvents, which
+// the engine routes, risk-checks and records like strategy orders. The token
+// is required as "Authorization: Bearer <token>" and compared in constant
+// time; the handler refuses every request while token is empty.
+func NewOrderHandler(client *engine.ControlClient, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req OrderRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		ev, err := req.event()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), controlSendTimeout)
+		defer cancel()
+		if err := client.SendManualOrder(ctx, ev); err != nil {
+			if errors.Is(err, engine.ErrInvalidManualOrder) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, fmt.Sprintf("order not delivered: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// event converts the request; ControlClient.SendManualOrder validates it.
+func (req OrderRequest) event() (event.ManualOrderEvent, error) {
+	ev := event.ManualOrderEvent{
+		Action:    strings.ToUpper(strings.TrimSpace(req.Action)),
+		Exchange:  strings.ToUpper(strings.TrimSpace(req.Exchange)),
+		Symbol:    strings.ToUpper(strings.TrimSpace(req.Symbol)),
+		Side:      strings.ToUpper(strings.TrimSpace(req.Side)),
+		OrderType: strings.ToUpper(strings.TrimSpace(req.Type)),
+		Operator:  req.Operator,
+		Reason:    req.Reason,
+	}
+	if ev.Operator == "" {
+		ev.Operator = "api"
+	}
+	if ev.Action == event.ManualPlace && ev.OrderType == "" {
+		ev.OrderType = domain.OrderTypeMarket
+	}
+	if req.Price != "" {
+		if ev.PriceMicros = quant.ToPriceMicrosStr(req.Price.String()); ev.PriceMicros <= 0 {
+			return ev, fmt.Errorf("invalid price %q", req.Price)
+		}
+	}
+	if req.Qty != "" {
+		if ev.QtySats = quant.ToQtySatsStr(req.Qty.String()); ev.QtySats <= 0 {
+			return ev, fmt.Errorf("invalid qty %q", req.Qty)
+		}
+	}
+	return ev, nil
+}
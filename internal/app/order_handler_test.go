@@ -0,0 +1,68 @@
+package app
+
+import (
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOrderHandler(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	h := NewOrderHandler(engine.NewControlClient(inbox), "secret")
+
+	post := func(auth, body string) int {
+		req := httptest.NewRequest(http.MethodPost, OrdersPath, strings.NewReader(body))
+		if auth != "" {
+			req.Header.Set("Authorization", "Bearer "+auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	body := `{"action":"place","symbol":"btc","side":"buy","type":"limit","price":"95000000.5","qty":0.001,"reason":"dip"}`
+	if code := post("wrong", body); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a wrong token, got %d", code)
+	}
+	if code := post("", body); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", code)
+	}
+	if code := post("secret", body); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	ev := (<-inbox).(*event.ManualOrderEvent)
+	if ev.Action != event.ManualPlace || ev.Symbol != "BTC" || ev.Side != "BUY" || ev.OrderType != "LIMIT" ||
+		ev.PriceMicros != 95_000_000_500_000 || ev.QtySats != 100_000 || ev.Operator != "api" || ev.Reason != "dip" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	if code := post("secret", `{"action":"PLACE","symbol":"BTC","side":"BUY","qty":"0"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a zero qty, got %d", code)
+	}
+	if code := post("secret", `{"action":"PLACE","symbol":"BTC","side":"HOLD","qty":"1"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown side, got %d", code)
+	}
+	if code := post("secret", `{"action":"CLOSE","exchange":"upbit","symbol":"BTC"}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202 for CLOSE, got %d", code)
+	}
+	if ev := (<-inbox).(*event.ManualOrderEvent); ev.Action != event.ManualClose || ev.Exchange != "UPBIT" || ev.OrderType != "" {
+		t.Errorf("unexpected close event: %+v", ev)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, OrdersPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, OrdersPath, strings.NewReader(`{"action":"CANCEL_ALL"}`))
+	req.Header.Set("Authorization", "Bearer ")
+	NewOrderHandler(engine.NewControlClient(make(chan event.Event, 1)), "").ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with no token configured, got %d", rec.Code)
+	}
+}
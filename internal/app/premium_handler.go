@@ -0,0 +1,125 @@
+package app
+
+import (
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/storage"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PremiumHeatmapPath is the local HTTP path for the hourly premium heatmap.
+const PremiumHeatmapPath = "/premium/heatmap"
+
+// NewPremiumHeatmapHandler reports the kimchi premium by symbol x hour of day,
+// rebuilt from the market updates stored in the WAL:
+//
+//	GET /premium/heatmap?from=2026-03-01&to=2026-03-31&session=kst&domestic=BITHUMB&foreign=OKX_SPOT&price=bid_ask&format=text
+//
+// to defaults to from; session (kst | upbit | bitget) sets both the day range and
+// the hour labels; domestic, foreign and price override the configured formula
+// (see analytics.PremiumFormula); format=text returns a plain table instead of JSON.
+//
+// fxMaxAge sets how old each FX pair may be (pairs not listed use the default).
+func NewPremiumHeatmapHandler(store *storage.EventStore, formula analytics.PremiumFormula, fxMaxAge map[string]time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q := r.URL.Query()
+		fromDay, toDay := q.Get("from"), q.Get("to")
+		if toDay == "" {
+			toDay = fromDay
+		}
+		name := strings.ToLower(q.Get("session"))
+		if name == "" {
+			name = "kst"
+		}
+		session, ok := journalSessions[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown session %q", name), http.StatusBadRequest)
+			return
+		}
+		from, err := session.ParseDay(fromDay)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid from day: %v", err), http.StatusBadRequest)
+			return
+		}
+		last, err := session.ParseDay(toDay)
+		if err != nil || last < from {
+			http.Error(w, fmt.Sprintf("invalid to day %q", toDay), http.StatusBadRequest)
+			return
+		}
+
+		builder := analytics.NewHeatmapBuilder(session.Zone)
+		f := formula
+		if v := q.Get("domestic"); v != "" {
+			f.Domestic = strings.ToUpper(v)
+		}
+		if v := q.Get("foreign"); v != "" {
+			f.Foreign = strings.ToUpper(v)
+		}
+		if v := q.Get("price"); v != "" {
+			f.Price = strings.ToLower(v)
+		}
+		if err := f.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tracker := analytics.NewPremiumTracker(0, 0)
+		tracker.SetFormula(f)
+		for pair, maxAge := range fxMaxAge {
+			tracker.SetFXMaxAge(pair, maxAge)
+		}
+		if err := analytics.ScanPremiums(r.Context(), store, from, session.NextBoundary(last), tracker, builder.Add); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		heatmap := builder.Result()
+		if q.Get("format") == "text" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, heatmap.Text())
+			return
+		}
+		writeJSON(w, http.StatusOK, heatmap)
+	})
+}
+
+// BuildPremiumFormula converts and validates the premium config section.
+// Empty fields keep the default (Upbit vs Bitget spot, last price).
+func BuildPremiumFormula(cfg *infra.Config) (analytics.PremiumFormula, error) {
+	f := analytics.DefaultPremiumFormula()
+	pc := cfg.Premium
+	if pc.Domestic != "" {
+		f.Domestic = strings.ToUpper(pc.Domestic)
+	}
+	if pc.Foreign != "" {
+		f.Foreign = strings.ToUpper(pc.Foreign)
+	}
+	if pc.Price != "" {
+		f.Price = strings.ToLower(pc.Price)
+	}
+	if err := f.Validate(); err != nil {
+		return analytics.PremiumFormula{}, fmt.Errorf("premium: %w", err)
+	}
+	return f, nil
+}
+
+// FXMaxAges returns the configured staleness limit of every FX pair that sets one.
+func FXMaxAges(cfg *infra.Config) map[string]time.Duration {
+	out := make(map[string]time.Duration)
+	if sec := cfg.API.ExchangeRate.MaxAgeSec; sec > 0 {
+		out["USD/KRW"] = time.Duration(sec) * time.Second
+	}
+	for _, p := range cfg.API.ExchangeRate.Pairs {
+		if p.MaxAgeSec > 0 {
+			out[p.Symbol] = time.Duration(p.MaxAgeSec) * time.Second
+		}
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPremiumHeatmapHandler(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/premium.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	h := NewPremiumHeatmapHandler(store, analytics.DefaultPremiumFormula(), nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PremiumHeatmapPath+"?from=2026-03-01&to=2026-03-31", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var heatmap analytics.PremiumHeatmap
+	if err := json.Unmarshal(rec.Body.Bytes(), &heatmap); err != nil || heatmap.Zone != "KST" {
+		t.Errorf("unexpected heatmap %s (%v)", rec.Body, err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PremiumHeatmapPath+"?from=March", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad day, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PremiumHeatmapPath+"?from=2026-03-01&domestic=korbit", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown domestic exchange, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PremiumHeatmapPath+"?from=2026-03-01&price=mid", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown price basis, got %d", rec.Code)
+	}
+}
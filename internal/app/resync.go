@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/infra"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// resyncAttempts bounds the REST snapshot retries of one resync.
+const resyncAttempts = 3
+
+// ResyncSource is how one gateway is brought back in sync after a sequence gap.
+type ResyncSource struct {
+	Reconnect func()                                              // Restarts the stream (required)
+	Snapshot  func(ctx context.Context) error                     // Sends REST ticker/order book snapshots into the inbox (optional)
+	Balances  func(ctx context.Context) ([]domain.Balance, error) // Venue balances booked by the END (optional)
+}
+
+// Resyncer runs the resync protocol behind engine.GapResync. The sequencer
+// pauses order dispatch with a BEGIN and calls Resync; the Resyncer then
+// reconnects the gateway, sends its REST snapshots through the inbox like any
+// gateway event, fetches its balances and sends the END that resumes dispatch.
+// An END is sent even when the snapshots failed (after resyncAttempts): the
+// reconnected stream recovers the state, and trading must not stay paused
+// behind a flaky REST endpoint.
+type Resyncer struct {
+	ctx    context.Context
+	client *engine.ResyncClient
+
+	mu      sync.Mutex
+	sources map[string]ResyncSource
+}
+
+// NewResyncer creates a resyncer sending its END events with client. Resyncs
+// stop when ctx is done.
+func NewResyncer(ctx context.Context, client *engine.ResyncClient) *Resyncer {
+	return &Resyncer{ctx: ctx, client: client, sources: make(map[string]ResyncSource)}
+}
+
+// Register sets how gateway source (its event source name, e.g. "UPBIT") is resynced.
+func (r *Resyncer) Register(source string, src ResyncSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources[source] = src
+}
+
+// Resync starts resyncing source in the background. It never blocks, so it
+// can be the sequencer's resync handler. Unknown sources are ended at once.
+func (r *Resyncer) Resync(source string) {
+	r.mu.Lock()
+	src, ok := r.sources[source]
+	r.mu.Unlock()
+	go r.run(source, src, ok)
+}
+
+func (r *Resyncer) run(source string, src ResyncSource, known bool) {
+	detail := "no resync registered"
+	var balances []domain.Balance
+	if known {
+		detail = "ok"
+		if src.Reconnect != nil {
+			src.Reconnect()
+		}
+		if src.Snapshot != nil {
+			if err := r.retry(func() error { return src.Snapshot(r.ctx) }); err != nil {
+				slog.Error("RESYNC_SNAPSHOT_FAILED", slog.String("source", source), slog.Any("error", err))
+				detail = "snapshot failed: " + err.Error()
+			}
+		}
+		if src.Balances != nil {
+			err := r.retry(func() (err error) {
+				balances, err = src.Balances(r.ctx)
+				return err
+			})
+			if err != nil {
+				slog.Error("RESYNC_BALANCES_FAILED", slog.String("source", source), slog.Any("error", err))
+				balances = nil
+			}
+		}
+	}
+
+	if err := r.client.End(r.ctx, source, detail, balances); err != nil {
+		slog.Warn("Resync end not delivered", slog.String("source", source), slog.Any("error", err))
+	}
+}
+
+// retry calls fn up to resyncAttempts times with backoff.
+func (r *Resyncer) retry(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < resyncAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		select {
+		case <-time.After(infra.CalculateBackoff(attempt)):
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		}
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"testing"
+	"time"
+)
+
+func TestResyncer(t *testing.T) {
+	inbox := make(chan event.Event, 4)
+	r := NewResyncer(context.Background(), engine.NewResyncClient(inbox))
+
+	var reconnected, snapshotted bool
+	r.Register("UPBIT", ResyncSource{
+		Reconnect: func() { reconnected = true },
+		Snapshot: func(ctx context.Context) error {
+			snapshotted = true
+			inbox <- &event.MarketUpdateEvent{Symbol: "KRW-BTC"}
+			return nil
+		},
+		Balances: func(ctx context.Context) ([]domain.Balance, error) {
+			return []domain.Balance{{Symbol: "KRW", AmountSats: 100}}, nil
+		},
+	})
+
+	r.Resync("UPBIT")
+	recv := func() event.Event {
+		select {
+		case ev := <-inbox:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the resync")
+			return nil
+		}
+	}
+	if _, ok := recv().(*event.MarketUpdateEvent); !ok {
+		t.Fatal("the snapshot must reach the inbox before the END")
+	}
+	end, ok := recv().(*event.ResyncEvent)
+	if !ok || end.Phase != event.ResyncEnd || end.Source != "UPBIT" || end.Detail != "ok" || end.Seq != 1 {
+		t.Fatalf("unexpected END: %+v", end)
+	}
+	if len(end.Balances) != 1 || end.Balances[0].AmountSats != 100 {
+		t.Errorf("END must carry the venue balances: %+v", end.Balances)
+	}
+	if !reconnected || !snapshotted {
+		t.Errorf("reconnect=%v snapshot=%v; want both", reconnected, snapshotted)
+	}
+
+	// An unregistered source is ended at once so dispatch does not stay paused
+	r.Resync("OKX")
+	if end := recv().(*event.ResyncEvent); end.Source != "OKX" || end.Seq != 2 || end.Detail != "no resync registered" {
+		t.Errorf("unexpected END for an unknown source: %+v", end)
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"crypto_go/internal/infra"
+	"net/http"
+)
+
+// RiskPath is the local HTTP path for the portfolio exposure and VaR.
+const RiskPath = "/risk"
+
+// NewRiskHandler serves the exposure per asset class (spot/perpetual x
+// KRW/USDT) and the historical VaR per quote currency of the open positions:
+//
+//	GET /risk
+//
+// 404 without a risk engine (engine.risk disabled).
+func NewRiskHandler(src infra.RiskSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		rep, ok := src.GetRiskReport()
+		if !ok {
+			http.Error(w, "risk engine disabled", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, rep)
+	})
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/risk"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRiskHandler(t *testing.T) {
+	seq := engine.NewSequencer(10, nil, nil, nil)
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		NewRiskHandler(seq).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, RiskPath, nil))
+		return rec
+	}
+	if rec := get(); rec.Code != http.StatusNotFound {
+		t.Errorf("without a risk engine: expected 404, got %d", rec.Code)
+	}
+
+	oms := engine.NewOrderManager("t", 4)
+	oms.SetRisk(risk.NewEngine(risk.Limits{}, seq.PositionBook()))
+	seq.SetOrderManager(oms)
+	seq.PositionBook().Get("UPBIT", "BTC").ApplyFill(domain.SideBuy, quant.QtyScale, 1_000_000, 1)
+
+	rec := get()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var rep risk.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid JSON %s: %v", rec.Body, err)
+	}
+	if len(rep.Exposure) != 1 || rep.Exposure[0].Class.Quote != domain.QuoteKRW || rep.Exposure[0].NetMicros != 1_000_000 {
+		t.Errorf("unexpected report %+v", rep)
+	}
+}
@@ -0,0 +1,344 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SettingKind is the type of a setting's value.
+type SettingKind string
+
+const (
+	SettingBool     SettingKind = "bool"
+	SettingInt      SettingKind = "int"
+	SettingDuration SettingKind = "duration" // time.ParseDuration syntax, e.g. "90s"
+	SettingDecimal  SettingKind = "decimal"  // Fixed point with up to 6 decimals, read as micros
+	SettingString   SettingKind = "string"
+)
+
+// Setting keys used by the monitor.
+const (
+	SettingUITheme          = "ui.theme"
+	SettingNoticeAlerts     = "alerts.notices"
+	SettingRiskMaxNotional  = "risk.max_order_notional"
+	SettingRiskDailyLoss    = "risk.daily_loss_limit"
+	SettingRiskMaxOpenOrder = "risk.max_open_orders"
+)
+
+// settingKeyPrefix namespaces settings in the metadata table.
+const settingKeyPrefix = "setting:"
+
+// Errors returned by Settings.Set.
+var (
+	ErrUnknownSetting = errors.New("unknown setting")
+	ErrInvalidSetting = errors.New("invalid setting value")
+)
+
+// SettingDef declares a setting: its type, default and constraints.
+type SettingDef struct {
+	Key         string
+	Kind        SettingKind
+	Default     string
+	Help        string
+	NonNegative bool     // Int, duration and decimal settings only
+	Choices     []string // String settings only; empty allows any value
+}
+
+// SettingInfo is a setting as listed for the UI.
+type SettingInfo struct {
+	domain.AppConfig
+	Kind    SettingKind `json:"kind"`
+	Default string      `json:"default"`
+	Help    string      `json:"help,omitempty"`
+	Choices []string    `json:"choices,omitempty"`
+}
+
+// SettingsStore persists settings (storage.EventStore's metadata table).
+type SettingsStore interface {
+	GetMetadata(ctx context.Context, key string) (string, error)
+	UpsertMetadata(ctx context.Context, key, value string, ts int64) error
+}
+
+// Settings serves typed, validated application settings persisted as
+// domain.AppConfig entries. Every value is parsed once, when it is loaded or
+// set, so readers get a bool, int, duration or micros instead of a raw string.
+// Getters panic on a key that is not defined with the requested kind: keys
+// are constants, so that is a programming error.
+type Settings struct {
+	store SettingsStore
+	defs  []SettingDef
+	index map[string]int
+
+	mu      sync.RWMutex
+	values  map[string]any // bool | int64 | time.Duration | string
+	entries map[string]domain.AppConfig
+	subs    map[string][]func()
+}
+
+// NewSettings creates the settings of defs, all at their defaults until Load.
+// It panics on an invalid definition.
+func NewSettings(store SettingsStore, defs []SettingDef) *Settings {
+	s := &Settings{
+		store:   store,
+		defs:    defs,
+		index:   make(map[string]int, len(defs)),
+		values:  make(map[string]any, len(defs)),
+		entries: make(map[string]domain.AppConfig, len(defs)),
+		subs:    make(map[string][]func()),
+	}
+	for i, def := range defs {
+		if _, dup := s.index[def.Key]; dup {
+			panic(fmt.Sprintf("settings: duplicate key %q", def.Key))
+		}
+		v, err := parseSetting(def, def.Default)
+		if err != nil {
+			panic(fmt.Sprintf("settings: invalid default of %q: %v", def.Key, err))
+		}
+		s.index[def.Key] = i
+		s.values[def.Key] = v
+		s.entries[def.Key] = domain.AppConfig{Key: def.Key, Value: def.Default}
+	}
+	return s
+}
+
+// Load reads the persisted values. A stored value that no longer validates
+// (e.g. after a definition changed) is logged and the default kept.
+func (s *Settings) Load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, def := range s.defs {
+		raw, err := s.store.GetMetadata(ctx, settingKeyPrefix+def.Key)
+		if err != nil {
+			return fmt.Errorf("failed to load setting %s: %w", def.Key, err)
+		}
+		if raw == "" {
+			continue
+		}
+		var entry domain.AppConfig
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			slog.Warn("SETTING_INVALID", slog.String("key", def.Key), slog.Any("error", err))
+			continue
+		}
+		v, err := parseSetting(def, entry.Value)
+		if err != nil {
+			slog.Warn("SETTING_INVALID", slog.String("key", def.Key), slog.String("value", entry.Value), slog.Any("error", err))
+			continue
+		}
+		entry.Key = def.Key
+		s.values[def.Key] = v
+		s.entries[def.Key] = entry
+	}
+	return nil
+}
+
+// Set validates and persists a new value, then calls the key's OnChange
+// subscribers. An invalid value is rejected and changes nothing.
+func (s *Settings) Set(ctx context.Context, key, value string) error {
+	i, ok := s.index[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+	def := s.defs[i]
+	v, err := parseSetting(def, value)
+	if err != nil {
+		return fmt.Errorf("%w for %s: %v", ErrInvalidSetting, key, err)
+	}
+
+	entry := domain.AppConfig{Key: key, Value: strings.TrimSpace(value), UpdatedAtUnixM: time.Now().UnixMicro()}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if err := s.store.UpsertMetadata(ctx, settingKeyPrefix+key, string(data), entry.UpdatedAtUnixM); err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to save setting %s: %w", key, err)
+	}
+	s.values[key] = v
+	s.entries[key] = entry
+	subs := slices.Clone(s.subs[key])
+	s.mu.Unlock()
+
+	slog.Info("Setting changed", slog.String("key", key), slog.String("value", entry.Value))
+	for _, fn := range subs {
+		fn()
+	}
+	return nil
+}
+
+// OnChange calls fn after every successful Set of key; fn reads the new value
+// with the typed getter. It panics on an unknown key.
+func (s *Settings) OnChange(key string, fn func()) {
+	s.def(key)
+	s.mu.Lock()
+	s.subs[key] = append(s.subs[key], fn)
+	s.mu.Unlock()
+}
+
+// All lists every setting in definition order.
+func (s *Settings) All() []SettingInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]SettingInfo, 0, len(s.defs))
+	for _, def := range s.defs {
+		out = append(out, SettingInfo{
+			AppConfig: s.entries[def.Key],
+			Kind:      def.Kind,
+			Default:   def.Default,
+			Help:      def.Help,
+			Choices:   def.Choices,
+		})
+	}
+	return out
+}
+
+// GetBool returns a bool setting.
+func (s *Settings) GetBool(key string) bool { return s.get(key, SettingBool).(bool) }
+
+// GetInt returns an int setting.
+func (s *Settings) GetInt(key string) int64 { return s.get(key, SettingInt).(int64) }
+
+// GetDuration returns a duration setting.
+func (s *Settings) GetDuration(key string) time.Duration {
+	return s.get(key, SettingDuration).(time.Duration)
+}
+
+// GetDecimal returns a decimal setting in micros (1.5 -> 1_500_000).
+func (s *Settings) GetDecimal(key string) int64 { return s.get(key, SettingDecimal).(int64) }
+
+// GetString returns a string setting.
+func (s *Settings) GetString(key string) string { return s.get(key, SettingString).(string) }
+
+func (s *Settings) def(key string) SettingDef {
+	i, ok := s.index[key]
+	if !ok {
+		panic(fmt.Sprintf("settings: undefined key %q", key))
+	}
+	return s.defs[i]
+}
+
+func (s *Settings) get(key string, kind SettingKind) any {
+	if def := s.def(key); def.Kind != kind {
+		panic(fmt.Sprintf("settings: %q is a %s setting, not %s", key, def.Kind, kind))
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[key]
+}
+
+// parseSetting parses and validates value for def.
+func parseSetting(def SettingDef, value string) (any, error) {
+	value = strings.TrimSpace(value)
+	var n int64
+	switch def.Kind {
+	case SettingBool:
+		return strconv.ParseBool(value)
+	case SettingString:
+		if len(def.Choices) > 0 && !slices.Contains(def.Choices, value) {
+			return nil, fmt.Errorf("%q is not one of %s", value, strings.Join(def.Choices, ", "))
+		}
+		return value, nil
+	case SettingInt:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		n = v
+	case SettingDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		if def.NonNegative && d < 0 {
+			return nil, errors.New("must not be negative")
+		}
+		return d, nil
+	case SettingDecimal:
+		v, err := parseDecimalMicros(value)
+		if err != nil {
+			return nil, err
+		}
+		n = v
+	default:
+		return nil, fmt.Errorf("unknown kind %q", def.Kind)
+	}
+	if def.NonNegative && n < 0 {
+		return nil, errors.New("must not be negative")
+	}
+	return n, nil
+}
+
+// parseDecimalMicros parses a plain decimal with up to 6 fraction digits into
+// micros without float64. Unlike quant.ToPriceMicrosStr it rejects malformed
+// input instead of reading it as 0.
+func parseDecimalMicros(s string) (int64, error) {
+	neg := strings.HasPrefix(s, "-")
+	intStr, fracStr, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if intStr == "" && fracStr == "" || strings.ContainsAny(intStr+fracStr, "+-") {
+		return 0, fmt.Errorf("%q is not a decimal", s)
+	}
+	if len(fracStr) > 6 {
+		return 0, fmt.Errorf("%q has more than 6 decimals", s)
+	}
+	var whole, frac uint64
+	var err error
+	if intStr != "" {
+		if whole, err = strconv.ParseUint(intStr, 10, 63); err != nil {
+			return 0, fmt.Errorf("%q is not a decimal", s)
+		}
+	}
+	if fracStr != "" {
+		if frac, err = strconv.ParseUint(fracStr+strings.Repeat("0", 6-len(fracStr)), 10, 63); err != nil {
+			return 0, fmt.Errorf("%q is not a decimal", s)
+		}
+	}
+	if whole > (1<<63-1-frac)/1_000_000 {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+	v := int64(whole*1_000_000 + frac)
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// formatMicros renders micros as a decimal accepted by parseDecimalMicros.
+func formatMicros(v int64) string {
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	s := fmt.Sprintf("%s%d.%06d", sign, v/1_000_000, v%1_000_000)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// DefaultSettings defines the runtime settings with cfg as defaults: the UI
+// theme, whether exchange notices raise alerts, and the risk limits that can
+// be changed while running (applied as SET_RISK_LIMIT control commands).
+func DefaultSettings(cfg *infra.Config) []SettingDef {
+	theme := cfg.UI.Theme
+	if theme == "" {
+		theme = "dark"
+	}
+	r := cfg.Engine.Risk
+	return []SettingDef{
+		{Key: SettingUITheme, Kind: SettingString, Default: theme, Choices: []string{"dark", "light"}, Help: "UI color theme"},
+		{Key: SettingNoticeAlerts, Kind: SettingBool, Default: "true", Help: "Send MQTT/webhook alerts for exchange notices"},
+		{Key: SettingRiskMaxNotional, Kind: SettingDecimal, Default: formatMicros(r.MaxOrderNotional), NonNegative: true,
+			Help: "Max notional of one order in the quote currency (0 = off)"},
+		{Key: SettingRiskDailyLoss, Kind: SettingDecimal, Default: formatMicros(r.DailyLossLimit), NonNegative: true,
+			Help: "Max net PnL drop per UTC day in the quote currency (0 = off)"},
+		{Key: SettingRiskMaxOpenOrder, Kind: SettingInt, Default: strconv.FormatInt(r.MaxOpenOrders, 10), NonNegative: true,
+			Help: "Max open orders (0 = off)"},
+	}
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// SettingsPath is the local HTTP path of the runtime settings.
+const SettingsPath = "/settings"
+
+// SettingRequest is the JSON body of a settings change.
+type SettingRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// NewSettingsHandler exposes settings to the UI:
+//
+//	GET  /settings                                          list with kinds and defaults
+//	POST /settings  {"key":"alerts.notices","value":"false"}  validate, persist, apply
+//
+// Mount it on the localhost-only admin listener: risk limits are among the settings.
+func NewSettingsHandler(settings *Settings) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, settings.All())
+
+		case http.MethodPost:
+			var req SettingRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			err := settings.Set(r.Context(), req.Key, req.Value)
+			switch {
+			case errors.Is(err, ErrUnknownSetting):
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case errors.Is(err, ErrInvalidSetting):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			default:
+				w.WriteHeader(http.StatusNoContent)
+			}
+
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
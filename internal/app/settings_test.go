@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSettings(t *testing.T) (*storage.EventStore, []SettingDef) {
+	t.Helper()
+	store, err := storage.NewEventStore(t.TempDir() + "/settings.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	cfg := &infra.Config{}
+	cfg.Engine.Risk.MaxOrderNotional = 2_500_000
+	defs := append(DefaultSettings(cfg), SettingDef{Key: "alerts.cooldown", Kind: SettingDuration, Default: "1m", NonNegative: true})
+	return store, defs
+}
+
+func TestSettings_TypedValues(t *testing.T) {
+	ctx := context.Background()
+	store, defs := newTestSettings(t)
+	s := NewSettings(store, defs)
+	if err := s.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.GetString(SettingUITheme) != "dark" || !s.GetBool(SettingNoticeAlerts) ||
+		s.GetDecimal(SettingRiskMaxNotional) != 2_500_000 || s.GetDuration("alerts.cooldown") != time.Minute {
+		t.Fatalf("defaults not applied: %+v", s.All())
+	}
+
+	changed := 0
+	s.OnChange(SettingRiskMaxNotional, func() { changed++ })
+	if err := s.Set(ctx, SettingRiskMaxNotional, "10.05"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetDecimal(SettingRiskMaxNotional); got != 10_050_000 || changed != 1 {
+		t.Errorf("expected 10050000 and one notification, got %d (%d)", got, changed)
+	}
+
+	for key, value := range map[string]string{
+		SettingRiskMaxNotional:  "-1",
+		SettingRiskMaxOpenOrder: "3.5",
+		SettingNoticeAlerts:     "maybe",
+		SettingUITheme:          "neon",
+		"alerts.cooldown":       "soon",
+	} {
+		if err := s.Set(ctx, key, value); !errors.Is(err, ErrInvalidSetting) {
+			t.Errorf("%s=%q: expected ErrInvalidSetting, got %v", key, value, err)
+		}
+	}
+	if changed != 1 || s.GetDecimal(SettingRiskMaxNotional) != 10_050_000 {
+		t.Error("a rejected value must change nothing")
+	}
+	if err := s.Set(ctx, "ui.font", "mono"); !errors.Is(err, ErrUnknownSetting) {
+		t.Errorf("expected ErrUnknownSetting, got %v", err)
+	}
+
+	// Persisted: a new instance loads the value
+	reloaded := NewSettings(store, defs)
+	if err := reloaded.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.GetDecimal(SettingRiskMaxNotional) != 10_050_000 {
+		t.Errorf("value not persisted: %+v", reloaded.All())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("reading a setting as the wrong kind must panic")
+		}
+	}()
+	s.GetBool(SettingUITheme)
+}
+
+func TestSettings_InvalidStoredValueKeepsDefault(t *testing.T) {
+	ctx := context.Background()
+	store, defs := newTestSettings(t)
+	store.UpsertMetadata(ctx, settingKeyPrefix+SettingRiskMaxOpenOrder, `{"key":"risk.max_open_orders","value":"lots"}`, 0)
+
+	s := NewSettings(store, defs)
+	if err := s.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.GetInt(SettingRiskMaxOpenOrder) != 0 {
+		t.Errorf("an invalid stored value must fall back to the default, got %d", s.GetInt(SettingRiskMaxOpenOrder))
+	}
+}
+
+func TestParseDecimalMicros(t *testing.T) {
+	valid := map[string]int64{"1": 1_000_000, "1.5": 1_500_000, ".25": 250_000, "-0.000001": -1, "1.": 1_000_000}
+	for in, want := range valid {
+		if got, err := parseDecimalMicros(in); err != nil || got != want {
+			t.Errorf("parseDecimalMicros(%q) = %d, %v; want %d", in, got, err, want)
+		}
+		if back, _ := parseDecimalMicros(formatMicros(want)); back != want {
+			t.Errorf("formatMicros(%d) = %q does not parse back", want, formatMicros(want))
+		}
+	}
+	for _, in := range []string{"", ".", "1.2.3", "1e6", "--1", "1.0000001", "9223372036855"} {
+		if _, err := parseDecimalMicros(in); err == nil {
+			t.Errorf("parseDecimalMicros(%q) must fail", in)
+		}
+	}
+}
+
+func TestSettingsHandler(t *testing.T) {
+	store, defs := newTestSettings(t)
+	s := NewSettings(store, defs)
+	h := NewSettingsHandler(s)
+
+	post := func(body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, SettingsPath, strings.NewReader(body)))
+		return rec.Code
+	}
+	if code := post(`{"key":"alerts.notices","value":"false"}`); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if code := post(`{"key":"alerts.notices","value":"nope"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid value, got %d", code)
+	}
+	if code := post(`{"key":"alerts.volume","value":"1"}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown key, got %d", code)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SettingsPath, nil))
+	var list []SettingInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range list {
+		if info.Key == SettingNoticeAlerts && (info.Value != "false" || info.Default != "true" || info.Kind != SettingBool) {
+			t.Errorf("unexpected listing: %+v", info)
+		}
+	}
+	if len(list) != len(defs) {
+		t.Errorf("expected %d settings, got %d", len(defs), len(list))
+	}
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SignalsPath is the HTTP path accepting external signal webhooks.
+const SignalsPath = "/signals"
+
+// SignalRequest is the JSON body of a signal webhook, e.g. a TradingView alert
+// message: {"token":"...","origin":"tradingview","symbol":"{{ticker}}","action":"{{strategy.order.action}}","price":{{close}}}
+type SignalRequest struct {
+	Token  string      `json:"token,omitempty"` // For senders that cannot set headers (TradingView)
+	Origin string      `json:"origin"`
+	Name   string      `json:"name,omitempty"`
+	Symbol string      `json:"symbol"`
+	Action string      `json:"action"`          // BUY / SELL / CLOSE, any case
+	Price  json.Number `json:"price,omitempty"` // Decimal, number or string
+	Note   string      `json:"note,omitempty"`
+}
+
+// NewSignalHandler turns authenticated webhooks into SignalEvents. The shared
+// token is accepted as "Authorization: Bearer <token>", an X-Signal-Token header
+// or the body's "token" field, and compared in constant time. Unlike the admin
+// endpoints this handler is meant to be reachable from outside: serve it on its
+// own listener (behind TLS), never on the admin one.
+func NewSignalHandler(client *engine.SignalClient, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req SignalRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if !signalAuthorized(r, req.Token, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		sig, err := req.signal()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), controlSendTimeout)
+		defer cancel()
+		if err := client.Send(ctx, sig); err != nil {
+			http.Error(w, fmt.Sprintf("signal not delivered: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// signalAuthorized checks the header token, falling back to the body token.
+func signalAuthorized(r *http.Request, bodyToken, token string) bool {
+	got := r.Header.Get("X-Signal-Token")
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		got = auth
+	}
+	if got == "" {
+		got = bodyToken
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// signal validates the request and converts it to the domain type.
+func (req SignalRequest) signal() (domain.Signal, error) {
+	sig := domain.Signal{
+		Origin: req.Origin,
+		Name:   req.Name,
+		Symbol: strings.ToUpper(strings.TrimSpace(req.Symbol)),
+		Action: strings.ToUpper(strings.TrimSpace(req.Action)),
+		Note:   req.Note,
+	}
+	if sig.Origin == "" {
+		sig.Origin = "webhook"
+	}
+	if sig.Symbol == "" {
+		return domain.Signal{}, fmt.Errorf("symbol is required")
+	}
+	if !domain.IsSignalAction(sig.Action) {
+		return domain.Signal{}, fmt.Errorf("unknown action %q (want BUY, SELL or CLOSE)", req.Action)
+	}
+	if req.Price != "" {
+		sig.PriceMicros = quant.ToPriceMicrosStr(req.Price.String())
+		if sig.PriceMicros <= 0 {
+			return domain.Signal{}, fmt.Errorf("invalid price %q", req.Price)
+		}
+	}
+	return sig, nil
+}
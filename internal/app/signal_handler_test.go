@@ -0,0 +1,64 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSignalHandler(t *testing.T) {
+	inbox := make(chan event.Event, 2)
+	h := NewSignalHandler(engine.NewSignalClient(inbox), "s3cret")
+
+	post := func(body string, header ...string) int {
+		req := httptest.NewRequest(http.MethodPost, SignalsPath, strings.NewReader(body))
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// TradingView style: token in the body, lower-case action, numeric price
+	if code := post(`{"token":"s3cret","origin":"tradingview","symbol":"btcusdt","action":"buy","price":64250.5}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	ev := (<-inbox).(*event.SignalEvent)
+	if ev.Symbol != "BTCUSDT" || ev.Action != domain.SignalBuy || ev.PriceMicros != 64250_500000 || ev.Origin != "tradingview" || ev.Seq != 1 {
+		t.Errorf("unexpected signal event: %+v", ev)
+	}
+
+	if code := post(`{"symbol":"ETHUSDT","action":"CLOSE"}`, "Authorization", "Bearer s3cret"); code != http.StatusAccepted {
+		t.Fatalf("expected 202 with bearer token, got %d", code)
+	}
+	if ev := (<-inbox).(*event.SignalEvent); ev.Origin != "webhook" || ev.Seq != 2 {
+		t.Errorf("unexpected signal event: %+v", ev)
+	}
+
+	if code := post(`{"symbol":"ETHUSDT","action":"BUY"}`, "X-Signal-Token", "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a wrong token, got %d", code)
+	}
+	if code := post(`{"symbol":"ETHUSDT","action":"BUY"}`); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", code)
+	}
+	if code := post(`{"token":"s3cret","symbol":"ETHUSDT","action":"HODL"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown action, got %d", code)
+	}
+	if code := post(`{"token":"s3cret","symbol":"ETHUSDT","action":"BUY","price":"-1"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for negative price, got %d", code)
+	}
+	if len(inbox) != 0 {
+		t.Errorf("rejected requests must not emit events")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SignalsPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+}
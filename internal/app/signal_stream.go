@@ -0,0 +1,207 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// SignalStreamPath serves the strategy's orders to external executors.
+const SignalStreamPath = "/strategy-signals"
+
+// signalSubscriberBuffer is how many signals a WebSocket client may fall
+// behind before it is disconnected (it resumes with ?after_seq=).
+const signalSubscriberBuffer = 256
+
+// StrategySignal is one strategy order that passed the engine's pre-trade
+// checks. Seq and Index identify it across restarts: the WAL never replays a
+// signal to the stream, so an executor deduplicates on the pair.
+type StrategySignal struct {
+	Seq   uint64          `json:"seq,string"` // Sequencer event that produced it
+	Index int             `json:"index"`      // Position among the orders of that event
+	Ts    quant.TimeStamp `json:"ts,string"`
+	Order domain.Order    `json:"order"`
+}
+
+// SignalPage is the REST answer of the signal stream.
+type SignalPage struct {
+	Signals []StrategySignal `json:"signals"`
+	Gap     bool             `json:"gap"` // Signals after after_seq were dropped from the buffer
+}
+
+// SignalBroadcaster keeps the most recent strategy signals and fans them out
+// to WebSocket clients. Publish is the sequencer's order observer.
+type SignalBroadcaster struct {
+	mu      sync.Mutex
+	recent  []StrategySignal // Oldest first, at most size
+	size    int
+	dropped uint64 // Seq of the newest signal pushed out of recent (0 = none)
+	subs    map[chan StrategySignal]struct{}
+}
+
+// NewSignalBroadcaster creates a broadcaster buffering size signals (0 = 1000).
+func NewSignalBroadcaster(size int) *SignalBroadcaster {
+	if size <= 0 {
+		size = 1000
+	}
+	return &SignalBroadcaster{size: size, subs: make(map[chan StrategySignal]struct{})}
+}
+
+// Publish records an order and sends it to every client. Never blocks: a
+// client whose buffer is full is disconnected.
+func (b *SignalBroadcaster) Publish(order domain.Order, seq uint64, ts quant.TimeStamp) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sig := StrategySignal{Seq: seq, Ts: ts, Order: order}
+	if n := len(b.recent); n > 0 && b.recent[n-1].Seq == seq {
+		sig.Index = b.recent[n-1].Index + 1
+	}
+	if len(b.recent) == b.size {
+		b.dropped = b.recent[0].Seq
+		b.recent = append(b.recent[:0], b.recent[1:]...)
+	}
+	b.recent = append(b.recent, sig)
+
+	for ch := range b.subs {
+		select {
+		case ch <- sig:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Since returns the buffered signals of events after afterSeq, oldest first.
+func (b *SignalBroadcaster) Since(afterSeq uint64) SignalPage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.since(afterSeq)
+}
+
+func (b *SignalBroadcaster) since(afterSeq uint64) SignalPage {
+	page := SignalPage{Signals: []StrategySignal{}, Gap: b.dropped > afterSeq}
+	for _, sig := range b.recent {
+		if sig.Seq > afterSeq {
+			page.Signals = append(page.Signals, sig)
+		}
+	}
+	return page
+}
+
+// subscribe returns the backlog after afterSeq and a channel receiving every
+// later signal; the channel is closed if the client falls behind.
+func (b *SignalBroadcaster) subscribe(afterSeq uint64) (SignalPage, chan StrategySignal) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan StrategySignal, signalSubscriberBuffer)
+	b.subs[ch] = struct{}{}
+	return b.since(afterSeq), ch
+}
+
+func (b *SignalBroadcaster) unsubscribe(ch chan StrategySignal) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// NewSignalStreamHandler serves the broadcaster's signals to executors
+// holding the token (Bearer or X-Signal-Token header):
+//
+//	GET /strategy-signals?after_seq=N                       → SignalPage (JSON poll)
+//	GET /strategy-signals?after_seq=N  (WebSocket upgrade)  → backlog, then live signals
+//
+// A WebSocket client first gets a SignalPage of the backlog, then one
+// StrategySignal per message. A client that falls behind is disconnected and
+// resumes from the last seq it processed. Like the signal webhook, serve it
+// on its own listener (behind TLS), never on the admin one.
+func NewSignalStreamHandler(b *SignalBroadcaster, token string) http.Handler {
+	var upgrader websocket.Upgrader
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !signalAuthorized(r, "", token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var afterSeq uint64
+		if v := r.URL.Query().Get("after_seq"); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid after_seq", http.StatusBadRequest)
+				return
+			}
+			afterSeq = n
+		}
+
+		if !websocket.IsWebSocketUpgrade(r) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(b.Since(afterSeq))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return // Upgrade has already replied with an HTTP error
+		}
+		defer conn.Close()
+		backlog, ch := b.subscribe(afterSeq)
+		defer b.unsubscribe(ch)
+
+		// Reader: consumes control frames and notices the client going away
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		write := func(v any) bool {
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(v); err != nil {
+				slog.Debug("SIGNAL_STREAM_CLIENT_GONE", slog.String("remote", r.RemoteAddr), slog.Any("error", err))
+				return false
+			}
+			return true
+		}
+		if !write(backlog) {
+			return
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig, ok := <-ch:
+				if !ok {
+					slog.Warn("SIGNAL_STREAM_CLIENT_LAGGING", slog.String("remote", r.RemoteAddr))
+					_ = conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "lagging: resume with after_seq"),
+						time.Now().Add(streamWriteTimeout))
+					return
+				}
+				if !write(sig) {
+					return
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestSignalStreamHandler_Poll(t *testing.T) {
+	b := NewSignalBroadcaster(2)
+	h := NewSignalStreamHandler(b, "secret")
+	b.Publish(domain.Order{Symbol: "BTC", Side: domain.SideBuy}, 5, 1)
+	b.Publish(domain.Order{Symbol: "ETH", Side: domain.SideBuy}, 5, 1)
+	b.Publish(domain.Order{Symbol: "BTC", Side: domain.SideSell}, 9, 2) // Pushes the first out
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SignalStreamPath, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, SignalStreamPath+"?after_seq=0", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var page SignalPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if !page.Gap || len(page.Signals) != 2 || page.Signals[0].Index != 1 || page.Signals[1].Seq != 9 || page.Signals[1].Index != 0 {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	if page = b.Since(5); page.Gap || len(page.Signals) != 1 || page.Signals[0].Order.Side != domain.SideSell {
+		t.Errorf("unexpected page after seq 5: %+v", page)
+	}
+}
+
+func TestSignalStreamHandler_WebSocket(t *testing.T) {
+	b := NewSignalBroadcaster(0)
+	b.Publish(domain.Order{Symbol: "BTC", Side: domain.SideBuy}, 3, 1)
+
+	server := httptest.NewServer(NewSignalStreamHandler(b, "secret"))
+	defer server.Close()
+	header := http.Header{"X-Signal-Token": []string{"secret"}}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+SignalStreamPath, header)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var backlog SignalPage
+	if err := conn.ReadJSON(&backlog); err != nil || len(backlog.Signals) != 1 || backlog.Signals[0].Seq != 3 {
+		t.Fatalf("unexpected backlog %+v (%v)", backlog, err)
+	}
+
+	b.Publish(domain.Order{Symbol: "ETH", Side: domain.SideSell, QtySats: 7}, 4, 2)
+	var sig StrategySignal
+	if err := conn.ReadJSON(&sig); err != nil {
+		t.Fatal(err)
+	}
+	if sig.Seq != 4 || sig.Order.Symbol != "ETH" || sig.Order.QtySats != 7 {
+		t.Errorf("unexpected signal %+v", sig)
+	}
+
+	// A client that stops reading is cut off instead of blocking the publisher
+	_, ch := b.subscribe(4)
+	for i := range signalSubscriberBuffer + 1 {
+		b.Publish(domain.Order{Symbol: "BTC"}, uint64(10+i), 3)
+	}
+	n := 0
+	for range ch {
+		n++
+	}
+	if n != signalSubscriberBuffer {
+		t.Errorf("expected the lagging subscriber closed after %d signals, got %d", signalSubscriberBuffer, n)
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Step is one node of the startup graph.
+type Step struct {
+	Name  string
+	After []string // Steps that must succeed before this one starts
+	Run   func(ctx context.Context) error
+}
+
+type stepState int
+
+const (
+	stepPending stepState = iota
+	stepRunning
+	stepDone
+	stepFailed
+	stepSkipped
+)
+
+type stepResult struct {
+	index int
+	err   error
+	took  time.Duration
+}
+
+// RunSteps runs steps in dependency order: each starts as soon as every step
+// in its After list succeeded, so independent steps run in parallel. A failed
+// step skips everything that depends on it while unrelated steps still run.
+// The timing of every step is logged; the error lists each failed and skipped
+// step by name. Duplicate names, unknown dependencies and cycles are rejected
+// before any step runs.
+func RunSteps(ctx context.Context, steps []Step) error {
+	index := make(map[string]int, len(steps))
+	for i, st := range steps {
+		if _, dup := index[st.Name]; dup {
+			return fmt.Errorf("duplicate startup step %q", st.Name)
+		}
+		index[st.Name] = i
+	}
+	for _, st := range steps {
+		for _, dep := range st.After {
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("startup step %q depends on unknown step %q", st.Name, dep)
+			}
+		}
+	}
+	if err := checkAcyclic(steps, index); err != nil {
+		return err
+	}
+
+	state := make([]stepState, len(steps))
+	results := make(chan stepResult, len(steps))
+	var errs []error
+	running := 0
+
+	for {
+		// Start (or skip) every pending step whose dependencies are settled
+		for progress := true; progress; {
+			progress = false
+			for i, st := range steps {
+				if state[i] != stepPending {
+					continue
+				}
+				ready, blocked := true, ""
+				for _, dep := range st.After {
+					switch state[index[dep]] {
+					case stepFailed, stepSkipped:
+						blocked = dep
+					case stepDone:
+					default:
+						ready = false
+					}
+				}
+				switch {
+				case blocked != "":
+					state[i] = stepSkipped
+					progress = true
+					slog.Warn("⏭️ Startup step skipped", "step", st.Name, "blocked_by", blocked)
+					errs = append(errs, fmt.Errorf("%s: skipped, %s did not complete", st.Name, blocked))
+				case ready && ctx.Err() != nil:
+					state[i] = stepFailed
+					progress = true
+					errs = append(errs, fmt.Errorf("%s: %w", st.Name, ctx.Err()))
+				case ready:
+					state[i] = stepRunning
+					running++
+					go func(i int, run func(context.Context) error) {
+						start := time.Now()
+						err := run(ctx)
+						results <- stepResult{index: i, err: err, took: time.Since(start)}
+					}(i, st.Run)
+				}
+			}
+		}
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+		name := steps[r.index].Name
+		if r.err != nil {
+			state[r.index] = stepFailed
+			slog.Error("❌ Startup step failed", "step", name, "took", r.took, "error", r.err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, r.err))
+			continue
+		}
+		state[r.index] = stepDone
+		slog.Info("⏱️ Startup step done", "step", name, "took", r.took)
+	}
+	return errors.Join(errs...)
+}
+
+// checkAcyclic rejects a graph with a cycle before any step has run.
+func checkAcyclic(steps []Step, index map[string]int) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	mark := make([]int, len(steps))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch mark[i] {
+		case visiting:
+			return fmt.Errorf("startup step %q is part of a dependency cycle", steps[i].Name)
+		case visited:
+			return nil
+		}
+		mark[i] = visiting
+		for _, dep := range steps[i].After {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		mark[i] = visited
+		return nil
+	}
+	for i := range steps {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
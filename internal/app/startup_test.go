@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunSteps_OrderAndParallelism(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	// a and b can only both finish if they run at the same time
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	meet := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			arrived.Done()
+			both := make(chan struct{})
+			go func() { arrived.Wait(); close(both) }()
+			select {
+			case <-both:
+			case <-time.After(2 * time.Second):
+				return errors.New("independent steps did not run in parallel")
+			}
+			record(name)
+			return nil
+		}
+	}
+
+	err := RunSteps(context.Background(), []Step{
+		{Name: "c", After: []string{"a", "b"}, Run: func(context.Context) error { record("c"); return nil }},
+		{Name: "a", Run: meet("a")},
+		{Name: "b", Run: meet("b")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 3 || order[2] != "c" {
+		t.Errorf("c must run after a and b, got %v", order)
+	}
+}
+
+func TestRunSteps_FailureSkipsDependents(t *testing.T) {
+	boom := errors.New("boom")
+	var ran sync.Map
+	run := func(name string) func(context.Context) error {
+		return func(context.Context) error { ran.Store(name, true); return nil }
+	}
+
+	err := RunSteps(context.Background(), []Step{
+		{Name: "config", Run: run("config")},
+		{Name: "storage", After: []string{"config"}, Run: func(context.Context) error { return boom }},
+		{Name: "engine", After: []string{"storage"}, Run: run("engine")},
+		{Name: "icons", After: []string{"config"}, Run: run("icons")},
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected the step error, got %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "storage: boom") || !strings.Contains(msg, "engine: skipped") {
+		t.Errorf("error must name the failed and the skipped step: %q", msg)
+	}
+	if _, ok := ran.Load("engine"); ok {
+		t.Error("a step after a failed one must not run")
+	}
+	if _, ok := ran.Load("icons"); !ok {
+		t.Error("an unrelated step must still run")
+	}
+}
+
+func TestRunSteps_InvalidGraph(t *testing.T) {
+	noop := func(context.Context) error { t.Error("no step may run in an invalid graph"); return nil }
+	graphs := map[string][]Step{
+		"unknown":   {{Name: "a", After: []string{"x"}, Run: noop}},
+		"duplicate": {{Name: "a", Run: noop}, {Name: "a", Run: noop}},
+		"cycle": {
+			{Name: "root", Run: noop},
+			{Name: "a", After: []string{"b"}, Run: noop},
+			{Name: "b", After: []string{"a"}, Run: noop},
+		},
+	}
+	for name, steps := range graphs {
+		if err := RunSteps(context.Background(), steps); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+	"crypto_go/internal/risk"
+	"crypto_go/internal/strategy"
+	"crypto_go/internal/strategy/script"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// StrategyName is the name the engine strategy is reported under (metrics,
+// control command targets).
+func StrategyName(cfg *infra.Config) string {
+	if len(cfg.Strategies) > 0 {
+		return "strategies"
+	}
+	if cfg.Strategy.FundingHarvest.Enabled {
+		return "funding_harvest"
+	}
+	if len(cfg.Strategy.DCA) > 0 {
+		return "dca"
+	}
+	if t := cfg.Strategy.Watchlist.Template; t != "" {
+		return "watchlist:" + t
+	}
+	return "sma_cross"
+}
+
+// BuildStrategy creates the engine strategy from the strategies list or, if
+// empty, the strategy config section. Without a watchlist template, the
+// funding harvest or DCA plans it falls back to the single example SMA cross.
+// With strategy.bars set the strategy runs on closed candles instead of ticks.
+func BuildStrategy(cfg *infra.Config) (strategy.Strategy, error) {
+	strat, err := buildBaseStrategy(cfg)
+	if err != nil || cfg.Strategy.Bars.Exchange == "" {
+		return strat, err
+	}
+	return strategy.NewBarStrategy(strat, cfg.Strategy.Bars.Exchange, cfg.Engine.Candles.Interval), nil
+}
+
+func buildBaseStrategy(cfg *infra.Config) (strategy.Strategy, error) {
+	if len(cfg.Strategies) > 0 {
+		return buildStrategies(cfg)
+	}
+	if cfg.Strategy.FundingHarvest.Enabled {
+		return buildFundingHarvest(cfg)
+	}
+	if len(cfg.Strategy.DCA) > 0 {
+		return buildDCA(cfg)
+	}
+	wc := cfg.Strategy.Watchlist
+	if wc.Template == "" {
+		// Example Strategy: SMA Cross (3, 5) for BTC-USDT
+		return strategy.NewSMACrossStrategy("BTC-USDT", 3, 5), nil
+	}
+
+	var tmpl strategy.Template
+	switch wc.Template {
+	case "sma_cross":
+		if wc.ShortPeriod <= 0 || wc.ShortPeriod >= wc.LongPeriod {
+			return nil, fmt.Errorf("strategy.watchlist: sma_cross needs 0 < short_period < long_period (got %d/%d)", wc.ShortPeriod, wc.LongPeriod)
+		}
+		tmpl = strategy.SMACrossTemplate(wc.ShortPeriod, wc.LongPeriod)
+	case "ema_cross":
+		if wc.ShortPeriod <= 0 || wc.ShortPeriod >= wc.LongPeriod {
+			return nil, fmt.Errorf("strategy.watchlist: ema_cross needs 0 < short_period < long_period (got %d/%d)", wc.ShortPeriod, wc.LongPeriod)
+		}
+		tmpl = strategy.EMACrossTemplate(wc.ShortPeriod, wc.LongPeriod)
+	case "rsi":
+		if wc.Period <= 0 || wc.Lower <= 0 || wc.Lower >= wc.Upper || wc.Upper >= 100 {
+			return nil, fmt.Errorf("strategy.watchlist: rsi needs period > 0 and 0 < lower < upper < 100 (got %d, %d/%d)", wc.Period, wc.Lower, wc.Upper)
+		}
+		tmpl = strategy.RSITemplate(wc.Period, wc.Lower, wc.Upper)
+	case "macd":
+		if wc.ShortPeriod <= 0 || wc.ShortPeriod >= wc.LongPeriod || wc.SignalPeriod <= 0 {
+			return nil, fmt.Errorf("strategy.watchlist: macd needs 0 < short_period < long_period and signal_period > 0 (got %d/%d/%d)", wc.ShortPeriod, wc.LongPeriod, wc.SignalPeriod)
+		}
+		tmpl = strategy.MACDTemplate(wc.ShortPeriod, wc.LongPeriod, wc.SignalPeriod)
+	case "bollinger":
+		if wc.Period < 2 || wc.StdDevBps <= 0 {
+			return nil, fmt.Errorf("strategy.watchlist: bollinger needs period >= 2 and std_dev_bps > 0 (got %d, %d)", wc.Period, wc.StdDevBps)
+		}
+		tmpl = strategy.BollingerTemplate(wc.Period, wc.StdDevBps)
+	default:
+		return nil, fmt.Errorf("strategy.watchlist: unknown template %q", wc.Template)
+	}
+
+	symbols := wc.Symbols
+	if len(symbols) == 0 {
+		symbols = cfg.API.Upbit.Symbols
+	}
+	if wc.MaxNotional < 0 {
+		return nil, fmt.Errorf("strategy.watchlist: max_notional must not be negative")
+	}
+
+	return strategy.NewWatchlistStrategy(tmpl, symbols, strategy.NewRiskBudget(wc.MaxNotional)), nil
+}
+
+// buildStrategies registers one strategy per strategies entry, named
+// <type>_<symbol> (scripts: <file name>_<symbol>) unless set, after loading
+// the strategy plugins.
+func buildStrategies(cfg *infra.Config) (strategy.Strategy, error) {
+	switch {
+	case cfg.Strategy.Watchlist.Template != "":
+		return nil, fmt.Errorf("strategies cannot run with strategy.watchlist.template %q", cfg.Strategy.Watchlist.Template)
+	case cfg.Strategy.FundingHarvest.Enabled:
+		return nil, fmt.Errorf("strategies cannot run with strategy.funding_harvest")
+	case len(cfg.Strategy.DCA) > 0:
+		return nil, fmt.Errorf("strategies cannot run with strategy.dca")
+	}
+	if dir := cfg.Strategy.Plugins.Dir; dir != "" {
+		if _, err := strategy.LoadPlugins(dir); err != nil {
+			return nil, err
+		}
+	}
+	reg := strategy.NewStrategyRegistry()
+	for i, sc := range cfg.Strategies {
+		var strat strategy.Strategy
+		base := sc.Type
+		if sc.Type == "script" {
+			if sc.Script == "" {
+				return nil, fmt.Errorf("strategies[%d]: type script needs script (file path)", i)
+			}
+			prog, err := script.Load(sc.Script)
+			if err != nil {
+				return nil, fmt.Errorf("strategies[%d]: %w", i, err)
+			}
+			if strat, err = prog.New(sc.Symbol, sc.Params); err != nil {
+				return nil, fmt.Errorf("strategies[%d]: %w", i, err)
+			}
+			base = prog.Name()
+		} else {
+			if sc.Script != "" {
+				return nil, fmt.Errorf("strategies[%d]: script is only used by type script", i)
+			}
+			var err error
+			if strat, err = strategy.New(sc.Type, sc.Symbol, sc.Params); err != nil {
+				return nil, fmt.Errorf("strategies[%d]: %w", i, err)
+			}
+		}
+		name := sc.Name
+		if name == "" {
+			name = strings.ToLower(base + "_" + sc.Symbol)
+		}
+		if strings.Contains(name, ".") {
+			return nil, fmt.Errorf("strategies[%d]: name %q must not contain '.'", i, name)
+		}
+		if err := reg.Register(name, strat, sc.Symbol); err != nil {
+			return nil, fmt.Errorf("strategies[%d]: %w", i, err)
+		}
+	}
+	return reg, nil
+}
+
+// FundingHarvestVenues returns the spot and perp venues of
+// strategy.funding_harvest, defaults applied.
+func FundingHarvestVenues(cfg *infra.Config) (spot, perp string) {
+	fc := cfg.Strategy.FundingHarvest
+	spot, perp = fc.SpotExchange, fc.PerpExchange
+	if spot == "" {
+		spot = "BITGET_SPOT"
+	}
+	if perp == "" {
+		perp = "BITGET_FUTURES"
+	}
+	return spot, perp
+}
+
+func buildFundingHarvest(cfg *infra.Config) (strategy.Strategy, error) {
+	fc := cfg.Strategy.FundingHarvest
+	spot, perp := FundingHarvestVenues(cfg)
+	switch {
+	case cfg.Strategy.Watchlist.Template != "":
+		return nil, fmt.Errorf("strategy.funding_harvest cannot run with strategy.watchlist.template %q", cfg.Strategy.Watchlist.Template)
+	case len(cfg.Strategy.DCA) > 0:
+		return nil, fmt.Errorf("strategy.funding_harvest cannot run with strategy.dca")
+	case cfg.Strategy.Limits.CooldownSec > 0 || cfg.Strategy.Limits.MaxEntriesPerDay > 0:
+		return nil, fmt.Errorf("strategy.funding_harvest: strategy.limits would drop the spot leg of a hedge; set cooldown_sec and max_entries_per_day to 0")
+	case spot == perp:
+		return nil, fmt.Errorf("strategy.funding_harvest: spot_exchange and perp_exchange must differ (got %s)", spot)
+	case fc.ExitRate >= fc.EntryRate || fc.Notional <= 0:
+		return nil, fmt.Errorf("strategy.funding_harvest needs exit_rate < entry_rate and notional > 0 (got %d/%d, %d)", fc.ExitRate, fc.EntryRate, fc.Notional)
+	}
+
+	symbols := fc.Symbols
+	if len(symbols) == 0 {
+		symbols = slices.Sorted(maps.Keys(cfg.API.Bitget.Symbols))
+	}
+	var limits risk.Limits
+	if r := cfg.Engine.Risk; r.Enabled {
+		limits.MaxOrderNotionalMicros, limits.MaxPositionSats = r.MaxOrderNotional, r.MaxPositionSats
+	}
+	return strategy.NewFundingHarvestStrategy(strategy.FundingHarvestConfig{
+		Symbols:         symbols,
+		SpotExchange:    spot,
+		PerpExchange:    perp,
+		EntryRateMicros: fc.EntryRate,
+		ExitRateMicros:  fc.ExitRate,
+		NotionalMicros:  fc.Notional,
+		Limits:          limits,
+	}), nil
+}
+
+// DCAVenues returns the venues strategy.dca buys on, in plan order.
+func DCAVenues(cfg *infra.Config) []string {
+	var venues []string
+	for _, p := range cfg.Strategy.DCA {
+		if !slices.Contains(venues, p.Exchange) {
+			venues = append(venues, p.Exchange)
+		}
+	}
+	return venues
+}
+
+func buildDCA(cfg *infra.Config) (strategy.Strategy, error) {
+	if t := cfg.Strategy.Watchlist.Template; t != "" {
+		return nil, fmt.Errorf("strategy.dca cannot run with strategy.watchlist.template %q", t)
+	}
+	plans := make([]strategy.DCAPlan, 0, len(cfg.Strategy.DCA))
+	for i, p := range cfg.Strategy.DCA {
+		class, ok := domain.VenueAssetClass(p.Exchange)
+		switch {
+		case p.Symbol == "":
+			return nil, fmt.Errorf("strategy.dca[%d]: symbol is required", i)
+		case !ok || class.Market != domain.MarketSpot:
+			return nil, fmt.Errorf("strategy.dca[%d]: %q is not a spot exchange", i, p.Exchange)
+		case !slices.ContainsFunc(cfg.Engine.Timers, func(t infra.TimerConfig) bool { return t.Name == p.Timer }):
+			return nil, fmt.Errorf("strategy.dca[%d]: timer %q is not in engine.timers", i, p.Timer)
+		case p.Amount <= 0 || p.Budget < 0:
+			return nil, fmt.Errorf("strategy.dca[%d]: needs amount > 0 and budget >= 0 (got %d, %d)", i, p.Amount, p.Budget)
+		}
+		plans = append(plans, strategy.DCAPlan{
+			Symbol:       p.Symbol,
+			Exchange:     p.Exchange,
+			Timer:        p.Timer,
+			AmountMicros: p.Amount,
+			BudgetMicros: p.Budget,
+		})
+	}
+	return strategy.NewDCAStrategy(plans), nil
+}
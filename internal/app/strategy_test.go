@@ -0,0 +1,189 @@
+package app
+
+import (
+	"crypto_go/internal/infra"
+	"crypto_go/internal/strategy"
+	"testing"
+)
+
+func TestBuildStrategy_WatchlistDefaultsToUpbitSymbols(t *testing.T) {
+	var cfg infra.Config
+	cfg.API.Upbit.Symbols = []string{"BTC", "ETH"}
+	cfg.Strategy.Watchlist.Template = "sma_cross"
+	cfg.Strategy.Watchlist.ShortPeriod = 20
+	cfg.Strategy.Watchlist.LongPeriod = 50
+
+	strat, err := BuildStrategy(&cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w, ok := strat.(*strategy.WatchlistStrategy)
+	if !ok {
+		t.Fatalf("expected watchlist strategy, got %T", strat)
+	}
+	if got := w.Symbols(); len(got) != 2 {
+		t.Errorf("expected 2 instances, got %v", got)
+	}
+}
+
+func TestBuildStrategy_RejectsInvalid(t *testing.T) {
+	var cfg infra.Config
+	cfg.Strategy.Watchlist.Template = "sma_cross"
+	cfg.Strategy.Watchlist.ShortPeriod = 50
+	cfg.Strategy.Watchlist.LongPeriod = 20
+	if _, err := BuildStrategy(&cfg); err == nil {
+		t.Error("expected error for short >= long")
+	}
+
+	cfg.Strategy.Watchlist.Template = "rsi"
+	cfg.Strategy.Watchlist.Period, cfg.Strategy.Watchlist.Lower, cfg.Strategy.Watchlist.Upper = 14, 70, 30
+	if _, err := BuildStrategy(&cfg); err == nil {
+		t.Error("expected error for rsi lower >= upper")
+	}
+
+	cfg.Strategy.Watchlist.Template = "moon"
+	if _, err := BuildStrategy(&cfg); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestBuildStrategy_IndicatorTemplates(t *testing.T) {
+	var cfg infra.Config
+	cfg.API.Upbit.Symbols = []string{"BTC"}
+	wc := &cfg.Strategy.Watchlist
+	wc.ShortPeriod, wc.LongPeriod, wc.SignalPeriod = 12, 26, 9
+	wc.Period, wc.Lower, wc.Upper, wc.StdDevBps = 14, 30, 70, 20_000
+	for _, tmpl := range []string{"ema_cross", "rsi", "macd", "bollinger"} {
+		wc.Template = tmpl
+		if _, err := BuildStrategy(&cfg); err != nil {
+			t.Errorf("%s: %v", tmpl, err)
+		}
+	}
+}
+
+func TestBuildStrategy_FundingHarvest(t *testing.T) {
+	var cfg infra.Config
+	cfg.API.Bitget.Symbols = map[string]string{"ETH": "ETHUSDT", "BTC": "BTCUSDT"}
+	fc := &cfg.Strategy.FundingHarvest
+	fc.Enabled, fc.EntryRate, fc.Notional = true, 300, 1_000_000_000
+
+	strat, err := BuildStrategy(&cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := strat.(*strategy.FundingHarvestStrategy); !ok || StrategyName(&cfg) != "funding_harvest" {
+		t.Fatalf("expected the funding harvest, got %T (%s)", strat, StrategyName(&cfg))
+	}
+
+	cfg.Strategy.Limits.CooldownSec = 300
+	if _, err := BuildStrategy(&cfg); err == nil {
+		t.Error("expected error for a trade cooldown")
+	}
+	cfg.Strategy.Limits.CooldownSec = 0
+	fc.SpotExchange = "BITGET_FUTURES"
+	if _, err := BuildStrategy(&cfg); err == nil {
+		t.Error("expected error for the same spot and perp venue")
+	}
+	fc.SpotExchange, fc.ExitRate = "", 300
+	if _, err := BuildStrategy(&cfg); err == nil {
+		t.Error("expected error for exit_rate >= entry_rate")
+	}
+}
+
+func TestBuildStrategy_DCA(t *testing.T) {
+	var cfg infra.Config
+	cfg.Engine.Timers = []infra.TimerConfig{{Name: "1d", IntervalSec: 86400}}
+	cfg.Strategy.DCA = []infra.DCAPlanConfig{
+		{Symbol: "BTC", Exchange: "UPBIT", Timer: "1d", Amount: 100_000_000_000},
+		{Symbol: "ETH", Exchange: "UPBIT", Timer: "1d", Amount: 50_000_000_000},
+	}
+	strat, err := BuildStrategy(&cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := strat.(*strategy.DCAStrategy); !ok || StrategyName(&cfg) != "dca" {
+		t.Fatalf("expected DCA, got %T (%s)", strat, StrategyName(&cfg))
+	}
+	if got := DCAVenues(&cfg); len(got) != 1 || got[0] != "UPBIT" {
+		t.Errorf("unexpected venues %v", got)
+	}
+
+	for _, bad := range []infra.DCAPlanConfig{
+		{Symbol: "BTC", Exchange: "BITGET_FUTURES", Timer: "1d", Amount: 1},
+		{Symbol: "BTC", Exchange: "UPBIT", Timer: "1h", Amount: 1},
+		{Symbol: "BTC", Exchange: "UPBIT", Timer: "1d"},
+	} {
+		cfg.Strategy.DCA = []infra.DCAPlanConfig{bad}
+		if _, err := BuildStrategy(&cfg); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+func TestBuildStrategy_Strategies(t *testing.T) {
+	var cfg infra.Config
+	cfg.Strategies = []infra.StrategyConfig{
+		{Name: "btc_rsi", Type: "rsi", Symbol: "BTC", Params: map[string]int64{"upper": 80}},
+		{Type: "sma_cross", Symbol: "ETH", Params: map[string]int64{"short_period": 3, "long_period": 5}},
+	}
+	strat, err := BuildStrategy(&cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg, ok := strat.(*strategy.StrategyRegistry)
+	if !ok || StrategyName(&cfg) != "strategies" {
+		t.Fatalf("expected registry, got %T (%s)", strat, StrategyName(&cfg))
+	}
+	if got := reg.Names(); len(got) != 2 || got[0] != "btc_rsi" || got[1] != "sma_cross_eth" {
+		t.Errorf("unexpected names %v", got)
+	}
+	if err := reg.SetParam("sma_cross_eth.long_period", 8); err != nil {
+		t.Errorf("set param: %v", err)
+	}
+
+	for _, bad := range [][]infra.StrategyConfig{
+		{{Type: "rsi", Symbol: "BTC"}, {Type: "rsi", Symbol: "btc"}},
+		{{Type: "rsi", Symbol: "BTC", Params: map[string]int64{"lower": 90}}},
+		{{Name: "a.b", Type: "rsi", Symbol: "BTC"}},
+		{{Type: "moon", Symbol: "BTC"}},
+	} {
+		cfg.Strategies = bad
+		if _, err := BuildStrategy(&cfg); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+
+	cfg.Strategies = []infra.StrategyConfig{{Type: "rsi", Symbol: "BTC"}}
+	cfg.Strategy.Watchlist.Template = "rsi"
+	if _, err := BuildStrategy(&cfg); err == nil {
+		t.Error("expected error with a watchlist template")
+	}
+}
+
+func TestBuildStrategy_Script(t *testing.T) {
+	var cfg infra.Config
+	cfg.Strategies = []infra.StrategyConfig{{Type: "script", Symbol: "BTC", Script: "../../examples/scripts/rsi_reversion.strat", Params: map[string]int64{"lower": 25}}}
+	strat, err := BuildStrategy(&cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := strat.(*strategy.StrategyRegistry)
+	if got := reg.Names(); len(got) != 1 || got[0] != "rsi_reversion_btc" {
+		t.Errorf("unexpected names %v", got)
+	}
+	if err := reg.SetParam("rsi_reversion_btc.upper", 80); err != nil {
+		t.Errorf("set param: %v", err)
+	}
+
+	for _, bad := range []infra.StrategyConfig{
+		{Type: "script", Symbol: "BTC"},
+		{Type: "script", Symbol: "BTC", Script: "missing.strat"},
+		{Type: "script", Symbol: "BTC", Script: "../../examples/scripts/rsi_reversion.strat", Params: map[string]int64{"x": 1}},
+		{Type: "rsi", Symbol: "BTC", Script: "../../examples/scripts/rsi_reversion.strat"},
+	} {
+		cfg.Strategies = []infra.StrategyConfig{bad}
+		if _, err := BuildStrategy(&cfg); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
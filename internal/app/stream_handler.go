@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/pkg/quant"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// StreamPath is the local WebSocket path for pushed market state.
+const StreamPath = "/stream"
+
+// Stream message types.
+const (
+	StreamKeyframe = "keyframe" // Full state of every market; replaces the client's copy
+	StreamDelta    = "delta"    // Changed fields only, keyed by symbol
+)
+
+const streamWriteTimeout = 5 * time.Second
+
+// StreamMessage is one pushed frame. In a keyframe every market carries all
+// fields; in a delta only the markets and fields that changed since the previous
+// frame are present.
+type StreamMessage struct {
+	Type    string                 `json:"type"`
+	Seq     uint64                 `json:"seq"` // Sequencer point the frame reflects
+	Markets map[string]MarketDelta `json:"markets"`
+}
+
+// MarketDelta is the wire form of one market in a StreamMessage (nil = unchanged).
+type MarketDelta struct {
+	PriceMicros      *quant.PriceMicros `json:"price,omitempty,string"`
+	TotalQtySats     *quant.QtySats     `json:"qty,omitempty,string"`
+	LastUpdateUnixM  *quant.TimeStamp   `json:"last_update,omitempty,string"`
+	NextFundingUnixM *quant.TimeStamp   `json:"next_funding,omitempty,string"` // Perpetual funding epoch (time to it: next_funding - last_update)
+}
+
+// NewStreamHandler pushes market state over a WebSocket for dashboards:
+//
+//	GET /stream  (WebSocket upgrade)
+//
+// The client gets a keyframe on connect, then every interval a delta with only
+// the fields that changed, and a fresh keyframe every keyframe so a client that
+// mishandled a delta resynchronises. Frames are built from GetMarketsSnapshot
+// off the hotpath; nothing is sent while the sequencer has not moved.
+func NewStreamHandler(src MarketsSource, interval, keyframe time.Duration) http.Handler {
+	keyframeEvery := max(int(keyframe/interval), 1)
+	var upgrader websocket.Upgrader
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return // Upgrade has already replied with an HTTP error
+		}
+		defer conn.Close()
+
+		// Reader: consumes control frames and notices the client going away
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var prev engine.MarketsSnapshot
+		sinceKeyframe := 0
+		for {
+			snap := src.GetMarketsSnapshot()
+			var msg StreamMessage
+			switch {
+			case prev.Markets == nil || sinceKeyframe >= keyframeEvery:
+				msg = StreamMessage{Type: StreamKeyframe, Seq: snap.Seq, Markets: fullMarkets(snap.Markets)}
+				sinceKeyframe = 0
+			case snap.Seq != prev.Seq:
+				msg = StreamMessage{Type: StreamDelta, Seq: snap.Seq, Markets: diffMarkets(prev.Markets, snap.Markets)}
+			}
+			sinceKeyframe++
+
+			if msg.Type == StreamKeyframe || len(msg.Markets) > 0 {
+				_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+				if err := conn.WriteJSON(msg); err != nil {
+					slog.Debug("STREAM_CLIENT_GONE", slog.String("remote", r.RemoteAddr), slog.Any("error", err))
+					return
+				}
+			}
+			prev = snap
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// fullMarkets converts every market to its keyframe form.
+func fullMarkets(markets map[string]domain.MarketState) map[string]MarketDelta {
+	out := make(map[string]MarketDelta, len(markets))
+	for symbol, m := range markets {
+		d := MarketDelta{PriceMicros: &m.PriceMicros, TotalQtySats: &m.TotalQtySats, LastUpdateUnixM: &m.LastUpdateUnixM}
+		if m.NextFundingUnixM != 0 {
+			d.NextFundingUnixM = &m.NextFundingUnixM
+		}
+		out[symbol] = d
+	}
+	return out
+}
+
+// diffMarkets returns the fields of next that differ from prev. New symbols
+// are sent in full.
+func diffMarkets(prev, next map[string]domain.MarketState) map[string]MarketDelta {
+	out := make(map[string]MarketDelta)
+	for symbol, m := range next {
+		old, ok := prev[symbol]
+		var d MarketDelta
+		if !ok || m.PriceMicros != old.PriceMicros {
+			d.PriceMicros = &m.PriceMicros
+		}
+		if !ok || m.TotalQtySats != old.TotalQtySats {
+			d.TotalQtySats = &m.TotalQtySats
+		}
+		if !ok || m.LastUpdateUnixM != old.LastUpdateUnixM {
+			d.LastUpdateUnixM = &m.LastUpdateUnixM
+		}
+		if m.NextFundingUnixM != old.NextFundingUnixM { // old is zero for a new symbol
+			d.NextFundingUnixM = &m.NextFundingUnixM
+		}
+		if d != (MarketDelta{}) {
+			out[symbol] = d
+		}
+	}
+	return out
+}
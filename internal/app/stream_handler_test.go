@@ -0,0 +1,90 @@
+package app
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type fakeMarkets struct {
+	mu   sync.Mutex
+	snap engine.MarketsSnapshot
+}
+
+func (f *fakeMarkets) GetMarketsSnapshot() engine.MarketsSnapshot {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cp := engine.MarketsSnapshot{Seq: f.snap.Seq, Markets: make(map[string]domain.MarketState)}
+	for k, v := range f.snap.Markets {
+		cp.Markets[k] = v
+	}
+	return cp
+}
+
+func (f *fakeMarkets) set(seq uint64, states ...domain.MarketState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.snap.Seq = seq
+	for _, s := range states {
+		f.snap.Markets[s.Symbol] = s
+	}
+}
+
+func TestStreamHandler_KeyframeThenDeltas(t *testing.T) {
+	src := &fakeMarkets{snap: engine.MarketsSnapshot{Markets: make(map[string]domain.MarketState)}}
+	src.set(1,
+		domain.MarketState{Symbol: "BTC", PriceMicros: 100, TotalQtySats: 5, LastUpdateUnixM: 1},
+		domain.MarketState{Symbol: "ETH", PriceMicros: 10, TotalQtySats: 7, LastUpdateUnixM: 1})
+
+	server := httptest.NewServer(NewStreamHandler(src, 10*time.Millisecond, time.Hour))
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+StreamPath, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg StreamMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read keyframe: %v", err)
+	}
+	if msg.Type != StreamKeyframe || msg.Seq != 1 || len(msg.Markets) != 2 || *msg.Markets["ETH"].TotalQtySats != 7 {
+		t.Fatalf("unexpected keyframe %+v", msg)
+	}
+
+	// Only BTC's price and time change: the delta must carry just those fields
+	src.set(2, domain.MarketState{Symbol: "BTC", PriceMicros: 101, TotalQtySats: 5, LastUpdateUnixM: 2})
+	msg = StreamMessage{}
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read delta: %v", err)
+	}
+	btc, ok := msg.Markets["BTC"]
+	if msg.Type != StreamDelta || msg.Seq != 2 || len(msg.Markets) != 1 || !ok {
+		t.Fatalf("unexpected delta %+v", msg)
+	}
+	if btc.PriceMicros == nil || *btc.PriceMicros != 101 || btc.TotalQtySats != nil || btc.LastUpdateUnixM == nil {
+		t.Errorf("delta must carry only changed fields: %+v", btc)
+	}
+}
+
+func TestDiffMarkets(t *testing.T) {
+	prev := map[string]domain.MarketState{"BTC": {Symbol: "BTC", PriceMicros: 100, TotalQtySats: 5}}
+	next := map[string]domain.MarketState{
+		"BTC": {Symbol: "BTC", PriceMicros: 100, TotalQtySats: 5},
+		"XRP": {Symbol: "XRP", PriceMicros: 1},
+	}
+	d := diffMarkets(prev, next)
+	if _, ok := d["BTC"]; ok {
+		t.Error("unchanged market must be omitted")
+	}
+	if x := d["XRP"]; x.PriceMicros == nil || x.TotalQtySats == nil || x.LastUpdateUnixM == nil {
+		t.Errorf("new market must be sent in full: %+v", x)
+	}
+}
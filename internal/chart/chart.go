@@ -0,0 +1,133 @@
+// Package chart renders small price charts from local history: unicode sparklines
+// and mini candle charts for text surfaces (terminal rows, alert messages), and an
+// optional PNG renderer where images can be attached.
+package chart
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"strings"
+	"time"
+)
+
+// sparkLevels are the eight block heights used by Sparkline.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Point is one price observation.
+type Point struct {
+	Ts          quant.TimeStamp
+	PriceMicros quant.PriceMicros
+}
+
+// Candle is one OHLC bucket.
+type Candle struct {
+	Start quant.TimeStamp
+	Open  quant.PriceMicros
+	High  quant.PriceMicros
+	Low   quant.PriceMicros
+	Close quant.PriceMicros
+}
+
+// Sparkline renders the last width values (all if width <= 0) as block characters
+// scaled between their min and max. A flat series renders at mid height.
+func Sparkline(values []int64, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := bounds(values)
+	var b strings.Builder
+	for _, v := range values {
+		b.WriteRune(sparkLevels[scale(v, lo, hi, len(sparkLevels))])
+	}
+	return b.String()
+}
+
+// PriceSparkline is Sparkline over the prices of points.
+func PriceSparkline(points []Point, width int) string {
+	values := make([]int64, len(points))
+	for i, p := range points {
+		values[i] = int64(p.PriceMicros)
+	}
+	return Sparkline(values, width)
+}
+
+// BuildCandles buckets points (in time order) into candles aligned to the session
+// day, so a candle never straddles a venue day boundary. interval must divide 24h.
+func BuildCandles(points []Point, session domain.Session, interval time.Duration) []Candle {
+	var out []Candle
+	for _, p := range points {
+		start := session.BucketStart(p.Ts, interval)
+		if n := len(out); n > 0 && out[n-1].Start == start {
+			c := &out[n-1]
+			c.High = max(c.High, p.PriceMicros)
+			c.Low = min(c.Low, p.PriceMicros)
+			c.Close = p.PriceMicros
+			continue
+		}
+		out = append(out, Candle{Start: start, Open: p.PriceMicros, High: p.PriceMicros, Low: p.PriceMicros, Close: p.PriceMicros})
+	}
+	return out
+}
+
+// Candles renders candles as height text rows (top first), one column per candle:
+// '█' rising body, '▒' falling body, '│' wick. Use a monospace font.
+func Candles(candles []Candle, height int) []string {
+	if len(candles) == 0 || height <= 0 {
+		return nil
+	}
+
+	values := make([]int64, 0, len(candles)*2)
+	for _, c := range candles {
+		values = append(values, int64(c.High), int64(c.Low))
+	}
+	lo, hi := bounds(values)
+
+	grid := make([][]rune, height)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", len(candles)))
+	}
+	for col, c := range candles {
+		body := '█'
+		if c.Close < c.Open {
+			body = '▒'
+		}
+		top, bottom := scale(int64(c.High), lo, hi, height), scale(int64(c.Low), lo, hi, height)
+		bodyTop := scale(int64(max(c.Open, c.Close)), lo, hi, height)
+		bodyBottom := scale(int64(min(c.Open, c.Close)), lo, hi, height)
+		for level := bottom; level <= top; level++ {
+			ch := '│'
+			if level >= bodyBottom && level <= bodyTop {
+				ch = body
+			}
+			grid[height-1-level][col] = ch
+		}
+	}
+
+	rows := make([]string, height)
+	for i, r := range grid {
+		rows[i] = string(r)
+	}
+	return rows
+}
+
+func bounds(values []int64) (lo, hi int64) {
+	lo, hi = values[0], values[0]
+	for _, v := range values[1:] {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	return lo, hi
+}
+
+// scale maps v in [lo, hi] to a level in [0, levels-1]; a flat range maps to the middle.
+func scale(v, lo, hi int64, levels int) int {
+	if hi == lo {
+		return (levels - 1) / 2
+	}
+	// Float is fine here: this is display only, never money math
+	return int(float64(v-lo) / float64(hi-lo) * float64(levels-1))
+}
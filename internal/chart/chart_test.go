@@ -0,0 +1,104 @@
+package chart
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int64{1, 2, 3, 4, 5, 6, 7, 8}, 0); got != "▁▂▃▄▅▆▇█" {
+		t.Errorf("ramp: got %q", got)
+	}
+	if got := Sparkline([]int64{9, 9, 9}, 0); got != "▄▄▄" {
+		t.Errorf("flat series should sit mid height: got %q", got)
+	}
+	if got := Sparkline([]int64{100, 0, 50, 100}, 2); got != "▁█" {
+		t.Errorf("width keeps the newest values: got %q", got)
+	}
+	if Sparkline(nil, 10) != "" {
+		t.Error("empty input should render empty")
+	}
+}
+
+func TestBuildCandlesAndRender(t *testing.T) {
+	base := quant.TimeStamp(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC).UnixMicro())
+	minute := quant.TimeStamp(time.Minute.Microseconds())
+	points := []Point{
+		{base, 100}, {base + 10*minute, 130}, {base + 20*minute, 120}, // hour 0: up
+		{base + 60*minute, 120}, {base + 70*minute, 90}, // hour 1: down
+	}
+
+	candles := BuildCandles(points, domain.SessionBitget, time.Hour)
+	if len(candles) != 2 {
+		t.Fatalf("expected 2 candles, got %+v", candles)
+	}
+	if c := candles[0]; c.Open != 100 || c.High != 130 || c.Low != 100 || c.Close != 120 {
+		t.Errorf("unexpected first candle %+v", c)
+	}
+
+	rows := Candles(candles, 5)
+	if len(rows) != 5 {
+		t.Fatalf("expected 5 rows, got %d", len(rows))
+	}
+	// Top row: the first candle's high wick; bottom row: the second's low
+	if []rune(rows[0])[0] != '│' || []rune(rows[4])[1] == ' ' {
+		t.Errorf("unexpected rendering:\n%s\n%s\n%s\n%s\n%s", rows[0], rows[1], rows[2], rows[3], rows[4])
+	}
+	if got := []rune(rows[2])[1]; got != '▒' {
+		t.Errorf("falling body should be '▒', got %q", got)
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	data, err := RenderPNG([]int64{5, 3, 8, 6}, DefaultPNGStyle)
+	if err != nil {
+		t.Fatalf("RenderPNG failed: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != DefaultPNGStyle.Width || b.Dy() != DefaultPNGStyle.Height {
+		t.Errorf("unexpected size %v", b)
+	}
+	if _, err := RenderPNG([]int64{1}, DefaultPNGStyle); err == nil {
+		t.Error("expected error for a single value")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/chart.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	evs := []*event.MarketUpdateEvent{
+		{BaseEvent: event.BaseEvent{Seq: 1, Ts: 10}, Exchange: "UPBIT", Symbol: "KRW-BTC", PriceMicros: 1},
+		{BaseEvent: event.BaseEvent{Seq: 2, Ts: 20}, Exchange: "UPBIT", Symbol: "KRW-ETH", PriceMicros: 2},
+		{BaseEvent: event.BaseEvent{Seq: 3, Ts: 30}, Exchange: "UPBIT", Symbol: "KRW-BTC", PriceMicros: 3},
+		{BaseEvent: event.BaseEvent{Seq: 4, Ts: 40}, Exchange: "UPBIT", Symbol: "KRW-BTC", PriceMicros: 4},
+	}
+	for _, ev := range evs {
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	points, err := History(ctx, store, "UPBIT", "KRW-BTC", 0, 40)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(points) != 2 || points[0].PriceMicros != 1 || points[1].PriceMicros != 3 {
+		t.Errorf("unexpected history %+v", points)
+	}
+}
@@ -0,0 +1,41 @@
+package chart
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"fmt"
+)
+
+// History loads exchange/symbol prices in [from, to) from the WAL, oldest first.
+func History(ctx context.Context, store *storage.EventStore, exchange, symbol string, from, to quant.TimeStamp) ([]Point, error) {
+	rows, err := store.DB().QueryContext(ctx,
+		"SELECT payload FROM events WHERE type = ? AND ts >= ? AND ts < ? ORDER BY id ASC",
+		event.EvMarketUpdate, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market updates: %w", err)
+	}
+	defer rows.Close()
+
+	var points []Point
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		decoded, err := event.Decode(event.EvMarketUpdate, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal market update: %w", err)
+		}
+		ev := decoded.(*event.MarketUpdateEvent)
+		if ev.Symbol == symbol && (exchange == "" || ev.Exchange == exchange) {
+			points = append(points, Point{Ts: ev.Ts, PriceMicros: ev.PriceMicros})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return points, nil
+}
@@ -0,0 +1,100 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// PNGStyle controls RenderPNG.
+type PNGStyle struct {
+	Width, Height int
+	Background    color.Color
+	Up, Down      color.Color // Line color when the series ends above / below its start
+}
+
+// DefaultPNGStyle is sized for a chat message preview.
+var DefaultPNGStyle = PNGStyle{
+	Width:      320,
+	Height:     96,
+	Background: color.RGBA{0x16, 0x1a, 0x23, 0xff},
+	Up:         color.RGBA{0xe1, 0x5a, 0x5a, 0xff}, // Korean convention: rising = red
+	Down:       color.RGBA{0x4a, 0x8c, 0xe8, 0xff},
+}
+
+// RenderPNG draws values as a line chart and encodes it as PNG, for channels that
+// accept image attachments. Uses only the standard library.
+func RenderPNG(values []int64, style PNGStyle) ([]byte, error) {
+	if len(values) < 2 {
+		return nil, errors.New("chart: need at least two values")
+	}
+	if style.Width < 2 || style.Height < 2 {
+		return nil, errors.New("chart: image too small")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, style.Width, style.Height))
+	for y := 0; y < style.Height; y++ {
+		for x := 0; x < style.Width; x++ {
+			img.Set(x, y, style.Background)
+		}
+	}
+
+	line := style.Up
+	if values[len(values)-1] < values[0] {
+		line = style.Down
+	}
+
+	lo, hi := bounds(values)
+	point := func(i int) (int, int) {
+		x := i * (style.Width - 1) / (len(values) - 1)
+		y := style.Height - 1 - scale(values[i], lo, hi, style.Height)
+		return x, y
+	}
+	x0, y0 := point(0)
+	for i := 1; i < len(values); i++ {
+		x1, y1 := point(i)
+		drawLine(img, x0, y0, x1, y1, line)
+		x0, y0 = x1, y1
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine is Bresenham's line algorithm.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			x0 += sx
+		} else {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
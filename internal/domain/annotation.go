@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Annotation targets.
+const (
+	AnnotationTrade = "trade" // Target = order ID
+	AnnotationDay   = "day"   // Target = journal day (YYYY-MM-DD)
+)
+
+// Limits keep notes journal-sized; they are free text typed by the user.
+const (
+	maxAnnotationNote = 2000
+	maxAnnotationTags = 16
+)
+
+// Annotation is a user note and/or tags attached to a trade or a day
+// (e.g. "news-driven spike"), shown next to fills in journals.
+type Annotation struct {
+	ID        int64    `json:"id"`
+	Kind      string   `json:"kind"`
+	Target    string   `json:"target"`
+	Note      string   `json:"note,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	CreatedAt int64    `json:"created_at"` // Unix micros
+}
+
+// Normalize trims the note and lowercases, trims, dedupes and sorts the tags,
+// then validates the result.
+func (a *Annotation) Normalize() error {
+	a.Kind = strings.ToLower(strings.TrimSpace(a.Kind))
+	a.Target = strings.TrimSpace(a.Target)
+	a.Note = strings.TrimSpace(a.Note)
+
+	seen := make(map[string]bool, len(a.Tags))
+	tags := a.Tags[:0]
+	for _, tag := range a.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.Contains(tag, ",") {
+			return fmt.Errorf("tag %q must not contain a comma", tag)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	a.Tags = tags
+
+	switch a.Kind {
+	case AnnotationTrade:
+		if a.Target == "" {
+			return errors.New("trade annotation needs an order id")
+		}
+	case AnnotationDay:
+		if _, err := time.Parse("2006-01-02", a.Target); err != nil {
+			return fmt.Errorf("day annotation target must be YYYY-MM-DD: %q", a.Target)
+		}
+	default:
+		return fmt.Errorf("unknown annotation kind %q (want %s or %s)", a.Kind, AnnotationTrade, AnnotationDay)
+	}
+
+	if a.Note == "" && len(a.Tags) == 0 {
+		return errors.New("annotation needs a note or at least one tag")
+	}
+	if len(a.Note) > maxAnnotationNote {
+		return fmt.Errorf("note longer than %d bytes", maxAnnotationNote)
+	}
+	if len(a.Tags) > maxAnnotationTags {
+		return fmt.Errorf("more than %d tags", maxAnnotationTags)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotation_Normalize(t *testing.T) {
+	a := Annotation{Kind: " Trade ", Target: "o-1", Note: "  chased the pump ", Tags: []string{"News", "fomo", "news", " "}}
+	if err := a.Normalize(); err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if a.Kind != AnnotationTrade || a.Note != "chased the pump" || !reflect.DeepEqual(a.Tags, []string{"fomo", "news"}) {
+		t.Errorf("unexpected normalized annotation: %+v", a)
+	}
+
+	bad := []Annotation{
+		{Kind: "week", Target: "x", Note: "n"},
+		{Kind: AnnotationTrade, Note: "n"},
+		{Kind: AnnotationDay, Target: "10/03/2026", Note: "n"},
+		{Kind: AnnotationDay, Target: "2026-03-10"},
+		{Kind: AnnotationTrade, Target: "o", Tags: []string{"a,b"}},
+	}
+	for i, b := range bad {
+		if err := b.Normalize(); err == nil {
+			t.Errorf("case %d: expected error for %+v", i, b)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+// Quote currencies of the venues.
+const (
+	QuoteKRW  = "KRW"
+	QuoteUSDT = "USDT"
+)
+
+// AssetClass groups positions for exposure and VaR: the market a venue trades
+// (spot, or perpetuals as MarketFutures) and the currency it quotes in.
+// Amounts of different quote currencies are never added together.
+type AssetClass struct {
+	Market string `json:"market"`
+	Quote  string `json:"quote"`
+}
+
+// AssetClassUnknown is the class of venues missing from the built-in table.
+var AssetClassUnknown = AssetClass{Market: "UNKNOWN", Quote: "UNKNOWN"}
+
+func (c AssetClass) String() string {
+	return c.Market + "/" + c.Quote
+}
+
+var venueClasses = map[string]AssetClass{
+	"UPBIT":          {MarketSpot, QuoteKRW},
+	"BITHUMB":        {MarketSpot, QuoteKRW},
+	"COINONE":        {MarketSpot, QuoteKRW},
+	"BITGET_SPOT":    {MarketSpot, QuoteUSDT},
+	"BITGET_FUTURES": {MarketFutures, QuoteUSDT},
+	"BITGET_S":       {MarketSpot, QuoteUSDT},
+	"BITGET_F":       {MarketFutures, QuoteUSDT},
+	"OKX_SPOT":       {MarketSpot, QuoteUSDT},
+	"OKX_SWAP":       {MarketFutures, QuoteUSDT},
+	"BYBIT_SPOT":     {MarketSpot, QuoteUSDT},
+	"BYBIT_LINEAR":   {MarketFutures, QuoteUSDT},
+}
+
+// VenueAssetClass returns the asset class of a venue (event Exchange name),
+// or AssetClassUnknown and false.
+func VenueAssetClass(venue string) (AssetClass, bool) {
+	c, ok := venueClasses[venue]
+	if !ok {
+		return AssetClassUnknown, false
+	}
+	return c, true
+}
@@ -21,12 +21,14 @@ func (b *Balance) AvailableSats() int64 {
 
 // Credit adds funds to the balance. Panics on overflow.
 func (b *Balance) Credit(amountSats int64, seq uint64) {
+	b.requireNonNegative("CREDIT", amountSats)
 	b.AmountSats = safe.SafeAdd(b.AmountSats, amountSats)
 	b.LastSeq = seq
 }
 
 // Debit removes funds from the balance. Panics if insufficient or overflow.
 func (b *Balance) Debit(amountSats int64, seq uint64) {
+	b.requireNonNegative("DEBIT", amountSats)
 	if amountSats > b.AvailableSats() {
 		panic(fmt.Sprintf("BALANCE_INSUFFICIENT: %s need %d, available %d",
 			b.Symbol, amountSats, b.AvailableSats()))
@@ -37,6 +39,7 @@ func (b *Balance) Debit(amountSats int64, seq uint64) {
 
 // Reserve locks funds for an order.
 func (b *Balance) Reserve(amountSats int64, seq uint64) {
+	b.requireNonNegative("RESERVE", amountSats)
 	if amountSats > b.AvailableSats() {
 		panic(fmt.Sprintf("BALANCE_RESERVE_INSUFFICIENT: %s need %d, available %d",
 			b.Symbol, amountSats, b.AvailableSats()))
@@ -47,6 +50,7 @@ func (b *Balance) Reserve(amountSats int64, seq uint64) {
 
 // Release unlocks reserved funds.
 func (b *Balance) Release(amountSats int64, seq uint64) {
+	b.requireNonNegative("RELEASE", amountSats)
 	if amountSats > b.ReservedSats {
 		panic(fmt.Sprintf("BALANCE_RELEASE_EXCEEDS_RESERVED: %s release %d, reserved %d",
 			b.Symbol, amountSats, b.ReservedSats))
@@ -55,6 +59,23 @@ func (b *Balance) Release(amountSats int64, seq uint64) {
 	b.LastSeq = seq
 }
 
+// Sync replaces the amount with one reported by the venue. Reservations
+// beyond it are dropped: the venue no longer holds them.
+func (b *Balance) Sync(amountSats int64, seq uint64) {
+	b.requireNonNegative("SYNC", amountSats)
+	b.AmountSats = amountSats
+	b.ReservedSats = min(b.ReservedSats, amountSats)
+	b.LastSeq = seq
+}
+
+// requireNonNegative panics on a negative amount: a negative credit would be a
+// debit that skips the availability check (and so on for the other operations).
+func (b *Balance) requireNonNegative(op string, amountSats int64) {
+	if amountSats < 0 {
+		panic(fmt.Sprintf("BALANCE_NEGATIVE_%s: %s amount %d", op, b.Symbol, amountSats))
+	}
+}
+
 // VerifyInvariant checks that balance satisfies invariants.
 // Call this after any state change to ensure data integrity.
 func (b *Balance) VerifyInvariant() {
@@ -99,6 +120,16 @@ func (bb *BalanceBook) Get(symbol string) *Balance {
 	return b
 }
 
+// Available returns the available amount of symbol without creating an
+// entry for it; ok is false for a symbol never booked.
+func (bb *BalanceBook) Available(symbol string) (amountSats int64, ok bool) {
+	b, ok := bb.balances[symbol]
+	if !ok {
+		return 0, false
+	}
+	return b.AvailableSats(), true
+}
+
 // VerifyAll checks invariants on all balances.
 func (bb *BalanceBook) VerifyAll() {
 	for _, b := range bb.balances {
@@ -115,6 +146,15 @@ func (bb *BalanceBook) Snapshot() map[string]Balance {
 	return result
 }
 
+// Restore replaces all balances with a copy of snap (as returned by Snapshot),
+// e.g. when recovering from a state snapshot.
+func (bb *BalanceBook) Restore(snap map[string]Balance) {
+	clear(bb.balances)
+	for symbol, b := range snap {
+		bb.balances[symbol] = &b
+	}
+}
+
 // CalculateTotalEquity computes the total value of the portfolio in the quote currency (e.g., KRW/USDT).
 // prices: map of symbol -> current price (PriceMicros).
 // returns: Total Equity in PriceMicros (int64).
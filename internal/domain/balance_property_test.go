@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// balanceOp is one Credit/Debit/Reserve/Release call.
+type balanceOp struct {
+	kind   int // 0 credit, 1 debit, 2 reserve, 3 release
+	amount int64
+}
+
+func (o balanceOp) String() string {
+	return fmt.Sprintf("%s(%d)", []string{"Credit", "Debit", "Reserve", "Release"}[o.kind], o.amount)
+}
+
+// balanceScript is a random operation sequence. Amounts are mostly small so
+// that debits and reserves often succeed, with negatives and int64 boundaries
+// mixed in.
+type balanceScript []balanceOp
+
+// Generate implements quick.Generator.
+func (balanceScript) Generate(r *rand.Rand, size int) reflect.Value {
+	ops := make(balanceScript, r.Intn(size*4+1))
+	for i := range ops {
+		amount := r.Int63n(1_000)
+		switch r.Intn(10) {
+		case 0:
+			amount = -amount - 1
+		case 1:
+			amount = []int64{0, math.MaxInt64, math.MaxInt64 - 1, math.MinInt64}[r.Intn(4)]
+		}
+		ops[i] = balanceOp{kind: r.Intn(4), amount: amount}
+	}
+	return reflect.ValueOf(ops)
+}
+
+// model is the reference behaviour: what each operation must do, or refuse.
+type model struct{ amount, reserved int64 }
+
+func (m model) apply(op balanceOp) (model, bool) {
+	a := op.amount
+	if a < 0 {
+		return m, false
+	}
+	available := m.amount - m.reserved
+	switch op.kind {
+	case 0:
+		if m.amount > math.MaxInt64-a {
+			return m, false
+		}
+		m.amount += a
+	case 1:
+		if a > available {
+			return m, false
+		}
+		m.amount -= a
+	case 2:
+		if a > available {
+			return m, false
+		}
+		m.reserved += a
+	case 3:
+		if a > m.reserved {
+			return m, false
+		}
+		m.reserved -= a
+	}
+	return m, true
+}
+
+// run applies op to b and reports whether it completed without panicking.
+func (o balanceOp) run(b *Balance, seq uint64) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	switch o.kind {
+	case 0:
+		b.Credit(o.amount, seq)
+	case 1:
+		b.Debit(o.amount, seq)
+	case 2:
+		b.Reserve(o.amount, seq)
+	case 3:
+		b.Release(o.amount, seq)
+	}
+	return true
+}
+
+func TestProperty_BalanceMatchesModel(t *testing.T) {
+	// Every operation either succeeds as the model says or panics leaving the
+	// balance untouched, and the invariants hold after each step.
+	matches := func(script balanceScript) bool {
+		b := &Balance{Symbol: "BTC"}
+		var m model
+		for i, op := range script {
+			want, allowed := m.apply(op)
+			before := *b
+			if ok := op.run(b, uint64(i+1)); ok != allowed {
+				t.Logf("step %d %s: completed=%v, model allows=%v", i, op, ok, allowed)
+				return false
+			}
+			if !allowed && *b != before {
+				t.Logf("step %d %s: refused operation changed %+v to %+v", i, op, before, *b)
+				return false
+			}
+			if b.AmountSats != want.amount || b.ReservedSats != want.reserved {
+				t.Logf("step %d %s: got %+v, model %+v", i, op, *b, want)
+				return false
+			}
+			b.VerifyInvariant()
+			m = want
+		}
+		return true
+	}
+	if err := quick.Check(matches, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_ReserveReleaseRestores(t *testing.T) {
+	// Reserving then releasing the same amount changes nothing but LastSeq
+	restores := func(amount, reserve uint32) bool {
+		b := &Balance{Symbol: "KRW"}
+		b.Credit(int64(amount), 1)
+		r := int64(reserve) % (int64(amount) + 1)
+		b.Reserve(r, 2)
+		if b.AvailableSats() != int64(amount)-r {
+			return false
+		}
+		b.Release(r, 3)
+		return b.AmountSats == int64(amount) && b.ReservedSats == 0 && b.AvailableSats() == int64(amount)
+	}
+	if err := quick.Check(restores, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"crypto_go/pkg/quant"
+	"time"
+)
+
+// Fixed-offset zones: no tzdata dependency, and neither KST nor UTC observes DST.
+var (
+	ZoneUTC = time.UTC
+	ZoneKST = time.FixedZone("KST", 9*60*60)
+)
+
+// Session defines where a venue's trading day starts.
+type Session struct {
+	Zone  *time.Location // Zone used for day labels (YYYY-MM-DD)
+	Reset time.Duration  // Offset of the day boundary from local midnight in Zone
+}
+
+// Venue sessions. Upbit resets its change rate at 09:00 KST; Bitget uses UTC midnight.
+// They coincide in absolute time today but label days differently, and that is
+// exactly what daily rollups must agree on.
+var (
+	SessionUpbit  = Session{Zone: ZoneKST, Reset: 9 * time.Hour}
+	SessionBitget = Session{Zone: ZoneUTC}
+	SessionKST    = Session{Zone: ZoneKST} // Calendar day in Korea (user-facing PnL)
+)
+
+// Calendar maps venues (event Exchange names) to their sessions. Unknown venues use UTC.
+// Read-only after construction; safe for concurrent use.
+type Calendar struct {
+	sessions map[string]Session
+	fallback Session
+}
+
+// NewCalendar returns a calendar with the built-in venue sessions.
+func NewCalendar() *Calendar {
+	return &Calendar{
+		sessions: map[string]Session{
+			"UPBIT":          SessionUpbit,
+			"BITHUMB":        SessionUpbit,
+			"COINONE":        SessionUpbit,
+			"BITGET_SPOT":    SessionBitget,
+			"BITGET_FUTURES": SessionBitget,
+			"BITGET_S":       SessionBitget,
+			"BITGET_F":       SessionBitget,
+			"OKX_SPOT":       SessionBitget,
+			"OKX_SWAP":       SessionBitget,
+			"BYBIT_SPOT":     SessionBitget,
+			"BYBIT_LINEAR":   SessionBitget,
+		},
+		fallback: SessionBitget,
+	}
+}
+
+// With returns a copy of the calendar with venue mapped to s.
+func (c *Calendar) With(venue string, s Session) *Calendar {
+	cp := &Calendar{sessions: make(map[string]Session, len(c.sessions)+1), fallback: c.fallback}
+	for k, v := range c.sessions {
+		cp.sessions[k] = v
+	}
+	cp.sessions[venue] = s
+	return cp
+}
+
+// Session returns the session for venue.
+func (c *Calendar) Session(venue string) Session {
+	if s, ok := c.sessions[venue]; ok {
+		return s
+	}
+	return c.fallback
+}
+
+// DayStart returns the start of the venue trading day containing ts.
+func (c *Calendar) DayStart(venue string, ts quant.TimeStamp) quant.TimeStamp {
+	return c.Session(venue).DayStart(ts)
+}
+
+// DayKey returns the venue trading day containing ts as YYYY-MM-DD.
+func (c *Calendar) DayKey(venue string, ts quant.TimeStamp) string {
+	return c.Session(venue).DayKey(ts)
+}
+
+// NextBoundary returns the start of the venue trading day after the one containing ts.
+func (c *Calendar) NextBoundary(venue string, ts quant.TimeStamp) quant.TimeStamp {
+	return c.Session(venue).NextBoundary(ts)
+}
+
+// DayStart returns the start of the session day containing ts.
+func (s Session) DayStart(ts quant.TimeStamp) quant.TimeStamp {
+	t := time.UnixMicro(int64(ts)).In(s.Zone).Add(-s.Reset)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.Zone).Add(s.Reset)
+	return quant.TimeStamp(start.UnixMicro())
+}
+
+// DayKey labels the session day containing ts with the local date of its start.
+func (s Session) DayKey(ts quant.TimeStamp) string {
+	return time.UnixMicro(int64(s.DayStart(ts))).In(s.Zone).Format("2006-01-02")
+}
+
+// ParseDay returns the start of the session day labelled day (YYYY-MM-DD), the inverse of DayKey.
+func (s Session) ParseDay(day string) (quant.TimeStamp, error) {
+	t, err := time.ParseInLocation("2006-01-02", day, s.Zone)
+	if err != nil {
+		return 0, err
+	}
+	return quant.TimeStamp(t.Add(s.Reset).UnixMicro()), nil
+}
+
+// NextBoundary returns the start of the next session day.
+func (s Session) NextBoundary(ts quant.TimeStamp) quant.TimeStamp {
+	start := time.UnixMicro(int64(s.DayStart(ts))).In(s.Zone)
+	return quant.TimeStamp(start.AddDate(0, 0, 1).UnixMicro())
+}
+
+// BucketStart aligns ts to an interval grid anchored at the session day start,
+// so candles never straddle a venue day boundary. interval must divide 24h.
+func (s Session) BucketStart(ts quant.TimeStamp, interval time.Duration) quant.TimeStamp {
+	day := s.DayStart(ts)
+	step := quant.TimeStamp(interval.Microseconds())
+	if step <= 0 {
+		return day
+	}
+	return day + (ts-day)/step*step
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"crypto_go/pkg/quant"
+	"testing"
+	"time"
+)
+
+func calTS(t time.Time) quant.TimeStamp { return quant.TimeStamp(t.UnixMicro()) }
+
+func TestCalendar_VenueDays(t *testing.T) {
+	cal := NewCalendar()
+
+	// 2026-03-10 08:30 KST = 2026-03-09 23:30 UTC: before both resets
+	before := calTS(time.Date(2026, 3, 9, 23, 30, 0, 0, time.UTC))
+	// 2026-03-10 09:30 KST = 2026-03-10 00:30 UTC: after both resets
+	after := calTS(time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC))
+
+	if got := cal.DayKey("UPBIT", before); got != "2026-03-09" {
+		t.Errorf("Upbit day before 09:00 KST: got %s", got)
+	}
+	if got := cal.DayKey("UPBIT", after); got != "2026-03-10" {
+		t.Errorf("Upbit day after 09:00 KST: got %s", got)
+	}
+	if got := cal.DayKey("BITGET_SPOT", before); got != "2026-03-09" {
+		t.Errorf("Bitget day: got %s", got)
+	}
+
+	// KST calendar day flips at local midnight, 9 hours earlier than the Upbit reset
+	kst := cal.With("PNL", SessionKST)
+	if got := kst.DayKey("PNL", before); got != "2026-03-10" {
+		t.Errorf("KST calendar day: got %s", got)
+	}
+
+	wantStart := calTS(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	if got := cal.DayStart("UPBIT", after); got != wantStart {
+		t.Errorf("Upbit day start: got %d, want %d", got, wantStart)
+	}
+	if got := cal.NextBoundary("UPBIT", before); got != wantStart {
+		t.Errorf("Upbit next boundary: got %d, want %d", got, wantStart)
+	}
+	if got := cal.DayKey("UNKNOWN", after); got != "2026-03-10" {
+		t.Errorf("unknown venue should fall back to UTC, got %s", got)
+	}
+}
+
+func TestSession_BucketStart(t *testing.T) {
+	at := calTS(time.Date(2026, 3, 10, 5, 47, 0, 0, time.UTC)) // 14:47 KST
+
+	// 4h candles on the KST calendar: anchored at 00:00 KST = 15:00 UTC the previous day
+	got := SessionKST.BucketStart(at, 4*time.Hour)
+	if want := calTS(time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC)); got != want {
+		t.Errorf("KST 4h bucket: got %d, want %d", got, want)
+	}
+	got = SessionBitget.BucketStart(at, 4*time.Hour)
+	if want := calTS(time.Date(2026, 3, 10, 4, 0, 0, 0, time.UTC)); got != want {
+		t.Errorf("UTC 4h bucket: got %d, want %d", got, want)
+	}
+}
+
+func TestSession_ParseDayRoundTrip(t *testing.T) {
+	ts := calTS(time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC))
+	for _, s := range []Session{SessionUpbit, SessionBitget, SessionKST} {
+		start, err := s.ParseDay(s.DayKey(ts))
+		if err != nil {
+			t.Fatalf("ParseDay failed: %v", err)
+		}
+		if start != s.DayStart(ts) {
+			t.Errorf("%v: ParseDay(DayKey) = %d, want DayStart %d", s.Zone, start, s.DayStart(ts))
+		}
+	}
+	if _, err := SessionUpbit.ParseDay("2026/03/10"); err == nil {
+		t.Error("expected error for malformed day")
+	}
+}
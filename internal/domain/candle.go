@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"crypto_go/pkg/quant"
+	"fmt"
+)
+
+// Supported candle (kline) intervals.
+const (
+	Candle1m = "1m"
+	Candle5m = "5m"
+	Candle1h = "1h"
+)
+
+var candleDurations = map[string]quant.TimeStamp{
+	Candle1m: 60 * 1_000_000,
+	Candle5m: 5 * 60 * 1_000_000,
+	Candle1h: 60 * 60 * 1_000_000,
+}
+
+// CandleDuration returns the length of an interval in micros.
+func CandleDuration(interval string) (quant.TimeStamp, error) {
+	d, ok := candleDurations[interval]
+	if !ok {
+		return 0, fmt.Errorf("unsupported candle interval %q (want %s, %s or %s)", interval, Candle1m, Candle5m, Candle1h)
+	}
+	return d, nil
+}
+
+// Candle is one OHLCV bar of an exchange/symbol. Venues push the in-progress
+// bar repeatedly; a bar is final once a bar with a later OpenTime arrives.
+type Candle struct {
+	Exchange    string            `json:"exchange"`
+	Symbol      string            `json:"symbol"`
+	Interval    string            `json:"interval"`
+	OpenTime    quant.TimeStamp   `json:"open_time"`
+	OpenMicros  quant.PriceMicros `json:"open"`
+	HighMicros  quant.PriceMicros `json:"high"`
+	LowMicros   quant.PriceMicros `json:"low"`
+	CloseMicros quant.PriceMicros `json:"close"`
+	VolumeSats  quant.QtySats     `json:"volume"` // Base asset volume
+}
+
+// CloseTime returns the end of the bar (exclusive), or OpenTime for an unknown interval.
+func (c Candle) CloseTime() quant.TimeStamp {
+	d, _ := CandleDuration(c.Interval)
+	return c.OpenTime + d
+}
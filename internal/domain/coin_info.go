@@ -9,6 +9,8 @@ type CoinInfo struct {
 	Symbol          string `json:"symbol"`
 	Name            string `json:"name"`
 	IconPath        string `json:"icon_path"`
-	IsActive        bool   `json:"is_active"`   // Active trading status
-	IsFavorite      bool   `json:"is_favorite"` // User favorite status
+	IconETag        string `json:"icon_etag,omitempty"`          // HTTP validator of the downloaded icon
+	IconModified    string `json:"icon_last_modified,omitempty"` // HTTP Last-Modified of the downloaded icon
+	IsActive        bool   `json:"is_active"`                    // Active trading status
+	IsFavorite      bool   `json:"is_favorite"`                  // User favorite status
 }
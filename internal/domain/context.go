@@ -0,0 +1,22 @@
+package domain
+
+import "crypto_go/pkg/quant"
+
+// Context metric names. Context metrics are low-frequency (daily) readings from
+// off-exchange sources that strategies use as regime filters, not as signals to
+// trade on directly. Values are USD micros unless noted.
+const (
+	MetricStablecoinSupply  = "STABLECOIN_SUPPLY"  // Circulating USD-pegged stablecoins
+	MetricStablecoinNetflow = "STABLECOIN_NETFLOW" // Day-over-day supply change (mint - burn)
+	MetricExchangeReserve   = "EXCHANGE_RESERVE"   // Assets held by the Subject exchange
+	MetricExchangeNetflow   = "EXCHANGE_NETFLOW"   // Day-over-day reserve change (includes price moves)
+)
+
+// ContextMetric is one reading of a context metric.
+type ContextMetric struct {
+	Source      string          // Provider, e.g. "ONCHAIN"
+	Name        string          // Metric* constant
+	Subject     string          // What it is about (e.g. an exchange); "" = market-wide
+	ValueMicros int64           // Value scaled by 1e6
+	Ts          quant.TimeStamp // Observation time (start of the reported day for daily data)
+}
@@ -77,3 +77,70 @@ var (
 	// ErrConfigNotFound is returned when configuration file is missing
 	ErrConfigNotFound = errors.New("configuration not found")
 )
+
+// RejectKind classifies why a venue refused a request, independent of the venue.
+type RejectKind string
+
+const (
+	RejectUnknown           RejectKind = "UNKNOWN"
+	RejectInsufficientFunds RejectKind = "INSUFFICIENT_FUNDS"
+	RejectMinSize           RejectKind = "MIN_SIZE"
+	RejectRateLimited       RejectKind = "RATE_LIMITED"
+	RejectInvalidPrice      RejectKind = "INVALID_PRICE"
+	RejectOrderNotFound     RejectKind = "ORDER_NOT_FOUND"
+	RejectMarketUnavailable RejectKind = "MARKET_UNAVAILABLE"
+	RejectRiskLimit         RejectKind = "RISK_LIMIT"  // Refused by the pre-trade risk checks, never sent
+	RejectReduceOnly        RejectKind = "REDUCE_ONLY" // Would grow a position while its venue is reduce-only, never sent
+)
+
+// Sentinels for errors.Is matching on an ExchangeError's kind.
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrMinSize           = errors.New("below minimum order size")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrInvalidPrice      = errors.New("invalid price")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrMarketUnavailable = errors.New("market unavailable")
+)
+
+var rejectSentinels = map[RejectKind]error{
+	RejectInsufficientFunds: ErrInsufficientFunds,
+	RejectMinSize:           ErrMinSize,
+	RejectRateLimited:       ErrRateLimited,
+	RejectInvalidPrice:      ErrInvalidPrice,
+	RejectOrderNotFound:     ErrOrderNotFound,
+	RejectMarketUnavailable: ErrMarketUnavailable,
+}
+
+// ExchangeError is a business error returned by a venue, with its raw code kept
+// for diagnostics and a venue-independent Kind for strategies and risk logic.
+type ExchangeError struct {
+	Venue string     // "BITGET", "UPBIT", "PAPER"
+	Code  string     // Raw venue error code
+	Msg   string     // Raw venue message
+	Kind  RejectKind // Normalized classification
+}
+
+func (e *ExchangeError) Error() string {
+	return e.Venue + " rejected [" + string(e.Kind) + "] code=" + e.Code + " msg=" + e.Msg
+}
+
+// IsRetriable: only rate limiting clears by itself; the other rejections repeat until inputs change.
+func (e *ExchangeError) IsRetriable() bool {
+	return e.Kind == RejectRateLimited
+}
+
+// Is lets errors.Is(err, ErrInsufficientFunds) match by kind.
+func (e *ExchangeError) Is(target error) bool {
+	sentinel, ok := rejectSentinels[e.Kind]
+	return ok && sentinel == target
+}
+
+// RejectKindOf returns the kind of the ExchangeError in err's chain, or RejectUnknown.
+func RejectKindOf(err error) RejectKind {
+	var ee *ExchangeError
+	if errors.As(err, &ee) {
+		return ee.Kind
+	}
+	return RejectUnknown
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -64,3 +65,26 @@ func TestConfigError(t *testing.T) {
 		t.Errorf("Error message = %q, want %q", err.Error(), expected)
 	}
 }
+
+func TestExchangeError(t *testing.T) {
+	err := fmt.Errorf("place order: %w", &ExchangeError{Venue: "BITGET", Code: "43012", Msg: "Insufficient balance", Kind: RejectInsufficientFunds})
+
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Error("expected errors.Is to match the kind sentinel")
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Error("must not match another kind")
+	}
+	if RejectKindOf(err) != RejectInsufficientFunds {
+		t.Errorf("unexpected kind %s", RejectKindOf(err))
+	}
+	if IsRetriable(err) {
+		t.Error("insufficient funds is not retriable")
+	}
+	if !IsRetriable(&ExchangeError{Kind: RejectRateLimited}) {
+		t.Error("rate limiting is retriable")
+	}
+	if RejectKindOf(errors.New("boom")) != RejectUnknown {
+		t.Error("plain errors classify as unknown")
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "crypto_go/pkg/quant"
+
+// FundingEpoch is the next funding settlement of a perpetual contract on one
+// venue, as announced by its ticker (Bitget nextFundingTime, Bybit
+// nextFundingTime). RateMicros is the predicted rate (0.0001 = 100).
+type FundingEpoch struct {
+	Exchange   string          `json:"exchange"`
+	Symbol     string          `json:"symbol"`
+	NextTs     quant.TimeStamp `json:"next_ts,string"`
+	RateMicros int64           `json:"rate,string"`
+	Warned     bool            `json:"warned,omitempty"` // Pre-funding warning already raised for NextTs
+}
+
+// Until returns the time left until the epoch at ts (0 once it passed).
+func (f FundingEpoch) Until(ts quant.TimeStamp) quant.TimeStamp {
+	return max(f.NextTs-ts, 0)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"strings"
+)
+
+// fxVehicle is the currency cross rates are formed through (e.g. JPY/KRW =
+// USD/KRW / USD/JPY). One hop only, so the result is deterministic.
+const fxVehicle = "USD"
+
+type fxQuote struct {
+	price quant.PriceMicros
+	ts    quant.TimeStamp
+}
+
+// FXRates holds the latest rate of each polled pair ("BASE/QUOTE") and resolves
+// any currency pair from them: directly, inverted, or crossed through USD.
+// A pair older than its max age is treated as missing. Not goroutine-safe.
+type FXRates struct {
+	quotes        map[string]fxQuote
+	maxAge        map[string]quant.TimeStamp
+	defaultMaxAge quant.TimeStamp
+}
+
+// NewFXRates creates an empty table where pairs expire after defaultMaxAge (micros).
+func NewFXRates(defaultMaxAge quant.TimeStamp) *FXRates {
+	return &FXRates{
+		quotes:        make(map[string]fxQuote),
+		maxAge:        make(map[string]quant.TimeStamp),
+		defaultMaxAge: defaultMaxAge,
+	}
+}
+
+// SetMaxAge overrides the staleness limit of one pair (e.g. a slowly polled provider).
+func (r *FXRates) SetMaxAge(pair string, maxAge quant.TimeStamp) {
+	r.maxAge[pair] = maxAge
+}
+
+// Update records the rate of pair ("USD/KRW": KRW per 1 USD) at ts.
+func (r *FXRates) Update(pair string, price quant.PriceMicros, ts quant.TimeStamp) {
+	if price <= 0 || !strings.Contains(pair, "/") {
+		return
+	}
+	r.quotes[pair] = fxQuote{price: price, ts: ts}
+}
+
+// Rate returns how many quote units one base unit is worth at now, or ok=false
+// if no fresh direct, inverse or USD cross rate exists.
+func (r *FXRates) Rate(base, quote string, now quant.TimeStamp) (quant.PriceMicros, bool) {
+	if base == quote {
+		return quant.PriceMicros(quant.PriceScale), true
+	}
+	if num, den, ok := r.ratio(base, quote, now); ok {
+		return quant.PriceMicros(safe.SafeMulDiv(num, quant.PriceScale, den)), true
+	}
+	if base == fxVehicle || quote == fxVehicle {
+		return 0, false
+	}
+	num1, den1, ok1 := r.ratio(base, fxVehicle, now)
+	num2, den2, ok2 := r.ratio(fxVehicle, quote, now)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	// Multiply before dividing: inverting a leg first would truncate it (1/140 JPY)
+	return quant.PriceMicros(safe.SafeMulDiv(safe.SafeMulDiv(num1, num2, den1), quant.PriceScale, den2)), true
+}
+
+// ratio resolves base/quote as num/den from the pair itself or its inverse.
+func (r *FXRates) ratio(base, quote string, now quant.TimeStamp) (num, den int64, ok bool) {
+	if q, ok := r.fresh(base+"/"+quote, now); ok {
+		return int64(q.price), quant.PriceScale, true
+	}
+	if q, ok := r.fresh(quote+"/"+base, now); ok {
+		return quant.PriceScale, int64(q.price), true
+	}
+	return 0, 0, false
+}
+
+func (r *FXRates) fresh(pair string, now quant.TimeStamp) (fxQuote, bool) {
+	q, ok := r.quotes[pair]
+	if !ok {
+		return fxQuote{}, false
+	}
+	maxAge, ok := r.maxAge[pair]
+	if !ok {
+		maxAge = r.defaultMaxAge
+	}
+	if now-q.ts > maxAge {
+		return fxQuote{}, false
+	}
+	return q, true
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+func TestFXRates_Resolve(t *testing.T) {
+	const maxAge = 600_000_000 // 10 min
+	r := NewFXRates(maxAge)
+	r.Update("USD/KRW", 1_400_000_000, 0) // 1,400 KRW per USD
+	r.Update("USD/JPY", 140_000_000, 0)   // 140 JPY per USD
+	r.Update("EUR/USD", 1_100_000, 0)     // 1.1 USD per EUR
+
+	cases := []struct {
+		base, quote string
+		want        quant.PriceMicros
+	}{
+		{"KRW", "KRW", 1_000_000},
+		{"USD", "KRW", 1_400_000_000}, // Direct
+		{"KRW", "USD", 714},           // Inverse (1/1400, truncated)
+		{"JPY", "KRW", 10_000_000},    // Cross: 1400 / 140
+		{"EUR", "KRW", 1_540_000_000}, // Cross: 1.1 * 1400
+	}
+	for _, c := range cases {
+		got, ok := r.Rate(c.base, c.quote, 1)
+		if !ok || got != c.want {
+			t.Errorf("%s/%s = %d (%v), want %d", c.base, c.quote, got, ok, c.want)
+		}
+	}
+	if _, ok := r.Rate("GBP", "KRW", 1); ok {
+		t.Error("GBP has no path to KRW")
+	}
+}
+
+func TestFXRates_Staleness(t *testing.T) {
+	r := NewFXRates(600_000_000)
+	r.SetMaxAge("USD/JPY", 60_000_000) // Polled often: 1 min
+	r.Update("USD/KRW", 1_400_000_000, 0)
+	r.Update("USD/JPY", 140_000_000, 0)
+
+	if _, ok := r.Rate("JPY", "KRW", 60_000_000); !ok {
+		t.Fatal("both legs are still fresh")
+	}
+	if _, ok := r.Rate("JPY", "KRW", 120_000_000); ok {
+		t.Error("a stale USD/JPY leg must fail the cross rate")
+	}
+	if _, ok := r.Rate("USD", "KRW", 120_000_000); !ok {
+		t.Error("USD/KRW keeps the default max age")
+	}
+}
@@ -12,4 +12,18 @@ type MarketState struct {
 	LastUpdateUnixM quant.TimeStamp   `json:"last_update,string"`
 	// Cold fields (less frequent access)
 	Symbol string `json:"symbol"`
+	// Next funding epoch of the symbol's perpetuals on any venue (0 = none known)
+	NextFundingUnixM quant.TimeStamp `json:"next_funding,string,omitempty"`
+	FundingInMicros  quant.TimeStamp `json:"funding_in,string,omitempty"` // Time to NextFundingUnixM at LastUpdateUnixM
+}
+
+// Trade is one executed trade (tick) on a venue's public tape.
+// Side is the aggressor: SideBuy = a buyer lifted the ask, SideSell = a seller hit the bid.
+type Trade struct {
+	Exchange    string
+	Symbol      string
+	Side        string
+	PriceMicros quant.PriceMicros
+	QtySats     quant.QtySats
+	Ts          quant.TimeStamp // Exchange trade time
 }
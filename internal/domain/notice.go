@@ -0,0 +1,20 @@
+package domain
+
+import "crypto_go/pkg/quant"
+
+// Notice kinds: exchange announcements that move markets within seconds.
+const (
+	NoticeListing   = "LISTING"   // New trading support (KRW listings pump)
+	NoticeDelisting = "DELISTING" // Trading support ends
+	NoticeWallet    = "WALLET"    // Deposit/withdrawal suspension or wallet maintenance
+)
+
+// Notice is one classified exchange announcement.
+type Notice struct {
+	Exchange string          `json:"exchange"`
+	ID       int64           `json:"id"`   // Exchange notice ID (increasing)
+	Kind     string          `json:"kind"` // Notice* constant
+	Title    string          `json:"title"`
+	Symbols  []string        `json:"symbols,omitempty"` // Tickers named in the title
+	Ts       quant.TimeStamp `json:"ts"`                // Publication time
+}
@@ -1,32 +1,100 @@
 package domain
 
+import "fmt"
+
 // Order represents a trading order.
 // All monetary values are strictly int64.
 type Order struct {
 	ID           string
 	Symbol       string
 	Side         string // "BUY", "SELL"
-	Type         string // "LIMIT", "MARKET"
-	PriceMicros  int64  `json:"price,string"` // Limit Price in Micros. 0 for Market Order.
+	Type         string // "LIMIT", "MARKET", "OCO", "TRAILING_STOP"
+	Market       string // "SPOT", "FUTURES"; empty = the venue's default market
+	Exchange     string `json:",omitempty"`   // Venue chosen by the order router; empty = the gateway's default venue
+	PriceMicros  int64  `json:"price,string"` // Limit Price in Micros. 0 for Market Order. OCO: take-profit limit.
 	QtySats      int64  `json:"qty,string"`   // Order Quantity in Satoshis.
-	Status       string // "NEW", "PARTIALLY_FILLED", "FILLED", "CANCELED"
+	Status       string // "NEW", "ARMED", "SENT", "ACK", "PARTIALLY_FILLED", "FILLED", "CANCELED", "REJECTED"
 	CreatedUnixM int64  `json:"created_at,string"` // Unix Microseconds
+
+	// Conditional orders (OCO, TRAILING_STOP) are held by the OMS and sent as a
+	// LIMIT or MARKET order once triggered, for venues without native support.
+	StopPriceMicros int64  `json:"stop_price,string,omitempty"` // OCO: stop leg trigger price
+	TrailBps        int64  `json:"trail_bps,omitempty"`         // TRAILING_STOP: retracement from the best price (1..9999)
+	TriggerExchange string `json:"trigger_exchange,omitempty"`  // Price feed that triggers (empty = OMS default)
+
+	Urgency string `json:"urgency,omitempty"` // Router execution path: UrgencyHigh, UrgencyLow, empty = by cost
+	TTLSec  int64  `json:"ttl_sec,omitempty"` // Resting LIMIT order: canceled this long after it was sent (0 = OMS default)
+
+	Strategy string `json:"strategy,omitempty"` // Registered strategy that emitted it (StrategyRegistry); empty = the only strategy or the operator
 }
 
 const (
 	SideBuy  = "BUY"
 	SideSell = "SELL"
 
-	OrderTypeLimit  = "LIMIT"
-	OrderTypeMarket = "MARKET"
+	OrderTypeLimit        = "LIMIT"
+	OrderTypeMarket       = "MARKET"
+	OrderTypeOCO          = "OCO"           // Take-profit LIMIT at PriceMicros or stop MARKET at StopPriceMicros, whichever triggers first
+	OrderTypeTrailingStop = "TRAILING_STOP" // MARKET once the price retraces TrailBps from its best since placement
+
+	UrgencyHigh = "HIGH" // Take liquidity now
+	UrgencyLow  = "LOW"  // Rest as a maker whenever the book allows, escalate on timeout
+
+	MarketSpot    = "SPOT"
+	MarketFutures = "FUTURES"
 
 	OrderStatusNew             = "NEW"
+	OrderStatusArmed           = "ARMED" // Conditional order held by the OMS until its trigger fires
+	OrderStatusSent            = "SENT"  // Handed to the execution gateway, no venue reply yet
+	OrderStatusAcked           = "ACK"   // Accepted by the venue, nothing filled yet
 	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
 	OrderStatusFilled          = "FILLED"
 	OrderStatusCanceled        = "CANCELED"
+	OrderStatusRejected        = "REJECTED"
 )
 
 // IsOpen checks if the order is still active.
 func (o *Order) IsOpen() bool {
-	return o.Status == "NEW" || o.Status == "PARTIALLY_FILLED"
+	switch o.Status {
+	case OrderStatusNew, OrderStatusArmed, OrderStatusSent, OrderStatusAcked, OrderStatusPartiallyFilled:
+		return true
+	}
+	return false
+}
+
+// IsConditional reports whether the order is simulated by the OMS (OCO, trailing stop).
+func (o *Order) IsConditional() bool {
+	return o.Type == OrderTypeOCO || o.Type == OrderTypeTrailingStop
+}
+
+// TriggerError checks the trigger of a conditional order: an OCO stop must lie
+// on the losing side of the take-profit, a trail must be a fraction of the
+// price. Nil for plain orders.
+func (o *Order) TriggerError() error {
+	switch o.Type {
+	case OrderTypeOCO:
+		if o.PriceMicros <= 0 || o.StopPriceMicros <= 0 {
+			return fmt.Errorf("OCO needs a take-profit and a stop price")
+		}
+		if o.Side == SideSell && o.StopPriceMicros >= o.PriceMicros {
+			return fmt.Errorf("OCO SELL stop %d must be below take-profit %d", o.StopPriceMicros, o.PriceMicros)
+		}
+		if o.Side == SideBuy && o.StopPriceMicros <= o.PriceMicros {
+			return fmt.Errorf("OCO BUY stop %d must be above take-profit %d", o.StopPriceMicros, o.PriceMicros)
+		}
+	case OrderTypeTrailingStop:
+		if o.TrailBps <= 0 || o.TrailBps >= 10_000 {
+			return fmt.Errorf("trailing stop needs 0 < trail_bps < 10000, got %d", o.TrailBps)
+		}
+	}
+	return nil
+}
+
+// OrderRejection describes an order the venue refused, in venue-independent terms.
+type OrderRejection struct {
+	Order    Order      // The order as submitted (Status = REJECTED)
+	Exchange string     // Venue that refused it
+	Reason   RejectKind // Normalized reason
+	Code     string     // Raw venue code (diagnostics only)
+	Message  string
 }
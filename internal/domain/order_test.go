@@ -9,9 +9,12 @@ func TestOrder_IsOpen(t *testing.T) {
 		want   bool
 	}{
 		{"NEW", "NEW", true},
+		{"SENT", "SENT", true},
+		{"ACK", "ACK", true},
 		{"PARTIALL_FILLED", "PARTIALLY_FILLED", true},
 		{"FILLED", "FILLED", false},
 		{"CANCELED", "CANCELED", false},
+		{"REJECTED", "REJECTED", false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"sort"
+)
+
+// BookLevel is one price level of an L2 order book.
+type BookLevel struct {
+	PriceMicros quant.PriceMicros `json:"price"`
+	QtySats     quant.QtySats     `json:"qty"` // 0 = level removed (delta only)
+}
+
+// OrderBook is the top-N L2 book of one exchange/symbol.
+// Owned by the Sequencer (single-threaded); readers get a Clone.
+type OrderBook struct {
+	Exchange string
+	Symbol   string
+	Ts       quant.TimeStamp // Time of the last applied update
+	Synced   bool            // A snapshot has been applied; deltas before it are ignored
+
+	bids map[quant.PriceMicros]quant.QtySats
+	asks map[quant.PriceMicros]quant.QtySats
+}
+
+// NewOrderBook creates an empty book.
+func NewOrderBook(exchange, symbol string) *OrderBook {
+	return &OrderBook{
+		Exchange: exchange,
+		Symbol:   symbol,
+		bids:     make(map[quant.PriceMicros]quant.QtySats),
+		asks:     make(map[quant.PriceMicros]quant.QtySats),
+	}
+}
+
+// Apply folds one update into the book. A snapshot replaces both sides; a delta
+// sets the listed levels (qty 0 removes one) and is ignored until the first
+// snapshot. Returns whether the update was applied.
+func (b *OrderBook) Apply(snapshot bool, bids, asks []BookLevel, ts quant.TimeStamp) bool {
+	if snapshot {
+		clear(b.bids)
+		clear(b.asks)
+		b.Synced = true
+	} else if !b.Synced {
+		return false
+	}
+
+	setLevels(b.bids, bids)
+	setLevels(b.asks, asks)
+	b.Ts = ts
+	return true
+}
+
+// Clone returns an independent copy.
+func (b *OrderBook) Clone() *OrderBook {
+	cp := NewOrderBook(b.Exchange, b.Symbol)
+	cp.Ts, cp.Synced = b.Ts, b.Synced
+	for p, q := range b.bids {
+		cp.bids[p] = q
+	}
+	for p, q := range b.asks {
+		cp.asks[p] = q
+	}
+	return cp
+}
+
+// Bids returns up to depth bid levels, best (highest) first. depth <= 0 = all.
+func (b *OrderBook) Bids(depth int) []BookLevel {
+	return sortedLevels(b.bids, depth, func(x, y quant.PriceMicros) bool { return x > y })
+}
+
+// Asks returns up to depth ask levels, best (lowest) first. depth <= 0 = all.
+func (b *OrderBook) Asks(depth int) []BookLevel {
+	return sortedLevels(b.asks, depth, func(x, y quant.PriceMicros) bool { return x < y })
+}
+
+// BestBid returns the highest bid, or ok=false for an empty side.
+func (b *OrderBook) BestBid() (BookLevel, bool) {
+	return bestLevel(b.bids, func(x, y quant.PriceMicros) bool { return x > y })
+}
+
+// BestAsk returns the lowest ask, or ok=false for an empty side.
+func (b *OrderBook) BestAsk() (BookLevel, bool) {
+	return bestLevel(b.asks, func(x, y quant.PriceMicros) bool { return x < y })
+}
+
+// SpreadMicros returns best ask - best bid, or ok=false if either side is empty.
+func (b *OrderBook) SpreadMicros() (quant.PriceMicros, bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return ask.PriceMicros - bid.PriceMicros, true
+}
+
+// EstimateFill walks the book for a market order of qtySats on side (a BUY takes
+// asks, a SELL takes bids) and returns the volume-weighted average price and the
+// quantity the visible levels can fill. filled < qtySats means the top-N book is
+// too thin; avg is 0 when nothing fills.
+func (b *OrderBook) EstimateFill(side string, qtySats quant.QtySats) (avg quant.PriceMicros, filled quant.QtySats) {
+	levels := b.Bids(0)
+	if side == SideBuy {
+		levels = b.Asks(0)
+	}
+
+	var notional int64 // Sum of price * qty / 1e8 (quote micros)
+	for _, l := range levels {
+		if filled >= qtySats {
+			break
+		}
+		take := min(l.QtySats, qtySats-filled)
+		notional = safe.SafeAdd(notional, safe.SafeMulDiv(int64(l.PriceMicros), int64(take), quant.QtyScale))
+		filled += take
+	}
+	if filled == 0 {
+		return 0, 0
+	}
+	return quant.PriceMicros(safe.SafeMulDiv(notional, quant.QtyScale, int64(filled))), filled
+}
+
+func setLevels(side map[quant.PriceMicros]quant.QtySats, levels []BookLevel) {
+	for _, l := range levels {
+		if l.QtySats <= 0 {
+			delete(side, l.PriceMicros)
+			continue
+		}
+		side[l.PriceMicros] = l.QtySats
+	}
+}
+
+func sortedLevels(side map[quant.PriceMicros]quant.QtySats, depth int, better func(x, y quant.PriceMicros) bool) []BookLevel {
+	out := make([]BookLevel, 0, len(side))
+	for p, q := range side {
+		out = append(out, BookLevel{PriceMicros: p, QtySats: q})
+	}
+	sort.Slice(out, func(i, j int) bool { return better(out[i].PriceMicros, out[j].PriceMicros) })
+	if depth > 0 && len(out) > depth {
+		out = out[:depth]
+	}
+	return out
+}
+
+func bestLevel(side map[quant.PriceMicros]quant.QtySats, better func(x, y quant.PriceMicros) bool) (BookLevel, bool) {
+	var best BookLevel
+	found := false
+	for p, q := range side {
+		if !found || better(p, best.PriceMicros) {
+			best, found = BookLevel{PriceMicros: p, QtySats: q}, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+func lvl(price, qty int64) BookLevel {
+	return BookLevel{PriceMicros: quant.PriceMicros(price), QtySats: quant.QtySats(qty)}
+}
+
+func TestOrderBook_ApplySnapshotAndDelta(t *testing.T) {
+	b := NewOrderBook("UPBIT", "BTC")
+
+	// Delta before any snapshot is ignored
+	if b.Apply(false, []BookLevel{lvl(99, 1)}, nil, 0) {
+		t.Fatal("delta before snapshot should be ignored")
+	}
+
+	b.Apply(true, []BookLevel{lvl(100, 5), lvl(99, 3)}, []BookLevel{lvl(101, 2), lvl(102, 4)}, 1)
+	b.Apply(false, []BookLevel{lvl(100, 0), lvl(98, 7)}, []BookLevel{lvl(101, 9)}, 2) // Remove best bid, add a level
+
+	if bid, _ := b.BestBid(); bid != lvl(99, 3) {
+		t.Errorf("best bid = %+v, want 99x3", bid)
+	}
+	if ask, _ := b.BestAsk(); ask != lvl(101, 9) {
+		t.Errorf("best ask = %+v, want 101x9", ask)
+	}
+	if got := b.Bids(0); len(got) != 2 || got[1] != lvl(98, 7) {
+		t.Errorf("bids = %+v", got)
+	}
+	if spread, ok := b.SpreadMicros(); !ok || spread != 2 {
+		t.Errorf("spread = %d, %v", spread, ok)
+	}
+	if b.Ts != 2 {
+		t.Errorf("ts = %d, want 2", b.Ts)
+	}
+
+	// A snapshot replaces everything
+	b.Apply(true, []BookLevel{lvl(50, 1)}, nil, 3)
+	if len(b.Bids(0)) != 1 || len(b.Asks(0)) != 0 {
+		t.Errorf("snapshot must replace the book: bids %v asks %v", b.Bids(0), b.Asks(0))
+	}
+}
+
+func TestOrderBook_CloneIsIndependent(t *testing.T) {
+	b := NewOrderBook("UPBIT", "BTC")
+	b.Apply(true, []BookLevel{lvl(100, 1)}, nil, 1)
+	cp := b.Clone()
+	b.Apply(false, []BookLevel{lvl(100, 0)}, nil, 2)
+
+	if bid, ok := cp.BestBid(); !ok || bid != lvl(100, 1) || !cp.Synced {
+		t.Errorf("clone changed with the original: %+v", cp.Bids(0))
+	}
+}
+
+func TestOrderBook_EstimateFill(t *testing.T) {
+	const btc = quant.QtyScale
+	b := NewOrderBook("UPBIT", "BTC")
+	b.Apply(true,
+		[]BookLevel{lvl(99_000_000, btc)},
+		[]BookLevel{lvl(100_000_000, btc), lvl(102_000_000, btc)},
+		1)
+
+	// 1.5 BTC: 1 @ 100 + 0.5 @ 102 = 100.666...
+	avg, filled := b.EstimateFill(SideBuy, 3*btc/2)
+	if filled != 3*btc/2 || avg != 100_666_666 {
+		t.Errorf("buy 1.5: avg %d filled %d", avg, filled)
+	}
+
+	// Thin book: only 1 BTC of bids
+	avg, filled = b.EstimateFill(SideSell, 2*btc)
+	if filled != btc || avg != 99_000_000 {
+		t.Errorf("sell 2: avg %d filled %d", avg, filled)
+	}
+
+	if avg, filled := NewOrderBook("UPBIT", "ETH").EstimateFill(SideBuy, btc); avg != 0 || filled != 0 {
+		t.Errorf("empty book: avg %d filled %d", avg, filled)
+	}
+}
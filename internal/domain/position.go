@@ -1,12 +1,23 @@
 package domain
 
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"fmt"
+)
+
 // Position represents an open trading position.
 // All monetary values are strictly int64.
 type Position struct {
+	Exchange            string `json:"exchange,omitempty"` // Venue of a routed order; empty = the default venue
 	Symbol              string `json:"symbol"`
 	QtySats             int64  `json:"qty,string"`             // Positive for Long, Negative for Short.
 	AvgEntryPriceMicros int64  `json:"avg_entry_price,string"` // Weighted Average Entry Price.
 	RealizedPnLMicros   int64  `json:"realized_pnl,string"`    // Realized Profit/Loss.
+	UnrealizedPnLMicros int64  `json:"unrealized_pnl,string"`  // Open quantity valued at MarkPriceMicros.
+	MarkPriceMicros     int64  `json:"mark_price,string"`      // Last market price (last fill until one arrives).
+	FundingPaidMicros   int64  `json:"funding_paid,string"`    // Perpetual funding paid (negative = received).
+	LastSeq             uint64 `json:"last_seq"`               // Last event sequence that modified this
 }
 
 // IsLong checks if the position is Long.
@@ -19,4 +30,182 @@ func (p *Position) IsShort() bool {
 	return p.QtySats < 0
 }
 
-// IsProductTypeCompatible check logic if needed in future
+// NetPnLMicros returns realized plus unrealized PnL, less funding paid.
+func (p *Position) NetPnLMicros() int64 {
+	return safe.SafeSub(safe.SafeAdd(p.RealizedPnLMicros, p.UnrealizedPnLMicros), p.FundingPaidMicros)
+}
+
+// ApplyFill books a fill of qtySats at priceMicros. A fill in the direction of
+// the position (or from flat) moves the average entry; an opposite fill
+// realizes PnL on the closed quantity, and any excess opens the other side at
+// the fill price. Panics on overflow.
+func (p *Position) ApplyFill(side string, qtySats, priceMicros int64, seq uint64) {
+	if qtySats <= 0 {
+		return
+	}
+	signed := qtySats
+	if side == SideSell {
+		signed = -qtySats
+	}
+
+	held := abs(p.QtySats)
+	if p.QtySats == 0 || (p.QtySats > 0) == (signed > 0) {
+		total := safe.SafeAdd(held, qtySats)
+		p.AvgEntryPriceMicros = safe.SafeAdd(
+			safe.SafeMulDiv(p.AvgEntryPriceMicros, held, total),
+			safe.SafeMulDiv(priceMicros, qtySats, total))
+	} else {
+		closed := min(qtySats, held)
+		pnl := safe.SafeMulDiv(safe.SafeSub(priceMicros, p.AvgEntryPriceMicros), closed, quant.QtyScale)
+		if p.IsShort() {
+			pnl = -pnl
+		}
+		p.RealizedPnLMicros = safe.SafeAdd(p.RealizedPnLMicros, pnl)
+		switch {
+		case qtySats == held:
+			p.AvgEntryPriceMicros = 0
+		case qtySats > held:
+			p.AvgEntryPriceMicros = priceMicros // Flipped: the excess opened at this fill
+		}
+	}
+	p.QtySats = safe.SafeAdd(p.QtySats, signed)
+
+	if p.MarkPriceMicros == 0 {
+		p.MarkPriceMicros = priceMicros
+	}
+	p.revalue()
+	p.LastSeq = seq
+}
+
+// Mark revalues the open quantity at priceMicros.
+func (p *Position) Mark(priceMicros int64, seq uint64) {
+	p.MarkPriceMicros = priceMicros
+	p.revalue()
+	p.LastSeq = seq
+}
+
+// PayFunding books a funding payment (negative = received).
+func (p *Position) PayFunding(amountMicros int64, seq uint64) {
+	p.FundingPaidMicros = safe.SafeAdd(p.FundingPaidMicros, amountMicros)
+	p.LastSeq = seq
+}
+
+func (p *Position) revalue() {
+	if p.QtySats == 0 {
+		p.UnrealizedPnLMicros = 0
+		return
+	}
+	p.UnrealizedPnLMicros = safe.SafeMulDiv(safe.SafeSub(p.MarkPriceMicros, p.AvgEntryPriceMicros), p.QtySats, quant.QtyScale)
+}
+
+// VerifyInvariant checks that position satisfies invariants.
+// Call this after any state change to ensure data integrity.
+func (p *Position) VerifyInvariant() {
+	// Invariant 1: A flat position has no entry price and nothing unrealized
+	if p.QtySats == 0 && (p.AvgEntryPriceMicros != 0 || p.UnrealizedPnLMicros != 0) {
+		panic(fmt.Sprintf("POSITION_INVARIANT_FLAT_NOT_CLEAR: %s avg=%d, unrealized=%d",
+			p.Symbol, p.AvgEntryPriceMicros, p.UnrealizedPnLMicros))
+	}
+
+	// Invariant 2: An open position has a positive entry price
+	if p.QtySats != 0 && p.AvgEntryPriceMicros <= 0 {
+		panic(fmt.Sprintf("POSITION_INVARIANT_NON_POSITIVE_ENTRY: %s qty=%d, avg=%d",
+			p.Symbol, p.QtySats, p.AvgEntryPriceMicros))
+	}
+
+	// Invariant 3: Prices are never negative
+	if p.MarkPriceMicros < 0 {
+		panic(fmt.Sprintf("POSITION_INVARIANT_NEGATIVE_MARK: %s = %d",
+			p.Symbol, p.MarkPriceMicros))
+	}
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+type positionKey struct{ exchange, symbol string }
+
+// PositionBook manages positions per venue and symbol with invariant checking.
+type PositionBook struct {
+	positions map[positionKey]*Position
+}
+
+// NewPositionBook creates a new position book.
+func NewPositionBook() *PositionBook {
+	return &PositionBook{
+		positions: make(map[positionKey]*Position),
+	}
+}
+
+// Get returns the position of exchange/symbol, creating if not exists.
+func (pb *PositionBook) Get(exchange, symbol string) *Position {
+	key := positionKey{exchange, symbol}
+	p, ok := pb.positions[key]
+	if !ok {
+		p = &Position{Exchange: exchange, Symbol: symbol}
+		pb.positions[key] = p
+	}
+	return p
+}
+
+// Lookup returns a copy of the exchange/symbol position without creating one.
+func (pb *PositionBook) Lookup(exchange, symbol string) (Position, bool) {
+	p, ok := pb.positions[positionKey{exchange, symbol}]
+	if !ok {
+		return Position{}, false
+	}
+	return *p, true
+}
+
+// NetPnLMicros returns the net PnL summed over all positions. Amounts are
+// added as they are, so it is meaningful for positions in one quote currency.
+func (pb *PositionBook) NetPnLMicros() int64 {
+	var total int64
+	for _, p := range pb.positions {
+		total = safe.SafeAdd(total, p.NetPnLMicros())
+	}
+	return total
+}
+
+// Mark revalues the exchange/symbol position, if one exists, at priceMicros.
+func (pb *PositionBook) Mark(exchange, symbol string, priceMicros int64, seq uint64) {
+	if p, ok := pb.positions[positionKey{exchange, symbol}]; ok {
+		p.Mark(priceMicros, seq)
+	}
+}
+
+// Range calls fn with a copy of every position, in no particular order.
+func (pb *PositionBook) Range(fn func(p Position)) {
+	for _, p := range pb.positions {
+		fn(*p)
+	}
+}
+
+// VerifyAll checks invariants on all positions.
+func (pb *PositionBook) VerifyAll() {
+	for _, p := range pb.positions {
+		p.VerifyInvariant()
+	}
+}
+
+// Snapshot returns a copy of all positions keyed "exchange|symbol" (for state dump).
+func (pb *PositionBook) Snapshot() map[string]Position {
+	result := make(map[string]Position, len(pb.positions))
+	for k, v := range pb.positions {
+		result[k.exchange+"|"+k.symbol] = *v
+	}
+	return result
+}
+
+// Restore replaces all positions with a copy of snap (as returned by
+// Snapshot), e.g. when recovering from a state snapshot.
+func (pb *PositionBook) Restore(snap map[string]Position) {
+	clear(pb.positions)
+	for _, p := range snap {
+		pb.positions[positionKey{p.Exchange, p.Symbol}] = &p
+	}
+}
@@ -25,3 +25,78 @@ func TestPosition_Direction(t *testing.T) {
 		})
 	}
 }
+
+const oneBTC = 100_000_000
+
+func TestPosition_FillsAndPnL(t *testing.T) {
+	p := &Position{Symbol: "BTC"}
+	p.ApplyFill(SideBuy, oneBTC, 100_000_000, 1)
+	p.ApplyFill(SideBuy, oneBTC, 110_000_000, 2)
+	if p.QtySats != 2*oneBTC || p.AvgEntryPriceMicros != 105_000_000 {
+		t.Fatalf("adding must average the entry: %+v", p)
+	}
+
+	p.Mark(120_000_000, 3)
+	if p.UnrealizedPnLMicros != 30_000_000 {
+		t.Errorf("unrealized = %d, want 30,000,000", p.UnrealizedPnLMicros)
+	}
+
+	// Partial close realizes on the closed part only; the entry stays
+	p.ApplyFill(SideSell, oneBTC/2, 125_000_000, 4)
+	if p.RealizedPnLMicros != 10_000_000 || p.AvgEntryPriceMicros != 105_000_000 || p.UnrealizedPnLMicros != 22_500_000 {
+		t.Errorf("unexpected partial close: %+v", p)
+	}
+
+	// Selling through zero flips to a short entered at the fill
+	p.ApplyFill(SideSell, 2*oneBTC, 100_000_000, 5)
+	if p.QtySats != -oneBTC/2 || p.AvgEntryPriceMicros != 100_000_000 || p.RealizedPnLMicros != 2_500_000 {
+		t.Errorf("unexpected flip: %+v", p)
+	}
+	p.Mark(90_000_000, 6)
+	if p.UnrealizedPnLMicros != 5_000_000 {
+		t.Errorf("short gains as the price falls, got %d", p.UnrealizedPnLMicros)
+	}
+
+	p.ApplyFill(SideBuy, oneBTC/2, 90_000_000, 7)
+	p.PayFunding(1_000_000, 8)
+	if p.QtySats != 0 || p.AvgEntryPriceMicros != 0 || p.UnrealizedPnLMicros != 0 || p.NetPnLMicros() != 6_500_000 || p.LastSeq != 8 {
+		t.Errorf("unexpected closed position: %+v net=%d", p, p.NetPnLMicros())
+	}
+	p.VerifyInvariant()
+}
+
+func TestPosition_InvariantPanic_OpenWithoutEntry(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for an open position without entry price")
+		}
+	}()
+
+	p := &Position{Symbol: "BTC", QtySats: 100}
+	p.VerifyInvariant()
+}
+
+func TestPosition_InvariantPanic_FlatWithEntry(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for a flat position with an entry price")
+		}
+	}()
+
+	p := &Position{Symbol: "BTC", AvgEntryPriceMicros: 100}
+	p.VerifyInvariant()
+}
+
+func TestPositionBook_ByVenue(t *testing.T) {
+	pb := NewPositionBook()
+	pb.Get("", "BTC").ApplyFill(SideBuy, oneBTC, 100, 1)
+	pb.Get("UPBIT", "BTC").ApplyFill(SideBuy, oneBTC, 140_000, 2)
+
+	pb.Mark("", "BTC", 110, 3)
+	pb.Mark("OKX", "BTC", 1, 4) // No position there: nothing is created
+	snap := pb.Snapshot()
+	if len(snap) != 2 || snap["|BTC"].UnrealizedPnLMicros != 10 || snap["UPBIT|BTC"].MarkPriceMicros != 140_000 {
+		t.Errorf("venues must be kept apart: %+v", snap)
+	}
+	pb.VerifyAll()
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "crypto_go/pkg/quant"
+
+// Sentiment indices.
+const (
+	// SentimentFearGreed is the Crypto Fear & Greed index: Value 0 (extreme fear)
+	// to 100 (extreme greed), with the provider's Label (e.g. "Extreme Fear").
+	SentimentFearGreed = "FEAR_GREED"
+	// SentimentFunding is the open-interest-weighted perpetual funding rate across
+	// the watched futures: Value in micros (0.0001 = 100). Positive = longs pay.
+	SentimentFunding = "FUNDING"
+)
+
+// Sentiment is one reading of a market sentiment index.
+type Sentiment struct {
+	Source string          `json:"source"`          // Provider, e.g. "SENTIMENT"
+	Index  string          `json:"index"`           // Sentiment* constant
+	Value  int64           `json:"value"`           // Index scale (see the Sentiment* constants)
+	Label  string          `json:"label,omitempty"` // Provider classification, if any
+	Ts     quant.TimeStamp `json:"ts"`              // Observation time reported by the provider
+}
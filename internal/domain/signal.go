@@ -0,0 +1,31 @@
+package domain
+
+import "crypto_go/pkg/quant"
+
+// Signal actions: what an external signal recommends for its symbol.
+const (
+	SignalBuy   = "BUY"
+	SignalSell  = "SELL"
+	SignalClose = "CLOSE" // Exit the position
+)
+
+// IsSignalAction reports whether action is a Signal* constant.
+func IsSignalAction(action string) bool {
+	switch action {
+	case SignalBuy, SignalSell, SignalClose:
+		return true
+	}
+	return false
+}
+
+// Signal is an external trading signal (TradingView alert, custom script)
+// received over the signal webhook.
+type Signal struct {
+	Origin      string            `json:"origin"` // Sender label, e.g. "tradingview"
+	Name        string            `json:"name,omitempty"`
+	Symbol      string            `json:"symbol"`
+	Action      string            `json:"action"`                 // Signal* constant
+	PriceMicros quant.PriceMicros `json:"price_micros,omitempty"` // Price seen by the sender (0 = not given)
+	Note        string            `json:"note,omitempty"`
+	Ts          quant.TimeStamp   `json:"ts"` // Receive time
+}
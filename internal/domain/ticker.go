@@ -45,6 +45,21 @@ func (m *MarketData) GapPct() int64 {
 	return safe.SafeDiv(num, int64(m.BitgetS.PriceMicros))
 }
 
+// KimchiPremiumMicros returns how far the domestic KRW price sits above the foreign
+// USDT price converted at usdKRW, in Micros (1% = 10,000). ok is false if any input
+// is non-positive. KRW prices overflow int64 when scaled, hence SafeMulDiv.
+func KimchiPremiumMicros(domesticKRW, foreignUSDT, usdKRW quant.PriceMicros) (premium int64, ok bool) {
+	if domesticKRW <= 0 || foreignUSDT <= 0 || usdKRW <= 0 {
+		return 0, false
+	}
+	foreignKRW := safe.SafeMulDiv(int64(foreignUSDT), int64(usdKRW), quant.PriceScale)
+	if foreignKRW == 0 {
+		return 0, false
+	}
+	diff := safe.SafeSub(int64(domesticKRW), foreignKRW)
+	return safe.SafeMulDiv(diff, quant.PriceScale, foreignKRW), true
+}
+
 // ChangeDirection returns "positive", "negative", or "neutral"
 func (m *MarketData) ChangeDirection() string {
 	if m.Upbit == nil {
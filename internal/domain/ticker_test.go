@@ -39,3 +39,21 @@ func TestMarketData_GapPct(t *testing.T) {
 		}
 	})
 }
+
+func TestKimchiPremiumMicros(t *testing.T) {
+	// 103,000,000 KRW vs 70,000 USDT * 1,400 KRW = 98,000,000 KRW -> +5.102%
+	premium, ok := KimchiPremiumMicros(103_000_000*quant.PriceScale, 70_000*quant.PriceScale, 1_400*quant.PriceScale)
+	if !ok || premium != 51_020 {
+		t.Errorf("expected 51,020 micros, got %d (%v)", premium, ok)
+	}
+
+	// Reverse premium
+	premium, _ = KimchiPremiumMicros(97_020_000*quant.PriceScale, 70_000*quant.PriceScale, 1_400*quant.PriceScale)
+	if premium != -10_000 {
+		t.Errorf("expected -10,000 micros, got %d", premium)
+	}
+
+	if _, ok := KimchiPremiumMicros(1, 0, 1); ok {
+		t.Error("missing foreign price must not produce a premium")
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "crypto_go/pkg/quant"
+
+// Timer is one tick of a named interval timer, e.g. every minute for bar
+// closes. Ts is the scheduled boundary (a multiple of the interval).
+type Timer struct {
+	Name       string          `json:"name"`
+	IntervalMs int64           `json:"interval_ms"`
+	Tick       uint64          `json:"tick"` // Ts / interval: consecutive ticks differ by 1
+	Ts         quant.TimeStamp `json:"ts"`
+}
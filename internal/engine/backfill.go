@@ -0,0 +1,238 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BackfillSource is the stored event log a dry-run backfill reads
+// (storage.EventStore).
+type BackfillSource interface {
+	LoadEventsBatch(ctx context.Context, fromSeq uint64, limit int) ([]event.Event, error)
+	SeqAt(ctx context.Context, ts quant.TimeStamp) (uint64, error)
+}
+
+// BackfillReport summarizes what a strategy would have done over a stretch
+// of stored data. Orders fill at their reference price without fees, so
+// PnLMicros is a sanity check, not a backtest.
+type BackfillReport struct {
+	From      quant.TimeStamp
+	To        quant.TimeStamp
+	Events    int
+	Buys      int
+	Sells     int
+	PnLMicros int64 // Fills marked to each symbol's last price (quote currency micros)
+}
+
+// String formats the report for the audit trail.
+func (r *BackfillReport) String() string {
+	window := time.Duration(r.To-r.From) * time.Microsecond
+	return fmt.Sprintf("backfill %s: %d events, %d orders (%d buy / %d sell), pnl %s",
+		window.Round(time.Minute), r.Events, r.Buys+r.Sells, r.Buys, r.Sells, quant.PriceMicros(r.PnLMicros))
+}
+
+// Backfill feeds the market data stored between from and to through strat on
+// a scratch sequencer that never sends orders, and reports the orders it
+// emitted. Control, halt, resync, operator order and order events are skipped: they concern
+// the live strategy, not this one.
+func Backfill(ctx context.Context, strat strategy.Strategy, src BackfillSource, from, to quant.TimeStamp) (*BackfillReport, error) {
+	next, err := src.SeqAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	rec := &backfillRecorder{
+		StrategyRegistry: strategy.NewStrategyRegistry(),
+		last:             make(map[string]int64),
+		held:             make(map[string]int64),
+	}
+	if err := rec.Register("backfill", strat); err != nil {
+		return nil, err
+	}
+	seq := NewSequencer(1, nil, rec, nil)
+	seq.SetTradingEnabled(false)
+	seq.SetClock(quant.NewSimClock(from))
+
+	report := &BackfillReport{From: from, To: to}
+	for {
+		events, err := src.LoadEventsBatch(ctx, next, DefaultFollowerBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read WAL: %w", err)
+		}
+		for _, ev := range events {
+			if ev.GetTs() > to {
+				return rec.finish(report), nil
+			}
+			next = ev.GetSeq() + 1
+			switch ev.(type) {
+			case *event.ControlEvent, *event.HaltEvent, *event.ResyncEvent, *event.ManualOrderEvent, *event.OrderUpdateEvent, *event.OrderRejectedEvent:
+				continue
+			}
+			if err := backfillDispatch(seq, ev); err != nil {
+				return nil, fmt.Errorf("seq %d: %w", ev.GetSeq(), err)
+			}
+			report.Events++
+		}
+		if len(events) < DefaultFollowerBatchSize {
+			return rec.finish(report), nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backfillDispatch applies ev to the scratch sequencer; a panicking
+// candidate strategy becomes an error instead of taking the caller down.
+func backfillDispatch(seq *Sequencer, ev event.Event) (err error) {
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("strategy panicked: %v", r)
+		}
+	}()
+	seq.dispatch(ev, true)
+	return nil
+}
+
+// backfillRecorder runs the backfilled strategy (through a one-entry
+// registry, which forwards every optional handler) and books its orders.
+type backfillRecorder struct {
+	*strategy.StrategyRegistry
+	buys, sells int
+	cash        int64            // Quote micros
+	held        map[string]int64 // Net sats by symbol
+	last        map[string]int64 // Last price by symbol
+}
+
+func (r *backfillRecorder) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	r.last[state.Symbol] = int64(state.PriceMicros)
+	return r.book(out, r.StrategyRegistry.OnMarketUpdate(state, out))
+}
+
+func (r *backfillRecorder) OnCandleClose(candle domain.Candle, out []domain.Order) int {
+	r.last[candle.Symbol] = int64(candle.CloseMicros)
+	return r.book(out, r.StrategyRegistry.OnCandleClose(candle, out))
+}
+
+func (r *backfillRecorder) OnSignal(signal domain.Signal, out []domain.Order) int {
+	return r.book(out, r.StrategyRegistry.OnSignal(signal, out))
+}
+
+func (r *backfillRecorder) OnFundingSoon(epoch domain.FundingEpoch, out []domain.Order) int {
+	return r.book(out, r.StrategyRegistry.OnFundingSoon(epoch, out))
+}
+
+func (r *backfillRecorder) OnFundingRate(epoch domain.FundingEpoch, out []domain.Order) int {
+	return r.book(out, r.StrategyRegistry.OnFundingRate(epoch, out))
+}
+
+func (r *backfillRecorder) OnTimer(timer domain.Timer, out []domain.Order) int {
+	return r.book(out, r.StrategyRegistry.OnTimer(timer, out))
+}
+
+// book fills the n orders in out at their reference price.
+func (r *backfillRecorder) book(out []domain.Order, n int) int {
+	for _, o := range out[:n] {
+		price := o.PriceMicros
+		if price == 0 {
+			price = r.last[o.Symbol]
+		}
+		notional := safe.SafeMulDiv(price, o.QtySats, 100_000_000)
+		switch o.Side {
+		case domain.SideBuy:
+			r.buys++
+			r.cash = safe.SafeSub(r.cash, notional)
+			r.held[o.Symbol] = safe.SafeAdd(r.held[o.Symbol], o.QtySats)
+		case domain.SideSell:
+			r.sells++
+			r.cash = safe.SafeAdd(r.cash, notional)
+			r.held[o.Symbol] = safe.SafeSub(r.held[o.Symbol], o.QtySats)
+		}
+	}
+	return n
+}
+
+func (r *backfillRecorder) finish(report *BackfillReport) *BackfillReport {
+	pnl := r.cash
+	for symbol, qty := range r.held {
+		pnl = safe.SafeAdd(pnl, safe.SafeMulDiv(r.last[symbol], qty, 100_000_000))
+	}
+	report.Buys, report.Sells, report.PnLMicros = r.buys, r.sells, pnl
+	return report
+}
+
+// paramChange is one strategy parameter change.
+type paramChange struct {
+	key   string
+	value int64
+}
+
+// Backfiller reviews strategy parameter changes (ControlClient.SetParamReview)
+// with a dry-run backfill: it builds a fresh strategy, applies every change
+// reviewed so far plus the new one, and runs it over the last window of
+// stored data. The report goes into the change's audit entry; a parameter
+// the strategy refuses stops the change before it reaches the engine.
+// Changes made before a restart are not re-applied to the candidates.
+type Backfiller struct {
+	src    BackfillSource
+	build  func() (strategy.Strategy, error)
+	window time.Duration
+	clock  quant.Clock
+
+	mu      sync.Mutex
+	changes []paramChange
+}
+
+// NewBackfiller creates a reviewer building candidates with build and
+// replaying window of src.
+func NewBackfiller(src BackfillSource, build func() (strategy.Strategy, error), window time.Duration) *Backfiller {
+	return &Backfiller{src: src, build: build, window: window, clock: quant.RealClock{}}
+}
+
+// SetClock replaces the clock that ends the window (default quant.RealClock).
+func (b *Backfiller) SetClock(clock quant.Clock) {
+	b.clock = clock
+}
+
+// Review implements ParamReview. A failed backfill does not stop the change:
+// its error becomes the report.
+func (b *Backfiller) Review(ctx context.Context, key string, value int64) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	candidate, err := b.build()
+	if err != nil {
+		return "", err
+	}
+	t, ok := candidate.(strategy.Tunable)
+	if !ok {
+		return "", fmt.Errorf("%w: strategy is not tunable", strategy.ErrInvalidParam)
+	}
+	for _, c := range b.changes {
+		if err := t.SetParam(c.key, c.value); err != nil {
+			return "", err
+		}
+	}
+	if err := t.SetParam(key, value); err != nil {
+		return "", err
+	}
+	b.changes = append(b.changes, paramChange{key, value})
+
+	to := quant.Stamp(b.clock)
+	report, err := Backfill(ctx, candidate, b.src, to-quant.TimeStamp(b.window.Microseconds()), to)
+	if err != nil {
+		slog.Warn("BACKFILL_FAILED", slog.String("param", key), slog.Any("error", err))
+		return "backfill failed: " + err.Error(), nil
+	}
+	slog.Info("BACKFILL_DONE", slog.String("param", key), slog.Int64("value", value), slog.String("report", report.String()))
+	return report.String(), nil
+}
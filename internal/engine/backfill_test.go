@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+)
+
+// tunableStub records the parameter changes the sequencer applies.
+type tunableStub struct {
+	params map[string]int64
+}
+
+func (s *tunableStub) OnMarketUpdate(domain.MarketState, []domain.Order) int { return 0 }
+func (s *tunableStub) OnOrderUpdate(domain.Order)                            {}
+func (s *tunableStub) SetParam(key string, value int64) error {
+	if value <= 0 {
+		return strategy.ErrInvalidParam
+	}
+	s.params[key] = value
+	return nil
+}
+
+func TestBackfiller_ReviewsParamChange(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/events.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	live := NewSequencer(100, store, nil, nil)
+	// Falls, then turns up: one golden cross for a fast enough short SMA
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for i, p := range []int64{100, 99, 98, 97, 96, 97, 99, 102, 106} {
+		ts := start.Add(time.Duration(i) * time.Minute).UnixMicro()
+		live.ProcessEventForTest(followerMarketEvent("BTC", p*1_000_000, ts))
+	}
+	live.ProcessEventForTest(&event.ControlEvent{BaseEvent: event.BaseEvent{Ts: quant.TimeStamp(start.Add(9 * time.Minute).UnixMicro())}, Command: event.CmdHalt})
+
+	build := func() (strategy.Strategy, error) { return strategy.NewSMACrossStrategy("BTC", 3, 8), nil }
+	b := NewBackfiller(store, build, time.Hour)
+	b.SetClock(quant.NewSimClock(quant.TimeStamp(start.Add(30 * time.Minute).UnixMicro())))
+
+	ctx := context.Background()
+	if _, err := b.Review(ctx, strategy.ParamLongPeriod, 2); !errors.Is(err, strategy.ErrInvalidParam) {
+		t.Fatalf("long_period below short_period: err = %v; want ErrInvalidParam", err)
+	}
+	report, err := b.Review(ctx, strategy.ParamLongPeriod, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "9 events, 1 orders (1 buy / 0 sell)"; !strings.Contains(report, want) {
+		t.Errorf("report %q; want %q", report, want)
+	}
+	// Earlier changes carry over to the next candidate
+	if report, _ = b.Review(ctx, strategy.ParamShortPeriod, 2); !strings.Contains(report, "(1 buy / 0 sell)") {
+		t.Errorf("report %q; want the golden cross of SMA 2/4", report)
+	}
+
+	// The report rides on the control event into the engine
+	inbox := make(chan event.Event, 1)
+	client := NewControlClient(inbox)
+	client.SetParamReview(b.Review)
+	if err := client.Send(ctx, event.CmdSetStrategyParam, strategy.ParamShortPeriod, 3, "tighter"); err != nil {
+		t.Fatal(err)
+	}
+	ce := (<-inbox).(*event.ControlEvent)
+	if !strings.HasPrefix(ce.Report, "backfill 1h0m0s: 9 events") || ce.Reason != "tighter" {
+		t.Errorf("control event %+v; want the backfill report attached", ce)
+	}
+	if err := client.Send(ctx, event.CmdSetStrategyParam, strategy.ParamShortPeriod, 9, ""); !errors.Is(err, strategy.ErrInvalidParam) || len(inbox) != 0 {
+		t.Errorf("invalid change: err = %v, %d sent; want ErrInvalidParam and nothing sent", err, len(inbox))
+	}
+}
+
+func TestControl_SetStrategyParam(t *testing.T) {
+	stub := &tunableStub{params: map[string]int64{}}
+	seq := NewSequencer(10, nil, stub, nil)
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdSetStrategyParam, Target: "period", Value: 21})
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdSetStrategyParam, Target: "period", Value: -1})
+	if stub.params["period"] != 21 {
+		t.Errorf("period = %d; want 21 (the invalid change ignored)", stub.params["period"])
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+)
+
+type candleKey struct{ exchange, symbol, interval string }
+
+// handleCandle folds a kline push into the exchange/symbol/interval bar. Venues
+// re-send the open bar as it trades; a push with a later OpenTime means the
+// previous bar is final, and only then is it handed to a bar-based strategy.
+// Pushes for older bars (e.g. history replayed after a reconnect) are ignored.
+func (s *Sequencer) handleCandle(e *event.CandleEvent) {
+	key := candleKey{e.Exchange, e.Symbol, e.Interval}
+	cur, ok := s.candles[key]
+	if !ok {
+		// Cold path: New series allocation
+		c := e.Candle()
+		s.candles[key] = &c
+		return
+	}
+	if e.OpenTime < cur.OpenTime {
+		return
+	}
+	if e.OpenTime == cur.OpenTime {
+		*cur = e.Candle()
+		return
+	}
+
+	closed := *cur
+	*cur = e.Candle()
+
+	if h, ok := s.strategy.(strategy.CandleHandler); ok && !s.strategyPaused {
+		count := h.OnCandleClose(closed, s.orderBuf[:])
+		for i := 0; i < count; i++ {
+			s.handleStrategyAction(&s.orderBuf[i], e.Ts)
+		}
+	}
+}
+
+// GetCandle returns the current (in-progress) bar of exchange/symbol/interval (thread-safe).
+func (s *Sequencer) GetCandle(exchange, symbol, interval string) (domain.Candle, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.candles[candleKey{exchange, symbol, interval}]
+	if !ok {
+		return domain.Candle{}, false
+	}
+	return *c, true
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+type barStrategy struct {
+	countingStrategy
+	closed []domain.Candle
+}
+
+func (s *barStrategy) OnCandleClose(c domain.Candle, out []domain.Order) int {
+	s.closed = append(s.closed, c)
+	return 0
+}
+
+func candleEvent(exchange string, open quant.TimeStamp, closePrice int64) *event.CandleEvent {
+	return &event.CandleEvent{Exchange: exchange, Symbol: "BTC", Interval: domain.Candle1m,
+		OpenTime: open, CloseMicros: quant.PriceMicros(closePrice)}
+}
+
+func TestSequencer_CandleClose(t *testing.T) {
+	strat := &barStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	const minute = 60_000_000
+
+	seq.ProcessEventForTest(candleEvent("UPBIT", minute, 100))
+	seq.ProcessEventForTest(candleEvent("UPBIT", minute, 105)) // Open bar re-sent: updated in place
+	seq.ProcessEventForTest(candleEvent("BITGET_SPOT", 2*minute, 70))
+	if len(strat.closed) != 0 {
+		t.Fatalf("no bar may close before the next one starts: %+v", strat.closed)
+	}
+	if c, ok := seq.GetCandle("UPBIT", "BTC", domain.Candle1m); !ok || c.CloseMicros != 105 {
+		t.Fatalf("open bar = %+v, %v", c, ok)
+	}
+
+	seq.ProcessEventForTest(candleEvent("UPBIT", 2*minute, 106))
+	seq.ProcessEventForTest(candleEvent("UPBIT", minute, 1)) // Stale history: ignored
+	if len(strat.closed) != 1 || strat.closed[0].CloseMicros != 105 || strat.closed[0].OpenTime != minute {
+		t.Fatalf("closed bars = %+v", strat.closed)
+	}
+	if c, _ := seq.GetCandle("UPBIT", "BTC", domain.Candle1m); c.CloseMicros != 106 {
+		t.Errorf("stale push changed the open bar: %+v", c)
+	}
+}
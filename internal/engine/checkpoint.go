@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrStateHashMismatch means a replay rebuilt different state than the live
+// run had at a snapshot: the event-sourced core is not deterministic.
+var ErrStateHashMismatch = errors.New("state hash mismatch")
+
+// loadCheckpoints reads the state hashes recorded by live snapshots, to be
+// checked when replay reaches their TRIGGER_SNAPSHOT.
+func (s *Sequencer) loadCheckpoints() error {
+	if s.snapshots == nil {
+		return nil
+	}
+	hashes, err := s.snapshots.LoadHashes()
+	if err != nil {
+		return err
+	}
+	s.checkpoints = hashes
+	return nil
+}
+
+// verifyCheckpoint compares the replayed state at a snapshot seq with the hash
+// the live run saved there (caller holds s.mu). The first mismatch is kept for
+// RecoverFromWAL; it is not a panic, which would quarantine the snapshot event
+// instead of stopping the recovery.
+func (s *Sequencer) verifyCheckpoint(seq uint64) {
+	want, ok := s.checkpoints[seq]
+	if !ok {
+		return
+	}
+	got := s.stateHashLocked()
+	if got == want {
+		s.checkpointsVerified++
+		return
+	}
+	slog.Error("STATE_HASH_MISMATCH", slog.Uint64("seq", seq), slog.String("live", want), slog.String("replay", got))
+	if s.checkpointErr == nil {
+		s.checkpointErr = fmt.Errorf("replay diverged at snapshot seq %d (live %s, replay %s): %w", seq, want, got, ErrStateHashMismatch)
+	}
+}
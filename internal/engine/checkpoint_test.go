@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+)
+
+func TestSequencer_ReplayVerifiesSnapshotHashes(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/checkpoint.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	snapDir := t.TempDir()
+	live := NewSequencer(100, store, nil, nil)
+	live.SetSnapshotManager(storage.NewSnapshotManager(snapDir))
+	live.ProcessEventForTest(tick("UPBIT", 100))
+	live.ProcessEventForTest(&event.ControlEvent{Command: event.CmdSetRiskLimit, Target: RiskLimitMaxOrderQtySats, Value: 5})
+	live.ProcessEventForTest(&event.ControlEvent{Command: event.CmdTriggerSnapshot})
+	live.ProcessEventForTest(tick("UPBIT", 101))
+
+	recovered := NewSequencer(100, store, nil, nil)
+	recovered.SetSnapshotManager(storage.NewSnapshotManager(snapDir))
+	if err := recovered.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatalf("a deterministic replay must pass: %v", err)
+	}
+	if recovered.checkpointsVerified != 1 || recovered.StateHash() != live.StateHash() {
+		t.Errorf("expected 1 verified snapshot and the live end state, got %d", recovered.checkpointsVerified)
+	}
+
+	// A live run whose state the WAL cannot reproduce (here: a doctored hash)
+	snap, _ := storage.NewSnapshotManager(snapDir).LoadLatest()
+	files, _ := filepath.Glob(filepath.Join(snapDir, "snapshot_3_*.json"))
+	if snap == nil || snap.StateHash == "" || len(files) != 1 {
+		t.Fatalf("expected a hashed snapshot at seq 3, got %+v", snap)
+	}
+	data, _ := os.ReadFile(files[0])
+	doctored := strings.Replace(string(data), snap.StateHash, strings.Repeat("0", len(snap.StateHash)), 1)
+	if err := os.WriteFile(files[0], []byte(doctored), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diverged := NewSequencer(100, store, nil, nil)
+	diverged.SetSnapshotManager(storage.NewSnapshotManager(snapDir))
+	if err := diverged.RecoverFromWAL(context.Background()); !errors.Is(err, ErrStateHashMismatch) {
+		t.Fatalf("expected ErrStateHashMismatch, got %v", err)
+	}
+}
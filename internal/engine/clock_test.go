@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+type clockStrategy struct {
+	countingStrategy
+	clock quant.Clock
+	seen  []quant.TimeStamp
+}
+
+func (s *clockStrategy) SetClock(clock quant.Clock) { s.clock = clock }
+
+func (s *clockStrategy) OnMarketUpdate(domain.MarketState, []domain.Order) int {
+	s.seen = append(s.seen, quant.Stamp(s.clock))
+	return 0
+}
+
+func TestSequencer_SimClockFollowsEvents(t *testing.T) {
+	store, err := storage.NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	live := &clockStrategy{}
+	seq := NewSequencer(10, store, live, nil)
+	if _, ok := live.clock.(quant.RealClock); !ok {
+		t.Fatalf("default clock %T; want quant.RealClock", live.clock)
+	}
+	seq.SetClock(quant.NewSimClock(0))
+	budget := NewStrategyBudget("clock", time.Nanosecond, 1, nil, nil)
+	seq.SetStrategyBudget(budget)
+	for _, ts := range []int64{1_000, 2_000, 1_500, 3_000} {
+		seq.ProcessEventForTest(mkt("UPBIT", ts, 100))
+	}
+	want := []quant.TimeStamp{1_000, 2_000, 2_000, 3_000} // Late event: time never runs back
+	if !slices.Equal(live.seen, want) {
+		t.Fatalf("strategy saw %v; want %v", live.seen, want)
+	}
+	if budget.Overruns() != 0 {
+		t.Errorf("simulated time must not overrun the budget: %d overruns", budget.Overruns())
+	}
+
+	// Replay, whenever it runs: the same clock readings
+	replayed := &clockStrategy{}
+	recovered := NewSequencer(10, store, replayed, nil)
+	recovered.SetClock(quant.NewSimClock(0))
+	if err := recovered.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(replayed.seen, want) {
+		t.Fatalf("replay saw %v; want %v", replayed.seen, want)
+	}
+}
@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+)
+
+type contextKey struct{ metric, subject string }
+
+// handleContext records the latest reading of a context metric. Providers are
+// polled and may resend the same day's reading (e.g. after a restart); only a
+// reading newer than the one held is passed to the strategy.
+func (s *Sequencer) handleContext(e *event.ContextEvent) {
+	key := contextKey{e.Metric, e.Subject}
+	if cur, ok := s.contexts[key]; ok && e.Ts <= cur.Ts {
+		return
+	}
+	m := e.Context()
+	s.contexts[key] = m
+
+	if h, ok := s.strategy.(strategy.ContextHandler); ok && !s.strategyPaused {
+		h.OnContext(m)
+	}
+}
+
+// GetContextMetric returns the latest reading of metric for subject ("" =
+// market-wide) (thread-safe).
+func (s *Sequencer) GetContextMetric(metric, subject string) (domain.ContextMetric, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.contexts[contextKey{metric, subject}]
+	return m, ok
+}
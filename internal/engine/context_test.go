@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+type contextStrategy struct {
+	countingStrategy
+	seen []domain.ContextMetric
+}
+
+func (s *contextStrategy) OnContext(m domain.ContextMetric) {
+	s.seen = append(s.seen, m)
+}
+
+func contextEvent(metric, subject string, day quant.TimeStamp, value int64) *event.ContextEvent {
+	ev := &event.ContextEvent{Source: "ONCHAIN", Metric: metric, Subject: subject, ValueMicros: value}
+	ev.Ts = day
+	return ev
+}
+
+func TestSequencer_ContextMetric(t *testing.T) {
+	strat := &contextStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	const day = 86_400_000_000
+
+	seq.ProcessEventForTest(contextEvent(domain.MetricExchangeNetflow, "BINANCE", day, -5))
+	seq.ProcessEventForTest(contextEvent(domain.MetricExchangeNetflow, "OKX", day, 7))
+	seq.ProcessEventForTest(contextEvent(domain.MetricExchangeNetflow, "BINANCE", day, -5)) // Re-polled: ignored
+	seq.ProcessEventForTest(contextEvent(domain.MetricExchangeNetflow, "BINANCE", 2*day, 3))
+
+	if len(strat.seen) != 3 {
+		t.Fatalf("expected 3 readings passed to the strategy, got %+v", strat.seen)
+	}
+	m, ok := seq.GetContextMetric(domain.MetricExchangeNetflow, "BINANCE")
+	if !ok || m.ValueMicros != 3 || m.Ts != 2*day || m.Source != "ONCHAIN" {
+		t.Errorf("latest BINANCE netflow = %+v, %v", m, ok)
+	}
+	if _, ok := seq.GetContextMetric(domain.MetricStablecoinSupply, ""); ok {
+		t.Error("unexpected reading for a metric never received")
+	}
+}
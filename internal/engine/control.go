@@ -0,0 +1,206 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"log/slog"
+	"sync"
+)
+
+// ControlSource is the sequence-validation source name for control events.
+const ControlSource = "CONTROL"
+
+// Risk limit keys understood by the sequencer (ControlEvent.Target for CmdSetRiskLimit).
+// The keys of risk.SetLimit change the OMS risk engine's limits as well.
+const (
+	RiskLimitMaxOrderQtySats = "max_order_qty_sats" // Orders above this quantity are not dispatched
+)
+
+// SetSnapshotManager enables CmdTriggerSnapshot. Must be called before Run.
+func (s *Sequencer) SetSnapshotManager(sm *storage.SnapshotManager) {
+	s.snapshots = sm
+}
+
+// handleControl applies an administrative command. Runs on the hotpath goroutine
+// (caller holds s.mu). During replay, side effects outside the engine state
+// (snapshot files) are skipped; state changes are re-applied identically,
+// so a Halt survives restarts until an explicit CmdResumeTrading.
+func (s *Sequencer) handleControl(e *event.ControlEvent, replay bool) {
+	if !replay {
+		slog.Warn("CONTROL_COMMAND",
+			slog.String("command", e.Command.String()),
+			slog.String("target", e.Target),
+			slog.Int64("value", e.Value),
+			slog.String("reason", e.Reason),
+			slog.String("report", e.Report),
+			slog.Uint64("seq", e.Seq))
+	}
+
+	switch e.Command {
+	case event.CmdPauseStrategy:
+		// Single strategy today; Target is recorded for the audit trail.
+		s.strategyPaused = true
+	case event.CmdResumeStrategy:
+		s.strategyPaused = false
+		if s.strategyBudget != nil {
+			s.strategyBudget.Reset() // Give it a fresh budget
+		}
+	case event.CmdSetRiskLimit:
+		s.riskLimits[e.Target] = e.Value
+		if s.orders != nil && s.orders.risk != nil {
+			s.orders.risk.SetLimit(e.Target, e.Value)
+		}
+	case event.CmdTriggerSnapshot:
+		if replay {
+			s.verifyCheckpoint(e.Seq)
+			return
+		}
+		s.sinceSnapshot, s.snapshotRequested = 0, false
+		if s.snapshots == nil {
+			return
+		}
+		// Snapshot seq = this command's seq: state reflects every event before it.
+		snap, err := s.captureSnapshot(e.Seq)
+		if err == nil {
+			err = s.snapshots.Save(snap)
+		}
+		if err != nil {
+			slog.Error("SNAPSHOT_FAILED", slog.Any("error", err))
+		}
+	case event.CmdHalt:
+		s.halted = true
+	case event.CmdResumeTrading:
+		s.halted = false
+		s.resetDrawdown()
+	case event.CmdHandover:
+		s.handover = true
+	case event.CmdTakeover:
+		s.handover = false
+	case event.CmdReduceOnly:
+		s.enterReduceOnly(e, replay)
+	case event.CmdLiftReduceOnly:
+		s.liftReduceOnly(e.Target)
+	case event.CmdSetStrategyParam:
+		s.setStrategyParam(e)
+	}
+}
+
+// setStrategyParam applies a parameter change to a Tunable strategy. A
+// change the strategy refuses is logged and ignored, on replay too.
+func (s *Sequencer) setStrategyParam(e *event.ControlEvent) {
+	t, ok := s.strategy.(strategy.Tunable)
+	if !ok {
+		slog.Warn("STRATEGY_PARAM_REJECTED", slog.String("param", e.Target), slog.String("error", "strategy is not tunable"))
+		return
+	}
+	if err := t.SetParam(e.Target, e.Value); err != nil {
+		slog.Warn("STRATEGY_PARAM_REJECTED", slog.String("param", e.Target), slog.Int64("value", e.Value), slog.Any("error", err))
+	}
+}
+
+// StrategyPaused reports whether the strategy is paused by a control command.
+func (s *Sequencer) StrategyPaused() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.strategyPaused
+}
+
+// Halted reports whether a Halt command or the kill switch stopped order
+// dispatch (until CmdResumeTrading).
+func (s *Sequencer) Halted() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.halted
+}
+
+// RiskLimit returns the operator-set limit for key.
+func (s *Sequencer) RiskLimit(key string) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.riskLimits[key]
+	return v, ok
+}
+
+// ControlClient submits control events into the sequencer inbox.
+// It owns the CONTROL source sequence, so several goroutines may share one client.
+type ControlClient struct {
+	inbox  chan<- event.Event
+	mu     sync.Mutex
+	seq    uint64
+	clock  quant.Clock
+	review ParamReview
+}
+
+// ParamReview checks a strategy parameter change before it is sent and
+// returns the report attached to its ControlEvent (e.g. Backfiller.Review).
+// An error stops the change.
+type ParamReview func(ctx context.Context, key string, value int64) (string, error)
+
+// NewControlClient creates a client sending to inbox (Sequencer.Inbox or a spillover front).
+func NewControlClient(inbox chan<- event.Event) *ControlClient {
+	return &ControlClient{inbox: inbox, clock: quant.RealClock{}}
+}
+
+// SetClock replaces the clock that stamps events (default quant.RealClock).
+func (c *ControlClient) SetClock(clock quant.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// SetParamReview runs review on every CmdSetStrategyParam before it is sent.
+func (c *ControlClient) SetParamReview(review ParamReview) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.review = review
+}
+
+// Send enqueues a command. Unlike market data, control events are never dropped:
+// Send blocks until the inbox accepts the event or ctx is done. A
+// CmdSetStrategyParam is reviewed first (see SetParamReview).
+func (c *ControlClient) Send(ctx context.Context, cmd event.ControlCommand, target string, value int64, reason string) error {
+	var report string
+	if cmd == event.CmdSetStrategyParam {
+		c.mu.Lock()
+		review := c.review
+		c.mu.Unlock()
+		if review != nil {
+			var err error
+			if report, err = review(ctx, target, value); err != nil {
+				return err
+			}
+		}
+	}
+	return c.deliver(ctx, func(base event.BaseEvent) event.Event {
+		return &event.ControlEvent{BaseEvent: base, Command: cmd, Target: target, Value: value, Reason: reason, Report: report}
+	})
+}
+
+// Kill triggers the kill switch: a HaltEvent (event.Halt* reason) that cancels
+// every open order and stops order dispatch until CmdResumeTrading. It blocks
+// like Send.
+func (c *ControlClient) Kill(ctx context.Context, reason, detail string) error {
+	return c.deliver(ctx, func(base event.BaseEvent) event.Event {
+		return &event.HaltEvent{BaseEvent: base, Reason: reason, Detail: detail}
+	})
+}
+
+// deliver stamps the next CONTROL seq onto the event built by build and sends it.
+func (c *ControlClient) deliver(ctx context.Context, build func(event.BaseEvent) event.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock() // Held across the send so seq order == inbox order
+
+	c.seq++
+	ev := build(event.BaseEvent{Seq: c.seq, Ts: quant.Stamp(c.clock)})
+
+	select {
+	case c.inbox <- ev:
+		return nil
+	case <-ctx.Done():
+		c.seq-- // Not delivered; keep the source sequence contiguous
+		return ctx.Err()
+	}
+}
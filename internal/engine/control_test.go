@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+)
+
+func TestControl_ReplayRestoresAdminState(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/control.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	snapDir := t.TempDir()
+	seq1 := NewSequencer(100, store, nil, nil)
+	seq1.SetSnapshotManager(storage.NewSnapshotManager(snapDir))
+
+	seq1.ProcessEventForTest(&event.ControlEvent{Command: event.CmdPauseStrategy, Target: "sma_cross"})
+	seq1.ProcessEventForTest(&event.ControlEvent{Command: event.CmdSetRiskLimit, Target: RiskLimitMaxOrderQtySats, Value: 50_000_000})
+	seq1.ProcessEventForTest(&event.ControlEvent{Command: event.CmdTriggerSnapshot})
+	seq1.ProcessEventForTest(&event.ControlEvent{Command: event.CmdHalt, Reason: "manual"})
+
+	if !seq1.StrategyPaused() || !seq1.Halted() {
+		t.Fatal("expected paused and halted after control events")
+	}
+	if snap, _ := storage.NewSnapshotManager(snapDir).LoadLatest(); snap == nil || snap.Seq != 3 {
+		t.Errorf("expected snapshot at seq 3, got %+v", snap)
+	}
+
+	// Replay must rebuild the same administrative state from the WAL
+	seq2 := NewSequencer(100, store, nil, nil)
+	if err := seq2.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatalf("RecoverFromWAL failed: %v", err)
+	}
+	if !seq2.StrategyPaused() || !seq2.Halted() {
+		t.Error("replay lost paused/halted state")
+	}
+	if v, ok := seq2.RiskLimit(RiskLimitMaxOrderQtySats); !ok || v != 50_000_000 {
+		t.Errorf("replay lost risk limit: %d, %v", v, ok)
+	}
+	if seq2.GetNextSeq() != 5 {
+		t.Errorf("expected nextSeq=5, got %d", seq2.GetNextSeq())
+	}
+
+	// Resume is ordered like any other event
+	seq2.ProcessEventForTest(&event.ControlEvent{Command: event.CmdResumeStrategy})
+	seq2.ProcessEventForTest(&event.ControlEvent{Command: event.CmdResumeTrading, Reason: "checked"})
+	if seq2.StrategyPaused() || seq2.Halted() {
+		t.Error("expected strategy resumed and halt lifted")
+	}
+
+	// The lifted halt is durable too
+	seq3 := NewSequencer(100, store, nil, nil)
+	if err := seq3.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatalf("RecoverFromWAL failed: %v", err)
+	}
+	if seq3.Halted() {
+		t.Error("replay re-applied a halt that was lifted")
+	}
+}
+
+func TestControlClient_SendAndCancel(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	c := NewControlClient(inbox)
+
+	if err := c.Send(context.Background(), event.CmdHalt, "", 0, "test"); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	// Inbox full: Send blocks until ctx expires instead of dropping
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.Send(ctx, event.CmdPauseStrategy, "", 0, ""); err == nil {
+		t.Fatal("expected ctx error on full inbox")
+	}
+
+	ev := (<-inbox).(*event.ControlEvent)
+	if ev.Command != event.CmdHalt || ev.Seq != 1 {
+		t.Errorf("unexpected control event: %+v", ev)
+	}
+
+	// Sequence stays contiguous after the failed send
+	c.Send(context.Background(), event.CmdResumeStrategy, "", 0, "")
+	if ev := (<-inbox).(*event.ControlEvent); ev.Seq != 2 {
+		t.Errorf("expected seq 2, got %d", ev.Seq)
+	}
+}
@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// fatalPanicPrefixes are never quarantined: a broken balance invariant means the
+// state itself is wrong, and skipping the event would only hide it (Rule #8).
+var fatalPanicPrefixes = []string{"BALANCE_"}
+
+// SetDeadLetterPolicy enables poison-event quarantine. A failing WAL write is
+// retried up to maxAttempts times (backoff * attempt between tries); after that,
+// or after a handler panic, the event goes to the dead letter table instead of
+// halting the engine. Handler panics are not retried: handlers mutate state
+// before they fail, so running one twice could double-apply a signal.
+// maxAttempts <= 0 keeps the fail-fast behaviour. Requires a store.
+func (s *Sequencer) SetDeadLetterPolicy(maxAttempts int, backoff time.Duration) {
+	s.maxAttempts = maxAttempts
+	s.retryBackoff = backoff
+}
+
+// persist writes ev to the WAL, retrying within the budget.
+func (s *Sequencer) persist(ev event.Event) (attempts int, err error) {
+	limit := max(s.maxAttempts, 1)
+	for attempts = 1; ; attempts++ {
+		err = s.log.Append(context.Background(), ev)
+		if err == nil || attempts >= limit {
+			return attempts, err
+		}
+		slog.Warn("WAL_WRITE_RETRY", slog.Uint64("seq", ev.GetSeq()), slog.Int("attempt", attempts), slog.Any("error", err))
+		time.Sleep(s.retryBackoff * time.Duration(attempts))
+	}
+}
+
+// dispatchGuarded runs the handler, converting a panic into an error when
+// quarantine is enabled. Without it (or without a store) the panic propagates as before.
+func (s *Sequencer) dispatchGuarded(ev event.Event, replay bool) (failure error, stack string) {
+	if s.maxAttempts > 0 && s.store != nil {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			msg := fmt.Sprint(r)
+			for _, prefix := range fatalPanicPrefixes {
+				if strings.HasPrefix(msg, prefix) {
+					panic(r)
+				}
+			}
+			failure = errors.New(msg)
+			stack = string(debug.Stack())
+		}()
+	}
+	s.dispatch(ev, replay)
+	return nil, ""
+}
+
+// quarantine records ev as a dead letter. If even that write fails there is
+// nowhere left to put the event, so the engine halts as it would have anyway.
+func (s *Sequencer) quarantine(ev event.Event, stage string, seq uint64, cause error, stack string, attempts int) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		payload = []byte(fmt.Sprintf("%q", fmt.Sprintf("%+v", ev)))
+	}
+
+	id, err := s.store.SaveDeadLetter(context.Background(), storage.DeadLetter{
+		Seq:       seq,
+		Stage:     stage,
+		Type:      ev.GetType(),
+		Payload:   payload,
+		Error:     cause.Error(),
+		Stack:     stack,
+		Attempts:  attempts,
+		CreatedAt: s.clock.Now().UnixMicro(),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("DEAD_LETTER_FAILURE: %v (original: %v)", err, cause))
+	}
+	if stage == storage.DeadLetterStageDispatch {
+		s.quarantined[seq] = true
+	}
+
+	slog.Error("EVENT_QUARANTINED",
+		slog.Int64("dead_letter_id", id),
+		slog.String("stage", stage),
+		slog.Uint64("seq", seq),
+		slog.Int("type", int(ev.GetType())),
+		slog.Int("attempts", attempts),
+		slog.Any("error", cause),
+	)
+}
+
+// loadQuarantine reads the seqs replay must skip.
+func (s *Sequencer) loadQuarantine(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	seqs, err := s.store.QuarantinedSeqs(ctx)
+	if err != nil {
+		return err
+	}
+	s.quarantined = seqs
+	return nil
+}
+
+// requeueDeadLetters finishes dead letters marked by `app deadletter requeue`.
+// DISPATCH letters were already replayed (they are no longer skipped); PERSIST
+// letters never reached the WAL and are sequenced now. Both are then removed.
+func (s *Sequencer) requeueDeadLetters(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	letters, err := s.store.ListDeadLetters(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range letters {
+		if !d.Requeued {
+			continue
+		}
+		if d.Stage == storage.DeadLetterStagePersist {
+			ev, err := d.Event()
+			if err != nil {
+				return fmt.Errorf("failed to decode dead letter %d: %w", d.ID, err)
+			}
+			if ev != nil {
+				s.processEvent(ev)
+			}
+		}
+		if err := s.store.DeleteDeadLetter(ctx, d.ID); err != nil {
+			return err
+		}
+		slog.Info("Dead letter requeued", slog.Int64("id", d.ID), slog.String("stage", d.Stage), slog.Uint64("seq", d.Seq))
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+)
+
+// poisonStrategy panics on every update for one symbol (a handler bug).
+type poisonStrategy struct{ symbol string }
+
+func (p *poisonStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol == p.symbol {
+		panic("index out of range")
+	}
+	return 0
+}
+
+func (p *poisonStrategy) OnOrderUpdate(order domain.Order) {}
+
+func newDeadLetterStore(t *testing.T) *storage.EventStore {
+	store, err := storage.NewEventStore(t.TempDir() + "/dl.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestDeadLetter_HandlerPanicIsQuarantined(t *testing.T) {
+	store := newDeadLetterStore(t)
+	ctx := context.Background()
+
+	seq := NewSequencer(10, store, &poisonStrategy{symbol: "BAD"}, nil)
+	seq.SetDeadLetterPolicy(3, 0)
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 1})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BAD", PriceMicros: 2})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 3})
+
+	if seq.GetNextSeq() != 4 {
+		t.Fatalf("engine should keep going, nextSeq=%d", seq.GetNextSeq())
+	}
+	letters, err := store.ListDeadLetters(ctx)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("expected one dead letter, got %v (%v)", letters, err)
+	}
+	d := letters[0]
+	if d.Stage != storage.DeadLetterStageDispatch || d.Seq != 2 || d.Attempts != 1 {
+		t.Errorf("unexpected dead letter: %+v", d)
+	}
+	if !strings.Contains(d.Error, "index out of range") || d.Stack == "" {
+		t.Errorf("missing failure context: error=%q stack=%d bytes", d.Error, len(d.Stack))
+	}
+
+	// Replay with the same bug skips the quarantined seq instead of crashing
+	replay := NewSequencer(10, store, &poisonStrategy{symbol: "BAD"}, nil)
+	replay.SetDeadLetterPolicy(3, 0)
+	if err := replay.RecoverFromWAL(ctx); err != nil {
+		t.Fatalf("RecoverFromWAL failed: %v", err)
+	}
+	if replay.GetNextSeq() != 4 {
+		t.Errorf("expected nextSeq=4 after replay, got %d", replay.GetNextSeq())
+	}
+	if letters, _ := store.ListDeadLetters(ctx); len(letters) != 1 {
+		t.Errorf("replay must not quarantine again, got %d letters", len(letters))
+	}
+
+	// After the fix, a requeued letter is applied during replay and removed
+	if err := store.RequeueDeadLetter(ctx, d.ID); err != nil {
+		t.Fatalf("RequeueDeadLetter failed: %v", err)
+	}
+	fixed := NewSequencer(10, store, nil, nil)
+	if err := fixed.RecoverFromWAL(ctx); err != nil {
+		t.Fatalf("RecoverFromWAL failed: %v", err)
+	}
+	if _, ok := fixed.GetMarketState("BAD"); !ok {
+		t.Error("requeued event was not applied")
+	}
+	if letters, _ := store.ListDeadLetters(ctx); len(letters) != 0 {
+		t.Errorf("requeued letter should be removed, got %d", len(letters))
+	}
+}
+
+func TestDeadLetter_PersistFailureAfterRetries(t *testing.T) {
+	store := newDeadLetterStore(t)
+	ctx := context.Background()
+
+	// Occupy seq 1 so every WAL insert of the next event conflicts
+	if err := store.SaveEvent(ctx, &event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: 1}, Symbol: "OLD"}); err != nil {
+		t.Fatalf("SaveEvent failed: %v", err)
+	}
+
+	seq := NewSequencer(10, store, nil, nil)
+	seq.SetDeadLetterPolicy(3, 0)
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdPauseStrategy, Reason: "poison"})
+
+	if seq.GetNextSeq() != 1 {
+		t.Errorf("unsequenced event must not consume a seq, nextSeq=%d", seq.GetNextSeq())
+	}
+	if seq.StrategyPaused() {
+		t.Error("quarantined event must not be applied")
+	}
+	letters, _ := store.ListDeadLetters(ctx)
+	if len(letters) != 1 || letters[0].Stage != storage.DeadLetterStagePersist || letters[0].Attempts != 3 {
+		t.Fatalf("expected one PERSIST letter after 3 attempts, got %+v", letters)
+	}
+
+	// Requeue: sequenced after the recovered WAL at the next start
+	if err := store.RequeueDeadLetter(ctx, letters[0].ID); err != nil {
+		t.Fatalf("RequeueDeadLetter failed: %v", err)
+	}
+	restarted := NewSequencer(10, store, nil, nil)
+	restarted.SetDeadLetterPolicy(3, 0)
+	if err := restarted.RecoverFromWAL(ctx); err != nil {
+		t.Fatalf("RecoverFromWAL failed: %v", err)
+	}
+	if !restarted.StrategyPaused() || restarted.GetNextSeq() != 3 {
+		t.Errorf("requeued event not sequenced: paused=%v nextSeq=%d", restarted.StrategyPaused(), restarted.GetNextSeq())
+	}
+	if letters, _ := store.ListDeadLetters(ctx); len(letters) != 0 {
+		t.Errorf("requeued letter should be removed, got %d", len(letters))
+	}
+}
+
+func TestDeadLetter_DisabledStillFailsFast(t *testing.T) {
+	seq := NewSequencer(10, newDeadLetterStore(t), &poisonStrategy{symbol: "BAD"}, nil)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic without a dead letter policy")
+		}
+	}()
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BAD"})
+}
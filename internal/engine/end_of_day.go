@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// EndOfDayReason is the audit reason of the commands EndOfDay sends.
+const EndOfDayReason = "end of day"
+
+// EndOfDaySpec is one venue's end-of-day window, in the venue's time zone.
+type EndOfDaySpec struct {
+	Exchange string         // Venue (ControlEvent.Target; "" = all)
+	Location *time.Location // Venue time zone
+	ReduceAt time.Duration  // Local time of day reduce-only starts
+	ResumeAt time.Duration  // Local time of day it is lifted (negative = never: the operator lifts it)
+	Flatten  bool           // Also close every position of the venue at ReduceAt
+}
+
+// inWindow reports whether now falls between ReduceAt and ResumeAt; a window
+// whose ResumeAt comes before ReduceAt crosses midnight, one without ResumeAt
+// lasts until midnight.
+func (sp EndOfDaySpec) inWindow(now time.Time) bool {
+	local := now.In(sp.Location)
+	h, m, sec := local.Clock()
+	tod := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+	switch {
+	case sp.ResumeAt < 0:
+		return tod >= sp.ReduceAt
+	case sp.ResumeAt > sp.ReduceAt:
+		return tod >= sp.ReduceAt && tod < sp.ResumeAt
+	default:
+		return tod >= sp.ReduceAt || tod < sp.ResumeAt
+	}
+}
+
+// next returns the first window edge after now.
+func (sp EndOfDaySpec) next(now time.Time) time.Time {
+	next := nextTimeOfDay(now, sp.Location, sp.ReduceAt)
+	if sp.ResumeAt >= 0 {
+		if resume := nextTimeOfDay(now, sp.Location, sp.ResumeAt); resume.Before(next) {
+			next = resume
+		}
+	}
+	return next
+}
+
+// nextTimeOfDay returns the first local time of day tod in loc after now.
+func nextTimeOfDay(now time.Time, loc *time.Location, tod time.Duration) time.Time {
+	local := now.In(loc)
+	y, mo, d := local.Date()
+	h, m := int(tod/time.Hour), int(tod%time.Hour/time.Minute)
+	at := time.Date(y, mo, d, h, m, 0, 0, loc)
+	if !at.After(now) {
+		at = time.Date(y, mo, d+1, h, m, 0, 0, loc)
+	}
+	return at
+}
+
+// EndOfDay puts venues into reduce-only mode at the end of their trading day
+// (CmdReduceOnly, flattening positions if the spec says so) and lifts it when
+// the next day starts (CmdLiftReduceOnly). The commands go through the
+// sequencer like operator commands, so the mode is WAL-logged and replayed.
+// On start each venue is put in the state its window calls for: a restart
+// inside the window re-enters reduce-only (a no-op if the WAL restored it),
+// one outside lifts it.
+type EndOfDay struct {
+	client *ControlClient
+	specs  []EndOfDaySpec
+	clock  quant.Clock
+}
+
+// NewEndOfDay creates a scheduler sending its commands with client.
+func NewEndOfDay(client *ControlClient, specs []EndOfDaySpec) *EndOfDay {
+	return &EndOfDay{client: client, specs: specs, clock: quant.RealClock{}}
+}
+
+// SetClock replaces the clock the scheduler reads (default quant.RealClock).
+// Must be called before Run.
+func (d *EndOfDay) SetClock(c quant.Clock) {
+	d.clock = c
+}
+
+// Run sends the window edges until ctx is done.
+func (d *EndOfDay) Run(ctx context.Context) {
+	if len(d.specs) == 0 {
+		return
+	}
+	next := make([]time.Time, len(d.specs))
+	now := d.clock.Now()
+	for i, sp := range d.specs {
+		if sp.ResumeAt >= 0 || sp.inWindow(now) {
+			if !d.apply(ctx, sp, now) {
+				return
+			}
+		}
+		next[i] = sp.next(now)
+	}
+
+	for {
+		wait := time.NewTimer(slices.MinFunc(next, time.Time.Compare).Sub(d.clock.Now()))
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return
+		case <-wait.C:
+		}
+		if !d.fire(ctx, next) {
+			return
+		}
+	}
+}
+
+// fire applies every spec whose edge is due by now, in configuration order,
+// and moves it to its next edge. After a stall the state at now is sent, not
+// each missed edge. Returns false once ctx is done.
+func (d *EndOfDay) fire(ctx context.Context, next []time.Time) bool {
+	now := d.clock.Now()
+	for i, sp := range d.specs {
+		if next[i].After(now) {
+			continue
+		}
+		if !d.apply(ctx, sp, now) {
+			return false
+		}
+		next[i] = sp.next(now)
+	}
+	return true
+}
+
+// apply sends the command putting sp's venue in its state at now.
+func (d *EndOfDay) apply(ctx context.Context, sp EndOfDaySpec, now time.Time) bool {
+	cmd, value := event.CmdLiftReduceOnly, int64(0)
+	if sp.inWindow(now) {
+		cmd = event.CmdReduceOnly
+		if sp.Flatten {
+			value = 1
+		}
+	}
+	if err := d.client.Send(ctx, cmd, sp.Exchange, value, EndOfDayReason); err != nil {
+		return false
+	}
+	slog.Info("END_OF_DAY", slog.String("command", cmd.String()), slog.String("exchange", sp.Exchange))
+	return true
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+	"time"
+)
+
+func TestEndOfDaySpec_Window(t *testing.T) {
+	seoul := domain.ZoneKST
+	kst := func(h, m int) time.Time { return time.Date(2026, 3, 2, h, m, 0, 0, seoul) }
+
+	overnight := EndOfDaySpec{Location: seoul, ReduceAt: 23 * time.Hour, ResumeAt: 9 * time.Hour}
+	sameDay := EndOfDaySpec{Location: seoul, ReduceAt: 8*time.Hour + 50*time.Minute, ResumeAt: 9 * time.Hour}
+	manual := EndOfDaySpec{Location: seoul, ReduceAt: 23 * time.Hour, ResumeAt: -1}
+	tests := []struct {
+		spec EndOfDaySpec
+		now  time.Time
+		want bool
+		next time.Time
+	}{
+		{overnight, kst(22, 59), false, kst(23, 0)},
+		{overnight, kst(23, 0), true, kst(24+9, 0)},
+		{overnight, kst(3, 0), true, kst(9, 0)},
+		{overnight, kst(9, 0), false, kst(23, 0)},
+		{sameDay, kst(8, 55), true, kst(9, 0)},
+		{sameDay, kst(12, 0), false, kst(24+8, 50)},
+		{manual, kst(3, 0), false, kst(23, 0)},
+		{manual, kst(23, 30), true, kst(24+23, 0)},
+	}
+	for i, tc := range tests {
+		if got := tc.spec.inWindow(tc.now); got != tc.want {
+			t.Errorf("%d: inWindow(%v) = %v; want %v", i, tc.now, got, tc.want)
+		}
+		if got := tc.spec.next(tc.now); !got.Equal(tc.next) {
+			t.Errorf("%d: next(%v) = %v; want %v", i, tc.now, got, tc.next)
+		}
+	}
+}
+
+func TestEndOfDay_Fire(t *testing.T) {
+	inbox := make(chan event.Event, 4)
+	spec := EndOfDaySpec{Exchange: "UPBIT", Location: time.UTC, ReduceAt: 23 * time.Hour, ResumeAt: time.Hour, Flatten: true}
+	eod := NewEndOfDay(NewControlClient(inbox), []EndOfDaySpec{spec})
+	start := time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC)
+	clock := quant.NewSimClock(quant.TimeStamp(start.UnixMicro()))
+	eod.SetClock(clock)
+	next := []time.Time{spec.next(start)}
+
+	eod.fire(context.Background(), next) // Not due yet
+	clock.Advance(time.Hour)
+	eod.fire(context.Background(), next)
+	clock.Advance(2 * time.Hour)
+	eod.fire(context.Background(), next)
+
+	if len(inbox) != 2 {
+		t.Fatalf("got %d commands; want 2", len(inbox))
+	}
+	enter, lift := (<-inbox).(*event.ControlEvent), (<-inbox).(*event.ControlEvent)
+	if enter.Command != event.CmdReduceOnly || enter.Target != "UPBIT" || enter.Value != 1 || enter.Reason != EndOfDayReason {
+		t.Errorf("enter = %+v; want a flattening REDUCE_ONLY for UPBIT", enter)
+	}
+	if lift.Command != event.CmdLiftReduceOnly || lift.Target != "UPBIT" || lift.Seq != enter.Seq+1 {
+		t.Errorf("lift = %+v; want the next CONTROL seq lifting UPBIT", lift)
+	}
+	if want := time.Date(2026, 3, 3, 23, 0, 0, 0, time.UTC); !next[0].Equal(want) {
+		t.Errorf("next edge %v; want %v", next[0], want)
+	}
+}
@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"crypto_go/internal/event"
+)
+
+// Follower defaults.
+const (
+	DefaultFollowerPollInterval = 500 * time.Millisecond
+	DefaultFollowerBatchSize    = 1000
+)
+
+// WALSource is a leader's event log as read by a follower (storage.EventStore
+// opened with OpenEventStoreReadOnly on a shared file).
+type WALSource interface {
+	LoadEventsBatch(ctx context.Context, fromSeq uint64, limit int) ([]event.Event, error)
+	QuarantinedSeqs(ctx context.Context) (map[uint64]bool, error)
+}
+
+// FollowerConfig configures a Follower. Zero values use the defaults.
+type FollowerConfig struct {
+	PollInterval time.Duration // Wait between polls once caught up
+	BatchSize    int           // Events read per query
+}
+
+// Follower keeps a Sequencer identical to a leader's by replaying the leader's
+// WAL as it grows, through the same code path as crash recovery. Replayed
+// events have no external side effects (strategy orders are tracked, never
+// sent), the follower writes no WAL of its own, and it serves dashboards and
+// analytics so read traffic stays off the trading instance.
+type Follower struct {
+	seq *Sequencer
+	src WALSource
+	cfg FollowerConfig
+}
+
+// NewFollower creates a follower driving seq, which must be configured like
+// the leader's (strategy, OMS, trade guard, filter) and must not be Run.
+func NewFollower(seq *Sequencer, src WALSource, cfg FollowerConfig) *Follower {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultFollowerPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultFollowerBatchSize
+	}
+	return &Follower{seq: seq, src: src, cfg: cfg}
+}
+
+// CatchUp applies every event the leader has written so far and returns how many.
+func (f *Follower) CatchUp(ctx context.Context) (int, error) {
+	// Events the leader quarantined stay in its WAL but are skipped, here too
+	quarantined, err := f.src.QuarantinedSeqs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read leader quarantine: %w", err)
+	}
+	f.seq.mu.Lock()
+	f.seq.quarantined = quarantined
+	f.seq.mu.Unlock()
+
+	applied := 0
+	for {
+		next := f.seq.GetNextSeq()
+		events, err := f.src.LoadEventsBatch(ctx, next, f.cfg.BatchSize)
+		if err != nil {
+			return applied, fmt.Errorf("failed to read leader WAL: %w", err)
+		}
+		for _, ev := range events {
+			if ev.GetSeq() != next {
+				return applied, fmt.Errorf("leader WAL gap: expected seq %d, got %d", next, ev.GetSeq())
+			}
+			f.seq.ReplayEvent(ev)
+			next++
+			applied++
+		}
+		if len(events) < f.cfg.BatchSize {
+			return applied, nil
+		}
+	}
+}
+
+// Verify catches up to the leader's sealed end and checks that this state
+// matches it. The leader's last writes may take a while to show on a file
+// share, so it polls until ctx ends.
+func (f *Follower) Verify(ctx context.Context, sealed HandoverState) error {
+	for f.seq.GetNextSeq() <= sealed.Seq {
+		if _, err := f.CatchUp(ctx); err != nil {
+			return err
+		}
+		if f.seq.GetNextSeq() > sealed.Seq {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("leader WAL ends at seq %d, sealed at %d: %w", f.seq.GetNextSeq()-1, sealed.Seq, ctx.Err())
+		case <-time.After(f.cfg.PollInterval):
+		}
+	}
+	if last := f.seq.GetNextSeq() - 1; last != sealed.Seq {
+		return fmt.Errorf("leader WAL continues past its seal: at seq %d, sealed at %d", last, sealed.Seq)
+	}
+	if hash := f.seq.StateHash(); hash != sealed.Hash {
+		return fmt.Errorf("state hash mismatch at seq %d: follower %s, leader %s", sealed.Seq, hash, sealed.Hash)
+	}
+	return nil
+}
+
+// Run follows the leader until ctx ends. Read errors are logged and retried.
+func (f *Follower) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+	failing := false
+	for {
+		if _, err := f.CatchUp(ctx); err != nil {
+			if !failing && ctx.Err() == nil {
+				slog.Error("FOLLOWER_STALLED", slog.Uint64("next_seq", f.seq.GetNextSeq()), slog.Any("error", err))
+			}
+			failing = true
+		} else if failing {
+			slog.Info("FOLLOWER_RESUMED", slog.Uint64("next_seq", f.seq.GetNextSeq()))
+			failing = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+)
+
+func followerMarketEvent(symbol string, price int64, ts int64) *event.MarketUpdateEvent {
+	ev := event.AcquireMarketUpdateEvent()
+	ev.Ts = quant.TimeStamp(ts)
+	ev.Symbol = symbol
+	ev.Exchange = "UPBIT"
+	ev.PriceMicros = quant.PriceMicros(price)
+	ev.QtySats = 1
+	return ev
+}
+
+func TestFollower_TailsLeaderWAL(t *testing.T) {
+	dbPath := t.TempDir() + "/leader.db"
+	store, err := storage.NewEventStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	leader := NewSequencer(100, store, nil, nil)
+
+	replica, err := storage.OpenEventStoreReadOnly(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open read-only: %v", err)
+	}
+	defer replica.Close()
+	seq := NewSequencer(1, nil, nil, nil)
+	f := NewFollower(seq, replica, FollowerConfig{BatchSize: 2})
+
+	ctx := context.Background()
+	for i, sym := range []string{"BTC", "ETH", "XRP", "BTC", "SOL"} {
+		leader.ProcessEventForTest(followerMarketEvent(sym, int64(100+i), int64(1000+i)))
+	}
+	if n, err := f.CatchUp(ctx); err != nil || n != 5 {
+		t.Fatalf("expected 5 events over several batches, got %d (%v)", n, err)
+	}
+	if !reflect.DeepEqual(seq.GetMarketsSnapshot(), leader.GetMarketsSnapshot()) {
+		t.Fatalf("follower diverged:\n%+v\n%+v", seq.GetMarketsSnapshot(), leader.GetMarketsSnapshot())
+	}
+
+	// Only new events are applied on the next poll
+	leader.ProcessEventForTest(followerMarketEvent("ETH", 200, 2000))
+	if n, err := f.CatchUp(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 new event, got %d (%v)", n, err)
+	}
+	if n, _ := f.CatchUp(ctx); n != 0 {
+		t.Errorf("nothing new must apply nothing, got %d", n)
+	}
+	if !reflect.DeepEqual(seq.GetMarketsSnapshot(), leader.GetMarketsSnapshot()) {
+		t.Errorf("follower diverged after tailing")
+	}
+}
+
+// gappyWAL returns a batch that skips seqs.
+type gappyWAL struct{}
+
+func (gappyWAL) LoadEventsBatch(context.Context, uint64, int) ([]event.Event, error) {
+	ev := followerMarketEvent("BTC", 100, 1000)
+	ev.Seq = 3
+	return []event.Event{ev}, nil
+}
+
+func (gappyWAL) QuarantinedSeqs(context.Context) (map[uint64]bool, error) { return nil, nil }
+
+func TestFollower_GapIsAnError(t *testing.T) {
+	seq := NewSequencer(1, nil, nil, nil)
+	_, err := NewFollower(seq, gappyWAL{}, FollowerConfig{}).CatchUp(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "gap") {
+		t.Fatalf("expected a gap error, got %v", err)
+	}
+	if seq.GetNextSeq() != 1 {
+		t.Errorf("nothing must be applied past a gap, next seq %d", seq.GetNextSeq())
+	}
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"cmp"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"slices"
+	"time"
+)
+
+// fundingKey identifies the funding schedule of one perpetual on one venue.
+type fundingKey struct{ exchange, symbol string }
+
+// SetFundingWarning raises a pre-funding warning before every funding epoch:
+// once per epoch, at the first market update or funding event of the symbol
+// within before of it (event timestamps: replays warn at the same events).
+// The strategy gets it through strategy.FundingHandler. 0 disables warnings.
+// It shapes replicated state, so a follower must use the same value. Must be
+// called before Run.
+func (s *Sequencer) SetFundingWarning(before time.Duration) {
+	s.fundingWarn = quant.TimeStamp(before.Microseconds())
+}
+
+// SetFundingObserver registers a callback receiving every live pre-funding
+// warning (replay is skipped), e.g. for alerts. It runs on the hotpath
+// goroutine and must not block. Must be called before Run.
+func (s *Sequencer) SetFundingObserver(fn func(domain.FundingEpoch)) {
+	s.onFundingSoon = fn
+}
+
+// handleFunding records the next funding epoch of a perpetual. An epoch before
+// the recorded one (a stale push) is ignored; the same epoch only updates the
+// predicted rate, and a later one replaces it and re-arms the warning. The
+// strategy sees every recorded epoch through strategy.FundingRateHandler.
+func (s *Sequencer) handleFunding(e *event.FundingEvent) {
+	k := fundingKey{e.Exchange, e.Symbol}
+	cur, ok := s.funding[k]
+	switch {
+	case !ok:
+		cur = new(domain.FundingEpoch)
+		*cur = e.FundingEpoch()
+		s.addFunding(cur)
+	case e.NextFundingTs < cur.NextTs:
+		return
+	case e.NextFundingTs == cur.NextTs:
+		cur.RateMicros = e.RateMicros
+	default:
+		*cur = e.FundingEpoch()
+	}
+	if h, ok := s.strategy.(strategy.FundingRateHandler); ok && !s.strategyPaused {
+		count := h.OnFundingRate(*cur, s.orderBuf[:])
+		for i := 0; i < count; i++ {
+			s.handleStrategyAction(&s.orderBuf[i], e.Ts)
+		}
+	}
+	s.checkFunding(e.Symbol, e.Ts)
+}
+
+// addFunding indexes a new schedule. A symbol's schedules are kept sorted by
+// venue so warnings due at the same event fire in a replayable order.
+func (s *Sequencer) addFunding(epoch *domain.FundingEpoch) {
+	s.funding[fundingKey{epoch.Exchange, epoch.Symbol}] = epoch
+	bySymbol := append(s.fundingIdx[epoch.Symbol], epoch)
+	slices.SortFunc(bySymbol, func(a, b *domain.FundingEpoch) int { return cmp.Compare(a.Exchange, b.Exchange) })
+	s.fundingIdx[epoch.Symbol] = bySymbol
+}
+
+// markFunding sets the market's time to its next funding epoch on any venue
+// as of ts (caller holds s.mu).
+func (s *Sequencer) markFunding(state *domain.MarketState, ts quant.TimeStamp) {
+	var next quant.TimeStamp
+	for _, f := range s.fundingIdx[state.Symbol] {
+		if f.NextTs > ts && (next == 0 || f.NextTs < next) {
+			next = f.NextTs
+		}
+	}
+	state.NextFundingUnixM = next
+	state.FundingInMicros = 0
+	if next > 0 {
+		state.FundingInMicros = next - ts
+	}
+	s.checkFunding(state.Symbol, ts)
+}
+
+// checkFunding raises the warnings of symbol's epochs that are due at ts.
+func (s *Sequencer) checkFunding(symbol string, ts quant.TimeStamp) {
+	if s.fundingWarn <= 0 {
+		return
+	}
+	for _, f := range s.fundingIdx[symbol] {
+		if f.Warned || ts >= f.NextTs || f.NextTs-ts > s.fundingWarn {
+			continue
+		}
+		f.Warned = true
+		if s.onFundingSoon != nil && !s.replaying {
+			s.onFundingSoon(*f)
+		}
+		if h, ok := s.strategy.(strategy.FundingHandler); ok && !s.strategyPaused {
+			count := h.OnFundingSoon(*f, s.orderBuf[:])
+			for i := 0; i < count; i++ {
+				s.handleStrategyAction(&s.orderBuf[i], ts)
+			}
+		}
+	}
+}
+
+// GetFundingCalendar returns the known funding epochs, soonest first (thread-safe).
+func (s *Sequencer) GetFundingCalendar() []domain.FundingEpoch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fundingEpochs()
+}
+
+// fundingEpochs lists the funding schedules in calendar order (caller holds s.mu).
+func (s *Sequencer) fundingEpochs() []domain.FundingEpoch {
+	out := make([]domain.FundingEpoch, 0, len(s.funding))
+	for _, f := range s.funding {
+		out = append(out, *f)
+	}
+	slices.SortFunc(out, func(a, b domain.FundingEpoch) int {
+		return cmp.Or(cmp.Compare(a.NextTs, b.NextTs), cmp.Compare(a.Exchange, b.Exchange), cmp.Compare(a.Symbol, b.Symbol))
+	})
+	return out
+}
@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+	"time"
+)
+
+type fundingStrategy struct {
+	countingStrategy
+	warned []domain.FundingEpoch
+}
+
+func (s *fundingStrategy) OnFundingSoon(epoch domain.FundingEpoch, out []domain.Order) int {
+	s.warned = append(s.warned, epoch)
+	out[0] = domain.Order{Symbol: epoch.Symbol, Side: domain.SideSell, Type: domain.OrderTypeMarket, QtySats: 1}
+	return 1
+}
+
+type fundingRateStrategy struct {
+	countingStrategy
+	rates []int64
+}
+
+func (s *fundingRateStrategy) OnFundingRate(epoch domain.FundingEpoch, out []domain.Order) int {
+	s.rates = append(s.rates, epoch.RateMicros)
+	out[0] = domain.Order{Symbol: epoch.Symbol, Side: domain.SideBuy, Type: domain.OrderTypeMarket, Market: domain.MarketSpot, QtySats: 1}
+	return 1
+}
+
+func funding(exchange string, ts, next, rate int64) *event.FundingEvent {
+	return &event.FundingEvent{
+		BaseEvent:     event.BaseEvent{Ts: quant.TimeStamp(ts)},
+		Exchange:      exchange,
+		Symbol:        "BTC",
+		NextFundingTs: quant.TimeStamp(next),
+		RateMicros:    rate,
+	}
+}
+
+func TestSequencer_FundingWarning(t *testing.T) {
+	const minute = int64(60_000_000)
+	epoch := 480 * minute
+	strat := &fundingStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+	seq.SetFundingWarning(10 * time.Minute)
+	var observed []domain.FundingEpoch
+	seq.SetFundingObserver(func(f domain.FundingEpoch) { observed = append(observed, f) })
+
+	seq.ProcessEventForTest(funding("BYBIT_LINEAR", epoch-60*minute, epoch+60*minute, 50))
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", epoch-60*minute, epoch, 100))
+	seq.ProcessEventForTest(mkt("UPBIT", epoch-30*minute, 100))
+	state, _ := seq.GetMarketState("BTC")
+	if state.NextFundingUnixM != quant.TimeStamp(epoch) || state.FundingInMicros != quant.TimeStamp(30*minute) {
+		t.Errorf("market should show the soonest epoch in 30m, got %+v", state)
+	}
+	if len(strat.warned) != 0 {
+		t.Fatalf("warned outside the window: %+v", strat.warned)
+	}
+
+	// A rate update of the same epoch keeps it armed; a stale epoch is ignored
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", epoch-20*minute, epoch, 120))
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", epoch-20*minute, epoch-480*minute, 90))
+	seq.ProcessEventForTest(mkt("UPBIT", epoch-5*minute, 100))
+	seq.ProcessEventForTest(mkt("UPBIT", epoch-2*minute, 100))
+	if len(strat.warned) != 1 || strat.warned[0].Exchange != "BITGET_FUTURES" || strat.warned[0].RateMicros != 120 {
+		t.Fatalf("expected one warning of the updated epoch, got %+v", strat.warned)
+	}
+	if len(observed) != 1 {
+		t.Errorf("observer should see the live warning once, got %d", len(observed))
+	}
+	if req := <-oms.Requests(); req.Symbol != "BTC" || req.Side != domain.SideSell {
+		t.Errorf("expected the strategy's order to be submitted, got %+v", req)
+	}
+
+	// Past the epoch the market counts down to the next venue's; the settled
+	// epoch's successor re-arms the warning
+	seq.ProcessEventForTest(mkt("UPBIT", epoch+minute, 100))
+	if state, _ := seq.GetMarketState("BTC"); state.NextFundingUnixM != quant.TimeStamp(epoch+60*minute) {
+		t.Errorf("expected the Bybit epoch after settlement, got %+v", state)
+	}
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", epoch+minute, epoch+480*minute, 80))
+	cal := seq.GetFundingCalendar()
+	if len(cal) != 2 || cal[0].Exchange != "BYBIT_LINEAR" || cal[1].Warned || cal[1].NextTs != quant.TimeStamp(epoch+480*minute) {
+		t.Errorf("unexpected calendar: %+v", cal)
+	}
+}
+
+func TestSequencer_FundingRate(t *testing.T) {
+	strat := &fundingRateStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", 1, 30_000_000, 100))
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", 2, 30_000_000, 120))
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", 3, 20_000_000, 90)) // Stale epoch
+	if len(strat.rates) != 2 || strat.rates[0] != 100 || strat.rates[1] != 120 {
+		t.Errorf("expected the new and the updated rate, got %v", strat.rates)
+	}
+	if req := <-oms.Requests(); req.Symbol != "BTC" || req.Market != domain.MarketSpot {
+		t.Errorf("expected the strategy's order to be submitted, got %+v", req)
+	}
+}
+
+func TestSequencer_FundingSnapshotRoundTrip(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	seq.SetFundingWarning(time.Minute)
+	seq.ProcessEventForTest(funding("BITGET_FUTURES", 1, 30_000_000, 100))
+	seq.ProcessEventForTest(funding("BYBIT_LINEAR", 1, 90_000_000, -20))
+	seq.ProcessEventForTest(mkt("UPBIT", 2, 100))
+
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	snap, err := seq.captureSnapshot(seq.nextSeq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewSequencer(10, nil, nil, nil)
+	if err := restored.restoreSnapshot(snap); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.stateHashLocked(); got != snap.StateHash {
+		t.Fatalf("restored state hash %s, want %s", got, snap.StateHash)
+	}
+	if len(restored.fundingIdx["BTC"]) != 2 || !restored.funding[fundingKey{"BITGET_FUTURES", "BTC"}].Warned {
+		t.Errorf("funding index not rebuilt: %+v", restored.fundingEpochs())
+	}
+}
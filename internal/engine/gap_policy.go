@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"crypto_go/internal/event"
+	"fmt"
+	"strings"
+)
+
+// GapAction decides what happens when a gap exceeds the tolerance.
+type GapAction int
+
+const (
+	GapHalt     GapAction = iota // Panic (state dump + halt). Safe default for order flow.
+	GapTolerate                  // Log and continue with the new baseline.
+	GapResync                    // Continue and ask the source to resynchronize (reconnect/snapshot).
+)
+
+func (a GapAction) String() string {
+	switch a {
+	case GapTolerate:
+		return "tolerate"
+	case GapResync:
+		return "resync"
+	default:
+		return "halt"
+	}
+}
+
+// ParseGapAction parses "tolerate", "resync" or "halt" (case-insensitive).
+func ParseGapAction(s string) (GapAction, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "halt":
+		return GapHalt, nil
+	case "tolerate":
+		return GapTolerate, nil
+	case "resync":
+		return GapResync, nil
+	default:
+		return GapHalt, fmt.Errorf("unknown gap action %q (want tolerate|resync|halt)", s)
+	}
+}
+
+// GapRule: gaps up to Tolerance are tolerated with a warning; larger gaps trigger Action.
+type GapRule struct {
+	Tolerance uint64
+	Action    GapAction
+}
+
+// GapPolicy selects a GapRule per event type, falling back to Default.
+type GapPolicy struct {
+	Default GapRule
+	ByType  map[event.Type]GapRule
+}
+
+// DefaultGapPolicy only observes: every gap is logged, recorded and tolerated.
+// Gateways drop events (and burn seq numbers) when the inbox is full, so halting
+// must be an explicit choice in config, never the default.
+func DefaultGapPolicy() GapPolicy {
+	return GapPolicy{Default: GapRule{Tolerance: 10, Action: GapTolerate}}
+}
+
+// RuleFor returns the rule applied to events of type t.
+func (p GapPolicy) RuleFor(t event.Type) GapRule {
+	if rule, ok := p.ByType[t]; ok {
+		return rule
+	}
+	return p.Default
+}
@@ -0,0 +1,316 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// Handover defaults.
+const (
+	DefaultHandoverDrainTimeout  = 10 * time.Second
+	DefaultHandoverCommitTimeout = 60 * time.Second
+)
+
+// Handover errors.
+var (
+	ErrNoHandover        = errors.New("no handover prepared")
+	ErrHandoverCommitted = errors.New("handover already committed")
+)
+
+// HandoverState is the sealed end of a leader's WAL, as a successor must
+// reproduce it before taking over.
+type HandoverState struct {
+	Seq      uint64 `json:"seq"`       // Last event the leader applied and wrote
+	Hash     string `json:"hash"`      // StateHash after Seq
+	InFlight int    `json:"in_flight"` // Orders still unanswered when the drain ended
+}
+
+// Seal stops Run between two events and returns the state it stopped at.
+// Nothing is written to the WAL afterwards; events left in the inbox stay
+// there. Run may be started again to resume (aborted handover).
+func (s *Sequencer) Seal(ctx context.Context) (HandoverState, error) {
+	reply := make(chan HandoverState, 1)
+	select {
+	case s.sealReq <- reply:
+		return <-reply, nil
+	case <-ctx.Done():
+		return HandoverState{}, ctx.Err()
+	}
+}
+
+// keepAlive pings the watchdog until stop is closed. A sealed Run is paused on
+// purpose, not stalled: the pings stop when it resumes or the handover commits.
+func (s *Sequencer) keepAlive(stop <-chan struct{}) {
+	if s.watchdog == nil || s.watchdogInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.watchdogInterval)
+	defer ticker.Stop()
+	s.watchdog.Ping()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.watchdog.Ping()
+		}
+	}
+}
+
+func (s *Sequencer) sealState() HandoverState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state := HandoverState{Seq: s.nextSeq - 1, Hash: s.stateHashLocked()}
+	if s.orders != nil {
+		state.InFlight = s.orders.InFlight()
+	}
+	return state
+}
+
+// StateHash fingerprints the replicated state (markets, books, bars, context,
+// notices, signals, balances, positions, administrative state and OMS orders), so a
+// follower can prove it matches its leader at the same seq. Thread-safe.
+func (s *Sequencer) StateHash() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stateHashLocked()
+}
+
+func (s *Sequencer) stateHashLocked() string {
+	// Struct map keys are flattened: encoding/json sorts string keys, so the
+	// encoding is canonical.
+	books := make(map[string]*domain.OrderBook, len(s.books))
+	for k, v := range s.books {
+		books[k.exchange+"|"+k.symbol] = v
+	}
+	candles := make(map[string]*domain.Candle, len(s.candles))
+	for k, v := range s.candles {
+		candles[k.exchange+"|"+k.symbol+"|"+k.interval] = v
+	}
+	contexts := make(map[string]domain.ContextMetric, len(s.contexts))
+	for k, v := range s.contexts {
+		contexts[k.metric+"|"+k.subject] = v
+	}
+	funding := make(map[string]*domain.FundingEpoch, len(s.funding))
+	for k, v := range s.funding {
+		funding[k.exchange+"|"+k.symbol] = v
+	}
+	var orders map[string]*ManagedOrder
+	if s.orders != nil {
+		orders = s.orders.orders
+	}
+
+	digest := struct {
+		LastSeq        uint64                          `json:"last_seq"`
+		Markets        map[string]*domain.MarketState  `json:"markets"`
+		Books          map[string]*domain.OrderBook    `json:"books"`
+		Candles        map[string]*domain.Candle       `json:"candles"`
+		Contexts       map[string]domain.ContextMetric `json:"contexts"`
+		Sentiments     map[string]domain.Sentiment     `json:"sentiments"`
+		Notices        []domain.Notice                 `json:"notices"`
+		NoticeIDs      map[string]int64                `json:"notice_ids"`
+		Signals        []domain.Signal                 `json:"signals"`
+		Funding        map[string]*domain.FundingEpoch `json:"funding,omitempty"`
+		Balances       map[string]domain.Balance       `json:"balances"`
+		Positions      map[string]domain.Position      `json:"positions,omitempty"`
+		StrategyPaused bool                            `json:"strategy_paused"`
+		Halted         bool                            `json:"halted"`
+		Handover       bool                            `json:"handover"`
+		Resyncing      map[string]quant.TimeStamp      `json:"resyncing,omitempty"`
+		ReduceOnly     map[string]bool                 `json:"reduce_only,omitempty"`
+		RiskLimits     map[string]int64                `json:"risk_limits"`
+		Orders         map[string]*ManagedOrder        `json:"orders"`
+	}{
+		LastSeq:        s.nextSeq - 1,
+		Markets:        s.markets,
+		Books:          books,
+		Candles:        candles,
+		Contexts:       contexts,
+		Sentiments:     s.sentiments,
+		Notices:        s.notices,
+		NoticeIDs:      s.noticeIDs,
+		Signals:        s.signals,
+		Funding:        funding,
+		Balances:       s.balanceBook.Snapshot(),
+		Positions:      s.positions.Snapshot(),
+		StrategyPaused: s.strategyPaused,
+		Halted:         s.halted,
+		Handover:       s.handover,
+		Resyncing:      s.resyncing,
+		ReduceOnly:     s.reduceOnly,
+		RiskLimits:     s.riskLimits,
+		Orders:         orders,
+	}
+	b, err := json.Marshal(digest)
+	if err != nil {
+		panic(fmt.Sprintf("STATE_HASH_FAILURE: %v", err)) // Plain data: cannot happen
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// HandoverPending reports whether the WAL ends in a HANDOVER that no TAKEOVER
+// followed: the previous leader sealed it and whoever writes next must take over.
+func (s *Sequencer) HandoverPending() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.handover
+}
+
+// GetInFlightOrders returns the number of orders awaiting a venue answer. Thread-safe.
+func (s *Sequencer) GetInFlightOrders() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.orders == nil {
+		return 0
+	}
+	return s.orders.InFlight()
+}
+
+// HandoverConfig configures a Handover. Zero values use the defaults.
+type HandoverConfig struct {
+	DrainTimeout  time.Duration // Wait for unanswered orders before sealing
+	CommitTimeout time.Duration // Resume trading if the successor neither commits nor aborts
+}
+
+// Handover is the leader's side of a blue/green handover:
+//
+//	Prepare: HANDOVER (WAL) stops new orders, unanswered orders drain, Run is sealed
+//	Commit:  the successor matched the sealed state; onCommit hands the lock over
+//	Abort:   Run resumes and TAKEOVER (WAL) lifts the order stop
+//
+// The successor replays the same HANDOVER, so both hash the same state. A
+// prepared handover that is not committed in time is aborted. While sealed,
+// the watchdog is pinged in place of Run, at most for the commit timeout.
+type Handover struct {
+	seq      *Sequencer
+	control  *ControlClient
+	resume   func() // Starts Run again
+	onCommit func()
+	cfg      HandoverConfig
+
+	mu        sync.Mutex
+	sealed    *HandoverState
+	committed bool
+	timer     *time.Timer
+	unsealed  chan struct{} // Closed when Run resumes or the handover commits
+}
+
+// NewHandover creates the leader side. resume must restart seq.Run; onCommit
+// runs once, after the successor committed (release the trading lock, exit).
+func NewHandover(seq *Sequencer, control *ControlClient, resume, onCommit func(), cfg HandoverConfig) *Handover {
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = DefaultHandoverDrainTimeout
+	}
+	if cfg.CommitTimeout <= 0 {
+		cfg.CommitTimeout = DefaultHandoverCommitTimeout
+	}
+	return &Handover{seq: seq, control: control, resume: resume, onCommit: onCommit, cfg: cfg}
+}
+
+// Prepare drains and seals the sequencer for successor. Repeating it returns
+// the same state.
+func (h *Handover) Prepare(ctx context.Context, successor string) (HandoverState, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.committed {
+		return HandoverState{}, ErrHandoverCommitted
+	}
+	if h.sealed != nil {
+		return *h.sealed, nil
+	}
+
+	if err := h.control.Send(ctx, event.CmdHandover, successor, 0, "handover: draining for successor"); err != nil {
+		return HandoverState{}, fmt.Errorf("failed to send HANDOVER: %w", err)
+	}
+	h.drain(ctx)
+
+	state, err := h.seq.Seal(ctx)
+	if err != nil {
+		h.lift("seal failed")
+		return HandoverState{}, fmt.Errorf("failed to seal sequencer: %w", err)
+	}
+	if state.InFlight > 0 {
+		slog.Warn("HANDOVER_DRAIN_INCOMPLETE", slog.Int("in_flight", state.InFlight))
+	}
+	slog.Warn("HANDOVER_PREPARED", slog.String("successor", successor), slog.Uint64("seq", state.Seq), slog.String("hash", state.Hash))
+
+	h.sealed = &state
+	h.unsealed = make(chan struct{})
+	go h.seq.keepAlive(h.unsealed)
+	h.timer = time.AfterFunc(h.cfg.CommitTimeout, func() {
+		if err := h.Abort("commit timeout"); err == nil {
+			slog.Warn("HANDOVER_TIMED_OUT", slog.Uint64("seq", state.Seq))
+		}
+	})
+	return state, nil
+}
+
+// drain waits until HANDOVER is applied and no order awaits the venue.
+func (h *Handover) drain(ctx context.Context) {
+	deadline := time.Now().Add(h.cfg.DrainTimeout)
+	for time.Now().Before(deadline) && ctx.Err() == nil {
+		if h.seq.HandoverPending() && h.seq.GetInFlightOrders() == 0 {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// Commit completes a prepared handover. This instance must stop trading for good.
+func (h *Handover) Commit() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.committed {
+		return nil
+	}
+	if h.sealed == nil {
+		return ErrNoHandover
+	}
+	h.timer.Stop()
+	close(h.unsealed)
+	h.committed = true
+	slog.Warn("HANDOVER_COMMITTED", slog.Uint64("seq", h.sealed.Seq))
+	go h.onCommit()
+	return nil
+}
+
+// Abort resumes trading after a prepared handover.
+func (h *Handover) Abort(reason string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.committed {
+		return ErrHandoverCommitted
+	}
+	if h.sealed == nil {
+		return ErrNoHandover
+	}
+	h.timer.Stop()
+	close(h.unsealed)
+	h.sealed = nil
+	h.resume()
+	h.lift(reason)
+	return nil
+}
+
+// lift records TAKEOVER by this instance, ending the order stop.
+func (h *Handover) lift(reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.control.Send(ctx, event.CmdTakeover, "", 0, "handover aborted: "+reason); err != nil {
+		slog.Error("HANDOVER_ABORT_FAILED", slog.String("reason", reason), slog.Any("error", err))
+		return
+	}
+	slog.Warn("HANDOVER_ABORTED", slog.String("reason", reason))
+}
@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+)
+
+// handoverLeader starts a WAL-backed leader with an OMS and a strategy that
+// buys on every market update.
+func handoverLeader(t *testing.T, ctx context.Context) (*Sequencer, *OrderManager, string) {
+	t.Helper()
+	dbPath := t.TempDir() + "/leader.db"
+	store, err := storage.NewEventStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	leader := NewSequencer(16, store, &orderRecorder{}, nil)
+	oms := NewOrderManager("t", 4)
+	leader.SetOrderManager(oms)
+	go leader.Run(ctx)
+	return leader, oms, dbPath
+}
+
+func TestHandover_DrainSealVerifyCommit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leader, oms, dbPath := handoverLeader(t, ctx)
+
+	leader.Inbox() <- followerMarketEvent("BTC", 100, 1000)
+	<-oms.Requests() // Unanswered: the drain must wait for it
+	committed := make(chan struct{})
+	h := NewHandover(leader, NewControlClient(leader.Inbox()), func() { go leader.Run(ctx) }, func() { close(committed) },
+		HandoverConfig{DrainTimeout: 5 * time.Second, CommitTimeout: time.Hour})
+
+	prepared := make(chan HandoverState, 1)
+	go func() {
+		state, err := h.Prepare(ctx, "green")
+		if err != nil {
+			t.Errorf("Prepare failed: %v", err)
+		}
+		prepared <- state
+	}()
+
+	// No new orders once HANDOVER is applied; the venue answers the open one
+	for !leader.HandoverPending() {
+		time.Sleep(time.Millisecond)
+	}
+	leader.Inbox() <- followerMarketEvent("BTC", 101, 1001)
+	order := leader.GetOpenOrders()[0]
+	leader.Inbox() <- &event.OrderUpdateEvent{OrderID: order.ID, Status: domain.OrderStatusAcked}
+
+	state := <-prepared
+	if state.Seq != 4 || state.InFlight != 0 || state.Hash != leader.StateHash() {
+		t.Fatalf("unexpected sealed state: %+v", state)
+	}
+	if len(oms.Requests()) != 0 {
+		t.Error("an order was sent during the handover")
+	}
+	if again, _ := h.Prepare(ctx, "green"); again != state {
+		t.Errorf("repeated prepare must return the sealed state, got %+v", again)
+	}
+
+	// A follower configured like the leader reproduces the sealed state
+	replica, err := storage.OpenEventStoreReadOnly(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open read-only: %v", err)
+	}
+	defer replica.Close()
+	follower := NewSequencer(1, nil, &orderRecorder{}, nil)
+	follower.SetOrderManager(NewOrderManager("t", 4))
+	f := NewFollower(follower, replica, FollowerConfig{PollInterval: time.Millisecond})
+	if err := f.Verify(ctx, state); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !follower.HandoverPending() {
+		t.Error("the follower must replay HANDOVER too")
+	}
+
+	if err := h.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	select {
+	case <-committed:
+	case <-time.After(time.Second):
+		t.Fatal("onCommit not called")
+	}
+	if err := h.Abort("late"); !errors.Is(err, ErrHandoverCommitted) {
+		t.Errorf("expected ErrHandoverCommitted, got %v", err)
+	}
+}
+
+func TestHandover_AbortResumesLeader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leader, oms, _ := handoverLeader(t, ctx)
+
+	h := NewHandover(leader, NewControlClient(leader.Inbox()), func() { go leader.Run(ctx) }, func() {},
+		HandoverConfig{DrainTimeout: time.Second, CommitTimeout: time.Hour})
+	if err := h.Commit(); !errors.Is(err, ErrNoHandover) {
+		t.Fatalf("expected ErrNoHandover, got %v", err)
+	}
+	if _, err := h.Prepare(ctx, "green"); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if err := h.Abort("hash mismatch"); err != nil {
+		t.Fatalf("Abort failed: %v", err)
+	}
+
+	// TAKEOVER lifts the order stop and the loop runs again
+	leader.Inbox() <- followerMarketEvent("BTC", 100, 1000)
+	select {
+	case <-oms.Requests():
+	case <-time.After(time.Second):
+		t.Fatal("leader did not resume trading after abort")
+	}
+	if leader.HandoverPending() {
+		t.Error("abort must record TAKEOVER")
+	}
+}
+
+func TestHandover_SealedLeaderKeepsWatchdogAlive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leader := NewSequencer(16, nil, &orderRecorder{}, nil)
+	wd := &countingWatchdog{}
+	leader.SetWatchdog(wd, 5*time.Millisecond)
+	go leader.Run(ctx)
+
+	h := NewHandover(leader, NewControlClient(leader.Inbox()), func() { go leader.Run(ctx) }, func() {},
+		HandoverConfig{DrainTimeout: time.Second, CommitTimeout: time.Hour})
+	if _, err := h.Prepare(ctx, "green"); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	sealed := wd.pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	if wd.pings.Load()-sealed < 3 {
+		t.Fatalf("a sealed leader must keep pinging, got %d pings", wd.pings.Load()-sealed)
+	}
+
+	if err := h.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	committed := wd.pings.Load()
+	time.Sleep(30 * time.Millisecond)
+	if wd.pings.Load() != committed {
+		t.Error("the pings must stop once the handover commits")
+	}
+}
+
+func TestFollower_VerifyRejectsMismatch(t *testing.T) {
+	dbPath := t.TempDir() + "/leader.db"
+	store, err := storage.NewEventStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	leader := NewSequencer(1, store, nil, nil)
+	leader.ProcessEventForTest(followerMarketEvent("BTC", 100, 1000))
+	leader.ProcessEventForTest(followerMarketEvent("BTC", 101, 1001))
+
+	f := NewFollower(NewSequencer(1, nil, nil, nil), store, FollowerConfig{PollInterval: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := f.Verify(ctx, HandoverState{Seq: 2, Hash: "bogus"}); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Errorf("expected a hash mismatch, got %v", err)
+	}
+	if err := f.Verify(ctx, HandoverState{Seq: 1, Hash: leader.StateHash()}); err == nil || !strings.Contains(err.Error(), "past its seal") {
+		t.Errorf("expected WAL past the seal, got %v", err)
+	}
+	if err := f.Verify(ctx, HandoverState{Seq: 5}); err == nil {
+		t.Error("expected a timeout waiting for the sealed seq")
+	}
+}
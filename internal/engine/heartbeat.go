@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"time"
+)
+
+// HeartbeatSource is the sequence-validation source name for heartbeats.
+const HeartbeatSource = "HEARTBEAT"
+
+// DefaultHeartbeatInterval is how often HeartbeatService beats by default.
+const DefaultHeartbeatInterval = time.Minute
+
+// HeartbeatService injects a HeartbeatEvent into the sequencer inbox every
+// interval, stamped with the send time, so the WAL itself records when the
+// engine was running. It owns the HEARTBEAT source sequence.
+type HeartbeatService struct {
+	inbox    chan<- event.Event
+	interval time.Duration
+	instance string
+	seq      uint64
+	clock    quant.Clock
+}
+
+// NewHeartbeatService creates a service beating every interval
+// (DefaultHeartbeatInterval if not positive) on behalf of instance.
+func NewHeartbeatService(inbox chan<- event.Event, interval time.Duration, instance string) *HeartbeatService {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	return &HeartbeatService{inbox: inbox, interval: interval, instance: instance, clock: quant.RealClock{}}
+}
+
+// SetClock replaces the clock that stamps heartbeats (default quant.RealClock).
+// Must be called before Run.
+func (h *HeartbeatService) SetClock(c quant.Clock) {
+	h.clock = c
+}
+
+// Run beats once at start and then every interval until ctx is done. Like
+// timer ticks, heartbeats are never dropped: a full inbox delays them.
+func (h *HeartbeatService) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for h.beat(ctx) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// beat sends the next heartbeat. Returns false once ctx is done.
+func (h *HeartbeatService) beat(ctx context.Context) bool {
+	h.seq++
+	ev := &event.HeartbeatEvent{
+		BaseEvent:  event.BaseEvent{Seq: h.seq, Ts: quant.Stamp(h.clock)},
+		Source:     HeartbeatSource,
+		Instance:   h.instance,
+		IntervalMs: h.interval.Milliseconds(),
+		Beat:       h.seq,
+	}
+	select {
+	case h.inbox <- ev:
+		return true
+	case <-ctx.Done():
+		h.seq-- // Not delivered; keep the source sequence contiguous
+		return false
+	}
+}
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatService_Beat(t *testing.T) {
+	inbox := make(chan event.Event, 2)
+	svc := NewHeartbeatService(inbox, 0, "i-1")
+	clock := quant.NewSimClock(1_000_000)
+	svc.SetClock(clock)
+
+	svc.beat(context.Background())
+	clock.Advance(time.Minute)
+	svc.beat(context.Background())
+
+	first, second := (<-inbox).(*event.HeartbeatEvent), (<-inbox).(*event.HeartbeatEvent)
+	if first.Seq != 1 || first.Beat != 1 || first.Source != HeartbeatSource || first.Instance != "i-1" || first.IntervalMs != 60_000 {
+		t.Errorf("first beat = %+v", first)
+	}
+	if second.Beat != 2 || second.Ts-first.Ts != quant.TimeStamp(time.Minute/time.Microsecond) {
+		t.Errorf("second beat = %+v; want beat 2 one minute later", second)
+	}
+
+	blocked := NewHeartbeatService(make(chan event.Event), 0, "i-1")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if blocked.beat(ctx) || blocked.seq != 0 {
+		t.Error("an undelivered beat must not consume a seq")
+	}
+}
+
+func TestSequencer_HeartbeatIsSequenced(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	seq.ProcessEventForTest(mkt("UPBIT", 1, 100))
+	seq.ProcessEventForTest(&event.HeartbeatEvent{BaseEvent: event.BaseEvent{Ts: 2}, Source: HeartbeatSource, IntervalMs: 60_000, Beat: 1})
+	seq.ReplayEvent(&event.HeartbeatEvent{BaseEvent: event.BaseEvent{Seq: 3, Ts: 3}, Source: HeartbeatSource, IntervalMs: 60_000, Beat: 2})
+
+	if seq.GetNextSeq() != 4 {
+		t.Errorf("heartbeats must take a seq live and on replay: nextSeq %d", seq.GetNextSeq())
+	}
+	if state, _ := seq.GetMarketState("BTC"); state.LastUpdateUnixM != 1 {
+		t.Errorf("a heartbeat must not touch market state: %+v", state)
+	}
+}
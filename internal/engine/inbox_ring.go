@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/pkg/ring"
+)
+
+// laneBatch bounds the events Run takes from the lanes between two looks at
+// its channels, so the shared inbox, the watchdog and ctx are not starved
+// while a burst drains.
+const laneBatch = 64
+
+// readyC is closed: selecting on it never blocks.
+var readyC = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// RingInbox is a lock-free inbox lane for exactly one producer goroutine,
+// typically a gateway's read loop (see infra.Outbox). It skips the channel's
+// lock and wakeup on every event; Run drains the lanes in turns next to the
+// shared Inbox() channel and sequences their events the same way, so the
+// order within a lane is kept, not the arrival order across lanes.
+type RingInbox struct {
+	q   *ring.SPSC[event.Event]
+	seq *Sequencer
+}
+
+// NewRingInbox adds a lane of at least size events (rounded up to a power of
+// two). Safe to call while Run is running, e.g. as gateways start.
+func (s *Sequencer) NewRingInbox(size int) *RingInbox {
+	l := &RingInbox{q: ring.NewSPSC[event.Event](size), seq: s}
+	s.lanesMu.Lock()
+	defer s.lanesMu.Unlock()
+	var lanes []*RingInbox
+	if cur := s.lanes.Load(); cur != nil {
+		lanes = append(lanes, *cur...) // Copy on write: Run reads the slice without a lock
+	}
+	lanes = append(lanes, l)
+	s.lanes.Store(&lanes)
+	s.wakeRun() // A Run blocked without lanes starts watching this one
+	return l
+}
+
+// ringInboxes returns the current lanes (nil = none).
+func (s *Sequencer) ringInboxes() []*RingInbox {
+	if lanes := s.lanes.Load(); lanes != nil {
+		return *lanes
+	}
+	return nil
+}
+
+// Send queues ev without blocking. Returns false if the lane is full.
+// Producer goroutine only.
+func (l *RingInbox) Send(ev event.Event) bool {
+	if !l.q.Push(ev) {
+		return false
+	}
+	// Push before the load: either Run sees the event before parking or
+	// this sees Run parked (both are sequentially consistent atomics)
+	if l.seq.parked.Load() {
+		l.seq.wakeRun()
+	}
+	return true
+}
+
+// wakeRun makes a blocked Run loop look at its lanes again.
+func (s *Sequencer) wakeRun() {
+	select {
+	case s.wake <- struct{}{}:
+	default: // A wakeup is already pending
+	}
+}
+
+// Len returns the number of queued events.
+func (l *RingInbox) Len() int {
+	return l.q.Len()
+}
+
+// Cap returns the lane capacity.
+func (l *RingInbox) Cap() int {
+	return l.q.Cap()
+}
+
+// drainLanes processes up to laneBatch events, one per non-empty lane in
+// turn; queued critical events go before each. Returns false if every lane
+// was empty. Run goroutine only.
+func (s *Sequencer) drainLanes(lanes []*RingInbox) bool {
+	n := 0
+	for n < laneBatch {
+		got := false
+		for _, l := range lanes {
+			if len(s.critical) > 0 {
+				s.drainCritical()
+			}
+			if ev, ok := l.q.Pop(); ok {
+				s.processEvent(ev)
+				got = true
+				n++
+			}
+		}
+		if !got {
+			break
+		}
+	}
+	return n > 0
+}
+
+// park announces that Run is about to block. Returns false (and stays
+// awake) if an event slipped into a lane after the last drain.
+func (s *Sequencer) park(lanes []*RingInbox) bool {
+	s.parked.Store(true)
+	for _, l := range lanes {
+		if l.q.Len() > 0 {
+			s.parked.Store(false)
+			return false
+		}
+	}
+	return true
+}
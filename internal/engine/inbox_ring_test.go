@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRingInbox_Full(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	lane := seq.NewRingInbox(2)
+	if !lane.Send(mkt("UPBIT", 1, 100)) || !lane.Send(mkt("UPBIT", 2, 100)) {
+		t.Fatal("lane with room refused an event")
+	}
+	if lane.Send(mkt("UPBIT", 3, 100)) {
+		t.Error("a full lane must refuse the event")
+	}
+	if lane.Len() != 2 {
+		t.Errorf("Len() = %d; want 2", lane.Len())
+	}
+}
+
+func TestRingInbox_Run(t *testing.T) {
+	const perLane = 2000
+	var mu sync.Mutex
+	last := make(map[string]quant.TimeStamp)
+	done := make(chan struct{})
+	count := 0
+	seq := NewSequencer(10, nil, nil, func(state *domain.MarketState) {
+		mu.Lock()
+		defer mu.Unlock()
+		if state.LastUpdateUnixM <= last[state.Symbol] {
+			t.Errorf("%s: ts %d after %d, lane order lost", state.Symbol, state.LastUpdateUnixM, last[state.Symbol])
+		}
+		last[state.Symbol] = state.LastUpdateUnixM
+		if count++; count == 2*perLane+1 {
+			close(done)
+		}
+	})
+	lanes := []*RingInbox{seq.NewRingInbox(16)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go seq.Run(ctx)
+
+	// The shared channel keeps working next to the lanes
+	ev := mkt("UPBIT", 1, 100)
+	ev.Symbol = "XRP"
+	seq.Inbox() <- ev
+
+	// A gateway may start after Run
+	time.Sleep(time.Millisecond)
+	lanes = append(lanes, seq.NewRingInbox(16))
+
+	// Small lanes and pauses make Run park and wake up repeatedly. Each lane
+	// feeds its own symbol so its order can be checked.
+	var wg sync.WaitGroup
+	for i, lane := range lanes {
+		symbol := []string{"BTC", "ETH"}[i]
+		wg.Go(func() {
+			for ts := int64(1); ts <= perLane; {
+				ev := mkt("UPBIT", ts, 100)
+				ev.Symbol = symbol
+				if !lane.Send(ev) {
+					time.Sleep(10 * time.Microsecond)
+					continue
+				}
+				if ts%500 == 0 {
+					time.Sleep(time.Millisecond)
+				}
+				ts++
+			}
+		})
+	}
+	wg.Wait()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		mu.Lock()
+		defer mu.Unlock()
+		t.Fatalf("processed %d of %d events", count, 2*perLane+1)
+	}
+	if got := seq.GetNextSeq(); got != 2*perLane+2 {
+		t.Errorf("next seq = %d; want %d", got, 2*perLane+2)
+	}
+}
+
+// The pipeline benchmarks feed b.N market updates from one gateway goroutine
+// until the sequencer has processed them all, through the shared channel and
+// through a lane of the same size.
+
+func BenchmarkSequencer_InboxChannel(b *testing.B) {
+	benchmarkInbox(b, func(seq *Sequencer) func(event.Event) bool {
+		inbox := seq.Inbox()
+		return func(ev event.Event) bool {
+			select {
+			case inbox <- ev:
+				return true
+			default:
+				return false
+			}
+		}
+	})
+}
+
+func BenchmarkSequencer_InboxRing(b *testing.B) {
+	benchmarkInbox(b, func(seq *Sequencer) func(event.Event) bool {
+		return seq.NewRingInbox(1024).Send
+	})
+}
+
+func benchmarkInbox(b *testing.B, sender func(*Sequencer) func(event.Event) bool) {
+	done := make(chan struct{})
+	processed := 0
+	seq := NewSequencer(1024, nil, nil, func(*domain.MarketState) {
+		if processed++; processed == b.N {
+			close(done)
+		}
+	})
+	send := sender(seq)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go seq.Run(ctx)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; {
+		ev := event.AcquireMarketUpdateEvent()
+		ev.Ts = quant.TimeStamp(i + 1)
+		ev.Symbol = "BTC"
+		ev.PriceMicros = 50000000000
+		ev.Exchange = "UPBIT"
+		for !send(ev) {
+			runtime.Gosched() // Full: let the sequencer catch up
+		}
+		i++
+	}
+	<-done
+}
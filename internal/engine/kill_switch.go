@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/pkg/safe"
+	"log/slog"
+)
+
+// handleHalt applies the kill switch (caller holds s.mu): order dispatch stops
+// like CmdHalt and every open order is canceled through the OMS. Cancels for
+// orders at a venue are only sent live; on replay the logged venue updates
+// close them.
+func (s *Sequencer) handleHalt(e *event.HaltEvent, replay bool) {
+	if !replay {
+		slog.Error("KILL_SWITCH",
+			slog.String("reason", e.Reason),
+			slog.String("detail", e.Detail),
+			slog.Uint64("seq", e.Seq))
+	}
+	s.halted = true
+	s.haltReason = e.Reason
+	s.drawdownFired = false
+
+	if s.orders == nil {
+		return
+	}
+	for _, mo := range s.orders.CancelAll(e.Seq, e.Ts, replay) {
+		if s.strategy != nil {
+			s.strategy.OnOrderUpdate(mo.Order)
+		}
+	}
+}
+
+// SetDrawdownHalt calls onBreach once the net PnL of all positions falls
+// limitMicros below its peak, so it can trigger the kill switch
+// (ControlClient.Kill with event.HaltDrawdown). onBreach runs on the hotpath
+// and must not block; it is called once per breach and never on replay, where
+// the recorded HaltEvent is re-applied instead. Must be called before Run.
+func (s *Sequencer) SetDrawdownHalt(limitMicros int64, onBreach func(drawdownMicros int64)) {
+	s.drawdownLimit = limitMicros
+	s.onDrawdown = onBreach
+}
+
+// checkDrawdown tracks the PnL peak after positions changed (caller holds s.mu).
+func (s *Sequencer) checkDrawdown() {
+	if s.drawdownLimit <= 0 {
+		return
+	}
+	pnl := s.positions.NetPnLMicros()
+	s.pnlPeak = max(s.pnlPeak, pnl)
+	drawdown := safe.SafeSub(s.pnlPeak, pnl)
+	if drawdown < s.drawdownLimit || s.halted || s.drawdownFired || s.replaying || s.onDrawdown == nil {
+		return
+	}
+	s.drawdownFired = true
+	slog.Warn("DRAWDOWN_LIMIT_BREACHED",
+		slog.Int64("drawdown", drawdown),
+		slog.Int64("limit", s.drawdownLimit),
+		slog.Int64("peak", s.pnlPeak))
+	s.onDrawdown(drawdown)
+}
+
+// resetDrawdown restarts peak tracking from the current PnL, so resuming after
+// a drawdown halt does not trip the switch again at once (caller holds s.mu).
+func (s *Sequencer) resetDrawdown() {
+	s.pnlPeak = s.positions.NetPnLMicros()
+	s.drawdownFired = false
+}
+
+// HaltReason returns the trigger of the last kill switch (event.Halt*), or ""
+// if it never fired.
+func (s *Sequencer) HaltReason() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.haltReason
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+func TestSequencer_KillSwitchCancelsAndHalts(t *testing.T) {
+	strat := &orderRecorder{}
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100}) // t-1-1 sent
+	<-oms.Requests()
+	oco, _ := oms.Submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeOCO, PriceMicros: 110, StopPriceMicros: 90, QtySats: 1}, 2, 0, false)
+
+	seq.ProcessEventForTest(&event.HaltEvent{Reason: event.HaltManual, Detail: "operator"})
+
+	if !seq.Halted() || seq.HaltReason() != event.HaltManual {
+		t.Fatalf("kill switch must halt: halted=%v reason=%q", seq.Halted(), seq.HaltReason())
+	}
+	if o, _ := seq.GetOrder(oco.ID); o.Status != domain.OrderStatusCanceled {
+		t.Errorf("armed order must be canceled locally: %+v", o)
+	}
+	if len(strat.updates) != 1 || strat.updates[0].ID != oco.ID {
+		t.Errorf("strategy must see the local cancel: %+v", strat.updates)
+	}
+	req := <-oms.Cancels()
+	if !req.Cancel || req.OrderID != "t-1-1" {
+		t.Errorf("expected a cancel request for the venue order, got %+v", req)
+	}
+
+	// Monitor-only until RESUME_TRADING; the venue's answer closes the order
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if len(oms.Requests()) != 0 {
+		t.Error("no order may be sent while halted")
+	}
+	seq.ProcessEventForTest(update("t-1-1", domain.OrderStatusCanceled, 0))
+	if open := seq.GetOpenOrders(); len(open) != 0 {
+		t.Errorf("expected no open orders, got %+v", open)
+	}
+}
+
+func TestSequencer_KillSwitchReplayDoesNotResend(t *testing.T) {
+	seq := NewSequencer(10, nil, &orderRecorder{}, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+
+	seq.ReplayEvent(&event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: 1}, Symbol: "BTC", PriceMicros: 100})
+	seq.ReplayEvent(&event.HaltEvent{BaseEvent: event.BaseEvent{Seq: 2}, Reason: event.HaltDrawdown})
+
+	if len(oms.Requests()) != 0 {
+		t.Error("replay must not send cancel requests")
+	}
+	if !seq.Halted() {
+		t.Error("replayed kill switch must halt")
+	}
+	if o, _ := seq.GetOrder("t-1-1"); o.Status != domain.OrderStatusSent {
+		t.Errorf("venue order stays open until its logged update: %+v", o)
+	}
+}
+
+func TestSequencer_DrawdownHalt(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+	var breaches []int64
+	seq.SetDrawdownHalt(10_000_000, func(drawdown int64) { breaches = append(breaches, drawdown) })
+
+	mo, _ := oms.Submit(domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 100_000_000, QtySats: quant.QtyScale}, 1, 0, true)
+	seq.ProcessEventForTest(&event.OrderUpdateEvent{OrderID: mo.ID, Status: domain.OrderStatusFilled, PriceMicros: 100_000_000, AccumulatedQtySats: quant.QtyScale})
+
+	for _, price := range []int64{105_000_000, 96_000_000, 95_000_000, 94_000_000} {
+		seq.ProcessEventForTest(tick("", price))
+	}
+	if len(breaches) != 1 || breaches[0] != 10_000_000 {
+		t.Fatalf("expected one breach of 10 from the peak of 5, got %v", breaches)
+	}
+
+	seq.ProcessEventForTest(&event.HaltEvent{Reason: event.HaltDrawdown})
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdResumeTrading})
+	seq.ProcessEventForTest(tick("", 93_000_000))
+	if len(breaches) != 1 {
+		t.Errorf("resuming must restart the peak, got %v", breaches)
+	}
+	seq.ProcessEventForTest(tick("", 84_000_000))
+	if len(breaches) != 2 {
+		t.Errorf("a new drawdown past the limit must fire again, got %v", breaches)
+	}
+}
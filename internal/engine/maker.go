@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"log/slog"
+)
+
+// makerOrder is a passive router leg awaiting its fill or escalation.
+type makerOrder struct {
+	id        string
+	deadline  quant.TimeStamp
+	taker     domain.Order // Sent for the unfilled rest once the resting order is canceled
+	canceling bool         // Timed out: cancel requested, escalate on CANCELED
+}
+
+// expireMakers requests the cancel of every resting maker leg past its
+// deadline and forgets the ones that finished (caller holds s.mu). Time comes
+// from event timestamps, so a replay escalates at the same seqs.
+func (s *Sequencer) expireMakers(ts quant.TimeStamp) {
+	if len(s.makers) == 0 {
+		return
+	}
+	kept := s.makers[:0]
+	for _, m := range s.makers {
+		mo, ok := s.orders.orders[m.id]
+		if !ok || !mo.IsOpen() {
+			continue
+		}
+		if !m.canceling && ts >= m.deadline {
+			m.canceling = true
+			s.orders.Cancel(mo, s.nextSeq, ts, s.replaying)
+		}
+		kept = append(kept, m)
+	}
+	clear(s.makers[len(kept):])
+	s.makers = kept
+}
+
+// escalateMaker sends the unfilled rest of a maker leg canceled after its
+// timeout as the leg's taker order (caller holds s.mu). Legs canceled for
+// other reasons (kill switch, venue) are not chased, nor is anything while
+// order dispatch is stopped.
+func (s *Sequencer) escalateMaker(mo *ManagedOrder, ts quant.TimeStamp) {
+	if mo.IsOpen() || len(s.makers) == 0 {
+		return
+	}
+	for i, m := range s.makers {
+		if m.id != mo.ID {
+			continue
+		}
+		s.makers = append(s.makers[:i], s.makers[i+1:]...)
+		rest := mo.QtySats - mo.FilledQtySats
+		if !m.canceling || mo.Status != domain.OrderStatusCanceled || rest <= 0 {
+			return
+		}
+		if s.dispatchStopped() {
+			return
+		}
+		taker := m.taker
+		taker.QtySats = rest
+		if !s.replaying {
+			slog.Info("MAKER_ESCALATED", slog.String("order_id", mo.ID), slog.String("strategy", mo.Strategy), slog.String("exchange", mo.Exchange), slog.Int64("rest_sats", rest))
+		}
+		s.submitOrder(&taker, ts)
+		return
+	}
+}
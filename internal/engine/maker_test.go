@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// twoSided builds a one-level BTC book snapshot of whole-unit prices.
+func twoSided(exchange string, bid, ask int64) *event.OrderBookUpdateEvent {
+	return &event.OrderBookUpdateEvent{
+		Exchange: exchange, Symbol: "BTC", Snapshot: true,
+		Bids: []domain.BookLevel{{PriceMicros: quant.PriceMicros(bid * quant.PriceScale), QtySats: 10 * tenthBTC}},
+		Asks: []domain.BookLevel{{PriceMicros: quant.PriceMicros(ask * quant.PriceScale), QtySats: 10 * tenthBTC}},
+	}
+}
+
+func newMakerRouter() *Router {
+	return NewRouter(RouterConfig{
+		Venues:       []RouteVenue{{Exchange: "UPBIT", Quote: "KRW", FeeBps: 5, MakerBps: 5}},
+		MakerTimeout: 30 * time.Second,
+	})
+}
+
+func TestRouter_MakerOrTaker(t *testing.T) {
+	r := newMakerRouter()
+	book := func(bid, ask int64) map[bookKey]*domain.OrderBook {
+		e := twoSided("UPBIT", bid, ask)
+		b := domain.NewOrderBook(e.Exchange, e.Symbol)
+		b.Apply(true, e.Bids, e.Asks, 0)
+		return map[bookKey]*domain.OrderBook{{"UPBIT", "BTC"}: b}
+	}
+	wide, tight := book(99_950, 100_050), book(99_995, 100_000) // 10bp / 0.5bp spread, equal fees
+	buy := func(urgency string) *domain.Order {
+		return &domain.Order{Symbol: "BTC", Side: domain.SideBuy, QtySats: tenthBTC, Urgency: urgency}
+	}
+
+	tests := []struct {
+		name     string
+		order    *domain.Order
+		books    map[bookKey]*domain.OrderBook
+		maker    bool
+		makerBid int64
+	}{
+		{"wide spread rests", buy(""), wide, true, 99_950},
+		{"tight spread takes", buy(""), tight, false, 0},
+		{"low urgency rests anyway", buy(domain.UrgencyLow), tight, true, 99_995},
+		{"high urgency takes", buy(domain.UrgencyHigh), wide, false, 0},
+	}
+	for _, tt := range tests {
+		legs := r.Route(tt.order, tt.books, 0)
+		if len(legs) != 1 || legs[0].Maker != tt.maker || legs[0].MakerPriceMicros != tt.makerBid*quant.PriceScale {
+			t.Errorf("%s: %+v", tt.name, legs)
+		}
+	}
+
+	// A cap above the ask: rest at the bid, keep the cap for the taker order
+	capped := buy("")
+	capped.Type, capped.PriceMicros = domain.OrderTypeLimit, 100_100*quant.PriceScale
+	legs := r.Route(capped, wide, 0)
+	if len(legs) != 1 || !legs[0].Maker || legs[0].MakerPriceMicros != 99_950*quant.PriceScale || legs[0].PriceMicros <= legs[0].MakerPriceMicros {
+		t.Errorf("capped leg must rest at the bid and keep its taker cap: %+v", legs)
+	}
+	// A cap below the bid: never rest above it
+	capped.PriceMicros = 99_900 * quant.PriceScale
+	if legs = r.Route(capped, wide, 0); len(legs) != 1 || !legs[0].Maker || legs[0].MakerPriceMicros != legs[0].PriceMicros {
+		t.Errorf("maker price must not exceed the cap: %+v", legs)
+	}
+
+	// Without a timeout the router always takes
+	if legs := newTestRouter().Route(buy(domain.UrgencyLow), wide, 0); len(legs) != 1 || legs[0].Maker {
+		t.Errorf("maker path needs a timeout: %+v", legs)
+	}
+}
+
+func TestSequencer_MakerEscalatesAfterTimeout(t *testing.T) {
+	seq := NewSequencer(10, nil, &buyStrategy{}, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+	seq.SetRouter(newMakerRouter())
+
+	at := func(e *event.MarketUpdateEvent, ts time.Duration) *event.MarketUpdateEvent {
+		e.Ts = quant.TimeStamp(ts.Microseconds())
+		return e
+	}
+	seq.ProcessEventForTest(twoSided("UPBIT", 99_950, 100_050))
+	seq.ProcessEventForTest(at(tick("UPBIT", 100_000*quant.PriceScale), time.Second))
+
+	rest := <-oms.Requests()
+	if rest.Type != domain.OrderTypeLimit || rest.PriceMicros != 99_950*quant.PriceScale || rest.QtySats != 10*tenthBTC {
+		t.Fatalf("expected a resting LIMIT at the bid: %+v", rest)
+	}
+	seq.ProcessEventForTest(&event.OrderUpdateEvent{OrderID: rest.OrderID, Status: domain.OrderStatusPartiallyFilled, AccumulatedQtySats: 4 * tenthBTC})
+
+	seq.ProcessEventForTest(at(tick("UPBIT", 100_000*quant.PriceScale), 30*time.Second))
+	if len(oms.Requests()) != 0 {
+		t.Fatal("nothing may happen before the timeout")
+	}
+	seq.ProcessEventForTest(at(tick("UPBIT", 100_000*quant.PriceScale), 31*time.Second))
+	if cancel := <-oms.Cancels(); !cancel.Cancel || cancel.OrderID != rest.OrderID {
+		t.Fatalf("expected the resting order to be canceled: %+v", cancel)
+	}
+	seq.ProcessEventForTest(at(tick("UPBIT", 100_000*quant.PriceScale), 31*time.Second))
+	if len(oms.Cancels()) != 0 {
+		t.Fatal("the cancel must be requested once")
+	}
+
+	seq.ProcessEventForTest(&event.OrderUpdateEvent{OrderID: rest.OrderID, Status: domain.OrderStatusCanceled, AccumulatedQtySats: 4 * tenthBTC})
+	take := <-oms.Requests()
+	if take.Type != domain.OrderTypeMarket || take.Exchange != "UPBIT" || take.QtySats != 6*tenthBTC || take.Cancel {
+		t.Errorf("expected the unfilled rest as a MARKET order: %+v", take)
+	}
+	if len(seq.makers) != 0 {
+		t.Errorf("escalated leg still tracked: %d", len(seq.makers))
+	}
+}
+
+func TestSequencer_MakerNotChasedAfterKill(t *testing.T) {
+	seq := NewSequencer(10, nil, &buyStrategy{}, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+	seq.SetRouter(newMakerRouter())
+
+	seq.ProcessEventForTest(twoSided("UPBIT", 99_950, 100_050))
+	seq.ProcessEventForTest(tick("UPBIT", 100_000*quant.PriceScale))
+	rest := <-oms.Requests()
+
+	// Canceled by someone else before the timeout: the rest is not taken
+	seq.ProcessEventForTest(&event.OrderUpdateEvent{OrderID: rest.OrderID, Status: domain.OrderStatusCanceled})
+	if len(oms.Requests()) != 0 || len(seq.makers) != 0 {
+		t.Errorf("a leg canceled before its timeout must be dropped, not escalated")
+	}
+}
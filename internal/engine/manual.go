@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"cmp"
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// ManualStrategy is the Order.Strategy of operator orders, so logs and the
+// OMS tell them from strategy orders.
+const ManualStrategy = "manual"
+
+// ErrInvalidManualOrder is wrapped by ValidateManualOrder errors.
+var ErrInvalidManualOrder = errors.New("invalid manual order")
+
+// ValidateManualOrder checks a ManualOrderEvent before it is sent: a PLACE
+// needs a symbol, BUY or SELL, MARKET or LIMIT, a positive quantity and, for
+// a LIMIT, a positive price.
+func ValidateManualOrder(e *event.ManualOrderEvent) error {
+	switch e.Action {
+	case event.ManualPlace:
+	case event.ManualClose, event.ManualCancelAll:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidManualOrder, e.Action)
+	}
+	switch {
+	case e.Symbol == "":
+		return fmt.Errorf("%w: symbol is required", ErrInvalidManualOrder)
+	case e.Side != domain.SideBuy && e.Side != domain.SideSell:
+		return fmt.Errorf("%w: side must be BUY or SELL", ErrInvalidManualOrder)
+	case e.OrderType != domain.OrderTypeMarket && e.OrderType != domain.OrderTypeLimit:
+		return fmt.Errorf("%w: type must be MARKET or LIMIT", ErrInvalidManualOrder)
+	case e.QtySats <= 0:
+		return fmt.Errorf("%w: qty must be positive", ErrInvalidManualOrder)
+	case e.OrderType == domain.OrderTypeLimit && e.PriceMicros <= 0:
+		return fmt.Errorf("%w: a LIMIT order needs a price", ErrInvalidManualOrder)
+	}
+	return nil
+}
+
+// SendManualOrder validates e, stamps it with the next CONTROL seq and sends
+// it. It blocks like Send.
+func (c *ControlClient) SendManualOrder(ctx context.Context, e event.ManualOrderEvent) error {
+	if err := ValidateManualOrder(&e); err != nil {
+		return err
+	}
+	return c.deliver(ctx, func(base event.BaseEvent) event.Event {
+		e.BaseEvent = base
+		return &e
+	})
+}
+
+// handleManualOrder applies an operator order action (caller holds s.mu).
+// Orders go through the router, the operator risk limit and the OMS (risk
+// engine, reduce-only) like strategy orders, but not the strategy's trade
+// guard. While order dispatch is stopped only CANCEL_ALL has an effect.
+func (s *Sequencer) handleManualOrder(e *event.ManualOrderEvent, replay bool) {
+	if !replay {
+		slog.Warn("MANUAL_ORDER",
+			slog.String("action", e.Action),
+			slog.String("exchange", e.Exchange),
+			slog.String("symbol", e.Symbol),
+			slog.String("side", e.Side),
+			slog.String("type", e.OrderType),
+			slog.Int64("price", int64(e.PriceMicros)),
+			slog.Int64("qty", int64(e.QtySats)),
+			slog.String("operator", e.Operator),
+			slog.String("reason", e.Reason),
+			slog.Uint64("seq", e.Seq))
+	}
+	if err := ValidateManualOrder(e); err != nil {
+		slog.Warn("MANUAL_ORDER_INVALID", slog.Uint64("seq", e.Seq), slog.Any("error", err))
+		return
+	}
+	if s.orders == nil {
+		return
+	}
+
+	switch e.Action {
+	case event.ManualCancelAll:
+		s.cancelManual(e)
+	case event.ManualClose:
+		if s.manualStopped(e) {
+			return
+		}
+		s.cancelManual(e)
+		s.closePositions(e.Exchange, e.Symbol, e.Ts)
+	case event.ManualPlace:
+		if s.manualStopped(e) {
+			return
+		}
+		order := domain.Order{
+			Symbol:      e.Symbol,
+			Side:        e.Side,
+			Type:        e.OrderType,
+			Exchange:    e.Exchange,
+			PriceMicros: int64(e.PriceMicros),
+			QtySats:     int64(e.QtySats),
+			Status:      domain.OrderStatusNew,
+			Strategy:    ManualStrategy,
+		}
+		if limit, ok := s.riskLimits[RiskLimitMaxOrderQtySats]; ok && order.QtySats > limit {
+			slog.Warn("MANUAL_ORDER_REFUSED", slog.Uint64("seq", e.Seq), slog.String("error", "above "+RiskLimitMaxOrderQtySats))
+			return
+		}
+		if s.router != nil && s.router.Routes(&order) {
+			s.routeOrder(&order, e.Ts)
+			return
+		}
+		s.submitOrder(&order, e.Ts)
+	}
+}
+
+// manualStopped reports (and logs) that order dispatch is stopped.
+func (s *Sequencer) manualStopped(e *event.ManualOrderEvent) bool {
+	if !s.dispatchStopped() {
+		return false
+	}
+	if !s.replaying {
+		slog.Warn("MANUAL_ORDER_REFUSED", slog.Uint64("seq", e.Seq), slog.String("error", "order dispatch is stopped"))
+	}
+	return true
+}
+
+// cancelManual cancels the open orders of venue e.Exchange ("" = all) and
+// symbol e.Symbol ("" = all) and tells the strategy.
+func (s *Sequencer) cancelManual(e *event.ManualOrderEvent) {
+	canceled := s.orders.CancelWhere(func(mo *ManagedOrder) bool {
+		return s.venueMatches(e.Exchange, mo.Exchange) && (e.Symbol == "" || mo.Symbol == e.Symbol)
+	}, e.Seq, e.Ts, s.replaying)
+	for _, mo := range canceled {
+		if s.strategy != nil {
+			s.strategy.OnOrderUpdate(mo.Order)
+		}
+	}
+}
+
+// closePositions submits a MARKET order closing each open position of venue
+// target ("" = all) and symbol ("" = all), ordered by venue and symbol so a
+// replay assigns the same IDs.
+func (s *Sequencer) closePositions(target, symbol string, ts quant.TimeStamp) {
+	var open []domain.Position
+	s.positions.Range(func(p domain.Position) {
+		if p.QtySats != 0 && s.venueMatches(target, p.Exchange) && (symbol == "" || p.Symbol == symbol) {
+			open = append(open, p)
+		}
+	})
+	slices.SortFunc(open, func(a, b domain.Position) int {
+		return cmp.Or(cmp.Compare(a.Exchange, b.Exchange), cmp.Compare(a.Symbol, b.Symbol))
+	})
+	for _, p := range open {
+		order := closingOrder(p)
+		order.Strategy = ManualStrategy
+		s.submitOrder(&order, ts)
+	}
+}
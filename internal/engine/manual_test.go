@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+)
+
+func TestSequencer_ManualOrders(t *testing.T) {
+	store, err := storage.NewEventStore(t.TempDir() + "/manual.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	seq := NewSequencer(10, store, nil, nil)
+	oms := NewOrderManager("t", 8)
+	seq.SetOrderManager(oms)
+
+	seq.ProcessEventForTest(&event.ManualOrderEvent{Action: event.ManualPlace, Symbol: "BTC", Side: domain.SideBuy, OrderType: domain.OrderTypeMarket, PriceMicros: 100, QtySats: 5, Operator: "ops"})
+	reqs := drainRequests(oms)
+	if len(reqs) != 1 || reqs[0].OrderID != "t-1-1" || reqs[0].Strategy != ManualStrategy || reqs[0].QtySats != 5 {
+		t.Fatalf("requests = %+v; want the manual buy", reqs)
+	}
+	seq.ProcessEventForTest(update("t-1-1", domain.OrderStatusFilled, 5))
+	seq.ProcessEventForTest(&event.ManualOrderEvent{Action: event.ManualPlace, Symbol: "ETH", Side: domain.SideBuy, OrderType: domain.OrderTypeLimit, PriceMicros: 50, QtySats: 2})
+	drainRequests(oms)
+
+	// The operator risk limit applies to manual orders too
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdSetRiskLimit, Target: RiskLimitMaxOrderQtySats, Value: 3})
+	seq.ProcessEventForTest(&event.ManualOrderEvent{Action: event.ManualPlace, Symbol: "BTC", Side: domain.SideBuy, OrderType: domain.OrderTypeMarket, QtySats: 4})
+	if reqs := drainRequests(oms); len(reqs) != 0 {
+		t.Errorf("order above the risk limit sent: %+v", reqs)
+	}
+
+	// Close BTC: its position is sold at market, the ETH limit order stays
+	seq.ProcessEventForTest(&event.ManualOrderEvent{Action: event.ManualClose, Symbol: "BTC"})
+	reqs = drainRequests(oms)
+	if len(reqs) != 1 || reqs[0].Side != domain.SideSell || reqs[0].Type != domain.OrderTypeMarket || reqs[0].QtySats != 5 || reqs[0].Symbol != "BTC" {
+		t.Fatalf("requests = %+v; want a market sell of the 5-sat BTC long", reqs)
+	}
+	seq.ProcessEventForTest(&event.ManualOrderEvent{Action: event.ManualCancelAll})
+	if reqs := drainRequests(oms); len(reqs) != 2 || !reqs[0].Cancel || !reqs[1].Cancel {
+		t.Errorf("requests = %+v; want both open orders canceled", reqs)
+	}
+
+	// A halt stops new manual orders
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdHalt})
+	seq.ProcessEventForTest(&event.ManualOrderEvent{Action: event.ManualPlace, Symbol: "BTC", Side: domain.SideBuy, OrderType: domain.OrderTypeMarket, QtySats: 1})
+	if reqs := drainRequests(oms); len(reqs) != 0 {
+		t.Errorf("manual order sent while halted: %+v", reqs)
+	}
+
+	// Replay rebuilds the same orders without sending them
+	replayed := NewSequencer(10, store, nil, nil)
+	replayOMS := NewOrderManager("t", 8)
+	replayed.SetOrderManager(replayOMS)
+	if err := replayed.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if reqs := drainRequests(replayOMS); len(reqs) != 0 {
+		t.Errorf("replay sent %d requests", len(reqs))
+	}
+	if replayed.StateHash() != seq.StateHash() {
+		t.Error("replay of manual orders diverged")
+	}
+}
+
+func TestControlClient_SendManualOrder(t *testing.T) {
+	inbox := make(chan event.Event, 2)
+	client := NewControlClient(inbox)
+	ctx := context.Background()
+	bad := event.ManualOrderEvent{Action: event.ManualPlace, Symbol: "BTC", Side: domain.SideBuy, OrderType: domain.OrderTypeLimit, QtySats: 1}
+	if err := client.SendManualOrder(ctx, bad); !errors.Is(err, ErrInvalidManualOrder) {
+		t.Errorf("limit without price: err = %v; want ErrInvalidManualOrder", err)
+	}
+	if err := client.Send(ctx, event.CmdHalt, "", 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendManualOrder(ctx, event.ManualOrderEvent{Action: event.ManualCancelAll}); err != nil {
+		t.Fatal(err)
+	}
+	<-inbox
+	if e := (<-inbox).(*event.ManualOrderEvent); e.Seq != 2 {
+		t.Errorf("manual order seq = %d; want 2 (shared CONTROL sequence)", e.Seq)
+	}
+}
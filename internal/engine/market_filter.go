@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// Filter reasons, in the order the chain checks them.
+const (
+	FilterExchange    = "exchange"     // Venue not in the allowlist
+	FilterInterval    = "min_interval" // Too soon after the last update passed on for the market
+	FilterPriceChange = "price_change" // Price moved less than the minimum since then
+)
+
+// MarketFilterConfig configures a MarketFilter. Zero values disable a stage.
+type MarketFilterConfig struct {
+	Exchanges    []string        // Allowlist of venues fed to the strategy (empty = all)
+	MinInterval  quant.TimeStamp // Minimum time between updates per exchange/symbol (micros)
+	MinChangeBps int64           // Minimum price move since the last passed update (1bp = 0.01%)
+}
+
+type filterKey struct{ exchange, symbol string } // Struct key: no string concat on the hotpath
+
+type filterMark struct {
+	price quant.PriceMicros
+	ts    quant.TimeStamp
+}
+
+// MarketFilter decides which market updates reach the strategy, so high-frequency
+// noise from one venue does not trigger needless strategy computation. Market
+// state is always updated; only the strategy call is skipped.
+//
+// The chain is: exchange allowlist -> min interval -> min price change. The
+// interval and price baselines are those of the last update that passed the
+// whole chain, per exchange/symbol. Time comes from the event timestamp, so
+// replay filters identically. Hotpath only (not goroutine-safe).
+type MarketFilter struct {
+	exchanges    map[string]bool
+	minInterval  quant.TimeStamp
+	minChangeBps int64
+	last         map[filterKey]filterMark
+	filtered     map[string]uint64 // By reason
+}
+
+// NewMarketFilter creates a filter chain from cfg.
+func NewMarketFilter(cfg MarketFilterConfig) *MarketFilter {
+	f := &MarketFilter{
+		minInterval:  cfg.MinInterval,
+		minChangeBps: cfg.MinChangeBps,
+		last:         make(map[filterKey]filterMark),
+		filtered:     make(map[string]uint64),
+	}
+	if len(cfg.Exchanges) > 0 {
+		f.exchanges = make(map[string]bool, len(cfg.Exchanges))
+		for _, ex := range cfg.Exchanges {
+			f.exchanges[ex] = true
+		}
+	}
+	return f
+}
+
+// Allow reports whether e should be passed to the strategy.
+func (f *MarketFilter) Allow(e *event.MarketUpdateEvent) bool {
+	if f.exchanges != nil && !f.exchanges[e.Exchange] {
+		f.filtered[FilterExchange]++
+		return false
+	}
+
+	key := filterKey{e.Exchange, e.Symbol}
+	prev, seen := f.last[key]
+	if seen {
+		if f.minInterval > 0 && e.Ts-prev.ts < f.minInterval {
+			f.filtered[FilterInterval]++
+			return false
+		}
+		if f.minChangeBps > 0 && !movedEnough(prev.price, e.PriceMicros, f.minChangeBps) {
+			f.filtered[FilterPriceChange]++
+			return false
+		}
+	}
+
+	f.last[key] = filterMark{price: e.PriceMicros, ts: e.Ts}
+	return true
+}
+
+// Filtered returns how many updates each stage has dropped so far.
+func (f *MarketFilter) Filtered() map[string]uint64 {
+	out := make(map[string]uint64, len(f.filtered))
+	for k, v := range f.filtered {
+		out[k] = v
+	}
+	return out
+}
+
+// movedEnough reports |next - prev| / prev >= bps / 10,000.
+func movedEnough(prev, next quant.PriceMicros, bps int64) bool {
+	if prev <= 0 {
+		return true
+	}
+	diff := int64(next - prev)
+	if diff < 0 {
+		diff = -diff
+	}
+	return safe.SafeMulDiv(diff, 10_000, int64(prev)) >= bps
+}
+
+// SetMarketFilter installs a filter chain in front of the strategy. Must be called before Run.
+func (s *Sequencer) SetMarketFilter(f *MarketFilter) {
+	s.marketFilter = f
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+func mkt(exchange string, ts, price int64) *event.MarketUpdateEvent {
+	return &event.MarketUpdateEvent{
+		BaseEvent:   event.BaseEvent{Ts: quant.TimeStamp(ts)},
+		Exchange:    exchange,
+		Symbol:      "BTC",
+		PriceMicros: quant.PriceMicros(price),
+	}
+}
+
+func TestMarketFilter_Chain(t *testing.T) {
+	f := NewMarketFilter(MarketFilterConfig{
+		Exchanges:    []string{"UPBIT", "BITGET_SPOT"},
+		MinInterval:  1_000_000, // 1s
+		MinChangeBps: 10,        // 0.1%
+	})
+
+	steps := []struct {
+		ev   *event.MarketUpdateEvent
+		want bool
+	}{
+		{mkt("BYBIT_SPOT", 0, 100_000), false},        // Not allowlisted
+		{mkt("UPBIT", 0, 100_000), true},              // First update always passes
+		{mkt("UPBIT", 500_000, 200_000), false},       // Too soon
+		{mkt("UPBIT", 1_000_000, 100_050), false},     // 5bp < 10bp
+		{mkt("UPBIT", 2_000_000, 99_900), true},       // 10bp down from the last passed price
+		{mkt("BITGET_SPOT", 2_100_000, 70_000), true}, // Baselines are per exchange
+		{mkt("UPBIT", 3_000_000, 99_950), false},      // 5bp from 99_900 (the rejected ones never move the baseline)
+	}
+	for i, s := range steps {
+		if got := f.Allow(s.ev); got != s.want {
+			t.Errorf("step %d (%s @%d): allow=%v, want %v", i, s.ev.Exchange, s.ev.Ts, got, s.want)
+		}
+	}
+
+	filtered := f.Filtered()
+	if filtered[FilterExchange] != 1 || filtered[FilterInterval] != 1 || filtered[FilterPriceChange] != 2 {
+		t.Errorf("unexpected filter counts %v", filtered)
+	}
+}
+
+// countingStrategy counts the updates it sees.
+type countingStrategy struct{ calls int }
+
+func (s *countingStrategy) OnMarketUpdate(domain.MarketState, []domain.Order) int {
+	s.calls++
+	return 0
+}
+
+func (s *countingStrategy) OnOrderUpdate(domain.Order) {}
+
+func TestSequencer_MarketFilterSkipsStrategyOnly(t *testing.T) {
+	strat := &countingStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	seq.SetMarketFilter(NewMarketFilter(MarketFilterConfig{Exchanges: []string{"UPBIT"}}))
+
+	seq.ProcessEventForTest(mkt("UPBIT", 1, 100))
+	seq.ProcessEventForTest(mkt("BYBIT_SPOT", 2, 70))
+
+	if strat.calls != 1 {
+		t.Errorf("strategy saw %d updates, want 1", strat.calls)
+	}
+	if state, ok := seq.GetMarketState("BTC"); !ok || state.PriceMicros != 70 {
+		t.Errorf("state must still follow filtered updates, got %+v", state)
+	}
+}
+
+// venueStrategy records the venue of each update and the balance book it got.
+type venueStrategy struct {
+	countingStrategy
+	venues []string
+	book   *domain.BalanceBook
+}
+
+func (s *venueStrategy) OnVenueUpdate(exchange string, _ domain.MarketState) {
+	s.venues = append(s.venues, exchange)
+}
+
+func (s *venueStrategy) SetBalances(book *domain.BalanceBook) { s.book = book }
+
+func TestSequencer_VenueUpdates(t *testing.T) {
+	strat := &venueStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	if strat.book != seq.BalanceBook() {
+		t.Error("strategy should get the engine balance book")
+	}
+	seq.SetMarketFilter(NewMarketFilter(MarketFilterConfig{Exchanges: []string{"UPBIT", "BITGET_SPOT"}}))
+
+	seq.ProcessEventForTest(mkt("UPBIT", 1, 100))
+	seq.ProcessEventForTest(mkt("BYBIT_SPOT", 2, 70))
+	seq.ProcessEventForTest(mkt("BITGET_SPOT", 3, 70))
+	if len(strat.venues) != 2 || strat.venues[0] != "UPBIT" || strat.venues[1] != "BITGET_SPOT" || strat.calls != 2 {
+		t.Errorf("unexpected venue updates %v (%d market updates)", strat.venues, strat.calls)
+	}
+}
@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+)
+
+// maxRecentNotices bounds the notices kept for GetRecentNotices.
+const maxRecentNotices = 50
+
+// handleNotice records an exchange announcement and passes it to the strategy.
+// Notices are ordered by the exchange's increasing ID; one not newer than the
+// last seen for its exchange (re-emitted after a restart) is ignored.
+func (s *Sequencer) handleNotice(e *event.NoticeEvent) {
+	if e.NoticeID <= s.noticeIDs[e.Exchange] {
+		return
+	}
+	s.noticeIDs[e.Exchange] = e.NoticeID
+
+	n := e.Notice()
+	s.notices = append(s.notices, n)
+	if len(s.notices) > maxRecentNotices {
+		s.notices = s.notices[len(s.notices)-maxRecentNotices:]
+	}
+
+	if h, ok := s.strategy.(strategy.NoticeHandler); ok && !s.strategyPaused {
+		h.OnNotice(n)
+	}
+}
+
+// GetRecentNotices returns the most recent notices, oldest first (thread-safe).
+func (s *Sequencer) GetRecentNotices() []domain.Notice {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]domain.Notice(nil), s.notices...)
+}
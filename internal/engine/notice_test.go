@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"testing"
+)
+
+type noticeStrategy struct {
+	countingStrategy
+	seen []domain.Notice
+}
+
+func (s *noticeStrategy) OnNotice(n domain.Notice) {
+	s.seen = append(s.seen, n)
+}
+
+func TestSequencer_Notice(t *testing.T) {
+	strat := &noticeStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+
+	notice := func(id int64, kind string) *event.NoticeEvent {
+		return &event.NoticeEvent{Source: "UPBIT_NOTICE", Exchange: "UPBIT", NoticeID: id, Kind: kind, Symbols: []string{"XYZ"}}
+	}
+	seq.ProcessEventForTest(notice(10, domain.NoticeListing))
+	seq.ProcessEventForTest(notice(10, domain.NoticeListing)) // Re-emitted after a restart: ignored
+	seq.ProcessEventForTest(notice(9, domain.NoticeWallet))   // Older: ignored
+	seq.ProcessEventForTest(notice(11, domain.NoticeDelisting))
+
+	if len(strat.seen) != 2 || strat.seen[1].Kind != domain.NoticeDelisting || strat.seen[0].Symbols[0] != "XYZ" {
+		t.Fatalf("unexpected notices passed to the strategy: %+v", strat.seen)
+	}
+	if recent := seq.GetRecentNotices(); len(recent) != 2 || recent[0].ID != 10 || recent[1].ID != 11 {
+		t.Errorf("unexpected recent notices: %+v", recent)
+	}
+}
@@ -0,0 +1,771 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/risk"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"errors"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// OMSExchange names the OMS as the "venue" of orders it refuses itself.
+const OMSExchange = "OMS"
+
+// DefaultOrderIDPrefix prefixes client order IDs when none is configured.
+const DefaultOrderIDPrefix = "cg"
+
+// ErrOrderQueueFull refuses an order the gateway queue had no room for.
+var ErrOrderQueueFull = errors.New("order queue full")
+
+// maxClosedOrders bounds how many finished orders stay queryable.
+const maxClosedOrders = 1024
+
+// cancelRetryMicros is how long a queued cancel may go unanswered before it is
+// queued again (see RetryCancels).
+const cancelRetryMicros = 2_000_000
+
+// Legs of a fired conditional order.
+const (
+	LegTakeProfit = "TAKE_PROFIT" // OCO limit leg, sent as LIMIT at PriceMicros
+	LegStop       = "STOP"        // OCO stop leg or trailing stop, sent as MARKET
+)
+
+// ManagedOrder is an order tracked by the OrderManager.
+type ManagedOrder struct {
+	domain.Order
+	FilledQtySats   int64           // Accumulated fill reported by the venue
+	FillPriceMicros int64           // Last reported fill price
+	UpdatedUnixM    quant.TimeStamp // Timestamp of the last applied change
+
+	BestMicros  int64  // TRAILING_STOP: best trigger price since armed
+	Leg         string // Conditional order: leg that fired (empty while armed)
+	FiredMicros int64  // Conditional order: trigger price that fired it
+	Stale       string // Why the sweeper requested its cancel (StaleTTL, StaleDrift; empty = not swept)
+
+	CancelPending bool `json:",omitempty"` // A cancel was requested and the venue has not closed the order yet
+
+	Refusal error `json:"-"` // Why the OMS rejected it itself (nil for venue outcomes)
+
+	cancelQueued bool            // The pending cancel is on the cancel lane (live only)
+	cancelSentAt quant.TimeStamp // When it was queued
+}
+
+// OrderManager owns the lifecycle of strategy orders:
+//
+//	NEW -> SENT -> ACK -> PARTIALLY_FILLED -> FILLED | CANCELED | REJECTED
+//
+// It assigns client order IDs, turns accepted strategy signals into
+// OrderRequestEvents for the execution gateway and reconciles the venue's
+// updates. Updates may skip states (a fill without an ACK) but never go back:
+// a stale ACK after a fill, or a shrinking fill, is ignored.
+//
+// Client IDs are derived from the seq of the triggering event, so a WAL replay
+// rebuilds the same orders. Requests are not persisted: on replay an order is
+// recorded as SENT without being re-sent, and the logged venue updates restore
+// the rest. Hotpath only (not goroutine-safe); the request queue is the only
+// crossing to the gateway.
+//
+// Conditional orders (OCO, trailing stop) start ARMED and are simulated here:
+// market updates from their trigger exchange decide when one is sent, as the
+// LIMIT or MARKET order of the leg that fired. Only one leg ever reaches the
+// venue, so an OCO needs no cancel. Triggers are market events, so a replay
+// fires them at the same seqs.
+//
+// With a risk engine every venue order passes its pre-trade checks first;
+// one that fails is REJECTED without reaching the queue.
+//
+// A request the full queue has no room for is refused with an event the
+// hotpath sequences right after (see RecordRefusals), so the refusal is in the
+// WAL like a venue rejection.
+//
+// Cancels are never dropped: they go on their own lane (Cancels), and an order
+// stays CancelPending, its cancel queued again by RetryCancels, until the
+// venue closes it.
+type OrderManager struct {
+	prefix    string
+	requests  chan *event.OrderRequestEvent
+	cancels   chan *event.OrderRequestEvent
+	orders    map[string]*ManagedOrder
+	closed    []string        // Finished order IDs, oldest first (bounded by maxClosedOrders)
+	canceling []*ManagedOrder // CancelPending orders, oldest request first
+
+	armed           map[string][]*ManagedOrder // Conditional orders by symbol, oldest first
+	triggerExchange string                     // Default trigger feed (empty = any exchange)
+	fired           []*ManagedOrder            // Reused result of Trigger
+
+	risk *risk.Engine // Pre-trade checks (optional)
+	open int          // Orders not yet terminal
+
+	resting       []restingOrder // LIMIT orders sent to a venue, oldest first (see SweepStale)
+	staleTTL      int64          // Default resting order TTL in micros (0 = none)
+	staleDriftBps int64          // Price drift that makes a resting order stale (0 = off)
+
+	lastSeq uint64 // Seq of the event that created the last order
+	seqN    int    // Orders created for lastSeq
+
+	dropped uint64 // Requests refused because the gateway queue was full
+	unknown uint64 // Updates for IDs this manager never issued
+
+	// Queue-full refusals awaiting the WAL (optional, see RecordRefusals)
+	refusals []*event.OrderRejectedEvent
+	nextSeq  *uint64
+}
+
+// NewOrderManager creates an OMS whose requests and cancels are queued (up to
+// queueSize each) for the execution gateway.
+func NewOrderManager(prefix string, queueSize int) *OrderManager {
+	if prefix == "" {
+		prefix = DefaultOrderIDPrefix
+	}
+	if queueSize <= 0 {
+		queueSize = 64
+	}
+	return &OrderManager{
+		prefix:   prefix,
+		requests: make(chan *event.OrderRequestEvent, queueSize),
+		cancels:  make(chan *event.OrderRequestEvent, queueSize),
+		orders:   make(map[string]*ManagedOrder),
+		armed:    make(map[string][]*ManagedOrder),
+	}
+}
+
+// SetTriggerExchange sets the exchange whose market updates trigger
+// conditional orders that name none (e.g. "BITGET_FUTURES"). Empty = any: a
+// symbol quoted in KRW and USDT would then mix both prices.
+func (m *OrderManager) SetTriggerExchange(exchange string) {
+	m.triggerExchange = exchange
+}
+
+// SetRisk makes every order pass r's pre-trade checks before it is sent.
+func (m *OrderManager) SetRisk(r *risk.Engine) {
+	m.risk = r
+}
+
+// RecordRefusals makes an order the full gateway queue refuses stay SENT and
+// come back as a RATE_LIMITED OrderRejectedEvent, stamped from seq (the ORDER
+// source sequence) when refused. The sequencer applies it right after the
+// event that sent the order, so it is in the WAL and replay closes the order
+// from the log. Otherwise the order is REJECTED in place, which replay cannot
+// reproduce.
+func (m *OrderManager) RecordRefusals(seq *uint64) {
+	if seq == nil {
+		seq = new(uint64)
+	}
+	m.nextSeq = seq
+}
+
+// Requests is the queue the execution gateway drains.
+func (m *OrderManager) Requests() <-chan *event.OrderRequestEvent {
+	return m.requests
+}
+
+// Cancels is the cancel lane: drain it apart from Requests, so cancels (the
+// kill switch) never wait behind new orders.
+func (m *OrderManager) Cancels() <-chan *event.OrderRequestEvent {
+	return m.cancels
+}
+
+// nextID returns "<prefix>-<seq>-<n>", n counting orders created by one event.
+func (m *OrderManager) nextID(seq uint64) string {
+	if seq != m.lastSeq {
+		m.lastSeq = seq
+		m.seqN = 0
+	}
+	m.seqN++
+	return m.prefix + "-" + strconv.FormatUint(seq, 10) + "-" + strconv.Itoa(m.seqN)
+}
+
+// Submit registers order (ID and status are assigned here) and queues it for the
+// gateway unless replay is set. A conditional order is armed instead. It
+// returns false if the trigger is invalid, a risk check failed or the queue was
+// full without an event sink; the order is then recorded as REJECTED with the
+// cause in Refusal.
+func (m *OrderManager) Submit(order domain.Order, seq uint64, ts quant.TimeStamp, replay bool) (*ManagedOrder, bool) {
+	order.ID = m.nextID(seq)
+	order.Status = domain.OrderStatusNew
+	order.CreatedUnixM = int64(ts)
+	mo := &ManagedOrder{Order: order, UpdatedUnixM: ts}
+	m.orders[order.ID] = mo
+	m.open++
+
+	if order.IsConditional() {
+		if err := order.TriggerError(); err != nil {
+			mo.Refusal = err
+			m.finish(mo, domain.OrderStatusRejected)
+			return mo, false
+		}
+		if mo.TriggerExchange == "" {
+			mo.TriggerExchange = m.triggerExchange
+		}
+		mo.Status = domain.OrderStatusArmed
+		m.armed[order.Symbol] = append(m.armed[order.Symbol], mo)
+		return mo, true
+	}
+	return mo, m.send(mo, order.Type, order.PriceMicros, seq, ts, replay)
+}
+
+// send checks the venue order for mo against the risk engine, queues it
+// (unless replay) and marks it SENT. The check also runs on replay: it decides
+// from replicated state, so the same orders are refused. A refused order is
+// recorded as REJECTED and false is returned.
+func (m *OrderManager) send(mo *ManagedOrder, orderType string, priceMicros int64, seq uint64, ts quant.TimeStamp, replay bool) bool {
+	if m.risk != nil {
+		if err := m.risk.Check(&mo.Order, orderType, priceMicros, m.openBeside(mo), ts); err != nil {
+			if !replay {
+				slog.Warn("RISK_REJECTED", slog.String("order_id", mo.ID), slog.String("strategy", mo.Strategy), slog.String("reason", err.Error()))
+			}
+			mo.Refusal = err
+			m.finish(mo, domain.OrderStatusRejected)
+			return false
+		}
+	}
+	if !replay {
+		req := &event.OrderRequestEvent{
+			OrderID:     mo.ID,
+			Symbol:      mo.Symbol,
+			Side:        mo.Side,
+			Type:        orderType,
+			Market:      mo.Market,
+			Exchange:    mo.Exchange,
+			PriceMicros: quant.PriceMicros(priceMicros),
+			QtySats:     quant.QtySats(mo.QtySats),
+			Strategy:    mo.Strategy,
+		}
+		req.Seq = seq
+		req.Ts = ts
+		select {
+		case m.requests <- req:
+		default:
+			// Never block the hotpath on a slow gateway
+			m.dropped++
+			slog.Warn("OMS_QUEUE_FULL", slog.String("order_id", mo.ID), slog.String("strategy", mo.Strategy), slog.String("symbol", mo.Symbol))
+			if m.nextSeq == nil {
+				mo.Refusal = ErrOrderQueueFull
+				m.finish(mo, domain.OrderStatusRejected)
+				return false
+			}
+			m.rejectQueued(mo, priceMicros, ts)
+		}
+	}
+	if orderType == domain.OrderTypeLimit {
+		m.resting = append(m.resting, restingOrder{mo: mo, since: ts})
+	}
+	mo.Status = domain.OrderStatusSent
+	return true
+}
+
+// openBeside returns what the open orders other than mo commit: their count,
+// and the unfilled quantity of those at a venue on mo's venue/symbol.
+func (m *OrderManager) openBeside(mo *ManagedOrder) risk.Open {
+	open := risk.Open{Orders: m.open - 1}
+	for _, o := range m.orders {
+		if o == mo || o.Exchange != mo.Exchange || o.Symbol != mo.Symbol {
+			continue
+		}
+		switch o.Status {
+		case domain.OrderStatusSent, domain.OrderStatusAcked, domain.OrderStatusPartiallyFilled:
+		default:
+			continue // Terminal, or not at a venue yet
+		}
+		rest := safe.SafeSub(o.QtySats, o.FilledQtySats)
+		if o.Side == domain.SideSell {
+			open.SellSats = safe.SafeAdd(open.SellSats, rest)
+		} else {
+			open.BuySats = safe.SafeAdd(open.BuySats, rest)
+		}
+	}
+	return open
+}
+
+// Trigger checks the armed orders of e's symbol against its price; trailing
+// stops follow it. With fire unset (order dispatch stopped) nothing fires.
+// Fired orders are sent like Submit and returned, valid until the next call;
+// one refused by a risk check or a full queue is REJECTED.
+func (m *OrderManager) Trigger(e *event.MarketUpdateEvent, seq uint64, fire, replay bool) []*ManagedOrder {
+	m.fired = m.fired[:0]
+	armed := m.armed[e.Symbol]
+	if len(armed) == 0 {
+		return m.fired
+	}
+
+	price := int64(e.PriceMicros)
+	kept := armed[:0]
+	for _, mo := range armed {
+		if mo.TriggerExchange != "" && mo.TriggerExchange != e.Exchange {
+			kept = append(kept, mo)
+			continue
+		}
+		leg, orderType, limit := mo.evaluate(price)
+		if leg == "" || !fire {
+			kept = append(kept, mo)
+			continue
+		}
+		mo.Leg = leg
+		mo.FiredMicros = price
+		mo.UpdatedUnixM = e.Ts
+		m.send(mo, orderType, limit, seq, e.Ts, replay)
+		m.fired = append(m.fired, mo)
+	}
+	clear(armed[len(kept):])
+	if len(kept) == 0 {
+		delete(m.armed, e.Symbol)
+	} else {
+		m.armed[e.Symbol] = kept
+	}
+	return m.fired
+}
+
+// evaluate returns the leg that price fires, with the venue order type and
+// limit to send, or "" if none. Trailing stops first move their best price.
+func (mo *ManagedOrder) evaluate(price int64) (leg, orderType string, limit int64) {
+	sell := mo.Side == domain.SideSell
+	switch mo.Type {
+	case domain.OrderTypeOCO:
+		switch {
+		case sell && price >= mo.PriceMicros, !sell && price <= mo.PriceMicros:
+			return LegTakeProfit, domain.OrderTypeLimit, mo.PriceMicros
+		case sell && price <= mo.StopPriceMicros, !sell && price >= mo.StopPriceMicros:
+			return LegStop, domain.OrderTypeMarket, 0
+		}
+	case domain.OrderTypeTrailingStop:
+		if mo.BestMicros == 0 || (sell && price > mo.BestMicros) || (!sell && price < mo.BestMicros) {
+			mo.BestMicros = price
+		}
+		// A fresh best never fires (the stop rounds onto it at tiny prices)
+		if price != mo.BestMicros && ((sell && price <= mo.TrailStopMicros()) || (!sell && price >= mo.TrailStopMicros())) {
+			return LegStop, domain.OrderTypeMarket, 0
+		}
+	}
+	return "", "", 0
+}
+
+// TrailStopMicros is the current stop level of an armed trailing stop: the
+// best price moved back by TrailBps. 0 before the first trigger price.
+func (mo *ManagedOrder) TrailStopMicros() int64 {
+	if mo.Type != domain.OrderTypeTrailingStop || mo.BestMicros == 0 {
+		return 0
+	}
+	bps := int64(10_000) - mo.TrailBps
+	if mo.Side != domain.SideSell {
+		bps = 10_000 + mo.TrailBps
+	}
+	return safe.SafeMulDiv(mo.BestMicros, bps, 10_000)
+}
+
+// statusRank orders the lifecycle; a transition must not decrease the rank.
+func statusRank(status string) int {
+	switch status {
+	case domain.OrderStatusNew, domain.OrderStatusArmed:
+		return 0
+	case domain.OrderStatusSent:
+		return 1
+	case domain.OrderStatusAcked:
+		return 2
+	case domain.OrderStatusPartiallyFilled:
+		return 3
+	case domain.OrderStatusFilled, domain.OrderStatusCanceled, domain.OrderStatusRejected:
+		return 4
+	}
+	return -1
+}
+
+// Apply reconciles a venue update. It returns the order if its state changed,
+// nil for unknown IDs and stale or invalid updates.
+func (m *OrderManager) Apply(e *event.OrderUpdateEvent) *ManagedOrder {
+	mo, ok := m.orders[e.OrderID]
+	if !ok {
+		m.unknown++
+		return nil
+	}
+	if !mo.IsOpen() {
+		return nil // Terminal states are final
+	}
+
+	rank, cur := statusRank(e.Status), statusRank(mo.Status)
+	filled := int64(e.AccumulatedQtySats)
+	switch {
+	case rank < 0 || rank < cur:
+		return nil
+	case rank < 4 && filled < mo.FilledQtySats:
+		return nil // Out-of-order partial fill: fills only accumulate
+	case rank == cur && filled <= mo.FilledQtySats:
+		return nil // Duplicate
+	}
+
+	if filled > mo.FilledQtySats {
+		mo.FilledQtySats = filled
+		mo.FillPriceMicros = int64(e.PriceMicros)
+	}
+	mo.UpdatedUnixM = e.Ts
+	if rank == 4 {
+		m.finish(mo, e.Status)
+	} else {
+		mo.Status = e.Status
+	}
+	return mo
+}
+
+// Reject marks an order the venue refused. It returns nil for unknown or
+// already finished orders.
+func (m *OrderManager) Reject(e *event.OrderRejectedEvent) *ManagedOrder {
+	mo, ok := m.orders[e.OrderID]
+	if !ok {
+		m.unknown++
+		return nil
+	}
+	if !mo.IsOpen() {
+		return nil
+	}
+	mo.UpdatedUnixM = e.Ts
+	m.finish(mo, domain.OrderStatusRejected)
+	return mo
+}
+
+// finish moves mo to a terminal status and evicts the oldest finished orders.
+func (m *OrderManager) finish(mo *ManagedOrder, status string) {
+	mo.Status = status
+	m.open--
+	if mo.CancelPending {
+		mo.CancelPending = false
+		m.canceling = slices.DeleteFunc(m.canceling, func(c *ManagedOrder) bool { return c == mo })
+	}
+	m.closed = append(m.closed, mo.ID)
+	if len(m.closed) > maxClosedOrders {
+		delete(m.orders, m.closed[0])
+		m.closed = m.closed[1:]
+	}
+}
+
+// Order returns a copy of the tracked order with the given client ID.
+func (m *OrderManager) Order(id string) (ManagedOrder, bool) {
+	mo, ok := m.orders[id]
+	if !ok {
+		return ManagedOrder{}, false
+	}
+	return *mo, true
+}
+
+// OpenOrders returns copies of all orders not yet in a terminal state, oldest first.
+func (m *OrderManager) OpenOrders() []ManagedOrder {
+	var out []ManagedOrder
+	for _, mo := range m.openSorted() {
+		out = append(out, *mo)
+	}
+	return out
+}
+
+// openSorted returns the orders not yet in a terminal state, oldest first.
+func (m *OrderManager) openSorted() []*ManagedOrder {
+	var out []*ManagedOrder
+	for _, mo := range m.orders {
+		if mo.IsOpen() {
+			out = append(out, mo)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedUnixM != out[j].CreatedUnixM {
+			return out[i].CreatedUnixM < out[j].CreatedUnixM
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// CancelAll cancels every open order, oldest first. Armed conditional orders
+// never reached a venue and are CANCELED here, also on replay; they are
+// returned. Orders at a venue become CancelPending and, unless replay is set,
+// a cancel request is queued: the venue's CANCELED (or fill) update closes
+// them.
+func (m *OrderManager) CancelAll(seq uint64, ts quant.TimeStamp, replay bool) []*ManagedOrder {
+	return m.CancelWhere(func(*ManagedOrder) bool { return true }, seq, ts, replay)
+}
+
+// CancelWhere cancels the open orders match selects, like CancelAll.
+func (m *OrderManager) CancelWhere(match func(*ManagedOrder) bool, seq uint64, ts quant.TimeStamp, replay bool) []*ManagedOrder {
+	var canceled []*ManagedOrder
+	for _, mo := range m.openSorted() {
+		if !match(mo) {
+			continue
+		}
+		if mo.Status == domain.OrderStatusArmed {
+			mo.UpdatedUnixM = ts
+			m.finish(mo, domain.OrderStatusCanceled)
+			m.disarm(mo)
+			canceled = append(canceled, mo)
+			continue
+		}
+		m.requestCancel(mo, seq, ts, replay)
+	}
+	return canceled
+}
+
+// disarm removes a finished conditional order from the armed set.
+func (m *OrderManager) disarm(mo *ManagedOrder) {
+	armed := slices.DeleteFunc(m.armed[mo.Symbol], func(a *ManagedOrder) bool { return a == mo })
+	if len(armed) == 0 {
+		delete(m.armed, mo.Symbol)
+	} else {
+		m.armed[mo.Symbol] = armed
+	}
+}
+
+// Cancel cancels an order at a venue like CancelAll. The venue's update
+// closes the order.
+func (m *OrderManager) Cancel(mo *ManagedOrder, seq uint64, ts quant.TimeStamp, replay bool) {
+	m.requestCancel(mo, seq, ts, replay)
+}
+
+// requestCancel marks mo CancelPending, also on replay: the mark is replicated
+// state, so after a restart RetryCancels sends the cancels the last run left
+// unanswered. A full lane only delays the request.
+func (m *OrderManager) requestCancel(mo *ManagedOrder, seq uint64, ts quant.TimeStamp, replay bool) {
+	if mo.CancelPending {
+		return // RetryCancels repeats it
+	}
+	mo.CancelPending = true
+	m.canceling = append(m.canceling, mo)
+	if !replay && !m.queueCancel(mo, seq, ts) {
+		slog.Warn("OMS_CANCEL_QUEUE_FULL: retrying", slog.String("order_id", mo.ID), slog.String("strategy", mo.Strategy), slog.String("symbol", mo.Symbol))
+	}
+}
+
+// RetryCancels queues the cancels of CancelPending orders again that did not
+// fit the lane, or that the venue left unanswered for cancelRetryMicros. Live
+// only (the hotpath calls it after each event); ts is the time of that event.
+func (m *OrderManager) RetryCancels(seq uint64, ts quant.TimeStamp) {
+	for _, mo := range m.canceling {
+		if mo.cancelQueued && ts-mo.cancelSentAt < cancelRetryMicros {
+			continue
+		}
+		if !m.queueCancel(mo, seq, ts) {
+			return // Still full
+		}
+	}
+}
+
+// queueCancel puts a cancel request for mo on the cancel lane without blocking.
+func (m *OrderManager) queueCancel(mo *ManagedOrder, seq uint64, ts quant.TimeStamp) bool {
+	req := &event.OrderRequestEvent{
+		OrderID:  mo.ID,
+		Symbol:   mo.Symbol,
+		Side:     mo.Side,
+		Type:     mo.Type,
+		Market:   mo.Market,
+		Exchange: mo.Exchange,
+		Cancel:   true,
+		Strategy: mo.Strategy,
+	}
+	req.Seq = seq
+	req.Ts = ts
+	select {
+	case m.cancels <- req:
+		mo.cancelQueued, mo.cancelSentAt = true, ts
+		return true
+	default:
+		return false
+	}
+}
+
+// rejectQueued records the refusal of mo by the full queue, in refusal order.
+func (m *OrderManager) rejectQueued(mo *ManagedOrder, priceMicros int64, ts quant.TimeStamp) {
+	rej := &event.OrderRejectedEvent{
+		BaseEvent:   event.BaseEvent{Ts: ts},
+		OrderID:     mo.ID,
+		Symbol:      mo.Symbol,
+		Side:        mo.Side,
+		PriceMicros: quant.PriceMicros(priceMicros),
+		QtySats:     quant.QtySats(mo.QtySats),
+		Exchange:    OMSExchange,
+		Reason:      domain.RejectRateLimited,
+		Message:     ErrOrderQueueFull.Error(),
+	}
+	rej.Seq = quant.NextSeq(m.nextSeq)
+	m.refusals = append(m.refusals, rej)
+}
+
+// InFlight returns the number of orders handed to the gateway that the venue
+// has not answered yet.
+func (m *OrderManager) InFlight() int {
+	n := 0
+	for _, mo := range m.orders {
+		if mo.Status == domain.OrderStatusSent {
+			n++
+		}
+	}
+	return n
+}
+
+// Dropped returns the number of orders refused because the gateway queue was
+// full.
+func (m *OrderManager) Dropped() uint64 {
+	return m.dropped
+}
+
+// Unknown returns the number of updates received for IDs this manager never issued.
+func (m *OrderManager) Unknown() uint64 {
+	return m.unknown
+}
+
+// SetOrderManager routes accepted strategy orders through m. Without one, signals
+// pass the risk checks but are not sent anywhere. Must be called before Run.
+func (s *Sequencer) SetOrderManager(m *OrderManager) {
+	s.orders = m
+}
+
+// submitOrder hands an accepted strategy order to the OMS (caller holds s.mu).
+// It returns the managed order, or nil without an OMS or if it was refused.
+// Orders growing a position on a reduce-only venue are refused before the OMS
+// sees them.
+func (s *Sequencer) submitOrder(order *domain.Order, ts quant.TimeStamp) *ManagedOrder {
+	if s.orders == nil {
+		return nil
+	}
+	if s.reduceOnlyBlocks(order) {
+		s.rejectOMSOrder(&ManagedOrder{Order: *order}, domain.RejectReduceOnly, "venue is reduce-only")
+		return nil
+	}
+	mo, ok := s.orders.Submit(*order, s.nextSeq, ts, s.replaying)
+	if !ok {
+		s.rejectRefused(mo)
+		return nil
+	}
+	return mo
+}
+
+// triggerOrders fires armed conditional orders on a market update (caller
+// holds s.mu). While order dispatch is stopped they follow the price but do
+// not fire, like strategy orders.
+func (s *Sequencer) triggerOrders(e *event.MarketUpdateEvent) {
+	if s.orders == nil {
+		return
+	}
+	fire := !s.dispatchStopped()
+	for _, mo := range s.orders.Trigger(e, s.nextSeq, fire, s.replaying) {
+		if mo.Status == domain.OrderStatusRejected {
+			s.rejectRefused(mo)
+		}
+		if s.strategy != nil {
+			s.strategy.OnOrderUpdate(mo.Order)
+		}
+	}
+}
+
+// observeRisk feeds a trade price to the risk engine (caller holds s.mu).
+// Default-venue orders are checked against the feed that marks positions.
+func (s *Sequencer) observeRisk(exchange, symbol string, priceMicros int64, ts quant.TimeStamp) {
+	if s.orders == nil || s.orders.risk == nil {
+		return
+	}
+	if s.markExchange == "" || exchange == s.markExchange {
+		s.orders.risk.Observe("", symbol, priceMicros, ts)
+	}
+	s.orders.risk.Observe(exchange, symbol, priceMicros, ts)
+}
+
+// GetRiskReport returns the exposure and VaR of the positions, or false
+// without a risk engine. Thread-safe.
+func (s *Sequencer) GetRiskReport() (risk.Report, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.orders == nil || s.orders.risk == nil {
+		return risk.Report{}, false
+	}
+	return s.orders.risk.Report(), true
+}
+
+// rejectRefused reports an order the OMS refused, classified by its Refusal.
+func (s *Sequencer) rejectRefused(mo *ManagedOrder) {
+	var rr *risk.RiskRejectedError
+	switch {
+	case errors.As(mo.Refusal, &rr):
+		s.rejectOMSOrder(mo, domain.RejectRiskLimit, mo.Refusal.Error())
+	case errors.Is(mo.Refusal, ErrOrderQueueFull):
+		s.rejectOMSOrder(mo, domain.RejectRateLimited, mo.Refusal.Error())
+	default:
+		s.rejectOMSOrder(mo, domain.RejectInvalidPrice, mo.Refusal.Error())
+	}
+}
+
+// rejectOMSOrder tells a rejection-aware strategy that the OMS refused mo itself.
+func (s *Sequencer) rejectOMSOrder(mo *ManagedOrder, reason domain.RejectKind, msg string) {
+	if h, isHandler := s.strategy.(strategy.RejectionHandler); isHandler {
+		h.OnOrderRejected(domain.OrderRejection{
+			Order:    mo.Order,
+			Exchange: OMSExchange,
+			Reason:   reason,
+			Message:  msg,
+		})
+	}
+}
+
+// handleOrderUpdate applies a venue update, books any new fill into the
+// position and notifies the strategy of the change.
+func (s *Sequencer) handleOrderUpdate(e *event.OrderUpdateEvent) {
+	if s.orders == nil {
+		return
+	}
+	var before int64
+	if mo, ok := s.orders.orders[e.OrderID]; ok {
+		before = mo.FilledQtySats
+	}
+	mo := s.orders.Apply(e)
+	if mo == nil {
+		return
+	}
+	if fill := mo.FilledQtySats - before; fill > 0 {
+		s.applyFill(mo, fill, e.Seq)
+	}
+	if s.strategy != nil {
+		s.strategy.OnOrderUpdate(mo.Order)
+	}
+	s.escalateMaker(mo, e.Ts)
+}
+
+// recordRefusals sequences the queue-full refusals of the event just applied,
+// and of those they cause, so each follows its order in the WAL (caller holds
+// s.mu).
+func (s *Sequencer) recordRefusals() {
+	for s.orders != nil && len(s.orders.refusals) > 0 {
+		batch := s.orders.refusals
+		s.orders.refusals = nil
+		for _, rej := range batch {
+			if s.validateSeq {
+				s.ValidateSequence(eventSource(rej), rej.GetType(), rej.Seq, rej.GetIncarnation())
+			}
+			s.sequence(rej)
+		}
+	}
+}
+
+// retryCancels repeats unanswered cancels after a live event (caller holds s.mu).
+func (s *Sequencer) retryCancels(ts quant.TimeStamp) {
+	if s.orders != nil && len(s.orders.canceling) > 0 {
+		s.orders.RetryCancels(s.nextSeq-1, ts)
+	}
+}
+
+// GetOrder returns the OMS view of a client order ID. Thread-safe.
+func (s *Sequencer) GetOrder(id string) (ManagedOrder, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.orders == nil {
+		return ManagedOrder{}, false
+	}
+	return s.orders.Order(id)
+}
+
+// GetOpenOrders returns all orders the OMS still considers open. Thread-safe.
+func (s *Sequencer) GetOpenOrders() []ManagedOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.orders == nil {
+		return nil
+	}
+	return s.orders.OpenOrders()
+}
@@ -0,0 +1,337 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/risk"
+	"crypto_go/pkg/quant"
+	"errors"
+	"testing"
+)
+
+// orderRecorder signals an entry on every update and records order updates.
+type orderRecorder struct {
+	rejectRecorder
+	updates []domain.Order
+}
+
+func (r *orderRecorder) OnOrderUpdate(o domain.Order) { r.updates = append(r.updates, o) }
+
+func update(id, status string, filled int64) *event.OrderUpdateEvent {
+	return &event.OrderUpdateEvent{OrderID: id, Status: status, AccumulatedQtySats: quant.QtySats(filled)}
+}
+
+func TestOrderManager_Lifecycle(t *testing.T) {
+	m := NewOrderManager("t", 4)
+	mo, ok := m.Submit(domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 100, QtySats: 10}, 7, 1, false)
+	if !ok || mo.ID != "t-7-1" || mo.Status != domain.OrderStatusSent {
+		t.Fatalf("unexpected submitted order: %+v", mo)
+	}
+	req := <-m.Requests()
+	if req.OrderID != "t-7-1" || req.Seq != 7 || req.QtySats != 10 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	steps := []struct {
+		ev      *event.OrderUpdateEvent
+		applied bool
+		status  string
+	}{
+		{update("t-7-1", domain.OrderStatusAcked, 0), true, domain.OrderStatusAcked},
+		{update("t-7-1", domain.OrderStatusAcked, 0), false, domain.OrderStatusAcked}, // Duplicate
+		{update("t-7-1", domain.OrderStatusPartiallyFilled, 4), true, domain.OrderStatusPartiallyFilled},
+		{update("t-7-1", domain.OrderStatusAcked, 0), false, domain.OrderStatusPartiallyFilled},           // Stale ACK
+		{update("t-7-1", domain.OrderStatusPartiallyFilled, 3), false, domain.OrderStatusPartiallyFilled}, // Out of order
+		{update("t-7-1", domain.OrderStatusPartiallyFilled, 6), true, domain.OrderStatusPartiallyFilled},
+		{update("t-7-1", domain.OrderStatusFilled, 10), true, domain.OrderStatusFilled},
+		{update("t-7-1", domain.OrderStatusCanceled, 10), false, domain.OrderStatusFilled}, // Terminal is final
+	}
+	for i, st := range steps {
+		if got := m.Apply(st.ev) != nil; got != st.applied {
+			t.Errorf("step %d: applied = %v, want %v", i, got, st.applied)
+		}
+		if o, _ := m.Order("t-7-1"); o.Status != st.status {
+			t.Errorf("step %d: status = %s, want %s", i, o.Status, st.status)
+		}
+	}
+	if o, _ := m.Order("t-7-1"); o.FilledQtySats != 10 {
+		t.Errorf("filled = %d, want 10", o.FilledQtySats)
+	}
+	if len(m.OpenOrders()) != 0 {
+		t.Error("filled order must not be open")
+	}
+
+	if m.Apply(update("other", domain.OrderStatusFilled, 1)) != nil || m.Unknown() != 1 {
+		t.Error("updates for unknown IDs must be counted and ignored")
+	}
+}
+
+func TestOrderManager_IDsAndQueueFull(t *testing.T) {
+	m := NewOrderManager("", 1)
+	a, _ := m.Submit(domain.Order{Symbol: "BTC"}, 3, 0, false)
+	b, ok := m.Submit(domain.Order{Symbol: "ETH"}, 3, 0, false)
+	if a.ID != "cg-3-1" || b.ID != "cg-3-2" {
+		t.Errorf("unexpected IDs: %s, %s", a.ID, b.ID)
+	}
+	if ok || b.Status != domain.OrderStatusRejected || m.Dropped() != 1 {
+		t.Errorf("second order must be refused on a full queue: ok=%v %+v", ok, b)
+	}
+
+	// Replay never touches the queue
+	c, ok := m.Submit(domain.Order{Symbol: "XRP"}, 4, 0, true)
+	if !ok || c.Status != domain.OrderStatusSent || len(m.Requests()) != 1 {
+		t.Errorf("replayed order must be recorded as SENT without a request: %+v", c)
+	}
+}
+
+func TestOrderManager_CancelRetriedUntilClosed(t *testing.T) {
+	m := NewOrderManager("t", 1)
+	a, _ := m.Submit(domain.Order{Symbol: "BTC"}, 1, 0, false)
+	<-m.Requests()
+	b, _ := m.Submit(domain.Order{Symbol: "ETH"}, 2, 0, false)
+	<-m.Requests()
+
+	// The lane holds one cancel: the other stays pending instead of being dropped
+	m.CancelAll(3, 0, false)
+	if !a.CancelPending || !b.CancelPending || m.Dropped() != 0 {
+		t.Fatalf("both cancels must be pending: a=%+v b=%+v dropped=%d", a, b, m.Dropped())
+	}
+	m.RetryCancels(4, 1)
+	if req := <-m.Cancels(); req.OrderID != a.ID || !req.Cancel {
+		t.Fatalf("unexpected cancel: %+v", req)
+	}
+	m.RetryCancels(5, 2)
+	if req := <-m.Cancels(); req.OrderID != b.ID {
+		t.Fatalf("the delayed cancel must be queued once there is room, got %+v", req)
+	}
+
+	// a is closed; b goes unanswered and is canceled again
+	m.Apply(update(a.ID, domain.OrderStatusCanceled, 0))
+	m.RetryCancels(6, 1_000_000)
+	if len(m.Cancels()) != 0 {
+		t.Fatal("an answered cancel must not be repeated early")
+	}
+	m.RetryCancels(7, 2+cancelRetryMicros)
+	if req := <-m.Cancels(); req.OrderID != b.ID || len(m.Cancels()) != 0 {
+		t.Fatalf("only the unanswered cancel must be repeated, got %+v", req)
+	}
+	if a.CancelPending || len(m.canceling) != 1 {
+		t.Errorf("a closed order must leave the pending cancels: %+v", a)
+	}
+
+	// Replay rebuilds the pending mark without queueing
+	r := NewOrderManager("t", 1)
+	c, _ := r.Submit(domain.Order{Symbol: "BTC"}, 1, 0, true)
+	r.Cancel(c, 2, 0, true)
+	if !c.CancelPending || len(r.Cancels()) != 0 {
+		t.Errorf("replay must mark the cancel without sending it: %+v", c)
+	}
+}
+
+func TestSequencer_OrderLifecycle(t *testing.T) {
+	strat := &orderRecorder{}
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	req := <-oms.Requests()
+	if req.OrderID != "t-1-1" || req.Side != domain.SideBuy {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if open := seq.GetOpenOrders(); len(open) != 1 || open[0].Status != domain.OrderStatusSent {
+		t.Fatalf("unexpected open orders: %+v", open)
+	}
+
+	seq.ProcessEventForTest(update("t-1-1", domain.OrderStatusAcked, 0))
+	seq.ProcessEventForTest(&event.OrderRejectedEvent{OrderID: "t-1-1", Reason: domain.RejectInsufficientFunds})
+
+	if len(strat.updates) != 2 || strat.updates[0].Status != domain.OrderStatusAcked || strat.updates[1].Status != domain.OrderStatusRejected {
+		t.Errorf("unexpected strategy updates: %+v", strat.updates)
+	}
+	if len(strat.got) != 1 {
+		t.Errorf("rejection must still reach the strategy, got %d", len(strat.got))
+	}
+	if o, ok := seq.GetOrder("t-1-1"); !ok || o.Status != domain.OrderStatusRejected {
+		t.Errorf("unexpected order: %+v", o)
+	}
+}
+
+func TestSequencer_OrderManagerReplayDoesNotResend(t *testing.T) {
+	seq := NewSequencer(10, nil, &orderRecorder{}, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+
+	seq.ReplayEvent(&event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: 1}, Symbol: "BTC", PriceMicros: 100})
+	seq.ReplayEvent(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 2}, OrderID: "t-1-1", Status: domain.OrderStatusAcked})
+
+	if len(oms.Requests()) != 0 {
+		t.Error("replay must not send order requests")
+	}
+	if o, ok := seq.GetOrder("t-1-1"); !ok || o.Status != domain.OrderStatusAcked {
+		t.Errorf("replay must rebuild the order: %+v", o)
+	}
+}
+
+func tick(exchange string, price int64) *event.MarketUpdateEvent {
+	return &event.MarketUpdateEvent{Exchange: exchange, Symbol: "BTC", PriceMicros: quant.PriceMicros(price)}
+}
+
+func TestOrderManager_OCO(t *testing.T) {
+	m := NewOrderManager("t", 4)
+	m.SetTriggerExchange("BITGET")
+	sell, ok := m.Submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeOCO, PriceMicros: 110, StopPriceMicros: 90, QtySats: 5}, 1, 0, false)
+	if !ok || sell.Status != domain.OrderStatusArmed || sell.TriggerExchange != "BITGET" || len(m.Requests()) != 0 {
+		t.Fatalf("OCO must be armed locally: %+v", sell)
+	}
+
+	if fired := m.Trigger(tick("UPBIT", 50), 2, true, false); len(fired) != 0 {
+		t.Error("another exchange's price must not trigger")
+	}
+	if fired := m.Trigger(tick("BITGET", 100), 3, true, false); len(fired) != 0 {
+		t.Error("price between the legs must not trigger")
+	}
+	fired := m.Trigger(tick("BITGET", 89), 4, true, false)
+	if len(fired) != 1 || fired[0].Leg != LegStop || fired[0].FiredMicros != 89 || fired[0].Status != domain.OrderStatusSent {
+		t.Fatalf("expected the stop leg to fire: %+v", fired)
+	}
+	if req := <-m.Requests(); req.OrderID != sell.ID || req.Type != domain.OrderTypeMarket || req.PriceMicros != 0 || req.QtySats != 5 || req.Seq != 4 {
+		t.Errorf("unexpected stop request: %+v", req)
+	}
+	if fired := m.Trigger(tick("BITGET", 120), 5, true, false); len(fired) != 0 || len(m.Requests()) != 0 {
+		t.Error("the other leg must never be sent")
+	}
+
+	// BUY side: take-profit below, stop above
+	buy, _ := m.Submit(domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeOCO, PriceMicros: 90, StopPriceMicros: 110}, 6, 0, false)
+	m.Trigger(tick("BITGET", 90), 7, true, false)
+	if req := <-m.Requests(); req.OrderID != buy.ID || req.Type != domain.OrderTypeLimit || req.PriceMicros != 90 {
+		t.Errorf("expected the take-profit LIMIT at 90: %+v", req)
+	}
+
+	bad, ok := m.Submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeOCO, PriceMicros: 90, StopPriceMicros: 110}, 8, 0, false)
+	if ok || bad.Status != domain.OrderStatusRejected {
+		t.Errorf("a stop above the take-profit of a SELL must be rejected: %+v", bad)
+	}
+}
+
+func TestOrderManager_TrailingStop(t *testing.T) {
+	m := NewOrderManager("t", 4)
+	mo, _ := m.Submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeTrailingStop, TrailBps: 1000}, 1, 0, false)
+
+	for _, p := range []int64{100, 120, 109} {
+		if fired := m.Trigger(tick("BITGET", p), 2, true, false); len(fired) != 0 {
+			t.Fatalf("fired early at %d", p)
+		}
+	}
+	if mo.BestMicros != 120 || mo.TrailStopMicros() != 108 {
+		t.Fatalf("stop must trail the high: best %d stop %d", mo.BestMicros, mo.TrailStopMicros())
+	}
+
+	// Dispatch stopped (halt): the trigger is held, the order stays armed
+	if fired := m.Trigger(tick("BITGET", 100), 3, false, false); len(fired) != 0 || mo.Status != domain.OrderStatusArmed {
+		t.Fatalf("must not fire while dispatch is stopped: %+v", mo)
+	}
+	if fired := m.Trigger(tick("BITGET", 108), 4, true, true); len(fired) != 1 || mo.Status != domain.OrderStatusSent || len(m.Requests()) != 0 {
+		t.Errorf("replay must fire without a request: %+v", mo)
+	}
+
+	if _, ok := m.Submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeTrailingStop}, 5, 0, false); ok {
+		t.Error("a trailing stop without a trail must be rejected")
+	}
+}
+
+// ocoStrategy protects a position with one OCO and records order updates.
+type ocoStrategy struct {
+	orderRecorder
+	sent bool
+}
+
+func (s *ocoStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if s.sent {
+		return 0
+	}
+	s.sent = true
+	out[0] = domain.Order{Symbol: state.Symbol, Side: domain.SideSell, Type: domain.OrderTypeOCO, PriceMicros: 110, StopPriceMicros: 90, QtySats: 1}
+	return 1
+}
+
+func TestSequencer_ConditionalOrderFiresOnMarketUpdate(t *testing.T) {
+	strat := &ocoStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+
+	seq.ProcessEventForTest(tick("BITGET", 100))
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdHalt})
+	seq.ProcessEventForTest(tick("BITGET", 111))
+	if open := seq.GetOpenOrders(); len(open) != 1 || open[0].Status != domain.OrderStatusArmed {
+		t.Fatalf("a halt must hold the trigger: %+v", open)
+	}
+
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdResumeTrading})
+	seq.ProcessEventForTest(tick("BITGET", 112))
+	req := <-oms.Requests()
+	if req.OrderID != "t-1-1" || req.Type != domain.OrderTypeLimit || req.PriceMicros != 110 || req.Seq != 5 {
+		t.Errorf("unexpected take-profit request: %+v", req)
+	}
+	if len(strat.updates) != 1 || strat.updates[0].Status != domain.OrderStatusSent {
+		t.Errorf("the strategy must see the fired order: %+v", strat.updates)
+	}
+}
+
+func TestSequencer_RiskRejectsBeforeSending(t *testing.T) {
+	strat := &orderRecorder{}
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 4)
+	oms.SetRisk(risk.NewEngine(risk.Limits{MaxOpenOrders: 1}, seq.PositionBook()))
+	seq.SetOrderManager(oms)
+
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if len(oms.Requests()) != 1 {
+		t.Fatalf("only the first order may be sent, queued %d", len(oms.Requests()))
+	}
+	if len(strat.got) != 1 || strat.got[0].Reason != domain.RejectRiskLimit || strat.got[0].Exchange != OMSExchange {
+		t.Fatalf("unexpected rejections: %+v", strat.got)
+	}
+	o, _ := seq.GetOrder("t-2-1")
+	var rr *risk.RiskRejectedError
+	if o.Status != domain.OrderStatusRejected || !errors.As(o.Refusal, &rr) || rr.Check != risk.CheckOpenOrders {
+		t.Errorf("unexpected refused order: %+v", o)
+	}
+
+	// Operators raise the limit through the WAL like any other risk limit
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdSetRiskLimit, Target: risk.LimitMaxOpenOrders, Value: 2})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if len(oms.Requests()) != 2 {
+		t.Errorf("raised limit must let a second order through, queued %d", len(oms.Requests()))
+	}
+}
+
+func TestOrderManager_RiskCountsOpenOrders(t *testing.T) {
+	m := NewOrderManager("t", 4)
+	m.SetRisk(risk.NewEngine(risk.Limits{MaxPositionSats: 2}, domain.NewPositionBook()))
+	buy := domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 2}
+
+	first, ok := m.Submit(buy, 1, 0, false)
+	if !ok {
+		t.Fatalf("first buy must pass: %v", first.Refusal)
+	}
+	buy.QtySats = 1
+	second, ok := m.Submit(buy, 2, 0, false)
+	var rr *risk.RiskRejectedError
+	if ok || !errors.As(second.Refusal, &rr) || rr.Check != risk.CheckPosition {
+		t.Fatalf("a buy on top of an unfilled one must fail the position limit: %+v", second)
+	}
+	routed := buy
+	routed.Exchange = "UPBIT"
+	if _, ok := m.Submit(routed, 3, 0, false); !ok {
+		t.Error("open orders of another venue must not count")
+	}
+
+	m.Apply(update(first.ID, domain.OrderStatusCanceled, 0))
+	if third, ok := m.Submit(buy, 4, 0, false); !ok {
+		t.Errorf("the canceled buy must no longer count: %v", third.Refusal)
+	}
+}
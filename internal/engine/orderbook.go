@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+)
+
+type bookKey struct{ exchange, symbol string }
+
+// handleOrderBookUpdate applies e to the exchange/symbol book and lets a
+// depth-aware strategy look at the result.
+func (s *Sequencer) handleOrderBookUpdate(e *event.OrderBookUpdateEvent) {
+	key := bookKey{e.Exchange, e.Symbol}
+	book, ok := s.books[key]
+	if !ok {
+		// Cold path: New book allocation
+		book = domain.NewOrderBook(e.Exchange, e.Symbol)
+		s.books[key] = book
+	}
+	if !book.Apply(e.Snapshot, e.Bids, e.Asks, e.Ts) {
+		return // Delta before the first snapshot
+	}
+
+	if h, ok := s.strategy.(strategy.OrderBookHandler); ok && !s.strategyPaused {
+		h.OnOrderBookUpdate(book)
+	}
+}
+
+// GetOrderBook returns a copy of the exchange/symbol book (thread-safe).
+func (s *Sequencer) GetOrderBook(exchange, symbol string) (*domain.OrderBook, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	book, ok := s.books[bookKey{exchange, symbol}]
+	if !ok || !book.Synced {
+		return nil, false
+	}
+	return book.Clone(), true
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"log/slog"
+	"sort"
+)
+
+// SetPositionMarkExchange sets the exchange whose market updates value
+// positions of the default venue (e.g. "BITGET_FUTURES"). Empty = any: a
+// symbol quoted in KRW and USDT would then mix both prices. Positions of
+// routed orders are always marked by their own venue. Must be called before Run.
+func (s *Sequencer) SetPositionMarkExchange(exchange string) {
+	s.markExchange = exchange
+}
+
+// applyFill books fillSats of mo into its venue/symbol position (caller holds
+// s.mu). The venue's fill price is used; without one, the order's limit or the
+// last market price.
+func (s *Sequencer) applyFill(mo *ManagedOrder, fillSats int64, seq uint64) {
+	price := mo.FillPriceMicros
+	if price <= 0 {
+		price = mo.PriceMicros
+	}
+	if state, ok := s.markets[mo.Symbol]; ok && price <= 0 {
+		price = int64(state.PriceMicros)
+	}
+	if price <= 0 {
+		slog.Warn("POSITION_FILL_UNPRICED", slog.String("order_id", mo.ID), slog.Int64("qty", fillSats))
+		return
+	}
+
+	p := s.positions.Get(mo.Exchange, mo.Symbol)
+	p.ApplyFill(mo.Side, fillSats, price, seq)
+	p.VerifyInvariant()
+	s.checkDrawdown()
+}
+
+// markPositions revalues the positions e prices (caller holds s.mu).
+func (s *Sequencer) markPositions(e *event.MarketUpdateEvent) {
+	if s.markExchange == "" || e.Exchange == s.markExchange {
+		s.positions.Mark("", e.Symbol, int64(e.PriceMicros), e.Seq)
+	}
+	s.positions.Mark(e.Exchange, e.Symbol, int64(e.PriceMicros), e.Seq)
+	s.checkDrawdown()
+}
+
+// GetPositions returns every position, open or closed, with its PnL. Thread-safe.
+func (s *Sequencer) GetPositions() []domain.Position {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap := s.positions.Snapshot()
+	out := make([]domain.Position, 0, len(snap))
+	for _, p := range snap {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Symbol != out[j].Symbol {
+			return out[i].Symbol < out[j].Symbol
+		}
+		return out[i].Exchange < out[j].Exchange
+	})
+	return out
+}
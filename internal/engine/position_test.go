@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+func TestSequencer_PositionsFromFills(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+	seq.SetPositionMarkExchange("BITGET")
+
+	mo, _ := oms.Submit(domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 100, QtySats: 2 * quant.QtyScale}, 1, 0, true)
+	fill := func(status string, price, filled int64) {
+		seq.ProcessEventForTest(&event.OrderUpdateEvent{OrderID: mo.ID, Status: status, PriceMicros: quant.PriceMicros(price), AccumulatedQtySats: quant.QtySats(filled)})
+	}
+
+	fill(domain.OrderStatusPartiallyFilled, 100, quant.QtyScale)
+	fill(domain.OrderStatusPartiallyFilled, 100, quant.QtyScale) // Duplicate: booked once
+	fill(domain.OrderStatusFilled, 90, 2*quant.QtyScale)
+	seq.ProcessEventForTest(tick("UPBIT", 500)) // Not the mark feed
+	seq.ProcessEventForTest(tick("BITGET", 120))
+
+	positions := seq.GetPositions()
+	if len(positions) != 1 {
+		t.Fatalf("expected one position, got %+v", positions)
+	}
+	p := positions[0]
+	if p.QtySats != 2*quant.QtyScale || p.AvgEntryPriceMicros != 95 || p.MarkPriceMicros != 120 || p.UnrealizedPnLMicros != 50 {
+		t.Errorf("unexpected position: %+v", p)
+	}
+}
@@ -0,0 +1,22 @@
+package engine
+
+import "crypto_go/internal/event"
+
+// CriticalInbox returns the trade-critical lane for control commands, the kill
+// switch and order/fill events. Run applies whatever is queued here before any
+// market data waiting in Inbox or the ring lanes, so a fill never queues behind
+// a ticker burst. Events still take the next global seq when applied: the WAL
+// order is the processing order, and replay is unchanged.
+//
+// A producer must keep to one lane, or events of its source may be reordered.
+// Resync ENDs stay on Inbox, behind the REST snapshots they close.
+func (s *Sequencer) CriticalInbox() chan<- event.Event {
+	return s.critical
+}
+
+// drainCritical applies the critical events queued on entry. Run goroutine only.
+func (s *Sequencer) drainCritical() {
+	for n := len(s.critical); n > 0; n-- {
+		s.processEvent(<-s.critical)
+	}
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSequencer_CriticalLaneFirst(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	lane := seq.NewRingInbox(8)
+	var mu sync.Mutex
+	var applied []event.Type
+	var seqs []uint64
+	seq.onApplied = func(ev event.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		applied = append(applied, ev.GetType())
+		seqs = append(seqs, ev.GetSeq())
+	}
+
+	// A ticker backlog on both market lanes, then a fill
+	for i := int64(1); i <= 3; i++ {
+		seq.Inbox() <- mkt("UPBIT", i, 100)
+		lane.Send(mkt("BITGET", i, 100))
+	}
+	seq.CriticalInbox() <- &event.OrderUpdateEvent{OrderID: "o1", Status: domain.OrderStatusFilled}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go seq.Run(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for seq.GetNextSeq() != 8 {
+		if time.Now().After(deadline) {
+			t.Fatalf("processed up to seq %d; want 7 events", seq.GetNextSeq()-1)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if applied[0] != event.EvOrderUpdate {
+		t.Errorf("applied %v; want the fill ahead of the queued market data", applied)
+	}
+	for i, s := range seqs {
+		if s != uint64(i+1) {
+			t.Errorf("event %d got seq %d; want one global sequence", i, s)
+		}
+	}
+}
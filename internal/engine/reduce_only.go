@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"cmp"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// SetReduceOnlyExempt opts the strategy out of reduce-only mode (e.g. an
+// end-of-day policy's opt-out list): its orders pass, and entering the mode
+// neither cancels nor flattens anything. Must be called before Run.
+func (s *Sequencer) SetReduceOnlyExempt(exempt bool) {
+	s.reduceExempt = exempt
+}
+
+// enterReduceOnly applies CmdReduceOnly (caller holds s.mu): orders for venue
+// e.Target ("" = all) that would grow a position are canceled and refused
+// from now on. With Value 1 every open order of the venue is canceled and its
+// positions are closed with MARKET orders, unless order dispatch is stopped.
+// Re-entering a venue already reduce-only changes nothing, so a repeated
+// command cannot flatten twice while the first flatten is in flight.
+func (s *Sequencer) enterReduceOnly(e *event.ControlEvent, replay bool) {
+	if s.reduceOnly[e.Target] {
+		return
+	}
+	s.reduceOnly[e.Target] = true
+	if s.orders == nil || s.reduceExempt {
+		return
+	}
+
+	flatten := e.Value == 1
+	canceled := s.orders.CancelWhere(func(mo *ManagedOrder) bool {
+		if !s.venueMatches(e.Target, mo.Exchange) {
+			return false
+		}
+		return flatten || s.opensPosition(&mo.Order, mo.QtySats-mo.FilledQtySats)
+	}, e.Seq, e.Ts, replay)
+	for _, mo := range canceled {
+		if s.strategy != nil {
+			s.strategy.OnOrderUpdate(mo.Order)
+		}
+	}
+	if flatten && !s.dispatchStopped() {
+		s.flatten(e.Target, e.Ts)
+	}
+}
+
+// liftReduceOnly applies CmdLiftReduceOnly: target "" lifts every venue.
+func (s *Sequencer) liftReduceOnly(target string) {
+	if target == "" {
+		clear(s.reduceOnly)
+		return
+	}
+	delete(s.reduceOnly, target)
+}
+
+// flatten submits a MARKET order closing each open position of venue target,
+// ordered by venue and symbol so a replay assigns the same IDs.
+func (s *Sequencer) flatten(target string, ts quant.TimeStamp) {
+	var open []domain.Position
+	s.positions.Range(func(p domain.Position) {
+		if p.QtySats != 0 && s.venueMatches(target, p.Exchange) {
+			open = append(open, p)
+		}
+	})
+	slices.SortFunc(open, func(a, b domain.Position) int {
+		return cmp.Or(cmp.Compare(a.Exchange, b.Exchange), cmp.Compare(a.Symbol, b.Symbol))
+	})
+	for _, p := range open {
+		order := closingOrder(p)
+		if !s.replaying {
+			slog.Warn("REDUCE_ONLY_FLATTEN", slog.String("exchange", p.Exchange), slog.String("symbol", p.Symbol), slog.Int64("qty", p.QtySats))
+		}
+		s.submitOrder(&order, ts)
+	}
+}
+
+// closingOrder returns the MARKET order that closes position p.
+func closingOrder(p domain.Position) domain.Order {
+	order := domain.Order{
+		Symbol:      p.Symbol,
+		Side:        domain.SideSell,
+		Type:        domain.OrderTypeMarket,
+		Exchange:    p.Exchange,
+		PriceMicros: p.MarkPriceMicros,
+		QtySats:     p.QtySats,
+	}
+	if p.IsShort() {
+		order.Side = domain.SideBuy
+		order.QtySats = -p.QtySats
+	}
+	return order
+}
+
+// reduceOnlyBlocks reports whether order must be refused because its venue is
+// reduce-only and it would grow the position.
+func (s *Sequencer) reduceOnlyBlocks(order *domain.Order) bool {
+	if len(s.reduceOnly) == 0 || s.reduceExempt {
+		return false
+	}
+	for target := range s.reduceOnly {
+		if s.venueMatches(target, order.Exchange) {
+			return s.opensPosition(order, order.QtySats)
+		}
+	}
+	return false
+}
+
+// opensPosition reports whether qtySats more of order would grow the absolute
+// position of its venue and symbol (the risk engine's notion of reducing).
+func (s *Sequencer) opensPosition(order *domain.Order, qtySats int64) bool {
+	pos, _ := s.positions.Lookup(order.Exchange, order.Symbol)
+	next := safe.SafeAdd(pos.QtySats, qtySats)
+	if order.Side == domain.SideSell {
+		next = safe.SafeSub(pos.QtySats, qtySats)
+	}
+	return abs64(next) > abs64(pos.QtySats)
+}
+
+// venueMatches reports whether exchange (empty = the default venue, priced by
+// the mark exchange) falls under reduce-only target ("" = all).
+func (s *Sequencer) venueMatches(target, exchange string) bool {
+	return target == "" || target == exchange || (exchange == "" && target == s.markExchange)
+}
+
+// ReduceOnly returns the reduce-only venues, sorted; "" stands for all
+// (thread-safe).
+func (s *Sequencer) ReduceOnly() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Sorted(maps.Keys(s.reduceOnly))
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+)
+
+// longBTC returns a sequencer whose OMS holds a filled 1-sat BTC long (t-1-1)
+// bought by strat.
+func longBTC(t *testing.T, strat *rejectRecorder) (*Sequencer, *OrderManager) {
+	t.Helper()
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 8)
+	seq.SetOrderManager(oms)
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	<-oms.Requests()
+	seq.ProcessEventForTest(update("t-1-1", domain.OrderStatusFilled, 1))
+	if p, _ := seq.positions.Lookup("", "BTC"); p.QtySats != 1 {
+		t.Fatalf("position = %+v; want a 1-sat long", p)
+	}
+	return seq, oms
+}
+
+// drainRequests returns the queued cancels, then the queued orders.
+func drainRequests(oms *OrderManager) []*event.OrderRequestEvent {
+	var reqs []*event.OrderRequestEvent
+	for len(oms.Cancels()) > 0 {
+		reqs = append(reqs, <-oms.Cancels())
+	}
+	for len(oms.Requests()) > 0 {
+		reqs = append(reqs, <-oms.Requests())
+	}
+	return reqs
+}
+
+func TestSequencer_ReduceOnly(t *testing.T) {
+	strat := &rejectRecorder{}
+	seq, oms := longBTC(t, strat)
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100}) // t-3-1: another buy
+	oco, _ := oms.Submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeOCO, PriceMicros: 110, StopPriceMicros: 90, QtySats: 1}, 4, 0, false)
+	drainRequests(oms)
+
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdReduceOnly, Reason: "test"})
+	reqs := drainRequests(oms)
+	if len(reqs) != 1 || !reqs[0].Cancel || reqs[0].OrderID != "t-3-1" {
+		t.Fatalf("requests = %+v; want a cancel of the opening buy only", reqs)
+	}
+	if o, _ := seq.GetOrder(oco.ID); o.Status != domain.OrderStatusArmed {
+		t.Errorf("a reducing stop must stay armed: %+v", o)
+	}
+
+	// New buys are refused; sells that reduce pass
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if reqs := drainRequests(oms); len(reqs) != 0 {
+		t.Errorf("opening order sent while reduce-only: %+v", reqs)
+	}
+	if len(strat.got) != 1 || strat.got[0].Reason != domain.RejectReduceOnly {
+		t.Errorf("rejections = %+v; want one REDUCE_ONLY", strat.got)
+	}
+	sell := domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeMarket, QtySats: 1}
+	if seq.reduceOnlyBlocks(&sell) {
+		t.Error("a reducing sell must pass")
+	}
+
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdLiftReduceOnly})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if reqs := drainRequests(oms); len(reqs) != 1 || reqs[0].Cancel {
+		t.Errorf("requests after lift = %+v; want one new buy", reqs)
+	}
+	if got := seq.ReduceOnly(); len(got) != 0 {
+		t.Errorf("reduce-only venues after lift = %v", got)
+	}
+}
+
+func TestSequencer_ReduceOnlyFlatten(t *testing.T) {
+	seq, oms := longBTC(t, &rejectRecorder{})
+	oco, _ := oms.Submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeOCO, PriceMicros: 110, StopPriceMicros: 90, QtySats: 1}, 3, 0, false)
+
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdReduceOnly, Value: 1})
+	if o, _ := seq.GetOrder(oco.ID); o.Status != domain.OrderStatusCanceled {
+		t.Errorf("flatten must cancel every open order: %+v", o)
+	}
+	if len(oms.armed) != 0 {
+		t.Errorf("canceled order still armed: %v", oms.armed)
+	}
+	reqs := drainRequests(oms)
+	if len(reqs) != 1 || reqs[0].Side != domain.SideSell || reqs[0].Type != domain.OrderTypeMarket || reqs[0].QtySats != 1 {
+		t.Fatalf("requests = %+v; want a 1-sat MARKET sell", reqs)
+	}
+
+	// Repeating the command must not flatten twice
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdReduceOnly, Value: 1})
+	if reqs := drainRequests(oms); len(reqs) != 0 {
+		t.Errorf("repeated REDUCE_ONLY sent %+v", reqs)
+	}
+}
+
+func TestSequencer_ReduceOnlyVenueAndOptOut(t *testing.T) {
+	strat := &rejectRecorder{}
+	seq, oms := longBTC(t, strat)
+	seq.SetPositionMarkExchange("BITGET")
+
+	// Default-venue orders belong to the mark exchange
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdReduceOnly, Target: "UPBIT"})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if reqs := drainRequests(oms); len(reqs) != 1 {
+		t.Errorf("UPBIT reduce-only must not block the default venue: %+v", reqs)
+	}
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdReduceOnly, Target: "BITGET"})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if reqs := drainRequests(oms); len(reqs) != 1 || !reqs[0].Cancel {
+		t.Errorf("BITGET reduce-only must cancel the open buy and block a new one: %+v", reqs)
+	}
+
+	seq.SetReduceOnlyExempt(true)
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	if reqs := drainRequests(oms); len(reqs) != 1 || reqs[0].Cancel {
+		t.Errorf("an opted-out strategy must keep trading: %+v", reqs)
+	}
+}
+
+func TestSequencer_ReduceOnlyReplay(t *testing.T) {
+	seq := NewSequencer(10, nil, &rejectRecorder{}, nil)
+	oms := NewOrderManager("t", 8)
+	seq.SetOrderManager(oms)
+	seq.ReplayEvent(&event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: 1}, Symbol: "BTC", PriceMicros: 100})
+	seq.ReplayEvent(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 2}, OrderID: "t-1-1", Status: domain.OrderStatusFilled, AccumulatedQtySats: 1})
+	seq.ReplayEvent(&event.ControlEvent{BaseEvent: event.BaseEvent{Seq: 3}, Command: event.CmdReduceOnly, Value: 1})
+
+	if len(oms.Requests()) != 0 {
+		t.Error("replay must not send orders")
+	}
+	if o, ok := seq.GetOrder("t-3-1"); !ok || o.Side != domain.SideSell || o.Status != domain.OrderStatusSent {
+		t.Errorf("replay must rebuild the flatten order: %+v", o)
+	}
+	if got := seq.ReduceOnly(); len(got) != 1 || got[0] != "" {
+		t.Errorf("reduce-only venues = %q; want all", got)
+	}
+}
@@ -0,0 +1,223 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/storage"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ReplayOptions configures Replay.
+type ReplayOptions struct {
+	Snapshots *storage.SnapshotManager // Live snapshots to verify against; nil verifies nothing
+	ToSeq     uint64                   // Stop before this seq; 0 replays the whole log
+	BatchSize int                      // Events read per query (0 = DefaultFollowerBatchSize)
+}
+
+// ReplayCheckpoint compares the replayed state with one live snapshot.
+type ReplayCheckpoint struct {
+	Seq      uint64
+	Live     string   // State hash the live run recorded
+	Replay   string   // State hash the replay rebuilt
+	Sections []string // State parts that differ, for the first divergent snapshot only
+}
+
+// Diverged reports whether the replay rebuilt different state.
+func (c ReplayCheckpoint) Diverged() bool { return c.Live != c.Replay }
+
+// ReplayReport is the outcome of Replay.
+type ReplayReport struct {
+	Events      int    // Events applied (quarantined ones included)
+	NextSeq     uint64 // Seq after the last replayed event
+	StateHash   string // State after the last replayed event
+	Checkpoints []ReplayCheckpoint
+}
+
+// FirstDivergence returns the first snapshot the replay did not rebuild, or nil.
+func (r *ReplayReport) FirstDivergence() *ReplayCheckpoint {
+	for i := range r.Checkpoints {
+		if r.Checkpoints[i].Diverged() {
+			return &r.Checkpoints[i]
+		}
+	}
+	return nil
+}
+
+// Replay streams src's events through ReplayEvent, the crash recovery path,
+// on a fresh seq configured like the live one (or with another strategy, to
+// see where it decides differently). At the seq of every live snapshot the
+// state is hashed before the snapshot's TRIGGER_SNAPSHOT is applied, exactly
+// as the live run did; for the first mismatch the snapshot is loaded and the
+// differing state sections are named. Replay writes nothing.
+func Replay(ctx context.Context, seq *Sequencer, src WALSource, opts ReplayOptions) (*ReplayReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultFollowerBatchSize
+	}
+	hashes := map[uint64]string{}
+	if opts.Snapshots != nil {
+		var err error
+		if hashes, err = opts.Snapshots.LoadHashes(); err != nil {
+			return nil, fmt.Errorf("failed to load snapshot hashes: %w", err)
+		}
+	}
+	quarantined, err := src.QuarantinedSeqs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine: %w", err)
+	}
+	seq.mu.Lock()
+	seq.quarantined = quarantined
+	seq.mu.Unlock()
+
+	report := &ReplayReport{}
+	for {
+		next := seq.GetNextSeq()
+		events, err := src.LoadEventsBatch(ctx, next, opts.BatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to read WAL: %w", err)
+		}
+		for _, ev := range events {
+			if opts.ToSeq > 0 && ev.GetSeq() >= opts.ToSeq {
+				return report.finish(seq), nil
+			}
+			if ev.GetSeq() != next {
+				return report, fmt.Errorf("WAL gap: expected seq %d, got %d", next, ev.GetSeq())
+			}
+			if live, ok := hashes[next]; ok {
+				if err := report.check(seq, opts.Snapshots, next, live); err != nil {
+					return report, err
+				}
+			}
+			seq.ReplayEvent(ev)
+			next++
+			report.Events++
+		}
+		if len(events) < opts.BatchSize {
+			return report.finish(seq), nil
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+	}
+}
+
+func (r *ReplayReport) finish(seq *Sequencer) *ReplayReport {
+	r.NextSeq = seq.GetNextSeq()
+	r.StateHash = seq.StateHash()
+	return r
+}
+
+// check compares the state before event at with the live snapshot there.
+func (r *ReplayReport) check(seq *Sequencer, snapshots *storage.SnapshotManager, at uint64, live string) error {
+	seq.mu.Lock()
+	replay, err := seq.captureSnapshot(at)
+	seq.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c := ReplayCheckpoint{Seq: at, Live: live, Replay: replay.StateHash}
+	if c.Diverged() && r.FirstDivergence() == nil {
+		recorded, err := snapshots.Load(at)
+		if err != nil {
+			return fmt.Errorf("failed to load snapshot %d: %w", at, err)
+		}
+		if c.Sections, err = diffSnapshots(recorded, replay); err != nil {
+			return err
+		}
+	}
+	r.Checkpoints = append(r.Checkpoints, c)
+	return nil
+}
+
+// diffSnapshots names the state sections that differ between two snapshots,
+// e.g. "markets[UPBIT:BTC]", "books[UPBIT|BTC]" or "risk_limits". Orders are
+// hashed but not snapshotted: equal sections under different hashes point
+// there.
+func diffSnapshots(live, replay *storage.Snapshot) ([]string, error) {
+	a, err := snapshotSections(live)
+	if err != nil {
+		return nil, fmt.Errorf("live snapshot: %w", err)
+	}
+	b, err := snapshotSections(replay)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	var diff []string
+	for _, name := range slices.Sorted(maps.Keys(a)) {
+		if other, ok := b[name]; !ok || other != a[name] {
+			diff = append(diff, name)
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			diff = append(diff, name)
+		}
+	}
+	slices.Sort(diff)
+	if len(diff) == 0 {
+		diff = []string{"orders (not in snapshots)"}
+	}
+	return diff, nil
+}
+
+// snapshotSections flattens a snapshot into named, JSON-encoded sections.
+func snapshotSections(snap *storage.Snapshot) (map[string]string, error) {
+	sections := make(map[string]string)
+	add := func(name string, v any) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		sections[name] = string(data)
+		return nil
+	}
+	for k, v := range snap.Markets {
+		if err := add("markets["+k+"]", v); err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range snap.Balances {
+		if err := add("balances["+k+"]", v); err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range snap.Positions {
+		if err := add("positions["+k+"]", v); err != nil {
+			return nil, err
+		}
+	}
+
+	var eng engineSnapshot
+	if err := json.Unmarshal(snap.Engine, &eng); err != nil {
+		return nil, fmt.Errorf("failed to decode engine state: %w", err)
+	}
+	for _, b := range eng.Books {
+		if err := add("books["+b.Exchange+"|"+b.Symbol+"]", b); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range eng.Candles {
+		if err := add("candles["+c.Exchange+"|"+c.Symbol+"|"+c.Interval+"]", c); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range eng.Contexts {
+		if err := add("contexts["+m.Name+"|"+m.Subject+"]", m); err != nil {
+			return nil, err
+		}
+	}
+	eng.Books, eng.Candles, eng.Contexts = nil, nil, nil
+	data, err := json.Marshal(eng)
+	if err != nil {
+		return nil, err
+	}
+	var rest map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rest); err != nil {
+		return nil, err
+	}
+	for k, v := range rest {
+		sections[k] = string(v)
+	}
+	return sections, nil
+}
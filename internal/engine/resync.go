@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ResyncSource is the sequence-validation source name of resync END events.
+const ResyncSource = "RESYNC"
+
+// beginResync sequences a BEGIN for source ahead of the event that revealed its
+// gap (caller holds s.mu, live only). It returns false if source is already
+// resyncing: one resync covers every gap until its END.
+func (s *Sequencer) beginResync(source, detail string) bool {
+	if _, ok := s.resyncing[source]; ok {
+		return false
+	}
+	s.sequence(&event.ResyncEvent{
+		BaseEvent: event.BaseEvent{Ts: quant.Stamp(s.clock)},
+		Source:    source,
+		Phase:     event.ResyncBegin,
+		Detail:    detail,
+	})
+	return true
+}
+
+// handleResync pauses order dispatch while any gateway is resyncing. END
+// replaces the booked balances with the venue's (reservations beyond them are
+// dropped) and resumes dispatch once no resync is left.
+func (s *Sequencer) handleResync(e *event.ResyncEvent) {
+	switch e.Phase {
+	case event.ResyncBegin:
+		s.resyncing[e.Source] = e.Ts
+	case event.ResyncEnd:
+		for _, b := range e.Balances {
+			s.balanceBook.Get(b.Symbol).Sync(b.AmountSats, e.Seq)
+		}
+		delete(s.resyncing, e.Source)
+	}
+	if !s.replaying {
+		slog.Warn("GATEWAY_RESYNC",
+			slog.String("source", e.Source),
+			slog.String("phase", e.Phase),
+			slog.String("detail", e.Detail),
+			slog.Int("resyncing", len(s.resyncing)))
+	}
+}
+
+// Resyncing returns the gateways currently resyncing, sorted (thread-safe).
+func (s *Sequencer) Resyncing() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Sorted(maps.Keys(s.resyncing))
+}
+
+// ResyncClient ends gateway resyncs. It owns the RESYNC source sequence, so
+// several goroutines may share one client.
+type ResyncClient struct {
+	inbox chan<- event.Event
+	mu    sync.Mutex
+	seq   uint64
+	clock quant.Clock
+}
+
+// NewResyncClient creates a client sending to inbox (Sequencer.Inbox or a spillover front).
+func NewResyncClient(inbox chan<- event.Event) *ResyncClient {
+	return &ResyncClient{inbox: inbox, clock: quant.RealClock{}}
+}
+
+// End resumes order dispatch for source after its snapshots were sent, booking
+// balances (optional) as the venue's. Like control events, it is never
+// dropped: End blocks until the inbox accepts it or ctx is done.
+func (c *ResyncClient) End(ctx context.Context, source, detail string, balances []domain.Balance) error {
+	c.mu.Lock()
+	defer c.mu.Unlock() // Held across the send so seq order == inbox order
+
+	c.seq++
+	ev := &event.ResyncEvent{
+		BaseEvent: event.BaseEvent{Seq: c.seq, Ts: quant.Stamp(c.clock)},
+		Source:    source,
+		Phase:     event.ResyncEnd,
+		Detail:    detail,
+		Balances:  balances,
+	}
+	select {
+	case c.inbox <- ev:
+		return nil
+	case <-ctx.Done():
+		c.seq-- // Not delivered; keep the source sequence contiguous
+		return ctx.Err()
+	}
+}
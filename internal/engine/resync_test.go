@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+)
+
+// everyTickBuyer buys on every market update.
+type everyTickBuyer struct{ orderRecorder }
+
+func (s *everyTickBuyer) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	out[0] = domain.Order{Symbol: state.Symbol, Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 1}
+	return 1
+}
+
+func TestSequencer_ResyncPausesDispatch(t *testing.T) {
+	store, err := storage.NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	seq := NewSequencer(10, store, &everyTickBuyer{}, nil)
+	oms := NewOrderManager("t", 8)
+	seq.SetOrderManager(oms)
+	seq.EnableSequenceValidation(nil)
+	seq.SetGapPolicy(GapPolicy{Default: GapRule{Tolerance: 0, Action: GapResync}})
+	var resynced []string
+	seq.SetResyncHandler(func(source string) { resynced = append(resynced, source) })
+
+	market := func(workerSeq uint64) {
+		seq.ProcessEventForTest(&event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: workerSeq}, Exchange: "UPBIT", Symbol: "BTC", PriceMicros: 100})
+	}
+	market(1)
+	if len(oms.Requests()) != 1 {
+		t.Fatal("expected an order before the gap")
+	}
+	<-oms.Requests()
+
+	market(5) // Gap: BEGIN is sequenced first, the tick still updates state
+	market(9) // Another gap of the same resync
+	if !slices.Equal(resynced, []string{"UPBIT"}) || !slices.Equal(seq.Resyncing(), []string{"UPBIT"}) {
+		t.Fatalf("expected one resync of UPBIT, handler %v, resyncing %v", resynced, seq.Resyncing())
+	}
+	if len(oms.Requests()) != 0 {
+		t.Fatal("orders must not be dispatched while resyncing")
+	}
+	if state, _ := seq.GetMarketState("BTC"); state.PriceMicros != 100 {
+		t.Errorf("state must keep updating during a resync: %+v", state)
+	}
+
+	seq.ProcessEventForTest(&event.ResyncEvent{
+		BaseEvent: event.BaseEvent{Seq: 1},
+		Source:    "UPBIT",
+		Phase:     event.ResyncEnd,
+		Balances:  []domain.Balance{{Symbol: "KRW", AmountSats: 5_000}},
+	})
+	market(10)
+	if len(seq.Resyncing()) != 0 || len(oms.Requests()) != 1 {
+		t.Fatalf("dispatch must resume after END: resyncing %v, %d requests", seq.Resyncing(), len(oms.Requests()))
+	}
+	if b := seq.balanceBook.Get("KRW"); b.AmountSats != 5_000 {
+		t.Errorf("END must book the venue balance: %+v", b)
+	}
+
+	// The WAL holds both phases: a replay pauses at the same seqs
+	recovered := NewSequencer(10, store, &everyTickBuyer{}, nil)
+	recOMS := NewOrderManager("t", 8)
+	recovered.SetOrderManager(recOMS)
+	if err := recovered.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if recovered.StateHash() != seq.StateHash() {
+		t.Errorf("replay diverged: %s != %s", recovered.StateHash(), seq.StateHash())
+	}
+	if got := len(recOMS.OpenOrders()); got != 2 {
+		t.Errorf("replay rebuilt %d orders; want the 2 sent outside the resync", got)
+	}
+}
@@ -0,0 +1,297 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"sort"
+	"time"
+)
+
+// fxExchange is the source stamped on FX rate updates by ExchangeRateClient.
+const fxExchange = "FX"
+
+// routerBpsScale: venue fees are integer basis points.
+const routerBpsScale = 10_000
+
+// DefaultRouterFXMaxAge is how old an FX rate may be before a venue quoted in
+// that currency is left out of routing. The FX feed polls slowly.
+const DefaultRouterFXMaxAge = 10 * time.Minute
+
+// DefaultRouterMakerEdgeBps is how much cheaper than taking (spread plus the
+// fee difference) resting must be before a normal-urgency leg goes passive.
+const DefaultRouterMakerEdgeBps = 5
+
+// RouteVenue is one exchange the router may buy on.
+type RouteVenue struct {
+	Exchange string // Book exchange, e.g. "UPBIT", "BITGET_SPOT"
+	Quote    string // Currency its prices are in, e.g. "KRW", "USD" (USDT counts as USD)
+	Market   string // Order market on the venue ("SPOT", "FUTURES"; empty = keep the strategy's)
+	FeeBps   int64  // Taker fee
+	MakerBps int64  // Maker fee (passive legs)
+}
+
+// RouterConfig configures a Router. Zero values use the defaults.
+type RouterConfig struct {
+	Venues   []RouteVenue
+	Base     string        // Currency effective prices are compared in ("" = KRW)
+	FXMaxAge time.Duration // 0 = DefaultRouterFXMaxAge
+
+	// Passive execution: a leg may rest at the venue's best bid and is
+	// escalated to a taker order for its unfilled rest after MakerTimeout.
+	MakerTimeout time.Duration // 0 = always take
+	MakerEdgeBps int64         // 0 = DefaultRouterMakerEdgeBps
+}
+
+// RouteLeg is the part of a routed order placed on one venue.
+type RouteLeg struct {
+	Exchange     string
+	Market       string
+	QtySats      int64
+	PriceMicros  int64 // Venue limit price in its quote (0 = MARKET)
+	EffectiveAvg int64 // Expected average cost per unit in the base currency, fees included (0 = unknown)
+
+	// Maker legs rest as a LIMIT at MakerPriceMicros first; the taker order
+	// above (PriceMicros) is only sent for what is left after the timeout.
+	Maker            bool
+	MakerPriceMicros int64
+}
+
+// routeLevel is one ask level of a venue, priced in the base currency.
+type routeLevel struct {
+	venue     int
+	qty       int64
+	effective int64
+}
+
+// Router splits BUY orders across the venues a symbol trades on. Every ask
+// level of every venue is priced in the base currency, fees included, and the
+// order takes the cheapest levels first, so size spills onto the next venue
+// only when the better one's visible depth runs out. Quantity beyond all
+// visible depth goes to the venue with the cheapest best ask.
+//
+// A LIMIT order's price is a cap in the base currency: levels above it are not
+// taken and each leg is sent as a LIMIT at the cap converted into the venue's
+// quote, net of its fee. Other orders become MARKET legs.
+//
+// With a maker timeout each leg also picks its execution path: UrgencyHigh
+// orders always take; UrgencyLow orders rest at the best bid; others rest only
+// when the spread plus the taker-maker fee difference saves at least the maker
+// edge. A resting leg is canceled after the timeout and its unfilled rest sent
+// as the taker order (see Sequencer.expireMakers).
+//
+// FX rates come from the FX market updates in the WAL and books from the
+// sequencer, so a replay routes identically. Hotpath only (not goroutine-safe).
+type Router struct {
+	venues       []RouteVenue
+	base         string
+	fx           *domain.FXRates
+	makerTimeout quant.TimeStamp
+	makerEdge    int64
+
+	levels []routeLevel // Reused merge buffer
+	legs   []RouteLeg   // Reused result of Route
+	qty    []int64      // Per venue: quantity routed
+	costs  []int64      // Per venue: base-currency notional of the levels taken
+	rates  []int64      // Per venue: FX rate into base, 0 = not routable this time
+}
+
+// NewRouter creates a router over cfg.Venues, in tie-break order.
+func NewRouter(cfg RouterConfig) *Router {
+	if cfg.Base == "" {
+		cfg.Base = "KRW"
+	}
+	if cfg.FXMaxAge <= 0 {
+		cfg.FXMaxAge = DefaultRouterFXMaxAge
+	}
+	if cfg.MakerEdgeBps <= 0 {
+		cfg.MakerEdgeBps = DefaultRouterMakerEdgeBps
+	}
+	return &Router{
+		venues:       cfg.Venues,
+		base:         cfg.Base,
+		fx:           domain.NewFXRates(quant.TimeStamp(cfg.FXMaxAge.Microseconds())),
+		makerTimeout: quant.TimeStamp(cfg.MakerTimeout.Microseconds()),
+		makerEdge:    cfg.MakerEdgeBps,
+		qty:          make([]int64, len(cfg.Venues)),
+		costs:        make([]int64, len(cfg.Venues)),
+		rates:        make([]int64, len(cfg.Venues)),
+	}
+}
+
+// ObserveFX records an FX rate update (e.g. "USD/KRW"). Other updates are ignored.
+func (r *Router) ObserveFX(e *event.MarketUpdateEvent) {
+	if e.Exchange == fxExchange {
+		r.fx.Update(e.Symbol, e.PriceMicros, e.Ts)
+	}
+}
+
+// Routes reports whether order is one the router splits: a plain BUY that
+// names no venue yet.
+func (r *Router) Routes(order *domain.Order) bool {
+	return order.Side == domain.SideBuy && order.Exchange == "" && !order.IsConditional() && order.QtySats > 0
+}
+
+// Route returns the legs of order over books at now, valid until the next call.
+// It returns no legs when no venue has a synced book and a fresh FX rate; the
+// caller then sends the order unrouted.
+func (r *Router) Route(order *domain.Order, books map[bookKey]*domain.OrderBook, now quant.TimeStamp) []RouteLeg {
+	r.legs = r.legs[:0]
+	r.levels = r.levels[:0]
+	limit := int64(0)
+	if order.Type == domain.OrderTypeLimit {
+		limit = order.PriceMicros
+	}
+
+	best := -1 // Venue with the cheapest best ask
+	var bestEffective int64
+	for i, v := range r.venues {
+		r.qty[i], r.costs[i], r.rates[i] = 0, 0, 0
+		book, ok := books[bookKey{v.Exchange, order.Symbol}]
+		if !ok || !book.Synced {
+			continue
+		}
+		rate, ok := r.fx.Rate(v.Quote, r.base, now)
+		if !ok {
+			continue
+		}
+		r.rates[i] = int64(rate)
+		for j, l := range book.Asks(0) {
+			eff := r.effective(i, int64(l.PriceMicros))
+			if j == 0 && (best < 0 || eff < bestEffective) {
+				best, bestEffective = i, eff
+			}
+			if limit > 0 && eff > limit {
+				break // Asks are sorted: the rest is dearer
+			}
+			r.levels = append(r.levels, routeLevel{venue: i, qty: int64(l.QtySats), effective: eff})
+		}
+	}
+	if best < 0 {
+		return r.legs
+	}
+
+	sort.SliceStable(r.levels, func(a, b int) bool {
+		if r.levels[a].effective != r.levels[b].effective {
+			return r.levels[a].effective < r.levels[b].effective
+		}
+		return r.levels[a].venue < r.levels[b].venue
+	})
+
+	remaining := order.QtySats
+	for _, l := range r.levels {
+		if remaining == 0 {
+			break
+		}
+		take := min(l.qty, remaining)
+		r.qty[l.venue] += take
+		r.costs[l.venue] = safe.SafeAdd(r.costs[l.venue], safe.SafeMulDiv(l.effective, take, quant.QtyScale))
+		remaining -= take
+	}
+	r.qty[best] += remaining // Thinner than the order: the rest goes where the top is cheapest
+
+	for i, v := range r.venues {
+		if r.qty[i] == 0 {
+			continue
+		}
+		leg := RouteLeg{Exchange: v.Exchange, Market: v.Market, QtySats: r.qty[i]}
+		filled := r.qty[i]
+		if i == best {
+			filled -= remaining
+		}
+		if filled > 0 {
+			leg.EffectiveAvg = safe.SafeMulDiv(r.costs[i], quant.QtyScale, filled)
+		}
+		if limit > 0 {
+			leg.PriceMicros = r.venuePrice(i, limit)
+		}
+		if r.makerTimeout > 0 {
+			r.chooseMaker(order, i, books[bookKey{v.Exchange, order.Symbol}], &leg)
+		}
+		r.legs = append(r.legs, leg)
+	}
+	return r.legs
+}
+
+// chooseMaker makes leg passive at the venue's best bid (never above its
+// taker cap) when the order's urgency and the saving over taking allow.
+func (r *Router) chooseMaker(order *domain.Order, venue int, book *domain.OrderBook, leg *RouteLeg) {
+	if order.Urgency == domain.UrgencyHigh {
+		return
+	}
+	bid, okBid := book.BestBid()
+	ask, okAsk := book.BestAsk()
+	if !okBid || !okAsk || bid.PriceMicros >= ask.PriceMicros {
+		return
+	}
+	if order.Urgency != domain.UrgencyLow {
+		mid := (int64(bid.PriceMicros) + int64(ask.PriceMicros)) / 2
+		spreadBps := safe.SafeMulDiv(int64(ask.PriceMicros-bid.PriceMicros), routerBpsScale, mid)
+		v := r.venues[venue]
+		if spreadBps+v.FeeBps-v.MakerBps < r.makerEdge {
+			return
+		}
+	}
+	leg.Maker = true
+	leg.MakerPriceMicros = int64(bid.PriceMicros)
+	if leg.PriceMicros > 0 {
+		leg.MakerPriceMicros = min(leg.MakerPriceMicros, leg.PriceMicros)
+	}
+}
+
+// effective converts a venue price into the base currency and adds its fee.
+func (r *Router) effective(venue int, priceMicros int64) int64 {
+	p := safe.SafeMulDiv(priceMicros, r.rates[venue], quant.PriceScale)
+	return safe.SafeAdd(p, safe.SafeMulDiv(p, r.venues[venue].FeeBps, routerBpsScale))
+}
+
+// venuePrice converts a base-currency cap into the venue's quote, net of its fee.
+func (r *Router) venuePrice(venue int, capMicros int64) int64 {
+	gross := safe.SafeMulDiv(capMicros, routerBpsScale, routerBpsScale+r.venues[venue].FeeBps)
+	return safe.SafeMulDiv(gross, quant.PriceScale, r.rates[venue])
+}
+
+// SetRouter splits strategy BUY orders across venues with r. Must be called
+// before Run.
+func (s *Sequencer) SetRouter(r *Router) {
+	s.router = r
+}
+
+// routeOrder submits order as one child per venue leg (caller holds s.mu).
+// Unroutable orders are submitted as they are. Maker legs are submitted
+// resting and tracked until they fill or are escalated.
+func (s *Sequencer) routeOrder(order *domain.Order, ts quant.TimeStamp) {
+	legs := s.router.Route(order, s.books, ts)
+	if len(legs) == 0 {
+		s.submitOrder(order, ts)
+		return
+	}
+	for _, leg := range legs {
+		child := *order
+		child.Exchange = leg.Exchange
+		if leg.Market != "" {
+			child.Market = leg.Market
+		}
+		child.QtySats = leg.QtySats
+		child.Type = domain.OrderTypeMarket
+		child.PriceMicros = leg.PriceMicros
+		if leg.PriceMicros > 0 {
+			child.Type = domain.OrderTypeLimit
+		}
+		if !leg.Maker {
+			s.submitOrder(&child, ts)
+			continue
+		}
+		taker := child
+		child.Type = domain.OrderTypeLimit
+		child.PriceMicros = leg.MakerPriceMicros
+		if mo := s.submitOrder(&child, ts); mo != nil {
+			s.makers = append(s.makers, &makerOrder{
+				id:       mo.ID,
+				deadline: ts + s.router.makerTimeout,
+				taker:    taker,
+			})
+		}
+	}
+}
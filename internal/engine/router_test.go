@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+const tenthBTC = quant.QtyScale / 10
+
+// asks builds an ask snapshot of whole-unit prices and quantities in tenths of a coin.
+func asks(exchange string, levels ...int64) *event.OrderBookUpdateEvent {
+	e := &event.OrderBookUpdateEvent{Exchange: exchange, Symbol: "BTC", Snapshot: true}
+	for i := 0; i < len(levels); i += 2 {
+		e.Asks = append(e.Asks, domain.BookLevel{
+			PriceMicros: quant.PriceMicros(levels[i] * quant.PriceScale),
+			QtySats:     quant.QtySats(levels[i+1] * tenthBTC),
+		})
+	}
+	return e
+}
+
+func usdKRW(rate int64, ts quant.TimeStamp) *event.MarketUpdateEvent {
+	e := &event.MarketUpdateEvent{Exchange: fxExchange, Symbol: "USD/KRW", PriceMicros: quant.PriceMicros(rate * quant.PriceScale)}
+	e.Ts = ts
+	return e
+}
+
+func newTestRouter() *Router {
+	return NewRouter(RouterConfig{Venues: []RouteVenue{
+		{Exchange: "UPBIT", Quote: "KRW", FeeBps: 5},
+		{Exchange: "BITGET_SPOT", Quote: "USD", Market: domain.MarketSpot, FeeBps: 10},
+	}})
+}
+
+// routerBooks: Bitget's top (99,900 USD * 1,400 + 10bp = 139,999,860 KRW) beats
+// Upbit's (140,000,000 + 5bp = 140,070,000), Upbit's second level is the dearest.
+func routerBooks() map[bookKey]*domain.OrderBook {
+	books := make(map[bookKey]*domain.OrderBook)
+	for _, e := range []*event.OrderBookUpdateEvent{
+		asks("UPBIT", 140_000_000, 3, 141_000_000, 10),
+		asks("BITGET_SPOT", 99_900, 5, 100_100, 10),
+	} {
+		b := domain.NewOrderBook(e.Exchange, e.Symbol)
+		b.Apply(true, nil, e.Asks, 0)
+		books[bookKey{e.Exchange, e.Symbol}] = b
+	}
+	return books
+}
+
+func legQty(legs []RouteLeg, exchange string) int64 {
+	for _, l := range legs {
+		if l.Exchange == exchange {
+			return l.QtySats
+		}
+	}
+	return 0
+}
+
+func TestRouter_SplitsByEffectivePrice(t *testing.T) {
+	r := newTestRouter()
+	r.ObserveFX(usdKRW(1_400, 0))
+	books := routerBooks()
+
+	// 1 BTC: Bitget 0.5, Upbit 0.3, then Bitget's second level (140,280,140) before Upbit's (141,070,500)
+	legs := r.Route(&domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 10 * tenthBTC}, books, 0)
+	if len(legs) != 2 || legQty(legs, "UPBIT") != 3*tenthBTC || legQty(legs, "BITGET_SPOT") != 7*tenthBTC {
+		t.Fatalf("unexpected split: %+v", legs)
+	}
+	if legs[1].Market != domain.MarketSpot || legs[0].PriceMicros != 0 {
+		t.Errorf("market legs must carry the venue market and no price: %+v", legs)
+	}
+	if avg := legs[0].EffectiveAvg; avg != 140_070_000*quant.PriceScale {
+		t.Errorf("Upbit effective average = %d", avg)
+	}
+
+	// Small order: one venue, the cheapest after fees and FX
+	legs = r.Route(&domain.Order{Symbol: "BTC", Side: domain.SideBuy, QtySats: tenthBTC}, books, 0)
+	if len(legs) != 1 || legs[0].Exchange != "BITGET_SPOT" {
+		t.Errorf("expected Bitget only: %+v", legs)
+	}
+
+	// A dearer dollar flips it
+	r.ObserveFX(usdKRW(1_410, 0))
+	legs = r.Route(&domain.Order{Symbol: "BTC", Side: domain.SideBuy, QtySats: tenthBTC}, books, 0)
+	if len(legs) != 1 || legs[0].Exchange != "UPBIT" {
+		t.Errorf("expected Upbit only at 1,410 KRW/USD: %+v", legs)
+	}
+}
+
+func TestRouter_ThinBooksAndLimits(t *testing.T) {
+	r := newTestRouter()
+	r.ObserveFX(usdKRW(1_400, 0))
+	books := routerBooks()
+
+	// 5 BTC against 2.8 BTC of depth: the rest goes to the cheapest top
+	legs := r.Route(&domain.Order{Symbol: "BTC", Side: domain.SideBuy, QtySats: 50 * tenthBTC}, books, 0)
+	if legQty(legs, "UPBIT") != 13*tenthBTC || legQty(legs, "BITGET_SPOT") != 37*tenthBTC {
+		t.Errorf("unexpected thin-book split: %+v", legs)
+	}
+
+	// A KRW cap excludes the dearer levels; each leg is priced at the cap in its own quote
+	capKRW := int64(140_100_000 * quant.PriceScale)
+	legs = r.Route(&domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: capKRW, QtySats: 10 * tenthBTC}, books, 0)
+	if legQty(legs, "UPBIT") != 3*tenthBTC || legQty(legs, "BITGET_SPOT") != 7*tenthBTC {
+		t.Fatalf("unexpected capped split: %+v", legs)
+	}
+	for _, l := range legs {
+		i := 0
+		if l.Exchange == "BITGET_SPOT" {
+			i = 1
+		}
+		if eff := r.effective(i, l.PriceMicros); eff > capKRW || eff < capKRW-quant.PriceScale {
+			t.Errorf("%s limit %d costs %d, want just under the cap %d", l.Exchange, l.PriceMicros, eff, capKRW)
+		}
+	}
+}
+
+func TestRouter_StaleFXLeavesVenueOut(t *testing.T) {
+	r := NewRouter(RouterConfig{
+		Venues:   []RouteVenue{{Exchange: "UPBIT", Quote: "KRW"}, {Exchange: "BITGET_SPOT", Quote: "USD"}},
+		FXMaxAge: time.Minute,
+	})
+	books := routerBooks()
+	buy := &domain.Order{Symbol: "BTC", Side: domain.SideBuy, QtySats: tenthBTC}
+
+	if legs := r.Route(buy, books, 0); len(legs) != 1 || legs[0].Exchange != "UPBIT" {
+		t.Errorf("without a USD/KRW rate only the KRW venue is routable: %+v", legs)
+	}
+	r.ObserveFX(usdKRW(1_400, 0))
+	if legs := r.Route(buy, books, quant.TimeStamp(2*time.Minute.Microseconds())); len(legs) != 1 || legs[0].Exchange != "UPBIT" {
+		t.Errorf("a stale rate must not be used: %+v", legs)
+	}
+	if legs := r.Route(buy, map[bookKey]*domain.OrderBook{}, 0); len(legs) != 0 {
+		t.Errorf("no books = unroutable: %+v", legs)
+	}
+}
+
+// buyStrategy buys 1 BTC on the first update.
+type buyStrategy struct {
+	orderRecorder
+	sent bool
+}
+
+func (s *buyStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if s.sent || state.Symbol != "BTC" {
+		return 0
+	}
+	s.sent = true
+	out[0] = domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 10 * tenthBTC}
+	return 1
+}
+
+func TestSequencer_RoutesStrategyBuys(t *testing.T) {
+	seq := NewSequencer(10, nil, &buyStrategy{}, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+	seq.SetRouter(newTestRouter())
+
+	seq.ProcessEventForTest(asks("UPBIT", 140_000_000, 3, 141_000_000, 10))
+	seq.ProcessEventForTest(asks("BITGET_SPOT", 99_900, 5, 100_100, 10))
+	seq.ProcessEventForTest(usdKRW(1_400, 0)) // The FX update is also the strategy's first tick (symbol USD/KRW)
+	seq.ProcessEventForTest(tick("UPBIT", 140_000_000*quant.PriceScale))
+
+	upbit, bitget := <-oms.Requests(), <-oms.Requests()
+	if upbit.Exchange != "UPBIT" || upbit.QtySats != 3*tenthBTC || upbit.OrderID != "t-4-1" {
+		t.Errorf("unexpected Upbit child: %+v", upbit)
+	}
+	if bitget.Exchange != "BITGET_SPOT" || bitget.Market != domain.MarketSpot || bitget.QtySats != 7*tenthBTC || bitget.OrderID != "t-4-2" {
+		t.Errorf("unexpected Bitget child: %+v", bitget)
+	}
+}
@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+)
+
+// handleSentiment records the latest reading of a sentiment index. A reading
+// not newer than the one held (the provider re-polled before publishing a new
+// value) is ignored.
+func (s *Sequencer) handleSentiment(e *event.SentimentEvent) {
+	if cur, ok := s.sentiments[e.Index]; ok && e.Ts <= cur.Ts {
+		return
+	}
+	m := e.Sentiment()
+	s.sentiments[e.Index] = m
+
+	if h, ok := s.strategy.(strategy.SentimentHandler); ok && !s.strategyPaused {
+		h.OnSentiment(m)
+	}
+}
+
+// GetSentiment returns the latest reading of index (thread-safe).
+func (s *Sequencer) GetSentiment(index string) (domain.Sentiment, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.sentiments[index]
+	return m, ok
+}
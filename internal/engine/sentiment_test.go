@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+type sentimentStrategy struct {
+	countingStrategy
+	seen []domain.Sentiment
+}
+
+func (s *sentimentStrategy) OnSentiment(m domain.Sentiment) {
+	s.seen = append(s.seen, m)
+}
+
+func sentimentEvent(index string, ts quant.TimeStamp, value int64) *event.SentimentEvent {
+	ev := &event.SentimentEvent{Source: "SENTIMENT", Index: index, Value: value}
+	ev.Ts = ts
+	return ev
+}
+
+func TestSequencer_Sentiment(t *testing.T) {
+	strat := &sentimentStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+
+	seq.ProcessEventForTest(sentimentEvent(domain.SentimentFearGreed, 100, 25))
+	seq.ProcessEventForTest(sentimentEvent(domain.SentimentFearGreed, 100, 25)) // Re-polled: ignored
+	seq.ProcessEventForTest(sentimentEvent(domain.SentimentFunding, 150, 100))
+	seq.ProcessEventForTest(sentimentEvent(domain.SentimentFearGreed, 200, 60))
+
+	if len(strat.seen) != 3 {
+		t.Fatalf("expected 3 readings passed to the strategy, got %+v", strat.seen)
+	}
+	if m, ok := seq.GetSentiment(domain.SentimentFearGreed); !ok || m.Value != 60 || m.Ts != 200 {
+		t.Errorf("latest fear & greed = %+v, %v", m, ok)
+	}
+	if m, ok := seq.GetSentiment(domain.SentimentFunding); !ok || m.Value != 100 {
+		t.Errorf("latest funding = %+v, %v", m, ok)
+	}
+}
@@ -6,26 +6,130 @@ import (
 	"crypto_go/internal/event"
 	"crypto_go/internal/storage"
 	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// Watchdog is pinged from the hotpath loop to prove liveness (e.g. systemd WATCHDOG=1).
+// If the loop stalls inside processEvent, the pings stop and the supervisor restarts us.
+type Watchdog interface {
+	Ping()
+}
+
+// GapRecorder receives sequence anomalies detected on live input (metrics, reports).
+type GapRecorder interface {
+	RecordSequenceGap(source string, expected, got uint64)
+	RecordSequenceDuplicate(source string, expected, got uint64)
+}
+
 // Sequencer is the core single-threaded event processor.
 type Sequencer struct {
-	inbox   chan event.Event
-	markets map[string]*domain.MarketState
-	nextSeq uint64
-	store   *storage.EventStore
+	inbox      chan event.Event
+	critical   chan event.Event // Trade-critical lane, drained before market data (see CriticalInbox)
+	markets    map[string]*domain.MarketState
+	books      map[bookKey]*domain.OrderBook       // By exchange/symbol: KRW and USDT books must not mix
+	candles    map[candleKey]*domain.Candle        // In-progress bar by exchange/symbol/interval
+	contexts   map[contextKey]domain.ContextMetric // Latest reading by metric/subject
+	sentiments map[string]domain.Sentiment         // Latest sentiment reading by index
+	notices    []domain.Notice                     // Most recent notices, oldest first
+	noticeIDs  map[string]int64                    // Highest notice ID seen per exchange
+	signals    []domain.Signal                     // Most recent external signals, oldest first
+	funding    map[fundingKey]*domain.FundingEpoch // Next funding epoch by exchange/symbol
+	fundingIdx map[string][]*domain.FundingEpoch   // Same epochs by symbol, sorted by exchange
+	nextSeq    uint64
+	store      *storage.EventStore // Dead letters; also the event log unless SetEventLog
+	log        storage.EventLog    // WAL-first event log, nil = no persistence
 
 	strategy    strategy.Strategy
-	orderBuf    [16]domain.Order    // Pre-allocated buffer for strategy results (Rule #3: Zero-Alloc)
-	balanceBook *domain.BalanceBook // Rule #8: Balance invariant enforcement
+	orderBuf    [16]domain.Order     // Pre-allocated buffer for strategy results (Rule #3: Zero-Alloc)
+	balanceBook *domain.BalanceBook  // Rule #8: Balance invariant enforcement
+	positions   *domain.PositionBook // OMS fills, marked to market (same invariant discipline)
 
 	// Boundary: used to notify UI or other systems of state changes
 	onStateUpdate func(*domain.MarketState)
+	onMarket      func(event.MarketUpdateEvent)               // Live market updates with their venue (optional)
+	onApplied     func(event.Event)                           // Every live event after dispatch (ShardedSequencer merge)
+	onOrder       func(domain.Order, uint64, quant.TimeStamp) // Accepted live strategy orders (signal streaming)
+
+	watchdog         Watchdog
+	watchdogInterval time.Duration
+
+	// tradingEnabled gates order dispatch. False = monitor-only (e.g. another
+	// instance holds the trading lock). State and strategies keep running.
+	tradingEnabled bool
+
+	// Per-source sequence validation (opt-in)
+	validateSeq bool
+	sourceSeq   map[string]uint64
+	sourceInc   map[string]uint32 // Latest connection incarnation seen per source
+	gapRecorder GapRecorder
+	gapPolicy   GapPolicy
+	onResync    func(source string)
+
+	// Administrative state, changed only through ControlEvents (WAL-logged, replayable)
+	strategyPaused bool
+	halted         bool
+	haltReason     string                     // Trigger of the last kill switch (HaltEvent.Reason)
+	handover       bool                       // Between HANDOVER and TAKEOVER: no new orders while the WAL changes hands
+	resyncing      map[string]quant.TimeStamp // Gateways between resync BEGIN and END (BEGIN ts): no new orders
+	reduceOnly     map[string]bool            // Venues ("" = all) taking only position-reducing orders
+	riskLimits     map[string]int64
+	snapshots      *storage.SnapshotManager
+
+	// Periodic snapshots and fast recovery (see SetSnapshotInterval, SetFastRecovery)
+	snapshotEvery     uint64
+	onSnapshotDue     func()
+	sinceSnapshot     uint64 // Live events since the last snapshot
+	snapshotRequested bool   // onSnapshotDue fired, snapshot not yet taken
+	fastRecovery      bool
+
+	// Replay verification against the state hashes of live snapshots (see RecoverFromWAL)
+	checkpoints         map[uint64]string
+	checkpointsVerified int
+	checkpointErr       error
+
+	tradeGuard     *TradeGuard     // Entry frequency limits (optional)
+	strategyBudget *StrategyBudget // Per-event strategy time limit (optional, live only)
+	marketFilter   *MarketFilter   // Noise filter in front of the strategy (optional)
+	orders         *OrderManager   // Order lifecycle and gateway queue (optional)
+	router         *Router         // Splits BUY orders across venues (optional)
+	makers         []*makerOrder   // Resting maker legs of routed orders, oldest first
+	markExchange   string          // Market feed that values default-venue positions (empty = any)
+	reduceExempt   bool            // The strategy opted out of reduce-only (see SetReduceOnlyExempt)
+
+	// Pre-funding warnings (see SetFundingWarning)
+	fundingWarn   quant.TimeStamp
+	onFundingSoon func(domain.FundingEpoch)
+
+	// Drawdown kill switch (see SetDrawdownHalt)
+	drawdownLimit int64
+	onDrawdown    func(drawdownMicros int64)
+	pnlPeak       int64 // Highest net PnL since start or the last CmdResumeTrading
+	drawdownFired bool  // Kill requested, HaltEvent not yet applied
+
+	replaying bool // The event being dispatched comes from the WAL (no external side effects)
+
+	clock    quant.Clock     // Source of "now" (see SetClock)
+	simClock *quant.SimClock // clock when simulated: follows the dispatched events
+
+	// Poison-event quarantine (see SetDeadLetterPolicy)
+	maxAttempts  int
+	retryBackoff time.Duration
+	quarantined  map[uint64]bool // WAL seqs whose handler failed; skipped on replay
+
+	sealReq chan chan HandoverState // Stops Run between two events (see Seal)
+
+	// Lock-free gateway lanes drained next to inbox (see NewRingInbox)
+	lanes   atomic.Pointer[[]*RingInbox]
+	lanesMu sync.Mutex    // Serializes NewRingInbox
+	wake    chan struct{} // A lane got an event while Run was parked (capacity 1)
+	parked  atomic.Bool   // Run is about to block or blocked in select
 
 	mu sync.RWMutex // Used only for external reads (e.g. UI)
 }
@@ -33,43 +137,106 @@ type Sequencer struct {
 // NewSequencer creates a new sequencer instance.
 func NewSequencer(inboxSize int, store *storage.EventStore, strat strategy.Strategy, onUpdate func(*domain.MarketState)) *Sequencer {
 	seq := &Sequencer{
-		inbox:         make(chan event.Event, inboxSize),
-		markets:       make(map[string]*domain.MarketState),
-		nextSeq:       1,
-		store:         store,
-		strategy:      strat,
-		onStateUpdate: onUpdate,
-		balanceBook:   domain.NewBalanceBook(), // Rule #8: Invariant enforcement
+		inbox:          make(chan event.Event, inboxSize),
+		critical:       make(chan event.Event, inboxSize),
+		markets:        make(map[string]*domain.MarketState),
+		books:          make(map[bookKey]*domain.OrderBook),
+		candles:        make(map[candleKey]*domain.Candle),
+		contexts:       make(map[contextKey]domain.ContextMetric),
+		sentiments:     make(map[string]domain.Sentiment),
+		noticeIDs:      make(map[string]int64),
+		funding:        make(map[fundingKey]*domain.FundingEpoch),
+		fundingIdx:     make(map[string][]*domain.FundingEpoch),
+		nextSeq:        1,
+		store:          store,
+		strategy:       strat,
+		onStateUpdate:  onUpdate,
+		balanceBook:    domain.NewBalanceBook(), // Rule #8: Invariant enforcement
+		positions:      domain.NewPositionBook(),
+		tradingEnabled: true,
+		sourceSeq:      make(map[string]uint64),
+		sourceInc:      make(map[string]uint32),
+		gapPolicy:      DefaultGapPolicy(),
+		riskLimits:     make(map[string]int64),
+		resyncing:      make(map[string]quant.TimeStamp),
+		reduceOnly:     make(map[string]bool),
+		quarantined:    make(map[uint64]bool),
+		sealReq:        make(chan chan HandoverState),
+		wake:           make(chan struct{}, 1),
+	}
+	if store != nil {
+		seq.log = store
+	}
+	seq.SetClock(quant.RealClock{})
+	if a, ok := strat.(strategy.BalanceAware); ok {
+		a.SetBalances(seq.balanceBook)
 	}
 	return seq
 }
 
+// SetClock replaces the engine's source of "now" (default quant.RealClock)
+// and hands it to a strategy implementing strategy.ClockAware. A
+// *quant.SimClock is moved to each event's timestamp before dispatch, so
+// replays and backtests see the recorded time instead of wall time. Must be
+// called before RecoverFromWAL and Run.
+func (s *Sequencer) SetClock(c quant.Clock) {
+	s.clock = c
+	s.simClock, _ = c.(*quant.SimClock)
+	if a, ok := s.strategy.(strategy.ClockAware); ok {
+		a.SetClock(c)
+	}
+}
+
+// Clock returns the engine's clock.
+func (s *Sequencer) Clock() quant.Clock {
+	return s.clock
+}
+
+// SetEventLog replaces the event log written before each dispatch and
+// replayed by RecoverFromWAL (by default the store's event table), e.g. with
+// a storage.FileWAL mirrored into the store. Must be called before
+// RecoverFromWAL and Run.
+func (s *Sequencer) SetEventLog(log storage.EventLog) {
+	s.log = log
+}
+
 // RecoverFromWAL restores state by replaying all events from WAL.
 // This is the core of "Backtest is Reality" - same code path for live and replay.
+// With a snapshot manager, the state hash each live snapshot recorded is
+// recomputed when replay reaches its seq; any difference fails the recovery
+// with ErrStateHashMismatch.
 func (s *Sequencer) RecoverFromWAL(ctx context.Context) error {
-	if s.store == nil {
+	if s.log == nil {
 		slog.Info("No store configured, starting fresh")
 		return nil
 	}
 
 	// Get last sequence number from WAL
-	lastSeq, err := s.store.GetLastSeq(ctx)
+	lastSeq, err := s.log.LastSeq(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get last seq: %w", err)
 	}
 
+	if err := s.loadQuarantine(ctx); err != nil {
+		return fmt.Errorf("failed to load quarantined seqs: %w", err)
+	}
+	if err := s.loadCheckpoints(); err != nil {
+		return fmt.Errorf("failed to load snapshot hashes: %w", err)
+	}
+
 	if lastSeq == 0 {
 		slog.Info("WAL is empty, starting fresh")
-		return nil
+		return s.requeueDeadLetters(ctx)
 	}
 
-	// Load all events from WAL
-	events, err := s.store.LoadEvents(ctx, 1)
+	// Load the events after the latest snapshot (fast recovery) or the whole WAL
+	fromSeq := s.restoreLatestSnapshot(lastSeq)
+	events, err := s.log.ReadFrom(ctx, fromSeq)
 	if err != nil {
 		return fmt.Errorf("failed to load events: %w", err)
 	}
 
-	slog.Info("Replaying events from WAL", slog.Int("count", len(events)))
+	slog.Info("Replaying events from WAL", slog.Int("count", len(events)), slog.Uint64("from_seq", fromSeq))
 
 	// Replay each event using the same code path as live
 	for _, ev := range events {
@@ -78,44 +245,133 @@ func (s *Sequencer) RecoverFromWAL(ctx context.Context) error {
 
 	// Rule #8: Verify balance invariants after replay
 	s.balanceBook.VerifyAll()
+	s.positions.VerifyAll()
 
-	slog.Info("State recovered from WAL", slog.Uint64("next_seq", s.nextSeq))
-	return nil
+	// Determinism: every snapshot the live run hashed must have been rebuilt exactly
+	if s.checkpointErr != nil {
+		return s.checkpointErr
+	}
+
+	slog.Info("State recovered from WAL",
+		slog.Uint64("next_seq", s.nextSeq),
+		slog.String("state_hash", s.StateHash()),
+		slog.Int("verified_snapshots", s.checkpointsVerified))
+	return s.requeueDeadLetters(ctx)
 }
 
-// ValidateSequence checks for gaps based on strictness policy.
-func (s *Sequencer) ValidateSequence(evSeq uint64) {
-	expected := s.nextSeq
+// ValidateSequence checks a worker-assigned seq against the last one seen from
+// the same source. Each gateway owns its own counter, so a gap means the gateway
+// dropped events (e.g. inbox full) and a duplicate means a replayed message.
+// Gaps are handled according to the GapPolicy rule for evType. inc is the
+// event's connection incarnation: a seq that falls back under a newer
+// incarnation means the gateway restarted its counter with the connection, so
+// it becomes the new baseline instead of a duplicate.
+func (s *Sequencer) ValidateSequence(source string, evType event.Type, evSeq uint64, inc uint32) {
+	last, seen := s.sourceSeq[source]
+	lastInc := s.sourceInc[source]
+	s.sourceSeq[source] = evSeq
+	if inc > lastInc {
+		s.sourceInc[source] = inc
+	}
+	if !seen {
+		return // First event from this source establishes the baseline
+	}
+
+	expected := last + 1
 	if evSeq == expected {
 		return
 	}
 
-	diff := int64(evSeq) - int64(expected)
-
 	// Case 1: Replay/Duplicate (Old event)
-	if diff < 0 {
-		slog.Warn("SEQUENCE_DUPLICATE_IGNORED", slog.Uint64("expected", expected), slog.Uint64("got", evSeq))
+	if evSeq < expected {
+		if inc > lastInc {
+			slog.Info("SEQUENCE_REBASED", slog.String("source", source), slog.Uint64("incarnation", uint64(inc)), slog.Uint64("seq", evSeq))
+			return
+		}
+		s.sourceSeq[source] = last // Do not rewind
+		slog.Warn("SEQUENCE_DUPLICATE_IGNORED", slog.String("source", source), slog.Uint64("expected", expected), slog.Uint64("got", evSeq), slog.Uint64("incarnation", uint64(inc)))
+		if s.gapRecorder != nil {
+			s.gapRecorder.RecordSequenceDuplicate(source, expected, evSeq)
+		}
 		return
 	}
 
 	// Case 2: Future Gap
-	if diff > 0 {
-		// User Request: Allow small gaps <= 10 for Availability
-		if diff <= 10 {
-			slog.Warn("SEQUENCE_GAP_TOLERATED",
-				slog.Uint64("expected", expected),
-				slog.Uint64("got", evSeq),
-				slog.Int64("gap", diff))
-
-			// Fast-forward sequence to match event
-			// TODO: In Execution Phase, this MUST accept a state-resync triggers
-			s.nextSeq = evSeq
-			return
-		}
+	diff := evSeq - expected
+	if s.gapRecorder != nil {
+		s.gapRecorder.RecordSequenceGap(source, expected, evSeq)
+	}
+
+	rule := s.gapPolicy.RuleFor(evType)
+	if diff <= rule.Tolerance || rule.Action == GapTolerate {
+		slog.Warn("SEQUENCE_GAP_TOLERATED",
+			slog.String("source", source),
+			slog.Uint64("expected", expected),
+			slog.Uint64("got", evSeq),
+			slog.Uint64("gap", diff),
+			slog.Uint64("incarnation", uint64(inc)))
+		return
+	}
 
-		// Hard Panic for large gaps
-		panic(fmt.Sprintf("SEQUENCE_GAP_FATAL: expected %d, got %d", expected, evSeq))
+	if rule.Action == GapResync {
+		slog.Warn("SEQUENCE_GAP_RESYNC",
+			slog.String("source", source),
+			slog.Uint64("expected", expected),
+			slog.Uint64("got", evSeq),
+			slog.Uint64("gap", diff),
+			slog.Uint64("incarnation", uint64(inc)))
+		detail := fmt.Sprintf("%s gap: expected %d, got %d", evType, expected, evSeq)
+		if s.beginResync(source, detail) && s.onResync != nil {
+			s.onResync(source)
+		}
+		return
 	}
+
+	// Hard Panic for large gaps
+	panic(fmt.Sprintf("SEQUENCE_GAP_FATAL: source %s expected %d, got %d", source, expected, evSeq))
+}
+
+// EnableSequenceValidation turns on per-source gap detection for live events.
+// rec (optional) receives every gap/duplicate for metrics and reporting.
+// Must be called before Run.
+func (s *Sequencer) EnableSequenceValidation(rec GapRecorder) {
+	s.validateSeq = true
+	s.gapRecorder = rec
+}
+
+// SetGapPolicy replaces the gap tolerance/action rules. Must be called before Run.
+func (s *Sequencer) SetGapPolicy(p GapPolicy) {
+	s.gapPolicy = p
+}
+
+// SetResyncHandler registers the callback for GapResync, called once per
+// resync right after its BEGIN is sequenced. Order dispatch stays paused until
+// the handler's side sends the END (ResyncClient.End). It runs on the hotpath
+// goroutine and must not block.
+func (s *Sequencer) SetResyncHandler(fn func(source string)) {
+	s.onResync = fn
+}
+
+// SetMarketObserver registers a callback receiving a copy of every live market
+// update (replay is skipped), venue included. It runs on the hotpath goroutine
+// and must not block. Must be called before Run.
+func (s *Sequencer) SetMarketObserver(fn func(event.MarketUpdateEvent)) {
+	s.onMarket = fn
+}
+
+// SetOrderObserver registers a callback receiving every live strategy order
+// that passed the pre-trade checks, with the seq and time of the event that
+// produced it, before it is routed or handed to the OMS (replay is skipped).
+// It runs on the hotpath goroutine and must not block. Must be called before Run.
+func (s *Sequencer) SetOrderObserver(fn func(order domain.Order, seq uint64, ts quant.TimeStamp)) {
+	s.onOrder = fn
+}
+
+// SetWatchdog registers a liveness watchdog pinged every interval from the Run loop.
+// Must be called before Run.
+func (s *Sequencer) SetWatchdog(w Watchdog, interval time.Duration) {
+	s.watchdog = w
+	s.watchdogInterval = interval
 }
 
 // Inbox returns the event channel. External workers send events here.
@@ -136,17 +392,51 @@ func (s *Sequencer) Run(ctx context.Context) {
 		}
 	}()
 
+	// Watchdog ticks share the select with the inbox, so a stalled event blocks pings.
+	var watchdogC <-chan time.Time
+	if s.watchdog != nil && s.watchdogInterval > 0 {
+		ticker := time.NewTicker(s.watchdogInterval)
+		defer ticker.Stop()
+		watchdogC = ticker.C
+		s.watchdog.Ping()
+	}
+
 	for {
+		s.drainCritical()
+
+		// With lanes, the select waits only once they are empty
+		wakeC, parked := s.wake, false
+		if lanes := s.ringInboxes(); len(lanes) > 0 {
+			if s.drainLanes(lanes) {
+				wakeC = readyC // Still busy: look at the channels without waiting
+			} else if parked = s.park(lanes); !parked {
+				continue
+			}
+		}
+
 		select {
 		case <-ctx.Done():
 			slog.Info("Sequencer stopping...")
 			return
+		case <-watchdogC:
+			s.watchdog.Ping()
+		case reply := <-s.sealReq:
+			reply <- s.sealState()
+			slog.Info("Sequencer sealed for handover", slog.Uint64("last_seq", s.nextSeq-1))
+			return
+		case ev := <-s.critical:
+			s.processEvent(ev)
 		case ev, ok := <-s.inbox:
 			if !ok {
 				slog.Info("Sequencer inbox closed, stopping gracefully...")
 				return
 			}
+			s.drainCritical() // Both were ready: the critical lane goes first
 			s.processEvent(ev)
+		case <-wakeC:
+		}
+		if parked {
+			s.parked.Store(false)
 		}
 	}
 }
@@ -161,11 +451,15 @@ func (s *Sequencer) ReplayEvent(ev event.Event) {
 		panic(fmt.Sprintf("REPLAY_GAP_DETECTED: expected %d, got %d", s.nextSeq, ev.GetSeq()))
 	}
 
-	switch e := ev.(type) {
-	case *event.MarketUpdateEvent:
-		s.handleMarketUpdate(e)
-	case *event.OrderUpdateEvent:
-		// Pending
+	// A quarantined event stays in the WAL (seqs remain contiguous) but is not applied
+	if s.quarantined[ev.GetSeq()] {
+		slog.Warn("Skipping quarantined event", slog.Uint64("seq", ev.GetSeq()))
+		s.nextSeq++
+		return
+	}
+
+	if failure, stack := s.dispatchGuarded(ev, true); failure != nil {
+		s.quarantine(ev, storage.DeadLetterStageDispatch, ev.GetSeq(), failure, stack, 1)
 	}
 
 	s.nextSeq++
@@ -175,6 +469,18 @@ func (s *Sequencer) processEvent(ev event.Event) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// 0. Validate the gateway's own sequence before it is overwritten
+	if s.validateSeq {
+		s.ValidateSequence(eventSource(ev), ev.GetType(), ev.GetSeq(), ev.GetIncarnation())
+	}
+	ts := ev.GetTs() // ev is released once applied
+	s.sequence(ev)
+	s.recordRefusals()
+	s.retryCancels(ts)
+}
+
+// sequence stamps, persists and applies ev (caller holds s.mu).
+func (s *Sequencer) sequence(ev event.Event) {
 	// 1. Assign sequence number (Sequencer is the single source of truth for ordering)
 	// Worker-assigned seqs are ignored; the Sequencer stamps its own monotonic seq.
 	assignedSeq := s.nextSeq
@@ -183,31 +489,149 @@ func (s *Sequencer) processEvent(ev event.Event) {
 		e.Seq = assignedSeq
 	case *event.OrderUpdateEvent:
 		e.Seq = assignedSeq
+	case *event.ControlEvent:
+		e.Seq = assignedSeq
+	case *event.OrderRejectedEvent:
+		e.Seq = assignedSeq
+	case *event.OrderBookUpdateEvent:
+		e.Seq = assignedSeq
+	case *event.TradeEvent:
+		e.Seq = assignedSeq
+	case *event.CandleEvent:
+		e.Seq = assignedSeq
+	case *event.ContextEvent:
+		e.Seq = assignedSeq
+	case *event.SentimentEvent:
+		e.Seq = assignedSeq
+	case *event.NoticeEvent:
+		e.Seq = assignedSeq
+	case *event.SignalEvent:
+		e.Seq = assignedSeq
+	case *event.FundingEvent:
+		e.Seq = assignedSeq
+	case *event.TimerEvent:
+		e.Seq = assignedSeq
+	case *event.HeartbeatEvent:
+		e.Seq = assignedSeq
+	case *event.HaltEvent:
+		e.Seq = assignedSeq
+	case *event.ResyncEvent:
+		e.Seq = assignedSeq
+	case *event.ManualOrderEvent:
+		e.Seq = assignedSeq
 	}
 
 	// 2. WAL-first: Persistence
-	if s.store != nil {
-		if err := s.store.SaveEvent(context.Background(), ev); err != nil {
-			panic(fmt.Sprintf("PERSISTENCE_FAILURE: %v", err))
+	if s.log != nil {
+		if attempts, err := s.persist(ev); err != nil {
+			if s.maxAttempts <= 0 || s.store == nil {
+				panic(fmt.Sprintf("PERSISTENCE_FAILURE: %v", err))
+			}
+			// Never sequenced: the seq is reused so the WAL stays contiguous
+			s.quarantine(ev, storage.DeadLetterStagePersist, 0, err, "", attempts)
+			event.Release(ev)
+			return
 		}
 	}
 
 	// 3. Logic Dispatch
+	if failure, stack := s.dispatchGuarded(ev, false); failure != nil {
+		s.quarantine(ev, storage.DeadLetterStageDispatch, assignedSeq, failure, stack, 1)
+	}
+
+	if s.onApplied != nil {
+		s.onApplied(ev)
+	}
+
+	// 4. Release event back to pool after processing (Rule #3: Zero-Alloc)
+	event.Release(ev)
+
+	// 5. Increment Sequence
+	s.nextSeq++
+
+	// 6. Periodic snapshot request (goes through the WAL like any control command)
+	s.countSnapshotEvent()
+}
+
+// dispatch applies ev to state. Shared by live processing and replay.
+func (s *Sequencer) dispatch(ev event.Event, replay bool) {
+	s.replaying = replay
+	if s.simClock != nil {
+		s.simClock.Set(ev.GetTs())
+	}
 	switch e := ev.(type) {
 	case *event.MarketUpdateEvent:
-		s.handleMarketUpdate(e)
-		// 4. Release event back to pool after processing (Rule #3: Zero-Alloc)
-		event.ReleaseMarketUpdateEvent(e)
+		s.handleMarketUpdate(e, replay)
 	case *event.OrderUpdateEvent:
-		// Pending — release when OrderUpdateEvent handling is implemented
-		event.ReleaseOrderUpdateEvent(e)
+		s.handleOrderUpdate(e)
+	case *event.ControlEvent:
+		s.handleControl(e, replay)
+	case *event.OrderRejectedEvent:
+		s.handleOrderRejected(e)
+	case *event.OrderBookUpdateEvent:
+		s.handleOrderBookUpdate(e)
+	case *event.TradeEvent:
+		s.handleTrade(e)
+	case *event.CandleEvent:
+		s.handleCandle(e)
+	case *event.ContextEvent:
+		s.handleContext(e)
+	case *event.SentimentEvent:
+		s.handleSentiment(e)
+	case *event.NoticeEvent:
+		s.handleNotice(e)
+	case *event.SignalEvent:
+		s.handleSignal(e)
+	case *event.FundingEvent:
+		s.handleFunding(e)
+	case *event.TimerEvent:
+		s.handleTimer(e)
+	case *event.HeartbeatEvent:
+		// Liveness marker for WAL analysis: no state to change
+	case *event.HaltEvent:
+		s.handleHalt(e, replay)
+	case *event.ResyncEvent:
+		s.handleResync(e)
+	case *event.ManualOrderEvent:
+		s.handleManualOrder(e, replay)
 	}
+}
 
-	// 5. Increment Sequence
-	s.nextSeq++
+// eventSource identifies the gateway that produced ev (one seq counter per source).
+func eventSource(ev event.Event) string {
+	switch e := ev.(type) {
+	case *event.MarketUpdateEvent:
+		return e.Exchange
+	case *event.OrderBookUpdateEvent:
+		return e.Exchange
+	case *event.TradeEvent:
+		return e.Exchange
+	case *event.CandleEvent:
+		return e.Exchange
+	case *event.ContextEvent:
+		return e.Source
+	case *event.SentimentEvent:
+		return e.Source
+	case *event.NoticeEvent:
+		return e.Source
+	case *event.SignalEvent:
+		return e.Source
+	case *event.FundingEvent:
+		return e.Exchange
+	case *event.TimerEvent:
+		return e.Source
+	case *event.HeartbeatEvent:
+		return e.Source
+	case *event.ControlEvent, *event.HaltEvent, *event.ManualOrderEvent:
+		return ControlSource
+	case *event.ResyncEvent:
+		return ResyncSource
+	default:
+		return "ORDER"
+	}
 }
 
-func (s *Sequencer) handleMarketUpdate(e *event.MarketUpdateEvent) {
+func (s *Sequencer) handleMarketUpdate(e *event.MarketUpdateEvent, replay bool) {
 	state, ok := s.markets[e.Symbol]
 	if !ok {
 		// Cold path: New symbol allocation
@@ -219,18 +643,46 @@ func (s *Sequencer) handleMarketUpdate(e *event.MarketUpdateEvent) {
 	state.PriceMicros = e.PriceMicros
 	state.TotalQtySats = e.QtySats
 	state.LastUpdateUnixM = e.Ts
+	s.markFunding(state, e.Ts)
+
+	if s.router != nil {
+		s.router.ObserveFX(e)
+	}
+	s.markPositions(e)
+	s.observeRisk(e.Exchange, e.Symbol, int64(e.PriceMicros), e.Ts)
+
+	// Locally simulated OCO / trailing-stop orders see the price before the strategy
+	s.triggerOrders(e)
+	s.expireMakers(e.Ts)
+	s.sweepStale(e.Ts, e)
 
 	// Trace logging should be disabled or sampled in production (Rule #6: Lean Metrics)
 	// slog.Debug("HOT_INGEST", "symbol", e.Symbol, "price", e.PriceMicros)
 
-	// Invoke Strategy
-	if s.strategy != nil {
+	// Invoke Strategy (state above is always current; the filter only saves strategy work)
+	if s.strategy != nil && !s.strategyPaused && (s.marketFilter == nil || s.marketFilter.Allow(e)) {
+		if h, ok := s.strategy.(strategy.VenueHandler); ok {
+			h.OnVenueUpdate(e.Exchange, *state)
+		}
+		// Wall time is only measured live; replay must not depend on it
+		timed := s.strategyBudget != nil && !replay
+		var start time.Time
+		if timed {
+			start = s.clock.Now()
+		}
 		count := s.strategy.OnMarketUpdate(*state, s.orderBuf[:])
+		if timed {
+			s.strategyBudget.Observe(s.clock.Now().Sub(start))
+		}
 		for i := 0; i < count; i++ {
-			s.handleStrategyAction(&s.orderBuf[i])
+			s.handleStrategyAction(&s.orderBuf[i], e.Ts)
 		}
 	}
 
+	if s.onMarket != nil && !replay {
+		s.onMarket(*e) // Copy: the event returns to the pool after dispatch
+	}
+
 	if s.onStateUpdate != nil {
 		// Rule #2: Pass copy to external callback, not pointer (state ownership protection)
 		stateCopy := *state
@@ -238,11 +690,69 @@ func (s *Sequencer) handleMarketUpdate(e *event.MarketUpdateEvent) {
 	}
 }
 
-func (s *Sequencer) handleStrategyAction(order *domain.Order) {
+// handleTrade passes a trade to a tape-aware strategy and the risk engine's
+// price collar. Trades carry no market state.
+func (s *Sequencer) handleTrade(e *event.TradeEvent) {
+	s.observeRisk(e.Exchange, e.Symbol, int64(e.PriceMicros), e.Ts)
+	if h, ok := s.strategy.(strategy.TradeHandler); ok && !s.strategyPaused {
+		h.OnTrade(e.Trade())
+	}
+}
+
+// handleOrderRejected closes the order in the OMS and lets the strategy react
+// to the venue rejection.
+func (s *Sequencer) handleOrderRejected(e *event.OrderRejectedEvent) {
+	rej := e.Rejection()
+	if s.orders != nil {
+		if mo := s.orders.Reject(e); mo != nil {
+			rej.Order.Strategy = mo.Strategy // The venue does not know which strategy sent it
+			if s.strategy != nil {
+				s.strategy.OnOrderUpdate(mo.Order)
+			}
+		}
+	}
+	if h, ok := s.strategy.(strategy.RejectionHandler); ok {
+		h.OnOrderRejected(rej)
+	}
+}
+
+// SetTradingEnabled toggles order dispatch. Must be called before Run.
+func (s *Sequencer) SetTradingEnabled(enabled bool) {
+	s.tradingEnabled = enabled
+}
+
+// TradingEnabled reports whether strategy orders are dispatched.
+func (s *Sequencer) TradingEnabled() bool {
+	return s.tradingEnabled
+}
+
+// dispatchStopped reports whether no new order may be sent: monitor-only,
+// halted, handing over or resyncing a gateway.
+func (s *Sequencer) dispatchStopped() bool {
+	return !s.tradingEnabled || s.halted || s.handover || len(s.resyncing) > 0
+}
+
+func (s *Sequencer) handleStrategyAction(order *domain.Order, ts quant.TimeStamp) {
+	if s.dispatchStopped() {
+		return // Monitor-only: strategy signals are computed but never sent
+	}
+	if limit, ok := s.riskLimits[RiskLimitMaxOrderQtySats]; ok && order.QtySats > limit {
+		return // Rejected by operator risk limit
+	}
+	if s.tradeGuard != nil && !s.tradeGuard.Allow(order, ts) {
+		return // Cooldown or daily entry limit reached
+	}
+	if s.onOrder != nil && !s.replaying {
+		s.onOrder(*order, s.nextSeq, ts)
+	}
+
 	// Root of Rule #1: Deterministic order generation
 	// Rule #6: Hotpath logging removed. Use metrics or sampling if needed.
-
-	// TODO: Create OrderRequestEvent and dispatch to execution gateway
+	if s.router != nil && s.router.Routes(order) {
+		s.routeOrder(order, ts)
+		return
+	}
+	s.submitOrder(order, ts)
 }
 
 // GetMarketState returns a snapshot of the market state (external read).
@@ -257,6 +767,25 @@ func (s *Sequencer) GetMarketState(symbol string) (domain.MarketState, bool) {
 	return *state, true // Return copy
 }
 
+// MarketsSnapshot is the state of every market at one sequence point.
+type MarketsSnapshot struct {
+	Seq     uint64                        `json:"seq"` // Last applied event (0 = none yet)
+	Markets map[string]domain.MarketState `json:"markets"`
+}
+
+// GetMarketsSnapshot copies all market states under one lock, so every entry
+// reflects the same sequence point (external read).
+func (s *Sequencer) GetMarketsSnapshot() MarketsSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	markets := make(map[string]domain.MarketState, len(s.markets))
+	for symbol, state := range s.markets {
+		markets[symbol] = *state
+	}
+	return MarketsSnapshot{Seq: s.nextSeq - 1, Markets: markets}
+}
+
 // DumpState writes the entire internal state to a file (for post-mortem).
 func (s *Sequencer) DumpState(filename string) {
 	slog.Info("Dumping internal state...", slog.String("file", filename))
@@ -270,16 +799,19 @@ func (s *Sequencer) DumpState(filename string) {
 			}
 		}()
 		s.balanceBook.VerifyAll()
+		s.positions.VerifyAll()
 	}()
 
 	data := struct {
-		NextSeq  uint64                         `json:"next_seq"`
-		Markets  map[string]*domain.MarketState `json:"markets"`
-		Balances map[string]domain.Balance      `json:"balances"`
+		NextSeq   uint64                         `json:"next_seq"`
+		Markets   map[string]*domain.MarketState `json:"markets"`
+		Balances  map[string]domain.Balance      `json:"balances"`
+		Positions map[string]domain.Position     `json:"positions"`
 	}{
-		NextSeq:  s.nextSeq,
-		Markets:  s.markets,
-		Balances: s.balanceBook.Snapshot(),
+		NextSeq:   s.nextSeq,
+		Markets:   s.markets,
+		Balances:  s.balanceBook.Snapshot(),
+		Positions: s.positions.Snapshot(),
 	}
 
 	b, err := json.MarshalIndent(data, "", "  ")
@@ -299,6 +831,11 @@ func (s *Sequencer) BalanceBook() *domain.BalanceBook {
 	return s.balanceBook
 }
 
+// PositionBook returns the position book for external access (e.g., risk checks, testing).
+func (s *Sequencer) PositionBook() *domain.PositionBook {
+	return s.positions
+}
+
 // GetNextSeq returns the next expected sequence number (for testing).
 func (s *Sequencer) GetNextSeq() uint64 {
 	s.mu.RLock()
@@ -30,7 +30,7 @@ func BenchmarkSequencer_ProcessEvent(b *testing.B) {
 		seq.nextSeq = uint64(i + 1) // Align sequence to avoid gap panic
 
 		// Direct call to handleMarketUpdate (Hotpath core)
-		seq.handleMarketUpdate(ev)
+		seq.handleMarketUpdate(ev, false)
 	}
 
 	event.ReleaseMarketUpdateEvent(ev)
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"crypto_go/internal/domain"
 	"crypto_go/internal/event"
 	"crypto_go/internal/storage"
 	"crypto_go/pkg/quant"
@@ -88,3 +89,104 @@ func TestSequencer_Replay_SingleEvent(t *testing.T) {
 		t.Errorf("nextSeq mismatch: original=%d, replayed=%d", originalNextSeq, replayedNextSeq)
 	}
 }
+
+// TestSequencer_Replay_FileWAL recovers from a file WAL mirrored into the store.
+func TestSequencer_Replay_FileWAL(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewEventStore(t.TempDir() + "/test_file_wal.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	open := func() (*storage.FileWAL, storage.EventLog) {
+		wal, err := storage.OpenFileWAL(t.TempDir()+"/wal", storage.FileWALOptions{})
+		if err != nil {
+			t.Fatalf("failed to open file WAL: %v", err)
+		}
+		log, err := storage.NewMirroredLog(ctx, wal, store)
+		if err != nil {
+			t.Fatalf("failed to mirror: %v", err)
+		}
+		return wal, log
+	}
+
+	wal, log := open()
+	sequencer1 := NewSequencer(100, store, nil, nil)
+	sequencer1.SetEventLog(log)
+	for i, price := range []int64{100_000_000, 101_000_000} {
+		ev := &event.MarketUpdateEvent{Symbol: "BTC", Exchange: "UPBIT", PriceMicros: quant.PriceMicros(price), QtySats: 1}
+		ev.Ts = quant.TimeStamp(1704067200000000) + quant.TimeStamp(i)
+		sequencer1.ProcessEventForTest(ev)
+	}
+	wal.Close()
+
+	if last, _ := store.GetLastSeq(ctx); last != 2 {
+		t.Fatalf("events must be mirrored into the store, last seq = %d", last)
+	}
+
+	// A fresh WAL directory is seeded from the store, then replayed
+	wal, log = open()
+	defer wal.Close()
+	sequencer2 := NewSequencer(100, store, nil, nil)
+	sequencer2.SetEventLog(log)
+	if err := sequencer2.RecoverFromWAL(ctx); err != nil {
+		t.Fatalf("RecoverFromWAL failed: %v", err)
+	}
+	if sequencer2.StateHash() != sequencer1.StateHash() {
+		t.Errorf("replayed state differs: %s != %s", sequencer2.StateHash(), sequencer1.StateHash())
+	}
+	if sequencer2.GetNextSeq() != 3 {
+		t.Errorf("nextSeq = %d, want 3", sequencer2.GetNextSeq())
+	}
+}
+
+// TestSequencer_Replay_OMSQueueFull replays an order refused by the full
+// gateway queue: the refusal comes back through the log, so the recovered
+// OMS matches the live one.
+func TestSequencer_Replay_OMSQueueFull(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewEventStore(t.TempDir() + "/test_queue_full.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	sequencer1 := NewSequencer(100, store, alwaysBuyStrategy{}, nil)
+	oms := NewOrderManager("t", 1)
+	oms.RecordRefusals(new(uint64))
+	sequencer1.SetOrderManager(oms)
+
+	for i := int64(0); i < 2; i++ {
+		ev := &event.MarketUpdateEvent{Symbol: "BTC", Exchange: "UPBIT", PriceMicros: 100_000_000, QtySats: 1}
+		ev.Ts = quant.TimeStamp(1704067200000000) + quant.TimeStamp(i)
+		sequencer1.ProcessEventForTest(ev)
+	}
+	if o, _ := sequencer1.GetOrder("t-2-1"); o.Status != domain.OrderStatusRejected || oms.Dropped() != 1 || sequencer1.GetInFlightOrders() != 1 {
+		t.Fatalf("refused order = %s, dropped %d, in flight %d; want REJECTED with only t-1-1 in flight",
+			o.Status, oms.Dropped(), sequencer1.GetInFlightOrders())
+	}
+	events, err := store.ReadFrom(ctx, 1)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("WAL has %d events; want both market updates and the refusal", len(events))
+	}
+	rej, ok := events[2].(*event.OrderRejectedEvent)
+	if !ok || rej.OrderID != "t-2-1" || rej.Reason != domain.RejectRateLimited || rej.Exchange != OMSExchange {
+		t.Fatalf("the refusal must follow the refused order in the WAL, got %+v", events[2])
+	}
+
+	sequencer2 := NewSequencer(100, store, alwaysBuyStrategy{}, nil)
+	sequencer2.SetOrderManager(NewOrderManager("t", 1))
+	if err := sequencer2.RecoverFromWAL(ctx); err != nil {
+		t.Fatalf("RecoverFromWAL failed: %v", err)
+	}
+	if sequencer2.StateHash() != sequencer1.StateHash() {
+		t.Errorf("replayed state differs: %s != %s", sequencer2.StateHash(), sequencer1.StateHash())
+	}
+	if len(sequencer2.GetOpenOrders()) != 1 {
+		t.Errorf("open orders after replay = %+v; want only the queued one", sequencer2.GetOpenOrders())
+	}
+}
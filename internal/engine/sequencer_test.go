@@ -2,8 +2,11 @@ package engine
 
 import (
 	"context"
+	"crypto_go/internal/domain"
 	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
 	"crypto_go/pkg/quant"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -80,3 +83,315 @@ func TestSequencer_ReplayGapPanic(t *testing.T) {
 
 	seq.ReplayEvent(ev)
 }
+
+// countingWatchdog records pings from the Run loop.
+type countingWatchdog struct {
+	pings atomic.Int32
+}
+
+func (w *countingWatchdog) Ping() { w.pings.Add(1) }
+
+func TestSequencer_WatchdogPing(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	wd := &countingWatchdog{}
+	seq.SetWatchdog(wd, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go seq.Run(ctx)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if wd.pings.Load() < 3 {
+		t.Errorf("Expected watchdog to be pinged repeatedly, got %d pings", wd.pings.Load())
+	}
+}
+
+func TestSequencer_TradingEnabledToggle(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	if !seq.TradingEnabled() {
+		t.Fatal("Expected trading enabled by default")
+	}
+
+	seq.SetTradingEnabled(false)
+	if seq.TradingEnabled() {
+		t.Error("Expected monitor-only mode after SetTradingEnabled(false)")
+	}
+}
+
+type recordingGaps struct {
+	gaps, dups []uint64
+}
+
+func (r *recordingGaps) RecordSequenceGap(source string, expected, got uint64) {
+	r.gaps = append(r.gaps, got-expected)
+}
+
+func (r *recordingGaps) RecordSequenceDuplicate(source string, expected, got uint64) {
+	r.dups = append(r.dups, got)
+}
+
+func TestSequencer_PerSourceValidation(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	rec := &recordingGaps{}
+	seq.EnableSequenceValidation(rec)
+
+	send := func(exchange string, workerSeq uint64) {
+		seq.ProcessEventForTest(&event.MarketUpdateEvent{
+			BaseEvent: event.BaseEvent{Seq: workerSeq},
+			Symbol:    "BTC",
+			Exchange:  exchange,
+		})
+	}
+
+	// Interleaved sources with independent counters: no anomalies
+	send("UPBIT", 1)
+	send("BITGET_SPOT", 100)
+	send("UPBIT", 2)
+	send("BITGET_SPOT", 101)
+
+	send("UPBIT", 5) // 3,4 dropped -> gap of 2
+	send("UPBIT", 4) // late/duplicate
+
+	if len(rec.gaps) != 1 || rec.gaps[0] != 2 {
+		t.Errorf("Expected one gap of size 2, got %v", rec.gaps)
+	}
+	if len(rec.dups) != 1 || rec.dups[0] != 4 {
+		t.Errorf("Expected one duplicate (seq 4), got %v", rec.dups)
+	}
+
+	// Default policy only observes: a large burst of drops must not halt
+	send("UPBIT", 100)
+	if len(rec.gaps) != 2 || rec.gaps[1] != 94 {
+		t.Errorf("Expected large gap to be recorded, got %v", rec.gaps)
+	}
+
+	// Halting is opt-in
+	seq.SetGapPolicy(GapPolicy{Default: GapRule{Tolerance: 10, Action: GapHalt}})
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for gap beyond tolerance with explicit halt policy")
+		}
+	}()
+	send("UPBIT", 200)
+}
+
+func TestSequencer_ValidationPerIncarnation(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	rec := &recordingGaps{}
+	seq.EnableSequenceValidation(rec)
+
+	send := func(inc uint32, workerSeq uint64) {
+		seq.ProcessEventForTest(&event.MarketUpdateEvent{
+			BaseEvent: event.BaseEvent{Seq: workerSeq, Incarnation: inc},
+			Symbol:    "BTC",
+			Exchange:  "UPBIT",
+		})
+	}
+
+	send(1, 10)
+	send(1, 11)
+	send(1, 11) // Replayed within the connection
+	send(2, 1)  // Counter restarted with the reconnect: new baseline
+	send(2, 2)
+	send(1, 2) // Straggler of the old connection
+
+	if len(rec.dups) != 2 || rec.dups[0] != 11 || rec.dups[1] != 2 {
+		t.Errorf("duplicates = %v; want 11 and the straggler's 2", rec.dups)
+	}
+	if len(rec.gaps) != 0 {
+		t.Errorf("a rebase must not count as a gap: %v", rec.gaps)
+	}
+}
+
+func TestSequencer_GapPolicyPerType(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	seq.EnableSequenceValidation(nil)
+	seq.SetGapPolicy(GapPolicy{
+		Default: GapRule{Tolerance: 10, Action: GapHalt},
+		ByType: map[event.Type]GapRule{
+			event.EvMarketUpdate: {Tolerance: 2, Action: GapResync},
+			event.EvOrderUpdate:  {Tolerance: 0, Action: GapHalt},
+		},
+	})
+
+	var resynced []string
+	seq.SetResyncHandler(func(source string) { resynced = append(resynced, source) })
+
+	market := func(workerSeq uint64) {
+		seq.ProcessEventForTest(&event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: workerSeq}, Exchange: "UPBIT"})
+	}
+
+	market(1)
+	market(4) // gap 2: within tolerance
+	if len(resynced) != 0 {
+		t.Fatalf("Expected no resync within tolerance, got %v", resynced)
+	}
+	market(100) // gap 95: lenient market data resyncs instead of halting
+	if len(resynced) != 1 || resynced[0] != "UPBIT" {
+		t.Fatalf("Expected resync for UPBIT, got %v", resynced)
+	}
+
+	// Orders are strict: a single missing update halts
+	order := func(workerSeq uint64) {
+		seq.ProcessEventForTest(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: workerSeq}})
+	}
+	order(1)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected halt on order gap")
+		}
+	}()
+	order(3)
+}
+
+func TestParseGapAction(t *testing.T) {
+	for in, want := range map[string]GapAction{"halt": GapHalt, "Tolerate": GapTolerate, "resync": GapResync} {
+		got, err := ParseGapAction(in)
+		if err != nil || got != want {
+			t.Errorf("ParseGapAction(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParseGapAction("ignore"); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+}
+
+// rejectRecorder records rejections delivered by the sequencer.
+type rejectRecorder struct {
+	alwaysBuyStrategy
+	got []domain.OrderRejection
+}
+
+func (r *rejectRecorder) OnOrderRejected(rej domain.OrderRejection) { r.got = append(r.got, rej) }
+
+func TestSequencer_OrderRejectedReachesStrategy(t *testing.T) {
+	strat := &rejectRecorder{}
+	seq := NewSequencer(10, nil, strat, nil)
+
+	seq.ProcessEventForTest(&event.OrderRejectedEvent{OrderID: "o1", Symbol: "BTC", Side: domain.SideBuy, Reason: domain.RejectMinSize, Code: "45111"})
+
+	if len(strat.got) != 1 || strat.got[0].Reason != domain.RejectMinSize || strat.got[0].Order.Status != domain.OrderStatusRejected {
+		t.Fatalf("unexpected rejections: %+v", strat.got)
+	}
+	if seq.GetNextSeq() != 2 {
+		t.Errorf("rejection must be sequenced, nextSeq=%d", seq.GetNextSeq())
+	}
+}
+
+func TestSequencer_StrategyRegistryAttribution(t *testing.T) {
+	btc, eth := &rejectRecorder{}, &rejectRecorder{}
+	reg := strategy.NewStrategyRegistry()
+	reg.Register("btc", btc, "BTC")
+	reg.Register("eth", eth, "ETH")
+	seq := NewSequencer(10, nil, reg, nil)
+	oms := NewOrderManager("t", 8)
+	seq.SetOrderManager(oms)
+
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "ETH", PriceMicros: 100})
+	req := <-oms.Requests()
+	if req.Strategy != "eth" || req.Symbol != "ETH" {
+		t.Fatalf("request = %+v; want eth's ETH order", req)
+	}
+	if o, _ := seq.GetOrder(req.OrderID); o.Strategy != "eth" {
+		t.Errorf("OMS order = %+v; want it attributed to eth", o)
+	}
+
+	seq.ProcessEventForTest(&event.OrderRejectedEvent{OrderID: req.OrderID, Symbol: "ETH", Reason: domain.RejectMinSize})
+	if len(eth.got) != 1 || len(btc.got) != 0 || eth.got[0].Order.Strategy != "eth" {
+		t.Errorf("rejections btc=%+v eth=%+v; want the owner only", btc.got, eth.got)
+	}
+}
+
+// bookStrategy records the best ask it sees on every book update.
+type bookStrategy struct {
+	countingStrategy
+	bestAsks []int64
+}
+
+func (s *bookStrategy) OnOrderBookUpdate(book *domain.OrderBook) {
+	if ask, ok := book.BestAsk(); ok {
+		s.bestAsks = append(s.bestAsks, int64(ask.PriceMicros))
+	}
+}
+
+func TestSequencer_OrderBookState(t *testing.T) {
+	strat := &bookStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	level := func(price, qty int64) domain.BookLevel {
+		return domain.BookLevel{PriceMicros: quant.PriceMicros(price), QtySats: quant.QtySats(qty)}
+	}
+
+	seq.ProcessEventForTest(&event.OrderBookUpdateEvent{Exchange: "UPBIT", Symbol: "BTC",
+		Bids: []domain.BookLevel{level(99, 1)}}) // Delta before snapshot: ignored
+	if _, ok := seq.GetOrderBook("UPBIT", "BTC"); ok {
+		t.Fatal("book must not be readable before a snapshot")
+	}
+
+	seq.ProcessEventForTest(&event.OrderBookUpdateEvent{Exchange: "UPBIT", Symbol: "BTC", Snapshot: true,
+		Bids: []domain.BookLevel{level(99, 1)}, Asks: []domain.BookLevel{level(101, 1)}})
+	seq.ProcessEventForTest(&event.OrderBookUpdateEvent{Exchange: "BITGET_SPOT", Symbol: "BTC", Snapshot: true,
+		Asks: []domain.BookLevel{level(70, 1)}})
+
+	book, ok := seq.GetOrderBook("UPBIT", "BTC")
+	if !ok {
+		t.Fatal("expected the Upbit book")
+	}
+	if ask, _ := book.BestAsk(); ask.PriceMicros != 101 {
+		t.Errorf("books of different exchanges must not mix: best ask %d", ask.PriceMicros)
+	}
+	if len(strat.bestAsks) != 2 || strat.bestAsks[0] != 101 || strat.bestAsks[1] != 70 {
+		t.Errorf("strategy saw %v", strat.bestAsks)
+	}
+}
+
+func TestSequencer_MarketsSnapshot(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	if snap := seq.GetMarketsSnapshot(); snap.Seq != 0 || len(snap.Markets) != 0 {
+		t.Fatalf("empty sequencer: %+v", snap)
+	}
+
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 100})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "ETH", PriceMicros: 10})
+	snap := seq.GetMarketsSnapshot()
+	if snap.Seq != 2 || snap.Markets["BTC"].PriceMicros != 100 || snap.Markets["ETH"].PriceMicros != 10 {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+
+	// The snapshot is a copy: later events must not change it
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC", PriceMicros: 200})
+	if snap.Markets["BTC"].PriceMicros != 100 {
+		t.Errorf("snapshot changed after a later event: %d", snap.Markets["BTC"].PriceMicros)
+	}
+	if next := seq.GetMarketsSnapshot(); next.Seq != 3 || next.Markets["BTC"].PriceMicros != 200 {
+		t.Errorf("unexpected snapshot %+v", next)
+	}
+}
+
+type tapeStrategy struct {
+	countingStrategy
+	trades []domain.Trade
+}
+
+func (s *tapeStrategy) OnTrade(trade domain.Trade) { s.trades = append(s.trades, trade) }
+
+func TestSequencer_TradeReachesStrategy(t *testing.T) {
+	strat := &tapeStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+
+	tr := &event.TradeEvent{Symbol: "BTC", Exchange: "UPBIT", Side: domain.SideBuy, PriceMicros: 100, QtySats: 5}
+	tr.Ts = 42
+	seq.ProcessEventForTest(tr)
+	if len(strat.trades) != 1 || strat.trades[0].Side != domain.SideBuy || strat.trades[0].Ts != 42 {
+		t.Fatalf("strategy saw %+v", strat.trades)
+	}
+	if _, ok := seq.GetMarketState("BTC"); ok {
+		t.Error("a trade must not create market state")
+	}
+
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdPauseStrategy})
+	seq.ProcessEventForTest(&event.TradeEvent{Symbol: "BTC", Exchange: "UPBIT", Side: domain.SideSell})
+	if len(strat.trades) != 1 {
+		t.Error("paused strategy must not receive trades")
+	}
+}
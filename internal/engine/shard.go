@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"cmp"
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// MergedEvent is one event of the merged stream of a ShardedSequencer.
+type MergedEvent struct {
+	Seq      uint64 // Position in the merged stream (1, 2, ...; gapless)
+	Shard    int
+	ShardSeq uint64 // Seq the shard assigned (and wrote to its WAL)
+	Event    event.Event
+}
+
+// ShardedSequencer hash-routes events by symbol to N independent Sequencer
+// shards, each with its own inbox, Run goroutine and event log, so symbols no
+// longer queue behind each other on one hotpath. Every venue of a symbol
+// lands in the same shard, so per-symbol logic (strategies, premium of one
+// coin across exchanges) sees one ordered stream. Events without a symbol
+// (control, halt, context, sentiment, notices, timers, heartbeats) are copied
+// to every shard.
+//
+// Cross-symbol consumers (premium needing the FX feed, portfolio equity)
+// subscribe to the merged stream: every applied event of every shard in one
+// total order that keeps each shard's order. The merged market view served by
+// GetMarketsSnapshot is built from the same stream, so it reflects one point
+// of it.
+type ShardedSequencer struct {
+	inbox  chan event.Event
+	shards []*shard
+
+	mu          sync.RWMutex // Serializes the merged stream across shard goroutines
+	mergedSeq   uint64
+	markets     map[string]domain.MarketState
+	subscribers []func(MergedEvent)
+}
+
+// shard is one Sequencer plus the market state its last event produced.
+type shard struct {
+	id      int
+	seq     *Sequencer
+	state   domain.MarketState // onStateUpdate of the event being dispatched
+	updated bool
+}
+
+// NewShardedSequencer creates n shards with inboxes of inboxSize. newStrategy
+// builds the strategy of each shard (strategies are not shared: each shard
+// runs on its own goroutine); it may be nil.
+func NewShardedSequencer(n, inboxSize int, newStrategy func(shard int) strategy.Strategy) *ShardedSequencer {
+	m := &ShardedSequencer{
+		inbox:   make(chan event.Event, inboxSize),
+		markets: make(map[string]domain.MarketState),
+	}
+	for i := 0; i < n; i++ {
+		sh := &shard{id: i}
+		var strat strategy.Strategy
+		if newStrategy != nil {
+			strat = newStrategy(i)
+		}
+		sh.seq = NewSequencer(inboxSize, nil, strat, func(state *domain.MarketState) {
+			sh.state, sh.updated = *state, true // Shard goroutine; published with the event
+		})
+		sh.seq.onApplied = func(ev event.Event) { m.publish(sh, ev) }
+		m.shards = append(m.shards, sh)
+	}
+	return m
+}
+
+// Shards returns the number of shards.
+func (m *ShardedSequencer) Shards() int {
+	return len(m.shards)
+}
+
+// Shard returns shard i, e.g. to give it an event log or a snapshot manager
+// before RecoverFromWAL and Run.
+func (m *ShardedSequencer) Shard(i int) *Sequencer {
+	return m.shards[i].seq
+}
+
+// ShardFor returns the shard owning symbol (FNV-1a; stable across restarts
+// for the same shard count, which each shard's WAL depends on).
+func (m *ShardedSequencer) ShardFor(symbol string) int {
+	h := fnv.New32a()
+	h.Write([]byte(symbol))
+	return int(h.Sum32() % uint32(len(m.shards)))
+}
+
+// Subscribe registers fn for the merged stream. It runs on the goroutine of
+// the shard that applied the event, serialized with every other shard, and
+// must not block. The event is valid during the call only (it returns to its
+// pool): copy what you keep. Must be called before Run.
+func (m *ShardedSequencer) Subscribe(fn func(MergedEvent)) {
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Inbox returns the event channel. External workers send events here.
+func (m *ShardedSequencer) Inbox() chan<- event.Event {
+	return m.inbox
+}
+
+// RecoverFromWAL replays the event log of every shard (see Sequencer.RecoverFromWAL).
+// Replayed events are not part of the merged stream; the merged market view
+// starts from the recovered shard states.
+func (m *ShardedSequencer) RecoverFromWAL(ctx context.Context) error {
+	for _, sh := range m.shards {
+		if err := sh.seq.RecoverFromWAL(ctx); err != nil {
+			return err
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, sh := range m.shards {
+		for symbol, state := range sh.seq.GetMarketsSnapshot().Markets {
+			m.markets[symbol] = state
+		}
+	}
+	return nil
+}
+
+// Run starts every shard and routes the inbox to them until ctx is done.
+func (m *ShardedSequencer) Run(ctx context.Context) {
+	slog.Info("Sharded sequencer started", slog.Int("shards", len(m.shards)))
+
+	var wg sync.WaitGroup
+	for _, sh := range m.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sh.seq.Run(ctx)
+		}()
+	}
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-m.inbox:
+			if !m.route(ctx, ev) {
+				return
+			}
+		}
+	}
+}
+
+// route hands ev to the shard of its symbol, or a copy of it to every shard.
+// Blocks like a full Sequencer inbox; returns false once ctx is done.
+func (m *ShardedSequencer) route(ctx context.Context, ev event.Event) bool {
+	if symbol, ok := eventSymbol(ev); ok {
+		return m.send(ctx, m.shards[m.ShardFor(symbol)], ev)
+	}
+	// Copied before any send: each shard stamps its own seq on its event
+	copies := make([]event.Event, len(m.shards))
+	copies[0] = ev
+	for i := 1; i < len(copies); i++ {
+		copies[i] = cloneEvent(ev)
+	}
+	for i, sh := range m.shards {
+		if !m.send(ctx, sh, copies[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *ShardedSequencer) send(ctx context.Context, sh *shard, ev event.Event) bool {
+	select {
+	case sh.seq.inbox <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// publish appends an event applied by sh to the merged stream. Runs on the
+// shard goroutine (sh.seq.mu held).
+func (m *ShardedSequencer) publish(sh *shard, ev event.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mergedSeq++
+	if sh.updated {
+		m.markets[sh.state.Symbol] = sh.state
+		sh.updated = false
+	}
+	me := MergedEvent{Seq: m.mergedSeq, Shard: sh.id, ShardSeq: ev.GetSeq(), Event: ev}
+	for _, fn := range m.subscribers {
+		fn(me)
+	}
+}
+
+// GetMarketsSnapshot returns the merged market view at one point of the
+// merged stream (Seq = merged seq).
+func (m *ShardedSequencer) GetMarketsSnapshot() MarketsSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	markets := make(map[string]domain.MarketState, len(m.markets))
+	for symbol, state := range m.markets {
+		markets[symbol] = state
+	}
+	return MarketsSnapshot{Seq: m.mergedSeq, Markets: markets}
+}
+
+// GetFundingCalendar returns the funding epochs of every shard, soonest first.
+func (m *ShardedSequencer) GetFundingCalendar() []domain.FundingEpoch {
+	var out []domain.FundingEpoch
+	for _, sh := range m.shards {
+		out = append(out, sh.seq.GetFundingCalendar()...)
+	}
+	slices.SortFunc(out, func(a, b domain.FundingEpoch) int {
+		return cmp.Or(cmp.Compare(a.NextTs, b.NextTs), cmp.Compare(a.Exchange, b.Exchange), cmp.Compare(a.Symbol, b.Symbol))
+	})
+	return out
+}
+
+// eventSymbol returns the symbol ev is routed by; false for events that
+// concern every shard.
+func eventSymbol(ev event.Event) (string, bool) {
+	switch e := ev.(type) {
+	case *event.MarketUpdateEvent:
+		return e.Symbol, true
+	case *event.OrderBookUpdateEvent:
+		return e.Symbol, true
+	case *event.TradeEvent:
+		return e.Symbol, true
+	case *event.CandleEvent:
+		return e.Symbol, true
+	case *event.FundingEvent:
+		return e.Symbol, true
+	case *event.SignalEvent:
+		return e.Symbol, true
+	case *event.OrderUpdateEvent, *event.OrderRejectedEvent, *event.ManualOrderEvent:
+		return "", true // No OMS in sharded mode; kept in one shard
+	default:
+		return "", false
+	}
+}
+
+// cloneEvent copies a broadcast event (unpooled types; slices stay shared and
+// are never modified by the sequencer).
+func cloneEvent(ev event.Event) event.Event {
+	switch e := ev.(type) {
+	case *event.ControlEvent:
+		cp := *e
+		return &cp
+	case *event.HaltEvent:
+		cp := *e
+		return &cp
+	case *event.ContextEvent:
+		cp := *e
+		return &cp
+	case *event.SentimentEvent:
+		cp := *e
+		return &cp
+	case *event.NoticeEvent:
+		cp := *e
+		return &cp
+	case *event.TimerEvent:
+		cp := *e
+		return &cp
+	case *event.HeartbeatEvent:
+		cp := *e
+		return &cp
+	case *event.ResyncEvent:
+		cp := *e
+		return &cp
+	default:
+		return ev
+	}
+}
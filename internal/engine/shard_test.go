@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"sync"
+	"testing"
+	"time"
+)
+
+func symbolTick(exchange, symbol string, ts, price int64) *event.MarketUpdateEvent {
+	return &event.MarketUpdateEvent{
+		BaseEvent:   event.BaseEvent{Ts: quant.TimeStamp(ts)},
+		Exchange:    exchange,
+		Symbol:      symbol,
+		PriceMicros: quant.PriceMicros(price),
+	}
+}
+
+func TestShardedSequencer_Routing(t *testing.T) {
+	m := NewShardedSequencer(4, 16, nil)
+	ctx := context.Background()
+
+	symbols := []string{"BTC", "ETH", "XRP", "SOL", "DOGE", "USD/KRW"}
+	for i, symbol := range symbols {
+		for _, exchange := range []string{"UPBIT", "BITGET_SPOT"} {
+			if !m.route(ctx, symbolTick(exchange, symbol, int64(i+1), 100)) {
+				t.Fatal("route refused an event")
+			}
+		}
+	}
+	for i := 0; i < m.Shards(); i++ {
+		shard := m.Shard(i)
+		for len(shard.inbox) > 0 {
+			shard.processEvent(<-shard.inbox)
+		}
+	}
+
+	for _, symbol := range symbols {
+		owner := m.ShardFor(symbol)
+		for i := 0; i < m.Shards(); i++ {
+			_, ok := m.Shard(i).GetMarketState(symbol)
+			if ok != (i == owner) {
+				t.Errorf("%s in shard %d: %v (owner %d)", symbol, i, ok, owner)
+			}
+		}
+	}
+	if snap := m.GetMarketsSnapshot(); snap.Seq != uint64(2*len(symbols)) || len(snap.Markets) != len(symbols) {
+		t.Errorf("merged view: seq %d with %d markets; want %d with %d", snap.Seq, len(snap.Markets), 2*len(symbols), len(symbols))
+	}
+}
+
+func TestShardedSequencer_ControlBroadcast(t *testing.T) {
+	m := NewShardedSequencer(3, 16, nil)
+	ev := &event.ControlEvent{Command: event.CmdPauseStrategy, Target: "sma_cross"}
+	m.route(context.Background(), ev)
+
+	for i := 0; i < m.Shards(); i++ {
+		shard := m.Shard(i)
+		got := <-shard.inbox
+		if i == 0 && got != event.Event(ev) || i > 0 && got == event.Event(ev) {
+			t.Errorf("shard %d: every shard but the first needs its own copy", i)
+		}
+		shard.processEvent(got)
+		if !shard.StrategyPaused() {
+			t.Errorf("shard %d did not apply the broadcast command", i)
+		}
+	}
+}
+
+func TestShardedSequencer_MergedStream(t *testing.T) {
+	const perSymbol = 500
+	symbols := []string{"BTC", "ETH", "XRP", "SOL"}
+	m := NewShardedSequencer(2, 64, nil)
+
+	var mu sync.Mutex
+	var mergedSeq uint64
+	lastShardSeq := make(map[int]uint64)
+	lastTs := make(map[string]quant.TimeStamp)
+	done := make(chan struct{})
+	m.Subscribe(func(me MergedEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if me.Seq != mergedSeq+1 {
+			t.Errorf("merged seq %d after %d", me.Seq, mergedSeq)
+		}
+		mergedSeq = me.Seq
+		if me.ShardSeq != lastShardSeq[me.Shard]+1 {
+			t.Errorf("shard %d: seq %d after %d", me.Shard, me.ShardSeq, lastShardSeq[me.Shard])
+		}
+		lastShardSeq[me.Shard] = me.ShardSeq
+		e := me.Event.(*event.MarketUpdateEvent)
+		if e.Ts <= lastTs[e.Symbol] {
+			t.Errorf("%s: ts %d after %d", e.Symbol, e.Ts, lastTs[e.Symbol])
+		}
+		lastTs[e.Symbol] = e.Ts
+		if me.Seq == perSymbol*uint64(len(symbols)) {
+			close(done)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+	for i := 1; i <= perSymbol; i++ {
+		for _, symbol := range symbols {
+			m.Inbox() <- symbolTick("UPBIT", symbol, int64(i), int64(i))
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("merged stream did not deliver every event")
+	}
+	snap := m.GetMarketsSnapshot()
+	for _, symbol := range symbols {
+		if snap.Markets[symbol].PriceMicros != perSymbol {
+			t.Errorf("%s: merged view price %d; want %d", symbol, snap.Markets[symbol].PriceMicros, perSymbol)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"sync"
+)
+
+// SignalSource is the sequence-validation source name for external signals.
+const SignalSource = "SIGNAL"
+
+// maxRecentSignals bounds the signals kept for GetRecentSignals.
+const maxRecentSignals = 50
+
+// handleSignal records an external signal and passes it to the strategy, which
+// may answer with orders.
+func (s *Sequencer) handleSignal(e *event.SignalEvent) {
+	sig := e.Signal()
+	s.signals = append(s.signals, sig)
+	if len(s.signals) > maxRecentSignals {
+		s.signals = s.signals[len(s.signals)-maxRecentSignals:]
+	}
+
+	if h, ok := s.strategy.(strategy.SignalHandler); ok && !s.strategyPaused {
+		count := h.OnSignal(sig, s.orderBuf[:])
+		for i := 0; i < count; i++ {
+			s.handleStrategyAction(&s.orderBuf[i], e.Ts)
+		}
+	}
+}
+
+// GetRecentSignals returns the most recent external signals, oldest first (thread-safe).
+func (s *Sequencer) GetRecentSignals() []domain.Signal {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]domain.Signal(nil), s.signals...)
+}
+
+// SignalClient submits external signals into the sequencer inbox.
+// It owns the SIGNAL source sequence, so several goroutines may share one client.
+type SignalClient struct {
+	inbox chan<- event.Event
+	mu    sync.Mutex
+	seq   uint64
+	clock quant.Clock
+}
+
+// NewSignalClient creates a client sending to inbox (Sequencer.Inbox or a spillover front).
+func NewSignalClient(inbox chan<- event.Event) *SignalClient {
+	return &SignalClient{inbox: inbox, clock: quant.RealClock{}}
+}
+
+// SetClock replaces the clock that stamps events (default quant.RealClock).
+func (c *SignalClient) SetClock(clock quant.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// Send enqueues sig, stamped with the receive time. Like control events, signals
+// are never dropped: Send blocks until the inbox accepts the event or ctx is done.
+func (c *SignalClient) Send(ctx context.Context, sig domain.Signal) error {
+	c.mu.Lock()
+	defer c.mu.Unlock() // Held across the send so seq order == inbox order
+
+	c.seq++
+	ev := &event.SignalEvent{
+		BaseEvent:   event.BaseEvent{Seq: c.seq, Ts: quant.Stamp(c.clock)},
+		Source:      SignalSource,
+		Origin:      sig.Origin,
+		Name:        sig.Name,
+		Symbol:      sig.Symbol,
+		Action:      sig.Action,
+		PriceMicros: sig.PriceMicros,
+		Note:        sig.Note,
+	}
+
+	select {
+	case c.inbox <- ev:
+		return nil
+	case <-ctx.Done():
+		c.seq-- // Not delivered; keep the source sequence contiguous
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"testing"
+)
+
+type signalStrategy struct {
+	countingStrategy
+	seen []domain.Signal
+}
+
+func (s *signalStrategy) OnSignal(sig domain.Signal, out []domain.Order) int {
+	s.seen = append(s.seen, sig)
+	if sig.Action != domain.SignalBuy {
+		return 0
+	}
+	out[0] = domain.Order{Symbol: sig.Symbol, Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 1}
+	return 1
+}
+
+func TestSequencer_Signal(t *testing.T) {
+	strat := &signalStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+
+	inbox := make(chan event.Event, 2)
+	client := NewSignalClient(inbox)
+	ctx := context.Background()
+	if err := client.Send(ctx, domain.Signal{Origin: "tradingview", Symbol: "BTCUSDT", Action: domain.SignalBuy, PriceMicros: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Send(ctx, domain.Signal{Origin: "script", Symbol: "ETHUSDT", Action: domain.SignalClose}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := (<-inbox).(*event.SignalEvent)
+	if first.Source != SignalSource || first.Seq != 1 || first.Ts == 0 {
+		t.Fatalf("unexpected signal event: %+v", first)
+	}
+	seq.ProcessEventForTest(first)
+	seq.ProcessEventForTest(<-inbox)
+
+	if len(strat.seen) != 2 || strat.seen[0].Origin != "tradingview" || strat.seen[1].Action != domain.SignalClose {
+		t.Fatalf("unexpected signals passed to the strategy: %+v", strat.seen)
+	}
+	if req := <-oms.Requests(); req.Symbol != "BTCUSDT" || req.Side != domain.SideBuy {
+		t.Errorf("expected the strategy's order to be submitted, got %+v", req)
+	}
+	if recent := seq.GetRecentSignals(); len(recent) != 2 || recent[1].Symbol != "ETHUSDT" {
+		t.Errorf("unexpected recent signals: %+v", recent)
+	}
+}
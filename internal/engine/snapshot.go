@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+)
+
+// SetSnapshotInterval calls onDue once everyEvents live events were processed
+// since the last snapshot, so it can request one (ControlClient.Send with
+// event.CmdTriggerSnapshot): the snapshot then sits at a WAL-logged seq that
+// replay verifies. onDue runs on the hotpath and must not block; it is not
+// called again until the requested snapshot was taken, and never on replay.
+// Must be called before Run.
+func (s *Sequencer) SetSnapshotInterval(everyEvents uint64, onDue func()) {
+	s.snapshotEvery = everyEvents
+	s.onSnapshotDue = onDue
+}
+
+// SetFastRecovery lets RecoverFromWAL restore the latest snapshot and replay
+// only the WAL after it, instead of the whole WAL. It is used only without an
+// order manager: orders are rebuilt from strategy decisions on replay, and
+// strategy state is not part of a snapshot. Strategy indicators, the trade
+// guard and the market filter warm up again from the replayed tail. Must be
+// called before RecoverFromWAL.
+func (s *Sequencer) SetFastRecovery(enabled bool) {
+	s.fastRecovery = enabled
+}
+
+// countSnapshotEvent requests a periodic snapshot when one is due (caller
+// holds s.mu, live events only).
+func (s *Sequencer) countSnapshotEvent() {
+	if s.snapshotEvery == 0 || s.onSnapshotDue == nil {
+		return
+	}
+	s.sinceSnapshot++
+	if s.sinceSnapshot < s.snapshotEvery || s.snapshotRequested {
+		return
+	}
+	s.snapshotRequested = true
+	s.onSnapshotDue()
+}
+
+// engineSnapshot is the sequencer state a storage.Snapshot carries beyond
+// markets, balances and positions.
+type engineSnapshot struct {
+	Books          []bookSnapshot              `json:"books,omitempty"`
+	Candles        []domain.Candle             `json:"candles,omitempty"`
+	Contexts       []domain.ContextMetric      `json:"contexts,omitempty"`
+	Sentiments     map[string]domain.Sentiment `json:"sentiments,omitempty"`
+	Notices        []domain.Notice             `json:"notices,omitempty"`
+	NoticeIDs      map[string]int64            `json:"notice_ids,omitempty"`
+	Signals        []domain.Signal             `json:"signals,omitempty"`
+	Funding        []domain.FundingEpoch       `json:"funding,omitempty"`
+	StrategyPaused bool                        `json:"strategy_paused,omitempty"`
+	Halted         bool                        `json:"halted,omitempty"`
+	HaltReason     string                      `json:"halt_reason,omitempty"`
+	Handover       bool                        `json:"handover,omitempty"`
+	Resyncing      map[string]quant.TimeStamp  `json:"resyncing,omitempty"`
+	ReduceOnly     map[string]bool             `json:"reduce_only,omitempty"`
+	RiskLimits     map[string]int64            `json:"risk_limits,omitempty"`
+	PnLPeak        int64                       `json:"pnl_peak,omitempty"`
+}
+
+type bookSnapshot struct {
+	Exchange string             `json:"exchange"`
+	Symbol   string             `json:"symbol"`
+	Ts       quant.TimeStamp    `json:"ts"`
+	Synced   bool               `json:"synced"`
+	Bids     []domain.BookLevel `json:"bids,omitempty"`
+	Asks     []domain.BookLevel `json:"asks,omitempty"`
+}
+
+// captureSnapshot copies the state reflecting every event before seq (caller
+// holds s.mu).
+func (s *Sequencer) captureSnapshot(seq uint64) (*storage.Snapshot, error) {
+	snap := storage.CreateSnapshot(seq, s.markets)
+	snap.StateHash = s.stateHashLocked()
+	snap.Balances = s.balanceBook.Snapshot()
+	snap.Positions = s.positions.Snapshot()
+
+	eng := engineSnapshot{
+		Sentiments:     s.sentiments,
+		Notices:        s.notices,
+		NoticeIDs:      s.noticeIDs,
+		Signals:        s.signals,
+		Funding:        s.fundingEpochs(),
+		StrategyPaused: s.strategyPaused,
+		Halted:         s.halted,
+		HaltReason:     s.haltReason,
+		Handover:       s.handover,
+		Resyncing:      s.resyncing,
+		ReduceOnly:     s.reduceOnly,
+		RiskLimits:     s.riskLimits,
+		PnLPeak:        s.pnlPeak,
+	}
+	for _, b := range s.books {
+		eng.Books = append(eng.Books, bookSnapshot{
+			Exchange: b.Exchange,
+			Symbol:   b.Symbol,
+			Ts:       b.Ts,
+			Synced:   b.Synced,
+			Bids:     b.Bids(0),
+			Asks:     b.Asks(0),
+		})
+	}
+	for _, c := range s.candles {
+		eng.Candles = append(eng.Candles, *c)
+	}
+	for _, m := range s.contexts {
+		eng.Contexts = append(eng.Contexts, m)
+	}
+
+	data, err := json.Marshal(eng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode engine state: %w", err)
+	}
+	snap.Engine = data
+	return snap, nil
+}
+
+// restoreSnapshot replaces the state with snap; the next event to apply is
+// snap.Seq (caller holds s.mu).
+func (s *Sequencer) restoreSnapshot(snap *storage.Snapshot) error {
+	var eng engineSnapshot
+	if err := json.Unmarshal(snap.Engine, &eng); err != nil {
+		return fmt.Errorf("failed to decode engine state: %w", err)
+	}
+
+	clear(s.markets)
+	for symbol, state := range snap.Markets {
+		stateCopy := *state
+		s.markets[symbol] = &stateCopy
+	}
+	s.balanceBook.Restore(snap.Balances)
+	s.positions.Restore(snap.Positions)
+
+	clear(s.books)
+	for _, b := range eng.Books {
+		book := domain.NewOrderBook(b.Exchange, b.Symbol)
+		if b.Synced {
+			book.Apply(true, b.Bids, b.Asks, b.Ts)
+		}
+		book.Ts = b.Ts
+		s.books[bookKey{b.Exchange, b.Symbol}] = book
+	}
+	clear(s.candles)
+	for _, c := range eng.Candles {
+		s.candles[candleKey{c.Exchange, c.Symbol, c.Interval}] = &c
+	}
+	clear(s.contexts)
+	for _, m := range eng.Contexts {
+		s.contexts[contextKey{m.Name, m.Subject}] = m
+	}
+	clear(s.sentiments)
+	maps.Copy(s.sentiments, eng.Sentiments)
+	clear(s.noticeIDs)
+	maps.Copy(s.noticeIDs, eng.NoticeIDs)
+	clear(s.riskLimits)
+	maps.Copy(s.riskLimits, eng.RiskLimits)
+	clear(s.resyncing)
+	maps.Copy(s.resyncing, eng.Resyncing)
+	clear(s.reduceOnly)
+	maps.Copy(s.reduceOnly, eng.ReduceOnly)
+	s.notices = eng.Notices
+	s.signals = eng.Signals
+	clear(s.funding)
+	clear(s.fundingIdx)
+	for _, f := range eng.Funding {
+		s.addFunding(&f)
+	}
+
+	s.strategyPaused = eng.StrategyPaused
+	s.halted = eng.Halted
+	s.haltReason = eng.HaltReason
+	s.handover = eng.Handover
+	s.pnlPeak = eng.PnLPeak
+	s.nextSeq = snap.Seq
+	return nil
+}
+
+// restoreLatestSnapshot restores the newest snapshot for a fast recovery and
+// returns the seq to replay from, or 1 if recovery must replay the whole WAL
+// of lastSeq events. A restore that does not reproduce the snapshot's state
+// hash is undone.
+func (s *Sequencer) restoreLatestSnapshot(lastSeq uint64) uint64 {
+	if !s.fastRecovery || s.snapshots == nil {
+		return 1
+	}
+	skip := func(reason string, attrs ...any) uint64 {
+		slog.Warn("SNAPSHOT_RECOVERY_SKIPPED", append([]any{slog.String("reason", reason)}, attrs...)...)
+		return 1
+	}
+	if s.orders != nil {
+		return skip("order manager enabled")
+	}
+
+	snap, err := s.snapshots.LoadLatest()
+	if err != nil {
+		return skip("snapshot unreadable", slog.Any("error", err))
+	}
+	if snap == nil {
+		return 1
+	}
+	if snap.Engine == nil || snap.StateHash == "" {
+		return skip("snapshot predates restorable snapshots", slog.Uint64("seq", snap.Seq))
+	}
+	if snap.Seq > lastSeq {
+		return skip("snapshot is ahead of the WAL", slog.Uint64("seq", snap.Seq), slog.Uint64("last_seq", lastSeq))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fresh, err := s.captureSnapshot(s.nextSeq)
+	if err != nil {
+		return skip(err.Error())
+	}
+	if err := s.restoreSnapshot(snap); err != nil {
+		s.restoreSnapshot(fresh)
+		return skip(err.Error(), slog.Uint64("seq", snap.Seq))
+	}
+	if got := s.stateHashLocked(); got != snap.StateHash {
+		s.restoreSnapshot(fresh)
+		return skip("restored state hash differs", slog.Uint64("seq", snap.Seq),
+			slog.String("snapshot", snap.StateHash), slog.String("restored", got))
+	}
+
+	slog.Info("State restored from snapshot", slog.Uint64("seq", snap.Seq), slog.String("state_hash", snap.StateHash))
+	return snap.Seq
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+)
+
+// readRecorder remembers where recovery started reading the log.
+type readRecorder struct {
+	storage.EventLog
+	fromSeq uint64
+}
+
+func (r *readRecorder) ReadFrom(ctx context.Context, fromSeq uint64) ([]event.Event, error) {
+	r.fromSeq = fromSeq
+	return r.EventLog.ReadFrom(ctx, fromSeq)
+}
+
+func snapshotFixture(t *testing.T, oms bool) (*storage.EventStore, string, *Sequencer) {
+	t.Helper()
+	store, err := storage.NewEventStore(t.TempDir() + "/snapshot.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	snapDir := t.TempDir()
+	live := NewSequencer(100, store, nil, nil)
+	live.SetSnapshotManager(storage.NewSnapshotManager(snapDir))
+	if oms {
+		live.SetOrderManager(NewOrderManager("t", 4))
+	}
+	live.ProcessEventForTest(tick("UPBIT", 100))
+	live.ProcessEventForTest(&event.OrderBookUpdateEvent{Exchange: "UPBIT", Symbol: "BTC", Snapshot: true,
+		Bids: []domain.BookLevel{{PriceMicros: 99, QtySats: 5}}, Asks: []domain.BookLevel{{PriceMicros: 101, QtySats: 7}}})
+	live.ProcessEventForTest(&event.CandleEvent{Exchange: "UPBIT", Symbol: "BTC", Interval: domain.Candle1m, OpenTime: 60_000_000, CloseMicros: 100})
+	live.ProcessEventForTest(&event.SentimentEvent{Source: "SENTIMENT", Index: domain.SentimentFearGreed, Value: 40})
+	live.ProcessEventForTest(&event.ControlEvent{Command: event.CmdSetRiskLimit, Target: RiskLimitMaxOrderQtySats, Value: 5})
+	live.ProcessEventForTest(&event.ControlEvent{Command: event.CmdTriggerSnapshot}) // seq 6
+	live.ProcessEventForTest(tick("UPBIT", 101))
+	live.ProcessEventForTest(&event.OrderBookUpdateEvent{Exchange: "UPBIT", Symbol: "BTC",
+		Bids: []domain.BookLevel{{PriceMicros: 100, QtySats: 1}}})
+	return store, snapDir, live
+}
+
+func TestSequencer_FastRecoveryReplaysTail(t *testing.T) {
+	store, snapDir, live := snapshotFixture(t, false)
+
+	log := &readRecorder{EventLog: store}
+	recovered := NewSequencer(100, store, nil, nil)
+	recovered.SetEventLog(log)
+	recovered.SetSnapshotManager(storage.NewSnapshotManager(snapDir))
+	recovered.SetFastRecovery(true)
+	if err := recovered.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatalf("fast recovery failed: %v", err)
+	}
+
+	if log.fromSeq != 6 {
+		t.Errorf("expected replay from the snapshot seq 6, got %d", log.fromSeq)
+	}
+	if recovered.StateHash() != live.StateHash() || recovered.GetNextSeq() != 9 {
+		t.Errorf("fast recovery must rebuild the live state (next seq %d)", recovered.GetNextSeq())
+	}
+	book, _ := recovered.GetOrderBook("UPBIT", "BTC")
+	if bid, ok := book.BestBid(); !ok || bid.PriceMicros != 100 {
+		t.Errorf("book levels must be restored under the replayed delta: %+v", book.Bids(0))
+	}
+}
+
+func TestSequencer_FastRecoveryFallsBackWithOMS(t *testing.T) {
+	store, snapDir, live := snapshotFixture(t, true)
+
+	log := &readRecorder{EventLog: store}
+	recovered := NewSequencer(100, store, nil, nil)
+	recovered.SetEventLog(log)
+	recovered.SetSnapshotManager(storage.NewSnapshotManager(snapDir))
+	recovered.SetFastRecovery(true)
+	recovered.SetOrderManager(NewOrderManager("t", 4))
+	if err := recovered.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if log.fromSeq != 1 {
+		t.Errorf("orders come from strategy decisions: expected a full replay, got from seq %d", log.fromSeq)
+	}
+	if recovered.StateHash() != live.StateHash() {
+		t.Error("full replay must rebuild the live state")
+	}
+}
+
+func TestSequencer_SnapshotInterval(t *testing.T) {
+	seq := NewSequencer(10, nil, nil, nil)
+	due := 0
+	seq.SetSnapshotInterval(3, func() { due++ })
+
+	for range 7 {
+		seq.ProcessEventForTest(tick("UPBIT", 100))
+	}
+	if due != 1 {
+		t.Fatalf("expected one request until the snapshot is taken, got %d", due)
+	}
+
+	seq.ProcessEventForTest(&event.ControlEvent{Command: event.CmdTriggerSnapshot})
+	for range 2 {
+		seq.ProcessEventForTest(tick("UPBIT", 100))
+	}
+	if due != 2 {
+		t.Errorf("the count restarts at the snapshot, got %d requests", due)
+	}
+}
+
+func TestSequencer_FastRecoveryUndoesMismatchedRestore(t *testing.T) {
+	store, snapDir, live := snapshotFixture(t, false)
+
+	// A snapshot whose contents do not match its state hash
+	sm := storage.NewSnapshotManager(snapDir)
+	snap, _ := sm.LoadLatest()
+	var eng engineSnapshot
+	if err := json.Unmarshal(snap.Engine, &eng); err != nil {
+		t.Fatal(err)
+	}
+	eng.RiskLimits[RiskLimitMaxOrderQtySats] = 6
+	snap.Engine, _ = json.Marshal(eng)
+	if err := sm.Save(snap); err != nil {
+		t.Fatal(err)
+	}
+
+	log := &readRecorder{EventLog: store}
+	recovered := NewSequencer(100, store, nil, nil)
+	recovered.SetEventLog(log)
+	recovered.SetSnapshotManager(sm)
+	recovered.SetFastRecovery(true)
+	if err := recovered.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if log.fromSeq != 1 || recovered.StateHash() != live.StateHash() {
+		t.Errorf("a mismatched restore must fall back to a full replay, from seq %d", log.fromSeq)
+	}
+}
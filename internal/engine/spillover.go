@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// SpilloverInbox sits in front of the Sequencer inbox and makes ingestion lossless.
+//
+// Workers send into Inbox() as usual. While the sequencer keeps up, events pass
+// straight through. Once its inbox is full, events are appended to a SpillQueue
+// on disk; from then on every new event also goes to disk until the backlog
+// drains, so arrival order is preserved. The cost is latency, not data loss.
+type SpilloverInbox struct {
+	front chan event.Event
+	out   chan<- event.Event
+	queue *storage.SpillQueue
+
+	spilled atomic.Uint64 // Events written to disk (cumulative)
+	dropped atomic.Uint64 // Events lost because the spill file hit its limit
+	depth   atomic.Int64  // Events currently on disk
+}
+
+// NewSpilloverInbox wires a front channel of frontSize to out (the Sequencer inbox).
+func NewSpilloverInbox(frontSize int, out chan<- event.Event, queue *storage.SpillQueue) *SpilloverInbox {
+	return &SpilloverInbox{
+		front: make(chan event.Event, frontSize),
+		out:   out,
+		queue: queue,
+	}
+}
+
+// Inbox returns the channel workers should send to.
+func (p *SpilloverInbox) Inbox() chan<- event.Event {
+	return p.front
+}
+
+// Spilled returns the cumulative number of events routed through disk.
+func (p *SpilloverInbox) Spilled() uint64 { return p.spilled.Load() }
+
+// Dropped returns the number of events lost because the spill file was full.
+func (p *SpilloverInbox) Dropped() uint64 { return p.dropped.Load() }
+
+// Depth returns the number of events currently waiting on disk.
+func (p *SpilloverInbox) Depth() int64 { return p.depth.Load() }
+
+// Run pumps events until ctx is cancelled. Must be run in a single goroutine.
+// The pump owns the SpillQueue and closes it on exit.
+func (p *SpilloverInbox) Run(ctx context.Context) {
+	defer p.queue.Close()
+
+	var head event.Event // Decoded head of the disk queue, pending hand-off
+
+	for {
+		if p.queue.Len() == 0 {
+			// Fast path: pass-through
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-p.front:
+				select {
+				case p.out <- ev:
+				default:
+					slog.Warn("📦 Sequencer inbox full, spilling events to disk")
+					p.spill(ev)
+				}
+			}
+			continue
+		}
+
+		// Backlog path: drain disk head first, keep appending new arrivals behind it
+		if head == nil {
+			ev, err := p.queue.Peek()
+			if err != nil {
+				panic(fmt.Sprintf("SPILL_QUEUE_CORRUPTED: %v", err))
+			}
+			head = ev
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case p.out <- head:
+			head = nil
+			if err := p.queue.Pop(); err != nil {
+				panic(fmt.Sprintf("SPILL_QUEUE_CORRUPTED: %v", err))
+			}
+			p.depth.Add(-1)
+			if p.queue.Len() == 0 {
+				slog.Info("📦 Spill backlog drained", slog.Uint64("total_spilled", p.spilled.Load()))
+			}
+		case ev := <-p.front:
+			p.spill(ev)
+		}
+	}
+}
+
+func (p *SpilloverInbox) spill(ev event.Event) {
+	if err := p.queue.Push(ev); err != nil {
+		// Disk budget exhausted: fall back to the original drop semantics
+		p.dropped.Add(1)
+	} else {
+		p.spilled.Add(1)
+		p.depth.Add(1)
+	}
+	event.Release(ev)
+}
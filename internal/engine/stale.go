@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"log/slog"
+	"time"
+)
+
+// Reasons the sweeper cancels a resting order (ManagedOrder.Stale).
+const (
+	StaleTTL   = "TTL"   // Rested longer than its TTL
+	StaleDrift = "DRIFT" // The market moved too far from its price
+)
+
+// restingOrder is a LIMIT order at a venue, watched by the sweeper.
+type restingOrder struct {
+	mo    *ManagedOrder
+	since quant.TimeStamp // When it was sent
+}
+
+// SetStalePolicy makes SweepStale cancel resting LIMIT orders sent more than
+// ttl ago (an order's TTLSec overrides it; 0 = no default) or priced more than
+// driftBps away from the last price of their venue (0 = off).
+func (m *OrderManager) SetStalePolicy(ttl time.Duration, driftBps int64) {
+	m.staleTTL = ttl.Microseconds()
+	m.staleDriftBps = driftBps
+}
+
+// SweepStale requests the cancel of resting orders that outlived their TTL at
+// ts or, with e set, drifted too far from e's price. Each is swept once; the
+// venue's CANCELED (or fill) update closes it. Time and prices come from
+// events, so a replay sweeps the same orders (without sending cancels).
+func (m *OrderManager) SweepStale(ts quant.TimeStamp, e *event.MarketUpdateEvent, seq uint64, replay bool) {
+	if len(m.resting) == 0 {
+		return
+	}
+	kept := m.resting[:0]
+	for _, r := range m.resting {
+		if !r.mo.IsOpen() {
+			continue
+		}
+		kept = append(kept, r)
+		if r.mo.Stale != "" {
+			continue
+		}
+		if reason := m.staleReason(r, ts, e); reason != "" {
+			r.mo.Stale = reason
+			if !replay {
+				slog.Info("STALE_ORDER", slog.String("order_id", r.mo.ID), slog.String("strategy", r.mo.Strategy), slog.String("symbol", r.mo.Symbol), slog.String("reason", reason))
+			}
+			m.Cancel(r.mo, seq, ts, replay)
+		}
+	}
+	clear(m.resting[len(kept):])
+	m.resting = kept
+}
+
+// staleReason returns why r is stale at ts (given market update e, may be
+// nil), or "" if it is not.
+func (m *OrderManager) staleReason(r restingOrder, ts quant.TimeStamp, e *event.MarketUpdateEvent) string {
+	ttl := m.staleTTL
+	if r.mo.TTLSec > 0 {
+		ttl = r.mo.TTLSec * int64(time.Second/time.Microsecond)
+	}
+	if ttl > 0 && int64(ts-r.since) >= ttl {
+		return StaleTTL
+	}
+	if m.staleDriftBps == 0 || e == nil || e.Symbol != r.mo.Symbol || r.mo.PriceMicros <= 0 {
+		return ""
+	}
+	// Default-venue orders are priced by the default trigger feed
+	if r.mo.Exchange != e.Exchange && (r.mo.Exchange != "" || (m.triggerExchange != "" && m.triggerExchange != e.Exchange)) {
+		return ""
+	}
+	diff := int64(e.PriceMicros) - r.mo.PriceMicros
+	if diff < 0 {
+		diff = -diff
+	}
+	if safe.SafeMulDiv(diff, 10_000, r.mo.PriceMicros) >= m.staleDriftBps {
+		return StaleDrift
+	}
+	return ""
+}
+
+// sweepStale runs the OMS sweeper (caller holds s.mu). Cancels go out even
+// while order dispatch is stopped: they only take risk off.
+func (s *Sequencer) sweepStale(ts quant.TimeStamp, e *event.MarketUpdateEvent) {
+	if s.orders != nil {
+		s.orders.SweepStale(ts, e, s.nextSeq, s.replaying)
+	}
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"testing"
+	"time"
+)
+
+func TestOrderManager_SweepStale(t *testing.T) {
+	m := NewOrderManager("t", 8)
+	m.SetTriggerExchange("BITGET")
+	m.SetStalePolicy(10*time.Second, 100)
+	submit := func(o domain.Order) *ManagedOrder {
+		mo, ok := m.Submit(o, 1, 0, false)
+		if !ok {
+			t.Fatalf("order refused: %+v", mo.Refusal)
+		}
+		<-m.Requests()
+		return mo
+	}
+	aged := submit(domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 100_000_000, QtySats: 1})
+	quick := submit(domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 100_000_000, QtySats: 1, TTLSec: 2})
+	upbit := submit(domain.Order{Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeLimit, PriceMicros: 100_000_000, QtySats: 1, Exchange: "UPBIT", TTLSec: 3600})
+	submit(domain.Order{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 1}) // Never rests
+
+	cancels := func() []string {
+		var ids []string
+		for len(m.Cancels()) > 0 {
+			req := <-m.Cancels()
+			if !req.Cancel {
+				t.Errorf("unexpected request: %+v", req)
+			}
+			ids = append(ids, req.OrderID)
+		}
+		return ids
+	}
+
+	// 2s: only the order with its own short TTL expires
+	m.SweepStale(2_000_000, nil, 2, false)
+	if got := cancels(); len(got) != 1 || got[0] != quick.ID || quick.Stale != StaleTTL {
+		t.Fatalf("cancels at 2s = %v (stale %q); want %s", got, quick.Stale, quick.ID)
+	}
+	m.SweepStale(3_000_000, nil, 3, false)
+	if got := cancels(); len(got) != 0 {
+		t.Errorf("a swept order must not be canceled twice: %v", got)
+	}
+
+	// A 2% move on UPBIT drifts the UPBIT order only; the default-venue order follows BITGET
+	move := &event.MarketUpdateEvent{Symbol: "BTC", Exchange: "UPBIT", PriceMicros: 102_000_000}
+	m.SweepStale(4_000_000, move, 4, false)
+	if got := cancels(); len(got) != 1 || got[0] != upbit.ID || upbit.Stale != StaleDrift {
+		t.Fatalf("cancels on UPBIT drift = %v; want %s", got, upbit.ID)
+	}
+	move.Exchange = "BITGET"
+	move.PriceMicros = 100_500_000 // 50bp: within the threshold
+	m.SweepStale(5_000_000, move, 5, false)
+	if got := cancels(); len(got) != 0 {
+		t.Errorf("a small drift must not sweep: %v", got)
+	}
+
+	// Filled orders leave the sweeper; replay sweeps without sending cancels
+	m.Apply(update(quick.ID, domain.OrderStatusCanceled, 0))
+	m.SweepStale(10_000_000, nil, 6, true)
+	if got := cancels(); len(got) != 0 || aged.Stale != StaleTTL {
+		t.Errorf("replay sweep: cancels %v, stale %q; want none and TTL", got, aged.Stale)
+	}
+	if len(m.resting) != 2 {
+		t.Errorf("resting = %d; want the 2 open limit orders", len(m.resting))
+	}
+}
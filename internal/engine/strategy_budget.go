@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"log/slog"
+	"time"
+)
+
+// StrategyBudgetRecorder receives strategy time budget measurements (metrics).
+type StrategyBudgetRecorder interface {
+	RecordStrategyOverrun(strategy string, elapsed time.Duration)
+	SetStrategySlow(strategy string, slow bool)
+}
+
+// StrategyBudget limits the wall time the strategy may spend in one
+// OnMarketUpdate, so a slow strategy cannot stall the single-threaded hotpath.
+//
+// Each overrun adds a strike and each in-budget call removes one; at maxStrikes
+// the strategy is flagged (logged once, metrics) and, with auto-disable, paused
+// through onDisable. The pause must come back as a CmdPauseStrategy ControlEvent
+// so it is WAL-logged: replay never measures time and only re-applies the command.
+// Hotpath only (not goroutine-safe).
+type StrategyBudget struct {
+	name       string
+	perEvent   time.Duration
+	maxStrikes int
+	rec        StrategyBudgetRecorder
+	onDisable  func(strategy string) // nil = flag only; must not block (runs on the hotpath)
+
+	strikes  int
+	flagged  bool
+	overruns uint64
+}
+
+// NewStrategyBudget creates a budget of perEvent per call for the strategy called name.
+// rec and onDisable may be nil.
+func NewStrategyBudget(name string, perEvent time.Duration, maxStrikes int, rec StrategyBudgetRecorder, onDisable func(strategy string)) *StrategyBudget {
+	return &StrategyBudget{
+		name:       name,
+		perEvent:   perEvent,
+		maxStrikes: max(maxStrikes, 1),
+		rec:        rec,
+		onDisable:  onDisable,
+	}
+}
+
+// Observe records one OnMarketUpdate call that took elapsed.
+func (b *StrategyBudget) Observe(elapsed time.Duration) {
+	if elapsed <= b.perEvent {
+		if b.strikes > 0 {
+			b.strikes--
+		}
+		return
+	}
+
+	b.overruns++
+	b.strikes++
+	if b.rec != nil {
+		b.rec.RecordStrategyOverrun(b.name, elapsed)
+	}
+	if b.flagged || b.strikes < b.maxStrikes {
+		return
+	}
+
+	b.flagged = true
+	slog.Warn("STRATEGY_BUDGET_EXCEEDED",
+		slog.String("strategy", b.name),
+		slog.Duration("elapsed", elapsed),
+		slog.Duration("budget", b.perEvent),
+		slog.Uint64("overruns", b.overruns),
+		slog.Bool("auto_disable", b.onDisable != nil))
+	if b.rec != nil {
+		b.rec.SetStrategySlow(b.name, true)
+	}
+	if b.onDisable != nil {
+		b.onDisable(b.name)
+	}
+}
+
+// Reset clears the strikes and the flag (the strategy was resumed).
+func (b *StrategyBudget) Reset() {
+	b.strikes = 0
+	if b.flagged && b.rec != nil {
+		b.rec.SetStrategySlow(b.name, false)
+	}
+	b.flagged = false
+}
+
+// Flagged reports whether the strategy is currently over budget.
+func (b *StrategyBudget) Flagged() bool {
+	return b.flagged
+}
+
+// Overruns returns the number of calls that exceeded the budget so far.
+func (b *StrategyBudget) Overruns() uint64 {
+	return b.overruns
+}
+
+// SetStrategyBudget installs a per-event time budget for the strategy. Must be called before Run.
+func (s *Sequencer) SetStrategyBudget(b *StrategyBudget) {
+	s.strategyBudget = b
+}
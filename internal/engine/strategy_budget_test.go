@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"testing"
+	"time"
+)
+
+type budgetRecorder struct {
+	overruns int
+	slow     bool
+}
+
+func (r *budgetRecorder) RecordStrategyOverrun(string, time.Duration) { r.overruns++ }
+func (r *budgetRecorder) SetStrategySlow(_ string, slow bool)         { r.slow = slow }
+
+func TestStrategyBudget_StrikesAndReset(t *testing.T) {
+	rec := &budgetRecorder{}
+	var disabled []string
+	b := NewStrategyBudget("slow", time.Millisecond, 3, rec, func(name string) { disabled = append(disabled, name) })
+
+	// Occasional overruns are absorbed by in-budget calls
+	for i := 0; i < 10; i++ {
+		b.Observe(2 * time.Millisecond)
+		b.Observe(0)
+	}
+	if b.Flagged() || len(disabled) != 0 {
+		t.Fatal("alternating overruns must not trip the budget")
+	}
+
+	for i := 0; i < 5; i++ {
+		b.Observe(2 * time.Millisecond)
+	}
+	if !b.Flagged() || !rec.slow {
+		t.Fatal("repeated overruns must flag the strategy")
+	}
+	if len(disabled) != 1 || disabled[0] != "slow" {
+		t.Errorf("onDisable must fire exactly once, got %v", disabled)
+	}
+	if b.Overruns() != 15 || rec.overruns != 15 {
+		t.Errorf("overruns = %d (recorded %d), want 15", b.Overruns(), rec.overruns)
+	}
+
+	b.Reset()
+	if b.Flagged() || rec.slow {
+		t.Error("reset must clear the flag")
+	}
+}
+
+// sleepyStrategy takes longer than any sane budget.
+type sleepyStrategy struct{ calls int }
+
+func (s *sleepyStrategy) OnMarketUpdate(domain.MarketState, []domain.Order) int {
+	s.calls++
+	time.Sleep(2 * time.Millisecond)
+	return 0
+}
+
+func (s *sleepyStrategy) OnOrderUpdate(domain.Order) {}
+
+func TestSequencer_StrategyBudgetAutoPause(t *testing.T) {
+	strat := &sleepyStrategy{}
+	seq := NewSequencer(10, nil, strat, nil)
+
+	// onDisable runs on the hotpath; feed the pause back as a control event like main does
+	var pauses []*event.ControlEvent
+	seq.SetStrategyBudget(NewStrategyBudget("sleepy", time.Microsecond, 2, nil, func(name string) {
+		pauses = append(pauses, &event.ControlEvent{Command: event.CmdPauseStrategy, Target: name})
+	}))
+
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC"})
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC"})
+	if len(pauses) != 1 {
+		t.Fatalf("expected one pause request, got %d", len(pauses))
+	}
+	seq.ProcessEventForTest(pauses[0])
+	if !seq.StrategyPaused() {
+		t.Fatal("strategy should be paused")
+	}
+
+	seq.ProcessEventForTest(&event.MarketUpdateEvent{Symbol: "BTC"})
+	if strat.calls != 2 {
+		t.Errorf("paused strategy was called: %d calls", strat.calls)
+	}
+
+	// Replay never measures: re-applying WAL events cannot trip the budget again
+	replayed := NewSequencer(10, nil, &sleepyStrategy{}, nil)
+	replayed.SetStrategyBudget(NewStrategyBudget("sleepy", time.Microsecond, 1, nil, func(string) {
+		t.Error("replay must not measure strategy time")
+	}))
+	for i := uint64(1); i <= 3; i++ {
+		replayed.ReplayEvent(&event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: i}, Symbol: "BTC"})
+	}
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"slices"
+	"time"
+)
+
+// TimerSource is the sequence-validation source name for timer ticks.
+const TimerSource = "TIMER"
+
+// handleTimer escalates timed-out maker legs, sweeps expired orders and passes
+// a timer tick to the strategy, which may answer with orders.
+func (s *Sequencer) handleTimer(e *event.TimerEvent) {
+	s.expireMakers(e.Ts)
+	s.sweepStale(e.Ts, nil)
+	if h, ok := s.strategy.(strategy.TimerHandler); ok && !s.strategyPaused {
+		count := h.OnTimer(e.Timer(), s.orderBuf[:])
+		for i := 0; i < count; i++ {
+			s.handleStrategyAction(&s.orderBuf[i], e.Ts)
+		}
+	}
+}
+
+// TimerSpec is one named interval timer.
+type TimerSpec struct {
+	Name     string
+	Interval time.Duration
+}
+
+// TimerService injects a TimerEvent into the sequencer inbox at every boundary
+// of each timer's interval, aligned to UTC (a 1m timer ticks on the minute).
+// Ticks are stamped with their boundary and go through the WAL like any other
+// event, so time-based logic replays identically. Ticks missed while the
+// process was stalled (host sleep) are not made up: one tick at the latest
+// boundary stands for them, and its Tick shows the jump. It owns the TIMER
+// source sequence.
+type TimerService struct {
+	inbox  chan<- event.Event
+	timers []TimerSpec
+	seq    uint64
+	clock  quant.Clock
+}
+
+// NewTimerService creates a service sending timers' ticks to inbox (Sequencer.Inbox
+// or a spillover front).
+func NewTimerService(inbox chan<- event.Event, timers []TimerSpec) *TimerService {
+	return &TimerService{inbox: inbox, timers: timers, clock: quant.RealClock{}}
+}
+
+// SetClock replaces the clock the service reads (default quant.RealClock).
+// Must be called before Run.
+func (t *TimerService) SetClock(c quant.Clock) {
+	t.clock = c
+}
+
+// Run sends ticks until ctx is done. Like control events, ticks are never
+// dropped: a full inbox delays them.
+func (t *TimerService) Run(ctx context.Context) {
+	if len(t.timers) == 0 {
+		return
+	}
+	next := make([]time.Time, len(t.timers))
+	now := t.clock.Now()
+	for i, tm := range t.timers {
+		next[i] = nextBoundary(now, tm.Interval)
+	}
+
+	for {
+		wait := time.NewTimer(slices.MinFunc(next, time.Time.Compare).Sub(t.clock.Now()))
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return
+		case <-wait.C:
+		}
+		if !t.fire(ctx, next) {
+			return
+		}
+	}
+}
+
+// fire sends the tick of every timer due by now, in configuration order, and
+// moves it to its next boundary. A timer that fell behind ticks once, at the
+// latest boundary passed. Returns false once ctx is done.
+func (t *TimerService) fire(ctx context.Context, next []time.Time) bool {
+	now := t.clock.Now()
+	for i, tm := range t.timers {
+		if next[i].After(now) {
+			continue
+		}
+		t.seq++
+		us := tm.Interval.Microseconds()
+		ts := now.UnixMicro() / us * us
+		ev := &event.TimerEvent{
+			BaseEvent:  event.BaseEvent{Seq: t.seq, Ts: quant.TimeStamp(ts)},
+			Source:     TimerSource,
+			Name:       tm.Name,
+			IntervalMs: tm.Interval.Milliseconds(),
+			Tick:       uint64(ts / us),
+		}
+		select {
+		case t.inbox <- ev:
+		case <-ctx.Done():
+			t.seq-- // Not delivered; keep the source sequence contiguous
+			return false
+		}
+		next[i] = nextBoundary(now, tm.Interval)
+	}
+	return true
+}
+
+// nextBoundary returns the first multiple of interval (since the Unix epoch)
+// after now.
+func nextBoundary(now time.Time, interval time.Duration) time.Time {
+	us := interval.Microseconds()
+	return time.UnixMicro((now.UnixMicro()/us + 1) * us)
+}
@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type timerStrategy struct {
+	countingStrategy
+	seen []domain.Timer
+}
+
+func (s *timerStrategy) OnTimer(timer domain.Timer, out []domain.Order) int {
+	s.seen = append(s.seen, timer)
+	if timer.Name != "1h" {
+		return 0
+	}
+	out[0] = domain.Order{Symbol: "BTCUSDT", Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 1}
+	return 1
+}
+
+func TestTimerService_Fire(t *testing.T) {
+	inbox := make(chan event.Event, 8)
+	svc := NewTimerService(inbox, []TimerSpec{{Name: "1m", Interval: time.Minute}, {Name: "1h", Interval: time.Hour}})
+	start := time.Date(2026, 1, 2, 9, 59, 30, 0, time.UTC)
+	next := []time.Time{nextBoundary(start, time.Minute), nextBoundary(start, time.Hour)}
+	if hour := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC); !next[0].Equal(hour) || !next[1].Equal(hour) {
+		t.Fatalf("next boundaries %v; want both at %v", next, hour)
+	}
+
+	// Both due on the hour; then the host stalls past 10:03
+	clock := quant.NewSimClock(quant.TimeStamp(next[0].UnixMicro()))
+	svc.SetClock(clock)
+	svc.fire(context.Background(), next)
+	clock.Advance(3*time.Minute + 10*time.Second)
+	svc.fire(context.Background(), next)
+
+	var got []*event.TimerEvent
+	for len(inbox) > 0 {
+		got = append(got, (<-inbox).(*event.TimerEvent))
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d ticks; want 3", len(got))
+	}
+	hour := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	want := []struct {
+		name string
+		ts   time.Time
+	}{{"1m", hour}, {"1h", hour}, {"1m", hour.Add(3 * time.Minute)}}
+	for i, w := range want {
+		e := got[i]
+		if e.Seq != uint64(i+1) || e.Source != TimerSource || e.Name != w.name || e.Ts != quant.TimeStamp(w.ts.UnixMicro()) {
+			t.Errorf("tick %d: %+v; want %s at %v", i, e, w.name, w.ts)
+		}
+	}
+	if got[0].Tick+3 != got[2].Tick {
+		t.Errorf("1m ticks %d then %d; want a jump of 3", got[0].Tick, got[2].Tick)
+	}
+	if !next[0].Equal(hour.Add(4 * time.Minute)) {
+		t.Errorf("missed ticks must not be made up: next 1m tick at %v", next[0])
+	}
+}
+
+func TestSequencer_TimerReplay(t *testing.T) {
+	ticks := func() []event.Event {
+		return []event.Event{
+			&event.TimerEvent{BaseEvent: event.BaseEvent{Seq: 1, Ts: 60_000_000}, Source: TimerSource, Name: "1m", IntervalMs: 60_000, Tick: 1},
+			&event.TimerEvent{BaseEvent: event.BaseEvent{Seq: 2, Ts: 3_600_000_000}, Source: TimerSource, Name: "1h", IntervalMs: 3_600_000, Tick: 1},
+		}
+	}
+
+	store, err := storage.NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	live := &timerStrategy{}
+	seq := NewSequencer(10, store, live, nil)
+	oms := NewOrderManager("t", 4)
+	seq.SetOrderManager(oms)
+	for _, ev := range ticks() {
+		seq.ProcessEventForTest(ev)
+	}
+	if len(live.seen) != 2 || live.seen[1] != (domain.Timer{Name: "1h", IntervalMs: 3_600_000, Tick: 1, Ts: 3_600_000_000}) {
+		t.Fatalf("unexpected ticks passed to the strategy: %+v", live.seen)
+	}
+	if req := <-oms.Requests(); req.Symbol != "BTCUSDT" {
+		t.Errorf("expected the strategy's order to be submitted, got %+v", req)
+	}
+
+	replayed := &timerStrategy{}
+	recovered := NewSequencer(10, store, replayed, nil)
+	if err := recovered.RecoverFromWAL(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.seen) != len(live.seen) || replayed.seen[0] != live.seen[0] || replayed.seen[1] != live.seen[1] {
+		t.Errorf("replay saw %+v; live saw %+v", replayed.seen, live.seen)
+	}
+}
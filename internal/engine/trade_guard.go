@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+)
+
+// microsPerDay is one UTC day in quant.TimeStamp units.
+const microsPerDay = 86_400_000_000
+
+// TradeGuard limits how often the strategy may enter a position, per symbol.
+// Entries are BUY orders; exits (SELL) are never throttled so a position can always be closed.
+//
+// Time comes from the triggering event timestamp, not the wall clock, so the
+// guard makes identical decisions during WAL replay. Hotpath only (not goroutine-safe).
+type TradeGuard struct {
+	cooldown   quant.TimeStamp // Minimum time between entries on one symbol (0 = off)
+	maxPerDay  int             // Maximum entries per symbol per UTC day (0 = unlimited)
+	lastEntry  map[string]quant.TimeStamp
+	dayEntries map[string]int
+	day        int64 // UTC day index of dayEntries
+	rejected   uint64
+}
+
+// NewTradeGuard creates a guard. cooldownSec and maxPerDay of 0 disable the respective limit.
+func NewTradeGuard(cooldownSec int64, maxPerDay int) *TradeGuard {
+	return &TradeGuard{
+		cooldown:   quant.TimeStamp(cooldownSec * 1_000_000),
+		maxPerDay:  maxPerDay,
+		lastEntry:  make(map[string]quant.TimeStamp),
+		dayEntries: make(map[string]int),
+	}
+}
+
+// Allow reports whether order may be dispatched at ts and, if so, records the entry.
+func (g *TradeGuard) Allow(order *domain.Order, ts quant.TimeStamp) bool {
+	if order.Side != domain.SideBuy {
+		return true
+	}
+
+	if day := int64(ts) / microsPerDay; day != g.day {
+		g.day = day
+		clear(g.dayEntries)
+	}
+
+	if last, ok := g.lastEntry[order.Symbol]; ok && g.cooldown > 0 && ts-last < g.cooldown {
+		g.rejected++
+		return false
+	}
+	if g.maxPerDay > 0 && g.dayEntries[order.Symbol] >= g.maxPerDay {
+		g.rejected++
+		return false
+	}
+
+	g.lastEntry[order.Symbol] = ts
+	g.dayEntries[order.Symbol]++
+	return true
+}
+
+// Rejected returns the number of entries blocked so far.
+func (g *TradeGuard) Rejected() uint64 {
+	return g.rejected
+}
+
+// SetTradeGuard installs per-strategy frequency limits. Must be called before Run.
+func (s *Sequencer) SetTradeGuard(g *TradeGuard) {
+	s.tradeGuard = g
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+func TestTradeGuard_CooldownAndDailyLimit(t *testing.T) {
+	g := NewTradeGuard(60, 2)
+	buy := func(symbol string) *domain.Order { return &domain.Order{Symbol: symbol, Side: domain.SideBuy} }
+	sec := func(s int64) quant.TimeStamp { return quant.TimeStamp(s * 1_000_000) }
+
+	if !g.Allow(buy("BTC"), sec(0)) {
+		t.Fatal("first entry must pass")
+	}
+	if g.Allow(buy("BTC"), sec(30)) {
+		t.Error("entry within cooldown must be rejected")
+	}
+	if !g.Allow(buy("ETH"), sec(30)) {
+		t.Error("cooldown is per symbol")
+	}
+	if !g.Allow(&domain.Order{Symbol: "BTC", Side: domain.SideSell}, sec(31)) {
+		t.Error("exits are never throttled")
+	}
+	if !g.Allow(buy("BTC"), sec(60)) {
+		t.Error("entry after cooldown must pass")
+	}
+	if g.Allow(buy("BTC"), sec(3600)) {
+		t.Error("third entry of the day must be rejected")
+	}
+	if g.Rejected() != 2 {
+		t.Errorf("expected 2 rejections, got %d", g.Rejected())
+	}
+
+	// Next UTC day resets the daily count
+	if !g.Allow(buy("BTC"), sec(86_400)) {
+		t.Error("entry on the next day must pass")
+	}
+}
+
+// alwaysBuyStrategy signals an entry on every update.
+type alwaysBuyStrategy struct{}
+
+func (alwaysBuyStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	out[0] = domain.Order{Symbol: state.Symbol, Side: domain.SideBuy, QtySats: 1}
+	return 1
+}
+
+func (alwaysBuyStrategy) OnOrderUpdate(domain.Order) {}
+
+func TestSequencer_TradeGuardBlocksChurn(t *testing.T) {
+	seq := NewSequencer(10, nil, alwaysBuyStrategy{}, nil)
+	guard := NewTradeGuard(0, 1)
+	seq.SetTradeGuard(guard)
+
+	for i := int64(0); i < 3; i++ {
+		seq.ProcessEventForTest(&event.MarketUpdateEvent{
+			BaseEvent: event.BaseEvent{Ts: quant.TimeStamp(i * 1_000_000)},
+			Symbol:    "BTC",
+		})
+	}
+	if guard.Rejected() != 2 {
+		t.Errorf("expected 2 entries blocked by the daily limit, got %d", guard.Rejected())
+	}
+}
+
+func TestSequencer_OrderObserver(t *testing.T) {
+	seq := NewSequencer(10, nil, alwaysBuyStrategy{}, nil)
+	seq.SetTradeGuard(NewTradeGuard(0, 1))
+	var seen []uint64
+	seq.SetOrderObserver(func(order domain.Order, s uint64, ts quant.TimeStamp) {
+		if order.Side != domain.SideBuy || ts != 0 {
+			t.Errorf("unexpected order %+v at %d", order, ts)
+		}
+		seen = append(seen, s)
+	})
+
+	for i := int64(0); i < 3; i++ {
+		seq.ProcessEventForTest(&event.MarketUpdateEvent{
+			BaseEvent: event.BaseEvent{Ts: quant.TimeStamp(i * 1_000_000)},
+			Symbol:    "BTC",
+		})
+	}
+	// Entries the guard blocked are not signals
+	if len(seen) != 1 || seen[0] != 1 {
+		t.Errorf("expected the first event's order only, got seqs %v", seen)
+	}
+}
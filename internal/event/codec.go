@@ -0,0 +1,550 @@
+package event
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Payload codecs of persisted events (WAL, events.db, spill file).
+const (
+	CodecBinary = "binary" // Compact varint layout (default)
+	CodecJSON   = "json"   // Human-readable; every record before the binary codec
+)
+
+// The first byte of a binary payload is the layout version. A JSON payload
+// always starts with '{', so both can live in one log. A layout change gets a
+// new version byte and Decode keeps reading the old ones.
+const (
+	binaryV1 byte = 1
+	binaryV2 byte = 2 // BaseEvent gains Incarnation
+	binaryV3 byte = 3 // ControlEvent gains Report
+)
+
+// ErrCorruptPayload is returned for a binary payload that does not match its layout.
+var ErrCorruptPayload = errors.New("corrupt event payload")
+
+// Encode serializes ev for persistence with codec ("" = CodecBinary). Types
+// without a binary layout are stored as JSON, which Decode reads as well.
+func Encode(codec string, ev Event) ([]byte, error) {
+	switch codec {
+	case "", CodecBinary:
+		if b, ok := AppendBinary(nil, ev); ok {
+			return b, nil
+		}
+		return json.Marshal(ev)
+	case CodecJSON:
+		return json.Marshal(ev)
+	default:
+		return nil, fmt.Errorf("unknown event codec %q", codec)
+	}
+}
+
+// ValidCodec reports whether codec names an event codec ("" = CodecBinary).
+func ValidCodec(codec string) bool {
+	return codec == "" || codec == CodecBinary || codec == CodecJSON
+}
+
+// PayloadCodec reports which codec wrote payload.
+func PayloadCodec(payload []byte) string {
+	if len(payload) > 0 && payload[0] >= binaryV1 && payload[0] <= binaryV3 {
+		return CodecBinary
+	}
+	return CodecJSON
+}
+
+// Decode restores a persisted event of type t from a payload in either codec.
+// Returns (nil, nil) for unknown types so callers can skip them.
+func Decode(t Type, payload []byte) (Event, error) {
+	if len(payload) == 0 {
+		return nil, ErrCorruptPayload
+	}
+	switch payload[0] {
+	case binaryV1, binaryV2, binaryV3:
+		return decodeBinary(t, payload[0], payload[1:])
+	case '{':
+		return decodeJSON(t, payload)
+	default:
+		return nil, fmt.Errorf("%w: unknown payload version %d", ErrCorruptPayload, payload[0])
+	}
+}
+
+// AppendBinary appends the binary payload of ev to dst. It returns false for
+// a type without a binary layout.
+func AppendBinary(dst []byte, ev Event) ([]byte, bool) {
+	w := binWriter{buf: append(dst, binaryV3)}
+	switch e := ev.(type) {
+	case *MarketUpdateEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Symbol)
+		w.int(int64(e.PriceMicros))
+		w.int(int64(e.QtySats))
+		w.str(e.Exchange)
+	case *OrderUpdateEvent:
+		w.base(e.BaseEvent)
+		w.str(e.OrderID)
+		w.str(e.Status)
+		w.int(int64(e.PriceMicros))
+		w.int(int64(e.AccumulatedQtySats))
+	case *ControlEvent:
+		w.base(e.BaseEvent)
+		w.uint(uint64(e.Command))
+		w.str(e.Target)
+		w.int(e.Value)
+		w.str(e.Reason)
+		w.str(e.Report)
+	case *OrderRejectedEvent:
+		w.base(e.BaseEvent)
+		w.str(e.OrderID)
+		w.str(e.Symbol)
+		w.str(e.Side)
+		w.int(int64(e.PriceMicros))
+		w.int(int64(e.QtySats))
+		w.str(e.Exchange)
+		w.str(string(e.Reason))
+		w.str(e.Code)
+		w.str(e.Message)
+	case *OrderBookUpdateEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Symbol)
+		w.str(e.Exchange)
+		w.bool(e.Snapshot)
+		w.levels(e.Bids)
+		w.levels(e.Asks)
+	case *TradeEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Symbol)
+		w.str(e.Exchange)
+		w.str(e.Side)
+		w.int(int64(e.PriceMicros))
+		w.int(int64(e.QtySats))
+	case *CandleEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Exchange)
+		w.str(e.Symbol)
+		w.str(e.Interval)
+		w.int(int64(e.OpenTime))
+		w.int(int64(e.OpenMicros))
+		w.int(int64(e.HighMicros))
+		w.int(int64(e.LowMicros))
+		w.int(int64(e.CloseMicros))
+		w.int(int64(e.VolumeSats))
+	case *ContextEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Source)
+		w.str(e.Metric)
+		w.str(e.Subject)
+		w.int(e.ValueMicros)
+	case *SentimentEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Source)
+		w.str(e.Index)
+		w.int(e.Value)
+		w.str(e.Label)
+	case *NoticeEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Source)
+		w.str(e.Exchange)
+		w.int(e.NoticeID)
+		w.str(e.Kind)
+		w.str(e.Title)
+		w.uint(uint64(len(e.Symbols)))
+		for _, s := range e.Symbols {
+			w.str(s)
+		}
+	case *SignalEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Source)
+		w.str(e.Origin)
+		w.str(e.Name)
+		w.str(e.Symbol)
+		w.str(e.Action)
+		w.int(int64(e.PriceMicros))
+		w.str(e.Note)
+	case *FundingEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Exchange)
+		w.str(e.Symbol)
+		w.int(int64(e.NextFundingTs))
+		w.int(e.RateMicros)
+	case *TimerEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Source)
+		w.str(e.Name)
+		w.int(e.IntervalMs)
+		w.uint(e.Tick)
+	case *HeartbeatEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Source)
+		w.str(e.Instance)
+		w.int(e.IntervalMs)
+		w.uint(e.Beat)
+	case *HaltEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Reason)
+		w.str(e.Detail)
+	case *ResyncEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Source)
+		w.str(e.Phase)
+		w.str(e.Detail)
+		w.uint(uint64(len(e.Balances)))
+		for _, b := range e.Balances {
+			w.str(b.Symbol)
+			w.int(b.AmountSats)
+			w.int(b.ReservedSats)
+			w.uint(b.LastSeq)
+		}
+	case *ManualOrderEvent:
+		w.base(e.BaseEvent)
+		w.str(e.Action)
+		w.str(e.Exchange)
+		w.str(e.Symbol)
+		w.str(e.Side)
+		w.str(e.OrderType)
+		w.int(int64(e.PriceMicros))
+		w.int(int64(e.QtySats))
+		w.str(e.Operator)
+		w.str(e.Reason)
+	default:
+		return dst, false
+	}
+	return w.buf, true
+}
+
+func decodeBinary(t Type, version byte, b []byte) (Event, error) {
+	r := binReader{buf: b, version: version}
+	var ev Event
+	switch t {
+	case EvMarketUpdate:
+		e := &MarketUpdateEvent{BaseEvent: r.base()}
+		e.Symbol = r.str()
+		e.PriceMicros = quant.PriceMicros(r.int())
+		e.QtySats = quant.QtySats(r.int())
+		e.Exchange = r.str()
+		ev = e
+	case EvOrderUpdate:
+		e := &OrderUpdateEvent{BaseEvent: r.base()}
+		e.OrderID = r.str()
+		e.Status = r.str()
+		e.PriceMicros = quant.PriceMicros(r.int())
+		e.AccumulatedQtySats = quant.QtySats(r.int())
+		ev = e
+	case EvControl:
+		e := &ControlEvent{BaseEvent: r.base()}
+		e.Command = ControlCommand(r.uint())
+		e.Target = r.str()
+		e.Value = r.int()
+		e.Reason = r.str()
+		if r.version >= binaryV3 {
+			e.Report = r.str()
+		}
+		ev = e
+	case EvOrderRejected:
+		e := &OrderRejectedEvent{BaseEvent: r.base()}
+		e.OrderID = r.str()
+		e.Symbol = r.str()
+		e.Side = r.str()
+		e.PriceMicros = quant.PriceMicros(r.int())
+		e.QtySats = quant.QtySats(r.int())
+		e.Exchange = r.str()
+		e.Reason = domain.RejectKind(r.str())
+		e.Code = r.str()
+		e.Message = r.str()
+		ev = e
+	case EvOrderBook:
+		e := &OrderBookUpdateEvent{BaseEvent: r.base()}
+		e.Symbol = r.str()
+		e.Exchange = r.str()
+		e.Snapshot = r.bool()
+		e.Bids = r.levels()
+		e.Asks = r.levels()
+		ev = e
+	case EvTrade:
+		e := &TradeEvent{BaseEvent: r.base()}
+		e.Symbol = r.str()
+		e.Exchange = r.str()
+		e.Side = r.str()
+		e.PriceMicros = quant.PriceMicros(r.int())
+		e.QtySats = quant.QtySats(r.int())
+		ev = e
+	case EvCandle:
+		e := &CandleEvent{BaseEvent: r.base()}
+		e.Exchange = r.str()
+		e.Symbol = r.str()
+		e.Interval = r.str()
+		e.OpenTime = quant.TimeStamp(r.int())
+		e.OpenMicros = quant.PriceMicros(r.int())
+		e.HighMicros = quant.PriceMicros(r.int())
+		e.LowMicros = quant.PriceMicros(r.int())
+		e.CloseMicros = quant.PriceMicros(r.int())
+		e.VolumeSats = quant.QtySats(r.int())
+		ev = e
+	case EvContext:
+		e := &ContextEvent{BaseEvent: r.base()}
+		e.Source = r.str()
+		e.Metric = r.str()
+		e.Subject = r.str()
+		e.ValueMicros = r.int()
+		ev = e
+	case EvSentiment:
+		e := &SentimentEvent{BaseEvent: r.base()}
+		e.Source = r.str()
+		e.Index = r.str()
+		e.Value = r.int()
+		e.Label = r.str()
+		ev = e
+	case EvNotice:
+		e := &NoticeEvent{BaseEvent: r.base()}
+		e.Source = r.str()
+		e.Exchange = r.str()
+		e.NoticeID = r.int()
+		e.Kind = r.str()
+		e.Title = r.str()
+		if n := r.count(); n > 0 {
+			e.Symbols = make([]string, n)
+			for i := range e.Symbols {
+				e.Symbols[i] = r.str()
+			}
+		}
+		ev = e
+	case EvSignal:
+		e := &SignalEvent{BaseEvent: r.base()}
+		e.Source = r.str()
+		e.Origin = r.str()
+		e.Name = r.str()
+		e.Symbol = r.str()
+		e.Action = r.str()
+		e.PriceMicros = quant.PriceMicros(r.int())
+		e.Note = r.str()
+		ev = e
+	case EvFunding:
+		e := &FundingEvent{BaseEvent: r.base()}
+		e.Exchange = r.str()
+		e.Symbol = r.str()
+		e.NextFundingTs = quant.TimeStamp(r.int())
+		e.RateMicros = r.int()
+		ev = e
+	case EvTimer:
+		e := &TimerEvent{BaseEvent: r.base()}
+		e.Source = r.str()
+		e.Name = r.str()
+		e.IntervalMs = r.int()
+		e.Tick = r.uint()
+		ev = e
+	case EvHeartbeat:
+		e := &HeartbeatEvent{BaseEvent: r.base()}
+		e.Source = r.str()
+		e.Instance = r.str()
+		e.IntervalMs = r.int()
+		e.Beat = r.uint()
+		ev = e
+	case EvSystemHalt:
+		e := &HaltEvent{BaseEvent: r.base()}
+		e.Reason = r.str()
+		e.Detail = r.str()
+		ev = e
+	case EvResync:
+		e := &ResyncEvent{BaseEvent: r.base()}
+		e.Source = r.str()
+		e.Phase = r.str()
+		e.Detail = r.str()
+		if n := r.count(); n > 0 {
+			e.Balances = make([]domain.Balance, n)
+			for i := range e.Balances {
+				e.Balances[i].Symbol = r.str()
+				e.Balances[i].AmountSats = r.int()
+				e.Balances[i].ReservedSats = r.int()
+				e.Balances[i].LastSeq = r.uint()
+			}
+		}
+		ev = e
+	case EvManualOrder:
+		e := &ManualOrderEvent{BaseEvent: r.base()}
+		e.Action = r.str()
+		e.Exchange = r.str()
+		e.Symbol = r.str()
+		e.Side = r.str()
+		e.OrderType = r.str()
+		e.PriceMicros = quant.PriceMicros(r.int())
+		e.QtySats = quant.QtySats(r.int())
+		e.Operator = r.str()
+		e.Reason = r.str()
+		ev = e
+	default:
+		return nil, nil
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrCorruptPayload, t, r.err)
+	}
+	if len(r.buf) > 0 {
+		return nil, fmt.Errorf("%w: %s: %d trailing bytes", ErrCorruptPayload, t, len(r.buf))
+	}
+	return ev, nil
+}
+
+// decodeJSON restores an event from its JSON payload.
+func decodeJSON(t Type, payload []byte) (Event, error) {
+	var ev Event
+	switch t {
+	case EvMarketUpdate:
+		ev = &MarketUpdateEvent{}
+	case EvOrderUpdate:
+		ev = &OrderUpdateEvent{}
+	case EvControl:
+		ev = &ControlEvent{}
+	case EvOrderRejected:
+		ev = &OrderRejectedEvent{}
+	case EvOrderBook:
+		ev = &OrderBookUpdateEvent{}
+	case EvTrade:
+		ev = &TradeEvent{}
+	case EvCandle:
+		ev = &CandleEvent{}
+	case EvContext:
+		ev = &ContextEvent{}
+	case EvSentiment:
+		ev = &SentimentEvent{}
+	case EvNotice:
+		ev = &NoticeEvent{}
+	case EvSignal:
+		ev = &SignalEvent{}
+	case EvFunding:
+		ev = &FundingEvent{}
+	case EvTimer:
+		ev = &TimerEvent{}
+	case EvHeartbeat:
+		ev = &HeartbeatEvent{}
+	case EvSystemHalt:
+		ev = &HaltEvent{}
+	case EvResync:
+		ev = &ResyncEvent{}
+	case EvManualOrder:
+		ev = &ManualOrderEvent{}
+	default:
+		return nil, nil
+	}
+	if err := json.Unmarshal(payload, ev); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// binWriter appends varint fields: unsigned as uvarint, signed as zigzag
+// varint, strings and slices length-prefixed.
+type binWriter struct{ buf []byte }
+
+func (w *binWriter) uint(v uint64) { w.buf = binary.AppendUvarint(w.buf, v) }
+func (w *binWriter) int(v int64)   { w.buf = binary.AppendVarint(w.buf, v) }
+
+func (w *binWriter) str(s string) {
+	w.uint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *binWriter) bool(v bool) {
+	if v {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *binWriter) base(b BaseEvent) {
+	w.uint(b.Seq)
+	w.int(int64(b.Ts))
+	w.uint(uint64(b.Incarnation))
+}
+
+func (w *binWriter) levels(levels []domain.BookLevel) {
+	w.uint(uint64(len(levels)))
+	for _, l := range levels {
+		w.int(int64(l.PriceMicros))
+		w.int(int64(l.QtySats))
+	}
+}
+
+// binReader consumes what binWriter wrote. The first error sticks; later
+// reads return zero values.
+type binReader struct {
+	buf     []byte
+	err     error
+	version byte // Layout of the payload
+}
+
+func (r *binReader) fail(what string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("truncated %s", what)
+	}
+	r.buf = nil
+}
+
+func (r *binReader) uint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail("uvarint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *binReader) int() int64 {
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail("varint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+// count reads a length prefix that cannot exceed the bytes left.
+func (r *binReader) count() int {
+	n := r.uint()
+	if n > uint64(len(r.buf)) {
+		r.fail("length")
+		return 0
+	}
+	return int(n)
+}
+
+func (r *binReader) str() string {
+	n := r.count()
+	s := string(r.buf[:n])
+	r.buf = r.buf[n:]
+	return s
+}
+
+func (r *binReader) bool() bool {
+	if len(r.buf) == 0 || r.buf[0] > 1 {
+		r.fail("bool")
+		return false
+	}
+	v := r.buf[0] == 1
+	r.buf = r.buf[1:]
+	return v
+}
+
+func (r *binReader) base() BaseEvent {
+	b := BaseEvent{Seq: r.uint(), Ts: quant.TimeStamp(r.int())}
+	if r.version >= binaryV2 {
+		b.Incarnation = uint32(r.uint())
+	}
+	return b
+}
+
+func (r *binReader) levels() []domain.BookLevel {
+	n := r.count()
+	if n == 0 {
+		return nil
+	}
+	levels := make([]domain.BookLevel, n)
+	for i := range levels {
+		levels[i] = domain.BookLevel{PriceMicros: quant.PriceMicros(r.int()), QtySats: quant.QtySats(r.int())}
+	}
+	return levels
+}
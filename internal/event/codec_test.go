@@ -0,0 +1,183 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// persisted lists every event type with a binary layout.
+func persisted() []Event {
+	return []Event{
+		&MarketUpdateEvent{}, &OrderUpdateEvent{}, &ControlEvent{}, &OrderRejectedEvent{},
+		&OrderBookUpdateEvent{}, &TradeEvent{}, &CandleEvent{}, &ContextEvent{},
+		&SentimentEvent{}, &NoticeEvent{}, &SignalEvent{}, &FundingEvent{}, &TimerEvent{}, &HeartbeatEvent{}, &HaltEvent{},
+		&ResyncEvent{}, &ManualOrderEvent{},
+	}
+}
+
+// fill sets every field of v to a distinct non-zero value, so a field missing
+// from a binary layout fails the round trip.
+func fill(v reflect.Value, n *int64) {
+	*n++
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fill(v.Field(i), n)
+		}
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 2, 2)
+		for i := 0; i < 2; i++ {
+			fill(s.Index(i), n)
+		}
+		v.Set(s)
+	case reflect.String:
+		v.SetString("s" + string(rune('a'+*n%26)))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(-*n * 1_000_003)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(*n))
+	case reflect.Bool:
+		v.SetBool(true)
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	for _, ev := range persisted() {
+		var n int64
+		fill(reflect.ValueOf(ev).Elem(), &n)
+		for _, codec := range []string{CodecBinary, CodecJSON} {
+			payload, err := Encode(codec, ev)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", ev.GetType(), codec, err)
+			}
+			if got := PayloadCodec(payload); got != codec {
+				t.Errorf("%s: payload detected as %s, want %s", ev.GetType(), got, codec)
+			}
+			got, err := Decode(ev.GetType(), payload)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", ev.GetType(), codec, err)
+			}
+			if !reflect.DeepEqual(got, ev) {
+				t.Errorf("%s/%s round trip:\n got %+v\nwant %+v", ev.GetType(), codec, got, ev)
+			}
+		}
+	}
+}
+
+func TestCodec_LegacyJSON(t *testing.T) {
+	// A record written before the binary codec existed
+	payload := []byte(`{"seq":7,"ts":1700000000000000,"symbol":"BTC","price":95000000000,"qty":0,"exchange":"UPBIT"}`)
+	ev, err := Decode(EvMarketUpdate, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ev.(*MarketUpdateEvent)
+	if m.Seq != 7 || m.Symbol != "BTC" || m.PriceMicros != 95000000000 || m.Exchange != "UPBIT" {
+		t.Errorf("unexpected legacy decode: %+v", m)
+	}
+}
+
+func TestCodec_BinaryV1(t *testing.T) {
+	// A record written before BaseEvent.Incarnation: seq 7, ts 9, no incarnation
+	payload := []byte{binaryV1, 7, 18, 3, 'B', 'T', 'C', 4, 0, 0}
+	ev, err := Decode(EvMarketUpdate, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ev.(*MarketUpdateEvent)
+	if m.Seq != 7 || m.Ts != 9 || m.Incarnation != 0 || m.Symbol != "BTC" || m.PriceMicros != 2 {
+		t.Errorf("unexpected v1 decode: %+v", m)
+	}
+	if PayloadCodec(payload) != CodecBinary {
+		t.Error("a v1 payload must be detected as binary")
+	}
+}
+
+func TestCodec_Smaller(t *testing.T) {
+	ev := &MarketUpdateEvent{BaseEvent: BaseEvent{Seq: 123456, Ts: 1700000000000000}, Symbol: "BTC", PriceMicros: 95000000000, QtySats: 1500000, Exchange: "UPBIT"}
+	bin, _ := Encode(CodecBinary, ev)
+	js, _ := json.Marshal(ev)
+	if len(bin)*2 > len(js) {
+		t.Errorf("binary payload %d bytes, JSON %d", len(bin), len(js))
+	}
+}
+
+func TestCodec_Corrupt(t *testing.T) {
+	ev := &NoticeEvent{BaseEvent: BaseEvent{Seq: 1, Ts: 2}, Source: "upbit", Title: "listing", Symbols: []string{"BTC", "ETH"}}
+	payload, _ := Encode(CodecBinary, ev)
+
+	for i := 1; i < len(payload); i++ {
+		if _, err := Decode(EvNotice, payload[:i]); !errors.Is(err, ErrCorruptPayload) {
+			t.Fatalf("truncated at %d: expected ErrCorruptPayload, got %v", i, err)
+		}
+	}
+	if _, err := Decode(EvNotice, append(payload, 0)); !errors.Is(err, ErrCorruptPayload) {
+		t.Errorf("trailing byte: expected ErrCorruptPayload, got %v", err)
+	}
+	if _, err := Decode(EvNotice, []byte{9, 0}); !errors.Is(err, ErrCorruptPayload) {
+		t.Errorf("unknown version: expected ErrCorruptPayload, got %v", err)
+	}
+	if ev, err := Decode(Type(200), payload); ev != nil || err != nil {
+		t.Errorf("unknown type should be skipped, got %v, %v", ev, err)
+	}
+}
+
+func FuzzCodec_Decode(f *testing.F) {
+	for _, ev := range persisted() {
+		var n int64
+		fill(reflect.ValueOf(ev).Elem(), &n)
+		payload, _ := Encode(CodecBinary, ev)
+		f.Add(uint8(ev.GetType()), payload)
+	}
+	f.Fuzz(func(t *testing.T, typ uint8, payload []byte) {
+		ev, err := Decode(Type(typ), payload)
+		if err != nil || ev == nil || PayloadCodec(payload) != CodecBinary {
+			return
+		}
+		// Whatever decodes must survive a round trip of its own
+		again, _ := Encode(CodecBinary, ev)
+		if got, err := Decode(ev.GetType(), again); err != nil || !reflect.DeepEqual(got, ev) {
+			t.Errorf("%s: round trip of %x gave %+v, %v", ev.GetType(), payload, got, err)
+		}
+	})
+}
+
+func BenchmarkCodec_EncodeJSON(b *testing.B) {
+	benchmarkEncode(b, CodecJSON)
+}
+
+func BenchmarkCodec_EncodeBinary(b *testing.B) {
+	benchmarkEncode(b, CodecBinary)
+}
+
+func BenchmarkCodec_DecodeJSON(b *testing.B) {
+	benchmarkDecode(b, CodecJSON)
+}
+
+func BenchmarkCodec_DecodeBinary(b *testing.B) {
+	benchmarkDecode(b, CodecBinary)
+}
+
+var benchEvent = &MarketUpdateEvent{BaseEvent: BaseEvent{Seq: 123456, Ts: 1700000000000000}, Symbol: "BTC", PriceMicros: 95000000000, QtySats: 1500000, Exchange: "UPBIT"}
+
+func benchmarkEncode(b *testing.B, codec string) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Encode(codec, benchEvent); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDecode(b *testing.B, codec string) {
+	payload, _ := Encode(codec, benchEvent)
+	b.SetBytes(int64(len(payload)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Decode(EvMarketUpdate, payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -69,6 +69,16 @@ func ReleaseOrderUpdateEvent(ev *OrderUpdateEvent) {
 	orderUpdatePool.Put(ev)
 }
 
+// Release returns any pooled event to its pool. Unpooled types are ignored.
+func Release(ev Event) {
+	switch e := ev.(type) {
+	case *MarketUpdateEvent:
+		ReleaseMarketUpdateEvent(e)
+	case *OrderUpdateEvent:
+		ReleaseOrderUpdateEvent(e)
+	}
+}
+
 // Warmup pre-allocates event objects to reduce GC pressure at startup.
 // It acquires and releases a batch of events.
 func Warmup() {
@@ -1,7 +1,9 @@
 package event
 
 import (
+	"crypto_go/internal/domain"
 	"crypto_go/pkg/quant"
+	"fmt"
 )
 
 // Type defines the type of event.
@@ -12,12 +14,69 @@ const (
 	EvOrderUpdate
 	EvBalanceUpdate
 	EvSystemHalt
+	EvControl
+	EvOrderRejected
+	EvOrderBook
+	EvTrade
+	EvCandle
+	EvContext
+	EvSentiment
+	EvOrderRequest
+	EvNotice
+	EvSignal
+	EvFunding
+	EvTimer
+	EvResync
+	EvHeartbeat
+	EvManualOrder
 )
 
+// typeNames maps event types to their config/report names.
+var typeNames = map[Type]string{
+	EvMarketUpdate:  "market_update",
+	EvOrderUpdate:   "order_update",
+	EvBalanceUpdate: "balance_update",
+	EvSystemHalt:    "system_halt",
+	EvControl:       "control",
+	EvOrderRejected: "order_rejected",
+	EvOrderBook:     "order_book",
+	EvTrade:         "trade",
+	EvCandle:        "candle",
+	EvContext:       "context",
+	EvSentiment:     "sentiment",
+	EvOrderRequest:  "order_request",
+	EvNotice:        "notice",
+	EvSignal:        "signal",
+	EvFunding:       "funding",
+	EvTimer:         "timer",
+	EvResync:        "resync",
+	EvHeartbeat:     "heartbeat",
+	EvManualOrder:   "manual_order",
+}
+
+// String returns the snake_case name of the event type.
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("type_%d", uint16(t))
+}
+
+// ParseType resolves a snake_case event type name (as used in config).
+func ParseType(name string) (Type, error) {
+	for t, n := range typeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown event type %q", name)
+}
+
 // Event is the interface for all sequencer events.
 type Event interface {
 	GetSeq() uint64
 	GetTs() quant.TimeStamp
+	GetIncarnation() uint32
 	GetType() Type
 }
 
@@ -25,10 +84,21 @@ type Event interface {
 type BaseEvent struct {
 	Seq uint64          `json:"seq"`
 	Ts  quant.TimeStamp `json:"ts"`
+	// Incarnation is the gateway connection the event was read under: the
+	// gateway's reconnect counter, 1 for its first connection. 0 for events
+	// not sent by a gateway and for records written before it was tracked.
+	Incarnation uint32 `json:"inc,omitempty"`
 }
 
 func (e BaseEvent) GetSeq() uint64         { return e.Seq }
 func (e BaseEvent) GetTs() quant.TimeStamp { return e.Ts }
+func (e BaseEvent) GetIncarnation() uint32 { return e.Incarnation }
+
+// SetSeq restamps the source sequence (a gateway outbox that conflates events).
+func (e *BaseEvent) SetSeq(seq uint64) { e.Seq = seq }
+
+// SetIncarnation tags the event with its gateway connection (infra.Outbox).
+func (e *BaseEvent) SetIncarnation(inc uint32) { e.Incarnation = inc }
 
 // MarketUpdateEvent represents a price change in the market.
 type MarketUpdateEvent struct {
@@ -51,3 +121,401 @@ type OrderUpdateEvent struct {
 }
 
 func (e OrderUpdateEvent) GetType() Type { return EvOrderUpdate }
+
+// OrderRequestEvent asks the execution gateway to place an order. The OMS derives
+// it from a strategy signal while processing the triggering event and stamps it
+// with that event's seq, so it is never written to the WAL: replay rebuilds the
+// same request without sending it again.
+type OrderRequestEvent struct {
+	BaseEvent
+	OrderID     string            `json:"order_id"` // Client order ID (idempotency key at the venue)
+	Symbol      string            `json:"symbol"`
+	Side        string            `json:"side"`
+	Type        string            `json:"type"`
+	Market      string            `json:"market,omitempty"`
+	Exchange    string            `json:"exchange,omitempty"` // Routed venue; empty = the gateway's default
+	PriceMicros quant.PriceMicros `json:"price"`
+	QtySats     quant.QtySats     `json:"qty"`
+	Cancel      bool              `json:"cancel,omitempty"`   // Cancel OrderID instead of placing an order
+	Strategy    string            `json:"strategy,omitempty"` // Strategy that emitted the order (empty = single strategy or operator)
+}
+
+// Order converts the request into the order submitted to the venue.
+func (e *OrderRequestEvent) Order() domain.Order {
+	return domain.Order{
+		ID:           e.OrderID,
+		Symbol:       e.Symbol,
+		Side:         e.Side,
+		Type:         e.Type,
+		Market:       e.Market,
+		Exchange:     e.Exchange,
+		PriceMicros:  int64(e.PriceMicros),
+		QtySats:      int64(e.QtySats),
+		Status:       domain.OrderStatusSent,
+		CreatedUnixM: int64(e.Ts),
+		Strategy:     e.Strategy,
+	}
+}
+
+func (e OrderRequestEvent) GetType() Type { return EvOrderRequest }
+
+// OrderRejectedEvent reports that a venue refused an order, with a normalized
+// reason so strategies can react (shrink size, back off) instead of parsing strings.
+type OrderRejectedEvent struct {
+	BaseEvent
+	OrderID     string            `json:"order_id"`
+	Symbol      string            `json:"symbol"`
+	Side        string            `json:"side"`
+	PriceMicros quant.PriceMicros `json:"price"`
+	QtySats     quant.QtySats     `json:"qty"`
+	Exchange    string            `json:"exchange"`
+	Reason      domain.RejectKind `json:"reason"`
+	Code        string            `json:"code,omitempty"` // Raw venue error code
+	Message     string            `json:"message,omitempty"`
+}
+
+// Rejection converts the event into the strategy-facing domain type.
+func (e *OrderRejectedEvent) Rejection() domain.OrderRejection {
+	return domain.OrderRejection{
+		Order: domain.Order{
+			ID:          e.OrderID,
+			Symbol:      e.Symbol,
+			Side:        e.Side,
+			PriceMicros: int64(e.PriceMicros),
+			QtySats:     int64(e.QtySats),
+			Status:      domain.OrderStatusRejected,
+		},
+		Exchange: e.Exchange,
+		Reason:   e.Reason,
+		Code:     e.Code,
+		Message:  e.Message,
+	}
+}
+
+func (e OrderRejectedEvent) GetType() Type { return EvOrderRejected }
+
+// OrderBookUpdateEvent carries the top-N levels of an L2 order book. A snapshot
+// replaces the whole book; a delta sets the listed levels (qty 0 removes one).
+// The Sequencer applies it to its domain.OrderBook for the exchange/symbol.
+type OrderBookUpdateEvent struct {
+	BaseEvent
+	Symbol   string             `json:"symbol"`
+	Exchange string             `json:"exchange"`
+	Snapshot bool               `json:"snapshot,omitempty"`
+	Bids     []domain.BookLevel `json:"bids,omitempty"`
+	Asks     []domain.BookLevel `json:"asks,omitempty"`
+}
+
+func (e OrderBookUpdateEvent) GetType() Type { return EvOrderBook }
+
+// TradeEvent is one trade from a venue's public trade stream. Ts is the exchange
+// trade time; Side is the aggressor (domain.SideBuy / domain.SideSell).
+type TradeEvent struct {
+	BaseEvent
+	Symbol      string            `json:"symbol"`
+	Exchange    string            `json:"exchange"`
+	Side        string            `json:"side"`
+	PriceMicros quant.PriceMicros `json:"price"`
+	QtySats     quant.QtySats     `json:"qty"`
+}
+
+// Trade converts the event into the strategy-facing domain type.
+func (e *TradeEvent) Trade() domain.Trade {
+	return domain.Trade{
+		Exchange:    e.Exchange,
+		Symbol:      e.Symbol,
+		Side:        e.Side,
+		PriceMicros: e.PriceMicros,
+		QtySats:     e.QtySats,
+		Ts:          e.Ts,
+	}
+}
+
+func (e TradeEvent) GetType() Type { return EvTrade }
+
+// CandleEvent is a push of one (possibly still open) kline bar. Ts is the
+// exchange push time; the bar is identified by Exchange/Symbol/Interval/OpenTime.
+type CandleEvent struct {
+	BaseEvent
+	Exchange    string            `json:"exchange"`
+	Symbol      string            `json:"symbol"`
+	Interval    string            `json:"interval"` // domain.Candle1m / Candle5m / Candle1h
+	OpenTime    quant.TimeStamp   `json:"open_time"`
+	OpenMicros  quant.PriceMicros `json:"open"`
+	HighMicros  quant.PriceMicros `json:"high"`
+	LowMicros   quant.PriceMicros `json:"low"`
+	CloseMicros quant.PriceMicros `json:"close"`
+	VolumeSats  quant.QtySats     `json:"volume"`
+}
+
+// Candle converts the event into the domain bar.
+func (e *CandleEvent) Candle() domain.Candle {
+	return domain.Candle{
+		Exchange:    e.Exchange,
+		Symbol:      e.Symbol,
+		Interval:    e.Interval,
+		OpenTime:    e.OpenTime,
+		OpenMicros:  e.OpenMicros,
+		HighMicros:  e.HighMicros,
+		LowMicros:   e.LowMicros,
+		CloseMicros: e.CloseMicros,
+		VolumeSats:  e.VolumeSats,
+	}
+}
+
+func (e CandleEvent) GetType() Type { return EvCandle }
+
+// ContextEvent is one reading of a low-frequency context metric (on-chain flows,
+// sentiment). Ts is the observation time reported by the provider.
+type ContextEvent struct {
+	BaseEvent
+	Source      string `json:"source"`
+	Metric      string `json:"metric"`            // domain.Metric* constant
+	Subject     string `json:"subject,omitempty"` // e.g. exchange; "" = market-wide
+	ValueMicros int64  `json:"value"`
+}
+
+// Metric converts the event into the strategy-facing domain type.
+func (e *ContextEvent) Context() domain.ContextMetric {
+	return domain.ContextMetric{
+		Source:      e.Source,
+		Name:        e.Metric,
+		Subject:     e.Subject,
+		ValueMicros: e.ValueMicros,
+		Ts:          e.Ts,
+	}
+}
+
+func (e ContextEvent) GetType() Type { return EvContext }
+
+// SentimentEvent is one reading of a sentiment index (Fear & Greed, funding).
+// Ts is the observation time reported by the provider.
+type SentimentEvent struct {
+	BaseEvent
+	Source string `json:"source"`
+	Index  string `json:"index"` // domain.Sentiment* constant
+	Value  int64  `json:"value"`
+	Label  string `json:"label,omitempty"`
+}
+
+// Sentiment converts the event into the strategy-facing domain type.
+func (e *SentimentEvent) Sentiment() domain.Sentiment {
+	return domain.Sentiment{Source: e.Source, Index: e.Index, Value: e.Value, Label: e.Label, Ts: e.Ts}
+}
+
+func (e SentimentEvent) GetType() Type { return EvSentiment }
+
+// NoticeEvent is a classified exchange announcement (listing, delisting, wallet
+// maintenance). Ts is the publication time reported by the exchange.
+type NoticeEvent struct {
+	BaseEvent
+	Source   string   `json:"source"`
+	Exchange string   `json:"exchange"`
+	NoticeID int64    `json:"notice_id"`
+	Kind     string   `json:"kind"` // domain.Notice* constant
+	Title    string   `json:"title"`
+	Symbols  []string `json:"symbols,omitempty"`
+}
+
+// Notice converts the event into the strategy-facing domain type.
+func (e *NoticeEvent) Notice() domain.Notice {
+	return domain.Notice{Exchange: e.Exchange, ID: e.NoticeID, Kind: e.Kind, Title: e.Title, Symbols: e.Symbols, Ts: e.Ts}
+}
+
+func (e NoticeEvent) GetType() Type { return EvNotice }
+
+// SignalEvent is an external trading signal accepted by the signal webhook.
+// Ts is the receive time.
+type SignalEvent struct {
+	BaseEvent
+	Source      string            `json:"source"`
+	Origin      string            `json:"origin"`
+	Name        string            `json:"name,omitempty"`
+	Symbol      string            `json:"symbol"`
+	Action      string            `json:"action"` // domain.Signal* constant
+	PriceMicros quant.PriceMicros `json:"price_micros,omitempty"`
+	Note        string            `json:"note,omitempty"`
+}
+
+// Signal converts the event into the strategy-facing domain type.
+func (e *SignalEvent) Signal() domain.Signal {
+	return domain.Signal{Origin: e.Origin, Name: e.Name, Symbol: e.Symbol, Action: e.Action, PriceMicros: e.PriceMicros, Note: e.Note, Ts: e.Ts}
+}
+
+func (e SignalEvent) GetType() Type { return EvSignal }
+
+// FundingEvent announces the next funding epoch of a perpetual contract. Perp
+// workers emit it when the epoch moves on and when the predicted rate changes
+// (throttled), not with every ticker. Ts is the exchange push time.
+type FundingEvent struct {
+	BaseEvent
+	Exchange      string          `json:"exchange"`
+	Symbol        string          `json:"symbol"`
+	NextFundingTs quant.TimeStamp `json:"next_funding"`
+	RateMicros    int64           `json:"rate"`
+}
+
+// FundingEpoch converts the event into the strategy-facing domain type.
+func (e *FundingEvent) FundingEpoch() domain.FundingEpoch {
+	return domain.FundingEpoch{Exchange: e.Exchange, Symbol: e.Symbol, NextTs: e.NextFundingTs, RateMicros: e.RateMicros}
+}
+
+func (e FundingEvent) GetType() Type { return EvFunding }
+
+// TimerEvent is one tick of a named interval timer (TimerService). Ts is the
+// scheduled boundary, not the send time, so time-based logic replays
+// identically from the WAL.
+type TimerEvent struct {
+	BaseEvent
+	Source     string `json:"source"`
+	Name       string `json:"name"`
+	IntervalMs int64  `json:"interval_ms"`
+	Tick       uint64 `json:"tick"` // Boundary index: Ts / interval
+}
+
+// Timer converts the event into the strategy-facing domain type.
+func (e *TimerEvent) Timer() domain.Timer {
+	return domain.Timer{Name: e.Name, IntervalMs: e.IntervalMs, Tick: e.Tick, Ts: e.Ts}
+}
+
+func (e TimerEvent) GetType() Type { return EvTimer }
+
+// HeartbeatEvent marks the engine alive at Ts (HeartbeatService). In the WAL a
+// stretch without heartbeats means the engine was down; one with heartbeats
+// but no market data means the markets were quiet. Beat counts from 1 at
+// every start, so a restart shows as a reset even after a short outage.
+type HeartbeatEvent struct {
+	BaseEvent
+	Source     string `json:"source"`
+	Instance   string `json:"instance,omitempty"` // Process that wrote it (instance ID)
+	IntervalMs int64  `json:"interval_ms"`
+	Beat       uint64 `json:"beat"`
+}
+
+func (e HeartbeatEvent) GetType() Type { return EvHeartbeat }
+
+// Phases of a gateway resync.
+const (
+	ResyncBegin = "BEGIN" // Gap detected: order dispatch paused until END
+	ResyncEnd   = "END"   // Snapshots delivered: dispatch resumes
+)
+
+// ResyncEvent brackets the resynchronization of gateway Source after a
+// sequence gap. The engine itself sequences BEGIN right before the event that
+// revealed the gap; the resync coordinator sends END, with the venue balances
+// it fetched, after the gateway's REST snapshots. Both are in the WAL, so a
+// replay pauses and resumes at the same seqs.
+type ResyncEvent struct {
+	BaseEvent
+	Source   string           `json:"source"` // Gateway resynced (e.g. "UPBIT")
+	Phase    string           `json:"phase"`  // ResyncBegin | ResyncEnd
+	Detail   string           `json:"detail,omitempty"`
+	Balances []domain.Balance `json:"balances,omitempty"` // END: venue totals (Symbol, AmountSats)
+}
+
+func (e ResyncEvent) GetType() Type { return EvResync }
+
+// Actions of a ManualOrderEvent.
+const (
+	ManualPlace     = "PLACE"      // Place the order described by the event
+	ManualClose     = "CLOSE"      // Close the position of Exchange/Symbol ("" symbol = every position of the venue)
+	ManualCancelAll = "CANCEL_ALL" // Cancel every open order of Exchange ("" = all venues), Symbol if set
+)
+
+// ManualOrderEvent is an operator order action (order entry API). It shares
+// the CONTROL source sequence, is written to the WAL, and its orders take the
+// same path as strategy orders: router, risk checks, OMS. Replay rebuilds them
+// without sending them again.
+type ManualOrderEvent struct {
+	BaseEvent
+	Action      string            `json:"action"` // Manual* constant
+	Exchange    string            `json:"exchange,omitempty"`
+	Symbol      string            `json:"symbol,omitempty"`
+	Side        string            `json:"side,omitempty"`
+	OrderType   string            `json:"order_type,omitempty"` // MARKET | LIMIT
+	PriceMicros quant.PriceMicros `json:"price,omitempty"`
+	QtySats     quant.QtySats     `json:"qty,omitempty"`
+	Operator    string            `json:"operator,omitempty"` // Who sent it, for the audit trail
+	Reason      string            `json:"reason,omitempty"`
+}
+
+func (e ManualOrderEvent) GetType() Type { return EvManualOrder }
+
+// Kill switch triggers recorded in HaltEvent.Reason.
+const (
+	HaltManual      = "MANUAL"       // Operator via the control endpoint
+	HaltDrawdown    = "DRAWDOWN"     // Net PnL of the positions fell past the drawdown limit
+	HaltSequenceGap = "SEQUENCE_GAP" // A gateway needed a resync after a sequence gap
+)
+
+// HaltEvent is the kill switch: every open order is canceled and order
+// dispatch stops (monitor-only) until CmdResumeTrading. It shares the CONTROL
+// source sequence with ControlEvents.
+type HaltEvent struct {
+	BaseEvent
+	Reason string `json:"reason"` // Halt* constant
+	Detail string `json:"detail,omitempty"`
+}
+
+func (e HaltEvent) GetType() Type { return EvSystemHalt }
+
+// ControlCommand identifies an administrative action on the engine.
+type ControlCommand uint8
+
+const (
+	CmdPauseStrategy    ControlCommand = iota + 1 // Stop feeding the strategy (state keeps updating)
+	CmdResumeStrategy                             // Resume a paused strategy
+	CmdSetRiskLimit                               // Set risk limit Target to Value
+	CmdTriggerSnapshot                            // Persist a state snapshot at this point in the sequence
+	CmdHalt                                       // Stop all order dispatch (monitor-only) until CmdResumeTrading
+	CmdResumeTrading                              // Lift a Halt
+	CmdHandover                                   // Stop order dispatch: the WAL is being handed to successor Target
+	CmdTakeover                                   // Target now writes the WAL; lifts a Handover
+	CmdReduceOnly                                 // Venue Target ("" = all) only takes orders that reduce a position; Value 1 also flattens
+	CmdLiftReduceOnly                             // Lift reduce-only for venue Target ("" = every venue)
+	CmdSetStrategyParam                           // Set strategy parameter Target (e.g. "short_period") to Value
+)
+
+var commandNames = map[ControlCommand]string{
+	CmdPauseStrategy:    "PAUSE_STRATEGY",
+	CmdResumeStrategy:   "RESUME_STRATEGY",
+	CmdSetRiskLimit:     "SET_RISK_LIMIT",
+	CmdTriggerSnapshot:  "TRIGGER_SNAPSHOT",
+	CmdHalt:             "HALT",
+	CmdResumeTrading:    "RESUME_TRADING",
+	CmdHandover:         "HANDOVER",
+	CmdTakeover:         "TAKEOVER",
+	CmdReduceOnly:       "REDUCE_ONLY",
+	CmdLiftReduceOnly:   "LIFT_REDUCE_ONLY",
+	CmdSetStrategyParam: "SET_STRATEGY_PARAM",
+}
+
+func (c ControlCommand) String() string {
+	if name, ok := commandNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("CMD_%d", uint8(c))
+}
+
+// ParseControlCommand resolves an UPPER_SNAKE command name.
+func ParseControlCommand(name string) (ControlCommand, error) {
+	for c, n := range commandNames {
+		if n == name {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown control command %q", name)
+}
+
+// ControlEvent carries an administrative command through the sequencer so it is
+// ordered against market data, written to the WAL, and replayed on recovery.
+type ControlEvent struct {
+	BaseEvent
+	Command ControlCommand `json:"command"`
+	Target  string         `json:"target,omitempty"` // Strategy name, risk limit key or venue
+	Value   int64          `json:"value,omitempty"`  // Risk limit value (int64 units of the key) or flag
+	Reason  string         `json:"reason,omitempty"` // Operator note for the audit trail
+	Report  string         `json:"report,omitempty"` // Dry-run backfill of a CmdSetStrategyParam change
+}
+
+func (e ControlEvent) GetType() Type { return EvControl }
@@ -0,0 +1,180 @@
+package execution
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"strings"
+)
+
+// bpsScale: fees and slippage are integer basis points (1bp = 0.01%).
+const bpsScale = 10_000
+
+// FeeModel prices the commission of a simulated fill.
+type FeeModel interface {
+	// FeeMicros returns the fee in quote micros for a fill of notionalMicros.
+	// Negative = rebate.
+	FeeMicros(notionalMicros int64, maker bool) int64
+}
+
+// SlippageModel moves the price of a simulated taker fill against the order.
+type SlippageModel interface {
+	// Slip returns the execution price of qtySats on side at reference priceMicros.
+	Slip(side string, priceMicros quant.PriceMicros, qtySats int64) quant.PriceMicros
+}
+
+// BpsFee charges a flat rate per liquidity role. MakerBps may be negative (rebate).
+type BpsFee struct {
+	MakerBps int64
+	TakerBps int64
+}
+
+// FeeMicros implements FeeModel.
+func (f BpsFee) FeeMicros(notionalMicros int64, maker bool) int64 {
+	bps := f.TakerBps
+	if maker {
+		bps = f.MakerBps
+	}
+	return safe.SafeMulDiv(notionalMicros, bps, bpsScale)
+}
+
+// SpreadSlippage makes a taker cross half of a fixed bid/ask spread.
+type SpreadSlippage struct {
+	SpreadBps int64 // Full spread around the reference (mid) price
+}
+
+// Slip implements SlippageModel.
+func (s SpreadSlippage) Slip(side string, priceMicros quant.PriceMicros, _ int64) quant.PriceMicros {
+	return adverse(side, priceMicros, safe.SafeMulDiv(int64(priceMicros), s.SpreadBps, 2*bpsScale))
+}
+
+// ImpactSlippage models market impact growing linearly with order size:
+// Bps per LotSats of quantity, capped at MaxBps (0 = uncapped).
+type ImpactSlippage struct {
+	Bps     int64
+	LotSats int64
+	MaxBps  int64
+}
+
+// Slip implements SlippageModel.
+func (s ImpactSlippage) Slip(side string, priceMicros quant.PriceMicros, qtySats int64) quant.PriceMicros {
+	if s.LotSats <= 0 {
+		return priceMicros
+	}
+	bps := safe.SafeMulDiv(s.Bps, qtySats, s.LotSats)
+	if s.MaxBps > 0 && bps > s.MaxBps {
+		bps = s.MaxBps
+	}
+	return adverse(side, priceMicros, safe.SafeMulDiv(int64(priceMicros), bps, bpsScale))
+}
+
+// SlippageChain applies each model in turn to the previous model's price.
+type SlippageChain []SlippageModel
+
+// Slip implements SlippageModel.
+func (c SlippageChain) Slip(side string, priceMicros quant.PriceMicros, qtySats int64) quant.PriceMicros {
+	for _, m := range c {
+		priceMicros = m.Slip(side, priceMicros, qtySats)
+	}
+	return priceMicros
+}
+
+// adverse moves price by delta against side: up for BUY, down for SELL.
+func adverse(side string, price quant.PriceMicros, delta int64) quant.PriceMicros {
+	if side == domain.SideBuy {
+		return quant.PriceMicros(safe.SafeAdd(int64(price), delta))
+	}
+	return quant.PriceMicros(safe.SafeSub(int64(price), delta))
+}
+
+// Costs is what a simulated venue charges on every fill. Nil models cost nothing.
+//
+// With a FeeCurrency (e.g. BGB) fees are paid in that currency, converted at
+// its price in the fill's quote and discounted by FeeDiscountBps, as long as
+// the account holds enough of it; otherwise, and for rebates, in the quote.
+type Costs struct {
+	Fee            FeeModel
+	Slippage       SlippageModel
+	FeeCurrency    string
+	FeeDiscountBps int64
+}
+
+func (c Costs) fee(notionalMicros int64, maker bool) int64 {
+	if c.Fee == nil {
+		return 0
+	}
+	return c.Fee.FeeMicros(notionalMicros, maker)
+}
+
+func (c Costs) slip(side string, priceMicros quant.PriceMicros, qtySats int64) quant.PriceMicros {
+	if c.Slippage == nil {
+		return priceMicros
+	}
+	return c.Slippage.Slip(side, priceMicros, qtySats)
+}
+
+// DefaultCosts are the standard fee tiers per exchange, used when config.yaml
+// has no trading.costs entry for it.
+var DefaultCosts = map[string]infra.CostConfig{
+	"UPBIT":  {MakerBps: 5, TakerBps: 5}, // KRW market 0.05%
+	"BITGET": {MakerBps: 2, TakerBps: 6}, // USDT-M futures 0.02% / 0.06%
+	"OKX":    {MakerBps: 2, TakerBps: 5}, // Swap VIP0 0.02% / 0.05%
+	"PAPER":  {MakerBps: 0, TakerBps: 0}, // Frictionless, for comparison runs
+}
+
+// NewCosts builds the models described by cfg.
+func NewCosts(cfg infra.CostConfig) Costs {
+	var chain SlippageChain
+	if cfg.SpreadBps > 0 {
+		chain = append(chain, SpreadSlippage{SpreadBps: cfg.SpreadBps})
+	}
+	if cfg.ImpactBps > 0 {
+		chain = append(chain, ImpactSlippage{Bps: cfg.ImpactBps, LotSats: cfg.ImpactLotSats, MaxBps: cfg.MaxImpactBps})
+	}
+	c := Costs{
+		Fee:            BpsFee{MakerBps: cfg.MakerBps, TakerBps: cfg.TakerBps},
+		FeeCurrency:    cfg.FeeCurrency,
+		FeeDiscountBps: cfg.FeeDiscountBps,
+	}
+	if len(chain) > 0 {
+		c.Slippage = chain
+	}
+	return c
+}
+
+// CostsFor returns the costs of exchange: the config.yaml entry if present
+// (it replaces the default as a whole), else DefaultCosts, else none.
+func CostsFor(cfg *infra.Config, exchange string) Costs {
+	if c, ok := cfg.Trading.Costs[exchange]; ok {
+		return NewCosts(c)
+	}
+	if c, ok := DefaultCosts[exchange]; ok {
+		return NewCosts(c)
+	}
+	return Costs{}
+}
+
+// TakerBps returns the taker fee of exchange in basis points, from the same
+// source as CostsFor. A market name such as "BITGET_SPOT" falls back to its
+// exchange ("BITGET").
+func TakerBps(cfg *infra.Config, exchange string) int64 {
+	return feeConfig(cfg, exchange).TakerBps
+}
+
+// MakerBps returns the maker fee of exchange in basis points, like TakerBps.
+func MakerBps(cfg *infra.Config, exchange string) int64 {
+	return feeConfig(cfg, exchange).MakerBps
+}
+
+func feeConfig(cfg *infra.Config, exchange string) infra.CostConfig {
+	for _, name := range []string{exchange, strings.SplitN(exchange, "_", 2)[0]} {
+		if c, ok := cfg.Trading.Costs[name]; ok {
+			return c
+		}
+		if c, ok := DefaultCosts[name]; ok {
+			return c
+		}
+	}
+	return infra.CostConfig{}
+}
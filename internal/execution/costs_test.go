@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+)
+
+func TestSlippageModels(t *testing.T) {
+	const price = 50000_000000
+
+	spread := SpreadSlippage{SpreadBps: 10} // Taker pays 5bp
+	if got := spread.Slip(domain.SideBuy, price, 1); got != 50025_000000 {
+		t.Errorf("spread buy: got %d", got)
+	}
+	if got := spread.Slip(domain.SideSell, price, 1); got != 49975_000000 {
+		t.Errorf("spread sell: got %d", got)
+	}
+
+	impact := ImpactSlippage{Bps: 10, LotSats: 100_000000, MaxBps: 15}
+	if got := impact.Slip(domain.SideBuy, price, 50_000000); got != 50025_000000 {
+		t.Errorf("impact 0.5 lot: expected 5bp, got %d", got)
+	}
+	if got := impact.Slip(domain.SideBuy, price, 500_000000); got != 50075_000000 {
+		t.Errorf("impact must be capped at 15bp, got %d", got)
+	}
+
+	chain := NewCosts(infra.CostConfig{SpreadBps: 10, ImpactBps: 10, ImpactLotSats: 100_000000}).Slippage
+	if got := chain.Slip(domain.SideSell, price, 100_000000); got >= 49975_000000 {
+		t.Errorf("chain must apply spread then impact, got %d", got)
+	}
+}
+
+func TestCostsFor(t *testing.T) {
+	cfg := &infra.Config{}
+	if fee := CostsFor(cfg, "BITGET").fee(1000_000000, false); fee != 600000 {
+		t.Errorf("default Bitget taker fee: expected 0.6 USDT on 1000, got %d", fee)
+	}
+
+	cfg.Trading.Costs = map[string]infra.CostConfig{"BITGET": {MakerBps: -1, TakerBps: 4}}
+	costs := CostsFor(cfg, "BITGET")
+	if fee := costs.fee(1000_000000, true); fee != -100000 {
+		t.Errorf("configured maker rebate: got %d", fee)
+	}
+	if costs.Slippage != nil {
+		t.Errorf("an entry replaces the default as a whole")
+	}
+	if c := CostsFor(cfg, "NOWHERE"); c.fee(1000_000000, false) != 0 {
+		t.Errorf("unknown exchange must be frictionless")
+	}
+}
+
+func TestPaperExecution_Costs(t *testing.T) {
+	paper := NewPaperExecution(0)
+	paper.SetCosts(NewCosts(infra.CostConfig{MakerBps: 2, TakerBps: 10, SpreadBps: 20}))
+	paper.Deposit("USDT", 10000_000000)
+	paper.UpdatePrice("BTC-USDT", 50000_000000)
+	ctx := context.Background()
+
+	// Taker: 0.1 BTC at 50000 + 10bp half spread = 50050, fee 10bp of 5005
+	buy := domain.Order{ID: "m", Symbol: "BTC-USDT", Side: "BUY", Type: "MARKET", QtySats: 10_000000}
+	if err := paper.ExecuteOrder(ctx, buy); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+	fill := paper.GetFills()[0]
+	if fill.PriceMicros != 50050_000000 || fill.FeeMicros != 5_005000 || fill.Maker {
+		t.Errorf("unexpected taker fill: %+v", fill)
+	}
+	if got := paper.GetBalance("USDT").AmountSats; got != 10000_000000-5005_000000-5_005000 {
+		t.Errorf("unexpected USDT after taker buy: %d", got)
+	}
+
+	// Maker: resting sell at 51000 pays 2bp of 5100 out of the proceeds, no slippage
+	sell := domain.Order{ID: "l", Symbol: "BTC-USDT", Side: "SELL", Type: "LIMIT", PriceMicros: 51000_000000, QtySats: 10_000000}
+	if err := paper.ExecuteOrder(ctx, sell); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+	paper.UpdatePrice("BTC-USDT", 51200_000000)
+	fill = paper.GetFills()[1]
+	if fill.PriceMicros != 51000_000000 || fill.FeeMicros != 1_020000 || !fill.Maker {
+		t.Errorf("unexpected maker fill: %+v", fill)
+	}
+	if got := paper.GetBalance("USDT").AmountSats; got != 10000_000000-5005_000000-5_005000+5100_000000-1_020000 {
+		t.Errorf("unexpected USDT after maker sell: %d", got)
+	}
+}
+
+func TestPaperExecution_FeeCurrency(t *testing.T) {
+	paper := NewPaperExecution(0)
+	paper.SetCosts(NewCosts(infra.CostConfig{MakerBps: -1, TakerBps: 10, FeeCurrency: "BGB", FeeDiscountBps: 2000}))
+	paper.Deposit("USDT", 20000_000000)
+	paper.Deposit("BGB", 5*quant.QtyScale)
+	paper.UpdatePrice("BGB-USDT", 1_000000)
+	paper.UpdatePrice("BTC-USDT", 50000_000000)
+	ctx := context.Background()
+
+	// Taker: 10bp of 5000 USDT = 5 USDT, 20% off when paid in BGB = 4 BGB at 1 USDT
+	buy := domain.Order{ID: "t1", Symbol: "BTC-USDT", Side: "BUY", Type: "MARKET", QtySats: 10_000000}
+	if err := paper.ExecuteOrder(ctx, buy); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+	fill := paper.GetFills()[0]
+	if fill.FeeMicros != 4_000000 || fill.FeeCurrency != "BGB" || fill.FeeAmount != 4*quant.QtyScale {
+		t.Errorf("unexpected fee-currency fill: %+v", fill)
+	}
+	if usdt, bgb := paper.GetBalance("USDT").AmountSats, paper.GetBalance("BGB").AmountSats; usdt != 15000_000000 || bgb != quant.QtyScale {
+		t.Errorf("USDT %d, BGB %d after a fee-currency buy", usdt, bgb)
+	}
+
+	// 1 BGB left cannot cover the next 4: falls back to the full quote fee
+	buy.ID = "t2"
+	if err := paper.ExecuteOrder(ctx, buy); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+	if fill := paper.GetFills()[1]; fill.FeeMicros != 5_000000 || fill.FeeCurrency != "" {
+		t.Errorf("unexpected quote fallback fill: %+v", fill)
+	}
+	if usdt := paper.GetBalance("USDT").AmountSats; usdt != 15000_000000-5005_000000 {
+		t.Errorf("USDT %d after a quote-fee buy", usdt)
+	}
+
+	// Maker rebate: 1bp of 5100 credited in the quote, never in BGB
+	sell := domain.Order{ID: "m", Symbol: "BTC-USDT", Side: "SELL", Type: "LIMIT", PriceMicros: 51000_000000, QtySats: 10_000000}
+	if err := paper.ExecuteOrder(ctx, sell); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+	paper.UpdatePrice("BTC-USDT", 51000_000000)
+	if fill := paper.GetFills()[2]; fill.FeeMicros != -510000 || fill.FeeCurrency != "" || !fill.Maker {
+		t.Errorf("unexpected rebate fill: %+v", fill)
+	}
+
+	want := map[string]int64{"BGB": 4 * quant.QtyScale, "USDT": 5_000000 - 510000}
+	if got := paper.GetFeeTotals(); !maps.Equal(got, want) {
+		t.Errorf("fee totals %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package execution
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// UpdateEmitter is implemented by executions that report their own order
+// updates to the sequencer (LiveExecution); the Dispatcher then only reports
+// failures.
+type UpdateEmitter interface {
+	EmitsOrderUpdates() bool
+}
+
+// Dispatcher is the execution gateway behind the OMS: it drains the order request
+// queue into an Execution venue and reports each outcome to the sequencer, an ACK
+// OrderUpdateEvent on success or an OrderRejectedEvent on failure. It runs in its
+// own goroutine, so venue latency never blocks the hotpath.
+//
+// Cancel requests (the kill switch) go to the same venue; a successful cancel is
+// reported as a CANCELED OrderUpdateEvent, a failed one is only logged: the
+// order stays open until the venue reports otherwise, and the OMS repeats the
+// cancel meanwhile. Run a second Run on the OMS cancel lane, so cancels never
+// wait behind new orders; executions must allow both at once.
+//
+// Requests routed to a venue (OrderRequestEvent.Exchange) are placed on the
+// execution registered for it with AddVenue; one routed to a venue without an
+// execution is rejected as MARKET_UNAVAILABLE rather than placed elsewhere.
+type Dispatcher struct {
+	exec     domain.Execution
+	exchange string
+	venues   map[string]domain.Execution // Routed venues (optional)
+	inbox    chan<- event.Event
+	nextSeq  *uint64 // ORDER source sequence, shared with other order event producers
+}
+
+// NewDispatcher creates a gateway that places orders on exec and reports as exchange.
+func NewDispatcher(exec domain.Execution, exchange string, inbox chan<- event.Event, seq *uint64) *Dispatcher {
+	return &Dispatcher{exec: exec, exchange: exchange, venues: make(map[string]domain.Execution), inbox: inbox, nextSeq: seq}
+}
+
+// AddVenue places requests routed to exchange on exec. Must be called before Run.
+func (d *Dispatcher) AddVenue(exchange string, exec domain.Execution) {
+	d.venues[exchange] = exec
+}
+
+// route returns the execution of a request and the venue it reports as.
+func (d *Dispatcher) route(req *event.OrderRequestEvent) (domain.Execution, string) {
+	if req.Exchange == "" {
+		return d.exec, d.exchange
+	}
+	return d.venues[req.Exchange], req.Exchange
+}
+
+// Run places requests one at a time, in queue order, until ctx ends.
+func (d *Dispatcher) Run(ctx context.Context, requests <-chan *event.OrderRequestEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-requests:
+			d.place(ctx, req)
+		}
+	}
+}
+
+func (d *Dispatcher) place(ctx context.Context, req *event.OrderRequestEvent) {
+	if req.Cancel {
+		d.cancel(ctx, req)
+		return
+	}
+	order := req.Order()
+	exec, exchange := d.route(req)
+
+	var err error
+	if exec == nil {
+		err = &domain.ExchangeError{Venue: exchange, Kind: domain.RejectMarketUnavailable, Msg: "no execution for routed venue"}
+	} else {
+		err = exec.ExecuteOrder(ctx, order)
+	}
+
+	var ev event.Event
+	if err != nil {
+		slog.Warn("ORDER_REJECTED", slog.String("order_id", order.ID), slog.String("strategy", order.Strategy), slog.String("exchange", exchange), slog.Any("error", err))
+		rej := NewOrderRejectedEvent(order, exchange, err)
+		rej.Seq = quant.NextSeq(d.nextSeq)
+		ev = rej
+	} else if u, ok := exec.(UpdateEmitter); ok && u.EmitsOrderUpdates() {
+		return // The execution already reported the ACK
+	} else {
+		ack := event.AcquireOrderUpdateEvent()
+		ack.Seq = quant.NextSeq(d.nextSeq)
+		ack.Ts = quant.TimeStamp(time.Now().UnixMicro())
+		ack.OrderID = order.ID
+		ack.Status = domain.OrderStatusAcked
+		ack.PriceMicros = req.PriceMicros
+		ev = ack
+	}
+
+	// Order outcomes must not be dropped: wait for room
+	select {
+	case d.inbox <- ev:
+	case <-ctx.Done():
+	}
+}
+
+// cancel cancels req.OrderID at its venue and reports CANCELED unless the
+// execution reports its own updates.
+func (d *Dispatcher) cancel(ctx context.Context, req *event.OrderRequestEvent) {
+	exec, exchange := d.route(req)
+	if exec == nil {
+		slog.Warn("CANCEL_FAILED", slog.String("order_id", req.OrderID), slog.String("exchange", exchange), slog.String("error", "no execution for routed venue"))
+		return
+	}
+	if err := exec.CancelOrder(ctx, req.OrderID, req.Symbol); err != nil {
+		slog.Warn("CANCEL_FAILED", slog.String("order_id", req.OrderID), slog.String("exchange", exchange), slog.Any("error", err))
+		return
+	}
+	if u, ok := exec.(UpdateEmitter); ok && u.EmitsOrderUpdates() {
+		return
+	}
+
+	ev := event.AcquireOrderUpdateEvent()
+	ev.Seq = quant.NextSeq(d.nextSeq)
+	ev.Ts = quant.TimeStamp(time.Now().UnixMicro())
+	ev.OrderID = req.OrderID
+	ev.Status = domain.OrderStatusCanceled
+	select {
+	case d.inbox <- ev:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,111 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+)
+
+// failingExecution refuses every order with err.
+type failingExecution struct {
+	MockExecution
+	err error
+}
+
+func (f *failingExecution) ExecuteOrder(context.Context, domain.Order) error { return f.err }
+
+func TestDispatcher_ReportsOutcome(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inbox := make(chan event.Event, 2)
+	requests := make(chan *event.OrderRequestEvent, 2)
+	seq := new(uint64)
+
+	go NewDispatcher(NewMockExecution(), "MOCK", inbox, seq).Run(ctx, requests)
+	requests <- &event.OrderRequestEvent{OrderID: "a", Symbol: "BTC", PriceMicros: 100}
+
+	ack, ok := (<-inbox).(*event.OrderUpdateEvent)
+	if !ok || ack.OrderID != "a" || ack.Status != domain.OrderStatusAcked || ack.Seq != 1 {
+		t.Fatalf("expected ACK with seq 1, got %+v", ack)
+	}
+
+	refused := &failingExecution{err: &domain.ExchangeError{Venue: "MOCK", Code: "43012", Kind: domain.RejectInsufficientFunds}}
+	refusedRequests := make(chan *event.OrderRequestEvent, 1)
+	go NewDispatcher(refused, "MOCK", inbox, seq).Run(ctx, refusedRequests)
+	refusedRequests <- &event.OrderRequestEvent{OrderID: "b", Symbol: "BTC"}
+
+	rej, ok := (<-inbox).(*event.OrderRejectedEvent)
+	if !ok || rej.OrderID != "b" || rej.Reason != domain.RejectInsufficientFunds || rej.Code != "43012" || rej.Seq != 2 {
+		t.Fatalf("expected rejection with seq 2, got %+v", rej)
+	}
+}
+
+func TestDispatcher_SelfReportingExecution(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inbox := make(chan event.Event, 2)
+	requests := make(chan *event.OrderRequestEvent, 1)
+	seq := new(uint64)
+	live := newLiveExecution(&scriptedClient{}, inbox, seq)
+
+	go NewDispatcher(live, "BITGET", inbox, seq).Run(ctx, requests)
+	requests <- &event.OrderRequestEvent{OrderID: "a", Symbol: "BTCUSDT"}
+
+	if ack := (<-inbox).(*event.OrderUpdateEvent); ack.OrderID != "a" || ack.Seq != 1 {
+		t.Fatalf("unexpected update: %+v", ack)
+	}
+	select {
+	case ev := <-inbox:
+		t.Errorf("ACK must be reported once, got another %+v", ev)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestDispatcher_RoutedVenues(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inbox := make(chan event.Event, 2)
+	requests := make(chan *event.OrderRequestEvent, 2)
+	d := NewDispatcher(NewMockExecution(), "BITGET", inbox, new(uint64))
+	d.AddVenue("BITGET_SPOT", NewMockExecution())
+
+	go d.Run(ctx, requests)
+	requests <- &event.OrderRequestEvent{OrderID: "a", Symbol: "BTC", Exchange: "BITGET_SPOT"}
+	requests <- &event.OrderRequestEvent{OrderID: "b", Symbol: "BTC", Exchange: "UPBIT"}
+
+	if ack, ok := (<-inbox).(*event.OrderUpdateEvent); !ok || ack.OrderID != "a" || ack.Status != domain.OrderStatusAcked {
+		t.Fatalf("expected ACK from the registered venue, got %+v", ack)
+	}
+	rej, ok := (<-inbox).(*event.OrderRejectedEvent)
+	if !ok || rej.OrderID != "b" || rej.Exchange != "UPBIT" || rej.Reason != domain.RejectMarketUnavailable {
+		t.Fatalf("a venue without an execution must reject, got %+v", rej)
+	}
+}
+
+func TestDispatcher_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inbox := make(chan event.Event, 2)
+	requests := make(chan *event.OrderRequestEvent, 2)
+	seq := new(uint64)
+	go NewDispatcher(NewMockExecution(), "MOCK", inbox, seq).Run(ctx, requests)
+
+	requests <- &event.OrderRequestEvent{OrderID: "a", Symbol: "BTC", Cancel: true}
+	if u, ok := (<-inbox).(*event.OrderUpdateEvent); !ok || u.OrderID != "a" || u.Status != domain.OrderStatusCanceled || u.Seq != 1 {
+		t.Fatalf("expected CANCELED with seq 1, got %+v", u)
+	}
+
+	// A routed order without an execution cannot be canceled: nothing is reported
+	requests <- &event.OrderRequestEvent{OrderID: "b", Symbol: "BTC", Exchange: "UPBIT", Cancel: true}
+	requests <- &event.OrderRequestEvent{OrderID: "c", Symbol: "BTC", Cancel: true}
+	if u := (<-inbox).(*event.OrderUpdateEvent); u.OrderID != "c" {
+		t.Errorf("expected only c to be reported, got %+v", u)
+	}
+}
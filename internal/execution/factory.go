@@ -1,14 +1,15 @@
 package execution
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"os"
 
 	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
 	"crypto_go/internal/infra"
 	"crypto_go/internal/infra/bitget"
+	"crypto_go/internal/orderbook"
 	"crypto_go/pkg/quant"
 )
 
@@ -24,6 +25,10 @@ const (
 // ExecutionFactory creates execution instances based on mode
 type ExecutionFactory struct {
 	config *infra.Config
+
+	// Order update sink for live executions (optional)
+	inbox   chan<- event.Event
+	nextSeq *uint64
 }
 
 // NewExecutionFactory creates a new factory
@@ -31,6 +36,13 @@ func NewExecutionFactory(cfg *infra.Config) *ExecutionFactory {
 	return &ExecutionFactory{config: cfg}
 }
 
+// SetEventSink makes live and paper executions report order updates to inbox, stamped
+// from seq (the ORDER source sequence). Must be called before CreateExecution.
+func (f *ExecutionFactory) SetEventSink(inbox chan<- event.Event, seq *uint64) {
+	f.inbox = inbox
+	f.nextSeq = seq
+}
+
 // CreateExecution returns the appropriate Execution implementation
 func (f *ExecutionFactory) CreateExecution() (domain.Execution, error) {
 	mode := Mode(f.config.Trading.Mode)
@@ -41,7 +53,26 @@ func (f *ExecutionFactory) CreateExecution() (domain.Execution, error) {
 	case ModePaper:
 		// Paper Trading: Start with 100M KRW virtual balance
 		initialBalance := quant.ToPriceMicros(100_000_000.0)
-		return NewPaperExecution(initialBalance), nil
+		paper := NewPaperExecution(initialBalance)
+		// Fees and slippage of the venue being simulated
+		venue := f.config.Trading.PaperExchange
+		if venue == "" {
+			venue = "BITGET"
+		}
+		paper.SetCosts(CostsFor(f.config, venue))
+		// Passive fills from the recorded book (seed fixed: same run, same fills)
+		if path := f.config.Trading.FillModel; path != "" {
+			model, err := orderbook.LoadFillModel(path)
+			if err != nil {
+				return nil, err
+			}
+			paper.SetFillModel(model, 1)
+			slog.Info("Paper fill model loaded", slog.String("path", path), slog.Int64("samples", model.Samples))
+		}
+		if f.inbox != nil {
+			paper.SetEventSink(f.inbox, f.nextSeq)
+		}
+		return paper, nil
 
 	case ModeDemo:
 		// Demo Trading: Connect to Bitget Testnet
@@ -61,7 +92,7 @@ func (f *ExecutionFactory) CreateExecution() (domain.Execution, error) {
 		f.config.API.Bitget.Passphrase = secretCfg.API.Bitget.Passphrase
 
 		client := bitget.NewClient(f.config, true) // true = Testnet
-		return NewRealExecution(client), nil
+		return NewLiveExecution(client, f.inbox, f.nextSeq), nil
 
 	case ModeReal:
 		// Real Trading: SAFETY LATCH CHECK
@@ -82,36 +113,9 @@ func (f *ExecutionFactory) CreateExecution() (domain.Execution, error) {
 		f.config.API.Bitget.Passphrase = secretCfg.API.Bitget.Passphrase
 
 		client := bitget.NewClient(f.config, false) // false = Mainnet
-		return NewRealExecution(client), nil
+		return NewLiveExecution(client, f.inbox, f.nextSeq), nil
 
 	default:
 		return nil, fmt.Errorf("unknown execution mode: %s", mode)
 	}
 }
-
-// RealExecution adapts a real exchange client to the Execution interface
-// This is a placeholder for now, ensuring the skeleton exists.
-type RealExecution struct {
-	client *bitget.Client
-}
-
-func NewRealExecution(client *bitget.Client) *RealExecution {
-	return &RealExecution{client: client}
-}
-
-// Implement ExecuteOrder interface (Skeleton -> Real)
-func (e *RealExecution) ExecuteOrder(ctx context.Context, order domain.Order) error {
-	slog.Info("🚀 Sending Real/Testnet Order", "symbol", order.Symbol, "qty", order.QtySats, "price", order.PriceMicros)
-	return e.client.PlaceOrder(ctx, order)
-}
-
-// Implement CancelOrder interface (Skeleton -> Real)
-func (e *RealExecution) CancelOrder(ctx context.Context, orderID string, symbol string) error {
-	slog.Info("🗑️ Canceling Real/Testnet Order", "oid", orderID, "symbol", symbol)
-	return e.client.CancelOrder(ctx, orderID, symbol)
-}
-
-// Close cleans up resources.
-func (e *RealExecution) Close() error {
-	return e.client.Close()
-}
@@ -0,0 +1,282 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra/bitget"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// Retry defaults for LiveExecution.
+const (
+	DefaultLiveMaxRetries = 3
+	DefaultLiveBackoff    = 200 * time.Millisecond
+)
+
+// orderClient is the part of bitget.Client used for execution.
+type orderClient interface {
+	PlaceOrder(ctx context.Context, order domain.Order) error
+	CancelOrder(ctx context.Context, orderID string, symbol string) error
+	CancelSpotOrder(ctx context.Context, orderID string, symbol string) error
+	GetOrderDetail(ctx context.Context, symbol, clientOid string) (bitget.OrderDetail, error)
+	GetSpotOrderDetail(ctx context.Context, clientOid string) (bitget.OrderDetail, error)
+	Close() error
+}
+
+// DefaultLivePollInterval is how often Run checks open orders for fills.
+const DefaultLivePollInterval = time.Second
+
+// LiveExecution places orders on Bitget (mainnet or demo, per the client).
+// Implements domain.Execution.
+//
+// Every order carries a clientOid, generated once if the caller left the ID
+// empty, and the same clientOid is reused on every retry: a request that timed
+// out after reaching the exchange cannot be placed twice, and the exchange's
+// duplicate-clientOid refusal on the retry counts as success. Only retriable
+// NetworkErrors are retried; business rejections return immediately.
+//
+// With an inbox, accepted orders are reported to the sequencer as ACK
+// OrderUpdateEvents under the ORDER source sequence, and Run polls every open
+// order's venue state by clientOid: fills are reported as PARTIALLY_FILLED /
+// FILLED with the accumulated quantity and the price of the new fills, and a
+// cancel only as CANCELED once the venue shows the order canceled.
+type LiveExecution struct {
+	client     orderClient
+	inbox      chan<- event.Event // nil = do not report
+	nextSeq    *uint64
+	maxRetries int
+	backoff    time.Duration // Multiplied by the attempt number
+
+	idSeq atomic.Uint64
+	mu    sync.Mutex
+	open  map[string]*liveOrder // Placed orders the venue has not finished, by clientOid
+}
+
+// liveOrder is what LiveExecution knows of an open order.
+type liveOrder struct {
+	symbol     string
+	spot       bool  // Placed on the spot market (futures is the default)
+	filledSats int64 // Accumulated fill already reported
+	notional   int64 // Quote micros of filledSats at the venue's average price
+}
+
+// NewLiveExecution creates a live executor. inbox may be nil.
+func NewLiveExecution(client *bitget.Client, inbox chan<- event.Event, seq *uint64) *LiveExecution {
+	return newLiveExecution(client, inbox, seq)
+}
+
+func newLiveExecution(client orderClient, inbox chan<- event.Event, seq *uint64) *LiveExecution {
+	if seq == nil {
+		seq = new(uint64)
+	}
+	return &LiveExecution{
+		client:     client,
+		inbox:      inbox,
+		nextSeq:    seq,
+		maxRetries: DefaultLiveMaxRetries,
+		backoff:    DefaultLiveBackoff,
+		open:       make(map[string]*liveOrder),
+	}
+}
+
+// SetRetry changes how often (maxRetries after the first attempt) and how
+// patiently retriable failures are retried.
+func (e *LiveExecution) SetRetry(maxRetries int, backoff time.Duration) {
+	e.maxRetries = maxRetries
+	e.backoff = backoff
+}
+
+// EmitsOrderUpdates reports whether this executor publishes its own order updates.
+func (e *LiveExecution) EmitsOrderUpdates() bool {
+	return e.inbox != nil
+}
+
+// ExecuteOrder places order, retrying retriable network failures.
+func (e *LiveExecution) ExecuteOrder(ctx context.Context, order domain.Order) error {
+	if order.ID == "" {
+		order.ID = e.newClientOid()
+	}
+
+	err := e.retry(ctx, "place", func() error {
+		return e.client.PlaceOrder(ctx, order)
+	}, bitget.IsDuplicateClientOid)
+	if err != nil {
+		return fmt.Errorf("live order %s: %w", order.ID, err)
+	}
+
+	e.mu.Lock()
+	e.open[order.ID] = &liveOrder{symbol: order.Symbol, spot: order.Market == domain.MarketSpot}
+	e.mu.Unlock()
+
+	e.report(ctx, order.ID, domain.OrderStatusAcked, order.PriceMicros, 0)
+	return nil
+}
+
+// CancelOrder cancels by clientOid, on the market the order was placed on
+// (futures for orders this executor did not place). The order is reported
+// CANCELED (or FILLED, if the venue filled it first) only once the venue's
+// order state confirms it; until then Run keeps polling.
+func (e *LiveExecution) CancelOrder(ctx context.Context, orderID string, symbol string) error {
+	e.mu.Lock()
+	o := e.open[orderID]
+	e.mu.Unlock()
+
+	cancel := e.client.CancelOrder
+	if o != nil && o.spot {
+		cancel = e.client.CancelSpotOrder
+	}
+	err := e.retry(ctx, "cancel", func() error { return cancel(ctx, orderID, symbol) }, nil)
+	if o != nil {
+		// Filled before the cancel landed, or canceled: either way the venue knows
+		e.poll(ctx, orderID, o)
+	}
+	if err != nil {
+		return fmt.Errorf("live cancel %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// Run polls the open orders every interval (0 = DefaultLivePollInterval)
+// until ctx ends. Only needed with an inbox.
+func (e *LiveExecution) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultLivePollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Poll(ctx)
+		}
+	}
+}
+
+// Poll checks every open order once and reports what changed.
+func (e *LiveExecution) Poll(ctx context.Context) {
+	e.mu.Lock()
+	open := maps.Clone(e.open)
+	e.mu.Unlock()
+	for id, o := range open {
+		if ctx.Err() != nil {
+			return
+		}
+		e.poll(ctx, id, o)
+	}
+}
+
+// poll fetches one order's venue state and reports new fills and its end.
+// The Dispatcher (cancels) and Run may poll the same order: o is guarded by
+// e.mu, and only the poll that finishes it reports its end.
+func (e *LiveExecution) poll(ctx context.Context, id string, o *liveOrder) {
+	var d bitget.OrderDetail
+	var err error
+	if o.spot {
+		d, err = e.client.GetSpotOrderDetail(ctx, id)
+	} else {
+		d, err = e.client.GetOrderDetail(ctx, o.symbol, id)
+	}
+	if err != nil {
+		slog.Warn("LIVE_ORDER_POLL_FAILED", slog.String("order_id", id), slog.Any("error", err))
+		return
+	}
+
+	e.mu.Lock()
+	if e.open[id] != o {
+		e.mu.Unlock()
+		return // Finished by a concurrent poll
+	}
+	var status string
+	var price int64
+	if d.FilledSats > o.filledSats {
+		// Price of the new fills: venue average over the whole fill, minus what was reported
+		notional := safe.SafeMulDiv(d.AvgPriceMicros, d.FilledSats, quant.QtyScale)
+		price = safe.SafeMulDiv(notional-o.notional, quant.QtyScale, d.FilledSats-o.filledSats)
+		if price <= 0 {
+			price = d.AvgPriceMicros
+		}
+		o.filledSats, o.notional = d.FilledSats, notional
+		status = domain.OrderStatusPartiallyFilled
+	}
+	switch d.State {
+	case bitget.OrderStateFilled:
+		status = domain.OrderStatusFilled
+	case bitget.OrderStateCanceled:
+		status = domain.OrderStatusCanceled
+	}
+	if status == domain.OrderStatusFilled || status == domain.OrderStatusCanceled {
+		delete(e.open, id)
+	}
+	filled := o.filledSats
+	e.mu.Unlock()
+
+	if status != "" {
+		e.report(ctx, id, status, price, filled)
+	}
+}
+
+// Close wipes the client's secrets.
+func (e *LiveExecution) Close() error {
+	return e.client.Close()
+}
+
+// retry runs call until it succeeds, fails for good or runs out of attempts.
+// done, if set, recognises a retry's error that proves an earlier attempt landed.
+func (e *LiveExecution) retry(ctx context.Context, op string, call func() error, done func(error) bool) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = call()
+		if err == nil {
+			return nil
+		}
+		if attempt > 0 && done != nil && done(err) {
+			return nil
+		}
+		if !domain.IsRetriable(err) || attempt >= e.maxRetries {
+			return err
+		}
+
+		slog.Warn("LIVE_EXECUTION_RETRY", slog.String("op", op), slog.Int("attempt", attempt+1), slog.Any("error", err))
+		select {
+		case <-time.After(e.backoff * time.Duration(attempt+1)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// newClientOid returns a process-unique client order ID.
+func (e *LiveExecution) newClientOid() string {
+	return "live-" + strconv.FormatInt(time.Now().UnixMicro(), 36) + "-" + strconv.FormatUint(e.idSeq.Add(1), 36)
+}
+
+// report sends an order update to the sequencer, waiting for room: order
+// state must not be dropped.
+func (e *LiveExecution) report(ctx context.Context, orderID, status string, priceMicros, filledSats int64) {
+	if e.inbox == nil {
+		return
+	}
+	ev := event.AcquireOrderUpdateEvent()
+	ev.Seq = quant.NextSeq(e.nextSeq)
+	ev.Ts = quant.TimeStamp(time.Now().UnixMicro())
+	ev.OrderID = orderID
+	ev.Status = status
+	ev.PriceMicros = quant.PriceMicros(priceMicros)
+	ev.AccumulatedQtySats = quant.QtySats(filledSats)
+	select {
+	case e.inbox <- ev:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,207 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra/bitget"
+)
+
+// scriptedClient answers PlaceOrder with the next scripted error, order
+// details from details and records calls.
+type scriptedClient struct {
+	placeErrs []error
+	placed    []domain.Order
+	canceled  []string // "futures:<id>" / "spot:<id>"
+	details   map[string]bitget.OrderDetail
+}
+
+func (c *scriptedClient) PlaceOrder(_ context.Context, order domain.Order) error {
+	c.placed = append(c.placed, order)
+	if len(c.placeErrs) == 0 {
+		return nil
+	}
+	err := c.placeErrs[0]
+	c.placeErrs = c.placeErrs[1:]
+	return err
+}
+
+func (c *scriptedClient) CancelOrder(_ context.Context, orderID, _ string) error {
+	c.canceled = append(c.canceled, "futures:"+orderID)
+	return nil
+}
+
+func (c *scriptedClient) CancelSpotOrder(_ context.Context, orderID, _ string) error {
+	c.canceled = append(c.canceled, "spot:"+orderID)
+	return nil
+}
+
+func (c *scriptedClient) GetOrderDetail(_ context.Context, _, clientOid string) (bitget.OrderDetail, error) {
+	return c.detail(clientOid)
+}
+
+func (c *scriptedClient) GetSpotOrderDetail(_ context.Context, clientOid string) (bitget.OrderDetail, error) {
+	return c.detail(clientOid)
+}
+
+func (c *scriptedClient) detail(clientOid string) (bitget.OrderDetail, error) {
+	d, ok := c.details[clientOid]
+	if !ok {
+		return bitget.OrderDetail{ClientOid: clientOid, State: bitget.OrderStateLive}, nil
+	}
+	return d, nil
+}
+
+func (c *scriptedClient) Close() error { return nil }
+
+func newTestLive(client *scriptedClient, inbox chan<- event.Event) *LiveExecution {
+	e := newLiveExecution(client, inbox, new(uint64))
+	e.SetRetry(2, 0)
+	return e
+}
+
+func TestLiveExecution_RetriesWithSameClientOid(t *testing.T) {
+	timeout := domain.NewNetworkError("POST /api/v2/mix/order/place-order", errors.New("timeout"))
+	client := &scriptedClient{placeErrs: []error{timeout, timeout}}
+	inbox := make(chan event.Event, 1)
+	e := newTestLive(client, inbox)
+
+	if err := e.ExecuteOrder(context.Background(), domain.Order{Symbol: "BTCUSDT", PriceMicros: 100}); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+	if len(client.placed) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(client.placed))
+	}
+	id := client.placed[0].ID
+	if !strings.HasPrefix(id, "live-") || client.placed[1].ID != id || client.placed[2].ID != id {
+		t.Errorf("retries must reuse the generated clientOid: %s, %s, %s", id, client.placed[1].ID, client.placed[2].ID)
+	}
+
+	ack := (<-inbox).(*event.OrderUpdateEvent)
+	if ack.OrderID != id || ack.Status != domain.OrderStatusAcked || ack.Seq != 1 {
+		t.Errorf("unexpected update: %+v", ack)
+	}
+}
+
+func TestLiveExecution_DuplicateOnRetryIsSuccess(t *testing.T) {
+	timeout := domain.NewNetworkError("POST", errors.New("timeout"))
+	duplicate := &domain.ExchangeError{Venue: "BITGET", Code: "40786", Msg: "Duplicate clientOid"}
+	client := &scriptedClient{placeErrs: []error{timeout, duplicate}}
+	e := newTestLive(client, nil)
+
+	if err := e.ExecuteOrder(context.Background(), domain.Order{ID: "oid", Symbol: "BTCUSDT"}); err != nil {
+		t.Fatalf("duplicate clientOid after a lost response must count as placed: %v", err)
+	}
+	if client.placed[0].ID != "oid" {
+		t.Errorf("caller's ID must be kept, got %s", client.placed[0].ID)
+	}
+}
+
+func TestLiveExecution_NoRetryOnRejection(t *testing.T) {
+	rejected := &domain.ExchangeError{Venue: "BITGET", Code: "43012", Kind: domain.RejectInsufficientFunds}
+	client := &scriptedClient{placeErrs: []error{rejected}}
+	e := newTestLive(client, nil)
+
+	err := e.ExecuteOrder(context.Background(), domain.Order{ID: "oid", Symbol: "BTCUSDT"})
+	if !errors.Is(err, domain.ErrInsufficientFunds) || len(client.placed) != 1 {
+		t.Errorf("business rejection must not be retried: err=%v attempts=%d", err, len(client.placed))
+	}
+
+	// Retries are bounded
+	fail := domain.NewNetworkError("POST", errors.New("reset"))
+	client = &scriptedClient{placeErrs: []error{fail, fail, fail, fail}}
+	e = newTestLive(client, nil)
+	if err := e.ExecuteOrder(context.Background(), domain.Order{ID: "oid"}); !domain.IsRetriable(err) || len(client.placed) != 3 {
+		t.Errorf("expected the network error after 3 attempts: err=%v attempts=%d", err, len(client.placed))
+	}
+}
+
+func TestLiveExecution_CancelRoutesByMarket(t *testing.T) {
+	client := &scriptedClient{details: map[string]bitget.OrderDetail{
+		"s": {State: bitget.OrderStateCanceled},
+	}}
+	inbox := make(chan event.Event, 4)
+	e := newTestLive(client, inbox)
+
+	ctx := context.Background()
+	_ = e.ExecuteOrder(ctx, domain.Order{ID: "s", Symbol: "BTCUSDT", Market: domain.MarketSpot})
+	_ = e.ExecuteOrder(ctx, domain.Order{ID: "f", Symbol: "BTCUSDT"})
+	if err := e.CancelOrder(ctx, "s", "BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.CancelOrder(ctx, "f", "BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.canceled) != 2 || client.canceled[0] != "spot:s" || client.canceled[1] != "futures:f" {
+		t.Errorf("unexpected cancels: %v", client.canceled)
+	}
+	<-inbox
+	<-inbox
+	if u := (<-inbox).(*event.OrderUpdateEvent); u.OrderID != "s" || u.Status != domain.OrderStatusCanceled {
+		t.Errorf("unexpected cancel update: %+v", u)
+	}
+	if len(inbox) != 0 {
+		t.Errorf("f is still live at the venue: no update until the cancel is confirmed, got %+v", <-inbox)
+	}
+}
+
+func TestLiveExecution_PollReportsFills(t *testing.T) {
+	client := &scriptedClient{details: map[string]bitget.OrderDetail{}}
+	inbox := make(chan event.Event, 8)
+	e := newTestLive(client, inbox)
+	ctx := context.Background()
+	_ = e.ExecuteOrder(ctx, domain.Order{ID: "o", Symbol: "BTCUSDT", QtySats: 300_000_000})
+	<-inbox // ACK
+
+	next := func() *event.OrderUpdateEvent {
+		t.Helper()
+		e.Poll(ctx)
+		if len(inbox) == 0 {
+			return nil
+		}
+		return (<-inbox).(*event.OrderUpdateEvent)
+	}
+	if u := next(); u != nil {
+		t.Fatalf("nothing filled yet, got %+v", u)
+	}
+
+	client.details["o"] = bitget.OrderDetail{State: bitget.OrderStatePartiallyFilled, FilledSats: 100_000_000, AvgPriceMicros: 100_000_000}
+	u := next()
+	if u == nil || u.Status != domain.OrderStatusPartiallyFilled || u.AccumulatedQtySats != 100_000_000 || u.PriceMicros != 100_000_000 {
+		t.Fatalf("unexpected partial fill: %+v", u)
+	}
+	if u := next(); u != nil {
+		t.Fatalf("an unchanged order must not be reported again, got %+v", u)
+	}
+
+	// 2 more at 130: the average over 3 is 120
+	client.details["o"] = bitget.OrderDetail{State: bitget.OrderStateFilled, FilledSats: 300_000_000, AvgPriceMicros: 120_000_000}
+	u = next()
+	if u == nil || u.Status != domain.OrderStatusFilled || u.AccumulatedQtySats != 300_000_000 || u.PriceMicros != 130_000_000 {
+		t.Fatalf("unexpected fill: %+v", u)
+	}
+	if u := next(); u != nil {
+		t.Fatalf("a finished order must not be polled, got %+v", u)
+	}
+}
+
+func TestLiveExecution_CancelAfterFill(t *testing.T) {
+	client := &scriptedClient{details: map[string]bitget.OrderDetail{
+		"o": {State: bitget.OrderStateFilled, FilledSats: 5, AvgPriceMicros: 100},
+	}}
+	inbox := make(chan event.Event, 4)
+	e := newTestLive(client, inbox)
+	ctx := context.Background()
+	_ = e.ExecuteOrder(ctx, domain.Order{ID: "o", Symbol: "BTCUSDT"})
+	<-inbox // ACK
+
+	_ = e.CancelOrder(ctx, "o", "BTCUSDT")
+	if u := (<-inbox).(*event.OrderUpdateEvent); u.Status != domain.OrderStatusFilled || u.AccumulatedQtySats != 5 {
+		t.Errorf("a cancel of a filled order must report the fill, got %+v", u)
+	}
+}
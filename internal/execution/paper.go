@@ -3,10 +3,13 @@ package execution
 import (
 	"context"
 	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
 	"crypto_go/pkg/quant"
 	"crypto_go/pkg/safe"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math/rand/v2"
 	"strings"
 	"sync"
 	"time"
@@ -19,19 +22,81 @@ type Fill struct {
 	Side         string // "BUY" or "SELL"
 	PriceMicros  quant.PriceMicros
 	QtySats      quant.QtySats
+	FeeMicros    int64  // Fee in quote micros, after any fee-currency discount (negative = rebate)
+	FeeCurrency  string // Currency the fee was paid in instead of the quote ("" = quote)
+	FeeAmount    int64  // Fee in FeeCurrency units (sats)
+	Maker        bool   // Filled from the book (maker fee) rather than on arrival
 	TsUnixMicros int64
 }
 
+// feeCharge is how one fill pays its fee.
+type feeCharge struct {
+	quote    int64  // Out of (negative: rebated to) the quote balance
+	currency string // Fee currency paid instead ("" = none)
+	amount   int64  // In currency units (sats)
+	value    int64  // Quote micros the fee is worth (Fill.FeeMicros)
+}
+
+// FillProbability estimates how likely a passive LIMIT order fills (see
+// orderbook.FillModel).
+type FillProbability interface {
+	// FillProbBps returns the probability (bps) that an order on side resting
+	// distanceBps from mid fills within horizon; false if unknown.
+	FillProbBps(side string, distanceBps int64, horizon time.Duration) (int64, bool)
+}
+
 // PaperExecution simulates order execution with virtual balances.
 // This is used for strategy backtesting and pre-production validation.
+//
+// MARKET orders, and LIMIT orders that already cross the current price, fill
+// immediately at the current price. Other LIMIT orders rest in a per-symbol book
+// with their funds reserved, and fill at their limit price when a later price
+// update crosses it, oldest first. UpdateTrade caps the volume one print can
+// fill, so large orders fill partially; UpdatePrice fills in full.
+//
+// Every fill is charged through Costs (see SetCosts): immediate fills pay the
+// taker fee at a slipped price, book fills the maker fee at their limit price.
+// A fee currency other than the quote (Costs.FeeCurrency) is priced from its
+// "<CCY>-<QUOTE>" market and debited from its own balance (see GetFeeTotals).
+//
+// With a fill model (see SetFillModel) a print that only touches a resting
+// order's price no longer fills it outright: the queue ahead of it must have
+// cleared, which the model's probability for the order's distance and age
+// decides. Prints through the price still fill.
 type PaperExecution struct {
 	balances *domain.BalanceBook
 	orders   map[string]*domain.Order
+	book     map[string][]*restingOrder // Resting LIMIT orders by symbol, time priority
 	fills    []Fill
+	costs    Costs
+	fees     map[string]int64 // Net fees charged by currency (negative = rebates)
 	mu       sync.Mutex
 
+	fillModel FillProbability
+	rng       *rand.Rand
+	now       func() time.Time
+
 	// Current market prices for PnL calculation
 	prices map[string]quant.PriceMicros
+
+	// Venue of orders that name none, for unified symbols (see SetDefaultExchange)
+	exchange string
+
+	// Order update sink (optional, see SetEventSink)
+	inbox   chan<- event.Event
+	nextSeq *uint64
+}
+
+// restingOrder is a LIMIT order waiting in the paper book.
+type restingOrder struct {
+	order        *domain.Order
+	base, quote  string
+	filledSats   int64
+	reservedSats int64 // Funds still locked: quote for BUY, base for SELL
+
+	placedAt    time.Time
+	distanceBps int64 // From the last price when placed (fill model)
+	draw        int64 // Uniform in [0, 10000): fills at the touch once the model's probability exceeds it
 }
 
 // NewPaperExecution creates a new paper trading executor.
@@ -43,9 +108,68 @@ func NewPaperExecution(initialBalance quant.PriceMicros) *PaperExecution {
 	return &PaperExecution{
 		balances: balances,
 		orders:   make(map[string]*domain.Order),
+		book:     make(map[string][]*restingOrder),
 		fills:    make([]Fill, 0),
+		fees:     make(map[string]int64),
 		prices:   make(map[string]quant.PriceMicros),
+		now:      time.Now,
+	}
+}
+
+// SetEventSink makes the simulator report acks, fills and cancels to inbox as
+// OrderUpdateEvents, stamped from seq (the ORDER source sequence). Sends block,
+// so do not drive UpdatePrice from the goroutine that drains inbox.
+func (p *PaperExecution) SetEventSink(inbox chan<- event.Event, seq *uint64) {
+	if seq == nil {
+		seq = new(uint64)
 	}
+	p.inbox = inbox
+	p.nextSeq = seq
+}
+
+// EmitsOrderUpdates reports whether the simulator publishes its own order updates.
+func (p *PaperExecution) EmitsOrderUpdates() bool {
+	return p.inbox != nil
+}
+
+// SetCosts sets the fee and slippage models charged on fills (default: none).
+func (p *PaperExecution) SetCosts(c Costs) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.costs = c
+}
+
+// SetFillModel makes touch fills of resting orders follow m, drawing from a
+// generator seeded with seed so a backtest is reproducible (nil = always fill).
+func (p *PaperExecution) SetFillModel(m FillProbability, seed uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fillModel = m
+	p.rng = rand.New(rand.NewPCG(seed, seed))
+}
+
+// SetClock sets the time source of order ages and fill timestamps (backtests
+// pass the replayed time; default time.Now).
+func (p *PaperExecution) SetClock(now func() time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = now
+}
+
+// SetDefaultExchange sets the venue whose quote currency completes a unified
+// order symbol (e.g. "BTC") when the order names no exchange (see PaperSymbol).
+func (p *PaperExecution) SetDefaultExchange(exchange string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exchange = exchange
+}
+
+// Price returns the last price of symbol (BASE-QUOTE) and whether one is known.
+func (p *PaperExecution) Price(symbol string) (quant.PriceMicros, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	price, ok := p.prices[symbol]
+	return price, ok
 }
 
 // Deposit adds funds to the virtual account.
@@ -57,29 +181,42 @@ func (p *PaperExecution) Deposit(symbol string, amountSats int64) {
 	balance.Credit(amountSats, 0)
 }
 
-// UpdatePrice updates current market price for a symbol.
+// UpdatePrice updates current market price for a symbol and fills every
+// resting order it crosses.
 func (p *PaperExecution) UpdatePrice(symbol string, priceMicros quant.PriceMicros) {
+	p.UpdateTrade(symbol, priceMicros, -1)
+}
+
+// UpdateTrade is UpdatePrice for a trade print of qtySats: crossed resting
+// orders share at most qtySats, oldest first (negative = unlimited).
+func (p *PaperExecution) UpdateTrade(symbol string, priceMicros quant.PriceMicros, qtySats int64) {
 	p.mu.Lock()
-	defer p.mu.Unlock()
 	p.prices[symbol] = priceMicros
+	updates := p.matchLocked(symbol, priceMicros, qtySats)
+	p.mu.Unlock()
+
+	p.report(updates)
 }
 
-// ExecuteOrder executes a market order immediately against virtual balance.
-// For MARKET orders, uses current price. For LIMIT orders, uses order price.
+// ExecuteOrder fills a MARKET (or marketable LIMIT) order against the virtual
+// balance, or rests a LIMIT order until the price reaches it.
 func (p *PaperExecution) ExecuteOrder(ctx context.Context, order domain.Order) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	update, err := p.executeLocked(order)
+	p.mu.Unlock()
 
-	// Determine execution price
-	var execPrice quant.PriceMicros
-	if order.Type == "MARKET" {
-		price, ok := p.prices[order.Symbol]
-		if !ok {
-			return fmt.Errorf("no price available for %s", order.Symbol)
-		}
-		execPrice = price
+	if err == nil {
+		p.report([]event.OrderUpdateEvent{update})
+	}
+	return err
+}
+
+func (p *PaperExecution) executeLocked(order domain.Order) (event.OrderUpdateEvent, error) {
+	// Strategy orders carry unified symbols; the book is keyed by BASE-QUOTE
+	if order.Exchange != "" {
+		order.Symbol = PaperSymbol(order.Symbol, order.Exchange)
 	} else {
-		execPrice = quant.PriceMicros(order.PriceMicros)
+		order.Symbol = PaperSymbol(order.Symbol, p.exchange)
 	}
 
 	// Calculate required amount
@@ -87,21 +224,46 @@ func (p *PaperExecution) ExecuteOrder(ctx context.Context, order domain.Order) e
 	// SELL: need base currency (e.g., BTC)
 	parts := strings.SplitN(order.Symbol, "-", 2)
 	if len(parts) != 2 {
-		return fmt.Errorf("invalid symbol format (expected BASE-QUOTE): %s", order.Symbol)
+		return event.OrderUpdateEvent{}, fmt.Errorf("invalid symbol format (expected BASE-QUOTE): %s", order.Symbol)
 	}
 	baseSymbol := parts[0]  // e.g., "BTC" from "BTC-USDT"
 	quoteSymbol := parts[1] // e.g., "USDT" from "BTC-USDT"
 
-	if order.Side == "BUY" {
-		// Need quote currency: price * qty
-		requiredQuote := safe.SafeMul(int64(execPrice), order.QtySats)
-		// Scale down (price is in Micros, qty is in Sats)
-		requiredQuote = safe.SafeDiv(requiredQuote, quant.QtyScale)
+	// Determine execution price
+	price, hasPrice := p.prices[order.Symbol]
+	if order.Type == domain.OrderTypeMarket {
+		if !hasPrice {
+			return event.OrderUpdateEvent{}, fmt.Errorf("no price available for %s", order.Symbol)
+		}
+		return p.fillNowLocked(order, baseSymbol, quoteSymbol, p.costs.slip(order.Side, price, order.QtySats))
+	}
+	limit := quant.PriceMicros(order.PriceMicros)
+	if hasPrice && crosses(order.Side, limit, price) {
+		// Marketable: takes the current price, slipped no further than the limit
+		execPrice := p.costs.slip(order.Side, price, order.QtySats)
+		if !crosses(order.Side, limit, execPrice) {
+			execPrice = limit
+		}
+		return p.fillNowLocked(order, baseSymbol, quoteSymbol, execPrice)
+	}
+	return p.restLocked(order, baseSymbol, quoteSymbol)
+}
+
+// fillNowLocked fills order in full at execPrice, paying the taker fee.
+func (p *PaperExecution) fillNowLocked(order domain.Order, baseSymbol, quoteSymbol string, execPrice quant.PriceMicros) (event.OrderUpdateEvent, error) {
+	// Quote notional: price * qty, scaled down (price is in Micros, qty is in Sats)
+	notional := safe.SafeMulDiv(int64(execPrice), order.QtySats, quant.QtyScale)
+	var fee feeCharge
+
+	if order.Side == domain.SideBuy {
+		// Need quote currency: notional + fee (unless paid in the fee currency)
+		fee = p.feeLocked(quoteSymbol, notional, false)
+		requiredQuote := safe.SafeAdd(notional, fee.quote)
 
 		quoteBalance := p.balances.Get(quoteSymbol)
 		if quoteBalance.AvailableSats() < requiredQuote {
-			return fmt.Errorf("insufficient %s balance: need %d, have %d",
-				quoteSymbol, requiredQuote, quoteBalance.AvailableSats())
+			return event.OrderUpdateEvent{}, paperReject(domain.RejectInsufficientFunds, fmt.Sprintf("insufficient %s balance: need %d, have %d",
+				quoteSymbol, requiredQuote, quoteBalance.AvailableSats()))
 		}
 
 		// Execute: debit quote, credit base
@@ -112,31 +274,22 @@ func (p *PaperExecution) ExecuteOrder(ctx context.Context, order domain.Order) e
 	} else { // SELL
 		baseBalance := p.balances.Get(baseSymbol)
 		if baseBalance.AvailableSats() < order.QtySats {
-			return fmt.Errorf("insufficient %s balance: need %d, have %d",
-				baseSymbol, order.QtySats, baseBalance.AvailableSats())
+			return event.OrderUpdateEvent{}, paperReject(domain.RejectInsufficientFunds, fmt.Sprintf("insufficient %s balance: need %d, have %d",
+				baseSymbol, order.QtySats, baseBalance.AvailableSats()))
 		}
 
-		// Execute: debit base, credit quote
+		// Execute: debit base, credit quote net of the fee
 		baseBalance.Debit(order.QtySats, 0)
-		creditQuote := safe.SafeMul(int64(execPrice), order.QtySats)
-		creditQuote = safe.SafeDiv(creditQuote, quant.QtyScale)
+		fee = p.feeLocked(quoteSymbol, notional, false)
 		quoteBalance := p.balances.Get(quoteSymbol)
-		quoteBalance.Credit(creditQuote, 0)
+		quoteBalance.Credit(safe.SafeSub(notional, fee.quote), 0)
 	}
+	p.payFeeLocked(quoteSymbol, fee)
 
-	// Record fill
-	fill := Fill{
-		OrderID:      order.ID,
-		Symbol:       order.Symbol,
-		Side:         order.Side,
-		PriceMicros:  execPrice,
-		QtySats:      quant.QtySats(order.QtySats),
-		TsUnixMicros: time.Now().UnixMicro(),
-	}
-	p.fills = append(p.fills, fill)
+	p.recordFill(&order, execPrice, order.QtySats, fee, false)
 
 	// Update order status
-	order.Status = "FILLED"
+	order.Status = domain.OrderStatusFilled
 	p.orders[order.ID] = &order
 
 	slog.Info("PAPER EXECUTION: Order Filled",
@@ -144,9 +297,210 @@ func (p *PaperExecution) ExecuteOrder(ctx context.Context, order domain.Order) e
 		slog.String("symbol", order.Symbol),
 		slog.String("side", order.Side),
 		slog.Int64("price", int64(execPrice)),
+		slog.Int64("qty", order.QtySats),
+		slog.Int64("fee", fee.value),
+		slog.String("fee_currency", fee.currency))
+
+	return orderUpdate(order.ID, domain.OrderStatusFilled, execPrice, order.QtySats), nil
+}
+
+// restLocked reserves the funds of a LIMIT order and adds it to the book.
+func (p *PaperExecution) restLocked(order domain.Order, baseSymbol, quoteSymbol string) (event.OrderUpdateEvent, error) {
+	r := &restingOrder{order: &order, base: baseSymbol, quote: quoteSymbol, placedAt: p.now()}
+	if p.fillModel != nil {
+		if last := int64(p.prices[order.Symbol]); last > 0 {
+			gap := last - order.PriceMicros
+			if order.Side == domain.SideSell {
+				gap = -gap
+			}
+			r.distanceBps = safe.SafeMulDiv(max(gap, 0), bpsScale, last)
+		}
+		r.draw = p.rng.Int64N(bpsScale)
+	}
+	lockSymbol := baseSymbol
+	r.reservedSats = order.QtySats
+	if order.Side == domain.SideBuy {
+		lockSymbol = quoteSymbol
+		notional := safe.SafeMulDiv(order.PriceMicros, order.QtySats, quant.QtyScale)
+		r.reservedSats = safe.SafeAdd(notional, max(p.costs.fee(notional, true), 0)) // Maker fee included
+	}
+
+	balance := p.balances.Get(lockSymbol)
+	if balance.AvailableSats() < r.reservedSats {
+		return event.OrderUpdateEvent{}, paperReject(domain.RejectInsufficientFunds, fmt.Sprintf("insufficient %s balance: need %d, have %d",
+			lockSymbol, r.reservedSats, balance.AvailableSats()))
+	}
+	balance.Reserve(r.reservedSats, 0)
+
+	order.Status = domain.OrderStatusNew
+	p.orders[order.ID] = &order
+	p.book[order.Symbol] = append(p.book[order.Symbol], r)
+
+	slog.Info("PAPER EXECUTION: Limit Order Resting",
+		slog.String("id", order.ID),
+		slog.String("symbol", order.Symbol),
+		slog.String("side", order.Side),
+		slog.Int64("price", order.PriceMicros),
 		slog.Int64("qty", order.QtySats))
 
-	return nil
+	return orderUpdate(order.ID, domain.OrderStatusAcked, quant.PriceMicros(order.PriceMicros), 0), nil
+}
+
+// matchLocked fills the resting orders of symbol that price crosses, sharing
+// liquiditySats (negative = unlimited) in time priority. Fills are at the limit price.
+func (p *PaperExecution) matchLocked(symbol string, price quant.PriceMicros, liquiditySats int64) []event.OrderUpdateEvent {
+	var updates []event.OrderUpdateEvent
+	resting := p.book[symbol]
+	kept := resting[:0]
+	for _, r := range resting {
+		o := r.order
+		if liquiditySats == 0 || !crosses(o.Side, quant.PriceMicros(o.PriceMicros), price) ||
+			(int64(price) == o.PriceMicros && !p.touchFillsLocked(r)) {
+			kept = append(kept, r)
+			continue
+		}
+
+		qty := o.QtySats - r.filledSats
+		if liquiditySats > 0 {
+			qty = min(qty, liquiditySats)
+			liquiditySats -= qty
+		}
+		p.fillRestingLocked(r, qty)
+
+		if r.filledSats < o.QtySats {
+			o.Status = domain.OrderStatusPartiallyFilled
+			kept = append(kept, r)
+		} else {
+			o.Status = domain.OrderStatusFilled
+		}
+		updates = append(updates, orderUpdate(o.ID, o.Status, quant.PriceMicros(o.PriceMicros), r.filledSats))
+	}
+	clear(resting[len(kept):]) // Drop references to filled orders
+	if len(kept) == 0 {
+		delete(p.book, symbol)
+	} else {
+		p.book[symbol] = kept
+	}
+	return updates
+}
+
+// touchFillsLocked reports whether a print at r's price fills it: always
+// without a fill model, else once the model's probability for its distance
+// and age exceeds its draw.
+func (p *PaperExecution) touchFillsLocked(r *restingOrder) bool {
+	if p.fillModel == nil {
+		return true
+	}
+	prob, ok := p.fillModel.FillProbBps(r.order.Side, r.distanceBps, p.now().Sub(r.placedAt))
+	return ok && r.draw < prob
+}
+
+// fillRestingLocked settles qty of a resting order at its limit price out of
+// the reserved funds, paying the maker fee. The last fill releases any remainder.
+func (p *PaperExecution) fillRestingLocked(r *restingOrder, qty int64) {
+	o := r.order
+	cost := safe.SafeMulDiv(o.PriceMicros, qty, quant.QtyScale)
+	last := r.filledSats+qty == o.QtySats
+	var fee feeCharge
+
+	if o.Side == domain.SideBuy {
+		fee = p.feeLocked(r.quote, cost, true)
+		quoteBalance := p.balances.Get(r.quote)
+		spend := min(safe.SafeAdd(cost, fee.quote), r.reservedSats)
+		quoteBalance.Release(spend, 0)
+		quoteBalance.Debit(spend, 0)
+		r.reservedSats -= spend
+		if last && r.reservedSats > 0 {
+			quoteBalance.Release(r.reservedSats, 0)
+			r.reservedSats = 0
+		}
+		p.balances.Get(r.base).Credit(qty, 0)
+	} else {
+		baseBalance := p.balances.Get(r.base)
+		baseBalance.Release(qty, 0)
+		baseBalance.Debit(qty, 0)
+		r.reservedSats -= qty
+		fee = p.feeLocked(r.quote, cost, true)
+		p.balances.Get(r.quote).Credit(safe.SafeSub(cost, fee.quote), 0)
+	}
+	p.payFeeLocked(r.quote, fee)
+
+	r.filledSats += qty
+	p.recordFill(o, quant.PriceMicros(o.PriceMicros), qty, fee, true)
+}
+
+// feeLocked prices the fee of a fill worth notional quote micros: in the fee
+// currency, discounted, if it has a price in quote and the account can pay it,
+// else in quote. Call it after the fill's own debits.
+func (p *PaperExecution) feeLocked(quote string, notional int64, maker bool) feeCharge {
+	fee := p.costs.fee(notional, maker)
+	ccy := p.costs.FeeCurrency
+	if fee <= 0 || ccy == "" || ccy == quote {
+		return feeCharge{quote: fee, value: fee}
+	}
+	price := int64(p.prices[ccy+"-"+quote])
+	if price <= 0 {
+		return feeCharge{quote: fee, value: fee}
+	}
+	value := safe.SafeSub(fee, safe.SafeMulDiv(fee, p.costs.FeeDiscountBps, bpsScale))
+	amount := safe.SafeMulDiv(value, quant.QtyScale, price)
+	if p.balances.Get(ccy).AvailableSats() < amount {
+		return feeCharge{quote: fee, value: fee}
+	}
+	return feeCharge{currency: ccy, amount: amount, value: value}
+}
+
+// payFeeLocked debits the fee-currency part of c (the quote part settles with
+// the fill) and adds both to the fee totals.
+func (p *PaperExecution) payFeeLocked(quote string, c feeCharge) {
+	if c.currency != "" {
+		p.balances.Get(c.currency).Debit(c.amount, 0)
+		p.fees[c.currency] = safe.SafeAdd(p.fees[c.currency], c.amount)
+	}
+	if c.quote != 0 {
+		p.fees[quote] = safe.SafeAdd(p.fees[quote], c.quote)
+	}
+}
+
+func (p *PaperExecution) recordFill(order *domain.Order, price quant.PriceMicros, qty int64, fee feeCharge, maker bool) {
+	p.fills = append(p.fills, Fill{
+		OrderID:      order.ID,
+		Symbol:       order.Symbol,
+		Side:         order.Side,
+		PriceMicros:  price,
+		QtySats:      quant.QtySats(qty),
+		FeeMicros:    fee.value,
+		FeeCurrency:  fee.currency,
+		FeeAmount:    fee.amount,
+		Maker:        maker,
+		TsUnixMicros: p.now().UnixMicro(),
+	})
+}
+
+// crosses reports whether a LIMIT order on side at limit trades at price.
+func crosses(side string, limit, price quant.PriceMicros) bool {
+	if side == domain.SideBuy {
+		return price <= limit
+	}
+	return price >= limit
+}
+
+func orderUpdate(orderID, status string, price quant.PriceMicros, filledSats int64) event.OrderUpdateEvent {
+	return event.OrderUpdateEvent{OrderID: orderID, Status: status, PriceMicros: price, AccumulatedQtySats: quant.QtySats(filledSats)}
+}
+
+// report sends updates to the event sink, if any. Called without p.mu held.
+func (p *PaperExecution) report(updates []event.OrderUpdateEvent) {
+	if p.inbox == nil {
+		return
+	}
+	for _, u := range updates {
+		ev := event.AcquireOrderUpdateEvent()
+		*ev = u
+		ev.Seq = quant.NextSeq(p.nextSeq)
+		ev.Ts = quant.TimeStamp(time.Now().UnixMicro())
+		p.inbox <- ev
+	}
 }
 
 // Close implements Execution interface.
@@ -155,25 +509,56 @@ func (p *PaperExecution) Close() error {
 	return nil
 }
 
-// CancelOrder cancels an active order in the virtual simulation.
+// CancelOrder cancels a resting order in the virtual simulation and releases
+// its remaining reserved funds.
 func (p *PaperExecution) CancelOrder(ctx context.Context, orderID string, symbol string) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	order, ok := p.orders[orderID]
 	if !ok {
-		return fmt.Errorf("order not found: %s", orderID)
+		p.mu.Unlock()
+		return paperReject(domain.RejectOrderNotFound, "order not found: "+orderID)
+	}
+	if !order.IsOpen() {
+		p.mu.Unlock()
+		return fmt.Errorf("cannot cancel %s order: %s", strings.ToLower(order.Status), orderID)
 	}
 
-	if order.Status == "FILLED" {
-		return fmt.Errorf("cannot cancel filled order: %s", orderID)
+	var filled int64
+	resting := p.book[order.Symbol]
+	for i, r := range resting {
+		if r.order != order {
+			continue
+		}
+		lockSymbol := r.base
+		if order.Side == domain.SideBuy {
+			lockSymbol = r.quote
+		}
+		p.balances.Get(lockSymbol).Release(r.reservedSats, 0)
+		filled = r.filledSats
+		p.book[order.Symbol] = append(resting[:i], resting[i+1:]...)
+		break
 	}
+	order.Status = domain.OrderStatusCanceled
+	p.mu.Unlock()
 
-	order.Status = "CANCELED"
 	slog.Info("PAPER EXECUTION: Order Canceled", slog.String("id", orderID), slog.String("symbol", symbol)) // Add symbol log
+	p.report([]event.OrderUpdateEvent{orderUpdate(orderID, domain.OrderStatusCanceled, quant.PriceMicros(order.PriceMicros), filled)})
 	return nil
 }
 
+// OpenOrders returns copies of the resting orders, per symbol in time priority.
+func (p *PaperExecution) OpenOrders() []domain.Order {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []domain.Order
+	for _, resting := range p.book {
+		for _, r := range resting {
+			out = append(out, *r.order)
+		}
+	}
+	return out
+}
+
 // GetFills returns all executed fills.
 func (p *PaperExecution) GetFills() []Fill {
 	p.mu.Lock()
@@ -183,6 +568,15 @@ func (p *PaperExecution) GetFills() []Fill {
 	return result
 }
 
+// GetFeeTotals returns the net fees charged per currency, in that currency's
+// balance units (negative = net rebate), for reconciling with the venue's
+// statements.
+func (p *PaperExecution) GetFeeTotals() map[string]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return maps.Clone(p.fees)
+}
+
 // GetBalance returns balance for a symbol.
 func (p *PaperExecution) GetBalance(symbol string) domain.Balance {
 	p.mu.Lock()
@@ -0,0 +1,88 @@
+package execution
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// PaperSymbol returns the BASE-QUOTE symbol the paper book uses for a unified
+// symbol (e.g. "BTC") quoted on exchange: "BTC-KRW" for the KRW venues,
+// "BTC-USDT" otherwise. Symbols that already name their quote are kept.
+func PaperSymbol(symbol, exchange string) string {
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+	quote := domain.QuoteUSDT
+	if class, ok := domain.VenueAssetClass(exchange); ok {
+		quote = class.Quote
+	}
+	return symbol + "-" + quote
+}
+
+type paperPrice struct {
+	symbol string
+	price  quant.PriceMicros
+}
+
+// PaperFeed passes live market prices to a PaperExecution, so its MARKET
+// orders have a price and its resting LIMIT orders fill when the market
+// crosses them. Observe runs on the hotpath and never blocks; Run applies the
+// prices on its own goroutine, because a fill is reported to the sequencer
+// with a blocking send.
+type PaperFeed struct {
+	paper     *PaperExecution
+	exchanges map[string]bool // Venues whose prices are used (empty = all)
+	prices    chan paperPrice
+	dropped   atomic.Uint64
+}
+
+// NewPaperFeed creates a feed of size buffered prices for paper, taking
+// prices from exchanges only (none = every venue). Venues quoting the same
+// currency share a paper book, so list the ones orders are priced on.
+func NewPaperFeed(paper *PaperExecution, size int, exchanges ...string) *PaperFeed {
+	f := &PaperFeed{paper: paper, exchanges: make(map[string]bool), prices: make(chan paperPrice, size)}
+	for _, ex := range exchanges {
+		if ex != "" {
+			f.exchanges[ex] = true
+		}
+	}
+	return f
+}
+
+// Observe takes one market update (Sequencer.SetMarketObserver). A price that
+// does not fit the buffer is dropped: the next one supersedes it.
+func (f *PaperFeed) Observe(e event.MarketUpdateEvent) {
+	if e.PriceMicros <= 0 || (len(f.exchanges) > 0 && !f.exchanges[e.Exchange]) {
+		return
+	}
+	select {
+	case f.prices <- paperPrice{symbol: PaperSymbol(e.Symbol, e.Exchange), price: e.PriceMicros}:
+	default:
+		if f.dropped.Add(1) == 1 {
+			slog.Warn("PAPER_FEED_FULL: dropping prices", slog.String("symbol", e.Symbol), slog.String("exchange", e.Exchange))
+		}
+	}
+}
+
+// Run applies the observed prices until ctx ends.
+func (f *PaperFeed) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case p := <-f.prices:
+			f.paper.UpdatePrice(p.symbol, p.price)
+		}
+	}
+}
+
+// Dropped returns the number of prices dropped on a full buffer.
+func (f *PaperFeed) Dropped() uint64 {
+	return f.dropped.Load()
+}
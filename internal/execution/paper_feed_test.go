@@ -0,0 +1,145 @@
+package execution_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/engine"
+	"crypto_go/internal/event"
+	"crypto_go/internal/execution"
+	"crypto_go/pkg/quant"
+)
+
+func TestPaperSymbol(t *testing.T) {
+	cases := []struct{ symbol, exchange, want string }{
+		{"BTC", "UPBIT", "BTC-KRW"},
+		{"BTC", "BITGET_FUTURES", "BTC-USDT"},
+		{"BTC", "", "BTC-USDT"},
+		{"ETH-USDT", "UPBIT", "ETH-USDT"},
+	}
+	for _, c := range cases {
+		if got := execution.PaperSymbol(c.symbol, c.exchange); got != c.want {
+			t.Errorf("PaperSymbol(%q, %q) = %q, want %q", c.symbol, c.exchange, got, c.want)
+		}
+	}
+}
+
+// scriptedStrategy emits orders on the n-th market update (1-based).
+type scriptedStrategy struct {
+	calls int
+	plan  map[int][]domain.Order
+}
+
+func (s *scriptedStrategy) OnMarketUpdate(_ domain.MarketState, out []domain.Order) int {
+	s.calls++
+	return copy(out, s.plan[s.calls])
+}
+
+func (s *scriptedStrategy) OnOrderUpdate(domain.Order) {}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// paperApp is the app wiring in paper mode: sequencer → OMS → dispatcher →
+// paper, with market prices reaching the paper book through the feed.
+type paperApp struct {
+	seq   *engine.Sequencer
+	paper *execution.PaperExecution
+}
+
+func newPaperApp(t *testing.T, plan map[int][]domain.Order) *paperApp {
+	seq := engine.NewSequencer(16, nil, &scriptedStrategy{plan: plan}, nil)
+	oms := engine.NewOrderManager("t", 8)
+	seq.SetOrderManager(oms)
+
+	orderSeq := new(uint64)
+	paper := execution.NewPaperExecution(quant.PriceMicros(1_000_000_000)) // 1000 USDT
+	paper.SetEventSink(seq.CriticalInbox(), orderSeq)
+	paper.SetDefaultExchange("BITGET_FUTURES")
+	feed := execution.NewPaperFeed(paper, 16, "BITGET_FUTURES")
+	seq.SetMarketObserver(feed.Observe)
+	dispatcher := execution.NewDispatcher(paper, "PAPER", seq.CriticalInbox(), orderSeq)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go seq.Run(ctx)
+	go feed.Run(ctx)
+	go dispatcher.Run(ctx, oms.Requests())
+	go dispatcher.Run(ctx, oms.Cancels())
+	return &paperApp{seq: seq, paper: paper}
+}
+
+func (a *paperApp) tick(exchange string, price int64) {
+	a.seq.Inbox() <- &event.MarketUpdateEvent{Exchange: exchange, Symbol: "BTC", PriceMicros: quant.PriceMicros(price)}
+}
+
+// tickApplied sends a price and waits until the paper book has it.
+func (a *paperApp) tickApplied(t *testing.T, price int64) {
+	t.Helper()
+	a.tick("BITGET_FUTURES", price)
+	waitFor(t, "the price to reach the paper book", func() bool {
+		p, ok := a.paper.Price("BTC-USDT")
+		return ok && p == quant.PriceMicros(price)
+	})
+}
+
+func (a *paperApp) status(id string, want string) func() bool {
+	return func() bool {
+		o, ok := a.seq.GetOrder(id)
+		return ok && o.Status == want
+	}
+}
+
+func TestPaperFeed_MarketOrderFills(t *testing.T) {
+	a := newPaperApp(t, map[int][]domain.Order{
+		2: {{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeMarket, PriceMicros: 100_000_000, QtySats: 100_000_000}},
+	})
+	a.tickApplied(t, 100_000_000)
+	a.tick("BITGET_FUTURES", 100_000_000) // The strategy buys at market
+	waitFor(t, "the market order fill", a.status("t-2-1", domain.OrderStatusFilled))
+
+	if btc := a.paper.GetBalance("BTC"); btc.AvailableSats() != 100_000_000 {
+		t.Errorf("BTC balance = %d sats; want 1 BTC", btc.AvailableSats())
+	}
+	a.tick("UPBIT", 80_000_000) // Outside the feed
+	a.tickApplied(t, 101_000_000)
+	if _, ok := a.paper.Price("BTC-KRW"); ok {
+		t.Error("prices of venues outside the feed must be ignored")
+	}
+}
+
+func TestPaperFeed_RestingLimitFills(t *testing.T) {
+	a := newPaperApp(t, map[int][]domain.Order{
+		2: {{Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 90_000_000, QtySats: 50_000_000}},
+	})
+	a.tickApplied(t, 100_000_000)
+	a.tick("BITGET_FUTURES", 100_000_000) // The strategy bids below the market
+	waitFor(t, "the limit order to rest", a.status("t-2-1", domain.OrderStatusAcked))
+	if usdt := a.paper.GetBalance("USDT"); usdt.AvailableSats() != 955_000_000 {
+		t.Fatalf("available USDT = %d; want 45 USDT reserved", usdt.AvailableSats())
+	}
+
+	a.tickApplied(t, 95_000_000) // Above the limit: still resting
+	if o, _ := a.seq.GetOrder("t-2-1"); o.Status != domain.OrderStatusAcked {
+		t.Fatalf("limit order = %s; want ACKED above its price", o.Status)
+	}
+	a.tick("BITGET_FUTURES", 89_000_000) // Crosses
+	waitFor(t, "the resting limit order fill", a.status("t-2-1", domain.OrderStatusFilled))
+
+	if btc := a.paper.GetBalance("BTC"); btc.AvailableSats() != 50_000_000 {
+		t.Errorf("BTC balance = %d sats; want 0.5 BTC", btc.AvailableSats())
+	}
+	if len(a.paper.OpenOrders()) != 0 || len(a.seq.GetOpenOrders()) != 0 {
+		t.Error("the filled order must leave both books")
+	}
+}
@@ -3,7 +3,10 @@ package execution
 import (
 	"context"
 	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
 	"testing"
+	"time"
 )
 
 func TestPaperExecution_Buy(t *testing.T) {
@@ -111,3 +114,138 @@ func TestPaperExecution_InsufficientBalance(t *testing.T) {
 func TestPaperExecution_ImplementsInterface(t *testing.T) {
 	var _ domain.Execution = (*PaperExecution)(nil)
 }
+
+func TestPaperExecution_LimitRestsUntilCrossed(t *testing.T) {
+	paper := NewPaperExecution(0)
+	paper.Deposit("USDT", 10000_000000)
+	paper.UpdatePrice("BTC-USDT", 50000_000000)
+
+	// Buy 0.1 BTC at 49000: below the market, so it rests
+	order := domain.Order{ID: "limit-1", Symbol: "BTC-USDT", Side: "BUY", Type: "LIMIT", PriceMicros: 49000_000000, QtySats: 10_000000}
+	if err := paper.ExecuteOrder(context.Background(), order); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+	if got := paper.GetBalance("USDT"); got.ReservedSats != 4900_000000 {
+		t.Errorf("Expected 4900 USDT reserved, got %d", got.ReservedSats)
+	}
+	if len(paper.GetFills()) != 0 || len(paper.OpenOrders()) != 1 {
+		t.Fatalf("Expected a resting order and no fills")
+	}
+
+	paper.UpdatePrice("BTC-USDT", 49500_000000) // Not crossed
+	if len(paper.GetFills()) != 0 {
+		t.Fatalf("Order must not fill above its limit")
+	}
+
+	paper.UpdatePrice("BTC-USDT", 48000_000000)
+	fills := paper.GetFills()
+	if len(fills) != 1 || fills[0].PriceMicros != 49000_000000 {
+		t.Fatalf("Expected one fill at the limit price, got %+v", fills)
+	}
+	usdt := paper.GetBalance("USDT")
+	if usdt.AmountSats != 5100_000000 || usdt.ReservedSats != 0 {
+		t.Errorf("Expected 5100 USDT and no reserve, got %+v", usdt)
+	}
+	if got := paper.GetBalance("BTC").AmountSats; got != 10_000000 {
+		t.Errorf("Expected 10000000 BTC sats, got %d", got)
+	}
+	if len(paper.OpenOrders()) != 0 {
+		t.Errorf("Filled order must leave the book")
+	}
+}
+
+// ageFillModel fills every distance at the touch once an order has rested a minute.
+type ageFillModel struct{}
+
+func (ageFillModel) FillProbBps(_ string, _ int64, horizon time.Duration) (int64, bool) {
+	if horizon < time.Minute {
+		return 0, true
+	}
+	return 10_000, true
+}
+
+func TestPaperExecution_FillModelGatesTouch(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	paper := NewPaperExecution(0)
+	paper.SetClock(func() time.Time { return now })
+	paper.SetFillModel(ageFillModel{}, 1)
+	paper.Deposit("USDT", 10000_000000)
+	paper.UpdatePrice("BTC-USDT", 50000_000000)
+
+	for _, id := range []string{"touch", "through"} {
+		order := domain.Order{ID: id, Symbol: "BTC-USDT", Side: "BUY", Type: "LIMIT", PriceMicros: 49000_000000, QtySats: 1_000000}
+		if err := paper.ExecuteOrder(context.Background(), order); err != nil {
+			t.Fatalf("ExecuteOrder failed: %v", err)
+		}
+	}
+
+	now = now.Add(10 * time.Second)
+	paper.UpdateTrade("BTC-USDT", 49000_000000, -1) // Touch: queue not through yet
+	if fills := paper.GetFills(); len(fills) != 0 {
+		t.Fatalf("A touch before the model fills must not fill, got %+v", fills)
+	}
+
+	now = now.Add(time.Minute)
+	paper.UpdateTrade("BTC-USDT", 49000_000000, 1_000000) // Touch after a minute: fills the oldest
+	fills := paper.GetFills()
+	if len(fills) != 1 || fills[0].OrderID != "touch" || fills[0].TsUnixMicros != now.UnixMicro() {
+		t.Fatalf("Expected the first order filled at the touch, got %+v", fills)
+	}
+
+	paper.UpdateTrade("BTC-USDT", 48900_000000, -1) // Through the price: fills regardless
+	if fills := paper.GetFills(); len(fills) != 2 || fills[1].OrderID != "through" {
+		t.Fatalf("Expected a fill through the price, got %+v", fills)
+	}
+}
+
+func TestPaperExecution_PartialFillAndCancel(t *testing.T) {
+	paper := NewPaperExecution(0)
+	paper.Deposit("BTC", 100_000000)
+	inbox := make(chan event.Event, 8)
+	paper.SetEventSink(inbox, new(uint64))
+	ctx := context.Background()
+
+	// Sell 1 BTC at 51000 with no price yet: rests
+	order := domain.Order{ID: "limit-2", Symbol: "BTC-USDT", Side: "SELL", Type: "LIMIT", PriceMicros: 51000_000000, QtySats: 100_000000}
+	if err := paper.ExecuteOrder(ctx, order); err != nil {
+		t.Fatalf("ExecuteOrder failed: %v", err)
+	}
+
+	paper.UpdateTrade("BTC-USDT", 51500_000000, 30_000000) // 0.3 BTC printed through the limit
+	if got := paper.GetBalance("USDT").AmountSats; got != 15300_000000 {
+		t.Errorf("Expected 15300 USDT for 0.3 BTC, got %d", got)
+	}
+	if open := paper.OpenOrders(); len(open) != 1 || open[0].Status != domain.OrderStatusPartiallyFilled {
+		t.Fatalf("Expected a partially filled resting order, got %+v", open)
+	}
+
+	if err := paper.CancelOrder(ctx, "limit-2", "BTC-USDT"); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	btc := paper.GetBalance("BTC")
+	if btc.AmountSats != 70_000000 || btc.ReservedSats != 0 {
+		t.Errorf("Cancel must release the unfilled 0.7 BTC, got %+v", btc)
+	}
+	paper.UpdatePrice("BTC-USDT", 52000_000000)
+	if len(paper.GetFills()) != 1 {
+		t.Errorf("Canceled order must not fill")
+	}
+	if err := paper.CancelOrder(ctx, "limit-2", "BTC-USDT"); err == nil {
+		t.Errorf("Expected error canceling a canceled order")
+	}
+
+	want := []struct {
+		status string
+		filled quant.QtySats
+	}{
+		{domain.OrderStatusAcked, 0},
+		{domain.OrderStatusPartiallyFilled, 30_000000},
+		{domain.OrderStatusCanceled, 30_000000},
+	}
+	for i, w := range want {
+		u := (<-inbox).(*event.OrderUpdateEvent)
+		if u.OrderID != "limit-2" || u.Status != w.status || u.AccumulatedQtySats != w.filled || u.Seq != uint64(i+1) {
+			t.Errorf("update %d: expected %s/%d, got %+v", i, w.status, w.filled, u)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package execution
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"errors"
+	"time"
+)
+
+// paperReject builds the simulator's equivalent of a venue business error.
+func paperReject(kind domain.RejectKind, msg string) *domain.ExchangeError {
+	return &domain.ExchangeError{Venue: "PAPER", Code: string(kind), Msg: msg, Kind: kind}
+}
+
+// NewOrderRejectedEvent turns a failed ExecuteOrder into an event for the sequencer.
+// Errors that are not venue rejections (network, config) are classified RejectUnknown.
+func NewOrderRejectedEvent(order domain.Order, exchange string, err error) *event.OrderRejectedEvent {
+	ev := &event.OrderRejectedEvent{
+		BaseEvent:   event.BaseEvent{Ts: quant.TimeStamp(time.Now().UnixMicro())},
+		OrderID:     order.ID,
+		Symbol:      order.Symbol,
+		Side:        order.Side,
+		PriceMicros: quant.PriceMicros(order.PriceMicros),
+		QtySats:     quant.QtySats(order.QtySats),
+		Exchange:    exchange,
+		Reason:      domain.RejectKindOf(err),
+		Message:     err.Error(), // Full chain: keeps the caller's context
+	}
+	var ee *domain.ExchangeError
+	if errors.As(err, &ee) {
+		ev.Code = ee.Code
+	}
+	return ev
+}
@@ -0,0 +1,31 @@
+package execution
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewOrderRejectedEvent(t *testing.T) {
+	paper := NewPaperExecution(0)
+	order := domain.Order{ID: "o1", Symbol: "BTC-USDT", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 1_000_000, QtySats: 100_000_000}
+
+	err := paper.ExecuteOrder(context.Background(), order)
+	if !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Fatalf("expected insufficient funds, got %v", err)
+	}
+
+	ev := NewOrderRejectedEvent(order, "PAPER", fmt.Errorf("dispatch: %w", err))
+	if ev.Reason != domain.RejectInsufficientFunds || ev.Code != string(domain.RejectInsufficientFunds) {
+		t.Errorf("unexpected classification: %+v", ev)
+	}
+	if ev.OrderID != "o1" || ev.QtySats != 100_000_000 {
+		t.Errorf("order context lost: %+v", ev)
+	}
+
+	if ev := NewOrderRejectedEvent(order, "BITGET", errors.New("connection reset")); ev.Reason != domain.RejectUnknown {
+		t.Errorf("network errors must classify as unknown, got %s", ev.Reason)
+	}
+}
@@ -0,0 +1,150 @@
+package infra
+
+import (
+	"crypto_go/internal/event"
+	"log/slog"
+)
+
+// DefaultHighWatermarkPct is the inbox fill at which a gateway is pressured.
+const DefaultHighWatermarkPct = 80
+
+// Backpressure configures how a gateway's Outbox reacts to a filling inbox.
+type Backpressure struct {
+	HighPct int // Inbox fill (% of capacity) that starts pressure (0 = DefaultHighWatermarkPct)
+	LowPct  int // Fill that ends it (0 = HighPct/2)
+	// Conflate keeps the latest refused ticker per exchange/symbol and delivers
+	// it once the inbox has room, instead of dropping it. The outbox then
+	// restamps the source sequence: coalesced tickers leave no gap, dropped
+	// events of other types still do.
+	Conflate bool
+	OnChange func(gateway string, pressured bool) // Called on each transition, on the worker goroutine (optional)
+}
+
+// backpressure is the per-gateway state behind Outbox.SetBackpressure. Like
+// the Outbox, it belongs to the worker's single producer goroutine.
+type backpressure struct {
+	cfg       Backpressure
+	gateway   string
+	pressured bool
+	seq       uint64                     // Conflate: last source sequence handed out
+	pending   []*event.MarketUpdateEvent // Conflate: latest refused ticker per exchange/symbol, oldest first
+}
+
+// SetBackpressure names the outbox's gateway, counts its refused events
+// (Metrics.InboxDrops) and reports when its inbox crosses the watermarks
+// (Metrics.InboxPressured, cfg.OnChange). Must be called before the worker starts.
+func (o *Outbox) SetBackpressure(gateway string, cfg Backpressure) {
+	if cfg.HighPct <= 0 || cfg.HighPct > 100 {
+		cfg.HighPct = DefaultHighWatermarkPct
+	}
+	if cfg.LowPct <= 0 || cfg.LowPct >= cfg.HighPct {
+		cfg.LowPct = cfg.HighPct / 2
+	}
+	o.bp = &backpressure{cfg: cfg, gateway: gateway}
+}
+
+// Pressured reports whether the gateway's inbox is above its high watermark
+// (false without SetBackpressure).
+func (o *Outbox) Pressured() bool {
+	return o.bp != nil && o.bp.pressured
+}
+
+func (b *backpressure) send(o *Outbox, ev event.Event) bool {
+	b.flush(o)
+	tick, isTick := ev.(*event.MarketUpdateEvent)
+	ok := true
+	switch {
+	case !b.cfg.Conflate:
+		ok = o.push(ev)
+	case isTick && (len(b.pending) > 0 || !b.deliver(o, ev)):
+		b.conflate(tick) // Behind older pending tickers, or refused
+	case !isTick && !b.deliver(o, ev):
+		b.seq++ // Dropped: leave the gap for sequence validation
+		ok = false
+	}
+	if !ok {
+		GlobalMetrics.RecordInboxDrop(b.gateway)
+	}
+	b.observe(o, !ok)
+	return ok
+}
+
+// deliver stamps ev with the next source sequence and pushes it.
+func (b *backpressure) deliver(o *Outbox, ev event.Event) bool {
+	if s, ok := ev.(interface{ SetSeq(uint64) }); ok {
+		s.SetSeq(b.seq + 1)
+	}
+	if !o.push(ev) {
+		return false
+	}
+	b.seq++
+	return true
+}
+
+// flush delivers pending tickers, oldest first, while the inbox takes them.
+func (b *backpressure) flush(o *Outbox) {
+	n := 0
+	for n < len(b.pending) && b.deliver(o, b.pending[n]) {
+		n++
+	}
+	if n > 0 {
+		clear(b.pending[:n])
+		b.pending = append(b.pending[:0], b.pending[n:]...)
+	}
+}
+
+// conflate keeps tick as the pending ticker of its exchange/symbol, replacing
+// (and releasing) an older one.
+func (b *backpressure) conflate(tick *event.MarketUpdateEvent) {
+	for i, p := range b.pending {
+		if p.Symbol == tick.Symbol && p.Exchange == tick.Exchange {
+			b.pending[i] = tick
+			event.ReleaseMarketUpdateEvent(p)
+			GlobalMetrics.RecordInboxConflated(b.gateway)
+			return
+		}
+	}
+	b.pending = append(b.pending, tick)
+}
+
+// observe updates the pressure state from the inbox fill; a refused event
+// always counts as pressure.
+func (b *backpressure) observe(o *Outbox, refused bool) {
+	pressured := b.pressured
+	switch fill := o.fillPct(); {
+	case refused || len(b.pending) > 0 || fill >= b.cfg.HighPct:
+		pressured = true
+	case fill <= b.cfg.LowPct:
+		pressured = false
+	}
+	if pressured == b.pressured {
+		return
+	}
+	b.pressured = pressured
+	GlobalMetrics.SetInboxPressured(b.gateway, pressured)
+	if pressured {
+		slog.Warn("INBOX_PRESSURE", slog.String("gateway", b.gateway), slog.Int("fill_pct", o.fillPct()))
+	} else {
+		slog.Info("INBOX_PRESSURE_CLEARED", slog.String("gateway", b.gateway))
+	}
+	if b.cfg.OnChange != nil {
+		b.cfg.OnChange(b.gateway, pressured)
+	}
+}
+
+// fillPct returns how full the inbox is, in percent (0 if unknown).
+func (o *Outbox) fillPct() int {
+	if o.lane != nil {
+		if l, ok := o.lane.(interface {
+			Len() int
+			Cap() int
+		}); ok && l.Cap() > 0 {
+			return l.Len() * 100 / l.Cap()
+		}
+		return 0
+	}
+	if cap(o.ch) == 0 {
+		return 0
+	}
+	return len(o.ch) * 100 / cap(o.ch)
+}
@@ -0,0 +1,97 @@
+package infra
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+func TestOutbox_BackpressureWatermarks(t *testing.T) {
+	GlobalMetrics.Reset()
+	defer GlobalMetrics.Reset()
+
+	ch := make(chan event.Event, 4)
+	out := NewOutbox(ch)
+	var changes []bool
+	out.SetBackpressure("TEST", Backpressure{HighPct: 75, LowPct: 25, OnChange: func(gateway string, pressured bool) {
+		changes = append(changes, pressured)
+	}})
+
+	for i := 0; i < 2; i++ {
+		out.Send(&event.TradeEvent{})
+	}
+	if out.Pressured() {
+		t.Fatal("half full must not be pressured")
+	}
+	out.Send(&event.TradeEvent{})
+	out.Send(&event.TradeEvent{})
+	if !out.Pressured() || len(changes) != 1 {
+		t.Fatalf("75%% full must be pressured (changes %v)", changes)
+	}
+	if out.Send(&event.TradeEvent{}) {
+		t.Error("a full inbox must refuse the event")
+	}
+	for len(ch) > 0 {
+		<-ch
+	}
+	out.Send(&event.TradeEvent{})
+	if out.Pressured() || len(changes) != 2 || changes[1] {
+		t.Errorf("25%% full must clear the pressure (changes %v)", changes)
+	}
+
+	snap := GlobalMetrics.Snapshot()
+	if snap.InboxDrops["TEST"] != 1 || snap.InboxPressured["TEST"] {
+		t.Errorf("drops %d, pressured %v; want 1, false", snap.InboxDrops["TEST"], snap.InboxPressured["TEST"])
+	}
+}
+
+func TestOutbox_Conflate(t *testing.T) {
+	GlobalMetrics.Reset()
+	defer GlobalMetrics.Reset()
+
+	ch := make(chan event.Event, 2)
+	out := NewOutbox(ch)
+	out.SetBackpressure("TEST", Backpressure{Conflate: true})
+	tick := func(symbol string, price quant.PriceMicros) *event.MarketUpdateEvent {
+		ev := &event.MarketUpdateEvent{Symbol: symbol, Exchange: "TEST", PriceMicros: price}
+		ev.Seq = 999 // The worker's own stamp is replaced
+		return ev
+	}
+
+	out.Send(tick("BTC", 1))
+	out.Send(tick("ETH", 1))
+	// Full: BTC is kept and then replaced, ETH queues behind it
+	if !out.Send(tick("BTC", 2)) || !out.Send(tick("BTC", 3)) || !out.Send(tick("ETH", 2)) {
+		t.Fatal("conflated tickers must be accepted")
+	}
+	if out.Send(&event.TradeEvent{}) {
+		t.Error("a non-ticker event must still be refused")
+	}
+
+	var got []*event.MarketUpdateEvent
+	drain := func() {
+		for len(ch) > 0 {
+			got = append(got, (<-ch).(*event.MarketUpdateEvent))
+		}
+	}
+	drain()
+	out.Send(tick("XRP", 1)) // Flushes BTC and ETH first; XRP waits
+	drain()
+
+	want := []struct {
+		symbol string
+		price  quant.PriceMicros
+		seq    uint64
+	}{{"BTC", 1, 1}, {"ETH", 1, 2}, {"BTC", 3, 4}, {"ETH", 2, 5}} // Seq 3 was the dropped trade
+	if len(got) != len(want) {
+		t.Fatalf("got %d tickers; want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Symbol != w.symbol || got[i].PriceMicros != w.price || got[i].Seq != w.seq {
+			t.Errorf("ticker %d: %s %d seq %d; want %s %d seq %d", i, got[i].Symbol, got[i].PriceMicros, got[i].Seq, w.symbol, w.price, w.seq)
+		}
+	}
+	if snap := GlobalMetrics.Snapshot(); snap.InboxConflated["TEST"] != 1 || snap.InboxDrops["TEST"] != 1 {
+		t.Errorf("conflated %d, drops %d; want 1, 1", snap.InboxConflated["TEST"], snap.InboxDrops["TEST"])
+	}
+}
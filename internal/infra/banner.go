@@ -44,6 +44,9 @@ func PrintBanner(cfg *Config) {
 	fmt.Printf("%s#   MODE:    %-36s #%s\n", color, mode, ColorReset)
 	fmt.Printf("%s#   TYPE:    %-36s #%s\n", color, modeDesc, ColorReset)
 	fmt.Printf("%s#   VERSION: %-36s #%s\n", color, version, ColorReset)
+	if cfg.Profile != "" {
+		fmt.Printf("%s#   PROFILE: %-36s #%s\n", color, cfg.Profile, ColorReset)
+	}
 	fmt.Printf("%s#                                                         #%s\n", color, ColorReset)
 
 	if mode == "REAL" {
@@ -54,3 +57,13 @@ func PrintBanner(cfg *Config) {
 	fmt.Printf("%s###########################################################%s\n", color, ColorReset)
 	fmt.Println()
 }
+
+// PrintReadOnlyBanner warns that this instance lost the trading lock and only monitors
+func PrintReadOnlyBanner(instanceID string) {
+	fmt.Printf("%s###########################################################%s\n", ColorMagenta, ColorReset)
+	fmt.Printf("%s#   🔒 READ-ONLY MODE: ANOTHER INSTANCE IS TRADING         #%s\n", ColorMagenta, ColorReset)
+	fmt.Printf("%s#   SAME API KEYS DETECTED - ORDERS ARE DISABLED          #%s\n", ColorMagenta, ColorReset)
+	fmt.Printf("%s#   INSTANCE: %-35s #%s\n", ColorMagenta, instanceID, ColorReset)
+	fmt.Printf("%s###########################################################%s\n", ColorMagenta, ColorReset)
+	fmt.Println()
+}
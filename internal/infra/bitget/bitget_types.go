@@ -1,6 +1,7 @@
 package bitget
 
 import (
+	"crypto_go/internal/domain"
 	"crypto_go/pkg/quant"
 )
 
@@ -21,6 +22,11 @@ type subscribeArg struct {
 	InstId   string `json:"instId"`
 }
 
+// pushHeader is the part common to every push, used to route it by channel.
+type pushHeader struct {
+	Arg subscribeArg `json:"arg"`
+}
+
 // tickerResponse Structure
 type tickerResponse struct {
 	Action string       `json:"action"`
@@ -34,8 +40,63 @@ type tickerData struct {
 	LastPr     string `json:"lastPr"`     // Spot & Futures
 	BaseVolume string `json:"baseVolume"` // Spot
 	Volume24h  string `json:"volume24h"`  // Futures
+
+	FundingRate     string `json:"fundingRate"`     // Futures: predicted rate of the next epoch
+	NextFundingTime string `json:"nextFundingTime"` // Futures: Unix millis
 }
 
 func NextSeq(seq *uint64) uint64 {
 	return quant.NextSeq(seq)
 }
+
+// bookChannel is the top-15 depth channel: every push is a full snapshot.
+const bookChannel = "books15"
+
+// bookResponse is a push on the books15 channel.
+type bookResponse struct {
+	Action string       `json:"action"`
+	Arg    subscribeArg `json:"arg"`
+	Data   []bookData   `json:"data"`
+	Ts     int64        `json:"ts"`
+}
+
+type bookData struct {
+	Asks [][]string `json:"asks"` // [price, size]
+	Bids [][]string `json:"bids"`
+	Ts   string     `json:"ts"` // Milliseconds
+}
+
+// tradeChannel is the public trade stream. The first push after subscribing is a
+// snapshot of recent history; only "update" pushes are new trades.
+const tradeChannel = "trade"
+
+// tradeResponse is a push on the trade channel.
+type tradeResponse struct {
+	Action string       `json:"action"`
+	Arg    subscribeArg `json:"arg"`
+	Data   []tradeData  `json:"data"`
+}
+
+type tradeData struct {
+	Ts    string `json:"ts"` // Milliseconds
+	Price string `json:"price"`
+	Size  string `json:"size"`
+	Side  string `json:"side"` // buy | sell (taker)
+}
+
+// candleChannels maps domain intervals to Bitget kline channels. Like trades,
+// the first push is a snapshot of history.
+var candleChannels = map[string]string{
+	domain.Candle1m: "candle1m",
+	domain.Candle5m: "candle5m",
+	domain.Candle1h: "candle1H",
+}
+
+// candleResponse is a push on a kline channel.
+// Each row is [openTime ms, open, high, low, close, baseVolume, quoteVolume, usdtVolume].
+type candleResponse struct {
+	Action string       `json:"action"`
+	Arg    subscribeArg `json:"arg"`
+	Data   [][]string   `json:"data"`
+	Ts     int64        `json:"ts"`
+}
@@ -0,0 +1,60 @@
+package bitget
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// candleSnapshotKeep is how many bars of a history snapshot are published: the
+// last closed bar and the open one. Older history is not needed to track bars.
+const candleSnapshotKeep = 2
+
+// parseCandles converts a kline push for interval into CandleEvents, oldest first.
+// findSymbol maps instId to the unified symbol ("" = not subscribed).
+func parseCandles(msg []byte, exchange, interval string, findSymbol func(string) string, seq *uint64) []*event.CandleEvent {
+	var resp candleResponse
+	if err := json.Unmarshal(msg, &resp); err != nil || resp.Arg.Channel != candleChannels[interval] {
+		return nil
+	}
+	symbol := findSymbol(resp.Arg.InstId)
+	if symbol == "" {
+		return nil
+	}
+
+	out := make([]*event.CandleEvent, 0, len(resp.Data))
+	for _, row := range resp.Data {
+		if len(row) < 6 {
+			continue
+		}
+		ms, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		ev := &event.CandleEvent{
+			Exchange:    exchange,
+			Symbol:      symbol,
+			Interval:    interval,
+			OpenTime:    quant.TimeStamp(ms * 1000),
+			OpenMicros:  quant.ToPriceMicrosStr(row[1]),
+			HighMicros:  quant.ToPriceMicrosStr(row[2]),
+			LowMicros:   quant.ToPriceMicrosStr(row[3]),
+			CloseMicros: quant.ToPriceMicrosStr(row[4]),
+			VolumeSats:  quant.ToQtySatsStr(row[5]),
+		}
+		ev.Ts = quant.TimeStamp(resp.Ts * 1000)
+		out = append(out, ev)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].OpenTime < out[j].OpenTime })
+	if resp.Action == "snapshot" && len(out) > candleSnapshotKeep {
+		out = out[len(out)-candleSnapshotKeep:]
+	}
+	for _, ev := range out {
+		ev.Seq = quant.NextSeq(seq)
+	}
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -13,6 +14,8 @@ import (
 
 	"crypto_go/internal/domain"
 	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
 )
 
 // Bitget API Const// Base URLs
@@ -48,7 +51,7 @@ func NewClient(cfg *infra.Config, isTestnet bool) *Client {
 	)
 
 	return &Client{
-		httpClient:     &http.Client{Timeout: 10 * time.Second},
+		httpClient:     infra.NewHTTPClient(10 * time.Second),
 		baseURL:        baseURL,
 		signer:         signer,
 		logger:         slog.With("module", "bitget_client"),
@@ -78,9 +81,25 @@ type placeOrderRequest struct {
 	ClientOrderId string `json:"clientOid"`
 }
 
-// PlaceOrder sends an order to the exchange (FUTURES V2).
+// Futures (mix) account constants. Only USDT-margined perpetuals are traded.
+const (
+	productTypeUSDTFutures = "USDT-FUTURES"
+	marginCoinUSDT         = "USDT"
+)
+
+// PlaceOrder sends an order to the exchange (V2), routed by order.Market:
+// domain.MarketSpot goes to the spot endpoint, anything else (including empty,
+// the historical default) to USDT-M futures.
 // Quant: Inputs are strictly int64 types.
 func (c *Client) PlaceOrder(ctx context.Context, order domain.Order) error {
+	if order.Market == domain.MarketSpot {
+		return c.placeSpotOrder(ctx, order)
+	}
+	return c.placeFuturesOrder(ctx, order)
+}
+
+// placeFuturesOrder sends an order to the MIX (futures) endpoint.
+func (c *Client) placeFuturesOrder(ctx context.Context, order domain.Order) error {
 	// Rate Limiting: Prevent IP ban (보안 강화)
 	infra.GetBitgetOrderLimiter().Wait()
 
@@ -95,11 +114,11 @@ func (c *Client) PlaceOrder(ctx context.Context, order domain.Order) error {
 
 	reqBody := placeOrderRequest{
 		Symbol:      order.Symbol,
-		ProductType: "USDT-FUTURES", // Hardcoded for now
-		MarginMode:  "crossed",      // Default to Crossed
-		MarginCoin:  "USDT",
+		ProductType: productTypeUSDTFutures,
+		MarginMode:  "crossed", // Default to Crossed
+		MarginCoin:  marginCoinUSDT,
 		Side:        side,   // buy / sell
-		TradeSide:   "open", // open / close
+		TradeSide:   "open", // open / close (ignored in one-way mode)
 		OrderType:   "limit",
 		// Force:         "normal",    // Removing entirely to rely on default
 		Price:         priceStr,
@@ -123,7 +142,61 @@ func (c *Client) PlaceOrder(ctx context.Context, order domain.Order) error {
 		return fmt.Errorf("place order error: %w", err)
 	}
 
-	c.logger.Info("Order Placed Successfully", "oid", order.ID, "symbol", order.Symbol)
+	c.logger.Info("Order Placed Successfully", "oid", order.ID, "symbol", order.Symbol, "market", domain.MarketFutures)
+	return nil
+}
+
+// placeSpotOrderRequest - Internal Struct for JSON Marshaling (SPOT V2)
+type placeSpotOrderRequest struct {
+	Symbol        string `json:"symbol"`
+	Side          string `json:"side"`
+	OrderType     string `json:"orderType"`
+	Force         string `json:"force"`
+	Price         string `json:"price,omitempty"`
+	Size          string `json:"size"` // Base qty; quote amount for a market buy
+	ClientOrderId string `json:"clientOid"`
+}
+
+// placeSpotOrder sends an order to the SPOT endpoint. Bitget sizes a spot
+// market buy in quote currency, so it needs the expected price in PriceMicros.
+func (c *Client) placeSpotOrder(ctx context.Context, order domain.Order) error {
+	// Rate Limiting: Prevent IP ban (보안 강화)
+	infra.GetBitgetOrderLimiter().Wait()
+
+	reqBody := placeSpotOrderRequest{
+		Symbol:        order.Symbol,
+		Side:          "buy",
+		OrderType:     "limit",
+		Force:         "gtc",
+		Price:         formatFixedPoint(order.PriceMicros, 6),
+		Size:          formatFixedPoint(order.QtySats, 8),
+		ClientOrderId: order.ID,
+	}
+	if order.Side == domain.SideSell {
+		reqBody.Side = "sell"
+	}
+	if order.Type == domain.OrderTypeMarket {
+		reqBody.OrderType = "market"
+		reqBody.Price = ""
+		if order.Side == domain.SideBuy {
+			if order.PriceMicros <= 0 {
+				return fmt.Errorf("bitget spot market buy %s needs an expected price to size the quote amount", order.ID)
+			}
+			reqBody.Size = formatFixedPoint(safe.SafeMulDiv(order.PriceMicros, order.QtySats, quant.QtyScale), 6)
+		}
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/api/v2/spot/trade/place-order", reqBody)
+	if err != nil {
+		return fmt.Errorf("bitget place spot order failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := c.parseResponse(resp); err != nil {
+		return fmt.Errorf("place spot order error: %w", err)
+	}
+
+	c.logger.Info("Order Placed Successfully", "oid", order.ID, "symbol", order.Symbol, "market", domain.MarketSpot)
 	return nil
 }
 
@@ -134,7 +207,7 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string, symbol string)
 
 	reqBody := map[string]string{
 		"symbol":      symbol,
-		"productType": "USDT-FUTURES",
+		"productType": productTypeUSDTFutures,
 		"clientOid":   orderID,
 	}
 
@@ -152,6 +225,30 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string, symbol string)
 	return nil
 }
 
+// CancelSpotOrder sends a cancel request for a spot order (SPOT V2).
+func (c *Client) CancelSpotOrder(ctx context.Context, orderID string, symbol string) error {
+	// Rate Limiting: Prevent IP ban (보안 강화)
+	infra.GetBitgetOrderLimiter().Wait()
+
+	reqBody := map[string]string{
+		"symbol":    symbol,
+		"clientOid": orderID,
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/api/v2/spot/trade/cancel-order", reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := c.parseResponse(resp); err != nil {
+		return fmt.Errorf("cancel spot order error: %w", err)
+	}
+
+	c.logger.Info("Order Canceled Successfully", "oid", orderID, "symbol", symbol, "market", domain.MarketSpot)
+	return nil
+}
+
 // GetBalance fetches the available balance (FUTURES V2).
 func (c *Client) GetBalance(ctx context.Context, coin string) (int64, error) {
 	// Rate Limiting: Prevent IP ban (보안 강화)
@@ -201,8 +298,23 @@ func (c *Client) parseResponse(resp *http.Response) (json.RawMessage, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, classifyError("429", string(bodyBytes))
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http error: status=%d body=%s", resp.StatusCode, string(bodyBytes))
+		// Bitget reports business errors with 4xx + the usual JSON body
+		var apiErr struct {
+			Code string `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if json.Unmarshal(bodyBytes, &apiErr) == nil && apiErr.Code != "" {
+			return nil, classifyError(apiErr.Code, apiErr.Msg)
+		}
+		httpErr := fmt.Errorf("http error: status=%d body=%s", resp.StatusCode, string(bodyBytes))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, domain.NewNetworkError("bitget response", httpErr) // Gateway/server trouble: retriable
+		}
+		return nil, httpErr
 	}
 
 	var apiResp struct {
@@ -216,7 +328,7 @@ func (c *Client) parseResponse(resp *http.Response) (json.RawMessage, error) {
 	}
 
 	if apiResp.Code != "00000" {
-		return nil, fmt.Errorf("business error: code=%s msg=%s", apiResp.Code, apiResp.Msg)
+		return nil, classifyError(apiResp.Code, apiResp.Msg)
 	}
 
 	return apiResp.Data, nil
@@ -224,11 +336,6 @@ func (c *Client) parseResponse(resp *http.Response) (json.RawMessage, error) {
 
 // doRequest performs the HTTP request with circuit breaker protection.
 func (c *Client) doRequest(ctx context.Context, method, path string, payload interface{}) (*http.Response, error) {
-	// Circuit Breaker: Check if request is allowed (Rule #5: Fault isolation)
-	if !c.circuitBreaker.Allow() {
-		return nil, fmt.Errorf("circuit breaker open: bitget-api")
-	}
-
 	url := c.baseURL + path
 
 	var body io.Reader
@@ -268,15 +375,23 @@ func (c *Client) doRequest(ctx context.Context, method, path string, payload int
 	// 3. Add Browser-like User-Agent
 	req.Header.Set("User-Agent", infra.GetUserAgent())
 
-	// 4. Execute with Circuit Breaker recording
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.circuitBreaker.RecordFailure()
+	// 4. Execute through the Circuit Breaker (Rule #5: Fault isolation).
+	// Any HTTP response counts as success (even 4xx is "server responded").
+	var resp *http.Response
+	err = c.circuitBreaker.Execute(ctx, func() error {
+		var err error
+		resp, err = c.httpClient.Do(req)
+		return err
+	})
+	if errors.Is(err, infra.ErrCircuitOpen) {
 		return nil, err
 	}
-
-	// Record success for successful HTTP response (even 4xx is "server responded")
-	c.circuitBreaker.RecordSuccess()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, domain.NewFatalNetworkError(method+" "+signPath, err) // Caller gave up
+		}
+		return nil, domain.NewNetworkError(method+" "+signPath, err)
+	}
 	return resp, nil
 }
 
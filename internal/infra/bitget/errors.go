@@ -0,0 +1,59 @@
+package bitget
+
+import (
+	"crypto_go/internal/domain"
+	"errors"
+	"strings"
+)
+
+// errorKinds maps Bitget V2 business error codes to normalized reject kinds.
+// Codes not listed fall back to message matching, then RejectUnknown.
+var errorKinds = map[string]domain.RejectKind{
+	"43012": domain.RejectInsufficientFunds, // Insufficient balance
+	"40762": domain.RejectInsufficientFunds, // The order amount exceeds the balance
+	"43006": domain.RejectMinSize,           // The order quantity is less than the minimum
+	"45110": domain.RejectMinSize,           // Less than the minimum order amount
+	"45111": domain.RejectMinSize,           // Less than the minimum order quantity
+	"429":   domain.RejectRateLimited,       // Too many requests
+	"40808": domain.RejectInvalidPrice,      // Parameter verification exception (price precision)
+	"45115": domain.RejectInvalidPrice,      // Price is not a multiple of the tick size
+	"43001": domain.RejectOrderNotFound,     // The order does not exist
+	"40034": domain.RejectMarketUnavailable, // Parameter does not exist (unknown symbol)
+	"40309": domain.RejectMarketUnavailable, // The contract has been removed
+}
+
+// classifyError converts a Bitget business error into a domain.ExchangeError.
+func classifyError(code, msg string) *domain.ExchangeError {
+	kind, ok := errorKinds[code]
+	if !ok {
+		kind = classifyMessage(msg)
+	}
+	return &domain.ExchangeError{Venue: "BITGET", Code: code, Msg: msg, Kind: kind}
+}
+
+func classifyMessage(msg string) domain.RejectKind {
+	m := strings.ToLower(msg)
+	switch {
+	case strings.Contains(m, "insufficient"):
+		return domain.RejectInsufficientFunds
+	case strings.Contains(m, "minimum"):
+		return domain.RejectMinSize
+	case strings.Contains(m, "too many requests"), strings.Contains(m, "frequency"):
+		return domain.RejectRateLimited
+	default:
+		return domain.RejectUnknown
+	}
+}
+
+// IsDuplicateClientOid reports whether err is Bitget refusing an order because
+// its clientOid was already used, i.e. an earlier attempt of the same order
+// reached the exchange. Bitget uses several codes for this, so the message is
+// matched.
+func IsDuplicateClientOid(err error) bool {
+	var ee *domain.ExchangeError
+	if !errors.As(err, &ee) || ee.Venue != "BITGET" {
+		return false
+	}
+	m := strings.ToLower(ee.Msg)
+	return strings.Contains(m, "duplicate") || (strings.Contains(m, "clientoid") && strings.Contains(m, "exist"))
+}
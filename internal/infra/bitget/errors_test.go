@@ -0,0 +1,82 @@
+package bitget
+
+import (
+	"bytes"
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		code, msg string
+		want      domain.RejectKind
+	}{
+		{"43012", "Insufficient balance", domain.RejectInsufficientFunds},
+		{"45111", "less than the minimum order quantity", domain.RejectMinSize},
+		{"429", "Too Many Requests", domain.RejectRateLimited},
+		{"99999", "Insufficient margin", domain.RejectInsufficientFunds}, // Message fallback
+		{"99999", "something else", domain.RejectUnknown},
+	}
+	for _, c := range cases {
+		err := classifyError(c.code, c.msg)
+		if err.Kind != c.want || err.Venue != "BITGET" || err.Code != c.code {
+			t.Errorf("classifyError(%s, %q) = %+v, want kind %s", c.code, c.msg, err, c.want)
+		}
+	}
+}
+
+func TestClient_PlaceOrderRejected(t *testing.T) {
+	client := NewClient(&infra.Config{}, true)
+	client.httpClient.Transport = &MockRoundTripper{
+		Func: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"code":"43012","msg":"Insufficient balance","data":null}`)),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	err := client.PlaceOrder(context.Background(), domain.Order{ID: "oid", Symbol: "BTCUSDT", Side: domain.SideBuy, QtySats: 1})
+	if !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Fatalf("expected typed insufficient funds error, got %v", err)
+	}
+}
+
+func TestClient_ServerErrorIsRetriable(t *testing.T) {
+	client := newMockClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Body:       io.NopCloser(bytes.NewBufferString(`<html>bad gateway</html>`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	err := client.PlaceOrder(context.Background(), domain.Order{ID: "oid", Symbol: "BTCUSDT", Side: domain.SideBuy, QtySats: 1})
+	if !domain.IsRetriable(err) {
+		t.Errorf("expected a retriable error for 502, got %v", err)
+	}
+}
+
+func TestIsDuplicateClientOid(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{classifyError("40786", "Duplicate clientOid"), true},
+		{classifyError("43011", "The clientOid already exists"), true},
+		{classifyError("43012", "Insufficient balance"), false},
+		{&domain.ExchangeError{Venue: "UPBIT", Msg: "duplicate identifier"}, false},
+		{errors.New("duplicate"), false},
+	}
+	for _, c := range cases {
+		if got := IsDuplicateClientOid(c.err); got != c.want {
+			t.Errorf("IsDuplicateClientOid(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package bitget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+)
+
+func newMockClient(fn func(req *http.Request) (*http.Response, error)) *Client {
+	client := NewClient(&infra.Config{}, true)
+	client.httpClient.Transport = &MockRoundTripper{Func: fn}
+	return client
+}
+
+func okResponse(data string) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"code":"00000","msg":"success","data":` + data + `}`)),
+		Header:     make(http.Header),
+	}
+}
+
+func TestClient_PlaceOrder_Spot(t *testing.T) {
+	var body map[string]string
+	client := newMockClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/api/v2/spot/trade/place-order" {
+			t.Errorf("Unexpected path: %s", req.URL.Path)
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		return okResponse(`{"clientOid":"spot_oid"}`), nil
+	})
+
+	order := domain.Order{
+		ID:          "spot_oid",
+		Symbol:      "BTCUSDT",
+		Side:        domain.SideBuy,
+		Type:        domain.OrderTypeMarket,
+		Market:      domain.MarketSpot,
+		PriceMicros: 50_000_000_000, // $50,000 expected
+		QtySats:     100_000,        // 0.001 BTC
+	}
+	if err := client.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	// Spot market buy is sized in quote: 50,000 * 0.001 = 50 USDT
+	if body["orderType"] != "market" || body["size"] != "50.000000" || body["price"] != "" {
+		t.Errorf("unexpected spot market buy body: %v", body)
+	}
+	if _, ok := body["productType"]; ok {
+		t.Error("spot order must not carry productType")
+	}
+}
+
+func TestClient_PlaceOrder_FuturesByDefault(t *testing.T) {
+	for _, market := range []string{"", domain.MarketFutures} {
+		var body map[string]string
+		client := newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/api/v2/mix/order/place-order" {
+				t.Errorf("market %q: unexpected path: %s", market, req.URL.Path)
+			}
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			return okResponse(`{}`), nil
+		})
+
+		order := domain.Order{ID: "f", Symbol: "BTCUSDT", Side: domain.SideSell, Type: domain.OrderTypeLimit, Market: market, PriceMicros: 1_000_000, QtySats: 1}
+		if err := client.PlaceOrder(context.Background(), order); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+		if body["productType"] != "USDT-FUTURES" || body["side"] != "sell" {
+			t.Errorf("market %q: unexpected futures body: %v", market, body)
+		}
+	}
+}
+
+func TestClient_SetLeverage(t *testing.T) {
+	var body map[string]string
+	client := newMockClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/api/v2/mix/account/set-leverage" || req.Method != "POST" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		return okResponse(`{"longLeverage":"5","shortLeverage":"5"}`), nil
+	})
+
+	if err := client.SetLeverage(context.Background(), "BTCUSDT", 5, ""); err != nil {
+		t.Fatalf("SetLeverage failed: %v", err)
+	}
+	if body["leverage"] != "5" || body["marginCoin"] != "USDT" {
+		t.Errorf("unexpected body: %v", body)
+	}
+	if _, ok := body["holdSide"]; ok {
+		t.Error("holdSide must be omitted when empty")
+	}
+
+	if err := client.SetLeverage(context.Background(), "BTCUSDT", 0, ""); err == nil {
+		t.Error("expected error for leverage 0")
+	}
+}
+
+func TestClient_SetPositionMode(t *testing.T) {
+	var body map[string]string
+	client := newMockClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/api/v2/mix/account/set-position-mode" {
+			t.Errorf("Unexpected path: %s", req.URL.Path)
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		return okResponse(`{"posMode":"hedge_mode"}`), nil
+	})
+
+	if err := client.SetPositionMode(context.Background(), PositionModeHedge); err != nil {
+		t.Fatalf("SetPositionMode failed: %v", err)
+	}
+	if body["posMode"] != "hedge_mode" {
+		t.Errorf("unexpected body: %v", body)
+	}
+	if err := client.SetPositionMode(context.Background(), "both"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestClient_GetPositions(t *testing.T) {
+	var paths []string
+	client := newMockClient(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		if req.URL.Query().Get("productType") != "USDT-FUTURES" {
+			t.Errorf("missing productType: %s", req.URL.RawQuery)
+		}
+		return okResponse(`[{"symbol":"BTCUSDT","holdSide":"long","marginMode":"crossed","leverage":"10",
+			"total":"0.015","available":"0.01","openPriceAvg":"61000.5","markPrice":"61500",
+			"unrealizedPL":"-1.25","liquidationPrice":"55000"}]`), nil
+	})
+
+	positions, err := client.GetPositions(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetPositions failed: %v", err)
+	}
+	if len(positions) != 1 {
+		t.Fatalf("expected 1 position, got %d", len(positions))
+	}
+	want := Position{
+		Symbol: "BTCUSDT", HoldSide: HoldSideLong, MarginMode: "crossed", Leverage: 10,
+		TotalSats: 1_500_000, AvailableSats: 1_000_000,
+		OpenPriceMicros: 61_000_500_000, MarkPriceMicros: 61_500_000_000,
+		UnrealizedPLMicros: -1_250_000, LiqPriceMicros: 55_000_000_000,
+	}
+	if positions[0] != want {
+		t.Errorf("position mismatch.\n got %+v\nwant %+v", positions[0], want)
+	}
+
+	if _, err := client.GetPositions(context.Background(), ""); err != nil {
+		t.Fatalf("GetPositions(all) failed: %v", err)
+	}
+	if paths[0] != "/api/v2/mix/position/single-position" || paths[1] != "/api/v2/mix/position/all-position" {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+}
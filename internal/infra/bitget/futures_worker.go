@@ -14,51 +14,105 @@ import (
 
 // FuturesWorker handles Bitget Futures WebSocket using BaseWSWorker.
 type FuturesWorker struct {
-	base    *infra.BaseWSWorker
+	*infra.BaseWSWorker
 	symbols map[string]string
-	inbox   chan<- event.Event
+	inbox   infra.Outbox
 	seq     *uint64
+	funding *infra.FundingTracker
+
+	bookDepth int    // Order book levels per side (0 = books15 not subscribed)
+	trades    bool   // Subscribe to the trade channel
+	candle    string // Kline interval (domain.Candle1m...; "" = not subscribed)
 }
 
 // NewFuturesWorker factory.
 func NewFuturesWorker(symbols map[string]string, inbox chan<- event.Event, seq *uint64) *FuturesWorker {
 	w := &FuturesWorker{
 		symbols: symbols,
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     seq,
+		funding: infra.NewFundingTracker("BITGET_FUTURES", seq),
 	}
-	w.base = infra.NewBaseWSWorker(w)
+	w.BaseWSWorker = infra.NewBaseWSWorker(w)
+	w.Outbox = &w.inbox
 	return w
 }
 
 func (w *FuturesWorker) ID() string     { return "BITGET_FUTURES" }
 func (w *FuturesWorker) GetURL() string { return futuresWSURL }
 
+// SetOrderBookDepth subscribes to the books15 channel, keeping up to depth
+// (max 15) levels per side. Must be called before Connect.
+func (w *FuturesWorker) SetOrderBookDepth(depth int) {
+	w.bookDepth = min(depth, maxBookDepth)
+}
+
+// SetTrades subscribes to the trade channel (one TradeEvent per trade).
+// Must be called before Connect.
+func (w *FuturesWorker) SetTrades(enabled bool) {
+	w.trades = enabled
+}
+
+// SetCandleInterval subscribes to klines of interval (domain.Candle1m,
+// Candle5m or Candle1h; "" = off). Must be called before Connect.
+func (w *FuturesWorker) SetCandleInterval(interval string) {
+	w.candle = interval
+}
+
 func (w *FuturesWorker) Connect(ctx context.Context) error {
-	w.base.Start(ctx)
+	w.BaseWSWorker.Start(ctx)
 	return nil
 }
 
 func (w *FuturesWorker) Disconnect() {
-	w.base.Stop()
+	w.BaseWSWorker.Stop()
 }
 
 func (w *FuturesWorker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	w.inbox.Reconnected()
 	args := make([]subscribeArg, 0, len(w.symbols))
 	for _, id := range w.symbols {
 		// V2 API uses USDT-FUTURES
 		args = append(args, subscribeArg{InstType: "USDT-FUTURES", Channel: "ticker", InstId: id})
+		if w.bookDepth > 0 {
+			args = append(args, subscribeArg{InstType: "USDT-FUTURES", Channel: bookChannel, InstId: id})
+		}
+		if w.trades {
+			args = append(args, subscribeArg{InstType: "USDT-FUTURES", Channel: tradeChannel, InstId: id})
+		}
+		if ch, ok := candleChannels[w.candle]; ok {
+			args = append(args, subscribeArg{InstType: "USDT-FUTURES", Channel: ch, InstId: id})
+		}
 	}
 	req := subscribeRequest{Op: "subscribe", Args: args}
 	b, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal subscribe request: %w", err)
 	}
-	return w.base.Write(websocket.TextMessage, b)
+	return w.BaseWSWorker.Write(websocket.TextMessage, b)
 }
 
 func (w *FuturesWorker) OnMessage(ctx context.Context, msg []byte) {
 	if string(msg) == "pong" {
+		w.BaseWSWorker.NotifyPong()
+		return
+	}
+
+	// Route by channel first: kline rows are arrays and would not decode as tickers
+	var head pushHeader
+	if err := json.Unmarshal(msg, &head); err != nil {
+		return
+	}
+	if head.Arg.Channel == bookChannel && w.bookDepth > 0 {
+		w.publishBooks(parseBooks(msg, w.ID(), w.bookDepth, w.findSymbol, w.seq))
+		return
+	}
+	if head.Arg.Channel == tradeChannel && w.trades {
+		w.publishTrades(parseTrades(msg, w.ID(), w.findSymbol, w.seq))
+		return
+	}
+	if w.candle != "" && head.Arg.Channel == candleChannels[w.candle] {
+		w.publishCandles(parseCandles(msg, w.ID(), w.candle, w.findSymbol, w.seq))
 		return
 	}
 
@@ -86,16 +140,39 @@ func (w *FuturesWorker) OnMessage(ctx context.Context, msg []byte) {
 		ev.QtySats = quant.ToQtySatsStr(data.Volume24h)
 		ev.Exchange = "BITGET_FUTURES"
 
-		select {
-		case w.inbox <- ev:
-		default:
+		if !w.inbox.Send(ev) {
 			event.ReleaseMarketUpdateEvent(ev)
 		}
+
+		next, _ := quant.ParseTimeStamp(data.NextFundingTime)
+		if f := w.funding.Observe(symbol, next, int64(quant.ToPriceMicrosStr(data.FundingRate)), ts); f != nil {
+			if w.inbox.Send(f) {
+				w.funding.Sent(f)
+			}
+		}
 	}
 }
 
 func (w *FuturesWorker) OnPing(ctx context.Context, conn *websocket.Conn) error {
-	return w.base.Write(websocket.TextMessage, []byte("ping"))
+	return w.BaseWSWorker.Write(websocket.TextMessage, []byte("ping"))
+}
+
+func (w *FuturesWorker) publishBooks(books []*event.OrderBookUpdateEvent) {
+	for _, ev := range books {
+		w.inbox.Send(ev)
+	}
+}
+
+func (w *FuturesWorker) publishTrades(trades []*event.TradeEvent) {
+	for _, ev := range trades {
+		w.inbox.Send(ev)
+	}
+}
+
+func (w *FuturesWorker) publishCandles(candles []*event.CandleEvent) {
+	for _, ev := range candles {
+		w.inbox.Send(ev)
+	}
 }
 
 func (w *FuturesWorker) findSymbol(instId string) string {
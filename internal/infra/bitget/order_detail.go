@@ -0,0 +1,115 @@
+package bitget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"crypto_go/internal/infra"
+)
+
+// Order states of OrderDetail (V2, spot "cancelled" normalized).
+const (
+	OrderStateLive            = "live"
+	OrderStatePartiallyFilled = "partially_filled"
+	OrderStateFilled          = "filled"
+	OrderStateCanceled        = "canceled"
+)
+
+// OrderDetail is the venue state of one order, looked up by clientOid.
+// Quant: FilledSats is the accumulated fill, AvgPriceMicros its average price.
+type OrderDetail struct {
+	ClientOid      string
+	State          string // OrderState*
+	FilledSats     int64
+	AvgPriceMicros int64 // 0 = nothing filled
+}
+
+// orderDetailResponse is the wire form of a V2 order detail (futures "state",
+// spot "status").
+type orderDetailResponse struct {
+	ClientOid  string `json:"clientOid"`
+	State      string `json:"state"`
+	Status     string `json:"status"`
+	BaseVolume string `json:"baseVolume"`
+	PriceAvg   string `json:"priceAvg"`
+}
+
+func (r orderDetailResponse) toDetail() (OrderDetail, error) {
+	d := OrderDetail{ClientOid: r.ClientOid, State: r.State}
+	if d.State == "" {
+		d.State = r.Status
+	}
+	switch d.State {
+	case "cancelled":
+		d.State = OrderStateCanceled
+	case "init", "new":
+		d.State = OrderStateLive
+	}
+	var err error
+	if r.BaseVolume != "" {
+		if d.FilledSats, err = ParseValueToSats(r.BaseVolume); err != nil {
+			return d, fmt.Errorf("baseVolume: %w", err)
+		}
+	}
+	if r.PriceAvg != "" {
+		if d.AvgPriceMicros, err = ParseValueToMicros(r.PriceAvg); err != nil {
+			return d, fmt.Errorf("priceAvg: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// GetOrderDetail fetches a USDT-M futures order by clientOid (FUTURES V2).
+func (c *Client) GetOrderDetail(ctx context.Context, symbol, clientOid string) (OrderDetail, error) {
+	// Rate Limiting: Prevent IP ban (보안 강화)
+	infra.GetBitgetAccountLimiter().Wait()
+
+	q := url.Values{}
+	q.Set("symbol", symbol)
+	q.Set("productType", productTypeUSDTFutures)
+	q.Set("clientOid", clientOid)
+	resp, err := c.doRequest(ctx, "GET", "/api/v2/mix/order/detail?"+q.Encode(), nil)
+	if err != nil {
+		return OrderDetail{}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := c.parseResponse(resp)
+	if err != nil {
+		return OrderDetail{}, fmt.Errorf("get order detail error: %w", err)
+	}
+	var raw orderDetailResponse
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return OrderDetail{}, fmt.Errorf("failed to parse order detail json: %w", err)
+	}
+	return raw.toDetail()
+}
+
+// GetSpotOrderDetail fetches a spot order by clientOid (SPOT V2).
+func (c *Client) GetSpotOrderDetail(ctx context.Context, clientOid string) (OrderDetail, error) {
+	// Rate Limiting: Prevent IP ban (보안 강화)
+	infra.GetBitgetAccountLimiter().Wait()
+
+	q := url.Values{}
+	q.Set("clientOid", clientOid)
+	resp, err := c.doRequest(ctx, "GET", "/api/v2/spot/trade/orderInfo?"+q.Encode(), nil)
+	if err != nil {
+		return OrderDetail{}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := c.parseResponse(resp)
+	if err != nil {
+		return OrderDetail{}, fmt.Errorf("get spot order detail error: %w", err)
+	}
+	var raw []orderDetailResponse
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return OrderDetail{}, fmt.Errorf("failed to parse spot order detail json: %w", err)
+	}
+	if len(raw) == 0 {
+		return OrderDetail{}, fmt.Errorf("spot order %s not found", clientOid)
+	}
+	return raw[0].toDetail()
+}
@@ -0,0 +1,38 @@
+package bitget
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_GetOrderDetail(t *testing.T) {
+	client := newMockClient(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/api/v2/mix/order/detail":
+			if req.URL.Query().Get("clientOid") != "f1" || req.URL.Query().Get("productType") != productTypeUSDTFutures {
+				t.Errorf("unexpected query: %s", req.URL.RawQuery)
+			}
+			return okResponse(`{"clientOid":"f1","state":"partially_filled","baseVolume":"0.002","priceAvg":"50000.5"}`), nil
+		case "/api/v2/spot/trade/orderInfo":
+			return okResponse(`[{"clientOid":"s1","status":"cancelled","baseVolume":"0","priceAvg":""}]`), nil
+		}
+		t.Errorf("unexpected path: %s", req.URL.Path)
+		return okResponse(`{}`), nil
+	})
+
+	d, err := client.GetOrderDetail(context.Background(), "BTCUSDT", "f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.State != OrderStatePartiallyFilled || d.FilledSats != 200_000 || d.AvgPriceMicros != 50_000_500_000 {
+		t.Errorf("unexpected futures detail: %+v", d)
+	}
+	d, err = client.GetSpotOrderDetail(context.Background(), "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.State != OrderStateCanceled || d.FilledSats != 0 || d.AvgPriceMicros != 0 {
+		t.Errorf("unexpected spot detail: %+v", d)
+	}
+}
@@ -0,0 +1,62 @@
+package bitget
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// maxBookDepth is what the books15 channel delivers per side.
+const maxBookDepth = 15
+
+// parseBooks converts a books15 push into snapshot OrderBookUpdateEvents.
+// findSymbol maps instId to the unified symbol ("" = not subscribed).
+func parseBooks(msg []byte, exchange string, depth int, findSymbol func(string) string, seq *uint64) []*event.OrderBookUpdateEvent {
+	var resp bookResponse
+	if err := json.Unmarshal(msg, &resp); err != nil || resp.Arg.Channel != bookChannel {
+		return nil
+	}
+	symbol := findSymbol(resp.Arg.InstId)
+	if symbol == "" {
+		return nil
+	}
+
+	out := make([]*event.OrderBookUpdateEvent, 0, len(resp.Data))
+	for _, data := range resp.Data {
+		ms := resp.Ts
+		if v, err := strconv.ParseInt(data.Ts, 10, 64); err == nil {
+			ms = v
+		}
+		ev := &event.OrderBookUpdateEvent{
+			Symbol:   symbol,
+			Exchange: exchange,
+			Snapshot: true,
+			Bids:     toLevels(data.Bids, depth),
+			Asks:     toLevels(data.Asks, depth),
+		}
+		ev.Seq = quant.NextSeq(seq)
+		ev.Ts = quant.TimeStamp(ms * 1000)
+		out = append(out, ev)
+	}
+	return out
+}
+
+func toLevels(raw [][]string, depth int) []domain.BookLevel {
+	if len(raw) > depth {
+		raw = raw[:depth]
+	}
+	levels := make([]domain.BookLevel, 0, len(raw))
+	for _, l := range raw {
+		if len(l) < 2 {
+			continue
+		}
+		levels = append(levels, domain.BookLevel{
+			PriceMicros: quant.ToPriceMicrosStr(l[0]),
+			QtySats:     quant.ToQtySatsStr(l[1]),
+		})
+	}
+	return levels
+}
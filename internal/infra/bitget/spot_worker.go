@@ -14,50 +14,102 @@ import (
 
 // SpotWorker handles Bitget Spot WebSocket using BaseWSWorker.
 type SpotWorker struct {
-	base    *infra.BaseWSWorker
+	*infra.BaseWSWorker
 	symbols map[string]string
-	inbox   chan<- event.Event
+	inbox   infra.Outbox
 	seq     *uint64
+
+	bookDepth int    // Order book levels per side (0 = books15 not subscribed)
+	trades    bool   // Subscribe to the trade channel
+	candle    string // Kline interval (domain.Candle1m...; "" = not subscribed)
 }
 
 // NewSpotWorker factory.
 func NewSpotWorker(symbols map[string]string, inbox chan<- event.Event, seq *uint64) *SpotWorker {
 	w := &SpotWorker{
 		symbols: symbols,
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     seq,
 	}
-	w.base = infra.NewBaseWSWorker(w)
+	w.BaseWSWorker = infra.NewBaseWSWorker(w)
+	w.Outbox = &w.inbox
 	return w
 }
 
 func (w *SpotWorker) ID() string     { return "BITGET_SPOT" }
 func (w *SpotWorker) GetURL() string { return spotWSURL }
 
+// SetOrderBookDepth subscribes to the books15 channel, keeping up to depth
+// (max 15) levels per side. Must be called before Connect.
+func (w *SpotWorker) SetOrderBookDepth(depth int) {
+	w.bookDepth = min(depth, maxBookDepth)
+}
+
+// SetTrades subscribes to the trade channel (one TradeEvent per trade).
+// Must be called before Connect.
+func (w *SpotWorker) SetTrades(enabled bool) {
+	w.trades = enabled
+}
+
+// SetCandleInterval subscribes to klines of interval (domain.Candle1m,
+// Candle5m or Candle1h; "" = off). Must be called before Connect.
+func (w *SpotWorker) SetCandleInterval(interval string) {
+	w.candle = interval
+}
+
 func (w *SpotWorker) Connect(ctx context.Context) error {
-	w.base.Start(ctx)
+	w.BaseWSWorker.Start(ctx)
 	return nil
 }
 
 func (w *SpotWorker) Disconnect() {
-	w.base.Stop()
+	w.BaseWSWorker.Stop()
 }
 
 func (w *SpotWorker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	w.inbox.Reconnected()
 	args := make([]subscribeArg, 0, len(w.symbols))
 	for _, id := range w.symbols {
 		args = append(args, subscribeArg{InstType: "SPOT", Channel: "ticker", InstId: id})
+		if w.bookDepth > 0 {
+			args = append(args, subscribeArg{InstType: "SPOT", Channel: bookChannel, InstId: id})
+		}
+		if w.trades {
+			args = append(args, subscribeArg{InstType: "SPOT", Channel: tradeChannel, InstId: id})
+		}
+		if ch, ok := candleChannels[w.candle]; ok {
+			args = append(args, subscribeArg{InstType: "SPOT", Channel: ch, InstId: id})
+		}
 	}
 	req := subscribeRequest{Op: "subscribe", Args: args}
 	b, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal subscribe request: %w", err)
 	}
-	return w.base.Write(websocket.TextMessage, b)
+	return w.BaseWSWorker.Write(websocket.TextMessage, b)
 }
 
 func (w *SpotWorker) OnMessage(ctx context.Context, msg []byte) {
 	if string(msg) == "pong" {
+		w.BaseWSWorker.NotifyPong()
+		return
+	}
+
+	// Route by channel first: kline rows are arrays and would not decode as tickers
+	var head pushHeader
+	if err := json.Unmarshal(msg, &head); err != nil {
+		return
+	}
+	if head.Arg.Channel == bookChannel && w.bookDepth > 0 {
+		w.publishBooks(parseBooks(msg, w.ID(), w.bookDepth, w.findSymbol, w.seq))
+		return
+	}
+	if head.Arg.Channel == tradeChannel && w.trades {
+		w.publishTrades(parseTrades(msg, w.ID(), w.findSymbol, w.seq))
+		return
+	}
+	if w.candle != "" && head.Arg.Channel == candleChannels[w.candle] {
+		w.publishCandles(parseCandles(msg, w.ID(), w.candle, w.findSymbol, w.seq))
 		return
 	}
 
@@ -86,16 +138,32 @@ func (w *SpotWorker) OnMessage(ctx context.Context, msg []byte) {
 		ev.QtySats = quant.ToQtySatsStr(data.BaseVolume)
 		ev.Exchange = "BITGET_SPOT"
 
-		select {
-		case w.inbox <- ev:
-		default:
+		if !w.inbox.Send(ev) {
 			event.ReleaseMarketUpdateEvent(ev)
 		}
 	}
 }
 
 func (w *SpotWorker) OnPing(ctx context.Context, conn *websocket.Conn) error {
-	return w.base.Write(websocket.TextMessage, []byte("ping"))
+	return w.BaseWSWorker.Write(websocket.TextMessage, []byte("ping"))
+}
+
+func (w *SpotWorker) publishBooks(books []*event.OrderBookUpdateEvent) {
+	for _, ev := range books {
+		w.inbox.Send(ev)
+	}
+}
+
+func (w *SpotWorker) publishTrades(trades []*event.TradeEvent) {
+	for _, ev := range trades {
+		w.inbox.Send(ev)
+	}
+}
+
+func (w *SpotWorker) publishCandles(candles []*event.CandleEvent) {
+	for _, ev := range candles {
+		w.inbox.Send(ev)
+	}
 }
 
 func (w *SpotWorker) findSymbol(instId string) string {
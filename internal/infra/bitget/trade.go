@@ -0,0 +1,47 @@
+package bitget
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// parseTrades converts a trade channel update into TradeEvents. The snapshot
+// push (recent history) is skipped so a reconnect never replays old trades.
+// findSymbol maps instId to the unified symbol ("" = not subscribed).
+func parseTrades(msg []byte, exchange string, findSymbol func(string) string, seq *uint64) []*event.TradeEvent {
+	var resp tradeResponse
+	if err := json.Unmarshal(msg, &resp); err != nil || resp.Arg.Channel != tradeChannel || resp.Action != "update" {
+		return nil
+	}
+	symbol := findSymbol(resp.Arg.InstId)
+	if symbol == "" {
+		return nil
+	}
+
+	out := make([]*event.TradeEvent, 0, len(resp.Data))
+	for _, data := range resp.Data {
+		ms, err := strconv.ParseInt(data.Ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		side := domain.SideBuy
+		if data.Side == "sell" {
+			side = domain.SideSell
+		}
+		ev := &event.TradeEvent{
+			Symbol:      symbol,
+			Exchange:    exchange,
+			Side:        side,
+			PriceMicros: quant.ToPriceMicrosStr(data.Price),
+			QtySats:     quant.ToQtySatsStr(data.Size),
+		}
+		ev.Seq = quant.NextSeq(seq)
+		ev.Ts = quant.TimeStamp(ms * 1000)
+		out = append(out, ev)
+	}
+	return out
+}
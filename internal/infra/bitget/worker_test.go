@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
 
 	"github.com/gorilla/websocket"
 )
@@ -55,7 +56,7 @@ func TestSpotWorker_TickerParsing(t *testing.T) {
 	// Map: symbol -> instId (BTC -> BTCUSDT)
 	worker := &SpotWorker{
 		symbols: map[string]string{"BTC": "BTCUSDT"},
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     &seq,
 	}
 
@@ -106,7 +107,7 @@ func TestSpotWorker_IgnoreNonTicker(t *testing.T) {
 
 	worker := &SpotWorker{
 		symbols: map[string]string{"BTCUSDT": "BTC"},
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     &seq,
 	}
 
@@ -135,7 +136,7 @@ func TestFuturesWorker_TickerParsing(t *testing.T) {
 	// Map: symbol -> instId (BTC -> BTCUSDT)
 	worker := &FuturesWorker{
 		symbols: map[string]string{"BTC": "BTCUSDT"},
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     &seq,
 	}
 
@@ -183,7 +184,7 @@ func TestFuturesWorker_IgnoreNonTicker(t *testing.T) {
 
 	worker := &FuturesWorker{
 		symbols: map[string]string{"BTCUSDT": "BTC"},
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     &seq,
 	}
 
@@ -203,3 +204,120 @@ func TestFuturesWorker_IgnoreNonTicker(t *testing.T) {
 		// Success
 	}
 }
+
+func TestFuturesWorker_BookParsing(t *testing.T) {
+	inbox := make(chan event.Event, 10)
+	var seq uint64 = 0
+
+	worker := &FuturesWorker{
+		symbols: map[string]string{"BTC": "BTCUSDT"},
+		inbox:   infra.NewOutbox(inbox),
+		seq:     &seq,
+	}
+	worker.SetOrderBookDepth(2)
+
+	msg := []byte(`{"action":"snapshot","arg":{"instType":"USDT-FUTURES","channel":"books15","instId":"BTCUSDT"},
+		"data":[{"asks":[["92101.5","0.5"],["92102","1"],["92103","2"]],"bids":[["92100","1.25"]],"ts":"1704067200123"}],"ts":1704067200200}`)
+	worker.OnMessage(context.Background(), msg)
+
+	select {
+	case ev := <-inbox:
+		book, ok := ev.(*event.OrderBookUpdateEvent)
+		if !ok {
+			t.Fatalf("expected OrderBookUpdateEvent, got %T", ev)
+		}
+		if !book.Snapshot || book.Symbol != "BTC" || book.Exchange != "BITGET_FUTURES" {
+			t.Errorf("unexpected header: %+v", book)
+		}
+		if len(book.Asks) != 2 || len(book.Bids) != 1 {
+			t.Fatalf("depth not applied: %d asks, %d bids", len(book.Asks), len(book.Bids))
+		}
+		if book.Asks[0].PriceMicros != 92_101_500_000 || book.Bids[0].QtySats != 125_000_000 {
+			t.Errorf("levels = %+v / %+v", book.Asks[0], book.Bids[0])
+		}
+		if book.Ts != 1704067200123000 {
+			t.Errorf("ts = %d", book.Ts)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("no event received")
+	}
+
+	// Without a depth the channel is not subscribed and pushes are ignored
+	worker.SetOrderBookDepth(0)
+	worker.OnMessage(context.Background(), msg)
+	select {
+	case ev := <-inbox:
+		t.Errorf("unexpected event %+v", ev)
+	default:
+	}
+}
+
+func TestSpotWorker_TradeParsing(t *testing.T) {
+	inbox := make(chan event.Event, 10)
+	var seq uint64 = 0
+
+	worker := &SpotWorker{
+		symbols: map[string]string{"BTC": "BTCUSDT"},
+		inbox:   infra.NewOutbox(inbox),
+		seq:     &seq,
+	}
+	worker.SetTrades(true)
+
+	// The snapshot push is recent history: it must not produce events
+	worker.OnMessage(context.Background(), []byte(`{"action":"snapshot","arg":{"instType":"SPOT","channel":"trade","instId":"BTCUSDT"},
+		"data":[{"ts":"1704067199000","price":"92000","size":"1","side":"buy","tradeId":"1"}],"ts":1704067200000}`))
+	worker.OnMessage(context.Background(), []byte(`{"action":"update","arg":{"instType":"SPOT","channel":"trade","instId":"BTCUSDT"},
+		"data":[{"ts":"1704067200123","price":"92100.5","size":"0.25","side":"sell","tradeId":"2"},
+		{"ts":"1704067200124","price":"92101","size":"0.1","side":"buy","tradeId":"3"}],"ts":1704067200200}`))
+
+	var trades []*event.TradeEvent
+	for len(inbox) > 0 {
+		trades = append(trades, (<-inbox).(*event.TradeEvent))
+	}
+	if len(trades) != 2 {
+		t.Fatalf("expected 2 trades, got %d", len(trades))
+	}
+	if tr := trades[0]; tr.Symbol != "BTC" || tr.Exchange != "BITGET_SPOT" || tr.Side != "SELL" ||
+		tr.PriceMicros != 92_100_500_000 || tr.QtySats != 25_000_000 || tr.Ts != 1704067200123000 {
+		t.Errorf("unexpected trade %+v", tr)
+	}
+	if trades[1].Side != "BUY" {
+		t.Errorf("side = %s", trades[1].Side)
+	}
+}
+
+func TestFuturesWorker_CandleParsing(t *testing.T) {
+	inbox := make(chan event.Event, 10)
+	var seq uint64 = 0
+
+	worker := &FuturesWorker{
+		symbols: map[string]string{"BTC": "BTCUSDT"},
+		inbox:   infra.NewOutbox(inbox),
+		seq:     &seq,
+	}
+	worker.SetCandleInterval("1h")
+
+	// Snapshot history (newest first): only the last closed bar and the open bar are published
+	worker.OnMessage(context.Background(), []byte(`{"action":"snapshot","arg":{"instType":"USDT-FUTURES","channel":"candle1H","instId":"BTCUSDT"},
+		"data":[["1704074400000","92000","92100","91900","92050","12.5","0","0"],
+		["1704070800000","91000","92000","90900","92000","20","0","0"],
+		["1704067200000","90000","91000","89900","91000","30","0","0"]],"ts":1704074500000}`))
+
+	var candles []*event.CandleEvent
+	for len(inbox) > 0 {
+		candles = append(candles, (<-inbox).(*event.CandleEvent))
+	}
+	if len(candles) != 2 {
+		t.Fatalf("expected 2 candles, got %d", len(candles))
+	}
+	if candles[0].OpenTime != 1704070800000000 || candles[1].OpenTime != 1704074400000000 {
+		t.Errorf("bars not oldest first: %d, %d", candles[0].OpenTime, candles[1].OpenTime)
+	}
+	if c := candles[1]; c.Symbol != "BTC" || c.Exchange != "BITGET_FUTURES" || c.Interval != "1h" ||
+		c.OpenMicros != 92_000_000_000 || c.CloseMicros != 92_050_000_000 || c.VolumeSats != 1_250_000_000 {
+		t.Errorf("unexpected candle %+v", c)
+	}
+	if candles[0].Seq >= candles[1].Seq {
+		t.Errorf("seqs must follow bar order: %d, %d", candles[0].Seq, candles[1].Seq)
+	}
+}
@@ -0,0 +1,117 @@
+// Package bithumb streams Bithumb KRW market tickers.
+package bithumb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultWSURL is Bithumb's public WebSocket (v2, Upbit-compatible message format).
+const DefaultWSURL = "wss://ws-api.bithumb.com/websocket/v1"
+
+// Exchange is the event Exchange tag of this gateway.
+const Exchange = "BITHUMB"
+
+// tickerResponse represents a Bithumb ticker push.
+// Uses json.Number to avoid float64 precision issues (Rule #1: No Float in Hotpath).
+type tickerResponse struct {
+	Type string `json:"type"` // ticker
+	Code string `json:"code"` // KRW-BTC
+
+	TradePrice        json.Number `json:"trade_price"`
+	AccTradeVolume24h json.Number `json:"acc_trade_volume_24h"`
+	Timestamp         int64       `json:"timestamp"` // Milliseconds
+}
+
+// Worker handles the Bithumb WebSocket connection using BaseWSWorker.
+type Worker struct {
+	*infra.BaseWSWorker
+	url     string
+	symbols []string
+	inbox   infra.Outbox
+	seq     *uint64
+}
+
+// NewWorker creates a Bithumb gateway worker for the KRW markets of symbols.
+// An empty url uses DefaultWSURL.
+func NewWorker(url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	if url == "" {
+		url = DefaultWSURL
+	}
+	w := &Worker{
+		url:     url,
+		symbols: symbols,
+		inbox:   infra.NewOutbox(inbox),
+		seq:     seq,
+	}
+	w.BaseWSWorker = infra.NewBaseWSWorker(w)
+	w.Outbox = &w.inbox
+	return w
+}
+
+func (w *Worker) ID() string     { return Exchange }
+func (w *Worker) GetURL() string { return w.url }
+
+func (w *Worker) Connect(ctx context.Context) error {
+	w.BaseWSWorker.Start(ctx)
+	return nil
+}
+
+func (w *Worker) Disconnect() {
+	w.BaseWSWorker.Stop()
+}
+
+func (w *Worker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	w.inbox.Reconnected()
+	codes := make([]string, 0, len(w.symbols))
+	for _, s := range w.symbols {
+		codes = append(codes, "KRW-"+s)
+	}
+
+	msg := []map[string]interface{}{
+		{"ticket": fmt.Sprintf("go-%d", time.Now().UnixNano())},
+		{"type": "ticker", "codes": codes},
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscribe message: %w", err)
+	}
+	return w.BaseWSWorker.Write(websocket.TextMessage, b)
+}
+
+func (w *Worker) OnMessage(ctx context.Context, msg []byte) {
+	var resp tickerResponse
+	if err := json.Unmarshal(msg, &resp); err != nil || resp.Type != "ticker" {
+		return
+	}
+	symbol, ok := strings.CutPrefix(resp.Code, "KRW-")
+	if !ok {
+		return
+	}
+
+	ev := event.AcquireMarketUpdateEvent()
+	ev.Seq = quant.NextSeq(w.seq)
+	ev.Ts = quant.TimeStamp(resp.Timestamp * 1000)
+	ev.Symbol = symbol
+	ev.PriceMicros = quant.ToPriceMicrosStr(resp.TradePrice.String())
+	ev.QtySats = quant.ToQtySatsStr(resp.AccTradeVolume24h.String())
+	ev.Exchange = Exchange
+
+	if !w.inbox.Send(ev) {
+		event.ReleaseMarketUpdateEvent(ev)
+	}
+}
+
+// OnPing sends a WebSocket ping frame; BaseWSWorker times the pong for RTT.
+func (w *Worker) OnPing(ctx context.Context, conn *websocket.Conn) error {
+	return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
+}
@@ -0,0 +1,53 @@
+package bithumb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/event"
+)
+
+func TestWorker_TickerParsing(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	var seq uint64
+	w := NewWorker("", []string{"BTC"}, inbox, &seq)
+
+	w.OnMessage(context.Background(), []byte(`{"type":"ticker","code":"KRW-BTC","trade_price":135000000,"acc_trade_volume_24h":1234.56789,"timestamp":1704067200000}`))
+
+	select {
+	case ev := <-inbox:
+		m := ev.(*event.MarketUpdateEvent)
+		if m.Symbol != "BTC" || m.Exchange != "BITHUMB" || m.Seq != 1 {
+			t.Errorf("unexpected event %+v", m)
+		}
+		if m.PriceMicros != 135_000_000_000_000 || m.QtySats != 123_456_789_000 {
+			t.Errorf("price %d, qty %d", m.PriceMicros, m.QtySats)
+		}
+		if m.Ts != 1704067200000*1000 {
+			t.Errorf("ts should be micros, got %d", m.Ts)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("no event received")
+	}
+}
+
+func TestWorker_IgnoresOtherMessages(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	var seq uint64
+	w := NewWorker("", []string{"BTC"}, inbox, &seq)
+
+	for _, msg := range []string{
+		`{"type":"orderbook","code":"KRW-BTC"}`,
+		`{"type":"ticker","code":"BTC-ETH","trade_price":0.05}`,
+		`{"status":"UP"}`,
+		`not json`,
+	} {
+		w.OnMessage(context.Background(), []byte(msg))
+	}
+	select {
+	case ev := <-inbox:
+		t.Errorf("unexpected event %+v", ev)
+	default:
+	}
+}
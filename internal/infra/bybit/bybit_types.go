@@ -0,0 +1,56 @@
+package bybit
+
+import "strings"
+
+// Bybit v5 public (login-free) WebSocket endpoints, one per category.
+const (
+	DefaultSpotWSURL   = "wss://stream.bybit.com/v5/public/spot"
+	DefaultLinearWSURL = "wss://stream.bybit.com/v5/public/linear"
+)
+
+// Unified symbols are quoted in USDT on Bybit, like Bitget.
+const quoteCurrency = "USDT"
+
+const tickerTopicPrefix = "tickers."
+
+// Ticker returns the Bybit symbol of a unified symbol ("BTC" -> "BTCUSDT").
+// Spot and linear perpetuals share the same name.
+func Ticker(symbol string) string {
+	return symbol + quoteCurrency
+}
+
+// UnifiedSymbol maps a USDT ticker back to the unified symbol ("BTCUSDT" -> "BTC").
+// Returns "" for other quotes.
+func UnifiedSymbol(ticker string) string {
+	base, ok := strings.CutSuffix(ticker, quoteCurrency)
+	if !ok || base == "" {
+		return ""
+	}
+	return base
+}
+
+type opRequest struct {
+	Op   string   `json:"op"`
+	Args []string `json:"args,omitempty"`
+}
+
+// tickerResponse is a push on a "tickers.*" topic. Operation acks (subscribe,
+// pong) carry "op" instead of "topic".
+type tickerResponse struct {
+	Op      string     `json:"op"`
+	Success *bool      `json:"success"`
+	RetMsg  string     `json:"ret_msg"`
+	Topic   string     `json:"topic"`
+	Type    string     `json:"type"` // snapshot | delta (linear sends only changed fields)
+	Ts      int64      `json:"ts"`   // Milliseconds
+	Data    tickerData `json:"data"`
+}
+
+type tickerData struct {
+	Symbol    string `json:"symbol"`
+	LastPrice string `json:"lastPrice"`
+	Volume24h string `json:"volume24h"` // Base currency (spot and USDT linear)
+
+	FundingRate     string `json:"fundingRate"`     // Linear: predicted rate of the next epoch
+	NextFundingTime string `json:"nextFundingTime"` // Linear: Unix millis
+}
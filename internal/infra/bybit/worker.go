@@ -0,0 +1,174 @@
+package bybit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+
+	"github.com/gorilla/websocket"
+)
+
+// pingInterval follows Bybit's recommended 20 second heartbeat.
+const pingInterval = 20 * time.Second
+
+// Worker streams Bybit public tickers for one category (spot or linear) using
+// BaseWSWorker, which also provides the reconnect backoff (infra.CalculateBackoff).
+type Worker struct {
+	*infra.BaseWSWorker
+	id      string
+	url     string
+	symbols map[string]string // Bybit ticker -> unified symbol
+	inbox   infra.Outbox
+	seq     *uint64
+
+	// Linear deltas omit unchanged fields; the last full values fill them in.
+	// Only touched from the read loop.
+	last map[string]tickerData
+
+	funding *infra.FundingTracker // Linear only
+}
+
+// NewSpotWorker creates a worker for USDT spot tickers of the unified symbols.
+func NewSpotWorker(url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	if url == "" {
+		url = DefaultSpotWSURL
+	}
+	return newWorker("BYBIT_SPOT", url, symbols, inbox, seq)
+}
+
+// NewLinearWorker creates a worker for USDT linear perpetual tickers of the unified symbols.
+func NewLinearWorker(url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	if url == "" {
+		url = DefaultLinearWSURL
+	}
+	w := newWorker("BYBIT_LINEAR", url, symbols, inbox, seq)
+	w.funding = infra.NewFundingTracker(w.id, seq)
+	return w
+}
+
+func newWorker(id, url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	w := &Worker{
+		id:      id,
+		url:     url,
+		symbols: make(map[string]string, len(symbols)),
+		inbox:   infra.NewOutbox(inbox),
+		seq:     seq,
+		last:    make(map[string]tickerData, len(symbols)),
+	}
+	for _, s := range symbols {
+		w.symbols[Ticker(s)] = s
+	}
+	w.BaseWSWorker = infra.NewBaseWSWorker(w)
+	w.Outbox = &w.inbox
+	w.BaseWSWorker.PingInterval = pingInterval
+	return w
+}
+
+func (w *Worker) ID() string     { return w.id }
+func (w *Worker) GetURL() string { return w.url }
+
+func (w *Worker) Connect(ctx context.Context) error {
+	w.BaseWSWorker.Start(ctx)
+	return nil
+}
+
+func (w *Worker) Disconnect() {
+	w.BaseWSWorker.Stop()
+}
+
+func (w *Worker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	clear(w.last) // A new subscription starts with snapshots
+	w.inbox.Reconnected()
+	args := make([]string, 0, len(w.symbols))
+	for ticker := range w.symbols {
+		args = append(args, tickerTopicPrefix+ticker)
+	}
+	b, err := json.Marshal(opRequest{Op: "subscribe", Args: args})
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscribe request: %w", err)
+	}
+	return w.BaseWSWorker.Write(websocket.TextMessage, b)
+}
+
+func (w *Worker) OnMessage(ctx context.Context, msg []byte) {
+	var resp tickerResponse
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+
+	switch resp.Op {
+	case "":
+	case "pong", "ping": // Linear answers "pong"; spot echoes "ping" with ret_msg "pong"
+		w.BaseWSWorker.NotifyPong()
+		return
+	default:
+		if resp.Success != nil && !*resp.Success {
+			slog.Warn("Bybit request failed", slog.String("gateway", w.id), slog.String("op", resp.Op), slog.String("msg", resp.RetMsg))
+		}
+		return
+	}
+
+	if !strings.HasPrefix(resp.Topic, tickerTopicPrefix) {
+		return
+	}
+	symbol, ok := w.symbols[resp.Data.Symbol]
+	if !ok {
+		return
+	}
+
+	data := resp.Data
+	if resp.Type == "delta" {
+		prev := w.last[data.Symbol]
+		if data.LastPrice == "" {
+			data.LastPrice = prev.LastPrice
+		}
+		if data.Volume24h == "" {
+			data.Volume24h = prev.Volume24h
+		}
+		if data.FundingRate == "" {
+			data.FundingRate = prev.FundingRate
+		}
+		if data.NextFundingTime == "" {
+			data.NextFundingTime = prev.NextFundingTime
+		}
+	}
+	w.last[data.Symbol] = data
+	if data.LastPrice == "" {
+		return // Delta before any snapshot
+	}
+
+	ts := quant.TimeStamp(resp.Ts * 1000)
+	ev := event.AcquireMarketUpdateEvent()
+	ev.Seq = quant.NextSeq(w.seq)
+	ev.Ts = ts
+	ev.Symbol = symbol
+	ev.PriceMicros = quant.ToPriceMicrosStr(data.LastPrice)
+	ev.QtySats = quant.ToQtySatsStr(data.Volume24h)
+	ev.Exchange = w.id
+
+	if !w.inbox.Send(ev) {
+		event.ReleaseMarketUpdateEvent(ev)
+	}
+
+	if w.funding == nil {
+		return
+	}
+	next, _ := quant.ParseTimeStamp(data.NextFundingTime)
+	if f := w.funding.Observe(symbol, next, int64(quant.ToPriceMicrosStr(data.FundingRate)), ts); f != nil {
+		if w.inbox.Send(f) {
+			w.funding.Sent(f)
+		}
+	}
+}
+
+// OnPing sends Bybit's JSON heartbeat.
+func (w *Worker) OnPing(ctx context.Context, conn *websocket.Conn) error {
+	return w.BaseWSWorker.Write(websocket.TextMessage, []byte(`{"op":"ping"}`))
+}
@@ -0,0 +1,137 @@
+package bybit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/event"
+)
+
+func TestSymbolMapping(t *testing.T) {
+	if Ticker("BTC") != "BTCUSDT" {
+		t.Errorf("Ticker(BTC) = %s", Ticker("BTC"))
+	}
+	cases := map[string]string{
+		"BTCUSDT":      "BTC",
+		"1000PEPEUSDT": "1000PEPE",
+		"BTCUSDC":      "",
+		"USDT":         "",
+	}
+	for ticker, want := range cases {
+		if got := UnifiedSymbol(ticker); got != want {
+			t.Errorf("UnifiedSymbol(%q) = %q, want %q", ticker, got, want)
+		}
+	}
+}
+
+func receive(t *testing.T, inbox chan event.Event) *event.MarketUpdateEvent {
+	t.Helper()
+	select {
+	case ev := <-inbox:
+		return ev.(*event.MarketUpdateEvent)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("no event received")
+		return nil
+	}
+}
+
+func TestWorker_SpotTicker(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	var seq uint64
+	w := NewSpotWorker("", []string{"BTC"}, inbox, &seq)
+
+	w.OnMessage(context.Background(), []byte(`{"topic":"tickers.BTCUSDT","type":"snapshot","ts":1704067200000,"cs":1,"data":{"symbol":"BTCUSDT","lastPrice":"92000.5","volume24h":"1234.5"}}`))
+
+	m := receive(t, inbox)
+	if m.Symbol != "BTC" || m.Exchange != "BYBIT_SPOT" || m.Seq != 1 {
+		t.Errorf("unexpected event %+v", m)
+	}
+	if m.PriceMicros != 92_000_500_000 || m.QtySats != 123_450_000_000 {
+		t.Errorf("price %d, qty %d", m.PriceMicros, m.QtySats)
+	}
+	if m.Ts != 1704067200000*1000 {
+		t.Errorf("ts should be micros, got %d", m.Ts)
+	}
+}
+
+func TestWorker_LinearDeltaMerge(t *testing.T) {
+	inbox := make(chan event.Event, 2)
+	var seq uint64
+	w := NewLinearWorker("", []string{"BTC"}, inbox, &seq)
+	ctx := context.Background()
+
+	// Delta before any snapshot has no price to report
+	w.OnMessage(ctx, []byte(`{"topic":"tickers.BTCUSDT","type":"delta","ts":1,"data":{"symbol":"BTCUSDT","volume24h":"10"}}`))
+	select {
+	case ev := <-inbox:
+		t.Fatalf("unexpected event %+v", ev)
+	default:
+	}
+
+	w.OnMessage(ctx, []byte(`{"topic":"tickers.BTCUSDT","type":"snapshot","ts":2,"data":{"symbol":"BTCUSDT","lastPrice":"100","volume24h":"10"}}`))
+	receive(t, inbox)
+
+	// Price-only delta keeps the last volume
+	w.OnMessage(ctx, []byte(`{"topic":"tickers.BTCUSDT","type":"delta","ts":3,"data":{"symbol":"BTCUSDT","lastPrice":"101"}}`))
+	m := receive(t, inbox)
+	if m.Exchange != "BYBIT_LINEAR" || m.PriceMicros != 101_000_000 || m.QtySats != 1_000_000_000 {
+		t.Errorf("merged delta = %+v", m)
+	}
+}
+
+func TestWorker_IgnoresAcksAndUnknownSymbols(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	var seq uint64
+	w := NewLinearWorker("", []string{"BTC"}, inbox, &seq)
+	ctx := context.Background()
+
+	for _, msg := range []string{
+		`{"success":true,"ret_msg":"","op":"subscribe"}`,
+		`{"success":false,"ret_msg":"error:handler not found","op":"subscribe"}`,
+		`{"op":"pong","args":["1675418560633"]}`,
+		`{"topic":"tickers.ETHUSDT","type":"snapshot","ts":1,"data":{"symbol":"ETHUSDT","lastPrice":"3000"}}`,
+		`not json`,
+	} {
+		w.OnMessage(ctx, []byte(msg))
+	}
+
+	select {
+	case ev := <-inbox:
+		t.Errorf("unexpected event %+v", ev)
+	default:
+	}
+	if seq != 0 {
+		t.Errorf("seq advanced to %d without events", seq)
+	}
+}
+
+func TestWorker_LinearFunding(t *testing.T) {
+	inbox := make(chan event.Event, 8)
+	var seq uint64
+	w := NewLinearWorker("", []string{"BTC"}, inbox, &seq)
+	ctx := context.Background()
+
+	w.OnMessage(ctx, []byte(`{"topic":"tickers.BTCUSDT","type":"snapshot","ts":1000,"data":{"symbol":"BTCUSDT","lastPrice":"100","volume24h":"10","fundingRate":"0.0001","nextFundingTime":"1704096000000"}}`))
+	receive(t, inbox)
+	f, ok := (<-inbox).(*event.FundingEvent)
+	if !ok || f.Exchange != "BYBIT_LINEAR" || f.Symbol != "BTC" || f.NextFundingTs != 1704096000000*1000 || f.RateMicros != 100 || f.Seq != 2 {
+		t.Fatalf("unexpected funding event %+v", f)
+	}
+
+	// Deltas keep the epoch: a rate change within the refresh interval is not re-reported
+	w.OnMessage(ctx, []byte(`{"topic":"tickers.BTCUSDT","type":"delta","ts":2000,"data":{"symbol":"BTCUSDT","lastPrice":"101","fundingRate":"0.00012"}}`))
+	receive(t, inbox)
+	select {
+	case ev := <-inbox:
+		t.Fatalf("unexpected event %+v", ev)
+	default:
+	}
+
+	// A new epoch is reported at once
+	w.OnMessage(ctx, []byte(`{"topic":"tickers.BTCUSDT","type":"delta","ts":3000,"data":{"symbol":"BTCUSDT","lastPrice":"102","nextFundingTime":"1704124800000"}}`))
+	receive(t, inbox)
+	if f := (<-inbox).(*event.FundingEvent); f.NextFundingTs != 1704124800000*1000 || f.RateMicros != 120 {
+		t.Errorf("unexpected funding event after the epoch moved %+v", f)
+	}
+}
@@ -1,11 +1,17 @@
 package infra
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
 )
 
+// ErrCircuitOpen is returned by Execute while the breaker rejects calls.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
 // State represents the circuit breaker state.
 type State int
 
@@ -29,7 +35,11 @@ func (s State) String() string {
 }
 
 // CircuitBreaker implements the circuit breaker pattern for fault isolation.
-// Thread-safe for concurrent use.
+// After FailureThreshold consecutive failures it opens and rejects calls at
+// once instead of letting them wait for a dead API to time out. After Timeout
+// it lets one probe call through at a time (half-open) and closes again after
+// SuccessThreshold successful probes. State changes are exported through
+// GlobalMetrics. Thread-safe for concurrent use.
 type CircuitBreaker struct {
 	name string
 	mu   sync.RWMutex
@@ -38,6 +48,7 @@ type CircuitBreaker struct {
 	failureCount int
 	successCount int
 	lastFailure  time.Time
+	probing      bool // A half-open probe is in flight
 
 	// Configuration
 	failureThreshold int           // Failures before opening
@@ -87,8 +98,9 @@ func (cb *CircuitBreaker) Allow() bool {
 	case StateOpen:
 		// Check if timeout has passed
 		if time.Since(cb.lastFailure) > cb.timeout {
-			cb.state = StateHalfOpen
+			cb.setState(StateHalfOpen)
 			cb.successCount = 0
+			cb.probing = true
 			slog.Info("Circuit breaker transitioning to HALF_OPEN",
 				slog.String("name", cb.name))
 			return true
@@ -96,7 +108,11 @@ func (cb *CircuitBreaker) Allow() bool {
 		return false
 
 	case StateHalfOpen:
-		// Allow limited requests in half-open state
+		// One probe at a time, so a still-dead API costs a single timeout
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
 		return true
 
 	default:
@@ -109,6 +125,7 @@ func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	cb.probing = false
 	switch cb.state {
 	case StateClosed:
 		cb.failureCount = 0
@@ -116,7 +133,7 @@ func (cb *CircuitBreaker) RecordSuccess() {
 	case StateHalfOpen:
 		cb.successCount++
 		if cb.successCount >= cb.successThreshold {
-			cb.state = StateClosed
+			cb.setState(StateClosed)
 			cb.failureCount = 0
 			cb.successCount = 0
 			slog.Info("Circuit breaker CLOSED (recovered)",
@@ -131,12 +148,13 @@ func (cb *CircuitBreaker) RecordFailure() {
 	defer cb.mu.Unlock()
 
 	cb.lastFailure = time.Now()
+	cb.probing = false
 
 	switch cb.state {
 	case StateClosed:
 		cb.failureCount++
 		if cb.failureCount >= cb.failureThreshold {
-			cb.state = StateOpen
+			cb.setState(StateOpen)
 			slog.Warn("Circuit breaker OPEN (failures exceeded threshold)",
 				slog.String("name", cb.name),
 				slog.Int("failures", cb.failureCount))
@@ -144,13 +162,46 @@ func (cb *CircuitBreaker) RecordFailure() {
 
 	case StateHalfOpen:
 		// Any failure in half-open returns to open
-		cb.state = StateOpen
+		cb.setState(StateOpen)
 		cb.successCount = 0
 		slog.Warn("Circuit breaker OPEN (half-open test failed)",
 			slog.String("name", cb.name))
 	}
 }
 
+// Execute runs fn if the breaker allows it and records the outcome: an error
+// counts as a failure, nil as a success. A call canceled by its context
+// (shutdown, caller gave up) is not held against the API. Rejected calls
+// return an error wrapping ErrCircuitOpen without running fn.
+func (cb *CircuitBreaker) Execute(ctx context.Context, fn func() error) error {
+	if !cb.Allow() {
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, cb.name)
+	}
+	err := fn()
+	switch {
+	case err == nil:
+		cb.RecordSuccess()
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		cb.release()
+	default:
+		cb.RecordFailure()
+	}
+	return err
+}
+
+// release ends a probe without a verdict.
+func (cb *CircuitBreaker) release() {
+	cb.mu.Lock()
+	cb.probing = false
+	cb.mu.Unlock()
+}
+
+// setState changes the state and exports it (caller holds cb.mu).
+func (cb *CircuitBreaker) setState(s State) {
+	cb.state = s
+	GlobalMetrics.SetCircuitBreakerState(cb.name, s)
+}
+
 // GetState returns the current state (for monitoring).
 func (cb *CircuitBreaker) GetState() State {
 	cb.mu.RLock()
@@ -163,8 +214,9 @@ func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cb.state = StateClosed
+	cb.setState(StateClosed)
 	cb.failureCount = 0
 	cb.successCount = 0
+	cb.probing = false
 	slog.Info("Circuit breaker RESET", slog.String("name", cb.name))
 }
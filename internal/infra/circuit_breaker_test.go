@@ -1,6 +1,8 @@
 package infra
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -128,3 +130,64 @@ func TestCircuitBreaker_Reset(t *testing.T) {
 		t.Error("Expected Allow() to return true after Reset")
 	}
 }
+
+func TestCircuitBreaker_Execute(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		Name:             "test-execute",
+		FailureThreshold: 2,
+		SuccessThreshold: 1,
+		Timeout:          10 * time.Millisecond,
+	})
+	ctx := context.Background()
+	fail := errors.New("timeout")
+
+	cb.Execute(ctx, func() error { return fail })
+	if err := cb.Execute(ctx, func() error { return fail }); err != fail {
+		t.Errorf("Execute must return the call's error, got %v", err)
+	}
+	ran := false
+	if err := cb.Execute(ctx, func() error { ran = true; return nil }); !errors.Is(err, ErrCircuitOpen) || ran {
+		t.Fatalf("open breaker must reject without calling: err=%v ran=%v", err, ran)
+	}
+	if !GlobalMetrics.Snapshot().CircuitOpen || GlobalMetrics.Snapshot().CircuitStates["test-execute"] != "OPEN" {
+		t.Error("open breaker must be exported")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if err := cb.Execute(ctx, func() error { return nil }); err != nil {
+		t.Fatalf("probe after timeout must run: %v", err)
+	}
+	if cb.GetState() != StateClosed || GlobalMetrics.Snapshot().CircuitStates["test-execute"] != "CLOSED" {
+		t.Errorf("expected CLOSED after a successful probe, got %s", cb.GetState())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenSingleProbe(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		Name:             "test",
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		Timeout:          10 * time.Millisecond,
+	})
+	cb.RecordFailure()
+	time.Sleep(15 * time.Millisecond)
+
+	// A canceled probe gives no verdict and frees the slot
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cb.Execute(ctx, func() error { return ctx.Err() })
+	if cb.GetState() != StateHalfOpen {
+		t.Fatalf("canceled probe must leave HALF_OPEN, got %s", cb.GetState())
+	}
+
+	if !cb.Allow() {
+		t.Fatal("next probe must be allowed")
+	}
+	if cb.Allow() {
+		t.Error("only one probe may be in flight")
+	}
+	cb.RecordSuccess()
+	if cb.GetState() != StateClosed {
+		t.Errorf("expected CLOSED, got %s", cb.GetState())
+	}
+}
@@ -0,0 +1,149 @@
+// Package coinone streams Coinone KRW market tickers.
+package coinone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultWSURL is Coinone's public WebSocket endpoint.
+const DefaultWSURL = "wss://stream.coinone.co.kr"
+
+// Exchange is the event Exchange tag of this gateway.
+const Exchange = "COINONE"
+
+// pingInterval keeps the connection well inside Coinone's idle timeout.
+const pingInterval = 20 * time.Second
+
+type subscribeRequest struct {
+	RequestType string `json:"request_type"` // SUBSCRIBE | PING
+	Channel     string `json:"channel,omitempty"`
+	Topic       *topic `json:"topic,omitempty"`
+}
+
+type topic struct {
+	QuoteCurrency  string `json:"quote_currency"`
+	TargetCurrency string `json:"target_currency"`
+}
+
+// tickerResponse is any server message; only DATA on TICKER carries a ticker.
+type tickerResponse struct {
+	ResponseType string     `json:"response_type"` // DATA | PONG | SUBSCRIBED | ERROR
+	Channel      string     `json:"channel"`
+	Message      string     `json:"message"`
+	Data         tickerData `json:"data"`
+}
+
+type tickerData struct {
+	QuoteCurrency  string `json:"quote_currency"`
+	TargetCurrency string `json:"target_currency"`
+	Timestamp      int64  `json:"timestamp"` // Milliseconds
+	Last           string `json:"last"`
+	TargetVolume   string `json:"target_volume"` // 24h base currency volume
+}
+
+// Worker handles the Coinone WebSocket connection using BaseWSWorker.
+type Worker struct {
+	*infra.BaseWSWorker
+	url     string
+	symbols []string
+	inbox   infra.Outbox
+	seq     *uint64
+}
+
+// NewWorker creates a Coinone gateway worker for the KRW markets of symbols.
+// An empty url uses DefaultWSURL.
+func NewWorker(url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	if url == "" {
+		url = DefaultWSURL
+	}
+	w := &Worker{
+		url:     url,
+		symbols: symbols,
+		inbox:   infra.NewOutbox(inbox),
+		seq:     seq,
+	}
+	w.BaseWSWorker = infra.NewBaseWSWorker(w)
+	w.Outbox = &w.inbox
+	w.BaseWSWorker.PingInterval = pingInterval
+	return w
+}
+
+func (w *Worker) ID() string     { return Exchange }
+func (w *Worker) GetURL() string { return w.url }
+
+func (w *Worker) Connect(ctx context.Context) error {
+	w.BaseWSWorker.Start(ctx)
+	return nil
+}
+
+func (w *Worker) Disconnect() {
+	w.BaseWSWorker.Stop()
+}
+
+// OnConnect subscribes each symbol; Coinone takes one topic per request.
+func (w *Worker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	w.inbox.Reconnected()
+	for _, s := range w.symbols {
+		b, err := json.Marshal(subscribeRequest{
+			RequestType: "SUBSCRIBE",
+			Channel:     "TICKER",
+			Topic:       &topic{QuoteCurrency: "KRW", TargetCurrency: s},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal subscribe request: %w", err)
+		}
+		if err := w.BaseWSWorker.Write(websocket.TextMessage, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) OnMessage(ctx context.Context, msg []byte) {
+	var resp tickerResponse
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+
+	switch resp.ResponseType {
+	case "PONG":
+		w.BaseWSWorker.NotifyPong()
+		return
+	case "ERROR":
+		slog.Warn("Coinone request failed", slog.String("message", resp.Message))
+		return
+	case "DATA":
+	default:
+		return
+	}
+	if resp.Channel != "TICKER" || resp.Data.QuoteCurrency != "KRW" || resp.Data.TargetCurrency == "" {
+		return
+	}
+
+	ev := event.AcquireMarketUpdateEvent()
+	ev.Seq = quant.NextSeq(w.seq)
+	ev.Ts = quant.TimeStamp(resp.Data.Timestamp * 1000)
+	ev.Symbol = resp.Data.TargetCurrency
+	ev.PriceMicros = quant.ToPriceMicrosStr(resp.Data.Last)
+	ev.QtySats = quant.ToQtySatsStr(resp.Data.TargetVolume)
+	ev.Exchange = Exchange
+
+	if !w.inbox.Send(ev) {
+		event.ReleaseMarketUpdateEvent(ev)
+	}
+}
+
+// OnPing sends Coinone's JSON heartbeat; the server answers PONG.
+func (w *Worker) OnPing(ctx context.Context, conn *websocket.Conn) error {
+	return w.BaseWSWorker.Write(websocket.TextMessage, []byte(`{"request_type":"PING"}`))
+}
@@ -0,0 +1,57 @@
+package coinone
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/event"
+)
+
+func TestWorker_TickerParsing(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	var seq uint64
+	w := NewWorker("", []string{"BTC"}, inbox, &seq)
+
+	w.OnMessage(context.Background(), []byte(`{"response_type":"DATA","channel":"TICKER","data":{"quote_currency":"KRW","target_currency":"BTC","timestamp":1704067200000,"last":"135000000","target_volume":"98.7654321"}}`))
+
+	select {
+	case ev := <-inbox:
+		m := ev.(*event.MarketUpdateEvent)
+		if m.Symbol != "BTC" || m.Exchange != "COINONE" || m.Seq != 1 {
+			t.Errorf("unexpected event %+v", m)
+		}
+		if m.PriceMicros != 135_000_000_000_000 || m.QtySats != 9_876_543_210 {
+			t.Errorf("price %d, qty %d", m.PriceMicros, m.QtySats)
+		}
+		if m.Ts != 1704067200000*1000 {
+			t.Errorf("ts should be micros, got %d", m.Ts)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("no event received")
+	}
+}
+
+func TestWorker_IgnoresControlMessages(t *testing.T) {
+	inbox := make(chan event.Event, 1)
+	var seq uint64
+	w := NewWorker("", []string{"BTC"}, inbox, &seq)
+
+	for _, msg := range []string{
+		`{"response_type":"SUBSCRIBED","channel":"TICKER"}`,
+		`{"response_type":"ERROR","message":"invalid topic"}`,
+		`{"response_type":"DATA","channel":"ORDERBOOK","data":{"quote_currency":"KRW","target_currency":"BTC"}}`,
+		`{"response_type":"DATA","channel":"TICKER","data":{"quote_currency":"USDT","target_currency":"BTC","last":"1"}}`,
+		`not json`,
+	} {
+		w.OnMessage(context.Background(), []byte(msg))
+	}
+	select {
+	case ev := <-inbox:
+		t.Errorf("unexpected event %+v", ev)
+	default:
+	}
+	if seq != 0 {
+		t.Errorf("seq advanced to %d without events", seq)
+	}
+}
@@ -1,10 +1,15 @@
 package infra
 
 import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
+	"time"
+	_ "time/tzdata" // engine.end_of_day time zones resolve without system zoneinfo
 
 	"gopkg.in/yaml.v3"
 )
@@ -57,6 +62,9 @@ func GetPlatformUserAgent() string {
 // Config는 애플리케이션의 모든 설정을 담습니다.
 // LoadConfig로 로드된 후에 환경 변수를 통해 민감 내용을 덮어씁니다.
 type Config struct {
+	// 적용된 프로필 이름 (profiles 섹션, 비우면 기본 설정)
+	Profile string `yaml:"-"`
+
 	App struct {
 		Name    string `yaml:"name"`
 		Version string `yaml:"version"`
@@ -64,6 +72,12 @@ type Config struct {
 
 	Trading struct {
 		Mode string `yaml:"mode"`
+		// PAPER 모드가 수수료/슬리피지를 흉내낼 거래소 (비우면 BITGET)
+		PaperExchange string `yaml:"paper_exchange"`
+		// 거래소별 체결 비용 (모의 체결/백테스트). 항목이 있으면 해당 거래소 기본값을 통째로 대체
+		Costs map[string]CostConfig `yaml:"costs"`
+		// PAPER 모드 지정가 대기 주문의 체결 확률 모델 (`app fillmodel -out` 으로 생성한 JSON, 비우면 가격 도달 시 항상 체결)
+		FillModel string `yaml:"fill_model"`
 	} `yaml:"trading"`
 
 	API struct {
@@ -82,17 +96,338 @@ type Config struct {
 			Passphrase string            `yaml:"passphrase"`
 			Symbols    map[string]string `yaml:"symbols"`
 		} `yaml:"bitget"`
+		// OKX 공개 시세 (로그인 불필요). 통합 기호만 지정하면 현물 BTC-USDT / 무기한 BTC-USDT-SWAP 으로 매핑
+		OKX struct {
+			WSURL   string   `yaml:"ws_url"`  // 비우면 공식 공개 엔드포인트
+			Symbols []string `yaml:"symbols"` // 비우면 비활성
+			Swap    bool     `yaml:"swap"`    // 무기한 스왑 시세도 수신
+		} `yaml:"okx"`
+		// 빗썸/코인원 공개 KRW 시세 (로그인 불필요, 김치 프리미엄 거래소 비교용)
+		Bithumb struct {
+			WSURL   string   `yaml:"ws_url"`  // 비우면 공식 공개 엔드포인트
+			Symbols []string `yaml:"symbols"` // 비우면 비활성
+		} `yaml:"bithumb"`
+		Coinone struct {
+			WSURL   string   `yaml:"ws_url"`  // 비우면 공식 공개 엔드포인트
+			Symbols []string `yaml:"symbols"` // 비우면 비활성
+		} `yaml:"coinone"`
+		// Bybit 공개 시세 (로그인 불필요). 통합 기호만 지정하면 현물/무기한 모두 BTCUSDT 로 매핑
+		Bybit struct {
+			SpotWSURL   string   `yaml:"spot_ws_url"`   // 비우면 공식 공개 엔드포인트
+			LinearWSURL string   `yaml:"linear_ws_url"` // 비우면 공식 공개 엔드포인트
+			Symbols     []string `yaml:"symbols"`       // 비우면 비활성
+			Linear      bool     `yaml:"linear"`        // USDT 무기한(linear) 시세도 수신 (Bitget 선물과 베이시스 비교)
+		} `yaml:"bybit"`
+		// 게이트웨이 공통 네트워크 설정 (DNS 로테이션으로 인한 재접속 지연 방지)
+		Network struct {
+			PreferIPv4     bool   `yaml:"prefer_ipv4"`      // IPv6 경로 장애 시 타임아웃까지 대기하는 문제 회피
+			DNSServer      string `yaml:"dns_server"`       // "1.1.1.1:53" (비우면 시스템 리졸버)
+			DNSTTLSec      int    `yaml:"dns_ttl_sec"`      // DNS 캐시 유지 시간 (0 = 캐시 안 함)
+			DialTimeoutSec int    `yaml:"dial_timeout_sec"` // 주소별 연결 타임아웃 (0 = 5초)
+			HTTPTimeoutSec int    `yaml:"http_timeout_sec"` // REST 요청별 타임아웃 (0 = 클라이언트별 기본값)
+		} `yaml:"network"`
 		ExchangeRate struct {
 			URL             string `yaml:"url"`
 			PollIntervalSec int    `yaml:"poll_interval_sec"`
+			MaxAgeSec       int    `yaml:"max_age_sec"` // USD/KRW 유효 시간 (0 = 600초)
+			// 추가 통화쌍: 엔화/유로 거래소 프리미엄용 교차 환율 (JPY/KRW = USD/KRW ÷ USD/JPY)
+			Pairs []FXPairConfig `yaml:"pairs"`
 		} `yaml:"exchange_rate"`
+		// 온체인 컨텍스트 지표 (DefiLlama 무료 API, 하루 단위): 스테이블코인 공급/유입, 거래소 보유량/순유입
+		OnChain struct {
+			Enabled         bool              `yaml:"enabled"`
+			StablecoinURL   string            `yaml:"stablecoin_url"`    // 비우면 DefiLlama stablecoincharts/all
+			ExchangeURL     string            `yaml:"exchange_url"`      // 거래소 slug 앞에 붙는 URL (비우면 DefiLlama protocol/)
+			Exchanges       map[string]string `yaml:"exchanges"`         // 거래소 → 제공자 slug (예: BINANCE: binance-cex)
+			PollIntervalSec int               `yaml:"poll_interval_sec"` // 0 = 하루
+		} `yaml:"onchain"`
+		// 심리 지표: Crypto Fear & Greed (alternative.me) + 선택적으로 미결제약정 가중 펀딩비 (Bitget 선물)
+		Sentiment struct {
+			Enabled         bool   `yaml:"enabled"`
+			FearGreedURL    string `yaml:"fear_greed_url"`    // 비우면 alternative.me
+			Funding         bool   `yaml:"funding"`           // api.bitget.symbols 선물의 펀딩비를 OI 가중 평균
+			FundingURL      string `yaml:"funding_url"`       // 비우면 Bitget V2 mix tickers
+			PollIntervalSec int    `yaml:"poll_interval_sec"` // 0 = 3600
+		} `yaml:"sentiment"`
+		// 업비트 공지: 신규 거래지원/거래지원 종료/입출금 중단 공지를 분류해 NoticeEvent + 알림
+		Notices struct {
+			Enabled         bool   `yaml:"enabled"`
+			URL             string `yaml:"url"`               // 비우면 업비트 공지 API
+			PollIntervalSec int    `yaml:"poll_interval_sec"` // 0 = 15
+			WebhookURL      string `yaml:"webhook_url"`       // 알림 웹훅 (Slack/Discord, 비우면 로그만)
+		} `yaml:"notices"`
+		// 외부 시그널 웹훅: TradingView 알림/스크립트의 POST /signals → SignalEvent (토큰 인증)
+		Signals struct {
+			Enabled    bool   `yaml:"enabled"`
+			ListenAddr string `yaml:"listen_addr"` // 비우면 127.0.0.1:8090 (외부 공개는 TLS 리버스 프록시 뒤에서)
+			Token      string `yaml:"token"`       // 공유 토큰 (환경 변수 CRYPTO_SIGNAL_TOKEN 권장)
+		} `yaml:"signals"`
+		// 시그널 전용 모드: 로컬 주문 실행(OMS) 없이 위험 검사를 통과한 전략 주문을 외부 실행기에 스트리밍
+		// 별도 리스너의 GET /strategy-signals (JSON 폴링 또는 WebSocket, 토큰 인증)
+		SignalStream struct {
+			Enabled    bool   `yaml:"enabled"`
+			ListenAddr string `yaml:"listen_addr"` // 비우면 127.0.0.1:8091
+			Token      string `yaml:"token"`       // 공유 토큰 (환경 변수 CRYPTO_SIGNAL_STREAM_TOKEN 권장)
+			Buffer     int    `yaml:"buffer"`      // 재접속 시 다시 받을 수 있는 최근 시그널 수 (0 = 1000)
+		} `yaml:"signal_stream"`
+		// 수동 주문 API: 관리 리스너의 POST /orders (주문/포지션 청산/전체 취소). 전략 주문과 같은 라우터·리스크 검사·WAL 기록
+		Orders struct {
+			Enabled bool   `yaml:"enabled"`
+			Token   string `yaml:"token"` // Bearer 토큰 (환경 변수 CRYPTO_ORDER_TOKEN 권장)
+		} `yaml:"orders"`
 	} `yaml:"api"`
 
+	Engine struct {
+		InboxSize int `yaml:"inbox_size"`
+		// 시세 워커마다 전용 lock-free 링 버퍼(SPSC) 인박스 크기. 0 = 모든 워커가 공용 인박스 채널 사용
+		InboxRing int `yaml:"inbox_ring"`
+		// 종목 해시로 나눈 시퀀서 샤드 수 (샤드마다 인박스·hotpath·WAL 세그먼트 분리). 0/1 = 단일 시퀀서
+		// 시세 모니터링 전용: 거래소 간 지표(프리미엄, 전체 시세)는 샤드를 합친 순서 스트림에서 계산
+		Shards    int `yaml:"shards"`
+		Spillover struct {
+			Enabled bool  `yaml:"enabled"`
+			MaxMB   int64 `yaml:"max_mb"` // Disk budget; 0 = unlimited
+		} `yaml:"spillover"`
+		// 시세 워커별 인박스 배압: 인박스(또는 전용 링)가 high_pct 이상 차면 혼잡 상태로 기록·경고하고
+		// low_pct 이하로 내려가면 해제. conflate: 가득 차서 거절된 시세는 버리지 않고 거래소/종목별 최신 값만 보관했다 전달
+		Backpressure struct {
+			HighPct  int  `yaml:"high_pct"` // 0 = 80
+			LowPct   int  `yaml:"low_pct"`  // 0 = high_pct 의 절반
+			Conflate bool `yaml:"conflate"`
+		} `yaml:"backpressure"`
+		// nil = section absent: observe-only (log + report, never halt)
+		GapPolicy *GapPolicyConfig `yaml:"gap_policy"`
+		// 이벤트 로그(WAL) 저장소. file: 세그먼트 파일 WAL (레코드별 CRC32, fsync 정책) 을 기준으로
+		// 기록하고 events.db 이벤트 테이블에 미러링 (분석 API/팔로워는 계속 events.db 사용)
+		WAL struct {
+			Backend        string `yaml:"backend"`          // sqlite (기본, events.db) | file
+			SegmentMB      int64  `yaml:"segment_mb"`       // 세그먼트 파일 크기 (0 = 64MB)
+			Sync           string `yaml:"sync"`             // always (기본, 이벤트마다 fsync) | interval | none
+			SyncIntervalMS int    `yaml:"sync_interval_ms"` // interval 정책의 fsync 주기 (0 = 100ms)
+			Codec          string `yaml:"codec"`            // binary (기본, 압축 바이너리) | json. 기존 JSON 레코드는 어느 쪽이든 그대로 읽힘
+		} `yaml:"wal"`
+		// 주기적 상태 스냅샷 (시세·호가·봉·잔고·포지션·관리 상태) 과 빠른 복구
+		Snapshot struct {
+			EveryEvents  uint64 `yaml:"every_events"`  // N 이벤트마다 TRIGGER_SNAPSHOT 자동 전송 (0 = 끔)
+			Keep         int    `yaml:"keep"`          // 최근 N개만 보관 (0 = 전부)
+			FastRecovery bool   `yaml:"fast_recovery"` // 기동 시 최신 스냅샷 복원 후 이후 WAL 만 재생 (OMS 사용 시 전체 재생)
+		} `yaml:"snapshot"`
+		// 반복 실패 이벤트 격리 (dead letter)
+		DeadLetter struct {
+			MaxAttempts    int `yaml:"max_attempts"`     // WAL 쓰기 최대 시도 횟수 (0 = 즉시 중단, 기존 동작)
+			RetryBackoffMS int `yaml:"retry_backoff_ms"` // 재시도 간격 (시도 횟수만큼 증가)
+		} `yaml:"dead_letter"`
+		// 호가창(depth) 수신: 시퀀서의 거래소/종목별 호가창 상태 + WAL 기록 (용량이 크게 늘어남)
+		OrderBook struct {
+			Enabled bool `yaml:"enabled"`
+			Depth   int  `yaml:"depth"` // 한쪽당 호가 단계 수 (1~15)
+		} `yaml:"orderbook"`
+		// 체결(trade) 스트림 수신: 업비트/비트겟 체결마다 TradeEvent (WAL 기록, 이벤트량 큼)
+		Trades struct {
+			Enabled bool `yaml:"enabled"`
+		} `yaml:"trades"`
+		// 캔들(kline) 수신: 업비트/비트겟 봉 데이터로 시퀀서가 거래소/종목별 OHLCV 봉 유지
+		Candles struct {
+			Interval string `yaml:"interval"` // 1m | 5m | 1h (비우면 비활성)
+		} `yaml:"candles"`
+		// 무기한 선물 펀딩 일정: 비트겟 선물/바이비트 무기한 티커의 다음 펀딩 시각·예상 펀딩비 (FundingEvent, WAL 기록)
+		Funding struct {
+			WarnBeforeMin int `yaml:"warn_before_min"` // 펀딩 N분 전 경고: 전략 OnFundingSoon + MQTT 알림 (0 = 끔)
+		} `yaml:"funding"`
+		// 타이머: 주기마다 TimerEvent 를 시퀀서에 주입 (WAL 기록, 전략 OnTimer). 봉 마감/카운트다운/시세 지연 감지용
+		Timers []TimerConfig `yaml:"timers"`
+		// 하트비트: 주기마다 HeartbeatEvent 를 WAL 에 기록해 "시세 없음"과 "시스템 중단"을 구분 (app outages)
+		HeartbeatSec int `yaml:"heartbeat_sec"` // 0 = 60, 음수 = 끔
+		// 주문 관리(OMS): 전략 신호를 클라이언트 주문 ID 가 붙은 주문으로 바꿔 trading.mode 의 실행기로 전송
+		OMS struct {
+			Enabled   bool   `yaml:"enabled"`
+			IDPrefix  string `yaml:"id_prefix"`  // 클라이언트 주문 ID 접두사 (비우면 cg)
+			QueueSize int    `yaml:"queue_size"` // 전송 대기열 크기 (가득 차면 주문 거절, 0 = 64)
+			// OCO/트레일링 스탑을 발동시키는 시세 거래소 (예: BITGET_FUTURES, 비우면 모든 거래소: KRW/USDT 시세가 섞임)
+			TriggerExchange string `yaml:"trigger_exchange"`
+			// 포지션 평가(미실현 손익) 시세 거래소 (비우면 trigger_exchange)
+			MarkExchange string `yaml:"mark_exchange"`
+			// 오래된 지정가 주문 정리: 전송 후 order_ttl_sec 이 지나거나 (주문별 TTLSec 우선)
+			// 해당 거래소 시세가 지정가에서 stale_drift_bps 이상 벌어지면 취소 요청 (0 = 비활성)
+			OrderTTLSec   int64 `yaml:"order_ttl_sec"`
+			StaleDriftBps int64 `yaml:"stale_drift_bps"`
+			// REAL/DEMO 주문 상태(체결/취소) 조회 주기 (0 = 1000)
+			FillPollMs int `yaml:"fill_poll_ms"`
+		} `yaml:"oms"`
+		// 주문 전 위험 검사 (OMS 필요): 한도를 넘는 주문은 전송하지 않고 RISK_LIMIT 으로 거절. 0 = 해당 검사 비활성
+		Risk struct {
+			Enabled          bool  `yaml:"enabled"`
+			MaxOrderNotional int64 `yaml:"max_order_notional"` // 주문 1건 금액 (가격 × 수량, 호가 통화 Micros)
+			MaxPositionSats  int64 `yaml:"max_position_sats"`  // 체결 후 거래소/종목별 포지션 절대값 (Sats, 같은 방향 미체결 주문 포함)
+			MaxOpenOrders    int64 `yaml:"max_open_orders"`    // 미완료 주문 수 (발동 대기 OCO/트레일링 포함)
+			PriceCollarBps   int64 `yaml:"price_collar_bps"`   // 지정가와 마지막 체결가의 최대 괴리 (bp)
+			DailyLossLimit   int64 `yaml:"daily_loss_limit"`   // UTC 하루 순손익 감소 한도 (호가 통화 Micros, 포지션 축소 주문은 허용)
+			MaxGrossExposure int64 `yaml:"max_gross_exposure"` // 주문 통화(KRW/USDT)별 포지션 명목 금액 절대값 합 (호가 통화 Micros)
+			MaxVaR           int64 `yaml:"max_var"`            // 주문 통화별 역사적 시뮬레이션 VaR (호가 통화 Micros)
+			VaRWindowDays    int   `yaml:"var_window_days"`    // VaR 에 쓰는 일간 수익률 개수 (0 = 250)
+			VaRConfidenceBps int64 `yaml:"var_confidence_bps"` // VaR 신뢰수준 (bp, 0 = 9900 = 99%)
+		} `yaml:"risk"`
+		// 킬 스위치: 모든 미체결 주문 취소 + 모니터 전용 전환 + HaltEvent WAL 기록 (RESUME_TRADING 으로 해제)
+		// 수동 발동은 `app control KILL -reason ...`
+		KillSwitch struct {
+			DrawdownLimit int64 `yaml:"drawdown_limit"` // 포지션 순손익이 최고점 대비 이만큼 하락하면 발동 (호가 통화 Micros, 0 = 비활성)
+			OnGapResync   bool  `yaml:"on_gap_resync"`  // 시퀀스 갭으로 게이트웨이 재동기화(gap_policy resync) 시 발동
+		} `yaml:"kill_switch"`
+		// 장 마감 정책 (거래소 현지 시간): reduce_at 부터 포지션을 줄이는 주문만 허용하고 늘리는 미체결 주문은 취소.
+		// flatten 이면 그 거래소의 미체결 주문을 모두 취소하고 포지션을 시장가로 전량 청산. resume_at 에 해제
+		// (비우면 `app control LIFT_REDUCE_ONLY -target <거래소>` 로 수동 해제). OMS 필요
+		EndOfDay struct {
+			Enabled bool             `yaml:"enabled"`
+			Venues  []EndOfDayConfig `yaml:"venues"`
+			OptOut  []string         `yaml:"opt_out"` // 적용하지 않을 전략 이름 (sma_cross, watchlist:<템플릿>)
+		} `yaml:"end_of_day"`
+		// 스마트 주문 라우터: 전략의 매수 주문을 수수료/환율 반영 실효가가 가장 싼 거래소로, 호가가 부족하면 여러 거래소로 분할
+		Router struct {
+			Enabled      bool               `yaml:"enabled"`
+			BaseCurrency string             `yaml:"base_currency"`  // 실효가 비교 통화 (비우면 KRW)
+			FXMaxAgeSec  int                `yaml:"fx_max_age_sec"` // 이보다 오래된 환율의 거래소는 제외 (0 = 600)
+			Venues       []RouteVenueConfig `yaml:"venues"`
+			// 메이커/테이커 선택: 분할분을 최우선 매수 호가에 지정가로 걸어두고 시간 초과 시 잔량을 테이커로 전환
+			Maker struct {
+				TimeoutSec int   `yaml:"timeout_sec"` // 메이커 대기 시간 (0 = 항상 테이커)
+				EdgeBps    int64 `yaml:"edge_bps"`    // 스프레드 + 수수료 차이가 이 이상일 때만 메이커 (긴급도 보통, 0 = 5)
+			} `yaml:"maker"`
+		} `yaml:"router"`
+		// 읽기 전용 팔로워: 다른 인스턴스(리더)의 WAL 을 따라가며 동일한 상태 유지 (대시보드/분석 전용, 거래 없음)
+		Follower struct {
+			Enabled        bool   `yaml:"enabled"`
+			LeaderDB       string `yaml:"leader_db"`        // 리더의 events.db 경로 (공유 폴더 가능, 읽기 전용으로 열림)
+			PollIntervalMS int    `yaml:"poll_interval_ms"` // WAL 확인 간격 (0 = 500)
+			BatchSize      int    `yaml:"batch_size"`       // 1회 조회 이벤트 수 (0 = 1000)
+			ListenAddr     string `yaml:"listen_addr"`      // 조회 API 주소 (비우면 localhost:6061)
+			Takeover       bool   `yaml:"takeover"`         // 따라잡은 뒤 리더에게서 거래를 인계받음 (블루/그린 배포)
+			LeaderAdmin    string `yaml:"leader_admin"`     // 리더의 관리 주소 (비우면 localhost:6060)
+		} `yaml:"follower"`
+		// 블루/그린 인계 (리더 쪽): 후계 인스턴스의 요청으로 신규 주문 중단 → 미응답 주문 대기 → WAL 봉인
+		Handover struct {
+			DrainTimeoutSec  int `yaml:"drain_timeout_sec"`  // 미응답 주문 대기 시간 (0 = 10)
+			CommitTimeoutSec int `yaml:"commit_timeout_sec"` // 후계가 확정하지 않으면 거래 재개 (0 = 60)
+		} `yaml:"handover"`
+		// Go 런타임 메모리 설정 (라즈베리 파이 등 저메모리 장비용, profiles.pi 참고)
+		Memory struct {
+			LimitMB    int64 `yaml:"limit_mb"`    // 소프트 메모리 한도 (GOMEMLIMIT, 가까워지면 GC 를 더 자주 실행, 0 = 제한 없음)
+			GCPercent  int   `yaml:"gc_percent"`  // GC 목표 비율 (GOGC, 낮을수록 힙이 작고 CPU 사용 증가, 0 = 기본 100)
+			SkipWarmup bool  `yaml:"skip_warmup"` // 기동 시 이벤트 풀 미리 할당 생략
+		} `yaml:"memory"`
+	} `yaml:"engine"`
+
+	Strategy struct {
+		// 관심 종목(watchlist)마다 동일한 템플릿 전략을 자동 생성
+		Watchlist struct {
+			Template     string   `yaml:"template"`      // sma_cross | ema_cross | rsi | macd | bollinger (비우면 비활성)
+			ShortPeriod  int      `yaml:"short_period"`  // sma_cross/ema_cross 단기, macd 빠른 EMA
+			LongPeriod   int      `yaml:"long_period"`   // sma_cross/ema_cross 장기, macd 느린 EMA
+			SignalPeriod int      `yaml:"signal_period"` // macd 시그널선 EMA
+			Period       int      `yaml:"period"`        // rsi/bollinger 기간
+			Lower        int64    `yaml:"lower"`         // rsi 과매도 기준 (예: 30, 하향 돌파 시 BUY)
+			Upper        int64    `yaml:"upper"`         // rsi 과매수 기준 (예: 70, 상향 돌파 시 SELL)
+			StdDevBps    int64    `yaml:"std_dev_bps"`   // bollinger 밴드 폭 (표준편차 x 10000, 20000 = 2σ)
+			Symbols      []string `yaml:"symbols"`       // 비우면 api.upbit.symbols 사용
+			MaxNotional  int64    `yaml:"max_notional"`  // 전체 종목 공유 위험 한도 (호가 통화 Micros, 0 = 무제한)
+		} `yaml:"watchlist"`
+		// 펀딩비 수확 (델타 중립): 선물 거래소의 예상 펀딩비가 entry_rate 이상이면 현물 매수 + 같은 수량 무기한 선물 매도,
+		// exit_rate 이하로 떨어지면 (펀딩비 역전 전) 양쪽 청산. watchlist 템플릿과 함께 사용할 수 없음
+		FundingHarvest struct {
+			Enabled      bool     `yaml:"enabled"`
+			Symbols      []string `yaml:"symbols"`       // 비우면 api.bitget.symbols 전체
+			SpotExchange string   `yaml:"spot_exchange"` // 현물 매수 거래소 (비우면 BITGET_SPOT)
+			PerpExchange string   `yaml:"perp_exchange"` // 선물 매도 + 펀딩비 기준 거래소 (비우면 BITGET_FUTURES)
+			EntryRate    int64    `yaml:"entry_rate"`    // 진입 예상 펀딩비 (Micros, 0.0003 = 300)
+			ExitRate     int64    `yaml:"exit_rate"`     // 청산 예상 펀딩비 (Micros, entry_rate 미만, 예: 0)
+			Notional     int64    `yaml:"notional"`      // 다리당 목표 금액 (USDT Micros, engine.risk 주문/포지션 한도로 제한)
+		} `yaml:"funding_harvest"`
+		// 적립식 매수(DCA): 계획마다 engine.timers 틱에 정해진 금액만큼 시장가 매수. 동기화된 잔고가 부족하면 건너뜀
+		// watchlist 템플릿/funding_harvest 와 함께 사용할 수 없음
+		DCA []DCAPlanConfig `yaml:"dca"`
+		// 전략 매매 빈도 제한 (진입 = BUY, 청산은 제한하지 않음)
+		Limits struct {
+			CooldownSec      int64 `yaml:"cooldown_sec"`        // 동일 종목 재진입 최소 간격 (0 = 비활성)
+			MaxEntriesPerDay int   `yaml:"max_entries_per_day"` // 종목별 UTC 일일 최대 진입 횟수 (0 = 무제한)
+		} `yaml:"limits"`
+		// 전략 1회 호출(OnMarketUpdate) 시간 예산. 느린 전략이 단일 스레드 hotpath 를 막지 않도록 감시
+		Budget struct {
+			PerEventUS  int64 `yaml:"per_event_us"` // 호출당 허용 시간 (마이크로초, 0 = 비활성)
+			MaxStrikes  int   `yaml:"max_strikes"`  // 초과 1회당 +1, 정상 1회당 -1. 이 값에 도달하면 경고 + 메트릭 표시
+			AutoDisable bool  `yaml:"auto_disable"` // 도달 시 PAUSE_STRATEGY 자동 전송 (RESUME_STRATEGY 로 복구)
+		} `yaml:"budget"`
+		// 전략 호출 전 시세 필터 (시세 상태는 항상 갱신, 전략 계산만 생략)
+		Filter struct {
+			Exchanges     []string `yaml:"exchanges"`       // 전략에 전달할 거래소 (비우면 전체)
+			MinIntervalMS int64    `yaml:"min_interval_ms"` // 거래소/종목별 최소 전달 간격 (0 = 비활성)
+			MinChangeBps  int64    `yaml:"min_change_bps"`  // 마지막 전달 가격 대비 최소 변동 (bp, 0 = 비활성)
+		} `yaml:"filter"`
+		// 봉 기반 실행: 틱 대신 완성된 캔들 종가로 전략 실행 (engine.candles.interval 필요)
+		Bars struct {
+			Exchange string `yaml:"exchange"` // 봉을 사용할 거래소 (예: UPBIT, 비우면 틱 기반)
+		} `yaml:"bars"`
+		// 외부 전략 플러그인 (Go plugin, 리눅스/macOS + cgo): strategies 목록을 만들 때 디렉터리의 *.so 를 읽어 전략 타입으로 등록
+		Plugins struct {
+			Dir string `yaml:"dir"` // 플러그인 디렉터리 (없으면 건너뜀, 비우면 비활성)
+		} `yaml:"plugins"`
+		// 런타임 파라미터 변경(SET_STRATEGY_PARAM) 전, 최근 저장 데이터로 새 파라미터를 모의 실행해 감사 기록에 결과 첨부
+		Backfill struct {
+			Hours int `yaml:"hours"` // 모의 실행 구간 (최근 N시간, 0 = 비활성)
+		} `yaml:"backfill"`
+	} `yaml:"strategy"`
+
+	// 설정 기반 전략 목록: 항목마다 등록된 전략 타입을 종목 하나에 생성해 함께 실행 (주문에 name 표시)
+	// 비우면 strategy 섹션 사용. strategy.watchlist.template/funding_harvest/dca 와 함께 사용할 수 없음
+	Strategies []StrategyConfig `yaml:"strategies"`
+
+	// 김치 프리미엄 계산식 (/premium/heatmap 기본값, 요청 시 domestic/foreign/price 로 변경 가능)
+	Premium struct {
+		Domestic string `yaml:"domestic"` // UPBIT | BITHUMB | COINONE (비우면 UPBIT)
+		Foreign  string `yaml:"foreign"`  // 기준 해외 거래소: BITGET_SPOT | BITGET_FUTURES | OKX_SPOT | OKX_SWAP | BYBIT_SPOT | BYBIT_LINEAR (비우면 BITGET_SPOT)
+		Price    string `yaml:"price"`    // last | bid_ask (국내 매수1호가 vs 해외 매도1호가, engine.orderbook 기록 필요)
+	} `yaml:"premium"`
+
+	// 알림 규칙: 조건식이 참이 되면 알림 + 동작(전략 일시정지, 축소 전용 전환, 헤지 주문)을 제어 이벤트로 실행
+	Alerts struct {
+		IntervalMS int               `yaml:"interval_ms"` // 규칙 검사 주기 (0 = 1000)
+		Rules      []AlertRuleConfig `yaml:"rules"`
+	} `yaml:"alerts"`
+
+	// 스트레스 테스트 (cmd/stress): 최신 스냅샷의 포지션에 충격 시나리오를 적용해 손익/증거금 사용률/청산 근접도 출력
+	Stress struct {
+		Leverage           int64                  `yaml:"leverage"`             // 선물 포지션 격리 증거금 레버리지 (0 = 증거금 계산 생략)
+		MaintenanceBps     int64                  `yaml:"maintenance_bps"`      // 유지 증거금률 (bp, 예: 50 = 0.5%)
+		PremiumLookbackMin int                    `yaml:"premium_lookback_min"` // 현재 김치 프리미엄을 찾을 WAL 구간 (0 = 60분)
+		Scenarios          []StressScenarioConfig `yaml:"scenarios"`            // 비우면 BTC -20%, 프리미엄 0 수렴, 환율 +5%
+	} `yaml:"stress"`
+
 	UI struct {
 		UpdateIntervalMS int    `yaml:"update_interval_ms"`
+		KeyframeSec      int    `yaml:"keyframe_sec"` // /stream 전체 상태 재전송 주기 (0 = 10초)
 		HistoryDays      int    `yaml:"history_days"`
 		GapThreshold     int64  `yaml:"gap_threshold"` // Micros
 		Theme            string `yaml:"theme"`
+		// 코인 아이콘 동기화 (기동 시 백그라운드). 최근에 확인한 아이콘은 네트워크 없이 재사용하고,
+		// 만료된 아이콘은 ETag/Last-Modified 조건부 요청으로 확인
+		Icons struct {
+			RefreshHours int `yaml:"refresh_hours"` // 재확인 주기 (0 = 168시간)
+			MaxDownloads int `yaml:"max_downloads"` // 실행당 최대 요청 수 (0 = 20)
+			BudgetSec    int `yaml:"budget_sec"`    // 실행당 전체 다운로드 시간 예산 (0 = 30초)
+			Concurrency  int `yaml:"concurrency"`   // 동시 요청 수 (0 = 5)
+		} `yaml:"icons"`
+		// MQTT 발행: 선택한 심볼의 시세/김치 프리미엄/알림을 홈 대시보드·IoT 기기용 토픽으로 전송
+		MQTT struct {
+			Enabled      bool     `yaml:"enabled"`
+			Broker       string   `yaml:"broker"`        // tcp://host:1883 | tls://host:8883
+			ClientID     string   `yaml:"client_id"`     // 비우면 crypto_go
+			Username     string   `yaml:"username"`      // 선택
+			Password     string   `yaml:"password"`      // 환경 변수 CRYPTO_MQTT_PASSWORD 권장
+			Symbols      []string `yaml:"symbols"`       // 발행할 심볼 (비우면 전체)
+			PriceTopic   string   `yaml:"price_topic"`   // {exchange}, {symbol} 치환 (비우면 cryptogo/{exchange}/{symbol}/price)
+			PremiumTopic string   `yaml:"premium_topic"` // {symbol} 치환 (비우면 cryptogo/{symbol}/premium)
+			AlertTopic   string   `yaml:"alert_topic"`   // 비우면 cryptogo/alert
+			IntervalSec  int      `yaml:"interval_sec"`  // 토픽당 최소 발행 간격 (0 = 5)
+			Retain       bool     `yaml:"retain"`        // 시세/프리미엄을 retained 메시지로 (새 구독자가 즉시 마지막 값 수신)
+		} `yaml:"mqtt"`
 	} `yaml:"ui"`
 
 	Logging struct {
@@ -100,6 +435,122 @@ type Config struct {
 	} `yaml:"logging"`
 }
 
+// FXPairConfig는 추가로 폴링할 환율 통화쌍입니다 (통화쌍별 제공자와 유효 시간).
+type FXPairConfig struct {
+	Symbol          string `yaml:"symbol"`            // BASE/QUOTE (예: USD/JPY = 1달러당 엔)
+	URL             string `yaml:"url"`               // 제공자 API (Yahoo chart 형식)
+	PollIntervalSec int    `yaml:"poll_interval_sec"` // 0 = 60초
+	MaxAgeSec       int    `yaml:"max_age_sec"`       // 이보다 오래된 환율은 사용하지 않음 (0 = 600초)
+}
+
+// GapPolicyConfig는 시퀀스 갭 처리 정책입니다. 검증과 변환은 app.BuildGapPolicy에서 수행합니다.
+type GapPolicyConfig struct {
+	Tolerance *uint64                  `yaml:"tolerance"` // nil = 기본값 (10), 0 = 엄격
+	Action    string                   `yaml:"action"`    // tolerate | resync | halt (생략 시 halt)
+	PerType   map[string]GapRuleConfig `yaml:"per_type"`
+	// 모드별 덮어쓰기 (PAPER/DEMO/REAL). trading.mode 항목의 지정된 필드만 상위 설정을 대체하고 per_type 은 유형별로 병합
+	PerMode map[string]GapPolicyConfig `yaml:"per_mode"`
+}
+
+// CostConfig는 거래소 하나의 체결 비용(수수료/슬리피지, bp 단위)입니다. 변환은 execution.NewCosts에서 수행합니다.
+type CostConfig struct {
+	MakerBps      int64 `yaml:"maker_bps"`       // 지정가(메이커) 수수료, 음수 = 리베이트
+	TakerBps      int64 `yaml:"taker_bps"`       // 시장가(테이커) 수수료
+	SpreadBps     int64 `yaml:"spread_bps"`      // 고정 호가 스프레드 (테이커가 절반을 지불)
+	ImpactBps     int64 `yaml:"impact_bps"`      // 주문량 impact_lot_sats 당 추가 슬리피지
+	ImpactLotSats int64 `yaml:"impact_lot_sats"` // 시장 충격 기준 수량 (Sats)
+	MaxImpactBps  int64 `yaml:"max_impact_bps"`  // 시장 충격 상한 (0 = 무제한)
+	// 수수료를 호가 통화 대신 이 통화로 차감 (예: BGB). 잔고/시세가 없으면 호가 통화로 차감, 리베이트는 항상 호가 통화로 지급
+	FeeCurrency    string `yaml:"fee_currency"`
+	FeeDiscountBps int64  `yaml:"fee_discount_bps"` // fee_currency 로 낼 때 할인율 (예: 2000 = 20%)
+}
+
+// RouteVenueConfig는 주문 라우터가 매수할 수 있는 거래소 하나입니다.
+type RouteVenueConfig struct {
+	Exchange string `yaml:"exchange"`  // 호가창 거래소 (예: UPBIT, BITGET_SPOT)
+	Quote    string `yaml:"quote"`     // 호가 통화 (KRW, USD; USDT 는 USD 로 취급)
+	Market   string `yaml:"market"`    // 주문 시장 (SPOT, FUTURES; 비우면 전략 주문 그대로)
+	FeeBps   *int64 `yaml:"fee_bps"`   // 테이커 수수료 (비우면 trading.costs / 기본값)
+	MakerBps *int64 `yaml:"maker_bps"` // 메이커 수수료 (비우면 trading.costs / 기본값)
+}
+
+// StressScenarioConfig는 스트레스 테스트 시나리오 하나입니다. 변환은 risk.Scenario 로 cmd/stress 에서 수행합니다.
+type StressScenarioConfig struct {
+	Name       string           `yaml:"name"`
+	PriceBps   map[string]int64 `yaml:"price_bps"`   // 심볼별 USD 가격 변동 (bp, "*" = 나머지 전체, 예: BTC: -2000)
+	FXBps      int64            `yaml:"fx_bps"`      // USD/KRW 환율 변동 (bp, KRW 거래소 가격에만 반영)
+	PremiumBps *int64           `yaml:"premium_bps"` // 김치 프리미엄 목표값 (bp, 비우면 유지, 0 = 프리미엄 소멸)
+}
+
+// TimerConfig는 시퀀서 타이머 하나입니다. 틱은 UTC 기준 주기 경계(1분 타이머 = 매분 정각)에 발생합니다.
+type TimerConfig struct {
+	Name        string `yaml:"name"`         // 전략이 구분하는 이름 (예: 1m)
+	IntervalSec int    `yaml:"interval_sec"` // 주기 (초)
+}
+
+// DCAPlanConfig는 적립식 매수 계획 하나입니다. 금액은 거래소 호가 통화(UPBIT = KRW, BITGET_SPOT = USDT) Micros 입니다.
+type DCAPlanConfig struct {
+	Symbol   string `yaml:"symbol"`   // 통합 기호 (예: BTC)
+	Exchange string `yaml:"exchange"` // UPBIT | BITGET_SPOT 등 현물 거래소
+	Timer    string `yaml:"timer"`    // engine.timers 이름 (틱마다 1회 매수)
+	Amount   int64  `yaml:"amount"`   // 1회 매수 금액
+	Budget   int64  `yaml:"budget"`   // 총 매수 한도 (0 = 무제한)
+}
+
+// StrategyConfig는 strategies 항목 하나입니다. params 는 타입별 파라미터 (strategy.watchlist 와 같은 이름,
+// 생략 시 타입 기본값)이며 런타임에 SET_STRATEGY_PARAM "<name>.<파라미터>" 로 변경할 수 있습니다.
+type StrategyConfig struct {
+	Name   string           `yaml:"name"`   // 고유 이름 (비우면 <type>_<symbol>, 스크립트는 <파일 이름>_<symbol>)
+	Type   string           `yaml:"type"`   // sma_cross | ema_cross | rsi | macd | bollinger | script | 플러그인 타입
+	Symbol string           `yaml:"symbol"` // 거래 종목 (예: BTC)
+	Params map[string]int64 `yaml:"params"` // 예: {short_period: 20, long_period: 50}
+	Script string           `yaml:"script"` // type: script 의 스크립트 파일 경로 (param/let/when 문법)
+}
+
+// AlertRuleConfig는 알림 규칙 하나입니다. when 은 price.<거래소>.<심볼>, premium.<심볼>(%) 변수와
+// 숫자, + - < <= > >= == != ! && || 괄호로 된 조건식입니다 (예: "premium.BTC >= 5 && price.UPBIT.BTC < 80000000").
+type AlertRuleConfig struct {
+	Name        string `yaml:"name"`
+	When        string `yaml:"when"`
+	Action      string `yaml:"action"`       // notify (기본) | pause_strategy | reduce_only | hedge
+	Venue       string `yaml:"venue"`        // reduce_only: 거래소 (비우면 전체)
+	Flatten     bool   `yaml:"flatten"`      // reduce_only: 포지션도 청산
+	CooldownSec int    `yaml:"cooldown_sec"` // 다시 실행되기까지 최소 간격 (0 = 300)
+	// hedge: 실행할 주문 (수동 주문과 같은 경로, engine.oms 필요)
+	Order struct {
+		Exchange string `yaml:"exchange"` // 비우면 기본 거래소
+		Symbol   string `yaml:"symbol"`
+		Side     string `yaml:"side"`  // BUY | SELL
+		Type     string `yaml:"type"`  // MARKET (기본) | LIMIT
+		Price    int64  `yaml:"price"` // LIMIT 가격 (Micros)
+		Qty      int64  `yaml:"qty"`   // 수량 (Sats)
+	} `yaml:"order"`
+}
+
+// EndOfDayConfig는 거래소 하나의 장 마감 시간대입니다.
+type EndOfDayConfig struct {
+	Exchange string `yaml:"exchange"`  // 거래소 (예: UPBIT, 비우면 전체)
+	Timezone string `yaml:"timezone"`  // IANA 시간대 (예: Asia/Seoul, 비우면 UTC)
+	ReduceAt string `yaml:"reduce_at"` // 축소 전용 시작 시각 HH:MM (현지)
+	ResumeAt string `yaml:"resume_at"` // 해제 시각 HH:MM (현지, reduce_at 보다 이르면 다음 날, 비우면 수동 해제)
+	Flatten  bool   `yaml:"flatten"`   // 시작 시 포지션 전량 시장가 청산
+}
+
+// ParseTimeOfDay parses a local time of day "HH:MM" into its offset from midnight.
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (want HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// GapRuleConfig는 이벤트 타입별 시퀀스 갭 처리 규칙입니다.
+type GapRuleConfig struct {
+	Tolerance *uint64 `yaml:"tolerance"` // nil = 상위 tolerance 상속
+	Action    string  `yaml:"action"`    // 생략 시 상위 action 상속
+}
+
 // LoadConfig는 설정 파일을 읽고 파싱합니다.
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -107,10 +558,17 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	// 선택된 프로필(--profile, CRYPTO_PROFILE)을 상위 설정 위에 병합
+	profile := ConfigProfile()
+	if data, err = applyProfile(data, profile); err != nil {
+		return nil, err
+	}
+
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
+	cfg.Profile = profile
 
 	// 4원칙: 보안 우선 - 환경 변수 오버라이드 지원
 	overrideWithEnv(&cfg)
@@ -138,10 +596,369 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid Bitget WS URL: %s", c.API.Bitget.WSURL)
 	}
 
+	// FX pairs
+	if oc := c.API.OnChain; oc.Enabled {
+		for _, u := range []string{oc.StablecoinURL, oc.ExchangeURL} {
+			if u != "" && !hasPrefix(u, "https://") && !hasPrefix(u, "http://") {
+				return fmt.Errorf("invalid onchain URL: %q", u)
+			}
+		}
+		for name, slug := range oc.Exchanges {
+			if slug == "" {
+				return fmt.Errorf("onchain exchange %s needs a provider slug", name)
+			}
+		}
+		if oc.PollIntervalSec < 0 {
+			return fmt.Errorf("onchain poll interval must not be negative")
+		}
+	}
+	if st := c.API.Sentiment; st.Enabled {
+		for _, u := range []string{st.FearGreedURL, st.FundingURL} {
+			if u != "" && !hasPrefix(u, "https://") && !hasPrefix(u, "http://") {
+				return fmt.Errorf("invalid sentiment URL: %q", u)
+			}
+		}
+		if st.Funding && len(c.API.Bitget.Symbols) == 0 {
+			return fmt.Errorf("sentiment.funding needs api.bitget.symbols")
+		}
+		if st.PollIntervalSec < 0 {
+			return fmt.Errorf("sentiment poll interval must not be negative")
+		}
+	}
+	if nt := c.API.Notices; nt.Enabled {
+		for _, u := range []string{nt.URL, nt.WebhookURL} {
+			if u != "" && !hasPrefix(u, "https://") && !hasPrefix(u, "http://") {
+				return fmt.Errorf("invalid notices URL: %q", u)
+			}
+		}
+		if nt.PollIntervalSec < 0 {
+			return fmt.Errorf("notices poll interval must not be negative")
+		}
+	}
+	for exchange, cost := range c.Trading.Costs {
+		if cost.TakerBps < 0 || cost.SpreadBps < 0 || cost.ImpactBps < 0 || cost.ImpactLotSats < 0 || cost.MaxImpactBps < 0 {
+			return fmt.Errorf("trading.costs.%s: only maker_bps may be negative", exchange)
+		}
+		if cost.MakerBps <= -10_000 || cost.TakerBps >= 10_000 {
+			return fmt.Errorf("trading.costs.%s: fees must be within ±100%%", exchange)
+		}
+		if cost.ImpactBps > 0 && cost.ImpactLotSats == 0 {
+			return fmt.Errorf("trading.costs.%s: impact_bps needs impact_lot_sats", exchange)
+		}
+		if cost.FeeDiscountBps < 0 || cost.FeeDiscountBps >= 10_000 {
+			return fmt.Errorf("trading.costs.%s: fee_discount_bps must be within [0, 10000)", exchange)
+		}
+		if cost.FeeDiscountBps > 0 && cost.FeeCurrency == "" {
+			return fmt.Errorf("trading.costs.%s: fee_discount_bps needs fee_currency", exchange)
+		}
+	}
+	if mq := c.UI.MQTT; mq.Enabled {
+		if !hasPrefix(mq.Broker, "tcp://") && !hasPrefix(mq.Broker, "tls://") && !hasPrefix(mq.Broker, "mqtt://") &&
+			!hasPrefix(mq.Broker, "mqtts://") && !hasPrefix(mq.Broker, "ssl://") {
+			return fmt.Errorf("invalid MQTT broker: %q (want tcp:// or tls://)", mq.Broker)
+		}
+		for _, t := range []string{mq.PriceTopic, mq.PremiumTopic, mq.AlertTopic} {
+			if strings.ContainsAny(t, "+#") {
+				return fmt.Errorf("MQTT topic %q must not contain wildcards", t)
+			}
+		}
+		if mq.IntervalSec < 0 {
+			return fmt.Errorf("MQTT interval must not be negative")
+		}
+	}
+	if sg := c.API.Signals; sg.Enabled && sg.Token == "" {
+		return fmt.Errorf("signals.token (or CRYPTO_SIGNAL_TOKEN) is required when signals are enabled")
+	}
+	if ss := c.API.SignalStream; ss.Enabled {
+		if ss.Token == "" {
+			return fmt.Errorf("signal_stream.token (or CRYPTO_SIGNAL_STREAM_TOKEN) is required when the signal stream is enabled")
+		}
+		if c.Engine.OMS.Enabled {
+			return fmt.Errorf("signal_stream streams signals instead of executing them: disable engine.oms")
+		}
+		if c.Engine.Shards > 1 {
+			return fmt.Errorf("signal_stream needs a single sequencer (engine.shards <= 1)")
+		}
+		if ss.Buffer < 0 {
+			return fmt.Errorf("signal_stream.buffer must not be negative")
+		}
+	}
+	if o := c.API.Orders; o.Enabled && o.Token == "" {
+		return fmt.Errorf("orders.token (or CRYPTO_ORDER_TOKEN) is required when the order API is enabled")
+	}
+	if c.API.Network.HTTPTimeoutSec < 0 {
+		return fmt.Errorf("network.http_timeout_sec must not be negative")
+	}
+	if c.API.ExchangeRate.MaxAgeSec < 0 {
+		return fmt.Errorf("exchange rate max age must not be negative")
+	}
+	seenPairs := map[string]bool{"USD/KRW": true}
+	for _, p := range c.API.ExchangeRate.Pairs {
+		base, quote, ok := strings.Cut(p.Symbol, "/")
+		if !ok || len(base) != 3 || len(quote) != 3 {
+			return fmt.Errorf("invalid FX pair %q (want BASE/QUOTE, e.g. USD/JPY)", p.Symbol)
+		}
+		if seenPairs[p.Symbol] {
+			return fmt.Errorf("duplicate FX pair %s", p.Symbol)
+		}
+		seenPairs[p.Symbol] = true
+		if !hasPrefix(p.URL, "https://") && !hasPrefix(p.URL, "http://") {
+			return fmt.Errorf("invalid URL for FX pair %s: %q", p.Symbol, p.URL)
+		}
+		if p.PollIntervalSec < 0 || p.MaxAgeSec < 0 {
+			return fmt.Errorf("FX pair %s: intervals must not be negative", p.Symbol)
+		}
+	}
+
+	// OKX (optional)
+	if u := c.API.OKX.WSURL; u != "" && !hasPrefix(u, "ws://") && !hasPrefix(u, "wss://") {
+		return fmt.Errorf("invalid OKX WS URL: %s", u)
+	}
+
+	// Bybit / Bithumb / Coinone (optional)
+	optionalWS := map[string]string{
+		"Bybit spot":   c.API.Bybit.SpotWSURL,
+		"Bybit linear": c.API.Bybit.LinearWSURL,
+		"Bithumb":      c.API.Bithumb.WSURL,
+		"Coinone":      c.API.Coinone.WSURL,
+	}
+	for name, u := range optionalWS {
+		if u != "" && !hasPrefix(u, "ws://") && !hasPrefix(u, "wss://") {
+			return fmt.Errorf("invalid %s WS URL: %s", name, u)
+		}
+	}
+
+	// Strategy limits
+	if c.Strategy.Limits.CooldownSec < 0 || c.Strategy.Limits.MaxEntriesPerDay < 0 {
+		return fmt.Errorf("strategy limits must not be negative")
+	}
+
+	// Strategy budget
+	if b := c.Strategy.Budget; b.PerEventUS < 0 || b.MaxStrikes < 0 {
+		return fmt.Errorf("strategy budget settings must not be negative")
+	}
+	if b := c.Strategy.Budget; b.PerEventUS > 0 && b.MaxStrikes == 0 {
+		return fmt.Errorf("strategy budget: max_strikes is required when per_event_us is set")
+	}
+
+	// Strategy filter
+	if f := c.Strategy.Filter; f.MinIntervalMS < 0 || f.MinChangeBps < 0 {
+		return fmt.Errorf("strategy filter settings must not be negative")
+	}
+
+	// Inbox
+	if m := c.Engine.Memory; m.LimitMB < 0 || m.GCPercent < 0 {
+		return fmt.Errorf("engine.memory settings must not be negative")
+	}
+	if c.Engine.InboxRing < 0 {
+		return fmt.Errorf("engine.inbox_ring must not be negative")
+	}
+	if c.Engine.InboxRing > 0 && c.Engine.Spillover.Enabled {
+		return fmt.Errorf("engine.inbox_ring bypasses the spillover inbox; enable only one of them")
+	}
+	if bp := c.Engine.Backpressure; bp.HighPct < 0 || bp.HighPct > 100 || bp.LowPct < 0 || (bp.LowPct > 0 && bp.HighPct > 0 && bp.LowPct >= bp.HighPct) {
+		return fmt.Errorf("engine.backpressure: need 0 <= low_pct < high_pct <= 100")
+	}
+
+	// Shards
+	if n := c.Engine.Shards; n < 0 || n > 64 {
+		return fmt.Errorf("engine.shards must be between 0 and 64, got %d", n)
+	}
+	if c.Engine.Shards > 1 {
+		switch {
+		case c.Engine.WAL.Backend != "file":
+			return fmt.Errorf("engine.shards needs engine.wal.backend file (one WAL per shard)")
+		case c.Engine.OMS.Enabled:
+			return fmt.Errorf("engine.shards is monitor-only; disable engine.oms")
+		case c.Engine.Follower.Enabled:
+			return fmt.Errorf("engine.shards cannot be combined with engine.follower")
+		case c.Engine.InboxRing > 0 || c.Engine.Spillover.Enabled:
+			return fmt.Errorf("engine.shards routes one shared inbox; disable engine.inbox_ring and engine.spillover")
+		}
+	}
+
+	// Event log
+	switch w := c.Engine.WAL; {
+	case w.Backend != "" && w.Backend != "sqlite" && w.Backend != "file":
+		return fmt.Errorf("engine.wal.backend must be sqlite or file, got %q", w.Backend)
+	case w.Sync != "" && w.Sync != "always" && w.Sync != "interval" && w.Sync != "none":
+		return fmt.Errorf("engine.wal.sync must be always, interval or none, got %q", w.Sync)
+	case w.Codec != "" && !event.ValidCodec(w.Codec):
+		return fmt.Errorf("engine.wal.codec must be binary or json, got %q", w.Codec)
+	case w.SegmentMB < 0 || w.SyncIntervalMS < 0:
+		return fmt.Errorf("engine.wal settings must not be negative")
+	}
+
+	// Snapshots
+	if c.Engine.Snapshot.Keep < 0 {
+		return fmt.Errorf("engine.snapshot.keep must not be negative")
+	}
+
+	// Dead letter
+	if c.Engine.DeadLetter.MaxAttempts < 0 || c.Engine.DeadLetter.RetryBackoffMS < 0 {
+		return fmt.Errorf("dead letter settings must not be negative")
+	}
+
+	// Order book channels
+	if ob := c.Engine.OrderBook; ob.Enabled && (ob.Depth < 1 || ob.Depth > 15) {
+		return fmt.Errorf("orderbook depth must be between 1 and 15 when enabled (got %d)", ob.Depth)
+	}
+
+	// Candles
+	if iv := c.Engine.Candles.Interval; iv != "" {
+		if _, err := domain.CandleDuration(iv); err != nil {
+			return fmt.Errorf("engine.candles: %w", err)
+		}
+	}
+	if c.Strategy.Bars.Exchange != "" && c.Engine.Candles.Interval == "" {
+		return fmt.Errorf("strategy.bars needs engine.candles.interval")
+	}
+
+	// Funding
+	if c.Engine.Funding.WarnBeforeMin < 0 {
+		return fmt.Errorf("engine.funding.warn_before_min must not be negative")
+	}
+
+	// Timers
+	timerNames := make(map[string]bool, len(c.Engine.Timers))
+	for i, tm := range c.Engine.Timers {
+		if tm.Name == "" || tm.IntervalSec <= 0 {
+			return fmt.Errorf("engine.timers[%d]: name and a positive interval_sec are required", i)
+		}
+		if timerNames[tm.Name] {
+			return fmt.Errorf("engine.timers[%d]: duplicate name %q", i, tm.Name)
+		}
+		timerNames[tm.Name] = true
+	}
+
+	// OMS
+	if c.Engine.OMS.QueueSize < 0 {
+		return fmt.Errorf("engine.oms.queue_size must not be negative")
+	}
+	if o := c.Engine.OMS; o.OrderTTLSec < 0 || o.StaleDriftBps < 0 {
+		return fmt.Errorf("engine.oms order_ttl_sec and stale_drift_bps must not be negative")
+	}
+	if c.Engine.OMS.FillPollMs < 0 {
+		return fmt.Errorf("engine.oms.fill_poll_ms must not be negative")
+	}
+
+	// End of day
+	if eod := c.Engine.EndOfDay; eod.Enabled {
+		if !c.Engine.OMS.Enabled {
+			return fmt.Errorf("engine.end_of_day needs engine.oms")
+		}
+		venues := make(map[string]bool, len(eod.Venues))
+		for i, v := range eod.Venues {
+			if venues[v.Exchange] {
+				return fmt.Errorf("engine.end_of_day.venues[%d]: duplicate exchange %q", i, v.Exchange)
+			}
+			venues[v.Exchange] = true
+			if _, err := time.LoadLocation(v.Timezone); err != nil {
+				return fmt.Errorf("engine.end_of_day.venues[%d]: %w", i, err)
+			}
+			reduceAt, err := ParseTimeOfDay(v.ReduceAt)
+			if err != nil {
+				return fmt.Errorf("engine.end_of_day.venues[%d].reduce_at: %w", i, err)
+			}
+			if v.ResumeAt == "" {
+				continue
+			}
+			resumeAt, err := ParseTimeOfDay(v.ResumeAt)
+			if err != nil {
+				return fmt.Errorf("engine.end_of_day.venues[%d].resume_at: %w", i, err)
+			}
+			if resumeAt == reduceAt {
+				return fmt.Errorf("engine.end_of_day.venues[%d]: resume_at equals reduce_at", i)
+			}
+		}
+	}
+
+	// Risk
+	if r := c.Engine.Risk; r.Enabled && !c.Engine.OMS.Enabled {
+		return fmt.Errorf("engine.risk needs engine.oms")
+	}
+	if r := c.Engine.Risk; r.MaxOrderNotional < 0 || r.MaxPositionSats < 0 || r.MaxOpenOrders < 0 || r.PriceCollarBps < 0 || r.DailyLossLimit < 0 ||
+		r.MaxGrossExposure < 0 || r.MaxVaR < 0 || r.VaRWindowDays < 0 {
+		return fmt.Errorf("engine.risk limits must not be negative")
+	}
+	if b := c.Engine.Risk.VaRConfidenceBps; b < 0 || b >= 10000 {
+		return fmt.Errorf("engine.risk.var_confidence_bps must be in [0, 10000), got %d", b)
+	}
+
+	// Kill switch
+	if c.Engine.KillSwitch.DrawdownLimit < 0 {
+		return fmt.Errorf("engine.kill_switch.drawdown_limit must not be negative")
+	}
+
+	// Router
+	if r := c.Engine.Router; r.Enabled {
+		if !c.Engine.OMS.Enabled {
+			return fmt.Errorf("engine.router needs engine.oms")
+		}
+		if len(r.Venues) == 0 {
+			return fmt.Errorf("engine.router.venues must not be empty")
+		}
+		for i, v := range r.Venues {
+			if v.Exchange == "" || v.Quote == "" {
+				return fmt.Errorf("engine.router.venues[%d]: exchange and quote are required", i)
+			}
+			if v.FeeBps != nil && (*v.FeeBps < 0 || *v.FeeBps >= 10_000) {
+				return fmt.Errorf("engine.router.venues[%d]: fee_bps must be within [0, 10000)", i)
+			}
+			if v.MakerBps != nil && (*v.MakerBps <= -10_000 || *v.MakerBps >= 10_000) {
+				return fmt.Errorf("engine.router.venues[%d]: maker_bps must be within (-10000, 10000)", i)
+			}
+		}
+	}
+	if c.Engine.Router.FXMaxAgeSec < 0 {
+		return fmt.Errorf("engine.router.fx_max_age_sec must not be negative")
+	}
+	if m := c.Engine.Router.Maker; m.TimeoutSec < 0 || m.EdgeBps < 0 {
+		return fmt.Errorf("engine.router.maker timeout_sec and edge_bps must not be negative")
+	}
+
+	// Follower
+	if f := c.Engine.Follower; f.Enabled && f.LeaderDB == "" {
+		return fmt.Errorf("engine.follower.leader_db is required when the follower is enabled")
+	}
+	if f := c.Engine.Follower; f.PollIntervalMS < 0 || f.BatchSize < 0 {
+		return fmt.Errorf("engine.follower.poll_interval_ms and batch_size must not be negative")
+	}
+	if h := c.Engine.Handover; h.DrainTimeoutSec < 0 || h.CommitTimeoutSec < 0 {
+		return fmt.Errorf("engine.handover timeouts must not be negative")
+	}
+
+	// Stress
+	if s := c.Stress; s.Leverage < 0 || s.PremiumLookbackMin < 0 {
+		return fmt.Errorf("stress.leverage and premium_lookback_min must not be negative")
+	}
+	if b := c.Stress.MaintenanceBps; b < 0 || b >= 10000 {
+		return fmt.Errorf("stress.maintenance_bps must be in [0, 10000), got %d", b)
+	}
+	for i, sc := range c.Stress.Scenarios {
+		if sc.Name == "" {
+			return fmt.Errorf("stress.scenarios[%d]: name is required", i)
+		}
+		for symbol, bps := range sc.PriceBps {
+			if bps <= -10000 {
+				return fmt.Errorf("stress.scenarios[%d]: price_bps of %s must be above -10000", i, symbol)
+			}
+		}
+		if sc.FXBps <= -10000 || sc.PremiumBps != nil && *sc.PremiumBps <= -10000 {
+			return fmt.Errorf("stress.scenarios[%d]: fx_bps and premium_bps must be above -10000", i)
+		}
+	}
+
 	// UI
 	if c.UI.UpdateIntervalMS <= 0 {
 		return fmt.Errorf("update interval must be positive")
 	}
+	if c.UI.KeyframeSec < 0 {
+		return fmt.Errorf("keyframe interval must not be negative")
+	}
+	if ic := c.UI.Icons; ic.RefreshHours < 0 || ic.MaxDownloads < 0 || ic.BudgetSec < 0 || ic.Concurrency < 0 {
+		return fmt.Errorf("icon sync settings must not be negative")
+	}
 
 	return nil
 }
@@ -177,4 +994,16 @@ func overrideWithEnv(cfg *Config) {
 	if pass := os.Getenv("CRYPTO_BITGET_PASSPHRASE"); pass != "" {
 		cfg.API.Bitget.Passphrase = pass
 	}
+	if pass := os.Getenv("CRYPTO_MQTT_PASSWORD"); pass != "" {
+		cfg.UI.MQTT.Password = pass
+	}
+	if token := os.Getenv("CRYPTO_SIGNAL_TOKEN"); token != "" {
+		cfg.API.Signals.Token = token
+	}
+	if token := os.Getenv("CRYPTO_SIGNAL_STREAM_TOKEN"); token != "" {
+		cfg.API.SignalStream.Token = token
+	}
+	if token := os.Getenv("CRYPTO_ORDER_TOKEN"); token != "" {
+		cfg.API.Orders.Token = token
+	}
 }
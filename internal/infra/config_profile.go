@@ -0,0 +1,102 @@
+package infra
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config.yaml 의 profiles 섹션: 이름별로 상위 설정 위에 덮어쓸 값만 적고,
+// extends 로 다른 프로필을 상속합니다. 맵은 재귀적으로 병합되고 값/목록은 통째로 대체됩니다.
+//
+//	profiles:
+//	  monitor: {trading: {mode: PAPER}, engine: {oms: {enabled: false}}}
+//	  live:    {extends: monitor, trading: {mode: REAL}, engine: {oms: {enabled: true}}}
+const (
+	profilesKey = "profiles"
+	extendsKey  = "extends"
+)
+
+var (
+	profileMu     sync.RWMutex
+	activeProfile string
+)
+
+// SetConfigProfile selects the profile LoadConfig applies (--profile).
+// Empty falls back to CRYPTO_PROFILE, then to the base configuration.
+func SetConfigProfile(name string) {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	activeProfile = name
+}
+
+// ConfigProfile returns the profile LoadConfig applies ("" = none).
+func ConfigProfile() string {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+	if activeProfile != "" {
+		return activeProfile
+	}
+	return os.Getenv("CRYPTO_PROFILE")
+}
+
+// applyProfile returns the YAML document data with profile name and its
+// ancestors merged over the base settings, oldest ancestor first. An empty
+// name returns data unchanged.
+func applyProfile(data []byte, name string) ([]byte, error) {
+	if name == "" {
+		return data, nil
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	profiles, _ := doc[profilesKey].(map[string]any)
+	delete(doc, profilesKey)
+
+	var names []string
+	var chain []map[string]any
+	for next := name; next != ""; {
+		if slices.Contains(names, next) {
+			return nil, fmt.Errorf("profile %q: inheritance cycle through %q", name, next)
+		}
+		raw, ok := profiles[next]
+		if !ok {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("unknown profile %q", next)
+			}
+			return nil, fmt.Errorf("profile %q extends unknown profile %q", names[len(names)-1], next)
+		}
+		p, isMap := raw.(map[string]any)
+		if raw != nil && !isMap {
+			return nil, fmt.Errorf("profile %q is not a mapping", next)
+		}
+		names = append(names, next)
+		chain = append(chain, p)
+		next, _ = p[extendsKey].(string)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		mergeYAML(doc, chain[i])
+	}
+	return yaml.Marshal(doc)
+}
+
+// mergeYAML sets every key of over on dst: nested maps merge, anything else
+// replaces. The extends key is not copied.
+func mergeYAML(dst, over map[string]any) {
+	for k, v := range over {
+		if k == extendsKey {
+			continue
+		}
+		sub, isMap := v.(map[string]any)
+		cur, curMap := dst[k].(map[string]any)
+		if isMap && curMap {
+			mergeYAML(cur, sub)
+			continue
+		}
+		dst[k] = v
+	}
+}
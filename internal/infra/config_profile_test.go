@@ -0,0 +1,110 @@
+package infra
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const profileDoc = `
+trading:
+  mode: PAPER
+engine:
+  oms:
+    enabled: false
+    id_prefix: cg
+  timers: [1m]
+profiles:
+  monitor: {}
+  paper:
+    extends: monitor
+    engine:
+      oms:
+        enabled: true
+  live:
+    extends: paper
+    trading:
+      mode: REAL
+    engine:
+      timers: [5m]
+  loop:
+    extends: loop2
+  loop2:
+    extends: loop
+`
+
+func TestApplyProfile(t *testing.T) {
+	type doc struct {
+		Trading struct {
+			Mode string `yaml:"mode"`
+		} `yaml:"trading"`
+		Engine struct {
+			OMS struct {
+				Enabled  bool   `yaml:"enabled"`
+				IDPrefix string `yaml:"id_prefix"`
+			} `yaml:"oms"`
+			Timers []string `yaml:"timers"`
+		} `yaml:"engine"`
+	}
+
+	data, err := applyProfile([]byte(profileDoc), "live")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got doc
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Trading.Mode != "REAL" || !got.Engine.OMS.Enabled || got.Engine.OMS.IDPrefix != "cg" {
+		t.Errorf("live = %+v; want REAL with the inherited OMS and the base prefix", got)
+	}
+	if len(got.Engine.Timers) != 1 || got.Engine.Timers[0] != "5m" {
+		t.Errorf("timers = %v; a list must be replaced, not merged", got.Engine.Timers)
+	}
+	if strings.Contains(string(data), "profiles") {
+		t.Error("the profiles section must not reach the config")
+	}
+
+	if data, _ := applyProfile([]byte(profileDoc), ""); string(data) != profileDoc {
+		t.Error("no profile must leave the file unchanged")
+	}
+	for _, name := range []string{"missing", "loop"} {
+		if _, err := applyProfile([]byte(profileDoc), name); err == nil {
+			t.Errorf("profile %q: expected an error", name)
+		}
+	}
+}
+
+func TestLoadConfig_Profiles(t *testing.T) {
+	defer SetConfigProfile("")
+	modes := map[string]string{"": "PAPER", "monitor": "PAPER", "paper": "PAPER", "live": "REAL"}
+	for name, mode := range modes {
+		SetConfigProfile(name)
+		cfg, err := LoadConfig("../../configs/config.yaml")
+		if err != nil {
+			t.Fatalf("profile %q: %v", name, err)
+		}
+		if cfg.Profile != name || cfg.Trading.Mode != mode {
+			t.Errorf("profile %q: got profile %q, mode %s; want mode %s", name, cfg.Profile, cfg.Trading.Mode, mode)
+		}
+	}
+}
+
+func TestLoadConfig_PiProfile(t *testing.T) {
+	defer SetConfigProfile("")
+	SetConfigProfile("pi")
+	cfg, err := LoadConfig("../../configs/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Engine.OMS.Enabled || cfg.Engine.OrderBook.Enabled || cfg.Engine.Trades.Enabled || cfg.Engine.Candles.Interval != "" {
+		t.Error("pi must monitor only, without order book, trade or candle feeds")
+	}
+	if m := cfg.Engine.Memory; m.LimitMB <= 0 || m.LimitMB > 150 || !m.SkipWarmup {
+		t.Errorf("memory = %+v; want a limit under 150MB and no warmup", m)
+	}
+	if cfg.Engine.InboxSize >= 1024 || cfg.UI.Icons.Concurrency != 1 {
+		t.Errorf("inbox %d, icon concurrency %d; want both reduced", cfg.Engine.InboxSize, cfg.UI.Icons.Concurrency)
+	}
+}
@@ -0,0 +1,224 @@
+package infra
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DialerConfig controls how gateways resolve and connect to exchange hosts.
+type DialerConfig struct {
+	PreferIPv4 bool          // Try IPv4 addresses before IPv6 (broken v6 routes stall for the full timeout)
+	Resolver   string        // DNS server "host:port"; "" = system resolver
+	DNSTTL     time.Duration // Cache resolved addresses this long; 0 = no cache
+	Timeout    time.Duration // Per-address connect timeout; 0 = 5s
+}
+
+type dnsEntry struct {
+	addrs   []net.IPAddr
+	expires time.Time
+}
+
+// Dialer resolves hosts itself so it can override the resolver and DNS TTL, then
+// tries each address in turn. Exchanges rotating DNS records otherwise leave the
+// default resolver handing out dead addresses for the whole system TTL.
+// Every connect attempt is timed and reported to Metrics.
+type Dialer struct {
+	cfg      DialerConfig
+	resolver *net.Resolver
+	metrics  *Metrics
+	lookup   func(ctx context.Context, host string) ([]net.IPAddr, error)
+	sessions tls.ClientSessionCache // Shared by WS and REST clients for TLS resumption
+	rootCAs  *x509.CertPool         // Tests only; nil = system roots
+
+	mu    sync.Mutex
+	cache map[string]dnsEntry
+}
+
+// NewDialer creates a dialer. metrics may be nil.
+func NewDialer(cfg DialerConfig, metrics *Metrics) *Dialer {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	resolver := net.DefaultResolver
+	if cfg.Resolver != "" {
+		server := cfg.Resolver
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	d := &Dialer{
+		cfg:      cfg,
+		resolver: resolver,
+		metrics:  metrics,
+		sessions: tls.NewLRUClientSessionCache(tlsSessionCacheSize),
+		cache:    make(map[string]dnsEntry),
+	}
+	d.lookup = d.resolver.LookupIPAddr
+	return d
+}
+
+// DialContext implements the net.Dialer / http.Transport / websocket.Dialer hook.
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("dns lookup %s: %w", host, err)
+	}
+	ips = orderAddrs(ips, network, d.cfg.PreferIPv4)
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no %s address for %s", network, host)
+	}
+
+	var errs []error
+	for _, ip := range ips {
+		start := time.Now()
+		conn, err := (&net.Dialer{Timeout: d.cfg.Timeout}).DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if d.metrics != nil {
+			d.metrics.RecordDial(host, time.Since(start), err == nil)
+		}
+		if err == nil {
+			return conn, nil
+		}
+
+		slog.Debug("Dial attempt failed", "host", host, "ip", ip.String(), "elapsed", time.Since(start), "err", err)
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// The cached set is evidently stale: force a fresh lookup next time
+	d.forget(host)
+	return nil, errors.Join(errs...)
+}
+
+func (d *Dialer) resolve(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, nil
+	}
+
+	if d.cfg.DNSTTL > 0 {
+		d.mu.Lock()
+		entry, ok := d.cache[host]
+		d.mu.Unlock()
+		if ok && time.Now().Before(entry.expires) {
+			return entry.addrs, nil
+		}
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.cfg.DNSTTL > 0 {
+		d.mu.Lock()
+		d.cache[host] = dnsEntry{addrs: addrs, expires: time.Now().Add(d.cfg.DNSTTL)}
+		d.mu.Unlock()
+	}
+	return addrs, nil
+}
+
+func (d *Dialer) forget(host string) {
+	d.mu.Lock()
+	delete(d.cache, host)
+	d.mu.Unlock()
+}
+
+// orderAddrs filters by network family (tcp4/tcp6) and puts IPv4 first when preferred.
+// Returns a new slice; the cached slice is never reordered in place.
+func orderAddrs(addrs []net.IPAddr, network string, preferIPv4 bool) []net.IPAddr {
+	var v4, v6 []net.IPAddr
+	for _, a := range addrs {
+		if a.IP.To4() != nil {
+			v4 = append(v4, a)
+		} else {
+			v6 = append(v6, a)
+		}
+	}
+
+	switch network {
+	case "tcp4":
+		return v4
+	case "tcp6":
+		return v6
+	}
+	if preferIPv4 {
+		return append(v4, v6...)
+	}
+
+	out := make([]net.IPAddr, len(addrs))
+	copy(out, addrs)
+	return out
+}
+
+var sharedDialer atomic.Pointer[Dialer]
+
+// SharedDialer returns the process-wide dialer used by all gateways.
+func SharedDialer() *Dialer {
+	if d := sharedDialer.Load(); d != nil {
+		return d
+	}
+	sharedDialer.CompareAndSwap(nil, NewDialer(DialerConfig{}, GlobalMetrics))
+	return sharedDialer.Load()
+}
+
+// SetSharedDialer replaces the process-wide dialer (call once at startup, from config).
+func SetSharedDialer(d *Dialer) {
+	sharedDialer.Store(d)
+}
+
+// sharedDialContext resolves the shared dialer at dial time, so clients created
+// before SetSharedDialer still pick up the configured one.
+func sharedDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return SharedDialer().DialContext(ctx, network, addr)
+}
+
+// NewHTTPTransport returns a transport dialing (and handshaking TLS) through the shared dialer.
+func NewHTTPTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = sharedDialContext
+	transport.DialTLSContext = sharedDialTLSContext
+	return transport
+}
+
+var httpTimeout atomic.Int64 // Configured REST request timeout in ns; 0 = per-client default
+
+// SetHTTPTimeout sets the per-request timeout of outbound REST calls (call once
+// at startup, from config, before creating clients). 0 keeps each client's default.
+func SetHTTPTimeout(d time.Duration) {
+	httpTimeout.Store(int64(d))
+}
+
+// HTTPTimeout returns the configured REST request timeout, or fallback if none is set.
+func HTTPTimeout(fallback time.Duration) time.Duration {
+	if d := time.Duration(httpTimeout.Load()); d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// NewHTTPClient returns a client using the shared dialer. Each request times out
+// after the configured REST timeout, or after timeout if none is set.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: HTTPTimeout(timeout), Transport: NewHTTPTransport()}
+}
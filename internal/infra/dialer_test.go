@@ -0,0 +1,88 @@
+package infra
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestOrderAddrs(t *testing.T) {
+	v6 := net.IPAddr{IP: net.ParseIP("::1")}
+	v4 := net.IPAddr{IP: net.ParseIP("127.0.0.1")}
+	addrs := []net.IPAddr{v6, v4}
+
+	if got := orderAddrs(addrs, "tcp", true); !got[0].IP.Equal(v4.IP) {
+		t.Errorf("expected IPv4 first, got %v", got)
+	}
+	if got := orderAddrs(addrs, "tcp", false); !got[0].IP.Equal(v6.IP) {
+		t.Errorf("expected resolver order kept, got %v", got)
+	}
+	if got := orderAddrs(addrs, "tcp6", true); len(got) != 1 || !got[0].IP.Equal(v6.IP) {
+		t.Errorf("expected tcp6 to keep only IPv6, got %v", got)
+	}
+	if !addrs[0].IP.Equal(v6.IP) {
+		t.Error("input slice must not be reordered")
+	}
+}
+
+func TestDialer_CacheFallbackAndMetrics(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	// A dead address first (nothing listens on 127.0.0.2): the dialer must fall through
+	deadIP := "127.0.0.2"
+
+	metrics := &Metrics{}
+	d := NewDialer(DialerConfig{DNSTTL: time.Minute, Timeout: time.Second}, metrics)
+	lookups := 0
+	d.lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		lookups++
+		return []net.IPAddr{{IP: net.ParseIP(deadIP)}, {IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		conn, err := d.DialContext(context.Background(), "tcp", net.JoinHostPort("exchange.test", port))
+		if err != nil {
+			t.Fatalf("dial %d: %v", i, err)
+		}
+		conn.Close()
+	}
+	if lookups != 1 {
+		t.Errorf("expected cached lookup, got %d lookups", lookups)
+	}
+
+	snap := metrics.Snapshot()
+	if snap.DialAttempts["exchange.test"] < 2 {
+		t.Errorf("expected dial attempts recorded, got %v", snap.DialAttempts)
+	}
+	if snap.DialAttempts["exchange.test"] != 2+snap.DialFailures["exchange.test"] {
+		t.Errorf("attempts %d should equal successes (2) + failures %d",
+			snap.DialAttempts["exchange.test"], snap.DialFailures["exchange.test"])
+	}
+}
+
+func TestHTTPTimeout(t *testing.T) {
+	defer SetHTTPTimeout(0)
+
+	if c := NewHTTPClient(3 * time.Second); c.Timeout != 3*time.Second {
+		t.Errorf("unconfigured client must keep its default, got %v", c.Timeout)
+	}
+	SetHTTPTimeout(7 * time.Second)
+	if c := NewHTTPClient(3 * time.Second); c.Timeout != 7*time.Second {
+		t.Errorf("configured timeout must apply, got %v", c.Timeout)
+	}
+}
@@ -3,6 +3,7 @@ package infra
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -31,26 +32,45 @@ type rateAPIResponse struct {
 	} `json:"chart"`
 }
 
-// ExchangeRateClient fetches USD/KRW exchange rate from configured API.
+// fxPair is one currency pair polled from its own provider URL.
+type fxPair struct {
+	symbol   string // BASE/QUOTE, e.g. USD/KRW
+	url      string
+	interval time.Duration
+	next     time.Time // Next poll due
+	breaker  *CircuitBreaker
+}
+
+func newFXPair(symbol, url string, interval time.Duration) *fxPair {
+	return &fxPair{
+		symbol:   symbol,
+		url:      url,
+		interval: interval,
+		breaker:  NewCircuitBreaker(DefaultCircuitBreakerConfig("fx-" + symbol)),
+	}
+}
+
+// ExchangeRateClient fetches FX rates (USD/KRW plus any added pairs) from the
+// configured APIs. All pairs are polled from one goroutine so the "FX" source
+// keeps a single, ordered sequence.
 type ExchangeRateClient struct {
-	inbox        chan<- event.Event
-	nextSeq      *uint64
-	pollInterval time.Duration
-	apiURL       string
-	httpClient   *http.Client
-	cancel       context.CancelFunc
+	inbox      chan<- event.Event
+	nextSeq    *uint64
+	pairs      []*fxPair // pairs[0] is USD/KRW
+	tick       time.Duration
+	httpClient *http.Client
+	cancel     context.CancelFunc
 }
 
 // NewExchangeRateClient creates a new exchange rate client.
 func NewExchangeRateClient(inbox chan<- event.Event, seq *uint64) *ExchangeRateClient {
 	return &ExchangeRateClient{
-		inbox:        inbox,
-		nextSeq:      seq,
-		pollInterval: 60 * time.Second,
-		apiURL:       "https://query1.finance.yahoo.com/v8/finance/chart/KRW=X",
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+		inbox:   inbox,
+		nextSeq: seq,
+		pairs: []*fxPair{
+			newFXPair("USD/KRW", "https://query1.finance.yahoo.com/v8/finance/chart/KRW=X", 60*time.Second),
 		},
+		httpClient: NewHTTPClient(10 * time.Second),
 	}
 }
 
@@ -58,14 +78,25 @@ func NewExchangeRateClient(inbox chan<- event.Event, seq *uint64) *ExchangeRateC
 func NewExchangeRateClientWithConfig(inbox chan<- event.Event, seq *uint64, apiURL string, pollIntervalSec int) *ExchangeRateClient {
 	client := NewExchangeRateClient(inbox, seq)
 	if apiURL != "" {
-		client.apiURL = apiURL
+		client.pairs[0].url = apiURL
 	}
 	if pollIntervalSec > 0 {
-		client.pollInterval = time.Duration(pollIntervalSec) * time.Second
+		client.pairs[0].interval = time.Duration(pollIntervalSec) * time.Second
 	}
 	return client
 }
 
+// AddPair polls another currency pair (e.g. "USD/JPY") from apiURL, which must
+// answer in the same chart format. pollIntervalSec <= 0 uses 60 seconds.
+// Must be called before Start.
+func (c *ExchangeRateClient) AddPair(symbol, apiURL string, pollIntervalSec int) {
+	interval := 60 * time.Second
+	if pollIntervalSec > 0 {
+		interval = time.Duration(pollIntervalSec) * time.Second
+	}
+	c.pairs = append(c.pairs, newFXPair(symbol, apiURL, interval))
+}
+
 // Start begins polling for exchange rate updates.
 func (c *ExchangeRateClient) Start(ctx context.Context) error {
 	ctx, c.cancel = context.WithCancel(ctx)
@@ -73,8 +104,13 @@ func (c *ExchangeRateClient) Start(ctx context.Context) error {
 		fmt.Printf("Initial exchange rate fetch failed: %v\n", err)
 	}
 
+	c.tick = c.pairs[0].interval
+	for _, p := range c.pairs {
+		c.tick = min(c.tick, p.interval)
+	}
+
 	go func() {
-		ticker := time.NewTicker(c.pollInterval)
+		ticker := time.NewTicker(c.tick)
 		defer ticker.Stop()
 		for {
 			select {
@@ -95,20 +131,49 @@ func (c *ExchangeRateClient) Stop() {
 	}
 }
 
+// fetchRate polls every pair that is due (within half a tick, so timer jitter
+// never skips a whole period). Returns the first failure.
 func (c *ExchangeRateClient) fetchRate(ctx context.Context) error {
+	var firstErr error
+	now := time.Now()
+	for _, p := range c.pairs {
+		if now.Add(c.tick / 2).Before(p.next) {
+			continue
+		}
+		p.next = now.Add(p.interval)
+		if err := c.fetchPair(ctx, p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// fetchPair polls p with retries. Each pair has its own circuit breaker, so
+// a provider that keeps failing is skipped until it recovers instead of
+// costing three timeouts per poll. Canceling ctx aborts the request and the
+// backoff between retries.
+func (c *ExchangeRateClient) fetchPair(ctx context.Context, p *fxPair) error {
 	for i := 0; i < 3; i++ {
 		if i > 0 {
-			time.Sleep(CalculateBackoff(i))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(CalculateBackoff(i)):
+			}
 		}
-		if err := c.doFetch(ctx); err == nil {
+		err := p.breaker.Execute(ctx, func() error { return c.doFetch(ctx, p) })
+		if err == nil {
 			return nil
 		}
+		if errors.Is(err, ErrCircuitOpen) {
+			return err
+		}
 	}
-	return fmt.Errorf("all fetch attempts failed")
+	return fmt.Errorf("all fetch attempts failed for %s", p.symbol)
 }
 
-func (c *ExchangeRateClient) doFetch(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL, nil)
+func (c *ExchangeRateClient) doFetch(ctx context.Context, p *fxPair) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
 	if err != nil {
 		return err
 	}
@@ -150,7 +215,7 @@ func (c *ExchangeRateClient) doFetch(ctx context.Context) error {
 	ev := event.AcquireMarketUpdateEvent()
 	ev.Seq = quant.NextSeq(c.nextSeq)
 	ev.Ts = quant.TimeStamp(time.Now().UnixMicro())
-	ev.Symbol = "USD/KRW"
+	ev.Symbol = p.symbol
 	ev.PriceMicros = quant.ToPriceMicrosStr(priceStr)
 	ev.QtySats = quant.QtyScale // 1.0 fixed as baseline for rate
 	ev.Exchange = "FX"
@@ -3,9 +3,11 @@ package infra
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -188,3 +190,92 @@ func TestExchangeRateClient_RetryOnFailure(t *testing.T) {
 		t.Errorf("Expected 3 calls, got %d", callCount)
 	}
 }
+
+func TestExchangeRateClient_CircuitOpens(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	inbox := make(chan event.Event, 5)
+	nextSeq := uint64(1)
+	client := NewExchangeRateClientWithConfig(inbox, &nextSeq, server.URL, 1)
+	client.pairs[0].breaker = NewCircuitBreaker(CircuitBreakerConfig{
+		Name:             "fx-test",
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		Timeout:          time.Hour,
+	})
+
+	// The first failure opens the breaker, which ends the retries
+	if err := client.fetchPair(context.Background(), client.pairs[0]); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if err := client.fetchPair(context.Background(), client.pairs[0]); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("an open breaker must not call the API: %d calls", n)
+	}
+}
+
+func TestExchangeRateClient_CancelStopsRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	inbox := make(chan event.Event, 5)
+	nextSeq := uint64(1)
+	client := NewExchangeRateClientWithConfig(inbox, &nextSeq, server.URL, 1)
+
+	// Shutdown during the retry backoff must not wait it out
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := client.fetchPair(ctx, client.pairs[0]); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("fetchPair took %v after cancel", elapsed)
+	}
+}
+
+func TestExchangeRateClient_AddPair(t *testing.T) {
+	krw, _ := json.Marshal(createMockRateResponse(1400.00))
+	jpy, _ := json.Marshal(createMockRateResponse(140.00))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jpy" {
+			w.Write(jpy)
+			return
+		}
+		w.Write(krw)
+	}))
+	defer server.Close()
+
+	inbox := make(chan event.Event, 4)
+	nextSeq := uint64(0)
+	client := NewExchangeRateClientWithConfig(inbox, &nextSeq, server.URL+"/krw", 60)
+	client.AddPair("USD/JPY", server.URL+"/jpy", 60)
+
+	if err := client.fetchRate(context.Background()); err != nil {
+		t.Fatalf("fetchRate failed: %v", err)
+	}
+	if len(inbox) != 2 {
+		t.Fatalf("expected one event per pair, got %d", len(inbox))
+	}
+	first, second := (<-inbox).(*event.MarketUpdateEvent), (<-inbox).(*event.MarketUpdateEvent)
+	if first.Symbol != "USD/KRW" || second.Symbol != "USD/JPY" || second.PriceMicros != quant.ToPriceMicros(140.00) {
+		t.Errorf("unexpected events %+v / %+v", first, second)
+	}
+	if second.Seq != first.Seq+1 || second.Exchange != "FX" {
+		t.Errorf("pairs must share the FX sequence: %d, %d", first.Seq, second.Seq)
+	}
+
+	// Not due again until the poll interval passes
+	if err := client.fetchRate(context.Background()); err != nil || len(inbox) != 0 {
+		t.Errorf("pairs polled again before their interval (%v, %d events)", err, len(inbox))
+	}
+}
@@ -0,0 +1,60 @@
+package infra
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"time"
+)
+
+// FundingRateRefresh is how often a perp worker re-reports a changed predicted
+// funding rate within one epoch. Tickers carry the rate on every push; a new
+// epoch is reported at once.
+const FundingRateRefresh = time.Minute
+
+type fundingReport struct {
+	next quant.TimeStamp
+	rate int64
+	ts   quant.TimeStamp
+}
+
+// FundingTracker turns the funding fields of perp tickers into FundingEvents
+// only when they change. Owned by one worker's read loop; not thread-safe.
+type FundingTracker struct {
+	exchange string
+	seq      *uint64
+	reported map[string]fundingReport // By unified symbol
+}
+
+// NewFundingTracker creates a tracker stamping events with exchange and the
+// worker's seq counter.
+func NewFundingTracker(exchange string, seq *uint64) *FundingTracker {
+	return &FundingTracker{exchange: exchange, seq: seq, reported: make(map[string]fundingReport)}
+}
+
+// Observe returns the FundingEvent to send for a ticker of symbol announcing
+// the epoch next at rate, or nil if nothing changed enough. Call Sent once the
+// inbox accepted the event, so a dropped one is retried with the next ticker.
+func (t *FundingTracker) Observe(symbol string, next quant.TimeStamp, rateMicros int64, ts quant.TimeStamp) *event.FundingEvent {
+	if next <= 0 {
+		return nil
+	}
+	last, ok := t.reported[symbol]
+	switch {
+	case !ok || next > last.next:
+	case next == last.next && rateMicros != last.rate && ts-last.ts >= quant.TimeStamp(FundingRateRefresh.Microseconds()):
+	default:
+		return nil
+	}
+	return &event.FundingEvent{
+		BaseEvent:     event.BaseEvent{Seq: quant.NextSeq(t.seq), Ts: ts},
+		Exchange:      t.exchange,
+		Symbol:        symbol,
+		NextFundingTs: next,
+		RateMicros:    rateMicros,
+	}
+}
+
+// Sent records ev as reported.
+func (t *FundingTracker) Sent(ev *event.FundingEvent) {
+	t.reported[ev.Symbol] = fundingReport{next: ev.NextFundingTs, rate: ev.RateMicros, ts: ev.Ts}
+}
@@ -0,0 +1,42 @@
+package infra
+
+import (
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+func TestFundingTracker_ReportsChanges(t *testing.T) {
+	var seq uint64
+	tr := NewFundingTracker("BITGET_FUTURES", &seq)
+	const next = quant.TimeStamp(8 * 3600 * 1_000_000)
+	refresh := quant.TimeStamp(FundingRateRefresh.Microseconds())
+
+	if tr.Observe("BTC", 0, 100, 1) != nil {
+		t.Error("a ticker without an epoch must not be reported")
+	}
+	f := tr.Observe("BTC", next, 100, 1)
+	if f == nil || f.Exchange != "BITGET_FUTURES" || f.Seq != 1 {
+		t.Fatalf("first epoch not reported: %+v", f)
+	}
+	// Not accepted by the inbox: retried with the next ticker
+	if f = tr.Observe("BTC", next, 100, 2); f == nil {
+		t.Fatal("an unsent report must be retried")
+	}
+	tr.Sent(f)
+
+	if tr.Observe("BTC", next, 100, refresh*2) != nil {
+		t.Error("an unchanged epoch must not be reported again")
+	}
+	if tr.Observe("BTC", next, 110, refresh) != nil {
+		t.Error("a rate change within the refresh interval must wait")
+	}
+	if f = tr.Observe("BTC", next, 110, refresh+2); f == nil || f.RateMicros != 110 {
+		t.Errorf("a rate change after the refresh interval must be reported, got %+v", f)
+	}
+	if tr.Observe("BTC", next-1, 110, refresh*3) != nil {
+		t.Error("an earlier epoch (stale push) must not be reported")
+	}
+	if tr.Observe("ETH", next, 100, 2) == nil {
+		t.Error("symbols are tracked separately")
+	}
+}
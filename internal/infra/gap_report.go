@@ -0,0 +1,222 @@
+package infra
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Gap report record kinds
+const (
+	GapKindGap          = "GAP"
+	GapKindDuplicate    = "DUPLICATE"
+	GapKindDailySummary = "DAILY_SUMMARY"
+)
+
+// GapRecord is one line of the gap report (JSONL).
+type GapRecord struct {
+	Kind     string `json:"kind"`
+	Source   string `json:"source"`
+	Expected uint64 `json:"expected"`
+	Got      uint64 `json:"got"`
+	Size     uint64 `json:"size"`      // Missing events (0 for duplicates)
+	WallTime string `json:"wall_time"` // RFC3339Nano, local wall clock
+}
+
+// GapSourceStats aggregates one source's anomalies for a day.
+// Buckets show how gap sizes distribute around the tolerance threshold.
+type GapSourceStats struct {
+	Gaps       uint64            `json:"gaps"`
+	Duplicates uint64            `json:"duplicates"`
+	Missing    uint64            `json:"missing"`
+	MaxGap     uint64            `json:"max_gap"`
+	Buckets    map[string]uint64 `json:"buckets"` // "1", "2-5", "6-10", "11-50", "51+"
+}
+
+// GapSummary is the daily roll-up record appended at UTC day rollover.
+type GapSummary struct {
+	Kind    string                     `json:"kind"`
+	Day     string                     `json:"day"` // YYYY-MM-DD (UTC)
+	Partial bool                       `json:"partial,omitempty"`
+	Sources map[string]*GapSourceStats `json:"sources"`
+}
+
+// GapReport appends every tolerated gap / duplicate to a JSONL file and
+// mirrors them into labeled metrics. Implements engine.GapRecorder.
+type GapReport struct {
+	mu      sync.Mutex
+	f       *os.File
+	metrics *Metrics
+	now     func() time.Time
+
+	day   string
+	stats map[string]*GapSourceStats
+}
+
+// NewGapReport opens (append mode) the report at path. metrics may be nil.
+func NewGapReport(path string, metrics *Metrics) (*GapReport, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gap report: %w", err)
+	}
+	return &GapReport{
+		f:       f,
+		metrics: metrics,
+		now:     time.Now,
+		stats:   make(map[string]*GapSourceStats),
+	}, nil
+}
+
+// RecordSequenceGap records a gap where events expected..got-1 never arrived.
+func (r *GapReport) RecordSequenceGap(source string, expected, got uint64) {
+	size := got - expected
+	if r.metrics != nil {
+		r.metrics.RecordSequenceGap(source, size)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st := r.sourceStats(source)
+	st.Gaps++
+	st.Missing += size
+	if size > st.MaxGap {
+		st.MaxGap = size
+	}
+	st.Buckets[gapBucket(size)]++
+
+	r.write(GapRecord{Kind: GapKindGap, Source: source, Expected: expected, Got: got, Size: size, WallTime: r.now().Format(time.RFC3339Nano)})
+}
+
+// RecordSequenceDuplicate records an event whose seq was already seen.
+func (r *GapReport) RecordSequenceDuplicate(source string, expected, got uint64) {
+	if r.metrics != nil {
+		r.metrics.RecordSequenceDuplicate(source)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sourceStats(source).Duplicates++
+	r.write(GapRecord{Kind: GapKindDuplicate, Source: source, Expected: expected, Got: got, WallTime: r.now().Format(time.RFC3339Nano)})
+}
+
+// Summary returns a copy of today's running statistics.
+func (r *GapReport) Summary() GapSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sum := GapSummary{Kind: GapKindDailySummary, Day: r.day, Partial: true, Sources: make(map[string]*GapSourceStats, len(r.stats))}
+	for src, st := range r.stats {
+		cp := *st
+		cp.Buckets = make(map[string]uint64, len(st.Buckets))
+		for k, v := range st.Buckets {
+			cp.Buckets[k] = v
+		}
+		sum.Sources[src] = &cp
+	}
+	return sum
+}
+
+// Close writes a partial summary for the current day and closes the file.
+func (r *GapReport) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushSummary(true)
+	return r.f.Close()
+}
+
+// Run flushes the daily summary at every UTC day boundary, so a quiet day after
+// a noisy one still produces its DAILY_SUMMARY. Blocks until done is closed.
+func (r *GapReport) Run(done <-chan struct{}) {
+	for {
+		now := r.now().UTC()
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		timer := time.NewTimer(midnight.Sub(now))
+
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			r.mu.Lock()
+			r.rollover()
+			r.mu.Unlock()
+		}
+	}
+}
+
+// rollover writes the previous day's summary once the UTC date changed. Caller holds r.mu.
+func (r *GapReport) rollover() {
+	day := r.now().UTC().Format("2006-01-02")
+	if r.day != day {
+		r.flushSummary(false)
+		r.day = day
+	}
+}
+
+// sourceStats returns the stats bucket, rolling the day over first if needed.
+func (r *GapReport) sourceStats(source string) *GapSourceStats {
+	r.rollover()
+
+	st, ok := r.stats[source]
+	if !ok {
+		st = &GapSourceStats{Buckets: make(map[string]uint64)}
+		r.stats[source] = st
+	}
+	return st
+}
+
+func (r *GapReport) flushSummary(partial bool) {
+	if r.day == "" || len(r.stats) == 0 {
+		return
+	}
+
+	sum := GapSummary{Kind: GapKindDailySummary, Day: r.day, Partial: partial, Sources: r.stats}
+	r.write(sum)
+
+	sources := make([]string, 0, len(r.stats))
+	for src := range r.stats {
+		sources = append(sources, src)
+	}
+	sort.Strings(sources)
+	for _, src := range sources {
+		st := r.stats[src]
+		slog.Info("📊 Sequence gap summary",
+			slog.String("day", r.day),
+			slog.String("source", src),
+			slog.Uint64("gaps", st.Gaps),
+			slog.Uint64("duplicates", st.Duplicates),
+			slog.Uint64("missing", st.Missing),
+			slog.Uint64("max_gap", st.MaxGap))
+	}
+
+	r.stats = make(map[string]*GapSourceStats)
+}
+
+func (r *GapReport) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	r.f.Write(append(data, '\n'))
+}
+
+func gapBucket(size uint64) string {
+	switch {
+	case size <= 1:
+		return "1"
+	case size <= 5:
+		return "2-5"
+	case size <= 10:
+		return "6-10"
+	case size <= 50:
+		return "11-50"
+	default:
+		return "51+"
+	}
+}
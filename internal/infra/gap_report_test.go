@@ -0,0 +1,110 @@
+package infra
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGapReport_RecordsAndDailySummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gaps.jsonl")
+	m := &Metrics{}
+	r, err := NewGapReport(path, m)
+	if err != nil {
+		t.Fatalf("Failed to open report: %v", err)
+	}
+
+	now := time.Date(2026, 1, 7, 23, 59, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	r.RecordSequenceGap("UPBIT", 10, 13) // size 3
+	r.RecordSequenceGap("UPBIT", 20, 21) // size 1
+	r.RecordSequenceDuplicate("BITGET_SPOT", 5, 4)
+
+	sum := r.Summary()
+	if st := sum.Sources["UPBIT"]; st == nil || st.Gaps != 2 || st.Missing != 4 || st.MaxGap != 3 {
+		t.Fatalf("Unexpected UPBIT stats: %+v", st)
+	}
+
+	// Next UTC day triggers the summary for the previous one
+	now = now.Add(2 * time.Minute)
+	r.RecordSequenceGap("UPBIT", 30, 32)
+	r.Close()
+
+	f, _ := os.Open(path)
+	defer f.Close()
+	var kinds []string
+	var firstSummary GapSummary
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec struct {
+			Kind string `json:"kind"`
+		}
+		json.Unmarshal(sc.Bytes(), &rec)
+		kinds = append(kinds, rec.Kind)
+		if rec.Kind == GapKindDailySummary && firstSummary.Day == "" {
+			json.Unmarshal(sc.Bytes(), &firstSummary)
+		}
+	}
+
+	want := []string{GapKindGap, GapKindGap, GapKindDuplicate, GapKindDailySummary, GapKindGap, GapKindDailySummary}
+	if len(kinds) != len(want) {
+		t.Fatalf("Expected records %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("Expected records %v, got %v", want, kinds)
+		}
+	}
+	if firstSummary.Day != "2026-01-07" || firstSummary.Partial {
+		t.Errorf("Unexpected first summary: %+v", firstSummary)
+	}
+	if firstSummary.Sources["UPBIT"].Buckets["2-5"] != 1 || firstSummary.Sources["UPBIT"].Buckets["1"] != 1 {
+		t.Errorf("Unexpected buckets: %+v", firstSummary.Sources["UPBIT"].Buckets)
+	}
+
+	snap := m.Snapshot()
+	if snap.SequenceGaps["UPBIT"] != 3 || snap.SequenceGapEvents["UPBIT"] != 6 || snap.SequenceDuplicates["BITGET_SPOT"] != 1 {
+		t.Errorf("Unexpected labeled metrics: %+v %+v %+v", snap.SequenceGaps, snap.SequenceGapEvents, snap.SequenceDuplicates)
+	}
+}
+
+func TestGapReport_RolloverWithoutNextDayGap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gaps.jsonl")
+	r, err := NewGapReport(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to open report: %v", err)
+	}
+	defer r.Close()
+
+	now := time.Date(2026, 1, 7, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	r.RecordSequenceGap("UPBIT", 1, 3)
+
+	// Quiet next day: the boundary timer alone must emit the summary
+	now = time.Date(2026, 1, 8, 0, 0, 1, 0, time.UTC)
+	r.mu.Lock()
+	r.rollover()
+	r.mu.Unlock()
+
+	data, _ := os.ReadFile(path)
+	var last GapSummary
+	lines := splitLines(data)
+	json.Unmarshal(lines[len(lines)-1], &last)
+	if last.Kind != GapKindDailySummary || last.Day != "2026-01-07" || last.Partial {
+		t.Fatalf("Expected full summary for 2026-01-07 before any new gap, got %s", lines[len(lines)-1])
+	}
+}
+
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		lines = append(lines, append([]byte(nil), sc.Bytes()...))
+	}
+	return lines
+}
@@ -12,12 +12,26 @@ import (
 	"github.com/disintegration/imaging"
 )
 
+const (
+	iconTimeout = 10 * time.Second // Per download unless configured
+	// Upbit CDN - best coverage for Korean exchanges
+	iconBaseURL = "https://static.upbit.com/logos"
+)
+
 // IconDownloader handles downloading and caching coin icons
 type IconDownloader struct {
 	basePath string
+	baseURL  string
 	client   *http.Client
 }
 
+// IconValidators are the HTTP cache validators of a downloaded icon, sent back
+// on the next fetch so an unchanged icon costs a 304 instead of a download.
+type IconValidators struct {
+	ETag         string
+	LastModified string
+}
+
 // NewIconDownloader creates a new IconDownloader
 func NewIconDownloader() (*IconDownloader, error) {
 	path, err := getAssetsPath()
@@ -31,61 +45,87 @@ func NewIconDownloader() (*IconDownloader, error) {
 	}
 
 	// Optimize HTTP Transport to prevent connection leaks
-	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport := NewHTTPTransport()
 	transport.MaxIdleConns = 100
 	transport.MaxConnsPerHost = 10
 	transport.IdleConnTimeout = 30 * time.Second
 
+	// No client timeout: each download gets its own from the request context
 	return &IconDownloader{
 		basePath: path,
-		client: &http.Client{
-			Timeout:   10 * time.Second,
-			Transport: transport,
-		},
+		baseURL:  iconBaseURL,
+		client:   &http.Client{Transport: transport},
 	}, nil
 }
 
 // DownloadIcon downloads the icon for a symbol if it doesn't exist
 // Returns the local file path on success
 // Images are resized to 24x24 pixels for consistent UI display
-func (d *IconDownloader) DownloadIcon(symbol string) (string, error) {
+// The download is canceled with ctx and times out after the configured REST
+// timeout (10 seconds by default).
+func (d *IconDownloader) DownloadIcon(ctx context.Context, symbol string) (string, error) {
+	// Check if exists
+	if safeSymbol := sanitizeSymbol(symbol); safeSymbol != "" {
+		if path := d.GetIconPath(safeSymbol); fileExists(path) {
+			return path, nil // Already exists (Cache Hit)
+		}
+	}
+	path, _, _, err := d.FetchIcon(ctx, symbol, IconValidators{})
+	return path, err
+}
+
+// FetchIcon downloads the icon for a symbol, replacing the local file. With the
+// validators of an earlier download it sends a conditional request; if the
+// server answers 304 Not Modified and the local file exists, nothing is
+// downloaded and changed is false. The returned validators are the ones to
+// pass next time. Timeouts as in DownloadIcon.
+func (d *IconDownloader) FetchIcon(ctx context.Context, symbol string, prev IconValidators) (path string, next IconValidators, changed bool, err error) {
 	// Security: Sanitize symbol to prevent path traversal
 	safeSymbol := sanitizeSymbol(symbol)
 	if safeSymbol == "" {
-		return "", fmt.Errorf("invalid symbol: %s", symbol)
+		return "", prev, false, fmt.Errorf("invalid symbol: %s", symbol)
 	}
+	filePath := d.GetIconPath(safeSymbol)
 
-	fileName := strings.ToLower(safeSymbol) + ".png"
-	filePath := filepath.Join(d.basePath, fileName)
-
-	// Check if exists
-	if _, err := os.Stat(filePath); err == nil {
-		return filePath, nil // Already exists (Cache Hit)
+	// Validators are only worth sending while the file they describe exists
+	if !fileExists(filePath) {
+		prev = IconValidators{}
 	}
 
-	// Construct URL (Using Upbit CDN - best coverage for Korean exchanges)
-	url := fmt.Sprintf("https://static.upbit.com/logos/%s.png", strings.ToUpper(symbol))
+	url := fmt.Sprintf("%s/%s.png", d.baseURL, strings.ToUpper(safeSymbol))
+
+	ctx, cancel := context.WithTimeout(ctx, HTTPTimeout(iconTimeout))
+	defer cancel()
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return "", prev, false, err
 	}
 	req.Header.Set("User-Agent", GetUserAgent())
+	if prev.ETag != "" {
+		req.Header.Set("If-None-Match", prev.ETag)
+	}
+	if prev.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.LastModified)
+	}
 
 	resp, err := d.client.Do(req)
 	if err != nil {
-		return "", err
+		return "", prev, false, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && (prev.ETag != "" || prev.LastModified != "") {
+		return filePath, prev, false, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("bad status: %s", resp.Status)
+		return "", prev, false, fmt.Errorf("bad status: %s", resp.Status)
 	}
 
 	// Decode the image
 	srcImg, err := imaging.Decode(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode image: %w", err)
+		return "", prev, false, fmt.Errorf("failed to decode image: %w", err)
 	}
 
 	// Resize to 24x24 with high-quality Lanczos filter
@@ -93,10 +133,14 @@ func (d *IconDownloader) DownloadIcon(symbol string) (string, error) {
 
 	// Save the resized image
 	if err := imaging.Save(resizedImg, filePath); err != nil {
-		return "", fmt.Errorf("failed to save resized image: %w", err)
+		return "", prev, false, fmt.Errorf("failed to save resized image: %w", err)
 	}
 
-	return filePath, nil
+	next = IconValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	return filePath, next, true, nil
 }
 
 // GetIconPath returns the local path for a symbol's icon
@@ -104,6 +148,11 @@ func (d *IconDownloader) GetIconPath(symbol string) string {
 	return filepath.Join(d.basePath, strings.ToLower(symbol)+".png")
 }
 
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 func getAssetsPath() (string, error) {
 	// Dynamically resolve base directory (Portable or OS-Standard)
 	base := GetWorkspaceDir()
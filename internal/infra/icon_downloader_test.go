@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIconDownloader_FetchIconRevalidates(t *testing.T) {
+	var body bytes.Buffer
+	png.Encode(&body, image.NewRGBA(image.Rect(0, 0, 48, 48)))
+
+	var downloads, conditional atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/BTC.png" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(body.Bytes())
+	}))
+	defer srv.Close()
+
+	d := &IconDownloader{basePath: t.TempDir(), baseURL: srv.URL, client: srv.Client()}
+	ctx := context.Background()
+
+	path, validators, changed, err := d.FetchIcon(ctx, "btc", IconValidators{})
+	if err != nil || !changed || validators.ETag != `"v1"` {
+		t.Fatalf("first fetch must download: changed=%v validators=%+v err=%v", changed, validators, err)
+	}
+
+	if _, _, changed, err := d.FetchIcon(ctx, "btc", validators); err != nil || changed {
+		t.Fatalf("unchanged icon must be revalidated, changed=%v err=%v", changed, err)
+	}
+	if downloads.Load() != 1 || conditional.Load() != 1 {
+		t.Errorf("downloads=%d conditional=%d, want 1 and 1", downloads.Load(), conditional.Load())
+	}
+
+	// Validators are useless without the file they describe
+	os.Remove(path)
+	if _, _, changed, err := d.FetchIcon(ctx, "btc", validators); err != nil || !changed {
+		t.Fatalf("missing file must be downloaded again, changed=%v err=%v", changed, err)
+	}
+	if downloads.Load() != 2 {
+		t.Errorf("downloads=%d, want 2", downloads.Load())
+	}
+
+	if _, err := d.DownloadIcon(ctx, "btc"); err != nil || downloads.Load() != 2 {
+		t.Errorf("DownloadIcon must reuse the file on disk, downloads=%d err=%v", downloads.Load(), err)
+	}
+}
@@ -0,0 +1,99 @@
+package infra
+
+import (
+	"sync"
+	"time"
+)
+
+// keepaliveMaxMissed is how many unanswered pings in a row abort the connection.
+const keepaliveMaxMissed = 3
+
+// Keepalive measures WebSocket round trip time from ping/pong pairs and adapts
+// the ping interval: relaxed (max) while healthy, tight (min) once the smoothed
+// RTT exceeds the degraded threshold or a pong goes missing. Probing faster on a
+// sick link detects the failure in seconds instead of waiting for the read timeout.
+//
+// RTT smoothing follows TCP (RFC 6298): srtt += (rtt - srtt) / 8.
+// Safe for concurrent use (pongs arrive on the read goroutine, pings on the ping loop).
+type Keepalive struct {
+	mu          sync.Mutex
+	minInterval time.Duration
+	maxInterval time.Duration
+	degradedRTT time.Duration
+
+	sentAt   time.Time // Zero = no ping outstanding
+	lastRTT  time.Duration
+	srtt     time.Duration
+	missed   int
+	degraded bool
+}
+
+// NewKeepalive creates a keepalive. degradedRTT of 0 disables the RTT criterion.
+func NewKeepalive(minInterval, maxInterval, degradedRTT time.Duration) *Keepalive {
+	if minInterval <= 0 || minInterval > maxInterval {
+		minInterval = maxInterval
+	}
+	return &Keepalive{minInterval: minInterval, maxInterval: maxInterval, degradedRTT: degradedRTT}
+}
+
+// PingSent records a ping at now. If the previous ping was never answered it
+// counts as missed; the returned count tells the caller when to give up.
+func (k *Keepalive) PingSent(now time.Time) (missed int) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !k.sentAt.IsZero() {
+		k.missed++
+	}
+	k.sentAt = now
+	k.updateDegraded()
+	return k.missed
+}
+
+// PongReceived matches a pong to the outstanding ping. ok is false for unsolicited pongs.
+func (k *Keepalive) PongReceived(now time.Time) (rtt time.Duration, ok bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.sentAt.IsZero() {
+		return 0, false
+	}
+
+	rtt = now.Sub(k.sentAt)
+	k.sentAt = time.Time{}
+	k.missed = 0
+	k.lastRTT = rtt
+	if k.srtt == 0 {
+		k.srtt = rtt
+	} else {
+		k.srtt += (rtt - k.srtt) / 8
+	}
+	k.updateDegraded()
+	return rtt, true
+}
+
+// NextInterval returns how long to wait before the next ping.
+func (k *Keepalive) NextInterval() time.Duration {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.degraded {
+		return k.minInterval
+	}
+	return k.maxInterval
+}
+
+// Degraded reports whether the connection looks unhealthy.
+func (k *Keepalive) Degraded() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.degraded
+}
+
+// RTT returns the last and the smoothed round trip time (0 before the first pong).
+func (k *Keepalive) RTT() (last, smoothed time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.lastRTT, k.srtt
+}
+
+func (k *Keepalive) updateDegraded() {
+	k.degraded = k.missed > 0 || (k.degradedRTT > 0 && k.srtt > k.degradedRTT)
+}
@@ -0,0 +1,56 @@
+package infra
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeepalive_AdaptsToRTT(t *testing.T) {
+	k := NewKeepalive(time.Second, 30*time.Second, 500*time.Millisecond)
+	now := time.Unix(0, 0)
+
+	if k.NextInterval() != 30*time.Second {
+		t.Fatalf("expected relaxed interval initially, got %v", k.NextInterval())
+	}
+
+	// Healthy: 50ms RTT
+	k.PingSent(now)
+	if rtt, ok := k.PongReceived(now.Add(50 * time.Millisecond)); !ok || rtt != 50*time.Millisecond {
+		t.Fatalf("unexpected rtt %v %v", rtt, ok)
+	}
+	if k.Degraded() {
+		t.Error("50ms RTT must not be degraded")
+	}
+
+	// RTT climbs: the smoothed value crosses the threshold after a few samples
+	for i := 0; i < 20 && !k.Degraded(); i++ {
+		now = now.Add(time.Second)
+		k.PingSent(now)
+		k.PongReceived(now.Add(2 * time.Second))
+	}
+	if !k.Degraded() || k.NextInterval() != time.Second {
+		t.Errorf("expected degraded with tight interval, got degraded=%v interval=%v", k.Degraded(), k.NextInterval())
+	}
+
+	if _, ok := k.PongReceived(now); ok {
+		t.Error("unsolicited pong must be ignored")
+	}
+}
+
+func TestKeepalive_MissedPongs(t *testing.T) {
+	k := NewKeepalive(time.Second, 30*time.Second, 0)
+	now := time.Unix(0, 0)
+
+	if missed := k.PingSent(now); missed != 0 {
+		t.Fatalf("first ping cannot be missed, got %d", missed)
+	}
+	if missed := k.PingSent(now.Add(time.Second)); missed != 1 || !k.Degraded() {
+		t.Fatalf("expected 1 missed and degraded, got %d %v", missed, k.Degraded())
+	}
+
+	// A late pong still recovers the connection
+	k.PongReceived(now.Add(1100 * time.Millisecond))
+	if k.Degraded() {
+		t.Error("expected recovery after pong (RTT criterion disabled)")
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package infra
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile attempts to acquire an exclusive, non-blocking lock on the given file.
+// It uses syscall.Flock for OS-level file locking.
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+// isLockContention reports whether a lockFile error means another process
+// holds the lock, as opposed to a failure to lock at all.
+func isLockContention(err error) bool {
+	return errors.Is(err, syscall.EWOULDBLOCK)
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package infra
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile attempts to acquire an exclusive, non-blocking lock on the given file.
+// It uses LockFileEx, which is released by the OS when the handle is closed.
+func lockFile(f *os.File) error {
+	ol := new(windows.Overlapped)
+	return windows.LockFileEx(windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+}
+
+// isLockContention reports whether a lockFile error means another process
+// holds the lock, as opposed to a failure to lock at all.
+func isLockContention(err error) bool {
+	return errors.Is(err, windows.ERROR_LOCK_VIOLATION)
+}
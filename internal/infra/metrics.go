@@ -1,6 +1,7 @@
 package infra
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -20,6 +21,40 @@ type Metrics struct {
 	// Gauges
 	activeConnections atomic.Int32
 	circuitOpen       atomic.Int32 // 1 = open, 0 = closed
+
+	// Labeled counters (by event source). Cold path: only touched on anomalies.
+	labelMu       sync.Mutex
+	seqGaps       map[string]uint64
+	seqGapEvents  map[string]uint64 // Sum of missing events per source
+	seqDuplicates map[string]uint64
+
+	// Dial attempts by host
+	dialAttempts  map[string]uint64
+	dialFailures  map[string]uint64
+	dialLatencyNs map[string]int64 // Sum over attempts
+
+	// TLS handshakes by host
+	tlsHandshakes map[string]uint64
+	tlsResumed    map[string]uint64
+	tlsFailures   map[string]uint64
+	tlsLatencyNs  map[string]int64 // Sum over successful handshakes
+
+	// WebSocket keepalive by gateway
+	wsRTTNs      map[string]int64 // Last measured RTT
+	wsSmoothedNs map[string]int64
+	wsDegraded   map[string]bool
+
+	// Gateway inbox backpressure by gateway
+	inboxDrops     map[string]uint64
+	inboxConflated map[string]uint64
+	inboxPressured map[string]bool
+
+	// Strategy time budget by strategy name
+	strategyOverruns map[string]uint64
+	strategySlow     map[string]bool
+
+	// Circuit breaker state by breaker name
+	circuitStates map[string]State
 }
 
 // GlobalMetrics is the singleton metrics instance.
@@ -42,6 +77,137 @@ func (m *Metrics) RecordOrderFilled() {
 	m.ordersFilled.Add(1)
 }
 
+// RecordSequenceGap records a tolerated sequence gap of size missing events from source.
+func (m *Metrics) RecordSequenceGap(source string, size uint64) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.seqGaps == nil {
+		m.seqGaps = make(map[string]uint64)
+		m.seqGapEvents = make(map[string]uint64)
+	}
+	m.seqGaps[source]++
+	m.seqGapEvents[source] += size
+}
+
+// RecordSequenceDuplicate records a duplicate/out-of-order event from source.
+func (m *Metrics) RecordSequenceDuplicate(source string) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.seqDuplicates == nil {
+		m.seqDuplicates = make(map[string]uint64)
+	}
+	m.seqDuplicates[source]++
+}
+
+// RecordDial records one TCP connect attempt to host and how long it took.
+func (m *Metrics) RecordDial(host string, elapsed time.Duration, ok bool) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.dialAttempts == nil {
+		m.dialAttempts = make(map[string]uint64)
+		m.dialFailures = make(map[string]uint64)
+		m.dialLatencyNs = make(map[string]int64)
+	}
+	m.dialAttempts[host]++
+	m.dialLatencyNs[host] += elapsed.Nanoseconds()
+	if !ok {
+		m.dialFailures[host]++
+	}
+}
+
+// RecordTLSHandshake records one TLS handshake with host, and whether it resumed a session.
+func (m *Metrics) RecordTLSHandshake(host string, elapsed time.Duration, ok, resumed bool) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.tlsHandshakes == nil {
+		m.tlsHandshakes = make(map[string]uint64)
+		m.tlsResumed = make(map[string]uint64)
+		m.tlsFailures = make(map[string]uint64)
+		m.tlsLatencyNs = make(map[string]int64)
+	}
+	if !ok {
+		m.tlsFailures[host]++
+		return
+	}
+	m.tlsHandshakes[host]++
+	m.tlsLatencyNs[host] += elapsed.Nanoseconds()
+	if resumed {
+		m.tlsResumed[host]++
+	}
+}
+
+// RecordRTT records a WebSocket ping round trip for gateway.
+func (m *Metrics) RecordRTT(gateway string, rtt, smoothed time.Duration) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.wsRTTNs == nil {
+		m.wsRTTNs = make(map[string]int64)
+		m.wsSmoothedNs = make(map[string]int64)
+	}
+	m.wsRTTNs[gateway] = rtt.Nanoseconds()
+	m.wsSmoothedNs[gateway] = smoothed.Nanoseconds()
+}
+
+// SetConnectionDegraded flags gateway's connection as degrading (slow or missing pongs).
+func (m *Metrics) SetConnectionDegraded(gateway string, degraded bool) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.wsDegraded == nil {
+		m.wsDegraded = make(map[string]bool)
+	}
+	m.wsDegraded[gateway] = degraded
+}
+
+// RecordInboxDrop records an event gateway's full inbox refused.
+func (m *Metrics) RecordInboxDrop(gateway string) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.inboxDrops == nil {
+		m.inboxDrops = make(map[string]uint64)
+	}
+	m.inboxDrops[gateway]++
+}
+
+// RecordInboxConflated records a pending ticker of gateway replaced by a newer one.
+func (m *Metrics) RecordInboxConflated(gateway string) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.inboxConflated == nil {
+		m.inboxConflated = make(map[string]uint64)
+	}
+	m.inboxConflated[gateway]++
+}
+
+// SetInboxPressured flags gateway's inbox as above its high watermark.
+func (m *Metrics) SetInboxPressured(gateway string, pressured bool) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.inboxPressured == nil {
+		m.inboxPressured = make(map[string]bool)
+	}
+	m.inboxPressured[gateway] = pressured
+}
+
+// RecordStrategyOverrun records one strategy call that exceeded its time budget.
+func (m *Metrics) RecordStrategyOverrun(strategy string, elapsed time.Duration) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.strategyOverruns == nil {
+		m.strategyOverruns = make(map[string]uint64)
+	}
+	m.strategyOverruns[strategy]++
+}
+
+// SetStrategySlow flags strategy as repeatedly over its time budget.
+func (m *Metrics) SetStrategySlow(strategy string, slow bool) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.strategySlow == nil {
+		m.strategySlow = make(map[string]bool)
+	}
+	m.strategySlow[strategy] = slow
+}
+
 // SetActiveConnections sets the current active connection count.
 func (m *Metrics) SetActiveConnections(count int32) {
 	m.activeConnections.Store(count)
@@ -66,6 +232,23 @@ func (m *Metrics) SetCircuitState(open bool) {
 	}
 }
 
+// SetCircuitBreakerState records the state of the named circuit breaker.
+// CircuitOpen reports whether any breaker is open.
+func (m *Metrics) SetCircuitBreakerState(name string, state State) {
+	m.labelMu.Lock()
+	defer m.labelMu.Unlock()
+	if m.circuitStates == nil {
+		m.circuitStates = make(map[string]State)
+	}
+	m.circuitStates[name] = state
+
+	open := false
+	for _, s := range m.circuitStates {
+		open = open || s == StateOpen
+	}
+	m.SetCircuitState(open)
+}
+
 // MetricsSnapshot is a point-in-time view of all metrics.
 type MetricsSnapshot struct {
 	EventsProcessed   uint64
@@ -75,6 +258,32 @@ type MetricsSnapshot struct {
 	ActiveConnections int32
 	CircuitOpen       bool
 	Timestamp         time.Time
+
+	SequenceGaps       map[string]uint64 // Gap occurrences by source
+	SequenceGapEvents  map[string]uint64 // Missing events by source
+	SequenceDuplicates map[string]uint64 // Duplicates by source
+
+	DialAttempts map[string]uint64 // Connect attempts by host
+	DialFailures map[string]uint64 // Failed attempts by host
+	DialAvgNs    map[string]int64  // Mean attempt duration by host
+
+	TLSHandshakes map[string]uint64 // Successful handshakes by host
+	TLSResumed    map[string]uint64 // Of which resumed a cached session
+	TLSFailures   map[string]uint64 // Failed handshakes by host
+	TLSAvgNs      map[string]int64  // Mean successful handshake duration by host
+
+	WSRTTNs         map[string]int64 // Last ping RTT by gateway
+	WSSmoothedRTTNs map[string]int64 // Smoothed ping RTT by gateway
+	WSDegraded      map[string]bool  // Gateways whose connection is degrading
+
+	InboxDrops     map[string]uint64 // Events refused by a full inbox by gateway
+	InboxConflated map[string]uint64 // Tickers coalesced into a newer one by gateway
+	InboxPressured map[string]bool   // Gateways whose inbox is above its high watermark
+
+	StrategyOverruns map[string]uint64 // Calls over the time budget by strategy
+	StrategySlow     map[string]bool   // Strategies repeatedly over budget
+
+	CircuitStates map[string]string // Breaker state (CLOSED/OPEN/HALF_OPEN) by name
 }
 
 // Snapshot returns current metrics as a snapshot.
@@ -85,15 +294,90 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 		avgLatency = m.latencySumNs.Load() / int64(count)
 	}
 
+	m.labelMu.Lock()
+	gaps := copyCounts(m.seqGaps)
+	gapEvents := copyCounts(m.seqGapEvents)
+	dups := copyCounts(m.seqDuplicates)
+	dialAttempts := copyCounts(m.dialAttempts)
+	dialFailures := copyCounts(m.dialFailures)
+	dialAvg := make(map[string]int64, len(m.dialAttempts))
+	for host, n := range m.dialAttempts {
+		dialAvg[host] = m.dialLatencyNs[host] / int64(n)
+	}
+	tlsHandshakes := copyCounts(m.tlsHandshakes)
+	tlsResumed := copyCounts(m.tlsResumed)
+	tlsFailures := copyCounts(m.tlsFailures)
+	tlsAvg := make(map[string]int64, len(m.tlsHandshakes))
+	for host, n := range m.tlsHandshakes {
+		tlsAvg[host] = m.tlsLatencyNs[host] / int64(n)
+	}
+	wsRTT := copyGauges(m.wsRTTNs)
+	wsSmoothed := copyGauges(m.wsSmoothedNs)
+	wsDegraded := make(map[string]bool, len(m.wsDegraded))
+	for k, v := range m.wsDegraded {
+		wsDegraded[k] = v
+	}
+	inboxDrops := copyCounts(m.inboxDrops)
+	inboxConflated := copyCounts(m.inboxConflated)
+	inboxPressured := make(map[string]bool, len(m.inboxPressured))
+	for k, v := range m.inboxPressured {
+		inboxPressured[k] = v
+	}
+	strategyOverruns := copyCounts(m.strategyOverruns)
+	strategySlow := make(map[string]bool, len(m.strategySlow))
+	for k, v := range m.strategySlow {
+		strategySlow[k] = v
+	}
+	circuitStates := make(map[string]string, len(m.circuitStates))
+	for k, v := range m.circuitStates {
+		circuitStates[k] = v.String()
+	}
+	m.labelMu.Unlock()
+
 	return MetricsSnapshot{
-		EventsProcessed:   m.eventsProcessed.Load(),
-		OrdersFilled:      m.ordersFilled.Load(),
-		ErrorsTotal:       m.errorsTotal.Load(),
-		AvgLatencyNs:      avgLatency,
-		ActiveConnections: m.activeConnections.Load(),
-		CircuitOpen:       m.circuitOpen.Load() == 1,
-		Timestamp:         time.Now(),
+		EventsProcessed:    m.eventsProcessed.Load(),
+		OrdersFilled:       m.ordersFilled.Load(),
+		ErrorsTotal:        m.errorsTotal.Load(),
+		AvgLatencyNs:       avgLatency,
+		ActiveConnections:  m.activeConnections.Load(),
+		CircuitOpen:        m.circuitOpen.Load() == 1,
+		Timestamp:          time.Now(),
+		SequenceGaps:       gaps,
+		SequenceGapEvents:  gapEvents,
+		SequenceDuplicates: dups,
+		DialAttempts:       dialAttempts,
+		DialFailures:       dialFailures,
+		DialAvgNs:          dialAvg,
+		TLSHandshakes:      tlsHandshakes,
+		TLSResumed:         tlsResumed,
+		TLSFailures:        tlsFailures,
+		TLSAvgNs:           tlsAvg,
+		WSRTTNs:            wsRTT,
+		WSSmoothedRTTNs:    wsSmoothed,
+		WSDegraded:         wsDegraded,
+		InboxDrops:         inboxDrops,
+		InboxConflated:     inboxConflated,
+		InboxPressured:     inboxPressured,
+		StrategyOverruns:   strategyOverruns,
+		StrategySlow:       strategySlow,
+		CircuitStates:      circuitStates,
+	}
+}
+
+func copyCounts(src map[string]uint64) map[string]uint64 {
+	dst := make(map[string]uint64, len(src))
+	for k, v := range src {
+		dst[k] = v
 	}
+	return dst
+}
+
+func copyGauges(src map[string]int64) map[string]int64 {
+	dst := make(map[string]int64, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
 }
 
 // Reset clears all metrics (for testing).
@@ -105,4 +389,26 @@ func (m *Metrics) Reset() {
 	m.latencyCount.Store(0)
 	m.activeConnections.Store(0)
 	m.circuitOpen.Store(0)
+
+	m.labelMu.Lock()
+	m.seqGaps = nil
+	m.seqGapEvents = nil
+	m.seqDuplicates = nil
+	m.dialAttempts = nil
+	m.dialFailures = nil
+	m.dialLatencyNs = nil
+	m.tlsHandshakes = nil
+	m.tlsResumed = nil
+	m.tlsFailures = nil
+	m.tlsLatencyNs = nil
+	m.wsRTTNs = nil
+	m.wsSmoothedNs = nil
+	m.wsDegraded = nil
+	m.inboxDrops = nil
+	m.inboxConflated = nil
+	m.inboxPressured = nil
+	m.strategyOverruns = nil
+	m.strategySlow = nil
+	m.circuitStates = nil
+	m.labelMu.Unlock()
 }
@@ -0,0 +1,320 @@
+// Package mqtt is a minimal MQTT 3.1.1 publisher: CONNECT, QoS 0 PUBLISH and
+// keepalive pings. It is enough to feed home dashboards and IoT displays through
+// any broker (Mosquitto, Home Assistant, EMQX) without a third-party client.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"crypto_go/internal/infra"
+)
+
+// Packet types (high nibble of the fixed header).
+const (
+	packetConnect    = 0x10
+	packetConnack    = 0x20
+	packetPublish    = 0x30
+	packetPingreq    = 0xC0
+	packetPingresp   = 0xD0
+	packetDisconnect = 0xE0
+)
+
+// maxRemainingLength is the protocol limit of one packet body (256 MiB).
+const maxRemainingLength = 268_435_455
+
+// ErrNotConnected is returned by Publish before Connect or after the connection dropped.
+var ErrNotConnected = errors.New("mqtt: not connected")
+
+// Config configures a Client. Zero values use the defaults.
+type Config struct {
+	Broker    string        // tcp://host:1883 or tls://host:8883 (also mqtt://, mqtts://, ssl://)
+	ClientID  string        // "" = "crypto_go"
+	Username  string        // Optional
+	Password  string        // Optional (sent only with Username)
+	KeepAlive time.Duration // 0 = 60s
+	Timeout   time.Duration // Dial and CONNACK timeout (0 = 10s)
+}
+
+// Client publishes QoS 0 messages over one broker connection. Safe for
+// concurrent use. It does not reconnect by itself: after an error, Close and
+// Connect again.
+type Client struct {
+	cfg Config
+
+	mu        sync.Mutex
+	conn      net.Conn
+	lastWrite time.Time
+	done      chan struct{} // Closed when the reader sees the connection end
+}
+
+// NewClient creates a client for cfg. Call Connect before Publish.
+func NewClient(cfg Config) *Client {
+	if cfg.ClientID == "" {
+		cfg.ClientID = "crypto_go"
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = 60 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &Client{cfg: cfg}
+}
+
+// Connect dials the broker and completes the CONNECT handshake (clean session).
+func (c *Client) Connect(ctx context.Context) error {
+	u, err := url.Parse(c.cfg.Broker)
+	if err != nil {
+		return fmt.Errorf("mqtt: invalid broker %q: %w", c.cfg.Broker, err)
+	}
+	secure := false
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "tls", "ssl", "mqtts":
+		secure = true
+	default:
+		return fmt.Errorf("mqtt: unsupported broker scheme %q", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		port := "1883"
+		if secure {
+			port = "8883"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	conn, err := infra.SharedDialer().DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("mqtt: dial %s: %w", host, err)
+	}
+	if secure {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("mqtt: tls handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	if _, err := conn.Write(encodeConnect(c.cfg)); err != nil {
+		conn.Close()
+		return fmt.Errorf("mqtt: connect: %w", err)
+	}
+	r := bufio.NewReader(conn)
+	typ, body, err := readPacket(r)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mqtt: connack: %w", err)
+	}
+	if typ != packetConnack || len(body) != 2 {
+		conn.Close()
+		return fmt.Errorf("mqtt: unexpected packet 0x%02x instead of CONNACK", typ)
+	}
+	if code := body[1]; code != 0 {
+		conn.Close()
+		return fmt.Errorf("mqtt: connection refused: %s", connackReason(code))
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	done := make(chan struct{})
+	c.mu.Lock()
+	c.conn, c.done, c.lastWrite = conn, done, time.Now()
+	c.mu.Unlock()
+
+	go c.readLoop(r, conn, done)
+	return nil
+}
+
+// readLoop drains broker packets (PINGRESP) and notices the connection ending.
+func (c *Client) readLoop(r *bufio.Reader, conn net.Conn, done chan struct{}) {
+	defer close(done)
+	for {
+		if _, _, err := readPacket(r); err != nil {
+			c.mu.Lock()
+			if c.conn == conn {
+				c.conn = nil
+			}
+			c.mu.Unlock()
+			conn.Close()
+			return
+		}
+	}
+}
+
+// Connected reports whether the connection is up.
+func (c *Client) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// Publish sends payload to topic with QoS 0 (fire and forget). retain asks the
+// broker to keep the message for future subscribers (last known value).
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	if topic == "" {
+		return errors.New("mqtt: empty topic")
+	}
+	flags := byte(0)
+	if retain {
+		flags = 0x01
+	}
+	body := make([]byte, 0, 2+len(topic)+len(payload))
+	body = appendString(body, topic)
+	body = append(body, payload...)
+	return c.write(packet(packetPublish|flags, body))
+}
+
+// Ping sends PINGREQ if nothing was written for half the keepalive, so the
+// broker does not drop an idle connection. Call it periodically.
+func (c *Client) Ping() error {
+	c.mu.Lock()
+	idle := time.Since(c.lastWrite)
+	c.mu.Unlock()
+	if idle < c.cfg.KeepAlive/2 {
+		return nil
+	}
+	return c.write([]byte{packetPingreq, 0})
+}
+
+func (c *Client) write(pkt []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return ErrNotConnected
+	}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.cfg.Timeout))
+	if _, err := c.conn.Write(pkt); err != nil {
+		c.conn.Close()
+		c.conn = nil
+		return fmt.Errorf("mqtt: write: %w", err)
+	}
+	c.lastWrite = time.Now()
+	return nil
+}
+
+// Close sends DISCONNECT and closes the connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	conn, done := c.conn, c.done
+	c.conn = nil
+	c.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, _ = conn.Write([]byte{packetDisconnect, 0})
+	err := conn.Close()
+	<-done
+	return err
+}
+
+// encodeConnect builds a CONNECT packet (protocol level 4 = 3.1.1, clean session).
+func encodeConnect(cfg Config) []byte {
+	flags := byte(0x02) // Clean session
+	if cfg.Username != "" {
+		flags |= 0x80
+		if cfg.Password != "" {
+			flags |= 0x40
+		}
+	}
+	keepAlive := uint16(min(cfg.KeepAlive/time.Second, 0xFFFF))
+
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags, byte(keepAlive>>8), byte(keepAlive))
+	body = appendString(body, cfg.ClientID)
+	if cfg.Username != "" {
+		body = appendString(body, cfg.Username)
+		if cfg.Password != "" {
+			body = appendString(body, cfg.Password)
+		}
+	}
+	return packet(packetConnect, body)
+}
+
+// packet prefixes body with the fixed header.
+func packet(header byte, body []byte) []byte {
+	out := append([]byte{header}, encodeLength(len(body))...)
+	return append(out, body...)
+}
+
+// encodeLength encodes the remaining length as a base-128 varint.
+func encodeLength(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			return out
+		}
+	}
+}
+
+func appendString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
+
+// readPacket reads one packet, returning its type nibble and body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, mult := 0, 1
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n += int(b&0x7F) * mult
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		mult *= 128
+	}
+	if n > maxRemainingLength {
+		return 0, nil, errors.New("packet too large")
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xF0, body, nil
+}
+
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	}
+	return fmt.Sprintf("code %d", code)
+}
@@ -0,0 +1,97 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBroker accepts one connection, answers CONNACK with code and forwards
+// every later packet to the returned channel.
+func fakeBroker(t *testing.T, code byte) (string, <-chan [2][]byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	packets := make(chan [2][]byte, 8)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			typ, body, err := readPacket(r)
+			if err != nil {
+				close(packets)
+				return
+			}
+			packets <- [2][]byte{{typ}, body}
+			if typ == packetConnect {
+				_, _ = conn.Write([]byte{packetConnack, 2, 0, code})
+			}
+		}
+	}()
+	return "tcp://" + ln.Addr().String(), packets
+}
+
+func TestClient_ConnectAndPublish(t *testing.T) {
+	broker, packets := fakeBroker(t, 0)
+	c := NewClient(Config{Broker: broker, ClientID: "dash", Username: "u", Password: "p", Timeout: time.Second})
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	connect := <-packets
+	body := connect[1]
+	if connect[0][0] != packetConnect || string(body[2:6]) != "MQTT" || body[6] != 4 || body[7] != 0xC2 {
+		t.Fatalf("unexpected CONNECT header: %v", body[:10])
+	}
+	if !strings.HasSuffix(string(body), "\x00\x04dash\x00\x01u\x00\x01p") {
+		t.Errorf("unexpected CONNECT payload: %q", body[10:])
+	}
+
+	if err := c.Publish("cryptogo/UPBIT/BTC/price", []byte("95000000.000000"), true); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	pub := <-packets
+	if pub[0][0] != packetPublish || string(pub[1]) != "\x00\x18cryptogo/UPBIT/BTC/price95000000.000000" {
+		t.Errorf("unexpected PUBLISH: %q", pub[1])
+	}
+
+	if err := c.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if p := <-packets; p[0][0] != packetDisconnect {
+		t.Errorf("expected DISCONNECT, got 0x%02x", p[0][0])
+	}
+	if c.Connected() || c.Publish("t", nil, false) != ErrNotConnected {
+		t.Error("publish after Close must fail with ErrNotConnected")
+	}
+}
+
+func TestClient_Refused(t *testing.T) {
+	broker, _ := fakeBroker(t, 5)
+	c := NewClient(Config{Broker: broker, Timeout: time.Second})
+	if err := c.Connect(context.Background()); err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("expected refusal, got %v", err)
+	}
+	if err := NewClient(Config{Broker: "http://localhost"}).Connect(context.Background()); err == nil {
+		t.Error("expected error for unsupported scheme")
+	}
+}
+
+func TestEncodeLength(t *testing.T) {
+	for n, want := range map[int]string{0: "\x00", 127: "\x7f", 128: "\x80\x01", 16_383: "\xff\x7f", 2_097_152: "\x80\x80\x80\x01"} {
+		if got := string(encodeLength(n)); got != want {
+			t.Errorf("encodeLength(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
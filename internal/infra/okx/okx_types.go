@@ -0,0 +1,64 @@
+package okx
+
+import "strings"
+
+// DefaultWSURL is OKX's public (login-free) WebSocket endpoint.
+const DefaultWSURL = "wss://ws.okx.com:8443/ws/v5/public"
+
+// Unified symbols are quoted in USDT on OKX, like Bitget.
+const (
+	quoteCurrency = "USDT"
+	swapSuffix    = "-SWAP"
+)
+
+// SpotInstID maps a unified symbol to the OKX spot instId ("BTC" -> "BTC-USDT").
+func SpotInstID(symbol string) string {
+	return symbol + "-" + quoteCurrency
+}
+
+// SwapInstID maps a unified symbol to the OKX perpetual instId ("BTC" -> "BTC-USDT-SWAP").
+func SwapInstID(symbol string) string {
+	return SpotInstID(symbol) + swapSuffix
+}
+
+// UnifiedSymbol maps a USDT spot or swap instId back to the unified symbol.
+// Returns "" for other quotes or instrument types (futures, options).
+func UnifiedSymbol(instID string) string {
+	base, rest, ok := strings.Cut(instID, "-")
+	if !ok || base == "" {
+		return ""
+	}
+	if rest == quoteCurrency || rest == quoteCurrency+swapSuffix {
+		return base
+	}
+	return ""
+}
+
+type subscribeRequest struct {
+	Op   string         `json:"op"`
+	Args []subscribeArg `json:"args"`
+}
+
+type subscribeArg struct {
+	Channel string `json:"channel"`
+	InstID  string `json:"instId"`
+}
+
+// tickerResponse is a push on the "tickers" channel. Subscription acks and
+// errors carry "event" instead of "data".
+type tickerResponse struct {
+	Event string       `json:"event"`
+	Code  string       `json:"code"`
+	Msg   string       `json:"msg"`
+	Arg   subscribeArg `json:"arg"`
+	Data  []tickerData `json:"data"`
+}
+
+type tickerData struct {
+	InstType  string `json:"instType"`
+	InstID    string `json:"instId"`
+	Last      string `json:"last"`
+	Vol24h    string `json:"vol24h"`    // Spot: base currency; Swap: contracts
+	VolCcy24h string `json:"volCcy24h"` // Spot: quote currency; Swap: base currency
+	Ts        string `json:"ts"`        // Milliseconds
+}
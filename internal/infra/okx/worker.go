@@ -0,0 +1,158 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+
+	"github.com/gorilla/websocket"
+)
+
+// pingInterval stays under OKX's 30 second idle timeout.
+const pingInterval = 20 * time.Second
+
+// market describes one OKX instrument type.
+type market struct {
+	id       string              // Gateway / event Exchange name
+	instType string              // OKX instType
+	instID   func(string) string // Unified symbol -> instId
+	volume   func(tickerData) string
+}
+
+var (
+	spotMarket = market{
+		id:       "OKX_SPOT",
+		instType: "SPOT",
+		instID:   SpotInstID,
+		volume:   func(d tickerData) string { return d.Vol24h },
+	}
+	// Swap vol24h counts contracts; volCcy24h is the base currency volume we report elsewhere
+	swapMarket = market{
+		id:       "OKX_SWAP",
+		instType: "SWAP",
+		instID:   SwapInstID,
+		volume:   func(d tickerData) string { return d.VolCcy24h },
+	}
+)
+
+// Worker streams OKX public tickers for one instrument type using BaseWSWorker.
+type Worker struct {
+	*infra.BaseWSWorker
+	market  market
+	url     string
+	symbols map[string]string // instId -> unified symbol
+	inbox   infra.Outbox
+	seq     *uint64
+}
+
+// NewSpotWorker creates a worker for USDT spot tickers of the unified symbols.
+func NewSpotWorker(url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	return newWorker(spotMarket, url, symbols, inbox, seq)
+}
+
+// NewSwapWorker creates a worker for USDT perpetual swap tickers of the unified symbols.
+func NewSwapWorker(url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	return newWorker(swapMarket, url, symbols, inbox, seq)
+}
+
+func newWorker(m market, url string, symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
+	if url == "" {
+		url = DefaultWSURL
+	}
+	w := &Worker{
+		market:  m,
+		url:     url,
+		symbols: make(map[string]string, len(symbols)),
+		inbox:   infra.NewOutbox(inbox),
+		seq:     seq,
+	}
+	for _, s := range symbols {
+		w.symbols[m.instID(s)] = s
+	}
+	w.BaseWSWorker = infra.NewBaseWSWorker(w)
+	w.Outbox = &w.inbox
+	w.BaseWSWorker.PingInterval = pingInterval
+	return w
+}
+
+func (w *Worker) ID() string     { return w.market.id }
+func (w *Worker) GetURL() string { return w.url }
+
+func (w *Worker) Connect(ctx context.Context) error {
+	w.BaseWSWorker.Start(ctx)
+	return nil
+}
+
+func (w *Worker) Disconnect() {
+	w.BaseWSWorker.Stop()
+}
+
+func (w *Worker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	w.inbox.Reconnected()
+	args := make([]subscribeArg, 0, len(w.symbols))
+	for instID := range w.symbols {
+		args = append(args, subscribeArg{Channel: "tickers", InstID: instID})
+	}
+	b, err := json.Marshal(subscribeRequest{Op: "subscribe", Args: args})
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscribe request: %w", err)
+	}
+	return w.BaseWSWorker.Write(websocket.TextMessage, b)
+}
+
+func (w *Worker) OnMessage(ctx context.Context, msg []byte) {
+	if string(msg) == "pong" {
+		w.BaseWSWorker.NotifyPong()
+		return
+	}
+
+	var resp tickerResponse
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+	if resp.Event == "error" {
+		slog.Warn("OKX subscription error", slog.String("gateway", w.market.id), slog.String("code", resp.Code), slog.String("msg", resp.Msg))
+		return
+	}
+	if resp.Arg.Channel != "tickers" || len(resp.Data) == 0 {
+		return
+	}
+
+	for _, data := range resp.Data {
+		if data.InstType != w.market.instType {
+			continue
+		}
+		symbol, ok := w.symbols[data.InstID]
+		if !ok {
+			continue
+		}
+		ms, err := strconv.ParseInt(data.Ts, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		ev := event.AcquireMarketUpdateEvent()
+		ev.Seq = quant.NextSeq(w.seq)
+		ev.Ts = quant.TimeStamp(ms * 1000)
+		ev.Symbol = symbol
+		ev.PriceMicros = quant.ToPriceMicrosStr(data.Last)
+		ev.QtySats = quant.ToQtySatsStr(w.market.volume(data))
+		ev.Exchange = w.market.id
+
+		if !w.inbox.Send(ev) {
+			event.ReleaseMarketUpdateEvent(ev)
+		}
+	}
+}
+
+// OnPing sends OKX's text keepalive; the server answers "pong".
+func (w *Worker) OnPing(ctx context.Context, conn *websocket.Conn) error {
+	return w.BaseWSWorker.Write(websocket.TextMessage, []byte("ping"))
+}
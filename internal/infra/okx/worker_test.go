@@ -0,0 +1,101 @@
+package okx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto_go/internal/event"
+)
+
+func TestInstIDMapping(t *testing.T) {
+	if SpotInstID("BTC") != "BTC-USDT" || SwapInstID("BTC") != "BTC-USDT-SWAP" {
+		t.Errorf("unexpected instIds %s, %s", SpotInstID("BTC"), SwapInstID("BTC"))
+	}
+	cases := map[string]string{
+		"BTC-USDT":        "BTC",
+		"ETH-USDT-SWAP":   "ETH",
+		"BTC-USDC":        "",
+		"BTC-USD-SWAP":    "",
+		"BTC-USDT-250328": "",
+		"garbage":         "",
+	}
+	for instID, want := range cases {
+		if got := UnifiedSymbol(instID); got != want {
+			t.Errorf("UnifiedSymbol(%q) = %q, want %q", instID, got, want)
+		}
+	}
+}
+
+func TestWorker_TickerParsing(t *testing.T) {
+	tests := []struct {
+		name     string
+		worker   func(inbox chan event.Event, seq *uint64) *Worker
+		msg      string
+		exchange string
+		qty      int64
+	}{
+		{
+			name: "spot",
+			worker: func(inbox chan event.Event, seq *uint64) *Worker {
+				return NewSpotWorker("", []string{"BTC"}, inbox, seq)
+			},
+			msg:      `{"arg":{"channel":"tickers","instId":"BTC-USDT"},"data":[{"instType":"SPOT","instId":"BTC-USDT","last":"92000.5","vol24h":"1234.5","volCcy24h":"113574000","ts":"1704067200000"}]}`,
+			exchange: "OKX_SPOT",
+			qty:      123_450_000_000,
+		},
+		{
+			name: "swap reports base currency volume",
+			worker: func(inbox chan event.Event, seq *uint64) *Worker {
+				return NewSwapWorker("", []string{"BTC"}, inbox, seq)
+			},
+			msg:      `{"arg":{"channel":"tickers","instId":"BTC-USDT-SWAP"},"data":[{"instType":"SWAP","instId":"BTC-USDT-SWAP","last":"92100","vol24h":"5000000","volCcy24h":"50","ts":"1704067200000"}]}`,
+			exchange: "OKX_SWAP",
+			qty:      5_000_000_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inbox := make(chan event.Event, 1)
+			var seq uint64
+			w := tt.worker(inbox, &seq)
+			w.OnMessage(context.Background(), []byte(tt.msg))
+
+			select {
+			case ev := <-inbox:
+				m := ev.(*event.MarketUpdateEvent)
+				if m.Symbol != "BTC" || m.Exchange != tt.exchange || m.Seq != 1 {
+					t.Errorf("unexpected event %+v", m)
+				}
+				if m.PriceMicros == 0 || int64(m.QtySats) != tt.qty {
+					t.Errorf("price %d, qty %d (want %d)", m.PriceMicros, m.QtySats, tt.qty)
+				}
+				if m.Ts != 1704067200000*1000 {
+					t.Errorf("ts should be micros, got %d", m.Ts)
+				}
+			case <-time.After(100 * time.Millisecond):
+				t.Fatal("no event received")
+			}
+		})
+	}
+}
+
+func TestWorker_IgnoresOtherMessages(t *testing.T) {
+	inbox := make(chan event.Event, 4)
+	var seq uint64
+	w := NewSpotWorker("", []string{"BTC"}, inbox, &seq)
+
+	for _, msg := range []string{
+		`{"event":"subscribe","arg":{"channel":"tickers","instId":"BTC-USDT"}}`,
+		`{"event":"error","code":"60018","msg":"doesn't exist"}`,
+		`{"arg":{"channel":"tickers","instId":"ETH-USDT"},"data":[{"instType":"SPOT","instId":"ETH-USDT","last":"1","ts":"1"}]}`,
+		`{"arg":{"channel":"tickers","instId":"BTC-USDT-SWAP"},"data":[{"instType":"SWAP","instId":"BTC-USDT-SWAP","last":"1","ts":"1"}]}`,
+		`pong`,
+	} {
+		w.OnMessage(context.Background(), []byte(msg))
+	}
+	if len(inbox) != 0 {
+		t.Errorf("expected no events, got %d", len(inbox))
+	}
+}
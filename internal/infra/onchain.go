@@ -0,0 +1,256 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+// OnChainSource is the event source (and seq owner) of on-chain context metrics.
+const OnChainSource = "ONCHAIN"
+
+// Default providers: DefiLlama's free, keyless APIs.
+const (
+	DefaultStablecoinURL = "https://stablecoins.llama.fi/stablecoincharts/all"
+	DefaultExchangeURL   = "https://api.llama.fi/protocol/" // + exchange slug
+)
+
+// OnChainConfig configures an OnChainClient. Zero values use the defaults.
+type OnChainConfig struct {
+	StablecoinURL string            // Daily total stablecoin supply
+	ExchangeURL   string            // Prefix of per-exchange reserve history
+	Exchanges     map[string]string // Exchange name -> provider slug (e.g. BINANCE -> binance-cex)
+	PollInterval  time.Duration     // 0 = once a day
+}
+
+// dailyPoint is one day of a provider time series.
+type dailyPoint struct {
+	day   quant.TimeStamp
+	value int64 // USD micros
+}
+
+// stablecoinChartResponse is DefiLlama's /stablecoincharts/all format.
+type stablecoinChartResponse []struct {
+	Date                json.Number `json:"date"` // Unix seconds (sent as a string)
+	TotalCirculatingUSD struct {
+		PeggedUSD json.Number `json:"peggedUSD"`
+	} `json:"totalCirculatingUSD"`
+}
+
+// exchangeReserveResponse is the part of DefiLlama's /protocol/{slug} format
+// used here (CEX entries report on-chain reserves as TVL).
+type exchangeReserveResponse struct {
+	TVL []struct {
+		Date              json.Number `json:"date"` // Unix seconds
+		TotalLiquidityUSD json.Number `json:"totalLiquidityUSD"`
+	} `json:"tvl"`
+}
+
+// OnChainClient polls on-chain indicators (stablecoin supply and flow, exchange
+// reserves and netflow) and emits them as ContextEvents. Readings are daily, so
+// each series is emitted only when the provider reports a new day; the WAL is
+// the local store and the Sequencer keeps the latest reading.
+//
+// Exchange netflow is approximated as the day-over-day change of the reported
+// reserve, so it includes price moves of the held assets, not only transfers.
+type OnChainClient struct {
+	inbox      chan<- event.Event
+	nextSeq    *uint64
+	cfg        OnChainConfig
+	httpClient *http.Client
+	cancel     context.CancelFunc
+	emitted    map[string]quant.TimeStamp // Last emitted day per metric/subject (poller goroutine only)
+}
+
+// NewOnChainClient creates a new on-chain indicator client.
+func NewOnChainClient(inbox chan<- event.Event, seq *uint64, cfg OnChainConfig) *OnChainClient {
+	if cfg.StablecoinURL == "" {
+		cfg.StablecoinURL = DefaultStablecoinURL
+	}
+	if cfg.ExchangeURL == "" {
+		cfg.ExchangeURL = DefaultExchangeURL
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 24 * time.Hour
+	}
+	return &OnChainClient{
+		inbox:      inbox,
+		nextSeq:    seq,
+		cfg:        cfg,
+		httpClient: NewHTTPClient(30 * time.Second),
+		emitted:    make(map[string]quant.TimeStamp),
+	}
+}
+
+// Start polls once immediately, then every PollInterval.
+func (c *OnChainClient) Start(ctx context.Context) error {
+	ctx, c.cancel = context.WithCancel(ctx)
+	go func() {
+		c.poll(ctx)
+		ticker := time.NewTicker(c.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.poll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop cancels the polling.
+func (c *OnChainClient) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// poll fetches every configured series; a failing provider does not block the others.
+func (c *OnChainClient) poll(ctx context.Context) {
+	if points, err := c.fetchStablecoins(ctx); err != nil {
+		slog.Warn("ONCHAIN_FETCH_FAILED", slog.String("series", "stablecoins"), slog.Any("error", err))
+	} else {
+		c.emitSeries(ctx, points, domain.MetricStablecoinSupply, domain.MetricStablecoinNetflow, "")
+	}
+
+	names := make([]string, 0, len(c.cfg.Exchanges))
+	for name := range c.cfg.Exchanges {
+		names = append(names, name)
+	}
+	sort.Strings(names) // Deterministic emission order
+
+	for _, name := range names {
+		points, err := c.fetchExchange(ctx, c.cfg.Exchanges[name])
+		if err != nil {
+			slog.Warn("ONCHAIN_FETCH_FAILED", slog.String("series", name), slog.Any("error", err))
+			continue
+		}
+		c.emitSeries(ctx, points, domain.MetricExchangeReserve, domain.MetricExchangeNetflow, strings.ToUpper(name))
+	}
+}
+
+func (c *OnChainClient) fetchStablecoins(ctx context.Context) ([]dailyPoint, error) {
+	var resp stablecoinChartResponse
+	if err := c.getJSON(ctx, c.cfg.StablecoinURL, &resp); err != nil {
+		return nil, err
+	}
+	points := make([]dailyPoint, 0, len(resp))
+	for _, r := range resp {
+		p, err := newDailyPoint(r.Date, r.TotalCirculatingUSD.PeggedUSD)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func (c *OnChainClient) fetchExchange(ctx context.Context, slug string) ([]dailyPoint, error) {
+	var resp exchangeReserveResponse
+	if err := c.getJSON(ctx, c.cfg.ExchangeURL+slug, &resp); err != nil {
+		return nil, err
+	}
+	points := make([]dailyPoint, 0, len(resp.TVL))
+	for _, r := range resp.TVL {
+		p, err := newDailyPoint(r.Date, r.TotalLiquidityUSD)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func (c *OnChainClient) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", GetUserAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// emitSeries emits the latest level and its change from the previous day, once
+// per new day. Points may arrive in any order.
+func (c *OnChainClient) emitSeries(ctx context.Context, points []dailyPoint, levelMetric, flowMetric, subject string) {
+	if len(points) == 0 {
+		return
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].day < points[j].day })
+	last := points[len(points)-1]
+
+	c.emit(ctx, levelMetric, subject, last)
+	if len(points) >= 2 {
+		prev := points[len(points)-2]
+		c.emit(ctx, flowMetric, subject, dailyPoint{day: last.day, value: last.value - prev.value})
+	}
+}
+
+func (c *OnChainClient) emit(ctx context.Context, metric, subject string, p dailyPoint) {
+	key := metric + "/" + subject
+	if p.day <= c.emitted[key] {
+		return // Already emitted this day
+	}
+
+	ev := &event.ContextEvent{Source: OnChainSource, Metric: metric, Subject: subject, ValueMicros: p.value}
+	ev.Seq = quant.NextSeq(c.nextSeq)
+	ev.Ts = p.day
+
+	// Daily data is not dropped on a full inbox: wait for room
+	select {
+	case c.inbox <- ev:
+		c.emitted[key] = p.day
+	case <-ctx.Done():
+	}
+}
+
+// newDailyPoint parses a provider date (Unix seconds) and USD value.
+func newDailyPoint(date, usd json.Number) (dailyPoint, error) {
+	sec, err := date.Int64()
+	if err != nil {
+		return dailyPoint{}, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	value, err := parseUSDMicros(usd)
+	if err != nil {
+		return dailyPoint{}, err
+	}
+	return dailyPoint{day: quant.TimeStamp(sec * 1_000_000), value: value}, nil
+}
+
+// parseUSDMicros converts a JSON number (possibly in exponent form, as large
+// aggregates often are) to micros exactly, truncating below 1e-6.
+func parseUSDMicros(n json.Number) (int64, error) {
+	r, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return 0, fmt.Errorf("invalid USD value %q", n)
+	}
+	r.Mul(r, big.NewRat(quant.PriceScale, 1))
+	micros := new(big.Int).Quo(r.Num(), r.Denom())
+	if !micros.IsInt64() {
+		return 0, fmt.Errorf("USD value %q overflows int64 micros", n)
+	}
+	return micros.Int64(), nil
+}
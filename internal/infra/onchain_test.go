@@ -0,0 +1,116 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+)
+
+func TestOnChainClient_Poll(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stablecoins", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"date":"1700006400","totalCirculatingUSD":{"peggedUSD":1.25e11}},
+			{"date":"1699920000","totalCirculatingUSD":{"peggedUSD":124900000000.5}}
+		]`))
+	})
+	mux.HandleFunc("/protocol/binance-cex", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Binance CEX","tvl":[
+			{"date":1699920000,"totalLiquidityUSD":100000000000},
+			{"date":1700006400,"totalLiquidityUSD":99500000000}
+		]}`))
+	})
+	mux.HandleFunc("/protocol/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	inbox := make(chan event.Event, 16)
+	client := NewOnChainClient(inbox, new(uint64), OnChainConfig{
+		StablecoinURL: server.URL + "/stablecoins",
+		ExchangeURL:   server.URL + "/protocol/",
+		Exchanges:     map[string]string{"binance": "binance-cex", "broken": "broken"},
+	})
+
+	client.poll(context.Background())
+	got := drainContext(inbox)
+
+	const lastDay = 1700006400 * 1_000_000
+	want := []event.ContextEvent{
+		{Source: OnChainSource, Metric: domain.MetricStablecoinSupply, ValueMicros: 125_000_000_000_000_000},
+		{Source: OnChainSource, Metric: domain.MetricStablecoinNetflow, ValueMicros: 99_999_999_500_000},
+		{Source: OnChainSource, Metric: domain.MetricExchangeReserve, Subject: "BINANCE", ValueMicros: 99_500_000_000_000_000},
+		{Source: OnChainSource, Metric: domain.MetricExchangeNetflow, Subject: "BINANCE", ValueMicros: -500_000_000_000_000},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events (broken provider skipped), got %+v", len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Source != w.Source || g.Metric != w.Metric || g.Subject != w.Subject || g.ValueMicros != w.ValueMicros {
+			t.Errorf("event %d = %+v, want %+v", i, g, w)
+		}
+		if g.Ts != lastDay {
+			t.Errorf("event %d Ts = %d, want the reported day %d", i, g.Ts, int64(lastDay))
+		}
+		if g.Seq != uint64(i+1) {
+			t.Errorf("event %d Seq = %d", i, g.Seq)
+		}
+	}
+
+	// Same day re-polled: nothing new
+	client.poll(context.Background())
+	if again := drainContext(inbox); len(again) != 0 {
+		t.Errorf("re-poll of the same day emitted %+v", again)
+	}
+}
+
+func TestOnChainClient_Defaults(t *testing.T) {
+	client := NewOnChainClient(make(chan event.Event), new(uint64), OnChainConfig{})
+	if client.cfg.StablecoinURL != DefaultStablecoinURL || client.cfg.ExchangeURL != DefaultExchangeURL {
+		t.Errorf("unexpected default URLs: %+v", client.cfg)
+	}
+	if client.cfg.PollInterval != 24*time.Hour {
+		t.Errorf("default poll interval = %v, want 24h", client.cfg.PollInterval)
+	}
+}
+
+func TestParseUSDMicros(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"1.5", 1_500_000},
+		{"1.25e11", 125_000_000_000_000_000},
+		{"-3.0000009", -3_000_000},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseUSDMicros(json.Number(tt.in))
+		if err != nil || got != tt.want {
+			t.Errorf("parseUSDMicros(%s) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseUSDMicros("1e20"); err == nil {
+		t.Error("expected overflow error")
+	}
+}
+
+func drainContext(inbox chan event.Event) []event.ContextEvent {
+	var out []event.ContextEvent
+	for {
+		select {
+		case ev := <-inbox:
+			out = append(out, *ev.(*event.ContextEvent))
+		default:
+			return out
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package infra
+
+import (
+	"crypto_go/internal/event"
+	"sync/atomic"
+)
+
+// EventLane is a sequencer inbox of a single producer (engine.RingInbox).
+type EventLane interface {
+	Send(ev event.Event) bool
+}
+
+// Outbox is where a market data worker publishes events: the sequencer's
+// shared inbox channel, or a lock-free lane of its own once one is attached.
+// Sends never block; a full inbox refuses the event (see SetBackpressure).
+// Every event is tagged with the connection incarnation it was sent under.
+type Outbox struct {
+	ch   chan<- event.Event
+	lane EventLane
+	bp   *backpressure // nil = refused events are simply dropped
+	inc  atomic.Uint32 // Connection incarnation (0 = never connected)
+}
+
+// NewOutbox publishes to the shared inbox channel ch.
+func NewOutbox(ch chan<- event.Event) Outbox {
+	return Outbox{ch: ch}
+}
+
+// Attach routes later sends to lane instead of the channel. The worker's read
+// loop becomes the lane's only producer. Must be called before the worker starts.
+func (o *Outbox) Attach(lane EventLane) {
+	o.lane = lane
+}
+
+// Reconnected starts the next connection incarnation and returns it. Workers
+// call it from OnConnect, so events read from one socket share a number and a
+// reconnect shows in the WAL (BaseEvent.Incarnation).
+func (o *Outbox) Reconnected() uint32 {
+	return o.inc.Add(1)
+}
+
+// Incarnation returns the current connection incarnation (thread-safe).
+func (o *Outbox) Incarnation() uint32 {
+	return o.inc.Load()
+}
+
+// Send publishes ev and reports whether the inbox accepted it. With
+// conflation, a refused ticker is kept by the outbox and Send returns true:
+// the caller no longer owns it either way. ev is tagged with the current
+// incarnation first; a conflated ticker keeps the one it was read under.
+func (o *Outbox) Send(ev event.Event) bool {
+	if e, ok := ev.(interface{ SetIncarnation(uint32) }); ok {
+		e.SetIncarnation(o.inc.Load())
+	}
+	if o.bp != nil {
+		return o.bp.send(o, ev)
+	}
+	return o.push(ev)
+}
+
+// push hands ev to the inbox without blocking.
+func (o *Outbox) push(ev event.Event) bool {
+	if o.lane != nil {
+		return o.lane.Send(ev)
+	}
+	select {
+	case o.ch <- ev:
+		return true
+	default:
+		return false
+	}
+}
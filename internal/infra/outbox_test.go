@@ -0,0 +1,48 @@
+package infra
+
+import (
+	"crypto_go/internal/event"
+	"testing"
+)
+
+type laneFunc func(event.Event) bool
+
+func (f laneFunc) Send(ev event.Event) bool { return f(ev) }
+
+func TestOutbox_ChannelAndLane(t *testing.T) {
+	ch := make(chan event.Event, 1)
+	out := NewOutbox(ch)
+	if !out.Send(&event.MarketUpdateEvent{}) {
+		t.Fatal("channel with room refused the event")
+	}
+	if out.Send(&event.MarketUpdateEvent{}) {
+		t.Error("a full channel must refuse the event without blocking")
+	}
+
+	var got []event.Event
+	out.Attach(laneFunc(func(ev event.Event) bool {
+		got = append(got, ev)
+		return true
+	}))
+	if !out.Send(&event.TradeEvent{}) || len(got) != 1 || len(ch) != 1 {
+		t.Errorf("an attached lane should take every send, got lane %d, channel %d", len(got), len(ch))
+	}
+}
+
+func TestOutbox_Incarnation(t *testing.T) {
+	ch := make(chan event.Event, 4)
+	out := NewOutbox(ch)
+	out.Send(&event.MarketUpdateEvent{})
+	if inc := out.Reconnected(); inc != 1 {
+		t.Fatalf("first connection = %d; want 1", inc)
+	}
+	out.Send(&event.TradeEvent{})
+	out.Reconnected()
+	out.Send(&event.MarketUpdateEvent{})
+
+	for i, want := range []uint32{0, 1, 2} {
+		if got := (<-ch).GetIncarnation(); got != want {
+			t.Errorf("event %d: incarnation %d; want %d", i, got, want)
+		}
+	}
+}
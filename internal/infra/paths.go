@@ -5,7 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
-	"syscall"
 )
 
 const (
@@ -93,12 +92,6 @@ func CreateLockFile(workDir string) (func(), error) {
 	return closer, nil
 }
 
-// lockFile attempts to acquire an exclusive, non-blocking lock on the given file.
-// It uses syscall.Flock for OS-level file locking.
-func lockFile(f *os.File) error {
-	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
-}
-
 // ResolveConfigPath attempts to find the config.yaml.
 // Priority: 1. Current Dir, 2. OS Config Dir
 func ResolveConfigPath() string {
@@ -119,3 +119,30 @@ func initBitgetLimiters() {
 	bitgetAccountLimiter = NewRateLimiter(5, 10) // 10 req/s, burst 5
 	bitgetMarketLimiter = NewRateLimiter(10, 20) // 20 req/s, burst 10
 }
+
+// Upbit limits: 8 requests/second for order placement/cancellation,
+// 30 requests/second for other Exchange API endpoints.
+var (
+	upbitOrderLimiter   *RateLimiter
+	upbitAccountLimiter *RateLimiter
+	upbitLimiterOnce    sync.Once
+)
+
+// GetUpbitOrderLimiter returns the rate limiter for order endpoints.
+// Limit: 8 requests/second with burst of 4.
+func GetUpbitOrderLimiter() *RateLimiter {
+	upbitLimiterOnce.Do(initUpbitLimiters)
+	return upbitOrderLimiter
+}
+
+// GetUpbitAccountLimiter returns the rate limiter for account endpoints.
+// Limit: 10 requests/second with burst of 5.
+func GetUpbitAccountLimiter() *RateLimiter {
+	upbitLimiterOnce.Do(initUpbitLimiters)
+	return upbitAccountLimiter
+}
+
+func initUpbitLimiters() {
+	upbitOrderLimiter = NewRateLimiter(4, 8)    // 8 req/s, burst 4
+	upbitAccountLimiter = NewRateLimiter(5, 10) // 10 req/s, burst 5 (well under the 30 req/s cap)
+}
@@ -0,0 +1,97 @@
+package infra
+
+import (
+	"crypto_go/internal/risk"
+	"crypto_go/pkg/quant"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// RiskKindDaily is the kind of the daily risk report record.
+const RiskKindDaily = "RISK_DAILY"
+
+// RiskRecord is one line of the risk report (JSONL): the portfolio risk at
+// the end of a UTC day.
+type RiskRecord struct {
+	Kind     string `json:"kind"`
+	Day      string `json:"day"` // YYYY-MM-DD (UTC) that ended
+	WallTime string `json:"wall_time"`
+	risk.Report
+}
+
+// RiskSource provides the portfolio risk (engine.Sequencer).
+type RiskSource interface {
+	GetRiskReport() (risk.Report, bool)
+}
+
+// RiskReport appends the exposure per asset class and the VaR per quote
+// currency of the open positions to a JSONL file at every UTC day boundary.
+type RiskReport struct {
+	f   *os.File
+	src RiskSource
+	now func() time.Time
+}
+
+// NewRiskReport opens (append mode) the report at path.
+func NewRiskReport(path string, src RiskSource) (*RiskReport, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open risk report: %w", err)
+	}
+	return &RiskReport{f: f, src: src, now: time.Now}, nil
+}
+
+// Close closes the file.
+func (r *RiskReport) Close() error {
+	return r.f.Close()
+}
+
+// Run writes the report at every UTC day boundary. Blocks until done is closed.
+func (r *RiskReport) Run(done <-chan struct{}) {
+	for {
+		now := r.now().UTC()
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		timer := time.NewTimer(midnight.Sub(now))
+
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			r.flush(midnight.AddDate(0, 0, -1).Format("2006-01-02"))
+		}
+	}
+}
+
+// flush writes the record of day and logs its summary.
+func (r *RiskReport) flush(day string) {
+	rep, ok := r.src.GetRiskReport()
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(RiskRecord{Kind: RiskKindDaily, Day: day, WallTime: r.now().Format(time.RFC3339Nano), Report: rep})
+	if err != nil {
+		return
+	}
+	r.f.Write(append(data, '\n'))
+
+	for _, e := range rep.Exposure {
+		slog.Info("📊 Exposure summary",
+			slog.String("day", day),
+			slog.String("class", e.Class.String()),
+			slog.String("net", quant.PriceMicros(e.NetMicros).String()),
+			slog.String("gross", quant.PriceMicros(e.GrossMicros).String()),
+			slog.Int("positions", e.Positions))
+	}
+	for _, v := range rep.VaR {
+		slog.Info("📊 VaR summary",
+			slog.String("day", day),
+			slog.String("quote", v.Quote),
+			slog.String("var", quant.PriceMicros(v.VaRMicros).String()),
+			slog.Int("days", rep.Days),
+			slog.Int64("confidence_bps", rep.ConfidenceBps))
+	}
+}
@@ -0,0 +1,52 @@
+package infra
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/risk"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type fakeRiskSource struct {
+	rep risk.Report
+	ok  bool
+}
+
+func (f fakeRiskSource) GetRiskReport() (risk.Report, bool) {
+	return f.rep, f.ok
+}
+
+func TestRiskReport_Flush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "risk.jsonl")
+	rep := risk.Report{
+		Exposure:      []risk.Exposure{{Class: domain.AssetClass{Market: domain.MarketSpot, Quote: domain.QuoteKRW}, NetMicros: -5, GrossMicros: 5, Positions: 1}},
+		VaR:           []risk.QuoteVaR{{Quote: domain.QuoteKRW, VaRMicros: 2, GrossMicros: 5}},
+		Days:          30,
+		ConfidenceBps: 9900,
+	}
+	r, err := NewRiskReport(path, fakeRiskSource{rep: rep, ok: true})
+	if err != nil {
+		t.Fatalf("Failed to open report: %v", err)
+	}
+	r.flush("2026-01-07")
+	r.src = fakeRiskSource{} // No risk engine: nothing to write
+	r.flush("2026-01-08")
+	r.Close()
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 record, got %q", data)
+	}
+	var got RiskRecord
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", lines[0], err)
+	}
+	if got.Kind != RiskKindDaily || got.Day != "2026-01-07" || got.Days != 30 ||
+		len(got.Exposure) != 1 || got.Exposure[0] != rep.Exposure[0] || len(got.VaR) != 1 || got.VaR[0] != rep.VaR[0] {
+		t.Errorf("unexpected record %+v", got)
+	}
+}
@@ -0,0 +1,233 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// SentimentSource is the event source (and seq owner) of sentiment readings.
+const SentimentSource = "SENTIMENT"
+
+// Default providers (free, keyless).
+const (
+	DefaultFearGreedURL = "https://api.alternative.me/fng/?limit=1"
+	DefaultFundingURL   = "https://api.bitget.com/api/v2/mix/market/tickers?productType=USDT-FUTURES"
+)
+
+// SentimentConfig configures a SentimentClient. Zero values use the defaults.
+type SentimentConfig struct {
+	FearGreedURL   string
+	FundingURL     string        // Bitget V2 mix tickers format
+	FundingSymbols []string      // Futures instIDs to weight (empty = funding sentiment off)
+	PollInterval   time.Duration // 0 = hourly
+}
+
+// fearGreedResponse is alternative.me's /fng/ format (all fields are strings).
+type fearGreedResponse struct {
+	Data []struct {
+		Value          string `json:"value"`
+		Classification string `json:"value_classification"`
+		Timestamp      string `json:"timestamp"` // Unix seconds
+	} `json:"data"`
+}
+
+// fundingTickersResponse is the part of Bitget's V2 mix tickers used here.
+type fundingTickersResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		Symbol        string `json:"symbol"`
+		MarkPrice     string `json:"markPrice"`
+		FundingRate   string `json:"fundingRate"`
+		HoldingAmount string `json:"holdingAmount"` // Open interest in base coin
+		Ts            string `json:"ts"`            // Unix millis
+	} `json:"data"`
+}
+
+// SentimentClient polls sentiment indices and emits SentimentEvents: the Crypto
+// Fear & Greed index and, if FundingSymbols is set, the open-interest-weighted
+// funding rate of those perpetuals. The index only changes once a day; the
+// Sequencer ignores re-polled readings with an unchanged timestamp.
+type SentimentClient struct {
+	inbox      chan<- event.Event
+	nextSeq    *uint64
+	cfg        SentimentConfig
+	httpClient *http.Client
+	cancel     context.CancelFunc
+	lastFG     quant.TimeStamp // Last emitted Fear & Greed reading (poller goroutine only)
+}
+
+// NewSentimentClient creates a new sentiment client.
+func NewSentimentClient(inbox chan<- event.Event, seq *uint64, cfg SentimentConfig) *SentimentClient {
+	if cfg.FearGreedURL == "" {
+		cfg.FearGreedURL = DefaultFearGreedURL
+	}
+	if cfg.FundingURL == "" {
+		cfg.FundingURL = DefaultFundingURL
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Hour
+	}
+	return &SentimentClient{
+		inbox:      inbox,
+		nextSeq:    seq,
+		cfg:        cfg,
+		httpClient: NewHTTPClient(10 * time.Second),
+	}
+}
+
+// Start polls once immediately, then every PollInterval.
+func (c *SentimentClient) Start(ctx context.Context) error {
+	ctx, c.cancel = context.WithCancel(ctx)
+	go func() {
+		c.poll(ctx)
+		ticker := time.NewTicker(c.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.poll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop cancels the polling.
+func (c *SentimentClient) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *SentimentClient) poll(ctx context.Context) {
+	if ev, err := c.fetchFearGreed(ctx); err != nil {
+		slog.Warn("SENTIMENT_FETCH_FAILED", slog.String("index", domain.SentimentFearGreed), slog.Any("error", err))
+	} else if ev.Ts > c.lastFG {
+		if c.emit(ctx, ev) {
+			c.lastFG = ev.Ts
+		}
+	}
+
+	if len(c.cfg.FundingSymbols) == 0 {
+		return
+	}
+	if ev, err := c.fetchFunding(ctx); err != nil {
+		slog.Warn("SENTIMENT_FETCH_FAILED", slog.String("index", domain.SentimentFunding), slog.Any("error", err))
+	} else {
+		c.emit(ctx, ev)
+	}
+}
+
+func (c *SentimentClient) fetchFearGreed(ctx context.Context) (*event.SentimentEvent, error) {
+	var resp fearGreedResponse
+	if err := c.getJSON(ctx, c.cfg.FearGreedURL, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("empty fear & greed response")
+	}
+	d := resp.Data[0] // Latest first
+	value, err := strconv.ParseInt(d.Value, 10, 64)
+	if err != nil || value < 0 || value > 100 {
+		return nil, fmt.Errorf("invalid fear & greed value %q", d.Value)
+	}
+	sec, err := strconv.ParseInt(d.Timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fear & greed timestamp %q", d.Timestamp)
+	}
+
+	ev := &event.SentimentEvent{Source: SentimentSource, Index: domain.SentimentFearGreed, Value: value, Label: d.Classification}
+	ev.Ts = quant.TimeStamp(sec * 1_000_000)
+	return ev, nil
+}
+
+// fetchFunding computes Σ(rate × OI notional) / Σ(OI notional) over the watched
+// symbols. Notional is weighted in whole USDT so the sum cannot overflow.
+func (c *SentimentClient) fetchFunding(ctx context.Context) (*event.SentimentEvent, error) {
+	var resp fundingTickersResponse
+	if err := c.getJSON(ctx, c.cfg.FundingURL, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "" && resp.Code != "00000" {
+		return nil, fmt.Errorf("funding API error: %s - %s", resp.Code, resp.Msg)
+	}
+
+	watched := make(map[string]bool, len(c.cfg.FundingSymbols))
+	for _, s := range c.cfg.FundingSymbols {
+		watched[s] = true
+	}
+
+	var weighted, totalWeight int64
+	var ts quant.TimeStamp
+	for _, t := range resp.Data {
+		if !watched[t.Symbol] {
+			continue
+		}
+		oi := quant.ToQtySatsStr(t.HoldingAmount)
+		mark := quant.ToPriceMicrosStr(t.MarkPrice)
+		weight := safe.SafeMulDiv(int64(oi), int64(mark), quant.QtyScale) / quant.PriceScale // Whole USDT
+		if weight <= 0 {
+			continue
+		}
+		rate := int64(quant.ToPriceMicrosStr(t.FundingRate)) // 0.0001 -> 100
+		weighted = safe.SafeAdd(weighted, safe.SafeMul(rate, weight))
+		totalWeight = safe.SafeAdd(totalWeight, weight)
+		if ms, err := strconv.ParseInt(t.Ts, 10, 64); err == nil {
+			ts = max(ts, quant.TimeStamp(ms*1000))
+		}
+	}
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("no open interest for the watched symbols")
+	}
+	if ts == 0 {
+		ts = quant.TimeStamp(time.Now().UnixMicro())
+	}
+
+	ev := &event.SentimentEvent{Source: SentimentSource, Index: domain.SentimentFunding, Value: weighted / totalWeight}
+	ev.Ts = ts
+	return ev, nil
+}
+
+func (c *SentimentClient) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", GetUserAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// emit sends ev, waiting for room: readings are rare and must not be dropped.
+// Returns false if ctx ended first.
+func (c *SentimentClient) emit(ctx context.Context, ev *event.SentimentEvent) bool {
+	ev.Seq = quant.NextSeq(c.nextSeq)
+	select {
+	case c.inbox <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
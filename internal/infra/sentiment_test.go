@@ -0,0 +1,58 @@
+package infra
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+)
+
+func TestSentimentClient_Poll(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fng/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Fear and Greed Index","data":[
+			{"value":"25","value_classification":"Extreme Fear","timestamp":"1700006400","time_until_update":"3600"}]}`))
+	})
+	mux.HandleFunc("/tickers", func(w http.ResponseWriter, r *http.Request) {
+		// BTC: OI 100 x 60,000 = 6,000,000 USDT at 0.0001; ETH: 1,000 x 2,000 = 2,000,000 USDT at -0.0001
+		w.Write([]byte(`{"code":"00000","msg":"success","data":[
+			{"symbol":"BTCUSDT","markPrice":"60000","fundingRate":"0.0001","holdingAmount":"100","ts":"1700006500000"},
+			{"symbol":"ETHUSDT","markPrice":"2000","fundingRate":"-0.0001","holdingAmount":"1000","ts":"1700006500000"},
+			{"symbol":"DOGEUSDT","markPrice":"0.1","fundingRate":"0.01","holdingAmount":"1000000000","ts":"1700006500000"}]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	inbox := make(chan event.Event, 8)
+	client := NewSentimentClient(inbox, new(uint64), SentimentConfig{
+		FearGreedURL:   server.URL + "/fng/",
+		FundingURL:     server.URL + "/tickers",
+		FundingSymbols: []string{"BTCUSDT", "ETHUSDT"},
+	})
+	client.poll(context.Background())
+
+	if len(inbox) != 2 {
+		t.Fatalf("expected 2 readings, got %d", len(inbox))
+	}
+	fg := (<-inbox).(*event.SentimentEvent)
+	if fg.Index != domain.SentimentFearGreed || fg.Value != 25 || fg.Label != "Extreme Fear" || fg.Ts != 1700006400_000000 || fg.Source != SentimentSource {
+		t.Errorf("unexpected fear & greed event: %+v", fg)
+	}
+	funding := (<-inbox).(*event.SentimentEvent)
+	// (100 * 6,000,000 - 100 * 2,000,000) / 8,000,000 = 50 (0.00005)
+	if funding.Index != domain.SentimentFunding || funding.Value != 50 || funding.Ts != 1700006500_000000 {
+		t.Errorf("unexpected funding event: %+v", funding)
+	}
+	if funding.Seq != fg.Seq+1 {
+		t.Errorf("expected consecutive seqs, got %d then %d", fg.Seq, funding.Seq)
+	}
+
+	// Same fear & greed day re-polled: only the funding reading is new
+	client.poll(context.Background())
+	if len(inbox) != 1 {
+		t.Fatalf("expected only a funding reading on re-poll, got %d", len(inbox))
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package service
+
+import "context"
+
+// Run executes fn in the foreground. systemd and launchd supervise plain
+// processes, so no service-control handshake is required outside Windows.
+func Run(ctx context.Context, name string, fn func(ctx context.Context)) error {
+	fn(ctx)
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package service registers the monitor with the OS service manager
+// (systemd on Linux, launchd on macOS, SCM on Windows).
+// The API mirrors kardianos/service: Install/Uninstall/Start/Stop/Status.
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Status is the service state as reported by the OS service manager.
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusNotInstalled
+	StatusStopped
+	StatusRunning
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusNotInstalled:
+		return "NOT_INSTALLED"
+	case StatusStopped:
+		return "STOPPED"
+	case StatusRunning:
+		return "RUNNING"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ErrUnsupportedPlatform is returned on platforms without a known service manager.
+var ErrUnsupportedPlatform = errors.New("service management not supported on this platform")
+
+// Config describes how the monitor is registered as a service.
+type Config struct {
+	Name        string   // Unit / label / service name (e.g. "crypto-go")
+	DisplayName string   // Human readable name (Windows SCM, launchd comments)
+	Description string   // One-line description
+	Executable  string   // Absolute path of the binary
+	Args        []string // Arguments passed to the binary (e.g. "run", "-workdir", dir)
+	WorkingDir  string   // Directory holding configs/ and _workspace/ (path resolution base)
+}
+
+// Manager controls the lifecycle of the installed service.
+type Manager interface {
+	Install() error
+	Uninstall() error
+	Start() error
+	Stop() error
+	Status() (Status, error)
+}
+
+// New returns the Manager for the current platform.
+func New(cfg Config) (Manager, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+	if cfg.Executable == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve executable: %w", err)
+		}
+		cfg.Executable = exe
+	}
+	if !filepath.IsAbs(cfg.Executable) {
+		abs, err := filepath.Abs(cfg.Executable)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Executable = abs
+	}
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = cfg.Name
+	}
+	return newManager(cfg)
+}
+
+// runCommand executes an OS service tool and folds its output into the error.
+func runCommand(name string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if err != nil {
+		return out.String(), fmt.Errorf("%s %s failed: %w (%s)",
+			name, strings.Join(args, " "), err, strings.TrimSpace(out.String()))
+	}
+	return out.String(), nil
+}
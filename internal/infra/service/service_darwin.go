@@ -0,0 +1,93 @@
+//go:build darwin
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// launchdManager installs a per-user launchd agent.
+type launchdManager struct {
+	cfg Config
+}
+
+func newManager(cfg Config) (Manager, error) {
+	return &launchdManager{cfg: cfg}, nil
+}
+
+func (m *launchdManager) plistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", m.cfg.Name+".plist"), nil
+}
+
+func (m *launchdManager) Install() error {
+	path, err := m.plistPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("service already installed: %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(renderLaunchdPlist(m.cfg)), 0644); err != nil {
+		return fmt.Errorf("failed to write plist: %w", err)
+	}
+	return nil
+}
+
+func (m *launchdManager) Uninstall() error {
+	path, err := m.plistPath()
+	if err != nil {
+		return err
+	}
+	runCommand("launchctl", "unload", path) // Best effort
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (m *launchdManager) Start() error {
+	path, err := m.plistPath()
+	if err != nil {
+		return err
+	}
+	_, err = runCommand("launchctl", "load", "-w", path)
+	return err
+}
+
+func (m *launchdManager) Stop() error {
+	path, err := m.plistPath()
+	if err != nil {
+		return err
+	}
+	_, err = runCommand("launchctl", "unload", path)
+	return err
+}
+
+func (m *launchdManager) Status() (Status, error) {
+	path, err := m.plistPath()
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return StatusNotInstalled, nil
+	}
+	out, err := runCommand("launchctl", "list", m.cfg.Name)
+	if err != nil {
+		return StatusStopped, nil // Not loaded
+	}
+	// Loaded agents report "PID" = <n>; a missing PID means loaded but not running.
+	if strings.Contains(out, `"PID" =`) {
+		return StatusRunning, nil
+	}
+	return StatusStopped, nil
+}
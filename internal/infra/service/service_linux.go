@@ -0,0 +1,93 @@
+//go:build linux
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// systemdManager installs a systemd *user* unit (no root required).
+type systemdManager struct {
+	cfg Config
+}
+
+func newManager(cfg Config) (Manager, error) {
+	return &systemdManager{cfg: cfg}, nil
+}
+
+func (m *systemdManager) unitPath() (string, error) {
+	configRoot, err := os.UserConfigDir() // $XDG_CONFIG_HOME or ~/.config
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configRoot, "systemd", "user", m.cfg.Name+".service"), nil
+}
+
+func (m *systemdManager) Install() error {
+	path, err := m.unitPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("service already installed: %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(renderSystemdUnit(m.cfg)), 0644); err != nil {
+		return fmt.Errorf("failed to write unit file: %w", err)
+	}
+	if _, err := runCommand("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	_, err = runCommand("systemctl", "--user", "enable", m.cfg.Name)
+	return err
+}
+
+func (m *systemdManager) Uninstall() error {
+	path, err := m.unitPath()
+	if err != nil {
+		return err
+	}
+	// Best effort: the unit may already be stopped/disabled.
+	runCommand("systemctl", "--user", "stop", m.cfg.Name)
+	runCommand("systemctl", "--user", "disable", m.cfg.Name)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	_, err = runCommand("systemctl", "--user", "daemon-reload")
+	return err
+}
+
+func (m *systemdManager) Start() error {
+	_, err := runCommand("systemctl", "--user", "start", m.cfg.Name)
+	return err
+}
+
+func (m *systemdManager) Stop() error {
+	_, err := runCommand("systemctl", "--user", "stop", m.cfg.Name)
+	return err
+}
+
+func (m *systemdManager) Status() (Status, error) {
+	path, err := m.unitPath()
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return StatusNotInstalled, nil
+	}
+	// is-active exits non-zero for inactive units; the output is what matters.
+	out, _ := runCommand("systemctl", "--user", "is-active", m.cfg.Name)
+	switch strings.TrimSpace(out) {
+	case "active", "reloading", "activating":
+		return StatusRunning, nil
+	case "inactive", "failed", "deactivating":
+		return StatusStopped, nil
+	default:
+		return StatusUnknown, nil
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package service
+
+func newManager(cfg Config) (Manager, error) {
+	return nil, ErrUnsupportedPlatform
+}
@@ -0,0 +1,160 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// scmManager registers the binary with the Windows Service Control Manager.
+type scmManager struct {
+	cfg Config
+}
+
+func newManager(cfg Config) (Manager, error) {
+	return &scmManager{cfg: cfg}, nil
+}
+
+func (m *scmManager) Install() error {
+	conn, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to SCM: %w", err)
+	}
+	defer conn.Disconnect()
+
+	if s, err := conn.OpenService(m.cfg.Name); err == nil {
+		s.Close()
+		return fmt.Errorf("service already installed: %s", m.cfg.Name)
+	}
+
+	s, err := conn.CreateService(m.cfg.Name, m.cfg.Executable, mgr.Config{
+		DisplayName: m.cfg.DisplayName,
+		Description: m.cfg.Description,
+		StartType:   mgr.StartAutomatic,
+	}, m.cfg.Args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	defer s.Close()
+
+	// Restart after 5s on crash (mirrors Restart=on-failure on Linux)
+	return s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+	}, 86400)
+}
+
+func (m *scmManager) Uninstall() error {
+	return m.withService(func(s *mgr.Service) error {
+		s.Control(svc.Stop) // Best effort
+		return s.Delete()
+	})
+}
+
+func (m *scmManager) Start() error {
+	return m.withService(func(s *mgr.Service) error {
+		return s.Start()
+	})
+}
+
+func (m *scmManager) Stop() error {
+	return m.withService(func(s *mgr.Service) error {
+		_, err := s.Control(svc.Stop)
+		return err
+	})
+}
+
+func (m *scmManager) Status() (Status, error) {
+	conn, err := mgr.Connect()
+	if err != nil {
+		return StatusUnknown, err
+	}
+	defer conn.Disconnect()
+
+	s, err := conn.OpenService(m.cfg.Name)
+	if err != nil {
+		return StatusNotInstalled, nil
+	}
+	defer s.Close()
+
+	st, err := s.Query()
+	if err != nil {
+		return StatusUnknown, err
+	}
+	switch st.State {
+	case svc.Running, svc.StartPending:
+		return StatusRunning, nil
+	case svc.Stopped, svc.StopPending:
+		return StatusStopped, nil
+	default:
+		return StatusUnknown, nil
+	}
+}
+
+func (m *scmManager) withService(fn func(s *mgr.Service) error) error {
+	conn, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to SCM: %w", err)
+	}
+	defer conn.Disconnect()
+
+	s, err := conn.OpenService(m.cfg.Name)
+	if err != nil {
+		return fmt.Errorf("service not installed: %w", err)
+	}
+	defer s.Close()
+	return fn(s)
+}
+
+// Run executes fn under the SCM when started as a Windows service, translating
+// Stop/Shutdown requests into context cancellation. Interactive runs call fn directly.
+func Run(ctx context.Context, name string, fn func(ctx context.Context)) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return err
+	}
+	if !isService {
+		fn(ctx)
+		return nil
+	}
+	return svc.Run(name, &windowsHandler{ctx: ctx, fn: fn})
+}
+
+type windowsHandler struct {
+	ctx context.Context
+	fn  func(ctx context.Context)
+}
+
+func (h *windowsHandler) Execute(args []string, req <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+
+	status <- svc.Status{State: svc.StartPending}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.fn(ctx)
+	}()
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case <-done:
+			return false, 0
+		case c := <-req:
+			switch c.Cmd {
+			case svc.Interrogate:
+				status <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderSystemdUnit builds a user unit. Type=notify pairs with infra.SystemdNotifier
+// so systemd waits for READY=1 and restarts the process when the hotpath stalls.
+func renderSystemdUnit(cfg Config) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", firstNonEmpty(cfg.Description, cfg.DisplayName))
+	b.WriteString("After=network-online.target\n")
+	b.WriteString("Wants=network-online.target\n\n")
+
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=notify\n")
+	b.WriteString("NotifyAccess=main\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", systemdQuoteCommand(cfg.Executable, cfg.Args))
+	if cfg.WorkingDir != "" {
+		fmt.Fprintf(&b, "WorkingDirectory=%s\n", cfg.WorkingDir)
+	}
+	b.WriteString("WatchdogSec=30\n")
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=5\n\n")
+
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+	return b.String()
+}
+
+// renderLaunchdPlist builds a launchd agent definition (~/Library/LaunchAgents).
+func renderLaunchdPlist(cfg Config) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(cfg.Name))
+
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(cfg.Executable))
+	for _, arg := range cfg.Args {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+
+	if cfg.WorkingDir != "" {
+		fmt.Fprintf(&b, "\t<key>WorkingDirectory</key>\n\t<string>%s</string>\n", xmlEscape(cfg.WorkingDir))
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// systemdQuoteCommand quotes arguments containing whitespace for ExecStart=.
+func systemdQuoteCommand(exe string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	for _, p := range append([]string{exe}, args...) {
+		if strings.ContainsAny(p, " \t\"") {
+			p = `"` + strings.ReplaceAll(p, `"`, `\"`) + `"`
+		}
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, " ")
+}
+
+func xmlEscape(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+	return r.Replace(s)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSystemdUnit(t *testing.T) {
+	unit := renderSystemdUnit(Config{
+		Name:        "crypto-go",
+		Description: "Crypto Go monitor",
+		Executable:  "/opt/crypto go/app",
+		Args:        []string{"run", "-workdir", "/opt/crypto"},
+		WorkingDir:  "/opt/crypto",
+	})
+
+	for _, want := range []string{
+		"Description=Crypto Go monitor",
+		"Type=notify",
+		`ExecStart="/opt/crypto go/app" run -workdir /opt/crypto`,
+		"WorkingDirectory=/opt/crypto",
+		"WatchdogSec=",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+}
+
+func TestRenderLaunchdPlist(t *testing.T) {
+	plist := renderLaunchdPlist(Config{
+		Name:       "com.chycee.crypto-go",
+		Executable: "/Applications/app",
+		Args:       []string{"run", "-workdir", "/Users/me/R&D"},
+		WorkingDir: "/Users/me/R&D",
+	})
+
+	for _, want := range []string{
+		"<string>com.chycee.crypto-go</string>",
+		"<string>/Applications/app</string>",
+		"<string>run</string>",
+		"<string>/Users/me/R&amp;D</string>",
+		"<key>RunAtLoad</key>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q:\n%s", want, plist)
+		}
+	}
+}
+
+func TestNew_RequiresName(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("expected error for empty service name")
+	}
+}
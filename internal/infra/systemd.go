@@ -0,0 +1,149 @@
+package infra
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SystemdNotifier implements the sd_notify protocol (READY/STOPPING/WATCHDOG)
+// without linking libsystemd. When the process is not supervised by systemd
+// (NOTIFY_SOCKET unset), every call is a no-op.
+type SystemdNotifier struct {
+	socket   string
+	interval time.Duration // Recommended ping interval (half of WATCHDOG_USEC)
+
+	mu        sync.Mutex
+	lastBeat  atomic.Int64  // Unix nanos of the last Ping call from the hotpath
+	pingFails atomic.Uint64 // WATCHDOG=1 writes that failed (socket problems, not stalls)
+}
+
+// NewSystemdNotifier reads NOTIFY_SOCKET / WATCHDOG_USEC / WATCHDOG_PID from the environment.
+func NewSystemdNotifier() *SystemdNotifier {
+	n := &SystemdNotifier{socket: os.Getenv("NOTIFY_SOCKET")}
+
+	// WATCHDOG_PID guards against inheriting the watchdog of a parent process.
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return n
+	}
+	if usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64); err == nil && usec > 0 {
+		// systemd recommends pinging at half the configured timeout.
+		n.interval = time.Duration(usec) * time.Microsecond / 2
+	}
+	return n
+}
+
+// Enabled reports whether systemd is listening for notifications.
+func (n *SystemdNotifier) Enabled() bool {
+	return n.socket != ""
+}
+
+// WatchdogInterval returns how often Ping must be called (0 = watchdog disabled).
+func (n *SystemdNotifier) WatchdogInterval() time.Duration {
+	if !n.Enabled() {
+		return 0
+	}
+	return n.interval
+}
+
+// Notify sends a raw state string (e.g. "READY=1") to systemd.
+func (n *SystemdNotifier) Notify(state string) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	addr := &net.UnixAddr{Name: n.socket, Net: "unixgram"}
+	// Abstract namespace sockets are announced with a leading '@'.
+	if addr.Name[0] == '@' {
+		addr.Name = "\x00" + addr.Name[1:]
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return fmt.Errorf("sd_notify dial failed: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("sd_notify write failed: %w", err)
+	}
+	return nil
+}
+
+// Ready tells systemd that bootstrap has completed (Type=notify units).
+func (n *SystemdNotifier) Ready() error {
+	return n.Notify("READY=1")
+}
+
+// Stopping tells systemd that a graceful shutdown is in progress.
+func (n *SystemdNotifier) Stopping() error {
+	return n.Notify("STOPPING=1")
+}
+
+// Ping sends WATCHDOG=1. It is called from the Sequencer loop so that a
+// stalled hotpath stops the pings and systemd restarts the service.
+// The heartbeat is recorded even if the write fails: a broken notify socket
+// is an infrastructure problem, not a stalled hotpath.
+func (n *SystemdNotifier) Ping() {
+	if !n.Enabled() {
+		return
+	}
+	n.lastBeat.Store(time.Now().UnixNano())
+	if err := n.Notify("WATCHDOG=1"); err != nil {
+		n.pingFails.Add(1)
+		slog.Warn("Systemd watchdog ping failed", slog.Any("error", err))
+	}
+}
+
+// PingFailures returns how many WATCHDOG=1 writes failed.
+func (n *SystemdNotifier) PingFailures() uint64 {
+	return n.pingFails.Load()
+}
+
+// StallThreshold is the default stallAfter for Supervise: 1.5x the ping interval,
+// i.e. 75% of WATCHDOG_USEC, so WATCHDOG=trigger fires before systemd's own timeout.
+func (n *SystemdNotifier) StallThreshold() time.Duration {
+	return n.interval + n.interval/2
+}
+
+// Supervise watches the hotpath heartbeats and explicitly requests a restart
+// (WATCHDOG=trigger) once no heartbeat was seen for stallAfter.
+// stallAfter must stay below WATCHDOG_USEC (2x interval), otherwise systemd
+// kills the unit first; use StallThreshold.
+// Blocks until done is closed; run it in its own goroutine.
+func (n *SystemdNotifier) Supervise(done <-chan struct{}, stallAfter time.Duration) {
+	if n.WatchdogInterval() == 0 || stallAfter <= 0 {
+		return
+	}
+
+	n.lastBeat.CompareAndSwap(0, time.Now().UnixNano())
+	// Check at a quarter of the interval so detection lands well before the timeout.
+	ticker := time.NewTicker(n.interval / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			last := time.Unix(0, n.lastBeat.Load())
+			if now.Sub(last) < stallAfter {
+				continue
+			}
+			slog.Error("🚨 HOTPATH_STALLED: requesting restart from systemd",
+				slog.Duration("since_last_beat", now.Sub(last)))
+			if err := n.Notify("WATCHDOG=trigger"); err != nil {
+				slog.Error("Failed to trigger systemd watchdog", slog.Any("error", err))
+			}
+			return
+		}
+	}
+}
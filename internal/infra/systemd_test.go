@@ -0,0 +1,129 @@
+package infra
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// listenNotifySocket creates a unixgram socket that mimics systemd's NOTIFY_SOCKET.
+func listenNotifySocket(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not supported: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func readNotify(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 256)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read notification: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestSystemdNotifier_Disabled(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	t.Setenv("WATCHDOG_USEC", "")
+
+	n := NewSystemdNotifier()
+	if n.Enabled() {
+		t.Error("Expected notifier to be disabled without NOTIFY_SOCKET")
+	}
+	if err := n.Ready(); err != nil {
+		t.Errorf("Ready should be a no-op when disabled, got %v", err)
+	}
+	if n.WatchdogInterval() != 0 {
+		t.Errorf("Expected no watchdog interval, got %v", n.WatchdogInterval())
+	}
+}
+
+func TestSystemdNotifier_ReadyAndPing(t *testing.T) {
+	conn := listenNotifySocket(t)
+	t.Setenv("WATCHDOG_USEC", "2000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	n := NewSystemdNotifier()
+	if got := n.WatchdogInterval(); got != time.Second {
+		t.Errorf("Expected watchdog interval 1s (half of WATCHDOG_USEC), got %v", got)
+	}
+
+	if err := n.Ready(); err != nil {
+		t.Fatalf("Ready failed: %v", err)
+	}
+	if msg := readNotify(t, conn); msg != "READY=1" {
+		t.Errorf("Expected READY=1, got %q", msg)
+	}
+
+	n.Ping()
+	if msg := readNotify(t, conn); msg != "WATCHDOG=1" {
+		t.Errorf("Expected WATCHDOG=1, got %q", msg)
+	}
+}
+
+func TestSystemdNotifier_ForeignWatchdogPID(t *testing.T) {
+	listenNotifySocket(t)
+	t.Setenv("WATCHDOG_USEC", "2000000")
+	t.Setenv("WATCHDOG_PID", "1")
+
+	n := NewSystemdNotifier()
+	if n.WatchdogInterval() != 0 {
+		t.Error("Watchdog of another PID must be ignored")
+	}
+}
+
+func TestSystemdNotifier_SuperviseTriggersOnStall(t *testing.T) {
+	conn := listenNotifySocket(t)
+	t.Setenv("WATCHDOG_USEC", "100000") // 50ms ping interval
+	t.Setenv("WATCHDOG_PID", "")
+
+	n := NewSystemdNotifier()
+	done := make(chan struct{})
+	defer close(done)
+
+	// No pings at all: the hotpath is considered stalled.
+	go n.Supervise(done, 100*time.Millisecond)
+
+	if msg := readNotify(t, conn); msg != "WATCHDOG=trigger" {
+		t.Errorf("Expected WATCHDOG=trigger, got %q", msg)
+	}
+}
+
+func TestSystemdNotifier_StallThresholdBelowTimeout(t *testing.T) {
+	listenNotifySocket(t)
+	t.Setenv("WATCHDOG_USEC", "30000000") // WatchdogSec=30
+	t.Setenv("WATCHDOG_PID", "")
+
+	n := NewSystemdNotifier()
+	timeout := 30 * time.Second
+	if got := n.StallThreshold(); got >= timeout || got <= n.WatchdogInterval() {
+		t.Errorf("Stall threshold %v must be between the ping interval and WATCHDOG_USEC", got)
+	}
+}
+
+func TestSystemdNotifier_PingFailureIsNotStall(t *testing.T) {
+	// Socket path that nobody listens on: every write fails
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+	t.Setenv("WATCHDOG_USEC", "100000")
+	t.Setenv("WATCHDOG_PID", "")
+
+	n := NewSystemdNotifier()
+	n.Ping()
+	if n.PingFailures() != 1 {
+		t.Errorf("Expected 1 ping failure, got %d", n.PingFailures())
+	}
+	if n.lastBeat.Load() == 0 {
+		t.Error("Heartbeat must be recorded even when the socket write fails")
+	}
+}
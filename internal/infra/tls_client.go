@@ -0,0 +1,54 @@
+package infra
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+)
+
+// tlsSessionCacheSize covers every exchange endpoint with room to spare.
+const tlsSessionCacheSize = 64
+
+// DialTLSContext dials addr and performs the TLS handshake itself, so the handshake
+// can be timed per endpoint and sessions are resumed from the dialer's shared cache.
+// After a network blip, a resumed handshake skips the certificate exchange and
+// saves a full round trip on every reconnect.
+func (d *Dialer) DialTLSContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := tls.Client(raw, &tls.Config{
+		ServerName:         host,
+		RootCAs:            d.rootCAs, // nil = system roots
+		ClientSessionCache: d.sessions,
+		MinVersion:         tls.VersionTLS12,
+		NextProtos:         []string{"http/1.1"}, // WebSocket upgrade and our REST clients speak HTTP/1.1
+	})
+
+	start := time.Now()
+	if err := conn.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		if d.metrics != nil {
+			d.metrics.RecordTLSHandshake(host, time.Since(start), false, false)
+		}
+		return nil, fmt.Errorf("tls handshake %s: %w", host, err)
+	}
+	if d.metrics != nil {
+		d.metrics.RecordTLSHandshake(host, time.Since(start), true, conn.ConnectionState().DidResume)
+	}
+	return conn, nil
+}
+
+// sharedDialTLSContext resolves the shared dialer at dial time (see sharedDialContext).
+func sharedDialTLSContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return SharedDialer().DialTLSContext(ctx, network, addr)
+}
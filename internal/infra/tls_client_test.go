@@ -0,0 +1,47 @@
+package infra
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDialer_TLSResumptionAndMetrics(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	metrics := &Metrics{}
+	d := NewDialer(DialerConfig{}, metrics)
+	d.rootCAs = srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	d.lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	url := "https://example.com:" + port + "/" // httptest certificate is valid for example.com
+
+	for i := 0; i < 2; i++ {
+		// Fresh transport each time: no pooled connection, a new handshake per request
+		transport := &http.Transport{DialTLSContext: d.DialTLSContext}
+		resp, err := (&http.Client{Transport: transport}).Get(url)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+		transport.CloseIdleConnections()
+	}
+
+	snap := metrics.Snapshot()
+	if snap.TLSHandshakes["example.com"] != 2 {
+		t.Errorf("expected 2 handshakes, got %v", snap.TLSHandshakes)
+	}
+	if snap.TLSResumed["example.com"] != 1 {
+		t.Errorf("expected the second handshake to resume, got %v", snap.TLSResumed)
+	}
+}
@@ -0,0 +1,125 @@
+package infra
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ErrTradingLockHeld is returned when another instance already trades with the same API keys.
+var ErrTradingLockHeld = errors.New("trading lock held by another instance")
+
+// TradingLock guarantees that at most one engine per set of API keys can place orders.
+//
+// instance.lock (CreateLockFile) only protects one workspace; two copies started from
+// different directories would both trade on the same account. This lock is keyed by a
+// hash of the credentials and lives in a host-wide directory, so every copy on this
+// machine competes for the same file regardless of its workspace.
+type TradingLock struct {
+	InstanceID string // Random ID of this process, written into the lock for diagnostics
+	Path       string
+	f          *os.File
+}
+
+// NewInstanceID returns a random 16-hex-char instance identifier.
+func NewInstanceID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("pid-%d", os.Getpid())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// TradingLockDir is the host-wide directory holding trading locks.
+// It must be shared by every user account and survive tmp cleaners,
+// so os.TempDir (per-user on Windows/macOS, aged on Linux) is not used.
+func TradingLockDir() string {
+	switch runtime.GOOS {
+	case "windows":
+		// C:\ProgramData\crypto-go\locks (machine-wide)
+		base := os.Getenv("ProgramData")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		return filepath.Join(base, AppName, "locks")
+	case "darwin":
+		// /Users/Shared is the machine-wide, world-writable location on macOS
+		return filepath.Join("/Users/Shared", AppName, "locks")
+	default:
+		// /run/lock is world-writable and not touched by tmp cleaners
+		for _, base := range []string{"/run/lock", "/var/lock"} {
+			if info, err := os.Stat(base); err == nil && info.IsDir() {
+				return filepath.Join(base, AppName)
+			}
+		}
+		return filepath.Join("/var/tmp", AppName+"-locks")
+	}
+}
+
+// TradingLockKey derives a stable, non-reversible lock name from API credentials.
+// Returns "" when no credentials are configured (nothing to protect).
+func TradingLockKey(credentials ...string) string {
+	joined := strings.Join(credentials, "\x00")
+	if strings.Trim(joined, "\x00") == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(joined))
+	return hex.EncodeToString(sum[:8])
+}
+
+// AcquireTradingLock tries to take the trading lock for key without blocking.
+// On contention it returns ErrTradingLockHeld along with the holder's description.
+// Any other error means the lock state could not be determined (I/O, permissions).
+func AcquireTradingLock(dir, key, instanceID string) (*TradingLock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock dir: %w", err)
+	}
+	// Shared between user accounts: world-writable + sticky (best effort, no-op on Windows)
+	os.Chmod(dir, 0777|os.ModeSticky)
+	path := filepath.Join(dir, "trading-"+key+".lock")
+
+	writable := true
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if errors.Is(err, os.ErrPermission) {
+		// Lock file created by another user: a read-only handle can still be locked
+		writable = false
+		f, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := lockFile(f); err != nil {
+		holder, _ := os.ReadFile(path)
+		f.Close()
+		if !isLockContention(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		return nil, fmt.Errorf("%w (holder: %s)", ErrTradingLockHeld, strings.TrimSpace(string(holder)))
+	}
+
+	if writable {
+		f.Truncate(0)
+		f.Seek(0, 0)
+		f.WriteString(fmt.Sprintf("instance=%s pid=%d", instanceID, os.Getpid()))
+	}
+
+	return &TradingLock{InstanceID: instanceID, Path: path, f: f}, nil
+}
+
+// Release drops the lock. The OS also releases it if the process dies.
+// The file is intentionally left in place: unlinking it would let a waiter lock
+// the orphaned inode while a newcomer locks a fresh file, admitting two traders.
+func (l *TradingLock) Release() {
+	if l == nil || l.f == nil {
+		return
+	}
+	l.f.Close()
+	l.f = nil
+}
@@ -0,0 +1,86 @@
+package infra
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTradingLockKey(t *testing.T) {
+	if key := TradingLockKey("", ""); key != "" {
+		t.Errorf("expected empty key without credentials, got %q", key)
+	}
+
+	a := TradingLockKey("upbit-key", "bitget-key")
+	b := TradingLockKey("upbit-key", "bitget-key")
+	c := TradingLockKey("upbit-key", "other-key")
+	if a == "" || a != b {
+		t.Errorf("key must be stable: %q vs %q", a, b)
+	}
+	if a == c {
+		t.Error("different credentials must yield different keys")
+	}
+}
+
+func TestAcquireTradingLock_SecondInstanceRejected(t *testing.T) {
+	dir := t.TempDir()
+	key := TradingLockKey("same-api-key")
+
+	first, err := AcquireTradingLock(dir, key, "instance-a")
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+
+	_, err = AcquireTradingLock(dir, key, "instance-b")
+	if !errors.Is(err, ErrTradingLockHeld) {
+		t.Fatalf("expected ErrTradingLockHeld, got %v", err)
+	}
+
+	// Different keys do not contend
+	other, err := AcquireTradingLock(dir, TradingLockKey("another-account"), "instance-c")
+	if err != nil {
+		t.Fatalf("independent key should lock: %v", err)
+	}
+	other.Release()
+
+	// After release the next instance takes over
+	first.Release()
+	second, err := AcquireTradingLock(dir, key, "instance-b")
+	if err != nil {
+		t.Fatalf("acquire after release failed: %v", err)
+	}
+	second.Release()
+}
+
+func TestAcquireTradingLock_IOErrorIsNotContention(t *testing.T) {
+	// A regular file where the lock directory should be: MkdirAll fails
+	blocker := filepath.Join(t.TempDir(), "not-a-dir")
+	os.WriteFile(blocker, nil, 0644)
+
+	_, err := AcquireTradingLock(filepath.Join(blocker, "locks"), TradingLockKey("k"), "instance-a")
+	if err == nil {
+		t.Fatal("expected an error for an unusable lock dir")
+	}
+	if errors.Is(err, ErrTradingLockHeld) {
+		t.Errorf("I/O failure must not be reported as lock contention: %v", err)
+	}
+}
+
+func TestLockFile_FailureIsNotContention(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "x.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := lockFile(f); err == nil || isLockContention(err) {
+		t.Errorf("locking a closed file must fail without contention, got %v", err)
+	}
+}
+
+func TestTradingLockDir_NotPerUserTemp(t *testing.T) {
+	if strings.HasPrefix(TradingLockDir(), os.TempDir()) {
+		t.Errorf("trading lock dir must be host-wide, got %s", TradingLockDir())
+	}
+}
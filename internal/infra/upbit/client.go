@@ -0,0 +1,273 @@
+package upbit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// BaseURL is the Upbit REST endpoint. Upbit has no testnet.
+const BaseURL = "https://api.upbit.com"
+
+// Client for the Upbit Exchange API (KRW spot).
+type Client struct {
+	httpClient     *http.Client
+	baseURL        string
+	signer         *Signer
+	logger         *slog.Logger
+	circuitBreaker *infra.CircuitBreaker // Rule #5: Fault isolation
+}
+
+// NewClient creates a new Upbit API client from cfg.API.Upbit.
+func NewClient(cfg *infra.Config) *Client {
+	baseURL := BaseURL
+	if cfg.API.Upbit.RestURL != "" {
+		baseURL = cfg.API.Upbit.RestURL
+	}
+
+	return &Client{
+		httpClient:     infra.NewHTTPClient(10 * time.Second),
+		baseURL:        baseURL,
+		signer:         NewSigner(cfg.API.Upbit.AccessKey, cfg.API.Upbit.SecretKey),
+		logger:         slog.With("module", "upbit_client"),
+		circuitBreaker: infra.NewCircuitBreaker(infra.DefaultCircuitBreakerConfig("upbit-api")),
+	}
+}
+
+// Close wipes secrets from memory.
+func (c *Client) Close() error {
+	c.signer.Wipe()
+	return nil
+}
+
+// PlaceOrder sends an order to the KRW market of order.Symbol.
+// order.ID is sent as the client identifier, so it must be unique per account.
+// A market BUY is sent as Upbit's "price" order for PriceMicros*QtySats of KRW,
+// so it needs the expected price; a market SELL sells QtySats at market.
+func (c *Client) PlaceOrder(ctx context.Context, order domain.Order) error {
+	// Rate Limiting: Prevent IP ban (보안 강화)
+	infra.GetUpbitOrderLimiter().Wait()
+
+	params := url.Values{}
+	params.Set("market", marketCode(order.Symbol))
+	params.Set("identifier", order.ID)
+	if order.Side == domain.SideSell {
+		params.Set("side", "ask")
+	} else {
+		params.Set("side", "bid")
+	}
+
+	switch {
+	case order.Type != domain.OrderTypeMarket:
+		params.Set("ord_type", "limit")
+		params.Set("price", formatDecimal(order.PriceMicros, 6))
+		params.Set("volume", formatDecimal(order.QtySats, 8))
+	case order.Side == domain.SideSell:
+		params.Set("ord_type", "market")
+		params.Set("volume", formatDecimal(order.QtySats, 8))
+	default:
+		if order.PriceMicros <= 0 {
+			return fmt.Errorf("upbit market buy %s needs an expected price to size the KRW amount", order.ID)
+		}
+		notional := safe.SafeMulDiv(order.PriceMicros, order.QtySats, quant.QtyScale)
+		params.Set("ord_type", "price")
+		params.Set("price", formatDecimal(notional, 6))
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v1/orders", params)
+	if err != nil {
+		return fmt.Errorf("upbit place order failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := c.parseResponse(resp); err != nil {
+		return fmt.Errorf("place order error: %w", err)
+	}
+
+	c.logger.Info("Order Placed Successfully", "oid", order.ID, "symbol", order.Symbol)
+	return nil
+}
+
+// CancelOrder cancels the order placed with client identifier orderID.
+// symbol is not needed by Upbit; it is kept for the domain.Execution signature.
+func (c *Client) CancelOrder(ctx context.Context, orderID string, symbol string) error {
+	// Rate Limiting: Prevent IP ban (보안 강화)
+	infra.GetUpbitOrderLimiter().Wait()
+
+	params := url.Values{}
+	params.Set("identifier", orderID)
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/v1/order", params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := c.parseResponse(resp); err != nil {
+		return fmt.Errorf("cancel order error: %w", err)
+	}
+
+	c.logger.Info("Order Canceled Successfully", "oid", orderID, "symbol", symbol)
+	return nil
+}
+
+// GetBalance fetches the available (unlocked) balance of currency.
+// KRW is returned in micros, coins in sats.
+func (c *Client) GetBalance(ctx context.Context, currency string) (int64, error) {
+	// Rate Limiting: Prevent IP ban (보안 강화)
+	infra.GetUpbitAccountLimiter().Wait()
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v1/accounts", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := c.parseResponse(resp)
+	if err != nil {
+		return 0, fmt.Errorf("get balance error: %w", err)
+	}
+
+	var accounts []struct {
+		Currency string `json:"currency"`
+		Balance  string `json:"balance"` // Available; "locked" is held by open orders
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return 0, fmt.Errorf("failed to parse accounts json: %w", err)
+	}
+
+	for _, acc := range accounts {
+		if acc.Currency == currency {
+			if currency == "KRW" {
+				return int64(quant.ToPriceMicrosStr(acc.Balance)), nil
+			}
+			return int64(quant.ToQtySatsStr(acc.Balance)), nil
+		}
+	}
+
+	return 0, nil // Not found
+}
+
+// parseResponse validates an Upbit response and returns the raw body.
+// Errors come back as non-2xx with {"error":{"name","message"}}.
+func (c *Client) parseResponse(resp *http.Response) (json.RawMessage, error) {
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, ClassifyError("too_many_requests", string(bodyBytes))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Name    string `json:"name"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(bodyBytes, &apiErr) == nil && apiErr.Error.Name != "" {
+			return nil, ClassifyError(apiErr.Error.Name, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("http error: status=%d body=%s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return bodyBytes, nil
+}
+
+// doRequest performs a signed request with circuit breaker protection.
+// params go in the query string for GET/DELETE and as a JSON body for POST;
+// either way the JWT carries the SHA512 hash of their unescaped query form.
+func (c *Client) doRequest(ctx context.Context, method, path string, params url.Values) (*http.Response, error) {
+	// Encode sorts keys, matching the key order of the JSON-marshaled map below
+	query := params.Encode()
+	hashQuery, err := url.QueryUnescape(query)
+	if err != nil {
+		return nil, fmt.Errorf("unescaping query: %w", err)
+	}
+
+	target := c.baseURL + path
+	var body io.Reader
+	if method == http.MethodPost {
+		fields := make(map[string]string, len(params))
+		for k := range params {
+			fields[k] = params.Get(k)
+		}
+		jsonBytes, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling payload: %w", err)
+		}
+		body = bytes.NewReader(jsonBytes)
+	} else if query != "" {
+		target += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	auth, err := c.signer.AuthorizationHeader(hashQuery)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", infra.GetUserAgent())
+
+	// Circuit Breaker (Rule #5: Fault isolation). Any HTTP response counts as
+	// success (even 4xx is "server responded").
+	var resp *http.Response
+	err = c.circuitBreaker.Execute(ctx, func() error {
+		var err error
+		resp, err = c.httpClient.Do(req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// marketCode maps a unified symbol ("BTC") to its Upbit KRW market ("KRW-BTC").
+// Symbols that already name a market are passed through.
+func marketCode(symbol string) string {
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+	return "KRW-" + symbol
+}
+
+// formatDecimal converts an int64 fixed-point value to a decimal string with
+// trailing fractional zeros dropped (e.g. 50000000000000 at precision 6 -> "50000000").
+func formatDecimal(value int64, precision int) string {
+	scale := int64(1)
+	for i := 0; i < precision; i++ {
+		scale *= 10
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	whole := value / scale
+	frac := value % scale
+	if frac == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+	fracStr := strings.TrimRight(fmt.Sprintf("%0*d", precision, frac), "0")
+	return fmt.Sprintf("%s%d.%s", sign, whole, fracStr)
+}
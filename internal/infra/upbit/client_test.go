@@ -0,0 +1,188 @@
+package upbit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/infra"
+)
+
+// mockRoundTripper allows us to mock HTTP responses
+type mockRoundTripper func(req *http.Request) (*http.Response, error)
+
+func (m mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return m(req)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+func newTestClient(rt mockRoundTripper) *Client {
+	cfg := &infra.Config{}
+	cfg.API.Upbit.AccessKey = "test_access"
+	cfg.API.Upbit.SecretKey = "test_secret"
+	client := NewClient(cfg)
+	client.httpClient.Transport = rt
+	return client
+}
+
+func TestClient_PlaceOrder_Limit(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/v1/orders" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+			t.Errorf("missing bearer token")
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		want := map[string]string{
+			"market": "KRW-BTC", "side": "bid", "ord_type": "limit",
+			"price": "95000000", "volume": "0.001", "identifier": "test_oid",
+		}
+		for k, v := range want {
+			if body[k] != v {
+				t.Errorf("%s: got %q, want %q", k, body[k], v)
+			}
+		}
+		return jsonResponse(http.StatusCreated, `{"uuid":"u-1","identifier":"test_oid"}`), nil
+	})
+
+	order := domain.Order{
+		ID:          "test_oid",
+		Symbol:      "BTC",
+		Side:        domain.SideBuy,
+		Type:        domain.OrderTypeLimit,
+		PriceMicros: 95_000_000_000_000, // 95,000,000 KRW
+		QtySats:     100_000,            // 0.001 BTC
+	}
+	if err := client.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+}
+
+func TestClient_PlaceOrder_Market(t *testing.T) {
+	var bodies []map[string]string
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		var body map[string]string
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		bodies = append(bodies, body)
+		return jsonResponse(http.StatusCreated, `{}`), nil
+	})
+
+	buy := domain.Order{ID: "b", Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeMarket, PriceMicros: 95_000_000_000_000, QtySats: 100_000}
+	sell := domain.Order{ID: "s", Symbol: "BTC", Side: domain.SideSell, Type: domain.OrderTypeMarket, QtySats: 100_000}
+	for _, o := range []domain.Order{buy, sell} {
+		if err := client.PlaceOrder(context.Background(), o); err != nil {
+			t.Fatalf("PlaceOrder %s failed: %v", o.ID, err)
+		}
+	}
+
+	// Market buy spends KRW: 95,000,000 * 0.001 = 95,000
+	if bodies[0]["ord_type"] != "price" || bodies[0]["price"] != "95000" || bodies[0]["volume"] != "" {
+		t.Errorf("unexpected market buy body: %v", bodies[0])
+	}
+	if bodies[1]["ord_type"] != "market" || bodies[1]["volume"] != "0.001" || bodies[1]["price"] != "" {
+		t.Errorf("unexpected market sell body: %v", bodies[1])
+	}
+
+	noPrice := domain.Order{ID: "x", Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeMarket, QtySats: 100_000}
+	if err := client.PlaceOrder(context.Background(), noPrice); err == nil {
+		t.Error("expected an error for a market buy without expected price")
+	}
+}
+
+func TestClient_CancelOrder(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodDelete || req.URL.Path != "/v1/order" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		if got := req.URL.Query().Get("identifier"); got != "test_oid" {
+			t.Errorf("identifier: got %q", got)
+		}
+		return jsonResponse(http.StatusOK, `{"uuid":"u-1"}`), nil
+	})
+
+	if err := client.CancelOrder(context.Background(), "test_oid", "BTC"); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+}
+
+func TestClient_GetBalance(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet || req.URL.Path != "/v1/accounts" {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		return jsonResponse(http.StatusOK, `[
+			{"currency":"KRW","balance":"1500000.5","locked":"0"},
+			{"currency":"BTC","balance":"1.23456789","locked":"0.1"}
+		]`), nil
+	})
+
+	krw, err := client.GetBalance(context.Background(), "KRW")
+	if err != nil {
+		t.Fatalf("GetBalance KRW failed: %v", err)
+	}
+	if krw != 1_500_000_500_000 {
+		t.Errorf("KRW: got %d", krw)
+	}
+
+	btc, err := client.GetBalance(context.Background(), "BTC")
+	if err != nil {
+		t.Fatalf("GetBalance BTC failed: %v", err)
+	}
+	if btc != 123_456_789 {
+		t.Errorf("BTC: got %d", btc)
+	}
+
+	eth, _ := client.GetBalance(context.Background(), "ETH")
+	if eth != 0 {
+		t.Errorf("ETH: got %d, want 0 for a missing account", eth)
+	}
+}
+
+func TestClient_ErrorClassification(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusBadRequest, `{"error":{"name":"insufficient_funds_bid","message":"주문가능한 금액(KRW)이 부족합니다."}}`), nil
+	})
+
+	order := domain.Order{ID: "o", Symbol: "BTC", Side: domain.SideBuy, Type: domain.OrderTypeLimit, PriceMicros: 1_000_000, QtySats: 1}
+	err := client.PlaceOrder(context.Background(), order)
+	if !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Errorf("expected ErrInsufficientFunds, got %v", err)
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	tests := []struct {
+		value     int64
+		precision int
+		want      string
+	}{
+		{95_000_000_000_000, 6, "95000000"},
+		{1_500_000, 6, "1.5"},
+		{100_000, 8, "0.001"},
+		{-1_234_567, 6, "-1.234567"},
+		{0, 8, "0"},
+	}
+	for _, tt := range tests {
+		if got := formatDecimal(tt.value, tt.precision); got != tt.want {
+			t.Errorf("formatDecimal(%d, %d) = %s, want %s", tt.value, tt.precision, got, tt.want)
+		}
+	}
+}
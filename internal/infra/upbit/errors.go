@@ -0,0 +1,28 @@
+package upbit
+
+import "crypto_go/internal/domain"
+
+// errorKinds maps Upbit REST error names (error.name) to normalized reject kinds.
+var errorKinds = map[string]domain.RejectKind{
+	"insufficient_funds_bid": domain.RejectInsufficientFunds,
+	"insufficient_funds_ask": domain.RejectInsufficientFunds,
+	"under_min_total_bid":    domain.RejectMinSize,
+	"under_min_total_ask":    domain.RejectMinSize,
+	"too_many_requests":      domain.RejectRateLimited,
+	"invalid_price_bid":      domain.RejectInvalidPrice,
+	"invalid_price_ask":      domain.RejectInvalidPrice,
+	"order_not_found":        domain.RejectOrderNotFound,
+	"market_does_not_exist":  domain.RejectMarketUnavailable,
+	"market_offline":         domain.RejectMarketUnavailable,
+}
+
+// ClassifyError converts an Upbit error response ({"error":{"name","message"}})
+// into a domain.ExchangeError. HTTP 429 carries no body name, so callers pass
+// "too_many_requests" for it.
+func ClassifyError(name, message string) *domain.ExchangeError {
+	kind, ok := errorKinds[name]
+	if !ok {
+		kind = domain.RejectUnknown
+	}
+	return &domain.ExchangeError{Venue: "UPBIT", Code: name, Msg: message, Kind: kind}
+}
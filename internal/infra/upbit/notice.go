@@ -0,0 +1,226 @@
+package upbit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
+	"crypto_go/pkg/quant"
+)
+
+// NoticeSource is the event source (and seq owner) of Upbit announcements.
+const NoticeSource = "UPBIT_NOTICE"
+
+// DefaultNoticeURL is Upbit's public announcement list (newest first).
+const DefaultNoticeURL = "https://api-manager.upbit.com/api/v1/announcements?os=web&page=1&per_page=20&category=all"
+
+// NoticeConfig configures a NoticeClient. Zero values use the defaults.
+type NoticeConfig struct {
+	URL          string
+	PollInterval time.Duration       // 0 = 15s: listings move KRW markets within seconds
+	MaxAge       time.Duration       // Older notices are skipped on the first poll (0 = 10m)
+	Notify       func(domain.Notice) // Called for each emitted notice (optional, poller goroutine)
+}
+
+// noticeListResponse is the part of the announcement API used here.
+type noticeListResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Notices []struct {
+			ID       int64  `json:"id"`
+			Title    string `json:"title"`
+			ListedAt string `json:"listed_at"` // RFC 3339, KST offset
+		} `json:"notices"`
+	} `json:"data"`
+}
+
+// noticeRules classify a title by keyword, first match wins. Delisting comes
+// before listing: "거래지원 종료" also contains "거래지원".
+var noticeRules = []struct {
+	kind     string
+	keywords []string
+}{
+	{domain.NoticeDelisting, []string{"거래지원 종료", "거래 지원 종료", "상장폐지"}},
+	{domain.NoticeListing, []string{"신규 거래지원", "디지털 자산 추가"}},
+	{domain.NoticeWallet, []string{"입출금", "입금", "출금", "지갑"}},
+}
+
+// tickerPattern matches a ticker in parentheses, e.g. "비트코인(BTC)". Market
+// lists such as "(KRW, BTC 마켓)" do not match.
+var tickerPattern = regexp.MustCompile(`\(([A-Z0-9]{2,12})\)`)
+
+// ClassifyNotice returns the Notice* kind of an announcement title, or "" if
+// the notice is not one that moves markets.
+func ClassifyNotice(title string) string {
+	for _, r := range noticeRules {
+		for _, kw := range r.keywords {
+			if strings.Contains(title, kw) {
+				return r.kind
+			}
+		}
+	}
+	return ""
+}
+
+// noticeSymbols returns the tickers named in title, in order, without duplicates.
+func noticeSymbols(title string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range tickerPattern.FindAllStringSubmatch(title, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			out = append(out, m[1])
+		}
+	}
+	return out
+}
+
+// NoticeClient polls Upbit announcements and emits listing, delisting and
+// wallet-maintenance notices as NoticeEvents. Other announcements are skipped.
+// On the first poll only notices younger than MaxAge are emitted, so a restart
+// does not replay the whole list; after that every new ID is emitted once.
+type NoticeClient struct {
+	inbox      chan<- event.Event
+	nextSeq    *uint64
+	cfg        NoticeConfig
+	httpClient *http.Client
+	cancel     context.CancelFunc
+	lastID     int64 // Highest notice ID seen (poller goroutine only)
+}
+
+// NewNoticeClient creates a new announcement poller.
+func NewNoticeClient(inbox chan<- event.Event, seq *uint64, cfg NoticeConfig) *NoticeClient {
+	if cfg.URL == "" {
+		cfg.URL = DefaultNoticeURL
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 15 * time.Second
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = 10 * time.Minute
+	}
+	return &NoticeClient{
+		inbox:      inbox,
+		nextSeq:    seq,
+		cfg:        cfg,
+		httpClient: infra.NewHTTPClient(10 * time.Second),
+	}
+}
+
+// Start polls once immediately, then every PollInterval.
+func (c *NoticeClient) Start(ctx context.Context) error {
+	ctx, c.cancel = context.WithCancel(ctx)
+	go func() {
+		c.poll(ctx)
+		ticker := time.NewTicker(c.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.poll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop cancels the polling.
+func (c *NoticeClient) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *NoticeClient) poll(ctx context.Context) {
+	notices, err := c.fetch(ctx)
+	if err != nil {
+		slog.Warn("NOTICE_FETCH_FAILED", slog.String("exchange", "UPBIT"), slog.Any("error", err))
+		return
+	}
+
+	first := c.lastID == 0
+	cutoff := quant.TimeStamp(time.Now().Add(-c.cfg.MaxAge).UnixMicro())
+	for _, n := range notices { // Oldest first
+		if n.ID <= c.lastID {
+			continue
+		}
+		if first && n.Ts < cutoff {
+			c.lastID = n.ID // Published before we started watching
+			continue
+		}
+		if n.Kind != "" && !c.emit(ctx, n) {
+			return // Retried on the next poll
+		}
+		c.lastID = n.ID
+	}
+}
+
+// fetch returns the listed notices, classified, in ascending ID order.
+func (c *NoticeClient) fetch(ctx context.Context) ([]domain.Notice, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", infra.GetUserAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	var list noticeListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+	if !list.Success {
+		return nil, fmt.Errorf("notice API reported failure")
+	}
+
+	notices := make([]domain.Notice, 0, len(list.Data.Notices))
+	for _, r := range list.Data.Notices {
+		listed, err := time.Parse(time.RFC3339, r.ListedAt)
+		if err != nil {
+			return nil, fmt.Errorf("notice %d: invalid listed_at %q", r.ID, r.ListedAt)
+		}
+		n := domain.Notice{Exchange: "UPBIT", ID: r.ID, Title: r.Title, Ts: quant.TimeStamp(listed.UnixMicro())}
+		if n.Kind = ClassifyNotice(r.Title); n.Kind != "" {
+			n.Symbols = noticeSymbols(r.Title)
+		}
+		notices = append(notices, n)
+	}
+	sort.Slice(notices, func(i, j int) bool { return notices[i].ID < notices[j].ID })
+	return notices, nil
+}
+
+// emit sends a notice, waiting for room: notices are rare and must not be
+// dropped. Returns false if ctx ended first.
+func (c *NoticeClient) emit(ctx context.Context, n domain.Notice) bool {
+	ev := &event.NoticeEvent{Source: NoticeSource, Exchange: n.Exchange, NoticeID: n.ID, Kind: n.Kind, Title: n.Title, Symbols: n.Symbols}
+	ev.Seq = quant.NextSeq(c.nextSeq)
+	ev.Ts = n.Ts
+
+	select {
+	case c.inbox <- ev:
+	case <-ctx.Done():
+		return false
+	}
+	if c.cfg.Notify != nil {
+		c.cfg.Notify(n)
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package upbit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+)
+
+func TestClassifyNotice(t *testing.T) {
+	tests := []struct {
+		title   string
+		kind    string
+		symbols []string
+	}{
+		{"[거래] 플로우(FLOW), 앵커(ANKR) 거래지원 종료 안내", domain.NoticeDelisting, []string{"FLOW", "ANKR"}},
+		{"[거래] 비트코인(BTC) 신규 거래지원 안내 (KRW, USDT 마켓)", domain.NoticeListing, []string{"BTC"}},
+		{"[거래] KRW 마켓 디지털 자산 추가 (SOL)", domain.NoticeListing, []string{"SOL"}},
+		{"[입출금] 이더리움(ETH) 네트워크 업그레이드에 따른 입출금 일시 중단 안내", domain.NoticeWallet, []string{"ETH"}},
+		{"[이벤트] 신규 회원 이벤트 안내", "", nil},
+	}
+	for _, tt := range tests {
+		if got := ClassifyNotice(tt.title); got != tt.kind {
+			t.Errorf("ClassifyNotice(%q) = %q, want %q", tt.title, got, tt.kind)
+		}
+		if got := noticeSymbols(tt.title); tt.kind != "" && !reflect.DeepEqual(got, tt.symbols) {
+			t.Errorf("noticeSymbols(%q) = %v, want %v", tt.title, got, tt.symbols)
+		}
+	}
+}
+
+func TestNoticeClient_Poll(t *testing.T) {
+	kst := time.FixedZone("KST", 9*3600)
+	recent := time.Now().In(kst).Format(time.RFC3339)
+	old := time.Now().Add(-time.Hour).In(kst).Format(time.RFC3339)
+
+	body := fmt.Sprintf(`{"success":true,"data":{"notices":[
+		{"id":102,"title":"[입출금] 리플(XRP) 지갑 점검 안내","listed_at":%q},
+		{"id":101,"title":"[이벤트] 거래 이벤트","listed_at":%q},
+		{"id":100,"title":"[거래] 도지코인(DOGE) 신규 거래지원 안내","listed_at":%q}]}}`, recent, recent, old)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	inbox := make(chan event.Event, 8)
+	var notified []domain.Notice
+	client := NewNoticeClient(inbox, new(uint64), NoticeConfig{
+		URL:    server.URL,
+		Notify: func(n domain.Notice) { notified = append(notified, n) },
+	})
+
+	// First poll: the hour-old listing predates us, the event notice is not classified
+	client.poll(context.Background())
+	if len(inbox) != 1 {
+		t.Fatalf("expected 1 notice, got %d", len(inbox))
+	}
+	ev := (<-inbox).(*event.NoticeEvent)
+	if ev.NoticeID != 102 || ev.Kind != domain.NoticeWallet || ev.Source != NoticeSource || !reflect.DeepEqual(ev.Symbols, []string{"XRP"}) {
+		t.Errorf("unexpected notice event: %+v", ev)
+	}
+	if len(notified) != 1 || notified[0].ID != 102 {
+		t.Errorf("unexpected notifications: %+v", notified)
+	}
+
+	// Re-poll: nothing new
+	client.poll(context.Background())
+	if len(inbox) != 0 {
+		t.Errorf("expected no notices on re-poll, got %d", len(inbox))
+	}
+
+	// A new listing is emitted regardless of age after the first poll
+	body = fmt.Sprintf(`{"success":true,"data":{"notices":[{"id":103,"title":"[거래] 솔라나(SOL) 신규 거래지원 안내","listed_at":%q}]}}`, old)
+	client.poll(context.Background())
+	if len(inbox) != 1 || (<-inbox).(*event.NoticeEvent).Kind != domain.NoticeListing {
+		t.Error("expected the new listing notice")
+	}
+}
@@ -0,0 +1,89 @@
+package upbit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSigner_Token(t *testing.T) {
+	signer := NewSigner("access", "secret")
+
+	auth, err := signer.token("market=KRW-BTC&side=bid", "nonce-1")
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+	if !strings.HasPrefix(auth, "Bearer ") {
+		t.Fatalf("expected Bearer prefix, got %s", auth)
+	}
+	parts := strings.Split(strings.TrimPrefix(auth, "Bearer "), ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 JWT segments, got %d", len(parts))
+	}
+
+	// Signature: HMAC-SHA256(secret, header.payload), base64url without padding
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if want := base64.RawURLEncoding.EncodeToString(mac.Sum(nil)); parts[2] != want {
+		t.Errorf("signature mismatch: got %s, want %s", parts[2], want)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	var claims jwtPayload
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	sum := sha512.Sum512([]byte("market=KRW-BTC&side=bid"))
+	if claims.AccessKey != "access" || claims.Nonce != "nonce-1" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if claims.QueryHash != hex.EncodeToString(sum[:]) || claims.QueryHashAlg != "SHA512" {
+		t.Errorf("unexpected query hash: %+v", claims)
+	}
+}
+
+func TestSigner_TokenWithoutQuery(t *testing.T) {
+	signer := NewSigner("access", "secret")
+
+	auth, err := signer.token("", "nonce-1")
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+	payload := strings.Split(auth, ".")[1]
+	raw, _ := base64.RawURLEncoding.DecodeString(payload)
+	if strings.Contains(string(raw), "query_hash") {
+		t.Errorf("query_hash must be omitted without parameters: %s", raw)
+	}
+}
+
+func TestNewNonce(t *testing.T) {
+	a, err := newNonce()
+	if err != nil {
+		t.Fatalf("newNonce: %v", err)
+	}
+	b, _ := newNonce()
+	if a == b {
+		t.Error("nonces must not repeat")
+	}
+	if len(a) != 36 || a[14] != '4' {
+		t.Errorf("expected a UUIDv4, got %s", a)
+	}
+}
+
+func TestSigner_Wipe(t *testing.T) {
+	signer := NewSigner("access", "secret")
+	signer.Wipe()
+	for _, b := range signer.secretKey {
+		if b != 0 {
+			t.Fatal("secret key not wiped")
+		}
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"crypto_go/internal/domain"
 	"crypto_go/internal/event"
 	"crypto_go/internal/infra"
 	"crypto_go/pkg/quant"
@@ -30,25 +34,117 @@ type tickerResponse struct {
 	Timestamp         int64       `json:"timestamp"`
 }
 
+// orderbookResponse is an Upbit orderbook message. Upbit always sends the full
+// top-of-book, so each message is recorded as a snapshot.
+type orderbookResponse struct {
+	Type      string          `json:"type"` // orderbook
+	Code      string          `json:"code"`
+	Market    string          `json:"market"` // REST responses name the market here instead of code
+	Timestamp int64           `json:"timestamp"`
+	Units     []orderbookUnit `json:"orderbook_units"`
+}
+
+// restTickerResponse is one market of the REST /v1/ticker response.
+type restTickerResponse struct {
+	Market            string      `json:"market"`
+	TradePrice        json.Number `json:"trade_price"`
+	AccTradeVolume24h json.Number `json:"acc_trade_volume_24h"`
+	Timestamp         int64       `json:"timestamp"`
+}
+
+// tradeResponse is an Upbit trade message (one per trade).
+type tradeResponse struct {
+	Type           string      `json:"type"` // trade
+	Code           string      `json:"code"`
+	TradePrice     json.Number `json:"trade_price"`
+	TradeVolume    json.Number `json:"trade_volume"`
+	AskBid         string      `json:"ask_bid"`         // ASK = seller-initiated, BID = buyer-initiated
+	TradeTimestamp int64       `json:"trade_timestamp"` // Milliseconds
+}
+
+// candleResponse is an Upbit candle message. The open bar is re-sent on every trade.
+type candleResponse struct {
+	Type        string      `json:"type"` // candle.1m
+	Code        string      `json:"code"`
+	DateTimeUTC string      `json:"candle_date_time_utc"` // Bar open, 2006-01-02T15:04:05
+	Open        json.Number `json:"opening_price"`
+	High        json.Number `json:"high_price"`
+	Low         json.Number `json:"low_price"`
+	Close       json.Number `json:"trade_price"`
+	Volume      json.Number `json:"candle_acc_trade_volume"`
+	Timestamp   int64       `json:"timestamp"`
+}
+
+// candleTypes maps domain intervals to Upbit candle subscription types.
+var candleTypes = map[string]string{
+	domain.Candle1m: "candle.1m",
+	domain.Candle5m: "candle.5m",
+	domain.Candle1h: "candle.60m",
+}
+
+type orderbookUnit struct {
+	AskPrice json.Number `json:"ask_price"`
+	BidPrice json.Number `json:"bid_price"`
+	AskSize  json.Number `json:"ask_size"`
+	BidSize  json.Number `json:"bid_size"`
+}
+
 // Worker handles Upbit WebSocket connection using BaseWSWorker.
 type Worker struct {
-	base    *infra.BaseWSWorker
+	*infra.BaseWSWorker
 	symbols []string
-	inbox   chan<- event.Event
+	inbox   infra.Outbox
 	seq     *uint64
+	sendMu  sync.Mutex // Keeps seq order == inbox order while a REST snapshot races the stream
+
+	bookDepth int    // Order book levels per side (0 = orderbook not subscribed)
+	trades    bool   // Subscribe to the trade channel
+	candle    string // Candle interval (domain.Candle1m...; "" = not subscribed)
+
+	restURL    string
+	httpClient *http.Client
 }
 
 // NewWorker creates a new Upbit gateway worker.
 func NewWorker(symbols []string, inbox chan<- event.Event, seq *uint64) *Worker {
 	w := &Worker{
 		symbols: symbols,
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     seq,
+		restURL: BaseURL,
+
+		httpClient: infra.NewHTTPClient(10 * time.Second),
 	}
-	w.base = infra.NewBaseWSWorker(w)
+	w.BaseWSWorker = infra.NewBaseWSWorker(w)
+	w.Outbox = &w.inbox
 	return w
 }
 
+// SetOrderBookDepth subscribes to the orderbook channel, keeping up to depth levels per side.
+// Must be called before Connect.
+func (w *Worker) SetOrderBookDepth(depth int) {
+	w.bookDepth = depth
+}
+
+// SetTrades subscribes to the trade channel (one TradeEvent per trade).
+// Must be called before Connect.
+func (w *Worker) SetTrades(enabled bool) {
+	w.trades = enabled
+}
+
+// SetCandleInterval subscribes to candles of interval (domain.Candle1m,
+// Candle5m or Candle1h; "" = off). Must be called before Connect.
+func (w *Worker) SetCandleInterval(interval string) {
+	w.candle = interval
+}
+
+// SetRestURL overrides the REST endpoint used by Snapshot (default BaseURL).
+func (w *Worker) SetRestURL(u string) {
+	if u != "" {
+		w.restURL = u
+	}
+}
+
 // ID returns the worker identifier.
 func (w *Worker) ID() string { return "UPBIT" }
 
@@ -62,17 +158,90 @@ func (w *Worker) GetURL() string {
 
 // Connect starts the WebSocket connection.
 func (w *Worker) Connect(ctx context.Context) error {
-	w.base.Start(ctx)
+	w.BaseWSWorker.Start(ctx)
 	return nil
 }
 
 // Disconnect terminates the connection.
 func (w *Worker) Disconnect() {
-	w.base.Stop()
+	w.BaseWSWorker.Stop()
+}
+
+// Snapshot fetches the tickers (and, if subscribed, order books) of the
+// worker's markets from the public REST API and sends them into the inbox as
+// stream events, so a resync refreshes the engine state without waiting for
+// the stream. It fails if the inbox refuses an event.
+func (w *Worker) Snapshot(ctx context.Context) error {
+	codes := make([]string, 0, len(w.symbols))
+	for _, s := range w.symbols {
+		codes = append(codes, "KRW-"+s)
+	}
+	markets := url.QueryEscape(strings.Join(codes, ","))
+
+	var tickers []restTickerResponse
+	if err := w.getJSON(ctx, "/v1/ticker?markets="+markets, &tickers); err != nil {
+		return fmt.Errorf("upbit ticker snapshot: %w", err)
+	}
+	for _, t := range tickers {
+		ev := event.AcquireMarketUpdateEvent()
+		ev.Ts = quant.TimeStamp(t.Timestamp * 1000)
+		ev.Symbol = strings.TrimPrefix(t.Market, "KRW-")
+		ev.PriceMicros = quant.ToPriceMicrosStr(t.TradePrice.String())
+		ev.QtySats = quant.ToQtySatsStr(t.AccTradeVolume24h.String())
+		ev.Exchange = "UPBIT"
+		if !w.send(ev, &ev.BaseEvent) {
+			event.ReleaseMarketUpdateEvent(ev)
+			return fmt.Errorf("inbox full during ticker snapshot")
+		}
+	}
+
+	if w.bookDepth == 0 {
+		return nil
+	}
+	var books []orderbookResponse
+	if err := w.getJSON(ctx, "/v1/orderbook?markets="+markets, &books); err != nil {
+		return fmt.Errorf("upbit orderbook snapshot: %w", err)
+	}
+	for i := range books {
+		if ev := w.bookEvent(books[i].Market, books[i].Timestamp, books[i].Units); ev != nil && !w.send(ev, &ev.BaseEvent) {
+			return fmt.Errorf("inbox full during orderbook snapshot")
+		}
+	}
+	return nil
+}
+
+// getJSON decodes the public REST endpoint path into out.
+func (w *Worker) getJSON(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.restURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", infra.GetUserAgent())
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send stamps ev (whose BaseEvent is base) with the next source sequence and
+// publishes it, reporting whether the inbox accepted it.
+func (w *Worker) send(ev event.Event, base *event.BaseEvent) bool {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+	base.Seq = quant.NextSeq(w.seq)
+	return w.inbox.Send(ev)
 }
 
 // OnConnect handles the subscription logic after connection is established.
 func (w *Worker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	w.inbox.Reconnected()
 	codes := make([]string, 0, len(w.symbols))
 	for _, s := range w.symbols {
 		codes = append(codes, "KRW-"+s)
@@ -82,17 +251,41 @@ func (w *Worker) OnConnect(ctx context.Context, conn *websocket.Conn) error {
 		{"ticket": fmt.Sprintf("go-%d", time.Now().UnixNano())},
 		{"type": "ticker", "codes": codes},
 	}
+	if w.bookDepth > 0 {
+		msg = append(msg, map[string]interface{}{"type": "orderbook", "codes": codes})
+	}
+	if w.trades {
+		msg = append(msg, map[string]interface{}{"type": "trade", "codes": codes})
+	}
+	if typ, ok := candleTypes[w.candle]; ok {
+		msg = append(msg, map[string]interface{}{"type": typ, "codes": codes})
+	}
 	b, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal subscribe message: %w", err)
 	}
-	return w.base.Write(websocket.TextMessage, b)
+	return w.BaseWSWorker.Write(websocket.TextMessage, b)
 }
 
-// OnMessage handles incoming ticker updates.
+// OnMessage handles incoming ticker (and, if subscribed, orderbook, trade and candle) updates.
 func (w *Worker) OnMessage(ctx context.Context, msg []byte) {
 	var resp tickerResponse
-	if err := json.Unmarshal(msg, &resp); err != nil || resp.Type != "ticker" {
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+	if resp.Type == "orderbook" && w.bookDepth > 0 {
+		w.onOrderBook(msg)
+		return
+	}
+	if resp.Type == "trade" && w.trades {
+		w.onTrade(msg)
+		return
+	}
+	if w.candle != "" && resp.Type == candleTypes[w.candle] {
+		w.onCandle(msg)
+		return
+	}
+	if resp.Type != "ticker" {
 		return
 	}
 
@@ -100,24 +293,113 @@ func (w *Worker) OnMessage(ctx context.Context, msg []byte) {
 
 	// Optimization: Use Pool and int64 conversion (Rule #1, #3)
 	ev := event.AcquireMarketUpdateEvent()
-	ev.Seq = quant.NextSeq(w.seq)
 	ev.Ts = quant.TimeStamp(resp.Timestamp * 1000)
 	ev.Symbol = symbol
 	ev.PriceMicros = quant.ToPriceMicrosStr(resp.TradePrice.String())
 	ev.QtySats = quant.ToQtySatsStr(resp.AccTradeVolume24h.String())
 	ev.Exchange = "UPBIT"
 
-	select {
-	case w.inbox <- ev:
-	default:
+	if !w.send(ev, &ev.BaseEvent) {
 		// Drop if inbox is full, but release to pool to prevent leak.
 		event.ReleaseMarketUpdateEvent(ev)
 	}
 }
 
-// OnPing is called by BaseWSWorker. Upbit doesn't require explicit ping,
-// as it uses Pong frames, but we leave it as a no-op or default.
+// onOrderBook converts an orderbook message into a snapshot OrderBookUpdateEvent.
+// Not pooled: the Sequencer keeps no reference, but books are opt-in and far
+// less frequent than tickers.
+func (w *Worker) onOrderBook(msg []byte) {
+	var resp orderbookResponse
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+	if ev := w.bookEvent(resp.Code, resp.Timestamp, resp.Units); ev != nil {
+		w.send(ev, &ev.BaseEvent)
+	}
+}
+
+// bookEvent builds the (unstamped) snapshot of market code's book, or nil if
+// it has no levels.
+func (w *Worker) bookEvent(code string, tsMs int64, units []orderbookUnit) *event.OrderBookUpdateEvent {
+	if len(units) == 0 {
+		return nil
+	}
+	if len(units) > w.bookDepth {
+		units = units[:w.bookDepth]
+	}
+	ev := &event.OrderBookUpdateEvent{
+		Symbol:   strings.TrimPrefix(code, "KRW-"),
+		Exchange: "UPBIT",
+		Snapshot: true,
+		Bids:     make([]domain.BookLevel, 0, len(units)),
+		Asks:     make([]domain.BookLevel, 0, len(units)),
+	}
+	ev.Ts = quant.TimeStamp(tsMs * 1000)
+	for _, u := range units {
+		ev.Bids = append(ev.Bids, domain.BookLevel{
+			PriceMicros: quant.ToPriceMicrosStr(u.BidPrice.String()),
+			QtySats:     quant.ToQtySatsStr(u.BidSize.String()),
+		})
+		ev.Asks = append(ev.Asks, domain.BookLevel{
+			PriceMicros: quant.ToPriceMicrosStr(u.AskPrice.String()),
+			QtySats:     quant.ToQtySatsStr(u.AskSize.String()),
+		})
+	}
+	return ev
+}
+
+// onTrade converts a trade message into a TradeEvent.
+func (w *Worker) onTrade(msg []byte) {
+	var resp tradeResponse
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+
+	side := domain.SideBuy
+	if resp.AskBid == "ASK" {
+		side = domain.SideSell
+	}
+	ev := &event.TradeEvent{
+		Symbol:      strings.TrimPrefix(resp.Code, "KRW-"),
+		Exchange:    "UPBIT",
+		Side:        side,
+		PriceMicros: quant.ToPriceMicrosStr(resp.TradePrice.String()),
+		QtySats:     quant.ToQtySatsStr(resp.TradeVolume.String()),
+	}
+	ev.Ts = quant.TimeStamp(resp.TradeTimestamp * 1000)
+
+	w.send(ev, &ev.BaseEvent)
+}
+
+// onCandle converts a candle message into a CandleEvent.
+func (w *Worker) onCandle(msg []byte) {
+	var resp candleResponse
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+	open, err := time.Parse("2006-01-02T15:04:05", resp.DateTimeUTC)
+	if err != nil {
+		return
+	}
+
+	ev := &event.CandleEvent{
+		Exchange:    "UPBIT",
+		Symbol:      strings.TrimPrefix(resp.Code, "KRW-"),
+		Interval:    w.candle,
+		OpenTime:    quant.TimeStamp(open.UnixMicro()),
+		OpenMicros:  quant.ToPriceMicrosStr(resp.Open.String()),
+		HighMicros:  quant.ToPriceMicrosStr(resp.High.String()),
+		LowMicros:   quant.ToPriceMicrosStr(resp.Low.String()),
+		CloseMicros: quant.ToPriceMicrosStr(resp.Close.String()),
+		VolumeSats:  quant.ToQtySatsStr(resp.Volume.String()),
+	}
+	ev.Ts = quant.TimeStamp(resp.Timestamp * 1000)
+
+	w.send(ev, &ev.BaseEvent)
+}
+
+// OnPing is called by BaseWSWorker. Upbit answers WebSocket ping frames with
+// pong frames, which BaseWSWorker times for RTT measurement.
 func (w *Worker) OnPing(ctx context.Context, conn *websocket.Conn) error {
-	// Upbit doesn't need explicit application-level ping for ticker.
-	return nil
+	return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"crypto_go/internal/event"
+	"crypto_go/internal/infra"
 
 	"github.com/gorilla/websocket"
 )
@@ -68,7 +69,7 @@ func TestUpbitWorker_TickerParsing(t *testing.T) {
 	// Create worker with mock URL
 	worker := &Worker{
 		symbols: []string{"BTC"},
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     &seq,
 	}
 
@@ -103,7 +104,7 @@ func TestUpbitWorker_IgnoreNonTicker(t *testing.T) {
 
 	worker := &Worker{
 		symbols: []string{"BTC"},
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     &seq,
 	}
 
@@ -130,7 +131,7 @@ func TestUpbitWorker_SymbolExtraction(t *testing.T) {
 
 	worker := &Worker{
 		symbols: []string{"ETH", "BTC"},
-		inbox:   inbox,
+		inbox:   infra.NewOutbox(inbox),
 		seq:     &seq,
 	}
 
@@ -155,3 +156,159 @@ func TestUpbitWorker_SymbolExtraction(t *testing.T) {
 		t.Error("no event received")
 	}
 }
+
+func TestUpbitWorker_OrderBookRecording(t *testing.T) {
+	inbox := make(chan event.Event, 10)
+	var seq uint64 = 0
+
+	worker := &Worker{
+		symbols:   []string{"BTC"},
+		inbox:     infra.NewOutbox(inbox),
+		seq:       &seq,
+		bookDepth: 1,
+	}
+
+	msg := []byte(`{"type":"orderbook","code":"KRW-BTC","timestamp":1700000000000,"orderbook_units":[
+		{"ask_price":50001000,"bid_price":50000000,"ask_size":0.5,"bid_size":1.25},
+		{"ask_price":50002000,"bid_price":49999000,"ask_size":1,"bid_size":2}]}`)
+	worker.OnMessage(context.Background(), msg)
+
+	select {
+	case ev := <-inbox:
+		book, ok := ev.(*event.OrderBookUpdateEvent)
+		if !ok {
+			t.Fatalf("expected OrderBookUpdateEvent, got %T", ev)
+		}
+		if !book.Snapshot || book.Symbol != "BTC" || book.Exchange != "UPBIT" {
+			t.Errorf("unexpected header: %+v", book)
+		}
+		if len(book.Bids) != 1 || len(book.Asks) != 1 {
+			t.Fatalf("depth not applied: %d bids, %d asks", len(book.Bids), len(book.Asks))
+		}
+		if book.Bids[0].PriceMicros != 50000000*1e6 || book.Bids[0].QtySats != 125000000 {
+			t.Errorf("bid = %+v", book.Bids[0])
+		}
+		if book.Ts != 1700000000000000 {
+			t.Errorf("ts = %d", book.Ts)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("no event received")
+	}
+}
+
+func TestUpbitWorker_TradeParsing(t *testing.T) {
+	inbox := make(chan event.Event, 10)
+	var seq uint64 = 0
+
+	worker := &Worker{
+		symbols: []string{"BTC"},
+		inbox:   infra.NewOutbox(inbox),
+		seq:     &seq,
+		trades:  true,
+	}
+
+	worker.OnMessage(context.Background(), []byte(`{"type":"trade","code":"KRW-BTC","trade_price":50000000,
+		"trade_volume":0.015,"ask_bid":"ASK","trade_timestamp":1700000000123,"timestamp":1700000000200}`))
+
+	select {
+	case ev := <-inbox:
+		trade, ok := ev.(*event.TradeEvent)
+		if !ok {
+			t.Fatalf("expected TradeEvent, got %T", ev)
+		}
+		if trade.Symbol != "BTC" || trade.Exchange != "UPBIT" || trade.Side != "SELL" {
+			t.Errorf("unexpected header: %+v", trade)
+		}
+		if trade.PriceMicros != 50000000*1e6 || trade.QtySats != 1500000 {
+			t.Errorf("price/qty = %d / %d", trade.PriceMicros, trade.QtySats)
+		}
+		if trade.Ts != 1700000000123000 {
+			t.Errorf("ts = %d (must be the trade time)", trade.Ts)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("no event received")
+	}
+}
+
+func TestUpbitWorker_CandleParsing(t *testing.T) {
+	inbox := make(chan event.Event, 10)
+	var seq uint64 = 0
+
+	worker := &Worker{
+		symbols: []string{"BTC"},
+		inbox:   infra.NewOutbox(inbox),
+		seq:     &seq,
+		candle:  "5m",
+	}
+
+	worker.OnMessage(context.Background(), []byte(`{"type":"candle.5m","code":"KRW-BTC","candle_date_time_utc":"2024-01-01T00:05:00",
+		"opening_price":50000000,"high_price":50100000,"low_price":49900000,"trade_price":50050000,
+		"candle_acc_trade_volume":1.5,"timestamp":1704067530000}`))
+
+	select {
+	case ev := <-inbox:
+		c, ok := ev.(*event.CandleEvent)
+		if !ok {
+			t.Fatalf("expected CandleEvent, got %T", ev)
+		}
+		if c.Symbol != "BTC" || c.Exchange != "UPBIT" || c.Interval != "5m" {
+			t.Errorf("unexpected header: %+v", c)
+		}
+		if c.OpenTime != 1704067500000000 || c.Ts != 1704067530000000 {
+			t.Errorf("open/ts = %d / %d", c.OpenTime, c.Ts)
+		}
+		if c.HighMicros != 50100000*1e6 || c.CloseMicros != 50050000*1e6 || c.VolumeSats != 150000000 {
+			t.Errorf("ohlcv = %+v", c)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("no event received")
+	}
+}
+
+func TestUpbitWorker_Snapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("markets"); got != "KRW-BTC,KRW-ETH" {
+			t.Errorf("markets = %q", got)
+		}
+		switch r.URL.Path {
+		case "/v1/ticker":
+			w.Write([]byte(`[{"market":"KRW-BTC","trade_price":50000000,"acc_trade_volume_24h":1.5,"timestamp":1700000000000},
+				{"market":"KRW-ETH","trade_price":3000000,"acc_trade_volume_24h":10,"timestamp":1700000000001}]`))
+		case "/v1/orderbook":
+			w.Write([]byte(`[{"market":"KRW-BTC","timestamp":1700000000002,"orderbook_units":[
+				{"ask_price":50001000,"bid_price":50000000,"ask_size":0.5,"bid_size":1.25}]}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	inbox := make(chan event.Event, 10)
+	var seq uint64 = 7
+	worker := NewWorker([]string{"BTC", "ETH"}, inbox, &seq)
+	worker.SetOrderBookDepth(5)
+	worker.SetRestURL(server.URL)
+	if err := worker.Snapshot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(inbox) != 3 {
+		t.Fatalf("got %d events; want 2 tickers and 1 book", len(inbox))
+	}
+	btc := (<-inbox).(*event.MarketUpdateEvent)
+	if btc.Seq != 8 || btc.Symbol != "BTC" || btc.PriceMicros != 50000000*1e6 || btc.QtySats != 150000000 || btc.Exchange != "UPBIT" {
+		t.Errorf("unexpected BTC ticker: %+v", btc)
+	}
+	if eth := (<-inbox).(*event.MarketUpdateEvent); eth.Seq != 9 || eth.Symbol != "ETH" {
+		t.Errorf("unexpected ETH ticker: %+v", eth)
+	}
+	book := (<-inbox).(*event.OrderBookUpdateEvent)
+	if book.Seq != 10 || !book.Snapshot || book.Symbol != "BTC" || len(book.Bids) != 1 || book.Ts != 1700000000002000 {
+		t.Errorf("unexpected book snapshot: %+v", book)
+	}
+
+	worker.SetRestURL(server.URL + "/missing")
+	if err := worker.Snapshot(context.Background()); err == nil {
+		t.Error("expected an error for a failing endpoint")
+	}
+}
@@ -25,21 +25,32 @@ type WebSocketHandler interface {
 type BaseWSWorker struct {
 	handler WebSocketHandler
 	mu      sync.RWMutex
-	conn    *websocket.Conn
+	conn    *websocket.Conn // Current connection for Write and Reconnect, guarded by mu
 	writeMu sync.Mutex
 	cancel  context.CancelFunc
 	wg      sync.WaitGroup
 
 	ReadTimeout  time.Duration
-	PingInterval time.Duration
+	PingInterval time.Duration // Ping interval while healthy (upper bound)
+
+	// Adaptive keepalive: pings speed up to MinPingInterval once the smoothed
+	// RTT exceeds DegradedRTT or a pong is missed.
+	MinPingInterval time.Duration
+	DegradedRTT     time.Duration
+
+	keepalive *Keepalive // Per connection, guarded by mu
+
+	Outbox *Outbox // Where the handler publishes its events (SetLane, SetBackpressure)
 }
 
 // NewBaseWSWorker creates a new generic WebSocket worker.
 func NewBaseWSWorker(handler WebSocketHandler) *BaseWSWorker {
 	return &BaseWSWorker{
-		handler:      handler,
-		ReadTimeout:  60 * time.Second,
-		PingInterval: 30 * time.Second,
+		handler:         handler,
+		ReadTimeout:     60 * time.Second,
+		PingInterval:    30 * time.Second,
+		MinPingInterval: 5 * time.Second,
+		DegradedRTT:     time.Second,
 	}
 }
 
@@ -59,6 +70,31 @@ func (w *BaseWSWorker) Stop() {
 	w.wg.Wait()
 }
 
+// Reconnect drops the current connection; runLoop redials and resubscribes,
+// which gives the exchange a chance to resend a fresh snapshot. Non-blocking.
+func (w *BaseWSWorker) Reconnect() {
+	slog.Info("WS Reconnect requested", "id", w.handler.ID())
+	w.close()
+}
+
+// Resync forces a reconnect so subscriptions restart from a fresh snapshot.
+func (w *BaseWSWorker) Resync() {
+	w.Reconnect()
+}
+
+// SetLane publishes to lane (an engine.RingInbox) instead of the shared inbox
+// channel. Must be called before Start.
+func (w *BaseWSWorker) SetLane(lane EventLane) {
+	w.Outbox.Attach(lane)
+}
+
+// SetBackpressure counts the events a full inbox refuses, reports inbox
+// pressure and optionally conflates tickers (see Backpressure). Must be
+// called before Start.
+func (w *BaseWSWorker) SetBackpressure(bp Backpressure) {
+	w.Outbox.SetBackpressure(w.handler.ID(), bp)
+}
+
 func (w *BaseWSWorker) runLoop(ctx context.Context) {
 	defer w.wg.Done()
 	retry := 0
@@ -70,7 +106,8 @@ func (w *BaseWSWorker) runLoop(ctx context.Context) {
 		default:
 		}
 
-		if err := w.connect(ctx); err != nil {
+		conn, ka, err := w.connect(ctx)
+		if err != nil {
 			slog.Warn("WS Connection failed", "id", w.handler.ID(), "err", err, "retry", retry)
 			delay := CalculateBackoff(retry)
 			retry++
@@ -84,51 +121,84 @@ func (w *BaseWSWorker) runLoop(ctx context.Context) {
 		}
 
 		retry = 0 // Reset on successful connect
-		w.process(ctx)
+		w.serve(ctx, conn, ka)
 	}
 }
 
-func (w *BaseWSWorker) connect(ctx context.Context) error {
-	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+// serve runs the read and ping loops of one connection and returns once both
+// have stopped, so no goroutine outlives its connection.
+func (w *BaseWSWorker) serve(ctx context.Context, conn *websocket.Conn, ka *Keepalive) {
+	if w.PingInterval <= 0 {
+		w.readLoop(ctx, conn)
+		return
+	}
+
+	pingCtx, stopPing := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.pingLoop(pingCtx, conn, ka)
+	}()
+
+	w.readLoop(ctx, conn)
+	stopPing()
+	<-done
+}
+
+// connect dials, subscribes and publishes the connection for Write. The
+// connection and its keepalive are returned so the loops of this connection
+// never pick up a later one through w.conn.
+func (w *BaseWSWorker) connect(ctx context.Context) (*websocket.Conn, *Keepalive, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout:  10 * time.Second,
+		NetDialContext:    sharedDialContext,
+		NetDialTLSContext: sharedDialTLSContext,
+	}
 	header := make(http.Header)
 	header.Set("User-Agent", GetUserAgent())
 
 	conn, _, err := dialer.DialContext(ctx, w.handler.GetURL(), header)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
+	keepalive := NewKeepalive(w.MinPingInterval, w.PingInterval, w.DegradedRTT)
+	conn.SetPongHandler(func(string) error {
+		w.notifyPong(keepalive)
+		return nil
+	})
+
 	w.mu.Lock()
+	if ctx.Err() != nil {
+		// Stop ran while dialing and found no connection to close
+		w.mu.Unlock()
+		conn.Close()
+		return nil, nil, ctx.Err()
+	}
 	w.conn = conn
+	w.keepalive = keepalive
 	w.mu.Unlock()
 
 	if err := w.handler.OnConnect(ctx, conn); err != nil {
-		w.close()
-		return fmt.Errorf("OnConnect failed: %w", err)
-	}
-
-	if w.PingInterval > 0 {
-		go w.pingLoop(ctx)
+		w.closeConn(conn)
+		return nil, nil, fmt.Errorf("OnConnect failed: %w", err)
 	}
 
 	slog.Info("WS Connected", "id", w.handler.ID())
-	return nil
+	return conn, keepalive, nil
 }
 
-func (w *BaseWSWorker) process(ctx context.Context) {
+// readLoop reads conn until it fails or is closed by Stop, Reconnect or the
+// ping loop. Only readLoop reads from and sets read deadlines on conn.
+func (w *BaseWSWorker) readLoop(ctx context.Context, conn *websocket.Conn) {
 	for {
-		w.mu.RLock()
-		c := w.conn
-		w.mu.RUnlock()
-		if c == nil {
-			return
-		}
-
-		c.SetReadDeadline(time.Now().Add(w.ReadTimeout))
-		_, msg, err := c.ReadMessage()
+		conn.SetReadDeadline(time.Now().Add(w.ReadTimeout))
+		_, msg, err := conn.ReadMessage()
 		if err != nil {
-			slog.Warn("WS Read error", "id", w.handler.ID(), "err", err)
-			w.close()
+			if ctx.Err() == nil {
+				slog.Warn("WS Read error", "id", w.handler.ID(), "err", err)
+			}
+			w.closeConn(conn)
 			return
 		}
 
@@ -136,30 +206,82 @@ func (w *BaseWSWorker) process(ctx context.Context) {
 	}
 }
 
-func (w *BaseWSWorker) pingLoop(ctx context.Context) {
-	ticker := time.NewTicker(w.PingInterval)
-	defer ticker.Stop()
+func (w *BaseWSWorker) pingLoop(ctx context.Context, conn *websocket.Conn, ka *Keepalive) {
+	timer := time.NewTimer(ka.NextInterval())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			w.mu.RLock()
-			c := w.conn
-			w.mu.RUnlock()
-			if c == nil {
+		case <-timer.C:
+			wasDegraded := ka.Degraded()
+			if missed := ka.PingSent(time.Now()); missed >= keepaliveMaxMissed {
+				slog.Warn("WS Pong missing, reconnecting", "id", w.handler.ID(), "missed", missed)
+				w.closeConn(conn)
 				return
 			}
-			if err := w.handler.OnPing(ctx, c); err != nil {
+			w.reportHealth(ka, wasDegraded)
+
+			if err := w.handler.OnPing(ctx, conn); err != nil {
 				slog.Warn("WS Ping error", "id", w.handler.ID(), "err", err)
-				w.close()
+				w.closeConn(conn)
 				return
 			}
+			timer.Reset(ka.NextInterval())
 		}
 	}
 }
 
+// NotifyPong must be called by handlers that receive application-level pongs
+// (e.g. Bitget's "pong" text). Control-frame pongs are handled automatically.
+func (w *BaseWSWorker) NotifyPong() {
+	w.mu.RLock()
+	ka := w.keepalive
+	w.mu.RUnlock()
+	if ka != nil {
+		w.notifyPong(ka)
+	}
+}
+
+// notifyPong records a pong on the keepalive of the connection it arrived on.
+func (w *BaseWSWorker) notifyPong(ka *Keepalive) {
+	wasDegraded := ka.Degraded()
+	rtt, ok := ka.PongReceived(time.Now())
+	if !ok {
+		return
+	}
+	_, srtt := ka.RTT()
+	GlobalMetrics.RecordRTT(w.handler.ID(), rtt, srtt)
+	w.reportHealth(ka, wasDegraded)
+}
+
+// RTT returns the last and smoothed round trip time of the current connection.
+func (w *BaseWSWorker) RTT() (last, smoothed time.Duration) {
+	w.mu.RLock()
+	ka := w.keepalive
+	w.mu.RUnlock()
+	if ka == nil {
+		return 0, 0
+	}
+	return ka.RTT()
+}
+
+// reportHealth logs and exports degraded/recovered transitions.
+func (w *BaseWSWorker) reportHealth(ka *Keepalive, wasDegraded bool) {
+	degraded := ka.Degraded()
+	if degraded == wasDegraded {
+		return
+	}
+	GlobalMetrics.SetConnectionDegraded(w.handler.ID(), degraded)
+	_, srtt := ka.RTT()
+	if degraded {
+		slog.Warn("WS Connection degrading", "id", w.handler.ID(), "srtt", srtt)
+	} else {
+		slog.Info("WS Connection recovered", "id", w.handler.ID(), "srtt", srtt)
+	}
+}
+
 func (w *BaseWSWorker) Write(msgType int, data []byte) error {
 	w.writeMu.Lock()
 	defer w.writeMu.Unlock()
@@ -175,11 +297,24 @@ func (w *BaseWSWorker) Write(msgType int, data []byte) error {
 	return c.WriteMessage(msgType, data)
 }
 
+// close closes the current connection, if any.
 func (w *BaseWSWorker) close() {
 	w.mu.Lock()
-	defer w.mu.Unlock()
-	if w.conn != nil {
-		w.conn.Close()
+	c := w.conn
+	w.conn = nil
+	w.mu.Unlock()
+	if c != nil {
+		c.Close()
+	}
+}
+
+// closeConn closes conn and unpublishes it if it is still the current
+// connection. A loop of a replaced connection never closes its successor.
+func (w *BaseWSWorker) closeConn(conn *websocket.Conn) {
+	w.mu.Lock()
+	if w.conn == conn {
 		w.conn = nil
 	}
+	w.mu.Unlock()
+	conn.Close()
 }
@@ -5,10 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"crypto_go/internal/event"
+
 	"github.com/gorilla/websocket"
 )
 
@@ -155,3 +158,229 @@ func TestBaseWSWorker_Write(t *testing.T) {
 
 	worker.Stop()
 }
+
+// pingHandler sends WebSocket ping frames like the Upbit worker.
+type pingHandler struct{ mockHandler }
+
+func (p *pingHandler) OnPing(ctx context.Context, conn *websocket.Conn) error {
+	return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second))
+}
+
+func TestBaseWSWorker_MeasuresRTT(t *testing.T) {
+	// Server keeps reading so gorilla's default ping handler answers with pongs
+	server := createMockWSServer(t, func(conn *websocket.Conn) {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	handler := &pingHandler{mockHandler{url: httpToWS(server.URL)}}
+	worker := NewBaseWSWorker(handler)
+	worker.PingInterval = 20 * time.Millisecond
+	worker.MinPingInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	worker.Start(ctx)
+	defer worker.Stop()
+
+	for ctx.Err() == nil {
+		if last, smoothed := worker.RTT(); last > 0 && smoothed > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no RTT measured from ping/pong")
+}
+
+// streamServer floods every connection with messages until the client goes
+// away, so reads are always in flight when the worker reconnects or stops.
+func streamServer(t *testing.T) *httptest.Server {
+	return createMockWSServer(t, func(conn *websocket.Conn) {
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		for {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"tick"}`)); err != nil {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
+
+// countingHandler counts callbacks and pings like the Upbit worker.
+type countingHandler struct {
+	url      string
+	connects atomic.Int32
+	messages atomic.Int64
+	pings    atomic.Int64
+}
+
+func (h *countingHandler) GetURL() string { return h.url }
+func (h *countingHandler) ID() string     { return "MOCK" }
+func (h *countingHandler) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	h.connects.Add(1)
+	return nil
+}
+func (h *countingHandler) OnMessage(ctx context.Context, msg []byte) { h.messages.Add(1) }
+func (h *countingHandler) OnPing(ctx context.Context, conn *websocket.Conn) error {
+	h.pings.Add(1)
+	return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second))
+}
+
+// Run with -race: Reconnect, Write and the keepalive accessors race the read
+// and ping loops of connections being replaced.
+func TestBaseWSWorker_ConcurrentReconnect(t *testing.T) {
+	server := streamServer(t)
+	defer server.Close()
+
+	handler := &countingHandler{url: httpToWS(server.URL)}
+	worker := NewBaseWSWorker(handler)
+	worker.PingInterval = 5 * time.Millisecond
+	worker.MinPingInterval = time.Millisecond
+
+	worker.Start(context.Background())
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				switch i {
+				case 0:
+					worker.Reconnect()
+					time.Sleep(20 * time.Millisecond)
+				case 1:
+					_ = worker.Write(websocket.TextMessage, []byte(`{"op":"ping"}`))
+				case 2:
+					worker.NotifyPong()
+				case 3:
+					worker.RTT()
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}(i)
+	}
+	wg.Wait()
+	worker.Stop()
+
+	if handler.connects.Load() < 2 {
+		t.Errorf("expected reconnects, got %d connects", handler.connects.Load())
+	}
+	if handler.messages.Load() == 0 {
+		t.Error("no messages read")
+	}
+}
+
+func TestBaseWSWorker_StopWaitsForLoops(t *testing.T) {
+	server := streamServer(t)
+	defer server.Close()
+
+	handler := &countingHandler{url: httpToWS(server.URL)}
+	worker := NewBaseWSWorker(handler)
+	worker.PingInterval = 2 * time.Millisecond
+	worker.MinPingInterval = time.Millisecond
+
+	worker.Start(context.Background())
+	for handler.pings.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	worker.Stop()
+
+	messages, pings := handler.messages.Load(), handler.pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	if handler.messages.Load() != messages || handler.pings.Load() != pings {
+		t.Error("read or ping loop still running after Stop returned")
+	}
+	if err := worker.Write(websocket.TextMessage, []byte("x")); err == nil {
+		t.Error("Write after Stop must fail")
+	}
+}
+
+// stalePingHandler fails the first ping of its first connection only after
+// that connection was replaced (or a timeout, if the worker waits for it).
+type stalePingHandler struct {
+	countingHandler
+	mu      sync.Mutex
+	first   *websocket.Conn
+	pinging chan struct{} // Closed once the stale ping is in flight
+	next    chan struct{} // Closed once the successor connected
+}
+
+func (h *stalePingHandler) OnConnect(ctx context.Context, conn *websocket.Conn) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch h.connects.Load() {
+	case 0:
+		h.first = conn
+	case 1:
+		close(h.next)
+	}
+	h.connects.Add(1)
+	return nil
+}
+
+func (h *stalePingHandler) OnPing(ctx context.Context, conn *websocket.Conn) error {
+	h.mu.Lock()
+	stale := conn == h.first
+	h.first = nil
+	h.mu.Unlock()
+	if !stale {
+		return h.countingHandler.OnPing(ctx, conn)
+	}
+	close(h.pinging)
+	select {
+	case <-h.next:
+	case <-time.After(100 * time.Millisecond):
+	}
+	return websocket.ErrCloseSent
+}
+
+func TestBaseWSWorker_StaleLoopKeepsSuccessor(t *testing.T) {
+	server := streamServer(t)
+	defer server.Close()
+
+	handler := &stalePingHandler{countingHandler: countingHandler{url: httpToWS(server.URL)},
+		pinging: make(chan struct{}),
+		next:    make(chan struct{}),
+	}
+	worker := NewBaseWSWorker(handler)
+	worker.PingInterval = 10 * time.Millisecond
+	worker.MinPingInterval = 10 * time.Millisecond
+	worker.Start(context.Background())
+	defer worker.Stop()
+
+	<-handler.pinging
+	worker.Reconnect()
+
+	time.Sleep(300 * time.Millisecond)
+	if n := handler.connects.Load(); n != 2 {
+		t.Errorf("the failed ping of a replaced connection closed its successor: %d connects", n)
+	}
+}
+
+func TestBaseWSWorker_OutboxSetters(t *testing.T) {
+	out := NewOutbox(make(chan event.Event))
+	w := NewBaseWSWorker(&mockHandler{})
+	w.Outbox = &out
+
+	w.SetBackpressure(Backpressure{})
+	if out.bp == nil || out.bp.gateway != "MOCK" {
+		t.Fatal("SetBackpressure must configure the outbox under the handler's ID")
+	}
+	sent := 0
+	w.SetLane(laneFunc(func(event.Event) bool { sent++; return true }))
+	if !out.Send(&event.MarketUpdateEvent{}) || sent != 1 {
+		t.Error("SetLane must route the outbox to the lane")
+	}
+}
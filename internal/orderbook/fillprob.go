@@ -0,0 +1,283 @@
+package orderbook
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+const fillBpsScale = 10_000
+
+// Default grid of a fill model.
+var (
+	DefaultFillDistancesBps = []int64{0, 5, 10, 25, 50, 100}
+	DefaultFillHorizons     = []time.Duration{10 * time.Second, time.Minute, 5 * time.Minute, 30 * time.Minute}
+)
+
+// DefaultFillSampleEvery is the minimum time between two book samples.
+const DefaultFillSampleEvery = time.Second
+
+// FillModelConfig is the grid a FillModel is estimated on. Zero values use the defaults.
+type FillModelConfig struct {
+	DistancesBps []int64         // Passive distances from mid, ascending
+	Horizons     []time.Duration // Resting times (time in force), ascending
+	SampleEvery  time.Duration
+}
+
+// FillModel is the empirical probability that a passive LIMIT order fills:
+// for a BUY resting DistancesBps below mid (SELL: above), the share of book
+// samples after which a trade printed at or through its price within each
+// horizon. Cells are [horizon][distance] fill counts out of Samples.
+type FillModel struct {
+	Exchange     string    `json:"exchange"`
+	Symbol       string    `json:"symbol"`
+	DistancesBps []int64   `json:"distances_bps"`
+	HorizonsMs   []int64   `json:"horizons_ms"`
+	Samples      int64     `json:"samples"`
+	BuyFills     [][]int64 `json:"buy_fills"`
+	SellFills    [][]int64 `json:"sell_fills"`
+}
+
+// FillProbBps returns the probability (bps) that an order on side resting
+// distanceBps from mid fills within horizon. It answers from the longest
+// horizon not above horizon and the nearest distance not below distanceBps,
+// so it never overstates; ok is false outside the grid or without samples.
+// Marketable orders (negative distance) always fill.
+func (m *FillModel) FillProbBps(side string, distanceBps int64, horizon time.Duration) (int64, bool) {
+	if distanceBps < 0 {
+		return fillBpsScale, true
+	}
+	if m == nil || m.Samples == 0 {
+		return 0, false
+	}
+	h := -1
+	for i, ms := range m.HorizonsMs {
+		if time.Duration(ms)*time.Millisecond <= horizon {
+			h = i
+		}
+	}
+	d := slices.IndexFunc(m.DistancesBps, func(bps int64) bool { return bps >= distanceBps })
+	if h < 0 || d < 0 {
+		return 0, false
+	}
+	fills := m.BuyFills
+	if side == domain.SideSell {
+		fills = m.SellFills
+	}
+	return safe.SafeMulDiv(fills[h][d], fillBpsScale, m.Samples), true
+}
+
+// Save writes the model as JSON.
+func (m *FillModel) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fill model: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadFillModel reads a model written by Save.
+func LoadFillModel(path string) (*FillModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fill model: %w", err)
+	}
+	var m FillModel
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse fill model %s: %w", path, err)
+	}
+	if len(m.BuyFills) != len(m.HorizonsMs) || len(m.SellFills) != len(m.HorizonsMs) {
+		return nil, fmt.Errorf("fill model %s: fills do not match its horizons", path)
+	}
+	for h := range m.HorizonsMs {
+		if len(m.BuyFills[h]) != len(m.DistancesBps) || len(m.SellFills[h]) != len(m.DistancesBps) {
+			return nil, fmt.Errorf("fill model %s: fills do not match its distances", path)
+		}
+	}
+	return &m, nil
+}
+
+// fillSample is one book sample waiting for its longest horizon to pass.
+type fillSample struct {
+	ts        quant.TimeStamp
+	mid       int64
+	low, high []int64 // Per horizon: lowest / highest trade price since ts (0 = none)
+}
+
+// FillEstimator builds a FillModel from one venue/symbol's order book updates
+// and trades, fed in recorded order. Only samples whose longest horizon has
+// passed are counted.
+type FillEstimator struct {
+	model       FillModel
+	horizons    []quant.TimeStamp
+	sampleEvery quant.TimeStamp
+
+	book       *domain.OrderBook
+	open       []*fillSample
+	lastSample quant.TimeStamp
+}
+
+// NewFillEstimator creates an estimator for exchange/symbol.
+func NewFillEstimator(exchange, symbol string, cfg FillModelConfig) *FillEstimator {
+	if len(cfg.DistancesBps) == 0 {
+		cfg.DistancesBps = DefaultFillDistancesBps
+	}
+	if len(cfg.Horizons) == 0 {
+		cfg.Horizons = DefaultFillHorizons
+	}
+	if cfg.SampleEvery <= 0 {
+		cfg.SampleEvery = DefaultFillSampleEvery
+	}
+	e := &FillEstimator{
+		model: FillModel{
+			Exchange:     exchange,
+			Symbol:       symbol,
+			DistancesBps: slices.Clone(cfg.DistancesBps),
+			BuyFills:     make([][]int64, len(cfg.Horizons)),
+			SellFills:    make([][]int64, len(cfg.Horizons)),
+		},
+		sampleEvery: quant.TimeStamp(cfg.SampleEvery.Microseconds()),
+		book:        domain.NewOrderBook(exchange, symbol),
+	}
+	for i, h := range cfg.Horizons {
+		e.model.HorizonsMs = append(e.model.HorizonsMs, h.Milliseconds())
+		e.horizons = append(e.horizons, quant.TimeStamp(h.Microseconds()))
+		e.model.BuyFills[i] = make([]int64, len(cfg.DistancesBps))
+		e.model.SellFills[i] = make([]int64, len(cfg.DistancesBps))
+	}
+	return e
+}
+
+// ObserveBook applies a book update and samples the mid at most once per
+// SampleEvery. Other venues and symbols are ignored.
+func (e *FillEstimator) ObserveBook(ev *event.OrderBookUpdateEvent) {
+	if ev.Exchange != e.model.Exchange || ev.Symbol != e.model.Symbol {
+		return
+	}
+	e.expire(ev.Ts)
+	e.book.Apply(ev.Snapshot, ev.Bids, ev.Asks, ev.Ts)
+	if !e.book.Synced || (e.lastSample != 0 && ev.Ts-e.lastSample < e.sampleEvery) {
+		return
+	}
+	bid, okBid := e.book.BestBid()
+	ask, okAsk := e.book.BestAsk()
+	if !okBid || !okAsk {
+		return
+	}
+	e.lastSample = ev.Ts
+	e.open = append(e.open, &fillSample{
+		ts:   ev.Ts,
+		mid:  (int64(bid.PriceMicros) + int64(ask.PriceMicros)) / 2,
+		low:  make([]int64, len(e.horizons)),
+		high: make([]int64, len(e.horizons)),
+	})
+}
+
+// ObserveTrade records a trade print against every open sample.
+func (e *FillEstimator) ObserveTrade(ev *event.TradeEvent) {
+	if ev.Exchange != e.model.Exchange || ev.Symbol != e.model.Symbol {
+		return
+	}
+	e.expire(ev.Ts)
+	price := int64(ev.PriceMicros)
+	for _, s := range e.open {
+		age := ev.Ts - s.ts
+		if age <= 0 {
+			continue
+		}
+		for h, horizon := range e.horizons {
+			if age > horizon {
+				continue
+			}
+			if s.low[h] == 0 || price < s.low[h] {
+				s.low[h] = price
+			}
+			s.high[h] = max(s.high[h], price)
+		}
+	}
+}
+
+// expire counts the samples whose longest horizon ended before now.
+func (e *FillEstimator) expire(now quant.TimeStamp) {
+	longest := e.horizons[len(e.horizons)-1]
+	n := 0
+	for ; n < len(e.open) && now-e.open[n].ts > longest; n++ {
+		s := e.open[n]
+		e.model.Samples++
+		for h := range e.horizons {
+			for d, bps := range e.model.DistancesBps {
+				if s.low[h] > 0 && s.low[h] <= safe.SafeMulDiv(s.mid, fillBpsScale-bps, fillBpsScale) {
+					e.model.BuyFills[h][d]++
+				}
+				if s.high[h] >= safe.SafeMulDiv(s.mid, fillBpsScale+bps, fillBpsScale) {
+					e.model.SellFills[h][d]++
+				}
+			}
+		}
+	}
+	e.open = slices.Delete(e.open, 0, n)
+}
+
+// Model returns the model of the samples counted so far.
+func (e *FillEstimator) Model() *FillModel {
+	m := e.model
+	m.DistancesBps = slices.Clone(m.DistancesBps)
+	m.HorizonsMs = slices.Clone(m.HorizonsMs)
+	m.BuyFills = cloneCells(m.BuyFills)
+	m.SellFills = cloneCells(m.SellFills)
+	return &m
+}
+
+func cloneCells(cells [][]int64) [][]int64 {
+	out := make([][]int64, len(cells))
+	for i, row := range cells {
+		out[i] = slices.Clone(row)
+	}
+	return out
+}
+
+// EstimateFills builds the fill model of exchange/symbol from the order books
+// and trades recorded in [from, to). Sampling starts at the first book
+// snapshot in the range, so engine.orderbook must have been on.
+func EstimateFills(ctx context.Context, store *storage.EventStore, exchange, symbol string, from, to quant.TimeStamp, cfg FillModelConfig) (*FillModel, error) {
+	est := NewFillEstimator(exchange, symbol, cfg)
+	rows, err := store.DB().QueryContext(ctx,
+		"SELECT id, type, payload FROM events WHERE type IN (?, ?) AND ts >= ? AND ts < ? ORDER BY id ASC",
+		event.EvOrderBook, event.EvTrade, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query book and trade events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var typ event.Type
+		var payload []byte
+		if err := rows.Scan(&id, &typ, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		decoded, err := event.Decode(typ, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %d: %w", id, err)
+		}
+		switch ev := decoded.(type) {
+		case *event.OrderBookUpdateEvent:
+			est.ObserveBook(ev)
+		case *event.TradeEvent:
+			est.ObserveTrade(ev)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return est.Model(), nil
+}
@@ -0,0 +1,96 @@
+package orderbook
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sec = quant.TimeStamp(1_000_000)
+
+func TestEstimateFills(t *testing.T) {
+	store, err := storage.NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	book := func(seq uint64, ts quant.TimeStamp, snapshot bool) *event.OrderBookUpdateEvent {
+		return &event.OrderBookUpdateEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: ts}, Exchange: "UPBIT", Symbol: "BTC", Snapshot: snapshot,
+			Bids: []domain.BookLevel{lvl(9_999, 1)}, Asks: []domain.BookLevel{lvl(10_001, 1)}}
+	}
+	trade := func(seq uint64, ts quant.TimeStamp, price int64) *event.TradeEvent {
+		return &event.TradeEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: ts}, Exchange: "UPBIT", Symbol: "BTC", PriceMicros: quant.PriceMicros(price)}
+	}
+	// Samples at 0s and 2s (mid 10,000); the sample at 13s has not matured
+	events := []event.Event{
+		book(1, 0, true),
+		trade(2, sec/2, 9_950), // 0.5% below mid, within 1s of the first sample
+		book(3, 2*sec, false),
+		trade(4, 5*sec, 10_150), // 1.5% above, within 10s of both
+		&event.OrderBookUpdateEvent{BaseEvent: event.BaseEvent{Seq: 5, Ts: 3 * sec}, Exchange: "UPBIT", Symbol: "ETH", Snapshot: true},
+		book(6, 13*sec, false),
+	}
+	for _, ev := range events {
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := EstimateFills(ctx, store, "UPBIT", "BTC", 0, 20*sec, FillModelConfig{
+		DistancesBps: []int64{0, 100},
+		Horizons:     []time.Duration{time.Second, 10 * time.Second},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Samples != 2 {
+		t.Fatalf("Samples = %d; want 2", m.Samples)
+	}
+	if want := [][]int64{{1, 0}, {1, 0}}; !reflect.DeepEqual(m.BuyFills, want) {
+		t.Errorf("BuyFills = %v; want %v", m.BuyFills, want)
+	}
+	if want := [][]int64{{0, 0}, {2, 2}}; !reflect.DeepEqual(m.SellFills, want) {
+		t.Errorf("SellFills = %v; want %v", m.SellFills, want)
+	}
+
+	cases := []struct {
+		side     string
+		distance int64
+		horizon  time.Duration
+		want     int64
+		ok       bool
+	}{
+		{domain.SideBuy, 0, time.Second, 5_000, true},
+		{domain.SideSell, 50, 10 * time.Second, 10_000, true}, // Answered by the 1% column
+		{domain.SideSell, 0, 5 * time.Second, 0, true},        // Answered by the 1s row
+		{domain.SideBuy, 0, 500 * time.Millisecond, 0, false},
+		{domain.SideBuy, 200, time.Minute, 0, false},
+		{domain.SideBuy, -5, time.Millisecond, 10_000, true},
+	}
+	for _, c := range cases {
+		got, ok := m.FillProbBps(c.side, c.distance, c.horizon)
+		if got != c.want || ok != c.ok {
+			t.Errorf("FillProbBps(%s, %d, %v) = %d, %v; want %d, %v", c.side, c.distance, c.horizon, got, ok, c.want, c.ok)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "fill.json")
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFillModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, m) {
+		t.Errorf("loaded %+v; want %+v", loaded, m)
+	}
+}
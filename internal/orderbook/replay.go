@@ -0,0 +1,56 @@
+// Package orderbook rebuilds recorded L2 order books from the WAL.
+package orderbook
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"fmt"
+)
+
+// Reconstruct replays the recorded order book updates of exchange/symbol from
+// the WAL and returns the book as it stood at `at` (inclusive). The result is
+// not Synced if no snapshot was recorded before `at`.
+func Reconstruct(ctx context.Context, store *storage.EventStore, exchange, symbol string, at quant.TimeStamp) (*domain.OrderBook, error) {
+	book := domain.NewOrderBook(exchange, symbol)
+
+	rows, err := store.DB().QueryContext(ctx,
+		"SELECT id, payload FROM events WHERE type = ? AND ts <= ? ORDER BY id ASC",
+		event.EvOrderBook, at,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order book events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*event.OrderBookUpdateEvent
+	for rows.Next() {
+		var id uint64
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		decoded, err := event.Decode(event.EvOrderBook, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal order book event %d: %w", id, err)
+		}
+		ev := decoded.(*event.OrderBookUpdateEvent)
+		if ev.Exchange != exchange || ev.Symbol != symbol {
+			continue
+		}
+		if ev.Snapshot {
+			events = events[:0] // Everything before the latest snapshot is superseded
+		}
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	for _, ev := range events {
+		book.Apply(ev.Snapshot, ev.Bids, ev.Asks, ev.Ts)
+	}
+	return book, nil
+}
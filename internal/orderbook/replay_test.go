@@ -0,0 +1,64 @@
+package orderbook
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"path/filepath"
+	"testing"
+)
+
+func lvl(price, qty int64) domain.BookLevel {
+	return domain.BookLevel{PriceMicros: quant.PriceMicros(price), QtySats: quant.QtySats(qty)}
+}
+
+func TestReconstruct_AtTimestamp(t *testing.T) {
+	store, err := storage.NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	events := []*event.OrderBookUpdateEvent{
+		{BaseEvent: event.BaseEvent{Seq: 1, Ts: 100}, Exchange: "UPBIT", Symbol: "BTC", Snapshot: true,
+			Bids: []domain.BookLevel{lvl(100, 1)}, Asks: []domain.BookLevel{lvl(101, 1)}},
+		{BaseEvent: event.BaseEvent{Seq: 2, Ts: 200}, Exchange: "UPBIT", Symbol: "ETH", Snapshot: true,
+			Bids: []domain.BookLevel{lvl(10, 1)}},
+		{BaseEvent: event.BaseEvent{Seq: 3, Ts: 300}, Exchange: "UPBIT", Symbol: "BTC",
+			Bids: []domain.BookLevel{lvl(100, 4)}},
+		{BaseEvent: event.BaseEvent{Seq: 4, Ts: 400}, Exchange: "UPBIT", Symbol: "BTC", Snapshot: true,
+			Bids: []domain.BookLevel{lvl(105, 1)}, Asks: []domain.BookLevel{lvl(106, 1)}},
+	}
+	for _, ev := range events {
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, err := Reconstruct(ctx, store, "UPBIT", "BTC", 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Synced {
+		t.Error("book before the first snapshot should not be synced")
+	}
+
+	mid, err := Reconstruct(ctx, store, "UPBIT", "BTC", 350)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bid, _ := mid.BestBid(); bid != lvl(100, 4) {
+		t.Errorf("book at 350: best bid = %+v, want 100x4", bid)
+	}
+
+	last, err := Reconstruct(ctx, store, "UPBIT", "BTC", 400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bid, _ := last.BestBid(); bid != lvl(105, 1) || len(last.Bids(0)) != 1 {
+		t.Errorf("book at 400: bids = %+v, want only 105x1", last.Bids(0))
+	}
+}
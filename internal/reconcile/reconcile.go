@@ -0,0 +1,310 @@
+package reconcile
+
+import (
+	"cmp"
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Kinds of discrepancy.
+const (
+	MissingLocal    = "missing_local"    // On the statement, not in the journal
+	MissingVenue    = "missing_venue"    // Filled in the journal, not on the statement
+	QtyMismatch     = "qty_mismatch"     // Filled quantity differs
+	PriceMismatch   = "price_mismatch"   // Average fill price differs beyond tolerance
+	BalanceMismatch = "balance_mismatch" // Booked venue balance differs
+	BalanceUnknown  = "balance_unknown"  // No booked balance at or before the statement line
+)
+
+// LocalOrder is an order's fills as recorded by its order updates.
+type LocalOrder struct {
+	OrderID        string
+	LastFill       quant.TimeStamp
+	QtySats        int64
+	NotionalMicros int64 // Sum of fill increments x reported price
+}
+
+// AvgPriceMicros returns the volume-weighted fill price (0 without fills).
+func (o *LocalOrder) AvgPriceMicros() int64 {
+	if o.QtySats == 0 {
+		return 0
+	}
+	return safe.SafeMulDiv(o.NotionalMicros, quant.QtyScale, o.QtySats)
+}
+
+// BalanceReading is a venue balance booked at a resync.
+type BalanceReading struct {
+	Time       quant.TimeStamp
+	Seq        uint64
+	AmountSats int64
+}
+
+// Local is what the engine recorded over a statement's period.
+type Local struct {
+	Orders   map[string]*LocalOrder      // Orders with fills, by client order ID
+	Balances map[string][]BalanceReading // By currency, in time order
+}
+
+// LoadLocal reads the order updates in [from, to] and the balances booked
+// by source's resyncs up to to from store.
+func LoadLocal(ctx context.Context, store *storage.EventStore, source string, from, to quant.TimeStamp) (*Local, error) {
+	local := &Local{Orders: make(map[string]*LocalOrder), Balances: make(map[string][]BalanceReading)}
+	rows, err := store.DB().QueryContext(ctx,
+		"SELECT id, type, ts, payload FROM events WHERE (type = ? AND ts >= ? AND ts <= ?) OR (type = ? AND ts <= ?) ORDER BY id ASC",
+		event.EvOrderUpdate, from, to, event.EvResync, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order updates and resyncs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var typ event.Type
+		var ts quant.TimeStamp
+		var payload []byte
+		if err := rows.Scan(&id, &typ, &ts, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		decoded, err := event.Decode(typ, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %d: %w", id, err)
+		}
+		switch ev := decoded.(type) {
+		case *event.OrderUpdateEvent:
+			local.applyUpdate(ev, ts)
+		case *event.ResyncEvent:
+			if ev.Phase != event.ResyncEnd || ev.Source != source {
+				continue
+			}
+			for _, b := range ev.Balances {
+				local.Balances[b.Symbol] = append(local.Balances[b.Symbol], BalanceReading{Time: ts, Seq: id, AmountSats: b.AmountSats})
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return local, nil
+}
+
+// applyUpdate books the fill increment of e, if any, at its reported price.
+// Like the OMS, a shrinking accumulated quantity is ignored.
+func (l *Local) applyUpdate(e *event.OrderUpdateEvent, ts quant.TimeStamp) {
+	o := l.Orders[e.OrderID]
+	prev := int64(0)
+	if o != nil {
+		prev = o.QtySats
+	}
+	delta := int64(e.AccumulatedQtySats) - prev
+	if delta <= 0 {
+		return
+	}
+	if o == nil {
+		o = &LocalOrder{OrderID: e.OrderID}
+		l.Orders[e.OrderID] = o
+	}
+	o.QtySats += delta
+	o.NotionalMicros = safe.SafeAdd(o.NotionalMicros, safe.SafeMulDiv(int64(e.PriceMicros), delta, quant.QtyScale))
+	o.LastFill = ts
+}
+
+// Options are the comparison tolerances.
+type Options struct {
+	PriceToleranceBps int64         // Average price difference accepted (venue rounding)
+	QtyToleranceSats  int64         // Quantity and coin balance difference accepted
+	CashToleranceSats int64         // Cash balance difference accepted (micros)
+	Window            time.Duration // Time distance for matching trades without an order ID
+}
+
+// Discrepancy is one difference between the statement and the local books.
+type Discrepancy struct {
+	Kind   string          `json:"kind"`
+	Ref    string          `json:"ref"` // Order ID (statement or local) or currency
+	Time   quant.TimeStamp `json:"ts"`
+	Local  int64           `json:"local"`
+	Venue  int64           `json:"venue"`
+	Detail string          `json:"detail,omitempty"`
+}
+
+// Report is the outcome of a reconciliation.
+type Report struct {
+	From            quant.TimeStamp  `json:"from"`
+	To              quant.TimeStamp  `json:"to"`
+	Orders          int              `json:"orders"` // Statement orders (lines without an order ID count alone)
+	Matched         int              `json:"matched"`
+	Balances        int              `json:"balances"` // Statement balance lines checked
+	BalancesMatched int              `json:"balances_matched"`
+	Fees            map[string]int64 `json:"fees,omitempty"` // Statement fees by currency (x 10^8)
+	Discrepancies   []Discrepancy    `json:"discrepancies"`
+}
+
+// OK reports whether the books match the statement.
+func (r *Report) OK() bool { return len(r.Discrepancies) == 0 }
+
+// String renders the report as a plain text table.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "orders   %d/%d matched\nbalances %d/%d matched\n", r.Matched, r.Orders, r.BalancesMatched, r.Balances)
+	for _, ccy := range slices.Sorted(maps.Keys(r.Fees)) {
+		fmt.Fprintf(&b, "fees     %s %s\n", quant.QtySats(r.Fees[ccy]), ccy)
+	}
+	if r.OK() {
+		b.WriteString("no discrepancies\n")
+		return b.String()
+	}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tREF\tTIME\tLOCAL\tVENUE\tDETAIL")
+	for _, d := range r.Discrepancies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", d.Kind, d.Ref,
+			time.UnixMicro(int64(d.Time)).UTC().Format(time.RFC3339), d.Local, d.Venue, d.Detail)
+	}
+	w.Flush()
+	fmt.Fprintf(&b, "%d discrepancies\n", len(r.Discrepancies))
+	return b.String()
+}
+
+// venueOrder is a statement order: its lines summed by order ID.
+type venueOrder struct {
+	ref      string
+	time     quant.TimeStamp // Last line
+	qty      int64
+	notional int64
+	hasID    bool
+}
+
+func (o *venueOrder) avgPrice() int64 {
+	if o.qty == 0 {
+		return 0
+	}
+	return safe.SafeMulDiv(o.notional, quant.QtyScale, o.qty)
+}
+
+// Compare reconciles stmt against local. Statement lines with an order ID
+// are summed per order and matched by ID; lines without one are matched
+// each to the closest unmatched local order within opts.Window with the same
+// quantity. Local orders filled in the statement's span (widened by
+// opts.Window) but absent from it are reported as missing on the venue.
+// Each statement balance is compared with the last reading booked at or
+// before its time.
+func Compare(stmt *Statement, local *Local, opts Options) *Report {
+	from, to := stmt.Span()
+	report := &Report{From: from, To: to, Fees: make(map[string]int64)}
+
+	var orders []*venueOrder
+	byID := make(map[string]*venueOrder)
+	for i, t := range stmt.Trades {
+		if t.FeeCurrency != "" {
+			report.Fees[t.FeeCurrency] = safe.SafeAdd(report.Fees[t.FeeCurrency], t.Fee)
+		}
+		o := byID[t.OrderID]
+		if t.OrderID == "" || o == nil {
+			o = &venueOrder{ref: t.OrderID, hasID: t.OrderID != ""}
+			if !o.hasID {
+				o.ref = fmt.Sprintf("line %d", i+1)
+			} else {
+				byID[t.OrderID] = o
+			}
+			orders = append(orders, o)
+		}
+		o.time = max(o.time, t.Time)
+		o.qty = safe.SafeAdd(o.qty, int64(t.QtySats))
+		o.notional = safe.SafeAdd(o.notional, safe.SafeMulDiv(int64(t.PriceMicros), int64(t.QtySats), quant.QtyScale))
+	}
+	report.Orders = len(orders)
+
+	ids := slices.Sorted(maps.Keys(local.Orders))
+	matched := make(map[string]bool)
+	window := quant.TimeStamp(opts.Window.Microseconds())
+	for _, v := range orders {
+		var lo *LocalOrder
+		if v.hasID {
+			lo = local.Orders[v.ref]
+		} else {
+			lo = closest(local, ids, matched, v, window, opts.QtyToleranceSats)
+		}
+		if lo == nil {
+			report.add(Discrepancy{Kind: MissingLocal, Ref: v.ref, Time: v.time, Venue: v.qty, Detail: "filled qty on the statement"})
+			continue
+		}
+		matched[lo.OrderID] = true
+		ok := true
+		if abs(lo.QtySats-v.qty) > opts.QtyToleranceSats {
+			report.add(Discrepancy{Kind: QtyMismatch, Ref: v.ref, Time: v.time, Local: lo.QtySats, Venue: v.qty})
+			ok = false
+		}
+		lp, vp := lo.AvgPriceMicros(), v.avgPrice()
+		if vp > 0 && lp > 0 && safe.SafeMulDiv(abs(lp-vp), 10_000, vp) > opts.PriceToleranceBps {
+			report.add(Discrepancy{Kind: PriceMismatch, Ref: v.ref, Time: v.time, Local: lp, Venue: vp, Detail: "average fill price (micros)"})
+			ok = false
+		}
+		if ok {
+			report.Matched++
+		}
+	}
+	for _, id := range ids {
+		lo := local.Orders[id]
+		if matched[id] || lo.LastFill < from-window || lo.LastFill > to+window {
+			continue
+		}
+		report.add(Discrepancy{Kind: MissingVenue, Ref: id, Time: lo.LastFill, Local: lo.QtySats, Detail: "filled qty in the journal"})
+	}
+
+	for _, b := range stmt.Balances {
+		report.Balances++
+		readings := local.Balances[b.Currency]
+		i, _ := slices.BinarySearchFunc(readings, b.Time+1, func(r BalanceReading, ts quant.TimeStamp) int { return cmp.Compare(r.Time, ts) })
+		if i == 0 {
+			report.add(Discrepancy{Kind: BalanceUnknown, Ref: b.Currency, Time: b.Time, Venue: b.AmountSats, Detail: "no resync booked before this line"})
+			continue
+		}
+		r := readings[i-1]
+		tolerance := opts.QtyToleranceSats
+		if slices.Contains(cashCurrencies, b.Currency) {
+			tolerance = opts.CashToleranceSats
+		}
+		if abs(r.AmountSats-b.AmountSats) > tolerance {
+			report.add(Discrepancy{Kind: BalanceMismatch, Ref: b.Currency, Time: b.Time, Local: r.AmountSats, Venue: b.AmountSats,
+				Detail: fmt.Sprintf("booked at seq %d, %s earlier", r.Seq, time.Duration(b.Time-r.Time)*time.Microsecond)})
+			continue
+		}
+		report.BalancesMatched++
+	}
+	return report
+}
+
+func (r *Report) add(d Discrepancy) {
+	r.Discrepancies = append(r.Discrepancies, d)
+}
+
+// closest returns the unmatched local order with v's quantity whose last fill
+// is nearest to v's time within window, or nil. ids are local's sorted IDs.
+func closest(local *Local, ids []string, matched map[string]bool, v *venueOrder, window quant.TimeStamp, tolerance int64) *LocalOrder {
+	var best *LocalOrder
+	for _, id := range ids {
+		lo := local.Orders[id]
+		if matched[id] || abs(lo.QtySats-v.qty) > tolerance {
+			continue
+		}
+		if d := abs(int64(lo.LastFill - v.time)); d <= int64(window) && (best == nil || d < abs(int64(best.LastFill-v.time))) {
+			best = lo
+		}
+	}
+	return best
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,140 @@
+package reconcile
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/storage"
+	"crypto_go/pkg/quant"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ts returns 2026-03-10 09:00 UTC plus min minutes.
+func ts(min int) quant.TimeStamp {
+	return quant.TimeStamp(time.Date(2026, 3, 10, 9, min, 0, 0, time.UTC).UnixMicro())
+}
+
+func TestParseStatement(t *testing.T) {
+	csv := "\uFEFFDate,Client OID,Pair,Side,Price,Quantity,Fee,Fee Coin,Coin,Balance\n" +
+		"2026-03-10 09:01:00,c-1,btcusdt,buy,\"50,000.5\",0.1,0.0005,BNB,,\n" +
+		"2026-03-10 09:02:00,,,,,,,,USDT,\"4,999.95\"\n" +
+		",,,,,,,,,\n"
+	stmt, err := ParseStatement(strings.NewReader(csv), nil)
+	if err != nil {
+		t.Fatalf("ParseStatement: %v", err)
+	}
+	want := Trade{Time: ts(1), OrderID: "c-1", Symbol: "BTCUSDT", Side: domain.SideBuy, PriceMicros: 50000_500000, QtySats: 10_000000, Fee: 50000, FeeCurrency: "BNB"}
+	if len(stmt.Trades) != 1 || stmt.Trades[0] != want {
+		t.Errorf("trades %+v, want %+v", stmt.Trades, want)
+	}
+	if len(stmt.Balances) != 1 || stmt.Balances[0] != (Balance{Time: ts(2), Currency: "USDT", AmountSats: 4999_950000}) {
+		t.Errorf("unexpected balances %+v", stmt.Balances)
+	}
+
+	kst := time.FixedZone("KST", 9*3600)
+	stmt, err = ParseStatement(strings.NewReader("체결시간,종류,거래단가,거래수량\n2026.03.10 18:01,매도,100,1\n"), kst)
+	if err != nil || len(stmt.Trades) != 1 || stmt.Trades[0].Time != ts(1) || stmt.Trades[0].Side != domain.SideSell {
+		t.Errorf("zone-less time in KST: %+v, %v", stmt, err)
+	}
+
+	for _, bad := range []string{"symbol,qty\nBTC,1\n", "time,qty\n2026-03-10,1\n", "time,qty\n1773133260,1e3\n", "time,price\n1773133260,1\n"} {
+		if _, err := ParseStatement(strings.NewReader(bad), nil); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	local := &Local{
+		Orders: map[string]*LocalOrder{
+			"ok":      {OrderID: "ok", LastFill: ts(1), QtySats: 20_000000, NotionalMicros: 10000_000000},
+			"short":   {OrderID: "short", LastFill: ts(2), QtySats: 5_000000, NotionalMicros: 2500_000000},
+			"pricey":  {OrderID: "pricey", LastFill: ts(3), QtySats: 10_000000, NotionalMicros: 5100_000000},
+			"noid":    {OrderID: "noid", LastFill: ts(4), QtySats: 30_000000, NotionalMicros: 15000_000000},
+			"ghost":   {OrderID: "ghost", LastFill: ts(5), QtySats: 1_000000, NotionalMicros: 500_000000},
+			"earlier": {OrderID: "earlier", LastFill: ts(0) - quant.TimeStamp(time.Hour.Microseconds()), QtySats: 1},
+		},
+		Balances: map[string][]BalanceReading{
+			"USDT": {{Time: ts(0), Seq: 7, AmountSats: 1000_000000}, {Time: ts(6), Seq: 9, AmountSats: 900_000000}},
+		},
+	}
+	stmt := &Statement{
+		Trades: []Trade{
+			{Time: ts(1), OrderID: "ok", PriceMicros: 50000_000000, QtySats: 10_000000, Fee: 100, FeeCurrency: "USDT"},
+			{Time: ts(1), OrderID: "ok", PriceMicros: 50000_000000, QtySats: 10_000000, Fee: 100, FeeCurrency: "USDT"},
+			{Time: ts(2), OrderID: "short", PriceMicros: 50000_000000, QtySats: 10_000000},
+			{Time: ts(3), OrderID: "pricey", PriceMicros: 50000_000000, QtySats: 10_000000},
+			{Time: ts(4), PriceMicros: 50000_000000, QtySats: 30_000000}, // No order ID: matched by qty and time
+			{Time: ts(5), OrderID: "unknown", PriceMicros: 50000_000000, QtySats: 1},
+		},
+		Balances: []Balance{
+			{Time: ts(5), Currency: "USDT", AmountSats: 1000_000000}, // Reading at ts(0)
+			{Time: ts(7), Currency: "USDT", AmountSats: 950_000000},  // Reading at ts(6): off by 50
+			{Time: ts(7), Currency: "BTC", AmountSats: 1},
+		},
+	}
+	report := Compare(stmt, local, Options{PriceToleranceBps: 10, Window: time.Minute})
+
+	want := []Discrepancy{
+		{Kind: QtyMismatch, Ref: "short", Local: 5_000000, Venue: 10_000000},
+		{Kind: PriceMismatch, Ref: "pricey", Local: 51000_000000, Venue: 50000_000000},
+		{Kind: MissingLocal, Ref: "unknown", Venue: 1},
+		{Kind: MissingVenue, Ref: "ghost", Local: 1_000000},
+		{Kind: BalanceMismatch, Ref: "USDT", Local: 900_000000, Venue: 950_000000},
+		{Kind: BalanceUnknown, Ref: "BTC", Venue: 1},
+	}
+	if len(report.Discrepancies) != len(want) {
+		t.Fatalf("got %d discrepancies, want %d:\n%s", len(report.Discrepancies), len(want), report)
+	}
+	for i, d := range report.Discrepancies {
+		if d.Kind != want[i].Kind || d.Ref != want[i].Ref || d.Local != want[i].Local || d.Venue != want[i].Venue {
+			t.Errorf("discrepancy %d: got %+v, want %+v", i, d, want[i])
+		}
+	}
+	if report.Orders != 5 || report.Matched != 2 || report.Balances != 3 || report.BalancesMatched != 1 || report.Fees["USDT"] != 200 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if report.OK() || !strings.Contains(report.String(), "6 discrepancies") {
+		t.Errorf("unexpected rendering:\n%s", report)
+	}
+}
+
+func TestLoadLocal(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	save := func(ev event.Event) {
+		t.Helper()
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save(&event.ResyncEvent{BaseEvent: event.BaseEvent{Seq: 1, Ts: ts(0)}, Source: "UPBIT", Phase: event.ResyncEnd,
+		Balances: []domain.Balance{{Symbol: "KRW", AmountSats: 5_000_000}}})
+	save(&event.ResyncEvent{BaseEvent: event.BaseEvent{Seq: 2, Ts: ts(0)}, Source: "BITGET", Phase: event.ResyncEnd,
+		Balances: []domain.Balance{{Symbol: "USDT", AmountSats: 1}}})
+	save(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 3, Ts: ts(1)}, OrderID: "a", Status: domain.OrderStatusAcked})
+	save(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 4, Ts: ts(2)}, OrderID: "a", Status: domain.OrderStatusPartiallyFilled, PriceMicros: 100, AccumulatedQtySats: quant.QtyScale})
+	save(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 5, Ts: ts(3)}, OrderID: "a", Status: domain.OrderStatusFilled, PriceMicros: 200, AccumulatedQtySats: 3 * quant.QtyScale})
+	save(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 6, Ts: ts(4)}, OrderID: "a", Status: domain.OrderStatusFilled, PriceMicros: 200, AccumulatedQtySats: quant.QtyScale})
+	save(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 7, Ts: ts(9)}, OrderID: "late", Status: domain.OrderStatusFilled, PriceMicros: 1, AccumulatedQtySats: 1})
+
+	local, err := LoadLocal(ctx, store, "UPBIT", ts(1), ts(5))
+	if err != nil {
+		t.Fatalf("LoadLocal: %v", err)
+	}
+	a := local.Orders["a"]
+	if len(local.Orders) != 1 || a == nil || a.QtySats != 3*quant.QtyScale || a.AvgPriceMicros() != 166 || a.LastFill != ts(3) {
+		t.Errorf("unexpected orders: %+v", local.Orders)
+	}
+	if got := local.Balances["KRW"]; len(got) != 1 || got[0].AmountSats != 5_000_000 || len(local.Balances) != 1 {
+		t.Errorf("unexpected balances: %+v", local.Balances)
+	}
+}
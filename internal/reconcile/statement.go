@@ -0,0 +1,263 @@
+// Package reconcile checks a venue's exported account statement against what
+// the engine recorded: the order updates of the trade journal and the venue
+// balances booked at each gateway resync.
+package reconcile
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Trade is one fill (or order total) line of a statement.
+type Trade struct {
+	Time        quant.TimeStamp
+	OrderID     string // Client order ID; "" if the venue does not export it
+	Symbol      string
+	Side        string // domain.SideBuy | domain.SideSell | ""
+	PriceMicros quant.PriceMicros
+	QtySats     quant.QtySats
+	Fee         int64 // In FeeCurrency units x 10^8
+	FeeCurrency string
+}
+
+// Balance is one balance line of a statement: the account's holding of
+// Currency after Time.
+type Balance struct {
+	Time       quant.TimeStamp
+	Currency   string
+	AmountSats int64 // Cash currencies in micros, coins in sats (domain.Balance units)
+}
+
+// Statement is a parsed statement export, each list in file order.
+type Statement struct {
+	Trades   []Trade
+	Balances []Balance
+}
+
+// Span returns the first and last time on the statement (0, 0 if empty).
+func (s *Statement) Span() (from, to quant.TimeStamp) {
+	first := true
+	see := func(ts quant.TimeStamp) {
+		if first || ts < from {
+			from = ts
+		}
+		if first || ts > to {
+			to = ts
+		}
+		first = false
+	}
+	for _, t := range s.Trades {
+		see(t.Time)
+	}
+	for _, b := range s.Balances {
+		see(b.Time)
+	}
+	return from, to
+}
+
+// Statement columns and the header names accepted for each, compared in
+// lower case. A line with a qty is a trade, one with a currency and a balance
+// a balance reading; a ledger line may be both.
+const (
+	colTime        = "time"
+	colOrderID     = "order_id"
+	colSymbol      = "symbol"
+	colSide        = "side"
+	colPrice       = "price"
+	colQty         = "qty"
+	colFee         = "fee"
+	colFeeCurrency = "fee_currency"
+	colCurrency    = "currency"
+	colBalance     = "balance"
+)
+
+var columnAliases = map[string][]string{
+	colTime:        {"time", "date", "timestamp", "fill time", "체결시간"},
+	colOrderID:     {"order_id", "client_oid", "client oid", "client order id", "주문번호"},
+	colSymbol:      {"symbol", "pair", "trading pair", "market"},
+	colSide:        {"side", "direction", "종류"},
+	colPrice:       {"price", "fill price", "거래단가"},
+	colQty:         {"qty", "quantity", "filled", "거래수량"},
+	colFee:         {"fee", "수수료"},
+	colFeeCurrency: {"fee_currency", "fee currency", "fee coin"},
+	colCurrency:    {"currency", "coin", "asset"},
+	colBalance:     {"balance", "잔고"},
+}
+
+// cashCurrencies are booked in micros; everything else in sats.
+var cashCurrencies = []string{"KRW", "USD", "USDT", "USDC"}
+
+// timeLayouts are the statement time formats tried in order (numbers are
+// Unix seconds, milliseconds or microseconds by magnitude).
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006.01.02 15:04:05",
+	"2006.01.02 15:04",
+	"2006/01/02 15:04:05",
+}
+
+// ParseStatement reads a CSV statement with a header line. Times without a
+// zone are read in loc (nil = UTC).
+func ParseStatement(r io.Reader, loc *time.Location) (*Statement, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statement header: %w", err)
+	}
+	index := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\uFEFF"))) // Spreadsheet BOM
+		for col, aliases := range columnAliases {
+			if _, seen := index[col]; !seen && slices.Contains(aliases, h) {
+				index[col] = i
+			}
+		}
+	}
+	if _, ok := index[colTime]; !ok {
+		return nil, errors.New("statement has no time column")
+	}
+	_, hasQty := index[colQty]
+	_, hasBalance := index[colBalance]
+	if !hasQty && !hasBalance {
+		return nil, errors.New("statement has neither a qty nor a balance column")
+	}
+
+	stmt := &Statement{}
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return stmt, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		field := func(col string) string {
+			if i, ok := index[col]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		if field(colTime) == "" {
+			continue // Blank or summary line
+		}
+		ts, err := parseTime(field(colTime), loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if qty := field(colQty); qty != "" {
+			t := Trade{
+				Time:        ts,
+				OrderID:     field(colOrderID),
+				Symbol:      strings.ToUpper(field(colSymbol)),
+				FeeCurrency: strings.ToUpper(field(colFeeCurrency)),
+			}
+			if t.Side, err = parseSide(field(colSide)); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if t.QtySats, err = parseQty(qty); err != nil {
+				return nil, fmt.Errorf("line %d: qty: %w", line, err)
+			}
+			if p := field(colPrice); p != "" {
+				if t.PriceMicros, err = parsePrice(p); err != nil {
+					return nil, fmt.Errorf("line %d: price: %w", line, err)
+				}
+			}
+			if f := field(colFee); f != "" {
+				fee, err := parseQty(f)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: fee: %w", line, err)
+				}
+				t.Fee = int64(fee)
+			}
+			stmt.Trades = append(stmt.Trades, t)
+		}
+
+		if currency, bal := strings.ToUpper(field(colCurrency)), field(colBalance); currency != "" && bal != "" {
+			b := Balance{Time: ts, Currency: currency}
+			if slices.Contains(cashCurrencies, currency) {
+				p, err := parsePrice(bal)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: balance: %w", line, err)
+				}
+				b.AmountSats = int64(p)
+			} else {
+				q, err := parseQty(bal)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: balance: %w", line, err)
+				}
+				b.AmountSats = int64(q)
+			}
+			stmt.Balances = append(stmt.Balances, b)
+		}
+	}
+}
+
+func parseTime(s string, loc *time.Location) (quant.TimeStamp, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		switch {
+		case n < 1e11:
+			return quant.TimeStamp(n * 1_000_000), nil
+		case n < 1e14:
+			return quant.TimeStamp(n * 1_000), nil
+		}
+		return quant.TimeStamp(n), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return quant.TimeStamp(t.UnixMicro()), nil
+		}
+	}
+	return 0, fmt.Errorf("unrecognized time %q", s)
+}
+
+func parseSide(s string) (string, error) {
+	switch strings.ToUpper(s) {
+	case "":
+		return "", nil
+	case "BUY", "BID", "매수":
+		return domain.SideBuy, nil
+	case "SELL", "ASK", "매도":
+		return domain.SideSell, nil
+	}
+	return "", fmt.Errorf("unknown side %q", s)
+}
+
+// parseQty parses a decimal (thousands separators allowed) into 10^-8 units.
+func parseQty(s string) (quant.QtySats, error) {
+	s, err := decimal(s)
+	return quant.ToQtySatsStr(s), err
+}
+
+// parsePrice parses a decimal (thousands separators allowed) into micros.
+func parsePrice(s string) (quant.PriceMicros, error) {
+	s, err := decimal(s)
+	return quant.ToPriceMicrosStr(s), err
+}
+
+// decimal strips thousands separators and checks for [-]digits[.digits].
+func decimal(s string) (string, error) {
+	s = strings.ReplaceAll(s, ",", "")
+	digits := strings.TrimPrefix(s, "-")
+	intPart, frac, _ := strings.Cut(digits, ".")
+	if intPart == "" && frac == "" || strings.Trim(intPart+frac, "0123456789") != "" {
+		return "", fmt.Errorf("invalid number %q", s)
+	}
+	return s, nil
+}
@@ -0,0 +1,201 @@
+package risk
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"slices"
+	"sort"
+)
+
+const (
+	retScale = 1_000_000 // Daily returns in micros: 1% = 10_000
+
+	// Default VaR model: one year of daily returns, 99% confidence.
+	DefaultVaRWindowDays    = 250
+	DefaultVaRConfidenceBps = 9_900
+)
+
+// dayReturns are the close-to-close returns of one UTC day per venue/symbol,
+// in retScale units.
+type dayReturns map[priceKey]int64
+
+// Exposure is the exposure of one asset class, in its quote currency.
+type Exposure struct {
+	Class       domain.AssetClass `json:"class"`
+	NetMicros   int64             `json:"net,string"`   // Signed notional at the mark price
+	GrossMicros int64             `json:"gross,string"` // Absolute notional
+	Positions   int               `json:"positions"`    // Open positions
+}
+
+// QuoteVaR is the historical-simulation VaR of the positions quoted in one
+// currency: the loss not exceeded on ConfidenceBps of the recorded days, had
+// today's positions been held through each of them.
+type QuoteVaR struct {
+	Quote       string `json:"quote"`
+	VaRMicros   int64  `json:"var,string"`
+	GrossMicros int64  `json:"gross,string"`
+}
+
+// Report is the portfolio risk of the open positions.
+type Report struct {
+	Exposure      []Exposure `json:"exposure"` // By asset class
+	VaR           []QuoteVaR `json:"var"`      // By quote currency
+	Days          int        `json:"days"`     // Daily returns the VaR is based on
+	ConfidenceBps int64      `json:"confidence_bps"`
+}
+
+// leg is the notional of one open position (or of one after a checked order).
+type leg struct {
+	key      priceKey
+	class    domain.AssetClass
+	notional int64
+}
+
+// SetVaRModel sets how many daily returns are kept and the VaR confidence
+// (e.g. 9900 = 99%). Must be called before the first event.
+func (r *Engine) SetVaRModel(windowDays int, confidenceBps int64) {
+	r.varWindow = windowDays
+	r.varConfidenceBps = confidenceBps
+}
+
+// SetDefaultVenue names the venue of default-venue ("") orders and
+// positions, which decides their asset class. Must be called before the first event.
+func (r *Engine) SetDefaultVenue(exchange string) {
+	r.defaultVenue = exchange
+}
+
+// classOf returns the asset class of exchange.
+func (r *Engine) classOf(exchange string) domain.AssetClass {
+	if exchange == "" {
+		exchange = r.defaultVenue
+	}
+	class, _ := domain.VenueAssetClass(exchange)
+	return class
+}
+
+// closeDay records the returns of the day that ended since the previous
+// close, from the last trade prices. A venue/symbol seen for the first time
+// only sets its close. Replays rebuild the same history from the WAL.
+func (r *Engine) closeDay() {
+	rets := make(map[priceKey]int64, len(r.last))
+	for k, price := range r.last {
+		if prev, ok := r.closes[k]; ok && prev > 0 {
+			rets[k] = safe.SafeMulDiv(price-prev, retScale, prev)
+		}
+		r.closes[k] = price
+	}
+	if len(rets) == 0 {
+		return
+	}
+	r.history = append(r.history, rets)
+	if over := len(r.history) - r.varWindow; over > 0 {
+		r.history = slices.Delete(r.history, 0, over)
+	}
+}
+
+// legs returns the open positions, valued at their mark (the last trade
+// until one is marked). With with.key set, that position is replaced by with.
+func (r *Engine) legs(with leg) []leg {
+	var out []leg
+	r.positions.Range(func(p domain.Position) {
+		k := priceKey{p.Exchange, p.Symbol}
+		if k == with.key || p.QtySats == 0 {
+			return
+		}
+		mark := p.MarkPriceMicros
+		if mark <= 0 {
+			mark = r.last[k]
+		}
+		out = append(out, leg{key: k, class: r.classOf(p.Exchange), notional: safe.SafeMulDiv(p.QtySats, mark, quant.QtyScale)})
+	})
+	if with.key != (priceKey{}) {
+		out = append(out, with)
+	}
+	return out
+}
+
+// quoteRisk returns the gross exposure and VaR of the legs quoted in quote.
+func (r *Engine) quoteRisk(legs []leg, quote string) (gross, vaR int64) {
+	pnl := make([]int64, len(r.history))
+	for _, l := range legs {
+		if l.class.Quote != quote {
+			continue
+		}
+		gross = safe.SafeAdd(gross, abs(l.notional))
+		for d, day := range r.history {
+			pnl[d] = safe.SafeAdd(pnl[d], safe.SafeMulDiv(l.notional, day[l.key], retScale))
+		}
+	}
+	return gross, r.quantileLoss(pnl)
+}
+
+// quantileLoss returns the loss at the VaR confidence of the scenario PnLs
+// (0 without history or if even that scenario gains).
+func (r *Engine) quantileLoss(pnl []int64) int64 {
+	if len(pnl) == 0 {
+		return 0
+	}
+	slices.Sort(pnl)
+	k := int(int64(len(pnl)) * (bpsScale - r.varConfidenceBps) / bpsScale)
+	return max(0, -pnl[min(k, len(pnl)-1)])
+}
+
+// checkPortfolio runs the gross exposure and VaR checks on the quote
+// currency of an order that takes the order.Exchange/Symbol position to
+// nextSats at priceMicros.
+func (r *Engine) checkPortfolio(order *domain.Order, nextSats, priceMicros int64) error {
+	l := r.limits
+	if l.MaxGrossExposureMicros <= 0 && l.MaxVaRMicros <= 0 {
+		return nil
+	}
+	if priceMicros <= 0 {
+		return reject(CheckNoPrice, order, 0, 0)
+	}
+	class := r.classOf(order.Exchange)
+	gross, vaR := r.quoteRisk(r.legs(leg{
+		key:      priceKey{order.Exchange, order.Symbol},
+		class:    class,
+		notional: safe.SafeMulDiv(nextSats, priceMicros, quant.QtyScale),
+	}), class.Quote)
+	if l.MaxGrossExposureMicros > 0 && gross > l.MaxGrossExposureMicros {
+		return reject(CheckGrossExposure, order, gross, l.MaxGrossExposureMicros)
+	}
+	if l.MaxVaRMicros > 0 && vaR > l.MaxVaRMicros {
+		return reject(CheckVaR, order, vaR, l.MaxVaRMicros)
+	}
+	return nil
+}
+
+// Report returns the exposure per asset class and the VaR per quote currency
+// of the open positions. It does not change the engine.
+func (r *Engine) Report() Report {
+	legs := r.legs(leg{})
+	rep := Report{Days: len(r.history), ConfidenceBps: r.varConfidenceBps}
+
+	byClass := make(map[domain.AssetClass]*Exposure)
+	quotes := make(map[string]bool)
+	for _, l := range legs {
+		e, ok := byClass[l.class]
+		if !ok {
+			e = &Exposure{Class: l.class}
+			byClass[l.class] = e
+		}
+		e.NetMicros = safe.SafeAdd(e.NetMicros, l.notional)
+		e.GrossMicros = safe.SafeAdd(e.GrossMicros, abs(l.notional))
+		e.Positions++
+		quotes[l.class.Quote] = true
+	}
+	for _, e := range byClass {
+		rep.Exposure = append(rep.Exposure, *e)
+	}
+	sort.Slice(rep.Exposure, func(i, j int) bool {
+		return rep.Exposure[i].Class.String() < rep.Exposure[j].Class.String()
+	})
+	for quote := range quotes {
+		gross, vaR := r.quoteRisk(legs, quote)
+		rep.VaR = append(rep.VaR, QuoteVaR{Quote: quote, VaRMicros: vaR, GrossMicros: gross})
+	}
+	sort.Slice(rep.VaR, func(i, j int) bool { return rep.VaR[i].Quote < rep.VaR[j].Quote })
+	return rep
+}
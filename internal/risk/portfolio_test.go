@@ -0,0 +1,103 @@
+package risk
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+// portfolio holds 1 BTC long at 100 on the default venue (BITGET_FUTURES),
+// 1 BTC long at 1000 KRW on UPBIT and 2 ETH short at 10 on OKX_SWAP.
+func portfolio() *domain.PositionBook {
+	positions := domain.NewPositionBook()
+	positions.Get("", "BTC").ApplyFill(domain.SideBuy, quant.QtyScale, 100_000_000, 1)
+	positions.Get("UPBIT", "BTC").ApplyFill(domain.SideBuy, quant.QtyScale, 1_000_000_000, 2)
+	positions.Get("OKX_SWAP", "ETH").ApplyFill(domain.SideSell, 2*quant.QtyScale, 10_000_000, 3)
+	return positions
+}
+
+// observeCloses observes one default-venue BTC trade per UTC day.
+func observeCloses(r *Engine, prices ...int64) {
+	for d, price := range prices {
+		r.Observe("", "BTC", price, quant.TimeStamp(d)*day+1)
+	}
+}
+
+func TestEngine_Report(t *testing.T) {
+	r := NewEngine(Limits{}, portfolio())
+	r.SetDefaultVenue("BITGET_FUTURES")
+	r.SetVaRModel(3, 9_900)
+
+	// Returns: -10%, +10%, 0% (the last day is still open)
+	observeCloses(r, 100_000_000, 90_000_000, 99_000_000, 99_000_000, 100_000_000)
+	rep := r.Report()
+	if rep.Days != 3 {
+		t.Fatalf("Days = %d; want 3", rep.Days)
+	}
+
+	want := []Exposure{
+		{Class: domain.AssetClass{Market: domain.MarketFutures, Quote: domain.QuoteUSDT}, NetMicros: 80_000_000, GrossMicros: 120_000_000, Positions: 2},
+		{Class: domain.AssetClass{Market: domain.MarketSpot, Quote: domain.QuoteKRW}, NetMicros: 1_000_000_000, GrossMicros: 1_000_000_000, Positions: 1},
+	}
+	if len(rep.Exposure) != len(want) {
+		t.Fatalf("Exposure = %+v; want %+v", rep.Exposure, want)
+	}
+	for i := range want {
+		if rep.Exposure[i] != want[i] {
+			t.Errorf("Exposure[%d] = %+v; want %+v", i, rep.Exposure[i], want[i])
+		}
+	}
+
+	// Only the default-venue BTC has returns: its worst day loses 10% of 100
+	if len(rep.VaR) != 2 || rep.VaR[0] != (QuoteVaR{Quote: domain.QuoteKRW, GrossMicros: 1_000_000_000}) ||
+		rep.VaR[1] != (QuoteVaR{Quote: domain.QuoteUSDT, VaRMicros: 10_000_000, GrossMicros: 120_000_000}) {
+		t.Errorf("VaR = %+v", rep.VaR)
+	}
+
+	// The window drops the -10% day: +10%, 0%, +1%
+	r.Observe("", "BTC", 100_000_000, 5*day)
+	if rep := r.Report(); rep.Days != 3 || rep.VaR[1].VaRMicros != 0 {
+		t.Errorf("after the window moved: Days = %d, VaR = %+v", rep.Days, rep.VaR)
+	}
+}
+
+func TestEngine_PortfolioChecks(t *testing.T) {
+	eth := func(side string) *domain.Order {
+		return &domain.Order{Exchange: "OKX_SWAP", Symbol: "ETH", Side: side, QtySats: quant.QtyScale}
+	}
+	upbit := buy(quant.QtyScale)
+	upbit.Exchange = "UPBIT"
+
+	tests := []struct {
+		name   string
+		limits Limits
+		order  *domain.Order
+		want   string
+	}{
+		{"VaR grows past limit", Limits{MaxVaRMicros: 15_000_000}, buy(quant.QtyScale), CheckVaR},
+		{"VaR within limit", Limits{MaxVaRMicros: 15_000_000}, buy(quant.QtyScale / 4), ""},
+		{"VaR reducing passes", Limits{MaxVaRMicros: 1}, sell(quant.QtyScale / 2), ""},
+		{"VaR of another currency", Limits{MaxVaRMicros: 1}, upbit, ""},
+		{"gross grows past limit", Limits{MaxGrossExposureMicros: 150_000_000}, buy(quant.QtyScale / 2), CheckGrossExposure},
+		{"gross within limit", Limits{MaxGrossExposureMicros: 150_000_000}, eth(domain.SideSell), ""},
+		{"gross reducing passes", Limits{MaxGrossExposureMicros: 1}, eth(domain.SideBuy), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewEngine(tt.limits, portfolio())
+			r.SetDefaultVenue("BITGET_FUTURES")
+			observeCloses(r, 100_000_000, 90_000_000, 99_000_000, 99_000_000, 100_000_000)
+			r.Observe("OKX_SWAP", "ETH", 10_000_000, 4*day)
+			r.Observe("UPBIT", "BTC", 1_000_000_000, 4*day)
+			if got := checkName(t, r.Check(tt.order, domain.OrderTypeMarket, 0, Open{}, 4*day)); got != tt.want {
+				t.Errorf("check = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Without a price the checks cannot value the order
+	r := NewEngine(Limits{MaxVaRMicros: 1}, domain.NewPositionBook())
+	if got := checkName(t, r.Check(buy(1), domain.OrderTypeMarket, 0, Open{}, 0)); got != CheckNoPrice {
+		t.Errorf("no price: check = %q, want %q", got, CheckNoPrice)
+	}
+}
@@ -0,0 +1,244 @@
+// Package risk implements the pre-trade checks the OMS runs before sending
+// any order to a venue.
+package risk
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"fmt"
+)
+
+const (
+	bpsScale     = 10_000
+	microsPerDay = 86_400_000_000 // One UTC day in quant.TimeStamp units
+)
+
+// Checks named by a RiskRejectedError.
+const (
+	CheckOrderNotional = "MAX_ORDER_NOTIONAL"
+	CheckPosition      = "MAX_POSITION"
+	CheckOpenOrders    = "MAX_OPEN_ORDERS"
+	CheckPriceCollar   = "PRICE_COLLAR"
+	CheckDailyLoss     = "DAILY_LOSS"
+	CheckGrossExposure = "MAX_GROSS_EXPOSURE"
+	CheckVaR           = "MAX_VAR"
+	CheckNoPrice       = "NO_REFERENCE_PRICE" // A price check is on but no trade was seen yet
+)
+
+// Limit keys accepted by SetLimit (ControlEvent.Target of CmdSetRiskLimit).
+const (
+	LimitMaxOrderNotional = "max_order_notional_micros"
+	LimitMaxPosition      = "max_position_sats"
+	LimitMaxOpenOrders    = "max_open_orders"
+	LimitPriceCollarBps   = "price_collar_bps"
+	LimitDailyLoss        = "daily_loss_micros"
+	LimitMaxGrossExposure = "max_gross_exposure_micros"
+	LimitMaxVaR           = "max_var_micros"
+)
+
+// Limits are the pre-trade limits. 0 disables a check. Amounts are in the
+// quote currency of the order's venue.
+type Limits struct {
+	MaxOrderNotionalMicros int64 // Price x quantity of one order
+	MaxPositionSats        int64 // Absolute position per venue/symbol after the order fills
+	MaxOpenOrders          int64 // Orders not yet terminal, armed conditionals included
+	PriceCollarBps         int64 // Deviation of a limit price from the last trade
+	DailyLossMicros        int64 // Net PnL drop since the start of the UTC day
+	MaxGrossExposureMicros int64 // Absolute notional of all positions in the order's quote currency
+	MaxVaRMicros           int64 // Historical VaR of the positions in the order's quote currency
+}
+
+// Open is what the open orders besides the checked one commit.
+type Open struct {
+	Orders   int   // Orders not yet terminal, armed conditionals included
+	BuySats  int64 // Unfilled buys at a venue, on the checked order's venue/symbol
+	SellSats int64 // Unfilled sells at a venue, on the checked order's venue/symbol
+}
+
+// RiskRejectedError is returned for an order that failed a check. Value is
+// what the order would have reached, Limit the configured bound.
+type RiskRejectedError struct {
+	Check  string
+	Symbol string
+	Value  int64
+	Limit  int64
+}
+
+func (e *RiskRejectedError) Error() string {
+	if e.Check == CheckNoPrice {
+		return "risk rejected [" + e.Check + "] " + e.Symbol
+	}
+	return fmt.Sprintf("risk rejected [%s] %s: %d exceeds limit %d", e.Check, e.Symbol, e.Value, e.Limit)
+}
+
+type priceKey struct{ exchange, symbol string }
+
+// Engine checks orders against Limits using the positions of a PositionBook
+// and the last trade price per venue/symbol.
+//
+// Orders that only reduce a position pass the position, daily loss, gross
+// exposure and VaR checks, so an exposure can always be closed; the notional, open order and collar
+// checks apply to every order. The position is taken as if the open orders on
+// the same side filled too, so resting orders cannot add up past a limit one
+// at a time, and an order only reduces what they leave. Time comes from event timestamps, so a WAL
+// replay makes the same decisions. Hotpath only (not goroutine-safe).
+type Engine struct {
+	limits    Limits
+	positions *domain.PositionBook
+	last      map[priceKey]int64
+
+	day       int64 // UTC day index of dayPnL
+	dayPnL    int64 // Net PnL of all positions when the day started
+	dayOpened bool
+
+	defaultVenue     string             // Venue of "" orders, for their asset class
+	closes           map[priceKey]int64 // Last trade price at the previous day close
+	history          []dayReturns       // Oldest first, at most varWindow days
+	varWindow        int
+	varConfidenceBps int64
+
+	rejected uint64
+}
+
+// NewEngine creates a risk engine reading positions from positions.
+func NewEngine(limits Limits, positions *domain.PositionBook) *Engine {
+	return &Engine{
+		limits:    limits,
+		positions: positions,
+		last:      make(map[priceKey]int64),
+		closes:    make(map[priceKey]int64),
+
+		varWindow:        DefaultVaRWindowDays,
+		varConfidenceBps: DefaultVaRConfidenceBps,
+	}
+}
+
+// Limits returns the limits in force.
+func (r *Engine) Limits() Limits {
+	return r.limits
+}
+
+// SetLimit changes one limit by key. It returns false for unknown keys.
+func (r *Engine) SetLimit(key string, value int64) bool {
+	switch key {
+	case LimitMaxOrderNotional:
+		r.limits.MaxOrderNotionalMicros = value
+	case LimitMaxPosition:
+		r.limits.MaxPositionSats = value
+	case LimitMaxOpenOrders:
+		r.limits.MaxOpenOrders = value
+	case LimitPriceCollarBps:
+		r.limits.PriceCollarBps = value
+	case LimitDailyLoss:
+		r.limits.DailyLossMicros = value
+	case LimitMaxGrossExposure:
+		r.limits.MaxGrossExposureMicros = value
+	case LimitMaxVaR:
+		r.limits.MaxVaRMicros = value
+	default:
+		return false
+	}
+	return true
+}
+
+// Observe records the last trade price of exchange/symbol at ts. Orders name
+// their venue in Exchange (empty = the default venue), so the caller decides
+// which feed is observed as exchange "".
+func (r *Engine) Observe(exchange, symbol string, priceMicros int64, ts quant.TimeStamp) {
+	r.roll(ts)
+	if priceMicros > 0 {
+		r.last[priceKey{exchange, symbol}] = priceMicros
+	}
+}
+
+// roll starts a new daily loss window on the first timestamp of a UTC day,
+// closing the previous day's returns.
+func (r *Engine) roll(ts quant.TimeStamp) {
+	if day := int64(ts) / microsPerDay; !r.dayOpened || day != r.day {
+		if r.dayOpened {
+			r.closeDay()
+		}
+		r.day = day
+		r.dayPnL = r.positions.NetPnLMicros()
+		r.dayOpened = true
+	}
+}
+
+// Check runs every enabled check on order about to be sent as orderType
+// (LIMIT at priceMicros, or MARKET) while the orders in open are open.
+// It returns a *RiskRejectedError for the first check that fails.
+func (r *Engine) Check(order *domain.Order, orderType string, priceMicros int64, open Open, ts quant.TimeStamp) error {
+	r.roll(ts)
+	if err := r.check(order, orderType, priceMicros, open); err != nil {
+		r.rejected++
+		return err
+	}
+	return nil
+}
+
+func (r *Engine) check(order *domain.Order, orderType string, priceMicros int64, open Open) error {
+	l := r.limits
+	if openOrders := int64(open.Orders); l.MaxOpenOrders > 0 && openOrders >= l.MaxOpenOrders {
+		return reject(CheckOpenOrders, order, openOrders+1, l.MaxOpenOrders)
+	}
+
+	last, seen := r.last[priceKey{order.Exchange, order.Symbol}]
+	price := last
+	if orderType == domain.OrderTypeLimit {
+		price = priceMicros
+	}
+	if l.PriceCollarBps > 0 && orderType == domain.OrderTypeLimit {
+		if !seen {
+			return reject(CheckNoPrice, order, 0, 0)
+		}
+		if dev := safe.SafeMulDiv(abs(price-last), bpsScale, last); dev > l.PriceCollarBps {
+			return reject(CheckPriceCollar, order, dev, l.PriceCollarBps)
+		}
+	}
+	if l.MaxOrderNotionalMicros > 0 {
+		if price <= 0 {
+			return reject(CheckNoPrice, order, 0, 0)
+		}
+		if notional := safe.SafeMulDiv(price, order.QtySats, quant.QtyScale); notional > l.MaxOrderNotionalMicros {
+			return reject(CheckOrderNotional, order, notional, l.MaxOrderNotionalMicros)
+		}
+	}
+
+	// Worst case on the order's side: the open orders on that side fill first
+	pos, _ := r.positions.Lookup(order.Exchange, order.Symbol)
+	base := safe.SafeAdd(pos.QtySats, open.BuySats)
+	next := safe.SafeAdd(base, order.QtySats)
+	if order.Side == domain.SideSell {
+		base = safe.SafeSub(pos.QtySats, open.SellSats)
+		next = safe.SafeSub(base, order.QtySats)
+	}
+	if abs(next) <= abs(base) {
+		return nil // Reduces the position
+	}
+	if l.MaxPositionSats > 0 && abs(next) > l.MaxPositionSats {
+		return reject(CheckPosition, order, abs(next), l.MaxPositionSats)
+	}
+	if l.DailyLossMicros > 0 {
+		if loss := safe.SafeSub(r.dayPnL, r.positions.NetPnLMicros()); loss >= l.DailyLossMicros {
+			return reject(CheckDailyLoss, order, loss, l.DailyLossMicros)
+		}
+	}
+	return r.checkPortfolio(order, next, price)
+}
+
+func reject(check string, order *domain.Order, value, limit int64) error {
+	return &RiskRejectedError{Check: check, Symbol: order.Symbol, Value: value, Limit: limit}
+}
+
+// Rejected returns the number of orders refused so far.
+func (r *Engine) Rejected() uint64 {
+	return r.rejected
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
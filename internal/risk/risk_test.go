@@ -0,0 +1,150 @@
+package risk
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"errors"
+	"testing"
+)
+
+const day = quant.TimeStamp(microsPerDay)
+
+func buy(qty int64) *domain.Order {
+	return &domain.Order{Symbol: "BTC", Side: domain.SideBuy, QtySats: qty}
+}
+
+func sell(qty int64) *domain.Order {
+	return &domain.Order{Symbol: "BTC", Side: domain.SideSell, QtySats: qty}
+}
+
+// checkName returns the failed check of err, or "" for nil.
+func checkName(t *testing.T, err error) string {
+	t.Helper()
+	if err == nil {
+		return ""
+	}
+	var rr *RiskRejectedError
+	if !errors.As(err, &rr) {
+		t.Fatalf("unexpected error type %T: %v", err, err)
+	}
+	return rr.Check
+}
+
+func TestEngine_Checks(t *testing.T) {
+	positions := domain.NewPositionBook()
+	positions.Get("", "BTC").ApplyFill(domain.SideBuy, quant.QtyScale, 100_000_000, 1) // Long 1 BTC at 100
+
+	tests := []struct {
+		name      string
+		limits    Limits
+		order     *domain.Order
+		orderType string
+		price     int64
+		open      int
+		want      string
+	}{
+		{"no limits", Limits{}, buy(5 * quant.QtyScale), domain.OrderTypeMarket, 0, 9, ""},
+		{"open orders at limit", Limits{MaxOpenOrders: 2}, buy(1), domain.OrderTypeMarket, 0, 2, CheckOpenOrders},
+		{"open orders below limit", Limits{MaxOpenOrders: 2}, buy(1), domain.OrderTypeMarket, 0, 1, ""},
+		{"notional at last price", Limits{MaxOrderNotionalMicros: 99_000_000}, buy(quant.QtyScale), domain.OrderTypeMarket, 0, 0, CheckOrderNotional},
+		{"notional at limit price", Limits{MaxOrderNotionalMicros: 99_000_000}, buy(quant.QtyScale), domain.OrderTypeLimit, 99_000_000, 0, ""},
+		{"collar inside", Limits{PriceCollarBps: 500}, buy(1), domain.OrderTypeLimit, 105_000_000, 0, ""},
+		{"collar outside", Limits{PriceCollarBps: 500}, buy(1), domain.OrderTypeLimit, 94_000_000, 0, CheckPriceCollar},
+		{"collar ignores market orders", Limits{PriceCollarBps: 1}, buy(1), domain.OrderTypeMarket, 0, 0, ""},
+		{"position grows past limit", Limits{MaxPositionSats: quant.QtyScale}, buy(1), domain.OrderTypeMarket, 0, 0, CheckPosition},
+		{"reducing always passes", Limits{MaxPositionSats: 1}, sell(quant.QtyScale / 2), domain.OrderTypeMarket, 0, 0, ""},
+		{"flip past limit", Limits{MaxPositionSats: quant.QtyScale}, sell(5 * quant.QtyScale / 2), domain.OrderTypeMarket, 0, 0, CheckPosition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewEngine(tt.limits, positions)
+			r.Observe("", "BTC", 100_000_000, 0)
+			if got := checkName(t, r.Check(tt.order, tt.orderType, tt.price, Open{Orders: tt.open}, 0)); got != tt.want {
+				t.Errorf("check = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_OpenOrdersCountTowardsPosition(t *testing.T) {
+	positions := domain.NewPositionBook()
+	positions.Get("", "BTC").ApplyFill(domain.SideSell, quant.QtyScale, 100_000_000, 1) // Short 1 BTC
+	r := NewEngine(Limits{MaxPositionSats: quant.QtyScale / 2}, positions)
+	r.Observe("", "BTC", 100_000_000, 0)
+
+	// Alone, a half-BTC buy reduces the short
+	if err := r.Check(buy(quant.QtyScale/2), domain.OrderTypeMarket, 0, Open{}, 0); err != nil {
+		t.Errorf("reducing buy must pass: %v", err)
+	}
+	// With a resting buy closing the short already, it opens a long
+	if got := checkName(t, r.Check(buy(quant.QtyScale), domain.OrderTypeMarket, 0, Open{Orders: 1, BuySats: quant.QtyScale}, 0)); got != CheckPosition {
+		t.Errorf("buy past the resting close: check = %q, want %q", got, CheckPosition)
+	}
+	// Resting orders on the other side do not count for this one
+	if err := r.Check(buy(quant.QtyScale/2), domain.OrderTypeMarket, 0, Open{Orders: 1, SellSats: quant.QtyScale}, 0); err != nil {
+		t.Errorf("resting sells must not block a reducing buy: %v", err)
+	}
+
+	// Resting orders add up towards the limit
+	flat := NewEngine(Limits{MaxPositionSats: quant.QtyScale}, domain.NewPositionBook())
+	if err := flat.Check(buy(quant.QtyScale), domain.OrderTypeMarket, 0, Open{}, 0); err != nil {
+		t.Errorf("buy up to the limit must pass: %v", err)
+	}
+	if got := checkName(t, flat.Check(buy(1), domain.OrderTypeMarket, 0, Open{Orders: 1, BuySats: quant.QtyScale}, 0)); got != CheckPosition {
+		t.Errorf("buy on top of a resting buy: check = %q, want %q", got, CheckPosition)
+	}
+}
+
+func TestEngine_NoReferencePrice(t *testing.T) {
+	r := NewEngine(Limits{PriceCollarBps: 100}, domain.NewPositionBook())
+	if got := checkName(t, r.Check(buy(1), domain.OrderTypeLimit, 100, Open{}, 0)); got != CheckNoPrice {
+		t.Errorf("collar without a trade: check = %q, want %q", got, CheckNoPrice)
+	}
+
+	// Prices are per venue: a routed order is not collared by the default feed
+	r.Observe("", "BTC", 100, 0)
+	routed := buy(1)
+	routed.Exchange = "UPBIT"
+	if got := checkName(t, r.Check(routed, domain.OrderTypeLimit, 100, Open{}, 0)); got != CheckNoPrice {
+		t.Errorf("routed order: check = %q, want %q", got, CheckNoPrice)
+	}
+	if r.Rejected() != 2 {
+		t.Errorf("rejected = %d, want 2", r.Rejected())
+	}
+}
+
+func TestEngine_DailyLoss(t *testing.T) {
+	positions := domain.NewPositionBook()
+	p := positions.Get("", "BTC")
+	p.ApplyFill(domain.SideBuy, quant.QtyScale, 100_000_000, 1)
+	r := NewEngine(Limits{DailyLossMicros: 10_000_000}, positions)
+	r.Observe("", "BTC", 100_000_000, day+1) // Day opens flat
+
+	p.Mark(91_000_000, 2)
+	if err := r.Check(buy(1), domain.OrderTypeMarket, 0, Open{}, day+2); err != nil {
+		t.Errorf("loss below the limit must pass: %v", err)
+	}
+
+	p.Mark(90_000_000, 3)
+	if got := checkName(t, r.Check(buy(1), domain.OrderTypeMarket, 0, Open{}, day+3)); got != CheckDailyLoss {
+		t.Errorf("check = %q, want %q", got, CheckDailyLoss)
+	}
+	if err := r.Check(sell(1), domain.OrderTypeMarket, 0, Open{}, day+3); err != nil {
+		t.Errorf("closing must pass after the loss limit: %v", err)
+	}
+
+	// A new UTC day starts from the current PnL
+	if err := r.Check(buy(1), domain.OrderTypeMarket, 0, Open{}, 2*day); err != nil {
+		t.Errorf("new day must reset the loss window: %v", err)
+	}
+}
+
+func TestEngine_SetLimit(t *testing.T) {
+	r := NewEngine(Limits{}, domain.NewPositionBook())
+	if !r.SetLimit(LimitMaxOpenOrders, 1) || r.SetLimit("max_order_qty_sats", 1) {
+		t.Fatal("SetLimit must accept risk keys only")
+	}
+	if got := checkName(t, r.Check(buy(1), domain.OrderTypeMarket, 0, Open{Orders: 1}, 0)); got != CheckOpenOrders {
+		t.Errorf("check = %q, want %q", got, CheckOpenOrders)
+	}
+}
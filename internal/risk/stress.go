@@ -0,0 +1,178 @@
+package risk
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+	"sort"
+)
+
+// AllSymbols is the Scenario.PriceBps key that moves every symbol without an
+// entry of its own.
+const AllSymbols = "*"
+
+// Scenario is a set of instantaneous shocks applied to the open positions.
+type Scenario struct {
+	Name       string
+	PriceBps   map[string]int64 // Move of the USD(T) price per symbol (-2000 = -20%)
+	FXBps      int64            // Move of USD/KRW
+	PremiumBps *int64           // Kimchi premium KRW prices move to (nil = unchanged, 0 = collapse)
+}
+
+// DefaultScenarios are run when none are configured: BTC -20%, premium
+// collapse to 0 and FX +5%.
+func DefaultScenarios() []Scenario {
+	zero := int64(0)
+	return []Scenario{
+		{Name: "btc_-20%", PriceBps: map[string]int64{"BTC": -2000}},
+		{Name: "premium_collapse", PremiumBps: &zero},
+		{Name: "fx_+5%", FXBps: 500},
+	}
+}
+
+// StressMarket is the market a scenario starts from.
+type StressMarket struct {
+	PremiumMicros map[string]int64 // Current kimchi premium per symbol (1% = 10,000)
+	DefaultVenue  string           // Venue of default-venue ("") positions
+}
+
+// MarginModel values futures positions as isolated margin: Leverage sets the
+// margin posted at entry, MaintenanceBps the share of the notional below which
+// the venue liquidates.
+type MarginModel struct {
+	Leverage       int64
+	MaintenanceBps int64
+}
+
+// StressPosition is one open position under a scenario. Amounts are in the
+// quote currency of its venue.
+type StressPosition struct {
+	Exchange      string            `json:"exchange"`
+	Symbol        string            `json:"symbol"`
+	Class         domain.AssetClass `json:"class"`
+	QtySats       int64             `json:"qty,string"`
+	MarkMicros    int64             `json:"mark,string"`
+	ShockedMicros int64             `json:"shocked,string"`
+	PnLMicros     int64             `json:"pnl,string"`           // Change of the position value
+	NoPremium     bool              `json:"no_premium,omitempty"` // Premium shock skipped: current premium unknown
+
+	// Futures only (isolated margin at the shocked mark)
+	MarginMicros      int64 `json:"margin,string,omitempty"`      // Posted margin plus unrealized PnL
+	MarginUsageBps    int64 `json:"margin_usage_bps,omitempty"`   // Maintenance margin / MarginMicros (>= 10000 = liquidated)
+	LiquidationMicros int64 `json:"liquidation,string,omitempty"` // Liquidation price
+	LiqDistanceBps    int64 `json:"liq_distance_bps,omitempty"`   // Adverse move from the shocked mark to liquidation (<= 0 = liquidated)
+}
+
+// Liquidated reports whether the shocked mark is past the liquidation price.
+func (p StressPosition) Liquidated() bool {
+	return p.Class.Market == domain.MarketFutures && p.LiquidationMicros > 0 && p.LiqDistanceBps <= 0
+}
+
+// QuotePnL is the scenario PnL of the positions quoted in one currency.
+type QuotePnL struct {
+	Quote     string `json:"quote"`
+	PnLMicros int64  `json:"pnl,string"`
+}
+
+// StressReport is the result of one scenario.
+type StressReport struct {
+	Scenario  string           `json:"scenario"`
+	Positions []StressPosition `json:"positions"`
+	PnL       []QuotePnL       `json:"pnl"` // By quote currency
+}
+
+// Stress applies sc to the open positions (as in a snapshot). It reads
+// positions only.
+func Stress(positions map[string]domain.Position, market StressMarket, margin MarginModel, sc Scenario) StressReport {
+	rep := StressReport{Scenario: sc.Name}
+	byQuote := make(map[string]int64)
+	for _, p := range positions {
+		if p.QtySats == 0 {
+			continue
+		}
+		sp := stressPosition(p, market, margin, sc)
+		byQuote[sp.Class.Quote] = safe.SafeAdd(byQuote[sp.Class.Quote], sp.PnLMicros)
+		rep.Positions = append(rep.Positions, sp)
+	}
+	sort.Slice(rep.Positions, func(i, j int) bool {
+		a, b := rep.Positions[i], rep.Positions[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Exchange < b.Exchange
+	})
+	for quote, pnl := range byQuote {
+		rep.PnL = append(rep.PnL, QuotePnL{Quote: quote, PnLMicros: pnl})
+	}
+	sort.Slice(rep.PnL, func(i, j int) bool { return rep.PnL[i].Quote < rep.PnL[j].Quote })
+	return rep
+}
+
+func stressPosition(p domain.Position, market StressMarket, margin MarginModel, sc Scenario) StressPosition {
+	exchange := p.Exchange
+	if exchange == "" {
+		exchange = market.DefaultVenue
+	}
+	class, _ := domain.VenueAssetClass(exchange)
+	mark := p.MarkPriceMicros
+	if mark <= 0 {
+		mark = p.AvgEntryPriceMicros
+	}
+	sp := StressPosition{Exchange: exchange, Symbol: p.Symbol, Class: class, QtySats: p.QtySats, MarkMicros: mark}
+
+	move, ok := sc.PriceBps[p.Symbol]
+	if !ok {
+		move = sc.PriceBps[AllSymbols]
+	}
+	shocked := safe.SafeMulDiv(mark, bpsScale+move, bpsScale)
+	if class.Quote == domain.QuoteKRW {
+		// KRW price = USD price × USD/KRW × (1 + premium)
+		shocked = safe.SafeMulDiv(shocked, bpsScale+sc.FXBps, bpsScale)
+		if sc.PremiumBps != nil {
+			if current, known := market.PremiumMicros[p.Symbol]; known {
+				target := *sc.PremiumBps * (quant.PriceScale / bpsScale)
+				shocked = safe.SafeMulDiv(shocked, quant.PriceScale+target, quant.PriceScale+current)
+			} else {
+				sp.NoPremium = true
+			}
+		}
+	}
+	sp.ShockedMicros = max(0, shocked)
+	sp.PnLMicros = safe.SafeMulDiv(p.QtySats, sp.ShockedMicros-mark, quant.QtyScale)
+
+	if class.Market == domain.MarketFutures && margin.Leverage > 0 && p.AvgEntryPriceMicros > 0 {
+		isolatedMargin(&sp, p.AvgEntryPriceMicros, margin)
+	}
+	return sp
+}
+
+// isolatedMargin fills the margin fields of a futures position entered at entry.
+func isolatedMargin(sp *StressPosition, entry int64, m MarginModel) {
+	qty := abs(sp.QtySats)
+	posted := safe.SafeMulDiv(safe.SafeMulDiv(qty, entry, quant.QtyScale), 1, m.Leverage)
+	upnl := safe.SafeMulDiv(sp.QtySats, sp.ShockedMicros-entry, quant.QtyScale)
+	sp.MarginMicros = safe.SafeAdd(posted, upnl)
+	maintenance := safe.SafeMulDiv(safe.SafeMulDiv(qty, sp.ShockedMicros, quant.QtyScale), m.MaintenanceBps, bpsScale)
+	if sp.MarginMicros > 0 {
+		sp.MarginUsageBps = safe.SafeMulDiv(maintenance, bpsScale, sp.MarginMicros)
+	} else {
+		sp.MarginUsageBps = bpsScale
+	}
+
+	// Long: entry × (1 - 1/L) / (1 - mm); short: entry × (1 + 1/L) / (1 + mm)
+	lev := m.Leverage * bpsScale
+	if sp.QtySats > 0 {
+		sp.LiquidationMicros = safe.SafeMulDiv(safe.SafeMulDiv(entry, lev-bpsScale, lev), bpsScale, bpsScale-m.MaintenanceBps)
+	} else {
+		sp.LiquidationMicros = safe.SafeMulDiv(safe.SafeMulDiv(entry, lev+bpsScale, lev), bpsScale, bpsScale+m.MaintenanceBps)
+	}
+	if sp.ShockedMicros > 0 {
+		dist := sp.ShockedMicros - sp.LiquidationMicros
+		if sp.QtySats < 0 {
+			dist = -dist
+		}
+		sp.LiqDistanceBps = safe.SafeMulDiv(dist, bpsScale, sp.ShockedMicros)
+	} else {
+		sp.LiqDistanceBps = -bpsScale
+	}
+}
@@ -0,0 +1,99 @@
+package risk
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+// stressPositions: 1 BTC long on UPBIT at 100M KRW, 1 BTC long at 50,000 on
+// the default venue (BITGET_FUTURES) and 10 ETH short at 2,000 on OKX_SWAP.
+func stressPositions() map[string]domain.Position {
+	positions := domain.NewPositionBook()
+	positions.Get("UPBIT", "BTC").ApplyFill(domain.SideBuy, quant.QtyScale, 100_000_000*quant.PriceScale, 1)
+	positions.Get("", "BTC").ApplyFill(domain.SideBuy, quant.QtyScale, 50_000*quant.PriceScale, 2)
+	positions.Get("OKX_SWAP", "ETH").ApplyFill(domain.SideSell, 10*quant.QtyScale, 2_000*quant.PriceScale, 3)
+	return positions.Snapshot()
+}
+
+func stressMarket() StressMarket {
+	return StressMarket{
+		PremiumMicros: map[string]int64{"BTC": 50_000}, // 5%
+		DefaultVenue:  "BITGET_FUTURES",
+	}
+}
+
+func findStressed(t *testing.T, rep StressReport, exchange, symbol string) StressPosition {
+	t.Helper()
+	for _, p := range rep.Positions {
+		if p.Exchange == exchange && p.Symbol == symbol {
+			return p
+		}
+	}
+	t.Fatalf("%s %s missing from %s", exchange, symbol, rep.Scenario)
+	return StressPosition{}
+}
+
+func TestStress_PriceShock(t *testing.T) {
+	margin := MarginModel{Leverage: 5, MaintenanceBps: 50}
+	rep := Stress(stressPositions(), stressMarket(), margin, DefaultScenarios()[0])
+
+	upbit := findStressed(t, rep, "UPBIT", "BTC")
+	if upbit.ShockedMicros != 80_000_000*quant.PriceScale || upbit.PnLMicros != -20_000_000*quant.PriceScale {
+		t.Errorf("UPBIT BTC: shocked %d, pnl %d", upbit.ShockedMicros, upbit.PnLMicros)
+	}
+	if upbit.MarginMicros != 0 || upbit.Liquidated() {
+		t.Errorf("spot position has margin fields: %+v", upbit)
+	}
+
+	// 5x long from 50,000: liquidation at 40,000 × 1/0.995, below the shocked 40,000
+	fut := findStressed(t, rep, "BITGET_FUTURES", "BTC")
+	if fut.ShockedMicros != 40_000*quant.PriceScale || fut.PnLMicros != -10_000*quant.PriceScale {
+		t.Errorf("futures BTC: shocked %d, pnl %d", fut.ShockedMicros, fut.PnLMicros)
+	}
+	if fut.LiquidationMicros != 40_201_005_025 || !fut.Liquidated() || fut.MarginUsageBps < bpsScale {
+		t.Errorf("futures BTC should be liquidated: %+v", fut)
+	}
+
+	eth := findStressed(t, rep, "OKX_SWAP", "ETH")
+	if eth.PnLMicros != 0 || eth.Liquidated() || eth.LiqDistanceBps <= 0 {
+		t.Errorf("unshocked ETH short: %+v", eth)
+	}
+	// 10 × 2,000 / 5 posted, 0.5% of 20,000 maintenance
+	if eth.MarginMicros != 4_000*quant.PriceScale || eth.MarginUsageBps != 250 {
+		t.Errorf("ETH margin %d, usage %d bps", eth.MarginMicros, eth.MarginUsageBps)
+	}
+
+	want := []QuotePnL{{Quote: "KRW", PnLMicros: -20_000_000 * quant.PriceScale}, {Quote: "USDT", PnLMicros: -10_000 * quant.PriceScale}}
+	if len(rep.PnL) != 2 || rep.PnL[0] != want[0] || rep.PnL[1] != want[1] {
+		t.Errorf("PnL = %+v; want %+v", rep.PnL, want)
+	}
+}
+
+func TestStress_PremiumAndFX(t *testing.T) {
+	scenarios := DefaultScenarios()
+
+	// 5% premium collapses: 100M / 1.05
+	rep := Stress(stressPositions(), stressMarket(), MarginModel{}, scenarios[1])
+	if got := findStressed(t, rep, "UPBIT", "BTC").ShockedMicros; got != 95_238_095_238_095 {
+		t.Errorf("premium collapse: shocked %d", got)
+	}
+	if got := findStressed(t, rep, "BITGET_FUTURES", "BTC"); got.PnLMicros != 0 || got.MarginMicros != 0 {
+		t.Errorf("premium shock moved a USDT position or margin without leverage: %+v", got)
+	}
+
+	// FX +5% moves KRW prices only
+	rep = Stress(stressPositions(), stressMarket(), MarginModel{}, scenarios[2])
+	if got := findStressed(t, rep, "UPBIT", "BTC").PnLMicros; got != 5_000_000*quant.PriceScale {
+		t.Errorf("FX +5%%: pnl %d", got)
+	}
+	if got := findStressed(t, rep, "OKX_SWAP", "ETH").PnLMicros; got != 0 {
+		t.Errorf("FX moved a USDT position: %d", got)
+	}
+
+	// Unknown premium: flagged, price left alone
+	rep = Stress(stressPositions(), StressMarket{DefaultVenue: "BITGET_FUTURES"}, MarginModel{}, scenarios[1])
+	if got := findStressed(t, rep, "UPBIT", "BTC"); !got.NoPremium || got.PnLMicros != 0 {
+		t.Errorf("unknown premium: %+v", got)
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAnnotationNotFound is returned for an unknown annotation id.
+var ErrAnnotationNotFound = errors.New("annotation not found")
+
+// SaveAnnotation normalizes and stores a, returning it with its id.
+func (s *EventStore) SaveAnnotation(ctx context.Context, a domain.Annotation) (domain.Annotation, error) {
+	if err := a.Normalize(); err != nil {
+		return a, err
+	}
+	err := s.mutate(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO annotations (kind, target, note, tags, created_at) VALUES (?, ?, ?, ?, ?)",
+			a.Kind, a.Target, a.Note, strings.Join(a.Tags, ","), a.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert annotation: %w", err)
+		}
+		a.ID, err = res.LastInsertId()
+		return err
+	})
+	return a, err
+}
+
+// ListAnnotations returns annotations oldest first. Empty kind/target match everything.
+func (s *EventStore) ListAnnotations(ctx context.Context, kind, target string) ([]domain.Annotation, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, kind, target, note, tags, created_at FROM annotations WHERE (? = '' OR kind = ?) AND (? = '' OR target = ?) ORDER BY id ASC",
+		kind, kind, target, target,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Annotation
+	for rows.Next() {
+		var a domain.Annotation
+		var tags string
+		if err := rows.Scan(&a.ID, &a.Kind, &a.Target, &a.Note, &tags, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan annotation: %w", err)
+		}
+		if tags != "" {
+			a.Tags = strings.Split(tags, ",")
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteAnnotation removes id.
+func (s *EventStore) DeleteAnnotation(ctx context.Context, id int64) error {
+	return s.mutate(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM annotations WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete annotation %d: %w", id, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrAnnotationNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ConsumerCursorKey is the metadata key of an external consumer's cursor.
+func ConsumerCursorKey(name string) string {
+	return "consumer:" + name
+}
+
+// ConsumerCursor returns the last seq consumer name acknowledged (0 = none).
+func (s *EventStore) ConsumerCursor(ctx context.Context, name string) (uint64, error) {
+	raw, err := s.GetMetadata(ctx, ConsumerCursorKey(name))
+	if err != nil || raw == "" {
+		return 0, err
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+// AckConsumer records that consumer name processed every event up to seq and
+// returns its cursor. An ack at or below the cursor (a redelivered batch) does
+// not move it back unless reset is set.
+func (s *EventStore) AckConsumer(ctx context.Context, name string, seq uint64, reset bool, ts int64) (uint64, error) {
+	cursor := seq
+	err := s.UpdateMetadata(ctx, ConsumerCursorKey(name), ts, func(current string) (string, error) {
+		if current != "" && !reset {
+			prev, err := strconv.ParseUint(current, 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("corrupt cursor of consumer %s: %w", name, err)
+			}
+			cursor = max(prev, seq)
+		}
+		return strconv.FormatUint(cursor, 10), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return cursor, nil
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestConsumerCursor(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if c, err := store.ConsumerCursor(ctx, "etl"); err != nil || c != 0 {
+		t.Fatalf("new consumer cursor = %d, %v; want 0", c, err)
+	}
+	for _, tc := range []struct {
+		seq   uint64
+		reset bool
+		want  uint64
+	}{
+		{10, false, 10},
+		{7, false, 10}, // Redelivered batch: no rewind
+		{25, false, 25},
+		{5, true, 5},
+	} {
+		if c, err := store.AckConsumer(ctx, "etl", tc.seq, tc.reset, 0); err != nil || c != tc.want {
+			t.Errorf("ack %d (reset %v) = %d, %v; want %d", tc.seq, tc.reset, c, err, tc.want)
+		}
+	}
+	if c, _ := store.ConsumerCursor(ctx, "etl"); c != 5 {
+		t.Errorf("cursor = %d, want 5", c)
+	}
+	if c, _ := store.ConsumerCursor(ctx, "other"); c != 0 {
+		t.Errorf("cursors must be per consumer, got %d", c)
+	}
+}
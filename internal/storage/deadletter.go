@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Dead letter stages: where processing gave up on the event.
+const (
+	// DeadLetterStagePersist: the WAL write kept failing. The event was never
+	// sequenced, so requeueing feeds it through the Sequencer again.
+	DeadLetterStagePersist = "PERSIST"
+	// DeadLetterStageDispatch: the event is in the WAL (Seq) but its handler
+	// panicked. Replay skips it until it is requeued.
+	DeadLetterStageDispatch = "DISPATCH"
+)
+
+// ErrDeadLetterNotFound is returned for an unknown dead letter id.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter is a quarantined event with the context needed to diagnose it.
+type DeadLetter struct {
+	ID        int64
+	Seq       uint64 // WAL seq (DISPATCH stage); 0 = never written
+	Stage     string
+	Type      event.Type
+	Payload   []byte // JSON for inspection; decodes like a WAL payload
+	Error     string
+	Stack     string
+	Attempts  int
+	CreatedAt int64 // Unix micros
+	Requeued  bool  // Marked for reprocessing at the next start
+}
+
+// Event decodes the quarantined payload. Returns (nil, nil) for unknown types.
+func (d DeadLetter) Event() (event.Event, error) {
+	return decodeEvent(d.Type, d.Payload)
+}
+
+// SaveDeadLetter stores d and returns its id.
+func (s *EventStore) SaveDeadLetter(ctx context.Context, d DeadLetter) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		"INSERT INTO dead_letters (seq, stage, type, payload, error, stack, attempts, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		d.Seq, d.Stage, d.Type, d.Payload, d.Error, d.Stack, d.Attempts, d.CreatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert dead letter: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// ListDeadLetters returns all dead letters, oldest first.
+func (s *EventStore) ListDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, seq, stage, type, payload, error, stack, attempts, created_at, requeued FROM dead_letters ORDER BY id ASC",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var out []DeadLetter
+	for rows.Next() {
+		d, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return out, nil
+}
+
+// GetDeadLetter returns one dead letter by id.
+func (s *EventStore) GetDeadLetter(ctx context.Context, id int64) (DeadLetter, error) {
+	row := s.db.QueryRowContext(ctx,
+		"SELECT id, seq, stage, type, payload, error, stack, attempts, created_at, requeued FROM dead_letters WHERE id = ?",
+		id,
+	)
+	d, err := scanDeadLetter(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	return d, err
+}
+
+// RequeueDeadLetter marks id for reprocessing at the next start.
+func (s *EventStore) RequeueDeadLetter(ctx context.Context, id int64) error {
+	return s.execDeadLetter(ctx, "UPDATE dead_letters SET requeued = 1 WHERE id = ?", id)
+}
+
+// DeleteDeadLetter removes id.
+func (s *EventStore) DeleteDeadLetter(ctx context.Context, id int64) error {
+	return s.execDeadLetter(ctx, "DELETE FROM dead_letters WHERE id = ?", id)
+}
+
+// QuarantinedSeqs returns the WAL seqs replay must skip (DISPATCH stage, not requeued).
+func (s *EventStore) QuarantinedSeqs(ctx context.Context) (map[uint64]bool, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT seq FROM dead_letters WHERE stage = ? AND requeued = 0",
+		DeadLetterStageDispatch,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quarantined seqs: %w", err)
+	}
+	defer rows.Close()
+
+	seqs := make(map[uint64]bool)
+	for rows.Next() {
+		var seq uint64
+		if err := rows.Scan(&seq); err != nil {
+			return nil, fmt.Errorf("failed to scan seq: %w", err)
+		}
+		seqs[seq] = true
+	}
+	return seqs, rows.Err()
+}
+
+func (s *EventStore) execDeadLetter(ctx context.Context, query string, id int64) error {
+	return s.mutate(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to update dead letter %d: %w", id, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrDeadLetterNotFound
+		}
+		return nil
+	})
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDeadLetter(r rowScanner) (DeadLetter, error) {
+	var d DeadLetter
+	var evType int
+	if err := r.Scan(&d.ID, &d.Seq, &d.Stage, &evType, &d.Payload, &d.Error, &d.Stack, &d.Attempts, &d.CreatedAt, &d.Requeued); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return d, err
+		}
+		return d, fmt.Errorf("failed to scan dead letter: %w", err)
+	}
+	d.Type = event.Type(evType)
+	return d, nil
+}
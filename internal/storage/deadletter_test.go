@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"crypto_go/internal/event"
+)
+
+func TestDeadLetters_Lifecycle(t *testing.T) {
+	store, err := NewEventStore(t.TempDir() + "/dl.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(&event.ControlEvent{Command: event.CmdHalt, Reason: "x"})
+	id, err := store.SaveDeadLetter(ctx, DeadLetter{
+		Seq:      7,
+		Stage:    DeadLetterStageDispatch,
+		Type:     event.EvControl,
+		Payload:  payload,
+		Error:    "boom",
+		Stack:    "goroutine 1",
+		Attempts: 1,
+	})
+	if err != nil {
+		t.Fatalf("SaveDeadLetter failed: %v", err)
+	}
+
+	d, err := store.GetDeadLetter(ctx, id)
+	if err != nil || d.Seq != 7 || d.Error != "boom" || d.Requeued {
+		t.Fatalf("unexpected letter %+v (%v)", d, err)
+	}
+	ev, err := d.Event()
+	if ce, ok := ev.(*event.ControlEvent); err != nil || !ok || ce.Command != event.CmdHalt {
+		t.Errorf("payload did not decode: %#v (%v)", ev, err)
+	}
+
+	if seqs, _ := store.QuarantinedSeqs(ctx); !seqs[7] {
+		t.Error("seq 7 should be quarantined")
+	}
+	if err := store.RequeueDeadLetter(ctx, id); err != nil {
+		t.Fatalf("RequeueDeadLetter failed: %v", err)
+	}
+	if seqs, _ := store.QuarantinedSeqs(ctx); seqs[7] {
+		t.Error("requeued seq must no longer be skipped")
+	}
+
+	if err := store.DeleteDeadLetter(ctx, id); err != nil {
+		t.Fatalf("DeleteDeadLetter failed: %v", err)
+	}
+	if _, err := store.GetDeadLetter(ctx, id); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+	if err := store.RequeueDeadLetter(ctx, id); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound on requeue, got %v", err)
+	}
+}
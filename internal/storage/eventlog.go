@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// mirrorRetryInterval spaces the backfills of a mirror that fell behind.
+const mirrorRetryInterval = time.Second
+
+// EventLog is the append-only event log the Sequencer writes before
+// dispatching each event (WAL-first) and replays on recovery. Seqs are
+// strictly increasing. Implemented by the SQLite EventStore and FileWAL.
+type EventLog interface {
+	Append(ctx context.Context, ev event.Event) error
+	ReadFrom(ctx context.Context, fromSeq uint64) ([]event.Event, error)
+	LastSeq(ctx context.Context) (uint64, error)
+	Sync() error
+}
+
+var (
+	_ EventLog = (*EventStore)(nil)
+	_ EventLog = (*FileWAL)(nil)
+	_ EventLog = (*MirroredLog)(nil)
+)
+
+// Append implements EventLog (SaveEvent).
+func (s *EventStore) Append(ctx context.Context, ev event.Event) error {
+	return s.SaveEvent(ctx, ev)
+}
+
+// ReadFrom implements EventLog (LoadEvents).
+func (s *EventStore) ReadFrom(ctx context.Context, fromSeq uint64) ([]event.Event, error) {
+	return s.LoadEvents(ctx, fromSeq)
+}
+
+// LastSeq implements EventLog (GetLastSeq).
+func (s *EventStore) LastSeq(ctx context.Context) (uint64, error) {
+	return s.GetLastSeq(ctx)
+}
+
+// Sync checkpoints the SQLite WAL into the database file. With
+// synchronous=NORMAL, commits since the last checkpoint are not yet fsynced.
+func (s *EventStore) Sync() error {
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(PASSIVE);"); err != nil {
+		return fmt.Errorf("failed to checkpoint sqlite wal: %w", err)
+	}
+	return nil
+}
+
+// MirroredLog writes to a durable primary log and copies every event to a
+// mirror, e.g. a FileWAL as the primary and the SQLite event table the
+// analytics queries and followers read as the mirror. Reads go to the primary;
+// a failed mirror write is logged but does not fail the append. The mirror
+// then takes no further events until it is backfilled from the primary, at a
+// later append or the next start, so it never has a hole.
+type MirroredLog struct {
+	primary EventLog
+	mirror  EventLog
+
+	behind  uint64    // First seq the mirror missed (0 = in step)
+	retryAt time.Time // Next backfill attempt
+}
+
+// NewMirroredLog reconciles the two logs and returns the combination. The log
+// that is behind is filled from the other: a new primary adopts the history of
+// an existing mirror, and a mirror that missed its last writes (crash between
+// the two appends) catches up from the primary.
+func NewMirroredLog(ctx context.Context, primary, mirror EventLog) (*MirroredLog, error) {
+	p, err := primary.LastSeq(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read primary last seq: %w", err)
+	}
+	m, err := mirror.LastSeq(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror last seq: %w", err)
+	}
+
+	switch {
+	case p < m:
+		n, err := copyEvents(ctx, mirror, primary, p+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seed primary log: %w", err)
+		}
+		if err := primary.Sync(); err != nil {
+			return nil, err
+		}
+		slog.Info("EVENT_LOG_SEEDED", slog.Int("events", n), slog.Uint64("last_seq", m))
+	case m < p:
+		n, err := copyEvents(ctx, primary, mirror, m+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to catch up mirror log: %w", err)
+		}
+		slog.Info("EVENT_MIRROR_CAUGHT_UP", slog.Int("events", n), slog.Uint64("last_seq", p))
+	}
+	return &MirroredLog{primary: primary, mirror: mirror}, nil
+}
+
+func copyEvents(ctx context.Context, from, to EventLog, fromSeq uint64) (int, error) {
+	events, err := from.ReadFrom(ctx, fromSeq)
+	if err != nil {
+		return 0, err
+	}
+	for _, ev := range events {
+		if err := to.Append(ctx, ev); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+// Append writes ev to the primary, then to the mirror, or backfills a mirror
+// that is behind (ev included).
+func (l *MirroredLog) Append(ctx context.Context, ev event.Event) error {
+	if err := l.primary.Append(ctx, ev); err != nil {
+		return err
+	}
+	if l.behind != 0 {
+		l.backfill(ctx)
+		return nil
+	}
+	if err := l.mirror.Append(ctx, ev); err != nil {
+		slog.Warn("EVENT_MIRROR_FAILED", slog.Uint64("seq", ev.GetSeq()), slog.Any("error", err))
+		l.behind = ev.GetSeq()
+		l.retryAt = time.Now().Add(mirrorRetryInterval)
+	}
+	return nil
+}
+
+// backfill copies the events the mirror is missing from the primary.
+func (l *MirroredLog) backfill(ctx context.Context) {
+	if time.Now().Before(l.retryAt) {
+		return
+	}
+	l.retryAt = time.Now().Add(mirrorRetryInterval)
+	last, err := l.mirror.LastSeq(ctx)
+	if err != nil {
+		slog.Warn("EVENT_MIRROR_BACKFILL_FAILED", slog.Uint64("from_seq", l.behind), slog.Any("error", err))
+		return
+	}
+	n, err := copyEvents(ctx, l.primary, l.mirror, last+1)
+	if err != nil {
+		slog.Warn("EVENT_MIRROR_BACKFILL_FAILED", slog.Uint64("from_seq", l.behind), slog.Any("error", err))
+		return
+	}
+	slog.Info("EVENT_MIRROR_BACKFILLED", slog.Uint64("from_seq", l.behind), slog.Int("events", n))
+	l.behind = 0
+}
+
+// ReadFrom reads the primary.
+func (l *MirroredLog) ReadFrom(ctx context.Context, fromSeq uint64) ([]event.Event, error) {
+	return l.primary.ReadFrom(ctx, fromSeq)
+}
+
+// LastSeq reads the primary.
+func (l *MirroredLog) LastSeq(ctx context.Context) (uint64, error) {
+	return l.primary.LastSeq(ctx)
+}
+
+// Sync syncs the primary.
+func (l *MirroredLog) Sync() error {
+	return l.primary.Sync()
+}
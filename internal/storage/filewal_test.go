@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func tickAt(seq uint64) *event.MarketUpdateEvent {
+	return &event.MarketUpdateEvent{
+		BaseEvent:   event.BaseEvent{Seq: seq, Ts: quant.TimeStamp(seq * 1000)},
+		Symbol:      "BTC",
+		PriceMicros: quant.PriceMicros(seq) * 1_000_000,
+		Exchange:    "UPBIT",
+	}
+}
+
+func appendTicks(t *testing.T, log EventLog, from, to uint64) {
+	t.Helper()
+	for seq := from; seq <= to; seq++ {
+		if err := log.Append(context.Background(), tickAt(seq)); err != nil {
+			t.Fatalf("append %d: %v", seq, err)
+		}
+	}
+}
+
+func TestFileWAL_SegmentsAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	w, err := OpenFileWAL(dir, FileWALOptions{SegmentBytes: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendTicks(t, w, 1, 10)
+	if err := w.Append(ctx, tickAt(10)); err == nil {
+		t.Error("a seq that is not increasing must be refused")
+	}
+	w.Close()
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.wal"))
+	if len(segments) < 2 {
+		t.Fatalf("expected several segments, got %v", segments)
+	}
+
+	w, err = OpenFileWAL(dir, FileWALOptions{SegmentBytes: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if last, _ := w.LastSeq(ctx); last != 10 {
+		t.Fatalf("last seq = %d, want 10", last)
+	}
+	appendTicks(t, w, 11, 12)
+
+	events, err := w.ReadFrom(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 9 || events[0].GetSeq() != 4 || events[8].GetSeq() != 12 {
+		t.Fatalf("expected seqs 4..12, got %d events", len(events))
+	}
+	if tick := events[0].(*event.MarketUpdateEvent); tick.PriceMicros != 4_000_000 || tick.Exchange != "UPBIT" {
+		t.Errorf("event not restored: %+v", tick)
+	}
+}
+
+func TestFileWAL_TornTail(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	w, _ := OpenFileWAL(dir, FileWALOptions{})
+	appendTicks(t, w, 1, 3)
+	w.Close()
+
+	// A crash mid-write leaves half a record behind
+	path := filepath.Join(dir, "00000000000000000001.wal")
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.Write([]byte{40, 0, 0, 0, 1, 2, 3})
+	f.Close()
+
+	w, err := OpenFileWAL(dir, FileWALOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if last, _ := w.LastSeq(ctx); last != 3 {
+		t.Fatalf("last seq = %d, want 3", last)
+	}
+	appendTicks(t, w, 4, 4)
+	if events, err := w.ReadFrom(ctx, 1); err != nil || len(events) != 4 {
+		t.Fatalf("expected 4 events after cutting the torn tail, got %d (%v)", len(events), err)
+	}
+}
+
+func TestFileWAL_CorruptRecord(t *testing.T) {
+	dir := t.TempDir()
+	w, _ := OpenFileWAL(dir, FileWALOptions{SegmentBytes: 300})
+	appendTicks(t, w, 1, 10)
+	w.Close()
+
+	// Flip a payload byte in the first (sealed) segment
+	path := filepath.Join(dir, "00000000000000000001.wal")
+	data, _ := os.ReadFile(path)
+	data[walHeaderSize+walBodyHeaderSize+2] ^= 0xff
+	os.WriteFile(path, data, 0o600)
+
+	w, err := OpenFileWAL(dir, FileWALOptions{SegmentBytes: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if _, err := w.ReadFrom(context.Background(), 1); !errors.Is(err, ErrWALCorrupt) {
+		t.Errorf("expected ErrWALCorrupt, got %v", err)
+	}
+}
+
+func TestFileWAL_UnknownEventType(t *testing.T) {
+	dir := t.TempDir()
+	w, _ := OpenFileWAL(dir, FileWALOptions{})
+	appendTicks(t, w, 1, 3)
+	w.Close()
+
+	// Retype the second record as an event this build does not know (valid CRC)
+	path := filepath.Join(dir, "00000000000000000001.wal")
+	data, _ := os.ReadFile(path)
+	rec := data[binary.LittleEndian.Uint32(data[0:4])+walHeaderSize:]
+	body := rec[walHeaderSize : walHeaderSize+binary.LittleEndian.Uint32(rec[0:4])]
+	binary.LittleEndian.PutUint16(body[0:2], 0xfff0)
+	binary.LittleEndian.PutUint32(rec[4:8], crc32.Checksum(body, walCRC))
+	os.WriteFile(path, data, 0o600)
+
+	w, err := OpenFileWAL(dir, FileWALOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if _, err := w.ReadFrom(context.Background(), 1); !errors.Is(err, ErrWALUnknownEvent) {
+		t.Errorf("expected ErrWALUnknownEvent, got %v", err)
+	}
+	if events, err := w.ReadFrom(context.Background(), 3); err != nil || len(events) != 1 {
+		t.Errorf("records before fromSeq are not decoded: %d events, %v", len(events), err)
+	}
+}
+
+// flakyLog fails its appends while down.
+type flakyLog struct {
+	EventLog
+	down bool
+}
+
+func (l *flakyLog) Append(ctx context.Context, ev event.Event) error {
+	if l.down {
+		return errors.New("mirror down")
+	}
+	return l.EventLog.Append(ctx, ev)
+}
+
+func TestMirroredLog_BackfillsMissedEvents(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	w, _ := OpenFileWAL(t.TempDir(), FileWALOptions{})
+	defer w.Close()
+	mirror := &flakyLog{EventLog: store}
+	log, err := NewMirroredLog(ctx, w, mirror)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appendTicks(t, log, 1, 2)
+	mirror.down = true
+	appendTicks(t, log, 3, 3)
+	mirror.down = false
+	appendTicks(t, log, 4, 4) // Waits for the retry interval: no event past the hole
+	if last, _ := store.LastSeq(ctx); last != 2 {
+		t.Fatalf("mirror last seq = %d; want 2 until backfilled", last)
+	}
+
+	log.retryAt = time.Time{}
+	appendTicks(t, log, 5, 5)
+	events, err := store.ReadFrom(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 || events[2].GetSeq() != 3 || events[4].GetSeq() != 5 {
+		t.Fatalf("mirror must hold seqs 1..5 after the backfill, got %d events", len(events))
+	}
+	appendTicks(t, log, 6, 6)
+	if last, _ := store.LastSeq(ctx); last != 6 {
+		t.Errorf("mirror last seq = %d; want 6 once in step again", last)
+	}
+}
+
+func TestMirroredLog_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	appendTicks(t, store, 1, 5) // History from before the file WAL
+
+	dir := t.TempDir()
+	w, _ := OpenFileWAL(dir, FileWALOptions{})
+	log, err := NewMirroredLog(ctx, w, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last, _ := w.LastSeq(ctx); last != 5 {
+		t.Fatalf("primary must adopt the mirror's history, last seq = %d", last)
+	}
+	appendTicks(t, log, 6, 6)
+	if last, _ := store.LastSeq(ctx); last != 6 {
+		t.Errorf("mirror last seq = %d, want 6", last)
+	}
+
+	// Crash between the two appends: the mirror catches up on the next start
+	appendTicks(t, w, 7, 8)
+	w.Close()
+	w, _ = OpenFileWAL(dir, FileWALOptions{})
+	defer w.Close()
+	if _, err := NewMirroredLog(ctx, w, store); err != nil {
+		t.Fatal(err)
+	}
+	if last, _ := store.LastSeq(ctx); last != 8 {
+		t.Errorf("mirror must catch up, last seq = %d", last)
+	}
+}
+
+func TestFileWAL_MixedCodecs(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	w, err := OpenFileWAL(dir, FileWALOptions{Codec: event.CodecJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendTicks(t, w, 1, 3)
+	w.Close()
+
+	// Switching to the binary codec keeps the JSON records readable
+	w, err = OpenFileWAL(dir, FileWALOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	appendTicks(t, w, 4, 6)
+
+	events, err := w.ReadFrom(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 6 {
+		t.Fatalf("expected 6 events, got %d", len(events))
+	}
+	for i, ev := range events {
+		if m, ok := ev.(*event.MarketUpdateEvent); !ok || *m != *tickAt(uint64(i + 1)) {
+			t.Errorf("event %d: got %+v", i+1, ev)
+		}
+	}
+	if _, err := OpenFileWAL(t.TempDir(), FileWALOptions{Codec: "xml"}); err == nil {
+		t.Error("an unknown codec must be refused")
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"time"
+)
+
+// Outage is a stretch of the WAL the engine was not running for: a silence
+// between two heartbeats longer than their interval allows, or a restart.
+type Outage struct {
+	FromSeq uint64          `json:"from_seq"` // Last heartbeat before the outage
+	ToSeq   uint64          `json:"to_seq"`   // First heartbeat after it
+	From    quant.TimeStamp `json:"from"`
+	To      quant.TimeStamp `json:"to"`
+	Restart bool            `json:"restart"` // The beat count reset: the engine restarted
+}
+
+// Duration returns how long the outage lasted, at heartbeat resolution.
+func (o Outage) Duration() time.Duration {
+	return time.Duration(o.To-o.From) * time.Microsecond
+}
+
+// Outages scans the heartbeats in the event table, oldest first. Two
+// consecutive heartbeats further apart than their interval plus slack, or
+// whose beat count went back (a restart), bound an outage. Quiet markets
+// leave no outage: heartbeats keep coming without market data.
+func (s *EventStore) Outages(ctx context.Context, slack time.Duration) ([]Outage, error) {
+	events, err := s.loadEvents(ctx, "SELECT id, type, ts, payload FROM events WHERE type = ? ORDER BY id ASC", event.EvHeartbeat)
+	if err != nil {
+		return nil, err
+	}
+
+	var outages []Outage
+	var prev *event.HeartbeatEvent
+	for _, ev := range events {
+		hb, ok := ev.(*event.HeartbeatEvent)
+		if !ok {
+			continue
+		}
+		if prev != nil {
+			limit := time.Duration(prev.IntervalMs)*time.Millisecond + slack
+			gap := time.Duration(hb.Ts-prev.Ts) * time.Microsecond
+			if restart := hb.Beat <= prev.Beat; restart || gap > limit {
+				outages = append(outages, Outage{FromSeq: prev.Seq, ToSeq: hb.Seq, From: prev.Ts, To: hb.Ts, Restart: restart})
+			}
+		}
+		prev = hb
+	}
+	return outages, nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"testing"
+	"time"
+)
+
+func TestEventStore_Outages(t *testing.T) {
+	store, err := NewEventStore(t.TempDir() + "/hb.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	const minute = int64(time.Minute / time.Microsecond)
+	seq := uint64(0)
+	save := func(ev event.Event) {
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	beat := func(minutes, beat int64) {
+		seq++
+		save(&event.HeartbeatEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: quant.TimeStamp(minutes * minute)},
+			Source: "HEARTBEAT", IntervalMs: time.Minute.Milliseconds(), Beat: uint64(beat)})
+	}
+	beat(0, 1)
+	beat(1, 2)
+	seq++ // Market data between heartbeats is not an outage
+	save(&event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: quant.TimeStamp(90 * time.Second / time.Microsecond)}, Symbol: "BTC"})
+	beat(2, 3)
+	beat(10, 4) // Stalled for 8 minutes
+	beat(11, 1) // Restarted within a minute
+
+	outages, err := store.Outages(ctx, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 2 {
+		t.Fatalf("outages = %+v; want 2", outages)
+	}
+	if o := outages[0]; o.FromSeq != 4 || o.ToSeq != 5 || o.Restart || o.Duration() != 8*time.Minute {
+		t.Errorf("stall = %+v (%v)", o, o.Duration())
+	}
+	if o := outages[1]; o.FromSeq != 5 || o.ToSeq != 6 || !o.Restart {
+		t.Errorf("restart = %+v", o)
+	}
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"fmt"
+	"sort"
+)
+
+// JournalTrade is one order update from the WAL with the user's notes on that order.
+type JournalTrade struct {
+	Seq         uint64              `json:"seq"`
+	Ts          quant.TimeStamp     `json:"ts"`
+	OrderID     string              `json:"order_id"`
+	Status      string              `json:"status"`
+	PriceMicros quant.PriceMicros   `json:"price"`
+	QtySats     quant.QtySats       `json:"qty"`
+	Notes       []domain.Annotation `json:"notes,omitempty"`
+}
+
+// JournalDay groups a session day's trades with the notes on the day itself and
+// the day's last reading of each recorded sentiment index.
+type JournalDay struct {
+	Day       string              `json:"day"`
+	Notes     []domain.Annotation `json:"notes,omitempty"`
+	Sentiment []domain.Sentiment  `json:"sentiment,omitempty"`
+	Trades    []JournalTrade      `json:"trades,omitempty"`
+}
+
+// BuildJournal assembles the journal for session days fromDay..toDay (inclusive,
+// YYYY-MM-DD). Days appear if they have trades or day notes.
+func (s *EventStore) BuildJournal(ctx context.Context, session domain.Session, fromDay, toDay string) ([]JournalDay, error) {
+	from, err := session.ParseDay(fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from day: %w", err)
+	}
+	last, err := session.ParseDay(toDay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to day: %w", err)
+	}
+	to := session.NextBoundary(last)
+	if to <= from {
+		return nil, fmt.Errorf("empty range %s..%s", fromDay, toDay)
+	}
+
+	notes, err := s.ListAnnotations(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+	tradeNotes := make(map[string][]domain.Annotation)
+	days := make(map[string]*JournalDay)
+	day := func(key string) *JournalDay {
+		d := days[key]
+		if d == nil {
+			d = &JournalDay{Day: key}
+			days[key] = d
+		}
+		return d
+	}
+	for _, a := range notes {
+		switch a.Kind {
+		case domain.AnnotationTrade:
+			tradeNotes[a.Target] = append(tradeNotes[a.Target], a)
+		case domain.AnnotationDay:
+			if a.Target >= fromDay && a.Target <= toDay {
+				day(a.Target).Notes = append(day(a.Target).Notes, a)
+			}
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, payload FROM events WHERE type = ? AND ts >= ? AND ts < ? ORDER BY id ASC",
+		event.EvOrderUpdate, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order updates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		decoded, err := decodeEvent(event.EvOrderUpdate, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %d: %w", id, err)
+		}
+		ev := decoded.(*event.OrderUpdateEvent)
+		d := day(session.DayKey(ev.Ts))
+		d.Trades = append(d.Trades, JournalTrade{
+			Seq:         id,
+			Ts:          ev.Ts,
+			OrderID:     ev.OrderID,
+			Status:      ev.Status,
+			PriceMicros: ev.PriceMicros,
+			QtySats:     ev.AccumulatedQtySats,
+			Notes:       tradeNotes[ev.OrderID],
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	if err := s.attachSentiment(ctx, session, from, to, days); err != nil {
+		return nil, err
+	}
+
+	out := make([]JournalDay, 0, len(days))
+	for _, d := range days {
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Day < out[j].Day })
+	return out, nil
+}
+
+// attachSentiment adds the last sentiment reading per index to each journal day
+// in [from, to). Readings on days without trades or notes are not reported.
+func (s *EventStore) attachSentiment(ctx context.Context, session domain.Session, from, to quant.TimeStamp, days map[string]*JournalDay) error {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, payload FROM events WHERE type = ? AND ts >= ? AND ts < ? ORDER BY id ASC",
+		event.EvSentiment, from, to,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query sentiment: %w", err)
+	}
+	defer rows.Close()
+
+	last := make(map[string]map[string]domain.Sentiment) // day -> index -> reading
+	for rows.Next() {
+		var id uint64
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		decoded, err := decodeEvent(event.EvSentiment, payload)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal event %d: %w", id, err)
+		}
+		ev := decoded.(*event.SentimentEvent)
+		key := session.DayKey(ev.Ts)
+		if days[key] == nil {
+			continue
+		}
+		if last[key] == nil {
+			last[key] = make(map[string]domain.Sentiment)
+		}
+		if cur, ok := last[key][ev.Index]; !ok || ev.Ts >= cur.Ts {
+			last[key][ev.Index] = ev.Sentiment()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	for key, byIndex := range last {
+		d := days[key]
+		for _, m := range byIndex {
+			d.Sentiment = append(d.Sentiment, m)
+		}
+		sort.Slice(d.Sentiment, func(i, j int) bool { return d.Sentiment[i].Index < d.Sentiment[j].Index })
+	}
+	return nil
+}
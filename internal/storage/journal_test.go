@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+)
+
+func TestJournal_IncludesAnnotations(t *testing.T) {
+	store, err := NewEventStore(t.TempDir() + "/journal.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	ts := func(d time.Time) quant.TimeStamp { return quant.TimeStamp(d.UnixMicro()) }
+	fills := []*event.OrderUpdateEvent{
+		{BaseEvent: event.BaseEvent{Seq: 1, Ts: ts(time.Date(2026, 3, 9, 23, 0, 0, 0, time.UTC))}, OrderID: "o1", Status: "FILLED"},
+		{BaseEvent: event.BaseEvent{Seq: 2, Ts: ts(time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC))}, OrderID: "o2", Status: "FILLED"},
+		{BaseEvent: event.BaseEvent{Seq: 3, Ts: ts(time.Date(2026, 3, 12, 1, 0, 0, 0, time.UTC))}, OrderID: "o3", Status: "FILLED"},
+	}
+	for _, f := range fills {
+		if err := store.SaveEvent(ctx, f); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	note, err := store.SaveAnnotation(ctx, domain.Annotation{Kind: "trade", Target: "o2", Note: "news-driven spike", Tags: []string{"News"}})
+	if err != nil || note.ID == 0 {
+		t.Fatalf("SaveAnnotation failed: %+v (%v)", note, err)
+	}
+	if _, err := store.SaveAnnotation(ctx, domain.Annotation{Kind: "day", Target: "2026-03-10", Note: "CPI day"}); err != nil {
+		t.Fatalf("SaveAnnotation failed: %v", err)
+	}
+	if _, err := store.SaveAnnotation(ctx, domain.Annotation{Kind: "day", Target: "bad"}); err == nil {
+		t.Error("expected validation error")
+	}
+
+	// Upbit session: o1 (08:00 KST) belongs to 03-09, o2 to 03-10, o3 is out of range
+	journal, err := store.BuildJournal(ctx, domain.SessionUpbit, "2026-03-09", "2026-03-10")
+	if err != nil {
+		t.Fatalf("BuildJournal failed: %v", err)
+	}
+	if len(journal) != 2 || journal[0].Day != "2026-03-09" || journal[1].Day != "2026-03-10" {
+		t.Fatalf("unexpected days: %+v", journal)
+	}
+	if len(journal[0].Trades) != 1 || journal[0].Trades[0].OrderID != "o1" {
+		t.Errorf("unexpected 03-09 trades: %+v", journal[0].Trades)
+	}
+	day := journal[1]
+	if len(day.Notes) != 1 || day.Notes[0].Note != "CPI day" {
+		t.Errorf("missing day note: %+v", day.Notes)
+	}
+	if len(day.Trades) != 1 || len(day.Trades[0].Notes) != 1 || day.Trades[0].Notes[0].Tags[0] != "news" {
+		t.Errorf("missing trade note: %+v", day.Trades)
+	}
+
+	if err := store.DeleteAnnotation(ctx, note.ID); err != nil {
+		t.Fatalf("DeleteAnnotation failed: %v", err)
+	}
+	if err := store.DeleteAnnotation(ctx, note.ID); !errors.Is(err, ErrAnnotationNotFound) {
+		t.Errorf("expected ErrAnnotationNotFound, got %v", err)
+	}
+	if notes, _ := store.ListAnnotations(ctx, domain.AnnotationTrade, ""); len(notes) != 0 {
+		t.Errorf("expected no trade notes, got %+v", notes)
+	}
+}
+
+func TestJournal_Sentiment(t *testing.T) {
+	store, err := NewEventStore(t.TempDir() + "/journal.db")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	ts := func(d time.Time) quant.TimeStamp { return quant.TimeStamp(d.UnixMicro()) }
+	events := []event.Event{
+		&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 1, Ts: ts(time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC))}, OrderID: "o1", Status: "FILLED"},
+		&event.SentimentEvent{BaseEvent: event.BaseEvent{Seq: 2, Ts: ts(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))}, Index: domain.SentimentFearGreed, Value: 20, Label: "Extreme Fear"},
+		&event.SentimentEvent{BaseEvent: event.BaseEvent{Seq: 3, Ts: ts(time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC))}, Index: domain.SentimentFunding, Value: 80},
+		&event.SentimentEvent{BaseEvent: event.BaseEvent{Seq: 4, Ts: ts(time.Date(2026, 3, 10, 5, 0, 0, 0, time.UTC))}, Index: domain.SentimentFunding, Value: 120},
+		&event.SentimentEvent{BaseEvent: event.BaseEvent{Seq: 5, Ts: ts(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC))}, Index: domain.SentimentFearGreed, Value: 30}, // Day without trades
+	}
+	for _, ev := range events {
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatalf("SaveEvent failed: %v", err)
+		}
+	}
+
+	journal, err := store.BuildJournal(ctx, domain.SessionBitget, "2026-03-10", "2026-03-11")
+	if err != nil {
+		t.Fatalf("BuildJournal failed: %v", err)
+	}
+	if len(journal) != 1 {
+		t.Fatalf("sentiment alone must not add a day: %+v", journal)
+	}
+	got := journal[0].Sentiment
+	if len(got) != 2 || got[0].Index != domain.SentimentFearGreed || got[0].Value != 20 || got[0].Label != "Extreme Fear" ||
+		got[1].Index != domain.SentimentFunding || got[1].Value != 120 {
+		t.Errorf("unexpected sentiment: %+v", got)
+	}
+}
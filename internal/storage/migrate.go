@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema changes are numbered SQL files: migrations/NNNN_name.up.sql plus a
+// matching .down.sql. Never edit a released migration; add the next number.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one versioned schema step.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrations returns the embedded migrations in version order.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), ".")
+		numStr, name, ok2 := strings.Cut(base, "_")
+		version, err := strconv.Atoi(numStr)
+		if !ok || !ok2 || err != nil || version <= 0 {
+			return nil, fmt.Errorf("bad migration file name %q (want NNNN_name.up.sql)", file)
+		}
+
+		body, err := migrationFiles.ReadFile("migrations/" + file)
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		switch direction {
+		case "up":
+			m.Up = string(body)
+		case "down":
+			m.Down = string(body)
+		default:
+			return nil, fmt.Errorf("bad migration direction in %q", file)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both up and down files", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	for i, m := range out {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration versions must be contiguous from 1, found %04d at position %d", m.Version, i+1)
+		}
+	}
+	return out, nil
+}
+
+// LatestSchemaVersion is the version this build migrates to.
+func LatestSchemaVersion() int {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0
+	}
+	return len(migrations)
+}
+
+func (s *EventStore) ensureVersionTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at INTEGER NOT NULL
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion returns the highest applied migration (0 = none).
+func (s *EventStore) SchemaVersion(ctx context.Context) (int, error) {
+	if err := s.ensureVersionTable(ctx); err != nil {
+		return 0, err
+	}
+	var version int
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// MigrateUp applies pending migrations up to target (0 = latest), each in its
+// own transaction. A database newer than this build is refused rather than
+// used with a schema the code does not know.
+func (s *EventStore) MigrateUp(ctx context.Context, target int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current > len(migrations) {
+		return nil, fmt.Errorf("database schema v%d is newer than this build (v%d)", current, len(migrations))
+	}
+	if target == 0 {
+		target = len(migrations)
+	}
+	if target > len(migrations) {
+		return nil, fmt.Errorf("unknown schema version %d (latest v%d)", target, len(migrations))
+	}
+
+	var applied []Migration
+	for _, m := range migrations[current:target] {
+		if err := s.applyMigration(ctx, m, m.Up, true); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m)
+		slog.Info("Schema migrated", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+	return applied, nil
+}
+
+// MigrateDown reverts migrations above target (newest first).
+func (s *EventStore) MigrateDown(ctx context.Context, target int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current > len(migrations) {
+		return nil, fmt.Errorf("database schema v%d is newer than this build (v%d)", current, len(migrations))
+	}
+	if target < 0 || target > current {
+		return nil, fmt.Errorf("cannot migrate down from v%d to v%d", current, target)
+	}
+
+	var reverted []Migration
+	for i := current - 1; i >= target; i-- {
+		m := migrations[i]
+		if err := s.applyMigration(ctx, m, m.Down, false); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, m)
+		slog.Info("Schema reverted", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+	return reverted, nil
+}
+
+func (s *EventStore) applyMigration(ctx context.Context, m Migration, script string, up bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %04d: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)",
+			m.Version, m.Name, time.Now().UnixMicro())
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM schema_version WHERE version = ?", m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %04d: %w", m.Version, err)
+	}
+	return tx.Commit()
+}
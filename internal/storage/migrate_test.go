@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n); err != nil {
+		t.Fatalf("failed to query sqlite_master: %v", err)
+	}
+	return n == 1
+}
+
+func TestMigrations_Embedded(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations failed: %v", err)
+	}
+	if len(migrations) == 0 || LatestSchemaVersion() != len(migrations) {
+		t.Fatalf("unexpected migrations: %d, latest %d", len(migrations), LatestSchemaVersion())
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 || m.Up == "" || m.Down == "" {
+			t.Errorf("migration %d incomplete: %+v", i, m)
+		}
+	}
+}
+
+func TestMigrate_UpDown(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewEventStore(t.TempDir() + "/m.db")
+	if err != nil {
+		t.Fatalf("NewEventStore failed: %v", err)
+	}
+	defer store.Close()
+
+	if v, _ := store.SchemaVersion(ctx); v != LatestSchemaVersion() {
+		t.Fatalf("new store at v%d, want v%d", v, LatestSchemaVersion())
+	}
+	if !tableExists(t, store.DB(), "dead_letters") {
+		t.Fatal("dead_letters missing after migrate up")
+	}
+
+	reverted, err := store.MigrateDown(ctx, 1)
+	if err != nil || len(reverted) != LatestSchemaVersion()-1 {
+		t.Fatalf("MigrateDown: %v (%d reverted)", err, len(reverted))
+	}
+	if tableExists(t, store.DB(), "dead_letters") || !tableExists(t, store.DB(), "events") {
+		t.Error("down to v1 should drop dead_letters and keep events")
+	}
+
+	applied, err := store.MigrateUp(ctx, 0)
+	if err != nil || len(applied) != LatestSchemaVersion()-1 {
+		t.Fatalf("MigrateUp: %v (%d applied)", err, len(applied))
+	}
+	if _, err := store.MigrateDown(ctx, LatestSchemaVersion()+1); err == nil {
+		t.Error("expected error migrating down to a higher version")
+	}
+}
+
+func TestMigrate_AdoptsLegacyDatabase(t *testing.T) {
+	path := t.TempDir() + "/legacy.db"
+	legacy, err := OpenEventStore(path)
+	if err != nil {
+		t.Fatalf("OpenEventStore failed: %v", err)
+	}
+	// Schema as created before versioned migrations, with data
+	if _, err := legacy.DB().Exec(`
+		CREATE TABLE metadata (key TEXT PRIMARY KEY, value TEXT NOT NULL, updated_at INTEGER NOT NULL);
+		CREATE TABLE events (id INTEGER PRIMARY KEY, type INTEGER NOT NULL, ts INTEGER NOT NULL, payload BLOB NOT NULL, version INTEGER NOT NULL DEFAULT 1);
+		INSERT INTO events (id, type, ts, payload) VALUES (1, 1, 0, '{}');
+	`); err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+	legacy.Close()
+
+	store, err := NewEventStore(path)
+	if err != nil {
+		t.Fatalf("NewEventStore on legacy db failed: %v", err)
+	}
+	defer store.Close()
+	if last, _ := store.GetLastSeq(context.Background()); last != 1 {
+		t.Errorf("legacy events lost, last seq %d", last)
+	}
+}
+
+func TestMigrate_RefusesNewerSchema(t *testing.T) {
+	path := t.TempDir() + "/newer.db"
+	store, err := NewEventStore(path)
+	if err != nil {
+		t.Fatalf("NewEventStore failed: %v", err)
+	}
+	if _, err := store.DB().Exec("INSERT INTO schema_version (version, name, applied_at) VALUES (?, 'future', 0)", LatestSchemaVersion()+1); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	store.Close()
+
+	if _, err := NewEventStore(path); err == nil || !strings.Contains(err.Error(), "newer than this build") {
+		t.Errorf("expected newer-schema error, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+-- Baseline schema. IF NOT EXISTS keeps it idempotent for databases created
+-- before versioned migrations existed.
+CREATE TABLE IF NOT EXISTS metadata (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS events (
+	id INTEGER PRIMARY KEY,
+	type INTEGER NOT NULL,
+	ts INTEGER NOT NULL,
+	payload BLOB NOT NULL,
+	version INTEGER NOT NULL DEFAULT 1
+);
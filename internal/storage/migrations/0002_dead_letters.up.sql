@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	seq INTEGER NOT NULL DEFAULT 0,
+	stage TEXT NOT NULL,
+	type INTEGER NOT NULL,
+	payload BLOB NOT NULL,
+	error TEXT NOT NULL,
+	stack TEXT NOT NULL DEFAULT '',
+	attempts INTEGER NOT NULL,
+	created_at INTEGER NOT NULL,
+	requeued INTEGER NOT NULL DEFAULT 0
+);
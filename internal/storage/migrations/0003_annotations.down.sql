@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_events_type_ts;
+DROP TABLE IF EXISTS annotations;
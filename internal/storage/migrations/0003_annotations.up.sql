@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS annotations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	kind TEXT NOT NULL,
+	target TEXT NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	tags TEXT NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_annotations_target ON annotations (kind, target);
+
+CREATE INDEX IF NOT EXISTS idx_events_type_ts ON events (type, ts);
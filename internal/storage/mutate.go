@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// User mutations (settings, favorites, annotations, dead letter actions) run
+// one at a time through a small queue, each in its own transaction, so UI
+// requests and background sync never interleave a read-modify-write. A
+// transaction that still finds the database busy (the driver waits for locks,
+// but a read snapshot made stale by another writer fails at once) is retried
+// from the start with backoff.
+const (
+	userWriteQueueSize = 32
+	userWriteAttempts  = 5
+	userWriteBackoff   = 20 * time.Millisecond
+)
+
+// SQLite primary result codes of a database or table held by another writer.
+const (
+	sqliteBusy   = 5
+	sqliteLocked = 6
+)
+
+type userWrite struct {
+	ctx  context.Context
+	fn   func(ctx context.Context, tx *sql.Tx) error
+	done chan error
+}
+
+// startUserWrites starts the queue writer (stopped by Close).
+func (s *EventStore) startUserWrites() {
+	s.userWrites = make(chan userWrite, userWriteQueueSize)
+	s.writerDone = make(chan struct{})
+	go func() {
+		defer close(s.writerDone)
+		for w := range s.userWrites {
+			w.done <- s.runTx(w.ctx, w.fn)
+		}
+	}()
+}
+
+// stopUserWrites lets queued mutations finish and stops the writer.
+func (s *EventStore) stopUserWrites() {
+	if s.userWrites == nil {
+		return
+	}
+	s.writeMu.Lock()
+	if !s.writesClosed {
+		s.writesClosed = true
+		close(s.userWrites)
+	}
+	s.writeMu.Unlock()
+	<-s.writerDone
+}
+
+// mutate queues fn and waits for its transaction to commit. It gives up when
+// ctx is done before fn started; once started, the transaction completes.
+func (s *EventStore) mutate(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	if s.userWrites == nil {
+		return errors.New("store is read-only")
+	}
+	w := userWrite{ctx: context.WithoutCancel(ctx), fn: fn, done: make(chan error, 1)}
+	s.writeMu.RLock()
+	if s.writesClosed {
+		s.writeMu.RUnlock()
+		return errors.New("store is closed")
+	}
+	select {
+	case s.userWrites <- w:
+		s.writeMu.RUnlock()
+	case <-ctx.Done():
+		s.writeMu.RUnlock()
+		return ctx.Err()
+	}
+	return <-w.done
+}
+
+// runTx runs fn in a transaction, retrying the whole transaction while the
+// database is busy.
+func (s *EventStore) runTx(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	backoff := userWriteBackoff
+	for attempt := 1; ; attempt++ {
+		err := s.tryTx(ctx, fn)
+		if err == nil || !isBusy(err) || attempt == userWriteAttempts {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (s *EventStore) tryTx(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(ctx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// isBusy reports whether err is SQLite's SQLITE_BUSY or SQLITE_LOCKED.
+func isBusy(err error) bool {
+	var coded interface{ Code() int }
+	if !errors.As(err, &coded) {
+		return false
+	}
+	code := coded.Code() & 0xff // Extended codes carry the primary code in the low byte
+	return code == sqliteBusy || code == sqliteLocked
+}
+
+// UpdateMetadata replaces the value of key with update(current) in one
+// transaction; current is "" if key is not set. Concurrent updates of the same
+// key each see the previous one's result.
+func (s *EventStore) UpdateMetadata(ctx context.Context, key string, ts int64, update func(current string) (string, error)) error {
+	return s.mutate(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var current string
+		err := tx.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key = ?", key).Scan(&current)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to read metadata %s: %w", key, err)
+		}
+		value, err := update(current)
+		if err != nil {
+			return err
+		}
+		return upsertMetadata(ctx, tx, key, value, ts)
+	})
+}
+
+// CoinMetadataKey is the metadata key of a coin's domain.CoinInfo.
+func CoinMetadataKey(symbol string) string {
+	return "coin:" + symbol
+}
+
+// SetFavorite marks symbol as a user favorite (or not), keeping the rest of
+// its CoinInfo. A coin without an entry yet gets a minimal one.
+func (s *EventStore) SetFavorite(ctx context.Context, symbol string, favorite bool) error {
+	now := time.Now().UnixMicro()
+	return s.UpdateMetadata(ctx, CoinMetadataKey(symbol), now, func(current string) (string, error) {
+		coin := domain.CoinInfo{Symbol: symbol, Name: symbol, IsActive: true, CreatedAtUnixM: now}
+		if current != "" {
+			if err := json.Unmarshal([]byte(current), &coin); err != nil {
+				return "", fmt.Errorf("invalid coin info for %s: %w", symbol, err)
+			}
+		}
+		coin.IsFavorite = favorite
+		coin.UpdatedAtUnixM = now
+		data, err := json.Marshal(coin)
+		return string(data), err
+	})
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"crypto_go/internal/domain"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestUpdateMetadata_Serialized(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// Read-modify-writes from many goroutines must not lose updates
+	var wg sync.WaitGroup
+	for range 40 {
+		wg.Go(func() {
+			err := store.UpdateMetadata(ctx, "counter", 0, func(current string) (string, error) {
+				n, _ := strconv.Atoi(current)
+				return strconv.Itoa(n + 1), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	// A favorite toggle racing a sync rewrite of the same coin survives it
+	wg.Go(func() {
+		if err := store.SetFavorite(ctx, "BTC", true); err != nil {
+			t.Error(err)
+		}
+	})
+	wg.Go(func() {
+		store.UpdateMetadata(ctx, CoinMetadataKey("BTC"), 0, func(current string) (string, error) {
+			var coin domain.CoinInfo
+			json.Unmarshal([]byte(current), &coin)
+			coin.Symbol, coin.IconPath = "BTC", "icons/btc.png"
+			data, err := json.Marshal(coin)
+			return string(data), err
+		})
+	})
+	wg.Wait()
+
+	if v, _ := store.GetMetadata(ctx, "counter"); v != "40" {
+		t.Errorf("counter = %q, want 40", v)
+	}
+	raw, _ := store.GetMetadata(ctx, CoinMetadataKey("BTC"))
+	var coin domain.CoinInfo
+	if err := json.Unmarshal([]byte(raw), &coin); err != nil || !coin.IsFavorite || coin.IconPath != "icons/btc.png" {
+		t.Errorf("both writes must be kept: %s", raw)
+	}
+}
+
+func TestMutate_RetriesBusySnapshot(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another connection commits between the transaction's read and its write:
+	// SQLite refuses the upgrade with SQLITE_BUSY right away (no busy wait)
+	calls := 0
+	err = store.UpdateMetadata(ctx, "setting:ui.theme", 0, func(current string) (string, error) {
+		calls++
+		if calls == 1 {
+			if _, err := store.DB().ExecContext(ctx, "INSERT INTO metadata (key, value, updated_at) VALUES ('sync', 'x', 0)"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return "light", nil
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("a busy transaction must be retried once: calls %d, %v", calls, err)
+	}
+	if v, _ := store.GetMetadata(ctx, "setting:ui.theme"); v != "light" {
+		t.Errorf("value = %q, want light", v)
+	}
+
+	store.Close()
+	if err := store.UpsertMetadata(ctx, "k", "v", 0); err == nil {
+		t.Error("a write after Close must fail")
+	}
+}
@@ -16,11 +16,23 @@ type Snapshot struct {
 	Seq     uint64                         `json:"seq"`     // Last processed sequence number
 	TsUnix  int64                          `json:"ts"`      // Snapshot creation timestamp (Unix seconds)
 	Markets map[string]*domain.MarketState `json:"markets"` // Market state at snapshot time
+
+	// StateHash fingerprints the full sequencer state at Seq (empty in older
+	// snapshots). Replay recomputes it at the same seq to prove determinism.
+	StateHash string `json:"state_hash,omitempty"`
+
+	// Balances, Positions and Engine (the rest of the sequencer state, in the
+	// engine's own encoding) make the snapshot restorable, so recovery can
+	// replay only the WAL after Seq. Empty in older snapshots.
+	Balances  map[string]domain.Balance  `json:"balances,omitempty"`
+	Positions map[string]domain.Position `json:"positions,omitempty"`
+	Engine    json.RawMessage            `json:"engine,omitempty"`
 }
 
 // SnapshotManager handles saving and loading snapshots.
 type SnapshotManager struct {
-	dir string
+	dir  string
+	keep int // Snapshots kept after each Save (0 = all)
 }
 
 // NewSnapshotManager creates a new snapshot manager.
@@ -29,6 +41,11 @@ func NewSnapshotManager(dir string) *SnapshotManager {
 	return &SnapshotManager{dir: dir}
 }
 
+// SetRetention makes Save remove all but the newest keep snapshots (0 = keep all).
+func (sm *SnapshotManager) SetRetention(keep int) {
+	sm.keep = keep
+}
+
 // Save writes a snapshot to disk.
 func (sm *SnapshotManager) Save(snap *Snapshot) error {
 	// Ensure directory exists
@@ -53,6 +70,11 @@ func (sm *SnapshotManager) Save(snap *Snapshot) error {
 		slog.Uint64("seq", snap.Seq),
 		slog.String("path", path))
 
+	if sm.keep > 0 {
+		if err := sm.Cleanup(sm.keep); err != nil {
+			slog.Warn("Failed to clean up old snapshots", slog.Any("error", err))
+		}
+	}
 	return nil
 }
 
@@ -109,6 +131,65 @@ func (sm *SnapshotManager) LoadLatest() (*Snapshot, error) {
 	return &snap, nil
 }
 
+// Load loads the snapshot taken at seq.
+func (sm *SnapshotManager) Load(seq uint64) (*Snapshot, error) {
+	matches, err := filepath.Glob(filepath.Join(sm.dir, fmt.Sprintf("snapshot_%d_*.json", seq)))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no snapshot at seq %d in %s", seq, sm.dir)
+	}
+	data, err := os.ReadFile(matches[len(matches)-1]) // Newest if taken twice
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// LoadHashes returns the state hash of every snapshot on disk by seq.
+// Snapshots without one are left out; an empty dir yields an empty map.
+func (sm *SnapshotManager) LoadHashes() (map[uint64]string, error) {
+	hashes := make(map[uint64]string)
+	entries, err := os.ReadDir(sm.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hashes, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		var seq uint64
+		var ts int64
+		if entry.IsDir() {
+			continue
+		}
+		if _, err := fmt.Sscanf(entry.Name(), "snapshot_%d_%d.json", &seq, &ts); err != nil {
+			continue // Not a snapshot file
+		}
+
+		data, err := os.ReadFile(filepath.Join(sm.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		var snap struct {
+			StateHash string `json:"state_hash"`
+		}
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal snapshot %s: %w", entry.Name(), err)
+		}
+		if snap.StateHash != "" {
+			hashes[seq] = snap.StateHash
+		}
+	}
+	return hashes, nil
+}
+
 // CreateSnapshot creates a snapshot from current state.
 func CreateSnapshot(seq uint64, markets map[string]*domain.MarketState) *Snapshot {
 	// Deep copy markets to avoid mutation
@@ -124,3 +124,46 @@ func TestSnapshot_Cleanup(t *testing.T) {
 		t.Errorf("Expected seq 5 to remain, got %d", loaded.Seq)
 	}
 }
+
+func TestSnapshot_Retention(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewSnapshotManager(dir)
+	sm.SetRetention(2)
+
+	for seq := uint64(1); seq <= 4; seq++ {
+		if err := sm.Save(&Snapshot{Seq: seq, TsUnix: int64(seq)}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 snapshots kept, got %d", len(entries))
+	}
+}
+
+func TestSnapshot_LoadHashes(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewSnapshotManager(dir)
+
+	if hashes, err := sm.LoadHashes(); err != nil || len(hashes) != 0 {
+		t.Fatalf("expected no hashes in an empty dir: %v, %v", hashes, err)
+	}
+
+	hashed := CreateSnapshot(7, nil)
+	hashed.StateHash = "abc"
+	if err := sm.Save(hashed); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Save(CreateSnapshot(9, nil)); err != nil { // Older format: no hash
+		t.Fatal(err)
+	}
+
+	hashes, err := sm.LoadHashes()
+	if err != nil {
+		t.Fatalf("LoadHashes failed: %v", err)
+	}
+	if len(hashes) != 1 || hashes[7] != "abc" {
+		t.Errorf("unexpected hashes: %v", hashes)
+	}
+}
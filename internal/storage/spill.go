@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"crypto_go/internal/event"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrSpillFull is returned when pending spilled bytes reached the size limit.
+var ErrSpillFull = errors.New("spill queue full")
+
+// spillHeaderSize = 4-byte payload length + 2-byte event type.
+const spillHeaderSize = 6
+
+// spillCompactAfter: once this many consumed bytes precede the live records,
+// the live tail is moved to the file start so a never-empty queue cannot grow forever.
+const spillCompactAfter = 1 << 20
+
+// SpillQueue is an append-only FIFO of events on disk.
+// It absorbs inbox overflow so bursts are delayed instead of dropped.
+//
+// Record layout: [len uint32][type uint16][binary payload].
+// The file is truncated whenever the queue drains, and compacted once the consumed
+// prefix grows large, so its size tracks the pending backlog rather than total traffic.
+// Contents are NOT durable across restarts: spilled events were never sequenced
+// (not yet in the WAL), so stale ticks from a previous run are discarded on open.
+//
+// Not goroutine-safe; owned by a single pump goroutine.
+type SpillQueue struct {
+	f        *os.File
+	path     string
+	readOff  int64
+	writeOff int64
+	count    int
+	maxBytes int64 // Limit on pending bytes; 0 = unlimited
+
+	compactAfter int64
+}
+
+// NewSpillQueue opens (and resets) the spill file at path.
+func NewSpillQueue(path string, maxBytes int64) (*SpillQueue, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spill file: %w", err)
+	}
+	return &SpillQueue{f: f, path: path, maxBytes: maxBytes, compactAfter: spillCompactAfter}, nil
+}
+
+// Push appends ev to the tail. The caller still owns ev (and should release it).
+func (q *SpillQueue) Push(ev event.Event) error {
+	payload, err := event.Encode(event.CodecBinary, ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	size := int64(spillHeaderSize + len(payload))
+	if q.maxBytes > 0 && q.Bytes()+size > q.maxBytes {
+		return ErrSpillFull
+	}
+
+	rec := make([]byte, size)
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint16(rec[4:6], uint16(ev.GetType()))
+	copy(rec[spillHeaderSize:], payload)
+
+	if _, err := q.f.WriteAt(rec, q.writeOff); err != nil {
+		return fmt.Errorf("failed to write spill record: %w", err)
+	}
+	q.writeOff += size
+	q.count++
+	return nil
+}
+
+// Peek decodes the head event without removing it. Returns nil when empty.
+// Each call returns a fresh event; the caller takes ownership.
+func (q *SpillQueue) Peek() (event.Event, error) {
+	if q.count == 0 {
+		return nil, nil
+	}
+
+	var hdr [spillHeaderSize]byte
+	if _, err := q.f.ReadAt(hdr[:], q.readOff); err != nil {
+		return nil, fmt.Errorf("failed to read spill header: %w", err)
+	}
+	n := binary.LittleEndian.Uint32(hdr[0:4])
+	evType := event.Type(binary.LittleEndian.Uint16(hdr[4:6]))
+
+	payload := make([]byte, n)
+	if _, err := q.f.ReadAt(payload, q.readOff+spillHeaderSize); err != nil {
+		return nil, fmt.Errorf("failed to read spill payload: %w", err)
+	}
+
+	ev, err := decodeEvent(evType, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode spilled event: %w", err)
+	}
+	if ev == nil {
+		return nil, fmt.Errorf("unknown spilled event type %d", evType)
+	}
+	return ev, nil
+}
+
+// Pop removes the head record (after a successful Peek + hand-off).
+func (q *SpillQueue) Pop() error {
+	if q.count == 0 {
+		return nil
+	}
+
+	var hdr [4]byte
+	if _, err := q.f.ReadAt(hdr[:], q.readOff); err != nil {
+		return fmt.Errorf("failed to read spill header: %w", err)
+	}
+	q.readOff += spillHeaderSize + int64(binary.LittleEndian.Uint32(hdr[:]))
+	q.count--
+
+	// Drained: reclaim disk space
+	if q.count == 0 {
+		q.readOff, q.writeOff = 0, 0
+		return q.f.Truncate(0)
+	}
+	if q.readOff >= q.compactAfter && q.readOff >= q.Bytes() {
+		return q.compact()
+	}
+	return nil
+}
+
+// compact moves the pending records to the start of the file and truncates the rest.
+// Only runs when the consumed prefix is at least as large as the live tail,
+// so the copy never overlaps its source and costs O(pending).
+func (q *SpillQueue) compact() error {
+	pending := q.Bytes()
+	buf := make([]byte, pending)
+	if _, err := q.f.ReadAt(buf, q.readOff); err != nil {
+		return fmt.Errorf("failed to read spill tail: %w", err)
+	}
+	if _, err := q.f.WriteAt(buf, 0); err != nil {
+		return fmt.Errorf("failed to compact spill file: %w", err)
+	}
+	q.readOff, q.writeOff = 0, pending
+	return q.f.Truncate(pending)
+}
+
+// Len returns the number of queued events.
+func (q *SpillQueue) Len() int {
+	return q.count
+}
+
+// Bytes returns the current on-disk size of pending records.
+func (q *SpillQueue) Bytes() int64 {
+	return q.writeOff - q.readOff
+}
+
+// Close closes and removes the spill file.
+func (q *SpillQueue) Close() error {
+	err := q.f.Close()
+	os.Remove(q.path)
+	return err
+}
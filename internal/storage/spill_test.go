@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpillQueue_FIFO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inbox.spill")
+	q, err := NewSpillQueue(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to open spill queue: %v", err)
+	}
+	defer q.Close()
+
+	for i := 1; i <= 3; i++ {
+		ev := &event.MarketUpdateEvent{
+			BaseEvent:   event.BaseEvent{Seq: uint64(i), Ts: quant.TimeStamp(i * 1000)},
+			Symbol:      "BTC",
+			PriceMicros: quant.PriceMicros(i * 1_000_000),
+			Exchange:    "UPBIT",
+		}
+		if err := q.Push(ev); err != nil {
+			t.Fatalf("Push %d failed: %v", i, err)
+		}
+	}
+	q.Push(&event.OrderUpdateEvent{BaseEvent: event.BaseEvent{Seq: 4}, OrderID: "o-1", Status: "FILLED"})
+
+	if q.Len() != 4 {
+		t.Fatalf("Expected 4 queued events, got %d", q.Len())
+	}
+
+	for i := 1; i <= 3; i++ {
+		ev, err := q.Peek()
+		if err != nil {
+			t.Fatalf("Peek failed: %v", err)
+		}
+		mu, ok := ev.(*event.MarketUpdateEvent)
+		if !ok || mu.Seq != uint64(i) || mu.PriceMicros != quant.PriceMicros(i*1_000_000) {
+			t.Fatalf("Out of order head at %d: %+v", i, ev)
+		}
+		if err := q.Pop(); err != nil {
+			t.Fatalf("Pop failed: %v", err)
+		}
+	}
+
+	ev, _ := q.Peek()
+	if ou, ok := ev.(*event.OrderUpdateEvent); !ok || ou.OrderID != "o-1" {
+		t.Fatalf("Expected order update at tail, got %+v", ev)
+	}
+	q.Pop()
+
+	// Drained queue must reclaim disk space
+	info, _ := os.Stat(path)
+	if q.Len() != 0 || info.Size() != 0 {
+		t.Errorf("Expected empty truncated file, len=%d size=%d", q.Len(), info.Size())
+	}
+}
+
+func TestSpillQueue_MaxBytes(t *testing.T) {
+	q, err := NewSpillQueue(filepath.Join(t.TempDir(), "inbox.spill"), 64)
+	if err != nil {
+		t.Fatalf("Failed to open spill queue: %v", err)
+	}
+	defer q.Close()
+
+	ev := &event.MarketUpdateEvent{Symbol: "BTC", Exchange: "UPBIT"}
+	var full bool
+	for i := 0; i < 10; i++ {
+		if err := q.Push(ev); errors.Is(err, ErrSpillFull) {
+			full = true
+			break
+		}
+	}
+	if !full {
+		t.Error("Expected ErrSpillFull once the byte budget is exceeded")
+	}
+}
+
+func TestSpillQueue_InterleavedNeverDrains(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inbox.spill")
+	q, err := NewSpillQueue(path, 4096)
+	if err != nil {
+		t.Fatalf("Failed to open spill queue: %v", err)
+	}
+	defer q.Close()
+	q.compactAfter = 512
+
+	push := func(seq uint64) {
+		t.Helper()
+		ev := &event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: seq}, Symbol: "BTC", Exchange: "UPBIT"}
+		if err := q.Push(ev); err != nil {
+			t.Fatalf("Push %d failed with %d pending bytes: %v", seq, q.Bytes(), err)
+		}
+	}
+
+	// Keep ~5 events pending while pushing far more than maxBytes in total
+	next, expect := uint64(1), uint64(1)
+	for ; next <= 5; next++ {
+		push(next)
+	}
+	for i := 0; i < 2000; i++ {
+		push(next)
+		next++
+
+		ev, err := q.Peek()
+		if err != nil {
+			t.Fatalf("Peek failed: %v", err)
+		}
+		if ev.GetSeq() != expect {
+			t.Fatalf("Out of order after compaction: expected %d, got %d", expect, ev.GetSeq())
+		}
+		expect++
+		if err := q.Pop(); err != nil {
+			t.Fatalf("Pop failed: %v", err)
+		}
+	}
+
+	if q.Len() != 5 {
+		t.Errorf("Expected 5 pending events, got %d", q.Len())
+	}
+	info, _ := os.Stat(path)
+	if info.Size() > 2*q.compactAfter {
+		t.Errorf("Spill file grew to %d bytes with only %d pending", info.Size(), q.Bytes())
+	}
+}
@@ -3,9 +3,11 @@ package storage
 import (
 	"context"
 	"crypto_go/internal/event"
+	"crypto_go/pkg/quant"
 	"database/sql"
-	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"sync"
 
 	_ "github.com/glebarez/go-sqlite"
 )
@@ -13,10 +15,32 @@ import (
 // EventStore handles persistent storage of events in SQLite.
 type EventStore struct {
 	db *sql.DB
+
+	userWrites   chan userWrite // Serialized user mutations (see mutate); nil when read-only
+	writerDone   chan struct{}
+	writeMu      sync.RWMutex // Guards sends on userWrites against Close
+	writesClosed bool
+
+	codec string // Payload codec of new events (event.Codec*; "" = binary)
 }
 
-// NewEventStore creates a new SQLite event store with WAL mode enabled.
+// NewEventStore creates a new SQLite event store with WAL mode enabled and
+// migrates its schema to the latest version.
 func NewEventStore(dbPath string) (*EventStore, error) {
+	s, err := OpenEventStore(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.MigrateUp(context.Background(), 0); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenEventStore opens the database without touching its schema.
+// Used by the migrate command; everything else should use NewEventStore.
+func OpenEventStore(dbPath string) (*EventStore, error) {
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite: %w", err)
@@ -32,42 +56,44 @@ func NewEventStore(dbPath string) (*EventStore, error) {
 
 	for _, pragma := range pragmas {
 		if _, err := db.Exec(pragma); err != nil {
+			db.Close()
 			return nil, fmt.Errorf("failed to set pragma %s: %w", pragma, err)
 		}
 	}
 
-	// Create metadata table for KV storage
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS metadata (
-			key TEXT PRIMARY KEY,
-			value TEXT NOT NULL,
-			updated_at INTEGER NOT NULL
-		);
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create metadata table: %w", err)
-	}
-
-	// Create events table for WAL-first event logging
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS events (
-			id INTEGER PRIMARY KEY,
-			type INTEGER NOT NULL,
-			ts INTEGER NOT NULL,
-			payload BLOB NOT NULL,
-			version INTEGER NOT NULL DEFAULT 1
-		);
-	`)
+	s := &EventStore{db: db}
+	s.startUserWrites()
+	return s, nil
+}
+
+// OpenEventStoreReadOnly opens another instance's database for reading only
+// (follower mode). Writes fail; the schema is neither checked nor migrated.
+func OpenEventStoreReadOnly(dbPath string) (*EventStore, error) {
+	db, err := sql.Open("sqlite", "file:"+filepath.ToSlash(dbPath)+"?mode=ro")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create events table: %w", err)
+		return nil, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s read-only: %w", dbPath, err)
 	}
-
 	return &EventStore{db: db}, nil
 }
 
+// SetCodec selects the payload codec of events saved from now on
+// (event.CodecBinary or event.CodecJSON). Events of either codec are read
+// back alike, so a store may mix them. Must be called before the first save.
+func (s *EventStore) SetCodec(codec string) error {
+	if !event.ValidCodec(codec) {
+		return fmt.Errorf("unknown event codec %q", codec)
+	}
+	s.codec = codec
+	return nil
+}
+
 // SaveEvent stores an event in the database.
 func (s *EventStore) SaveEvent(ctx context.Context, ev event.Event) error {
-	payload, err := json.Marshal(ev)
+	payload, err := event.Encode(s.codec, ev)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
@@ -85,11 +111,20 @@ func (s *EventStore) SaveEvent(ctx context.Context, ev event.Event) error {
 
 // UpsertMetadata saves a key-value pair to the metadata table.
 func (s *EventStore) UpsertMetadata(ctx context.Context, key, value string, ts int64) error {
-	_, err := s.db.ExecContext(ctx,
+	return s.mutate(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return upsertMetadata(ctx, tx, key, value, ts)
+	})
+}
+
+func upsertMetadata(ctx context.Context, tx *sql.Tx, key, value string, ts int64) error {
+	_, err := tx.ExecContext(ctx,
 		"INSERT INTO metadata (key, value, updated_at) VALUES (?, ?, ?) ON CONFLICT(key) DO UPDATE SET value=excluded.value, updated_at=excluded.updated_at",
 		key, value, ts,
 	)
-	return err
+	if err != nil {
+		return fmt.Errorf("failed to upsert metadata %s: %w", key, err)
+	}
+	return nil
 }
 
 // GetMetadata retrieves a value from the metadata table.
@@ -116,13 +151,45 @@ func (s *EventStore) GetLastSeq(ctx context.Context) (uint64, error) {
 	return uint64(lastSeq.Int64), nil
 }
 
+// SeqAt returns the first seq whose event was stamped at or after ts, or
+// the seq after the last event if none was. Timestamps follow the sequence,
+// so it binary-searches the primary key instead of scanning ts.
+func (s *EventStore) SeqAt(ctx context.Context, ts quant.TimeStamp) (uint64, error) {
+	last, err := s.GetLastSeq(ctx)
+	if err != nil {
+		return 0, err
+	}
+	lo, hi := uint64(1), last+1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		var id, at int64
+		err := s.db.QueryRowContext(ctx, "SELECT id, ts FROM events WHERE id >= ? ORDER BY id ASC LIMIT 1", mid).Scan(&id, &at)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read event ts: %w", err)
+		}
+		if quant.TimeStamp(at) >= ts {
+			hi = mid
+		} else {
+			lo = uint64(id) + 1
+		}
+	}
+	return lo, nil
+}
+
 // LoadEvents loads all events from WAL starting from fromSeq (inclusive).
 // Returns all event types as []event.Event for complete WAL replay.
 func (s *EventStore) LoadEvents(ctx context.Context, fromSeq uint64) ([]event.Event, error) {
-	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, type, ts, payload FROM events WHERE id >= ? ORDER BY id ASC",
-		fromSeq,
-	)
+	return s.loadEvents(ctx, "SELECT id, type, ts, payload FROM events WHERE id >= ? ORDER BY id ASC", fromSeq)
+}
+
+// LoadEventsBatch loads at most limit events starting from fromSeq (inclusive),
+// for tailing a WAL that is still growing.
+func (s *EventStore) LoadEventsBatch(ctx context.Context, fromSeq uint64, limit int) ([]event.Event, error) {
+	return s.loadEvents(ctx, "SELECT id, type, ts, payload FROM events WHERE id >= ? ORDER BY id ASC LIMIT ?", fromSeq, limit)
+}
+
+func (s *EventStore) loadEvents(ctx context.Context, query string, args ...any) ([]event.Event, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events: %w", err)
 	}
@@ -139,23 +206,15 @@ func (s *EventStore) LoadEvents(ctx context.Context, fromSeq uint64) ([]event.Ev
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
 
-		switch event.Type(evType) {
-		case event.EvMarketUpdate:
-			var ev event.MarketUpdateEvent
-			if err := json.Unmarshal(payload, &ev); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal event %d: %w", id, err)
-			}
-			events = append(events, &ev)
-		case event.EvOrderUpdate:
-			var ev event.OrderUpdateEvent
-			if err := json.Unmarshal(payload, &ev); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal event %d: %w", id, err)
-			}
-			events = append(events, &ev)
-		default:
+		ev, err := decodeEvent(event.Type(evType), payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %d: %w", id, err)
+		}
+		if ev == nil {
 			// Skip unknown event types
 			continue
 		}
+		events = append(events, ev)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -165,8 +224,15 @@ func (s *EventStore) LoadEvents(ctx context.Context, fromSeq uint64) ([]event.Ev
 	return events, nil
 }
 
-// Close closes the database connection.
+// decodeEvent restores a typed event from its binary or JSON payload.
+// Returns (nil, nil) for unknown types so callers can skip them.
+func decodeEvent(evType event.Type, payload []byte) (event.Event, error) {
+	return event.Decode(evType, payload)
+}
+
+// Close waits for queued user mutations and closes the database connection.
 func (s *EventStore) Close() error {
+	s.stopUserWrites()
 	return s.db.Close()
 }
 
@@ -5,6 +5,7 @@ import (
 	"crypto_go/internal/event"
 	"crypto_go/pkg/quant"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -124,3 +125,67 @@ func TestEventStore_GetLastSeq(t *testing.T) {
 		t.Errorf("Expected 10, got %d", lastSeq)
 	}
 }
+
+func TestEventStore_SeqAt(t *testing.T) {
+	store, err := NewEventStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	for seq := uint64(1); seq <= 9; seq++ {
+		ev := &event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: seq, Ts: quant.TimeStamp(seq * 100)}, Symbol: "TEST"}
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tc := range []struct {
+		ts   quant.TimeStamp
+		want uint64
+	}{{0, 1}, {100, 1}, {101, 2}, {550, 6}, {900, 9}, {901, 10}} {
+		got, err := store.SeqAt(ctx, tc.ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("SeqAt(%d) = %d; want %d", tc.ts, got, tc.want)
+		}
+	}
+}
+
+func TestEventStore_ReadOnly(t *testing.T) {
+	dbPath := t.TempDir() + "/events.db"
+	store, err := NewEventStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	for i := uint64(1); i <= 5; i++ {
+		ev := &event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: i, Ts: quant.TimeStamp(i)}, Symbol: "BTC", Exchange: "UPBIT"}
+		if err := store.SaveEvent(ctx, ev); err != nil {
+			t.Fatalf("Failed to save: %v", err)
+		}
+	}
+
+	ro, err := OpenEventStoreReadOnly(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open read-only: %v", err)
+	}
+	defer ro.Close()
+
+	batch, err := ro.LoadEventsBatch(ctx, 2, 3)
+	if err != nil {
+		t.Fatalf("LoadEventsBatch failed: %v", err)
+	}
+	if len(batch) != 3 || batch[0].GetSeq() != 2 || batch[2].GetSeq() != 4 {
+		t.Fatalf("expected seqs 2..4, got %d events", len(batch))
+	}
+
+	ev := &event.MarketUpdateEvent{BaseEvent: event.BaseEvent{Seq: 6}, Symbol: "BTC"}
+	if err := ro.SaveEvent(ctx, ev); err == nil {
+		t.Error("a read-only store must refuse writes")
+	}
+}
@@ -0,0 +1,42 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+)
+
+// BarStrategy runs a tick-based strategy (e.g. SMA cross) on closed OHLCV bars
+// instead of raw ticks: each closed bar of one exchange and interval is fed to
+// the inner strategy as a MarketState at the close price. Ticks are ignored.
+type BarStrategy struct {
+	inner    Strategy
+	exchange string
+	interval string
+}
+
+// NewBarStrategy wraps inner so it sees only closed bars of exchange/interval.
+func NewBarStrategy(inner Strategy, exchange, interval string) *BarStrategy {
+	return &BarStrategy{inner: inner, exchange: exchange, interval: interval}
+}
+
+// OnMarketUpdate ignores ticks: the inner strategy only sees bars.
+func (b *BarStrategy) OnMarketUpdate(domain.MarketState, []domain.Order) int {
+	return 0
+}
+
+// OnCandleClose feeds a closed bar to the inner strategy.
+func (b *BarStrategy) OnCandleClose(c domain.Candle, out []domain.Order) int {
+	if c.Exchange != b.exchange || c.Interval != b.interval {
+		return 0
+	}
+	return b.inner.OnMarketUpdate(domain.MarketState{
+		PriceMicros:     c.CloseMicros,
+		TotalQtySats:    c.VolumeSats,
+		LastUpdateUnixM: c.CloseTime(),
+		Symbol:          c.Symbol,
+	}, out)
+}
+
+// OnOrderUpdate forwards order updates to the inner strategy.
+func (b *BarStrategy) OnOrderUpdate(order domain.Order) {
+	b.inner.OnOrderUpdate(order)
+}
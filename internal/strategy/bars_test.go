@@ -0,0 +1,34 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"testing"
+)
+
+type recordingStrategy struct{ states []domain.MarketState }
+
+func (r *recordingStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	r.states = append(r.states, state)
+	return 0
+}
+
+func (r *recordingStrategy) OnOrderUpdate(domain.Order) {}
+
+func TestBarStrategy(t *testing.T) {
+	inner := &recordingStrategy{}
+	bars := NewBarStrategy(inner, "UPBIT", domain.Candle1m)
+	out := make([]domain.Order, 4)
+
+	bars.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: 1}, out)
+	bars.OnCandleClose(domain.Candle{Exchange: "BITGET_SPOT", Symbol: "BTC", Interval: domain.Candle1m, CloseMicros: 2}, out)
+	bars.OnCandleClose(domain.Candle{Exchange: "UPBIT", Symbol: "BTC", Interval: domain.Candle5m, CloseMicros: 3}, out)
+	bars.OnCandleClose(domain.Candle{Exchange: "UPBIT", Symbol: "BTC", Interval: domain.Candle1m,
+		OpenTime: 60_000_000, CloseMicros: 4, VolumeSats: 9}, out)
+
+	if len(inner.states) != 1 {
+		t.Fatalf("inner strategy must see only matching closed bars, got %+v", inner.states)
+	}
+	if s := inner.states[0]; s.Symbol != "BTC" || s.PriceMicros != 4 || s.TotalQtySats != 9 || s.LastUpdateUnixM != 120_000_000 {
+		t.Errorf("bar state = %+v", s)
+	}
+}
@@ -0,0 +1,68 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant/indicators"
+	"crypto_go/pkg/safe"
+)
+
+// BollingerStrategy trades band breakouts: it buys when the price closes
+// above the upper band (mean + k standard deviations of the last period
+// prices) and sells when it closes below the lower band, once per breakout.
+type BollingerStrategy struct {
+	band      *indicators.StdDev
+	kBps      int64 // Band width in standard deviations x 10_000
+	prevBreak string
+	symbol    string
+}
+
+// NewBollingerStrategy creates a strategy over period prices with bands
+// stdDevBps/10_000 standard deviations wide (20_000 = the classic 2σ).
+func NewBollingerStrategy(symbol string, period int, stdDevBps int64) *BollingerStrategy {
+	if period < 2 || stdDevBps <= 0 {
+		panic("BollingerStrategy: need period >= 2 and stdDevBps > 0")
+	}
+	return &BollingerStrategy{
+		band:   indicators.NewStdDev(period),
+		kBps:   stdDevBps,
+		symbol: symbol,
+	}
+}
+
+// OnMarketUpdate compares the price with the bands of the window before it,
+// then adds it to the window. Zero-Alloc.
+func (s *BollingerStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol != s.symbol {
+		return 0
+	}
+	price := int64(state.PriceMicros)
+
+	n := 0
+	if s.band.Ready() {
+		mean := int64(s.band.Mean())
+		width := safe.SafeMulDiv(int64(s.band.Value()), s.kBps, 10_000)
+		side := ""
+		switch {
+		case price > safe.SafeAdd(mean, width):
+			side = domain.SideBuy
+		case price < safe.SafeSub(mean, width):
+			side = domain.SideSell
+		}
+		if side != "" && side != s.prevBreak {
+			n = emit(out, n, s.symbol, side, price)
+		}
+		s.prevBreak = side
+	}
+	s.band.Update(state.PriceMicros)
+	return n
+}
+
+// OnOrderUpdate handles order updates (Empty for now)
+func (s *BollingerStrategy) OnOrderUpdate(domain.Order) {}
+
+// BollingerTemplate returns a Template producing Bollinger breakout strategies.
+func BollingerTemplate(period int, stdDevBps int64) Template {
+	return func(symbol string) Strategy {
+		return NewBollingerStrategy(symbol, period, stdDevBps)
+	}
+}
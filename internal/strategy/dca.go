@@ -0,0 +1,148 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/safe"
+)
+
+// DCAPlan buys AmountMicros of the venue's quote currency worth of Symbol on
+// Exchange at every tick of Timer, until BudgetMicros is spent.
+type DCAPlan struct {
+	Symbol       string
+	Exchange     string // Spot venue (e.g. UPBIT, BITGET_SPOT)
+	Timer        string // engine.timers name
+	AmountMicros int64  // Quote currency per buy
+	BudgetMicros int64  // Quote currency over all buys (0 = unlimited)
+}
+
+// dcaState is a plan's progress.
+type dcaState struct {
+	plan        DCAPlan
+	quote       string // Balance book currency paying for the buys
+	priceMicros int64  // Last price on the plan's venue (sizing reference)
+	spent       int64
+	skipped     uint64 // Ticks without a price, budget or balance
+}
+
+// DCAStrategy accumulates symbols on a schedule (dollar-cost averaging): at
+// every tick of a plan's timer it sends a MARKET buy of the plan's amount,
+// sized at the last price seen on the plan's venue. A buy is skipped while
+// the venue has no price yet, when it would exceed the plan's budget, or when
+// the engine balance book shows less of the quote currency available than it
+// costs (a currency the book never saw, e.g. before the first venue resync,
+// is not checked). Rejected buys are returned to the budget.
+type DCAStrategy struct {
+	plans    []*dcaState
+	balances *domain.BalanceBook
+}
+
+// NewDCAStrategy creates the strategy; it panics on a plan that cannot trade
+// (see the app's strategy.dca checks).
+func NewDCAStrategy(plans []DCAPlan) *DCAStrategy {
+	s := &DCAStrategy{plans: make([]*dcaState, 0, len(plans))}
+	for _, p := range plans {
+		class, ok := domain.VenueAssetClass(p.Exchange)
+		if p.Symbol == "" || p.Timer == "" || !ok || class.Market != domain.MarketSpot || p.AmountMicros <= 0 || p.BudgetMicros < 0 {
+			panic("DCAStrategy: need a symbol, a timer, a known spot venue and amount > 0")
+		}
+		s.plans = append(s.plans, &dcaState{plan: p, quote: class.Quote})
+	}
+	return s
+}
+
+// SetBalances implements BalanceAware.
+func (s *DCAStrategy) SetBalances(book *domain.BalanceBook) {
+	s.balances = book
+}
+
+// Spent returns how much of plan i's budget was spent.
+func (s *DCAStrategy) Spent(i int) int64 {
+	return s.plans[i].spent
+}
+
+// Skipped returns how many of plan i's ticks bought nothing.
+func (s *DCAStrategy) Skipped(i int) uint64 {
+	return s.plans[i].skipped
+}
+
+// OnVenueUpdate records the plans' venue prices.
+func (s *DCAStrategy) OnVenueUpdate(exchange string, state domain.MarketState) {
+	for _, p := range s.plans {
+		if p.plan.Exchange == exchange && p.plan.Symbol == state.Symbol {
+			p.priceMicros = int64(state.PriceMicros)
+		}
+	}
+}
+
+// OnMarketUpdate does nothing: buys happen on timer ticks.
+func (s *DCAStrategy) OnMarketUpdate(domain.MarketState, []domain.Order) int {
+	return 0
+}
+
+// OnTimer buys for every plan scheduled on timer. Zero-Alloc.
+func (s *DCAStrategy) OnTimer(timer domain.Timer, out []domain.Order) int {
+	n := 0
+	for _, p := range s.plans {
+		if p.plan.Timer != timer.Name || n >= len(out) {
+			continue
+		}
+		if !s.affordable(p) {
+			p.skipped++
+			continue
+		}
+		qty := safe.SafeMulDiv(p.plan.AmountMicros, 100_000_000, p.priceMicros)
+		if qty <= 0 {
+			p.skipped++
+			continue
+		}
+		out[n] = domain.Order{
+			Symbol:      p.plan.Symbol,
+			Side:        domain.SideBuy,
+			Type:        domain.OrderTypeMarket,
+			Market:      domain.MarketSpot,
+			Exchange:    p.plan.Exchange,
+			PriceMicros: p.priceMicros, // Reference only for a MARKET order
+			QtySats:     qty,
+			Status:      domain.OrderStatusNew,
+		}
+		p.spent = safe.SafeAdd(p.spent, p.plan.AmountMicros)
+		n++
+	}
+	return n
+}
+
+// affordable reports whether plan p can buy now.
+func (s *DCAStrategy) affordable(p *dcaState) bool {
+	if p.priceMicros <= 0 {
+		return false
+	}
+	if p.plan.BudgetMicros > 0 && safe.SafeAdd(p.spent, p.plan.AmountMicros) > p.plan.BudgetMicros {
+		return false
+	}
+	if s.balances != nil {
+		if available, ok := s.balances.Available(p.quote); ok && available < p.plan.AmountMicros {
+			return false
+		}
+	}
+	return true
+}
+
+// OnOrderRejected returns a rejected buy's amount to its plan's budget.
+func (s *DCAStrategy) OnOrderRejected(rej domain.OrderRejection) {
+	if rej.Order.Side != domain.SideBuy {
+		return
+	}
+	venue := rej.Order.Exchange // Set on OMS refusals; a venue rejection names it in rej.Exchange
+	if venue == "" {
+		venue = rej.Exchange
+	}
+	for _, p := range s.plans {
+		if p.plan.Symbol == rej.Order.Symbol && p.plan.Exchange == venue {
+			p.spent = max(safe.SafeSub(p.spent, p.plan.AmountMicros), 0)
+			return
+		}
+	}
+}
+
+// OnOrderUpdate handles order updates (Empty for now)
+func (s *DCAStrategy) OnOrderUpdate(domain.Order) {}
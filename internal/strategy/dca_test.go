@@ -0,0 +1,58 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"testing"
+)
+
+func TestDCAStrategy(t *testing.T) {
+	s := strategy.NewDCAStrategy([]strategy.DCAPlan{
+		{Symbol: "BTC", Exchange: "UPBIT", Timer: "1d", AmountMicros: 100_000_000_000, BudgetMicros: 250_000_000_000}, // 100k KRW, 250k budget
+		{Symbol: "BTC", Exchange: "BITGET_SPOT", Timer: "1h", AmountMicros: 50_000_000},                               // 50 USDT
+	})
+	book := domain.NewBalanceBook()
+	s.SetBalances(book)
+	out := make([]domain.Order, 4)
+	daily := domain.Timer{Name: "1d"}
+
+	// No Upbit price yet: the Bitget price must not size a KRW buy
+	s.OnVenueUpdate("BITGET_SPOT", domain.MarketState{Symbol: "BTC", PriceMicros: 50_000_000_000})
+	if n := s.OnTimer(daily, out); n != 0 || s.Skipped(0) != 1 {
+		t.Fatalf("bought without a venue price: %+v", out[:n])
+	}
+
+	s.OnVenueUpdate("UPBIT", domain.MarketState{Symbol: "BTC", PriceMicros: 100_000_000_000_000}) // 100M KRW
+	n := s.OnTimer(daily, out)
+	if n != 1 {
+		t.Fatalf("expected one buy, got %d", n)
+	}
+	if o := out[0]; o.Side != domain.SideBuy || o.Exchange != "UPBIT" || o.Market != domain.MarketSpot || o.QtySats != 100_000 {
+		t.Errorf("unexpected order %+v", o)
+	}
+
+	// The synced KRW balance no longer covers a buy
+	book.Get("KRW").Sync(50_000_000_000, 1)
+	if n := s.OnTimer(daily, out); n != 0 {
+		t.Fatalf("bought beyond the balance: %+v", out[:n])
+	}
+	book.Get("KRW").Sync(1_000_000_000_000, 2)
+	if n := s.OnTimer(daily, out); n != 1 || s.Spent(0) != 200_000_000_000 {
+		t.Fatalf("expected the second buy, got %d (spent %d)", n, s.Spent(0))
+	}
+
+	// Budget: a third buy would exceed 250k, unless one was rejected
+	if n := s.OnTimer(daily, out); n != 0 {
+		t.Fatalf("bought beyond the budget: %+v", out[:n])
+	}
+	s.OnOrderRejected(domain.OrderRejection{Order: domain.Order{Symbol: "BTC", Side: domain.SideBuy}, Exchange: "UPBIT"})
+	if n := s.OnTimer(daily, out); n != 1 {
+		t.Fatalf("expected the rejected amount back in the budget, got %d", n)
+	}
+
+	// The hourly plan sizes at the Bitget price; the USDT balance is unknown, so not checked
+	n = s.OnTimer(domain.Timer{Name: "1h"}, out)
+	if n != 1 || out[0].Exchange != "BITGET_SPOT" || out[0].QtySats != 100_000 {
+		t.Fatalf("unexpected hourly buy %+v", out[:n])
+	}
+}
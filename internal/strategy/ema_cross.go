@@ -0,0 +1,57 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant/indicators"
+)
+
+// EMACrossStrategy buys when the short EMA crosses above the long EMA and
+// sells when it crosses below. It reacts faster than SMACrossStrategy and
+// keeps no price history: two running averages are its whole state.
+type EMACrossStrategy struct {
+	short, long *indicators.EMA
+	prevShort   int64
+	prevLong    int64
+	symbol      string
+}
+
+// NewEMACrossStrategy creates a new instance.
+func NewEMACrossStrategy(symbol string, shortPeriod, longPeriod int) *EMACrossStrategy {
+	if shortPeriod <= 0 || shortPeriod >= longPeriod {
+		panic("EMACrossStrategy: need 0 < shortPeriod < longPeriod")
+	}
+	return &EMACrossStrategy{
+		short:  indicators.NewEMA(shortPeriod),
+		long:   indicators.NewEMA(longPeriod),
+		symbol: symbol,
+	}
+}
+
+// OnMarketUpdate feeds the price to both averages and emits on a cross once
+// the long one has seen a full period. Zero-Alloc.
+func (s *EMACrossStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol != s.symbol {
+		return 0
+	}
+	wasReady := s.long.Ready()
+	short, long := int64(s.short.Update(state.PriceMicros)), int64(s.long.Update(state.PriceMicros))
+
+	n := 0
+	if wasReady {
+		if side := crossSide(s.prevShort, s.prevLong, short, long); side != "" {
+			n = emit(out, n, s.symbol, side, int64(state.PriceMicros))
+		}
+	}
+	s.prevShort, s.prevLong = short, long
+	return n
+}
+
+// OnOrderUpdate handles order updates (Empty for now)
+func (s *EMACrossStrategy) OnOrderUpdate(domain.Order) {}
+
+// EMACrossTemplate returns a Template producing EMA cross strategies with the given periods.
+func EMACrossTemplate(shortPeriod, longPeriod int) Template {
+	return func(symbol string) Strategy {
+		return NewEMACrossStrategy(symbol, shortPeriod, longPeriod)
+	}
+}
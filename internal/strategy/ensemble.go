@@ -0,0 +1,113 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+)
+
+// Member is one child of an EnsembleStrategy.
+type Member struct {
+	Strategy Strategy
+	Weight   int64 // Vote weight (1 = plain K-of-N)
+	Veto     bool  // An opposing stance from this member blocks the action
+}
+
+// EnsembleStrategy combines child strategies on the same symbol.
+//
+// Children emit signals only at transitions (e.g. a cross), so each child's most
+// recent signal is remembered as its stance. The ensemble emits an order when the
+// weight behind one side reaches Threshold and that side differs from the last
+// emitted one; a veto member holding the opposite stance blocks it.
+// With unit weights and Threshold K this is "K of N agree".
+type EnsembleStrategy struct {
+	symbol    string
+	members   []Member
+	stances   []string // Last side per member ("" = no opinion yet)
+	lastOrder []domain.Order
+	scratch   []domain.Order // Reused child output buffer (Zero-Alloc)
+	threshold int64
+	lastSide  string
+}
+
+// NewEnsembleStrategy creates an ensemble for symbol that acts once threshold weight agrees.
+func NewEnsembleStrategy(symbol string, threshold int64, members ...Member) *EnsembleStrategy {
+	if len(members) == 0 || threshold <= 0 {
+		panic("EnsembleStrategy: need at least one member and a positive threshold")
+	}
+	return &EnsembleStrategy{
+		symbol:    symbol,
+		members:   members,
+		stances:   make([]string, len(members)),
+		lastOrder: make([]domain.Order, len(members)),
+		scratch:   make([]domain.Order, 4),
+		threshold: threshold,
+	}
+}
+
+// OnMarketUpdate feeds every child, updates their stances and emits at most one order.
+func (e *EnsembleStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol != e.symbol {
+		return 0
+	}
+
+	for i := range e.members {
+		n := e.members[i].Strategy.OnMarketUpdate(state, e.scratch)
+		if n > 0 {
+			// The newest signal defines the stance
+			e.lastOrder[i] = e.scratch[n-1]
+			e.stances[i] = e.scratch[n-1].Side
+		}
+	}
+
+	var buyWeight, sellWeight int64
+	buyVeto, sellVeto := false, false
+	for i, m := range e.members {
+		switch e.stances[i] {
+		case domain.SideBuy:
+			buyWeight += m.Weight
+			sellVeto = sellVeto || m.Veto
+		case domain.SideSell:
+			sellWeight += m.Weight
+			buyVeto = buyVeto || m.Veto
+		}
+	}
+
+	side := ""
+	switch {
+	case buyWeight >= e.threshold && !buyVeto:
+		side = domain.SideBuy
+	case sellWeight >= e.threshold && !sellVeto:
+		side = domain.SideSell
+	}
+	if side == "" || side == e.lastSide || len(out) == 0 {
+		return 0
+	}
+
+	// Use the heaviest agreeing member's order as the template, at the current price
+	best := -1
+	for i, m := range e.members {
+		if e.stances[i] == side && (best < 0 || m.Weight > e.members[best].Weight) {
+			best = i
+		}
+	}
+	out[0] = e.lastOrder[best]
+	out[0].Symbol = e.symbol
+	out[0].PriceMicros = int64(state.PriceMicros)
+	e.lastSide = side
+	return 1
+}
+
+// OnOrderUpdate forwards the update to every child.
+func (e *EnsembleStrategy) OnOrderUpdate(order domain.Order) {
+	for _, m := range e.members {
+		m.Strategy.OnOrderUpdate(order)
+	}
+}
+
+// OnOrderRejected forwards the rejection to every child that handles them.
+func (e *EnsembleStrategy) OnOrderRejected(rej domain.OrderRejection) {
+	for _, m := range e.members {
+		if h, ok := m.Strategy.(RejectionHandler); ok {
+			h.OnOrderRejected(rej)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"testing"
+)
+
+// scriptedStrategy emits the next scripted side ("" = no signal) on every update.
+type scriptedStrategy struct {
+	sides []string
+	i     int
+}
+
+func (s *scriptedStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	side := s.sides[s.i%len(s.sides)]
+	s.i++
+	if side == "" {
+		return 0
+	}
+	out[0] = domain.Order{Symbol: state.Symbol, Side: side, Type: domain.OrderTypeMarket, QtySats: 10000}
+	return 1
+}
+
+func (s *scriptedStrategy) OnOrderUpdate(domain.Order) {}
+
+func script(sides ...string) *scriptedStrategy { return &scriptedStrategy{sides: sides} }
+
+func TestEnsembleStrategy_TwoOfThree(t *testing.T) {
+	e := strategy.NewEnsembleStrategy("BTC", 2,
+		strategy.Member{Strategy: script("BUY", "", "", ""), Weight: 1},
+		strategy.Member{Strategy: script("", "", "BUY", ""), Weight: 1},
+		strategy.Member{Strategy: script("", "SELL", "", ""), Weight: 1},
+	)
+	out := make([]domain.Order, 1)
+	state := domain.MarketState{Symbol: "BTC", PriceMicros: 100}
+
+	if n := e.OnMarketUpdate(state, out); n != 0 {
+		t.Fatalf("tick 1: one BUY vote must not act, got %d", n)
+	}
+	if n := e.OnMarketUpdate(state, out); n != 0 {
+		t.Fatalf("tick 2: 1 BUY vs 1 SELL must not act, got %d", n)
+	}
+	if n := e.OnMarketUpdate(state, out); n != 1 || out[0].Side != domain.SideBuy || out[0].PriceMicros != 100 {
+		t.Fatalf("tick 3: expected BUY from 2 of 3, got %d %+v", n, out[0])
+	}
+	if n := e.OnMarketUpdate(state, out); n != 0 {
+		t.Errorf("tick 4: unchanged agreement must not repeat the order, got %d", n)
+	}
+}
+
+func TestEnsembleStrategy_WeightsAndVeto(t *testing.T) {
+	out := make([]domain.Order, 1)
+	state := domain.MarketState{Symbol: "BTC", PriceMicros: 100}
+
+	// A single heavy member reaches the threshold alone
+	heavy := strategy.NewEnsembleStrategy("BTC", 3,
+		strategy.Member{Strategy: script("SELL"), Weight: 3},
+		strategy.Member{Strategy: script(""), Weight: 1},
+	)
+	if n := heavy.OnMarketUpdate(state, out); n != 1 || out[0].Side != domain.SideSell {
+		t.Errorf("expected weighted SELL, got %d %+v", n, out[0])
+	}
+
+	// A veto member holding SELL blocks an otherwise unanimous BUY
+	vetoed := strategy.NewEnsembleStrategy("BTC", 2,
+		strategy.Member{Strategy: script("BUY"), Weight: 1},
+		strategy.Member{Strategy: script("BUY"), Weight: 1},
+		strategy.Member{Strategy: script("SELL"), Weight: 1, Veto: true},
+	)
+	if n := vetoed.OnMarketUpdate(state, out); n != 0 {
+		t.Errorf("expected veto to block BUY, got %d", n)
+	}
+
+	if n := heavy.OnMarketUpdate(domain.MarketState{Symbol: "ETH"}, out); n != 0 {
+		t.Errorf("other symbol must be ignored, got %d", n)
+	}
+}
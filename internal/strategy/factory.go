@@ -0,0 +1,97 @@
+package strategy
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Factory builds one strategy of a registered type bound to symbol. params
+// holds every parameter of the type: the type's defaults overridden by config.
+type Factory func(symbol string, params map[string]int64) (Strategy, error)
+
+type strategyType struct {
+	defaults map[string]int64
+	build    Factory
+}
+
+var types = map[string]strategyType{}
+
+// RegisterType makes a strategy type available to New under name. defaults
+// lists every parameter the type accepts. Call from init only.
+func RegisterType(name string, defaults map[string]int64, build Factory) {
+	if err := registerType(name, defaults, build); err != nil {
+		panic(err)
+	}
+}
+
+func registerType(name string, defaults map[string]int64, build Factory) error {
+	if _, dup := types[name]; dup {
+		return fmt.Errorf("strategy: duplicate type %q", name)
+	}
+	types[name] = strategyType{defaults: defaults, build: build}
+	return nil
+}
+
+// Types returns the registered strategy type names, sorted.
+func Types() []string {
+	return slices.Sorted(maps.Keys(types))
+}
+
+// New builds a strategy of type typ for symbol. params override the type's
+// defaults; an unknown key or an invalid combination wraps ErrInvalidParam.
+func New(typ, symbol string, params map[string]int64) (Strategy, error) {
+	t, ok := types[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy type %q (want one of %v)", typ, Types())
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("%s: symbol is required", typ)
+	}
+	merged := maps.Clone(t.defaults)
+	for key, value := range params {
+		if _, ok := merged[key]; !ok {
+			return nil, unknownParam(typ, key)
+		}
+		merged[key] = value
+	}
+	return t.build(symbol, merged)
+}
+
+func init() {
+	RegisterType("sma_cross", map[string]int64{ParamShortPeriod: 20, ParamLongPeriod: 50}, func(symbol string, p map[string]int64) (Strategy, error) {
+		short, long := p[ParamShortPeriod], p[ParamLongPeriod]
+		if short <= 0 || short >= long {
+			return nil, badParam("sma_cross", "0 < short_period < long_period")
+		}
+		return NewSMACrossStrategy(symbol, int(short), int(long)), nil
+	})
+	RegisterType("ema_cross", map[string]int64{ParamShortPeriod: 20, ParamLongPeriod: 50}, func(symbol string, p map[string]int64) (Strategy, error) {
+		short, long := p[ParamShortPeriod], p[ParamLongPeriod]
+		if short <= 0 || short >= long {
+			return nil, badParam("ema_cross", "0 < short_period < long_period")
+		}
+		return NewEMACrossStrategy(symbol, int(short), int(long)), nil
+	})
+	RegisterType("macd", map[string]int64{ParamShortPeriod: 12, ParamLongPeriod: 26, ParamSignalPeriod: 9}, func(symbol string, p map[string]int64) (Strategy, error) {
+		fast, slow, signal := p[ParamShortPeriod], p[ParamLongPeriod], p[ParamSignalPeriod]
+		if fast <= 0 || fast >= slow || signal <= 0 {
+			return nil, badParam("macd", "0 < short_period < long_period and signal_period > 0")
+		}
+		return NewMACDStrategy(symbol, int(fast), int(slow), int(signal)), nil
+	})
+	RegisterType("rsi", map[string]int64{ParamPeriod: 14, ParamLower: 30, ParamUpper: 70}, func(symbol string, p map[string]int64) (Strategy, error) {
+		period, lower, upper := p[ParamPeriod], p[ParamLower], p[ParamUpper]
+		if period <= 0 || lower <= 0 || lower >= upper || upper >= 100 {
+			return nil, badParam("rsi", "period > 0 and 0 < lower < upper < 100")
+		}
+		return NewRSIStrategy(symbol, int(period), lower, upper), nil
+	})
+	RegisterType("bollinger", map[string]int64{ParamPeriod: 20, ParamStdDevBps: 20_000}, func(symbol string, p map[string]int64) (Strategy, error) {
+		period, kBps := p[ParamPeriod], p[ParamStdDevBps]
+		if period < 2 || kBps <= 0 {
+			return nil, badParam("bollinger", "period >= 2 and std_dev_bps > 0")
+		}
+		return NewBollingerStrategy(symbol, int(period), kBps), nil
+	})
+}
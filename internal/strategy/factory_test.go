@@ -0,0 +1,40 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"errors"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, typ := range strategy.Types() {
+		if _, err := strategy.New(typ, "BTC", nil); err != nil {
+			t.Errorf("%s with defaults: %v", typ, err)
+		}
+	}
+
+	// Params override the defaults
+	s, err := strategy.New("ema_cross", "BTC", map[string]int64{strategy.ParamShortPeriod: 2, strategy.ParamLongPeriod: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := feed(s, 100, 100, 100, 100, 200, 210, 50, 40)
+	expectSides(t, got, map[int]string{4: domain.SideBuy, 6: domain.SideSell})
+
+	for _, params := range []map[string]int64{
+		{strategy.ParamShortPeriod: 60},       // short >= default long
+		{strategy.ParamUpper: 70},             // not an sma_cross parameter
+		{strategy.ParamLongPeriod: 0, "x": 1}, // unknown key
+	} {
+		if _, err := strategy.New("sma_cross", "BTC", params); !errors.Is(err, strategy.ErrInvalidParam) {
+			t.Errorf("%v: err = %v; want ErrInvalidParam", params, err)
+		}
+	}
+	if _, err := strategy.New("moon", "BTC", nil); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if _, err := strategy.New("rsi", "", nil); err == nil {
+		t.Error("expected error for missing symbol")
+	}
+}
@@ -0,0 +1,185 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/risk"
+	"crypto_go/pkg/safe"
+)
+
+// FundingHarvestConfig configures a FundingHarvestStrategy. Rates are the
+// predicted rate per funding epoch (0.0001 = 100), amounts are micros of the
+// quote currency both legs trade in (USDT).
+type FundingHarvestConfig struct {
+	Symbols         []string
+	SpotExchange    string      // Venue of the long spot leg (e.g. BITGET_SPOT)
+	PerpExchange    string      // Venue of the short perp leg, whose funding is harvested (e.g. BITGET_FUTURES)
+	EntryRateMicros int64       // Open when the predicted rate reaches it
+	ExitRateMicros  int64       // Unwind when it falls to it (< EntryRateMicros)
+	NotionalMicros  int64       // Target size of each leg
+	Limits          risk.Limits // Risk engine limits the legs are sized within (zero = none)
+}
+
+// harvestPosition is the hedge the strategy holds on one symbol, as sent:
+// both legs are MARKET orders, assumed filled unless rejected.
+type harvestPosition struct {
+	priceMicros int64 // Last market price (sizing reference)
+	spotSats    int64 // Long spot
+	perpSats    int64 // Short perp
+}
+
+// FundingHarvestStrategy collects perpetual funding delta-neutrally: when the
+// perp venue's predicted funding rate reaches the entry rate it buys spot and
+// shorts the same quantity of the perp, so longs pay it funding while price
+// moves cancel out. When the prediction falls to the exit rate (a flip
+// against the short is coming) it unwinds both legs, at the latest at the
+// pre-funding warning of the epoch that would charge it.
+//
+// Each leg is sized to NotionalMicros at the last price, capped by the risk
+// engine's order notional and position limits. A leg the OMS or the venue
+// rejects breaks the hedge; the other leg is then closed at the symbol's next
+// market update.
+type FundingHarvestStrategy struct {
+	cfg       FundingHarvestConfig
+	positions map[string]*harvestPosition
+}
+
+// NewFundingHarvestStrategy creates the strategy; it panics on a config that
+// cannot trade (see the app's strategy.funding_harvest checks).
+func NewFundingHarvestStrategy(cfg FundingHarvestConfig) *FundingHarvestStrategy {
+	if cfg.SpotExchange == "" || cfg.PerpExchange == "" || cfg.SpotExchange == cfg.PerpExchange ||
+		cfg.ExitRateMicros >= cfg.EntryRateMicros || cfg.NotionalMicros <= 0 {
+		panic("FundingHarvestStrategy: need distinct spot/perp venues, exit rate < entry rate and notional > 0")
+	}
+	s := &FundingHarvestStrategy{cfg: cfg, positions: make(map[string]*harvestPosition, len(cfg.Symbols))}
+	for _, symbol := range cfg.Symbols {
+		s.positions[symbol] = &harvestPosition{}
+	}
+	return s
+}
+
+// Held returns the spot and perp quantities held on symbol.
+func (s *FundingHarvestStrategy) Held(symbol string) (spotSats, perpSats int64) {
+	if p, ok := s.positions[symbol]; ok {
+		return p.spotSats, p.perpSats
+	}
+	return 0, 0
+}
+
+// OnMarketUpdate records the sizing price and closes what is left of a
+// broken hedge. Zero-Alloc.
+func (s *FundingHarvestStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	p, ok := s.positions[state.Symbol]
+	if !ok {
+		return 0
+	}
+	p.priceMicros = int64(state.PriceMicros)
+	if p.spotSats == p.perpSats {
+		return 0
+	}
+	return s.unwind(state.Symbol, p, out)
+}
+
+// OnFundingRate opens or unwinds the hedge on a new prediction of the perp venue.
+func (s *FundingHarvestStrategy) OnFundingRate(epoch domain.FundingEpoch, out []domain.Order) int {
+	return s.decide(epoch, out)
+}
+
+// OnFundingSoon re-checks the prediction right before the epoch settles.
+func (s *FundingHarvestStrategy) OnFundingSoon(epoch domain.FundingEpoch, out []domain.Order) int {
+	return s.decide(epoch, out)
+}
+
+func (s *FundingHarvestStrategy) decide(epoch domain.FundingEpoch, out []domain.Order) int {
+	p, ok := s.positions[epoch.Symbol]
+	if !ok || epoch.Exchange != s.cfg.PerpExchange {
+		return 0
+	}
+	if p.spotSats != 0 || p.perpSats != 0 {
+		if epoch.RateMicros <= s.cfg.ExitRateMicros {
+			return s.unwind(epoch.Symbol, p, out)
+		}
+		return 0
+	}
+	if epoch.RateMicros < s.cfg.EntryRateMicros || p.priceMicros <= 0 || len(out) < 2 {
+		return 0
+	}
+	qty := s.size(p.priceMicros)
+	if qty <= 0 {
+		return 0
+	}
+	out[0] = s.leg(epoch.Symbol, s.cfg.SpotExchange, domain.MarketSpot, domain.SideBuy, p.priceMicros, qty)
+	out[1] = s.leg(epoch.Symbol, s.cfg.PerpExchange, domain.MarketFutures, domain.SideSell, p.priceMicros, qty)
+	p.spotSats, p.perpSats = qty, qty
+	return 2
+}
+
+// size returns the quantity of each leg at price.
+func (s *FundingHarvestStrategy) size(price int64) int64 {
+	notional := s.cfg.NotionalMicros
+	if limit := s.cfg.Limits.MaxOrderNotionalMicros; limit > 0 && limit < notional {
+		notional = limit
+	}
+	qty := safe.SafeMulDiv(notional, 100_000_000, price)
+	if limit := s.cfg.Limits.MaxPositionSats; limit > 0 && limit < qty {
+		qty = limit
+	}
+	return qty
+}
+
+// unwind closes both legs of symbol's hedge (whichever are held).
+func (s *FundingHarvestStrategy) unwind(symbol string, p *harvestPosition, out []domain.Order) int {
+	if len(out) < 2 {
+		return 0
+	}
+	n := 0
+	if p.spotSats > 0 {
+		out[n] = s.leg(symbol, s.cfg.SpotExchange, domain.MarketSpot, domain.SideSell, p.priceMicros, p.spotSats)
+		n++
+	}
+	if p.perpSats > 0 {
+		out[n] = s.leg(symbol, s.cfg.PerpExchange, domain.MarketFutures, domain.SideBuy, p.priceMicros, p.perpSats)
+		n++
+	}
+	p.spotSats, p.perpSats = 0, 0
+	return n
+}
+
+func (s *FundingHarvestStrategy) leg(symbol, exchange, market, side string, price, qty int64) domain.Order {
+	return domain.Order{
+		Symbol:      symbol,
+		Side:        side,
+		Type:        domain.OrderTypeMarket,
+		Market:      market,
+		Exchange:    exchange,
+		PriceMicros: price, // Reference only for a MARKET order
+		QtySats:     qty,
+		Status:      domain.OrderStatusNew,
+	}
+}
+
+// OnOrderRejected takes a rejected leg back out of the position it was
+// meant to open or close.
+func (s *FundingHarvestStrategy) OnOrderRejected(rej domain.OrderRejection) {
+	p, ok := s.positions[rej.Order.Symbol]
+	if !ok {
+		return
+	}
+	venue := rej.Order.Exchange // Set on OMS refusals; a venue rejection names it in rej.Exchange
+	if venue == "" {
+		venue = rej.Exchange
+	}
+	qty := rej.Order.QtySats
+	switch {
+	case venue == s.cfg.SpotExchange && rej.Order.Side == domain.SideBuy:
+		p.spotSats = max(p.spotSats-qty, 0)
+	case venue == s.cfg.SpotExchange && rej.Order.Side == domain.SideSell:
+		p.spotSats += qty
+	case venue == s.cfg.PerpExchange && rej.Order.Side == domain.SideSell:
+		p.perpSats = max(p.perpSats-qty, 0)
+	case venue == s.cfg.PerpExchange && rej.Order.Side == domain.SideBuy:
+		p.perpSats += qty
+	}
+}
+
+// OnOrderUpdate handles order updates (Empty for now)
+func (s *FundingHarvestStrategy) OnOrderUpdate(domain.Order) {}
@@ -0,0 +1,100 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/risk"
+	"crypto_go/internal/strategy"
+	"errors"
+	"testing"
+)
+
+func harvestRate(exchange string, rate int64) domain.FundingEpoch {
+	return domain.FundingEpoch{Exchange: exchange, Symbol: "BTC", NextTs: 1, RateMicros: rate}
+}
+
+func TestFundingHarvestStrategy(t *testing.T) {
+	s := strategy.NewFundingHarvestStrategy(strategy.FundingHarvestConfig{
+		Symbols:         []string{"BTC"},
+		SpotExchange:    "BITGET_SPOT",
+		PerpExchange:    "BITGET_FUTURES",
+		EntryRateMicros: 300,
+		ExitRateMicros:  0,
+		NotionalMicros:  1_000_000_000, // 1000 USDT
+		Limits:          risk.Limits{MaxOrderNotionalMicros: 500_000_000},
+	})
+	out := make([]domain.Order, 4)
+
+	// No price yet, another venue's rate, or a rate below entry: nothing
+	if n := s.OnFundingRate(harvestRate("BITGET_FUTURES", 500), out); n != 0 {
+		t.Fatalf("opened without a price: %+v", out[:n])
+	}
+	s.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: 50_000_000_000}, out)
+	if n := s.OnFundingRate(harvestRate("BYBIT_LINEAR", 500), out); n != 0 {
+		t.Fatalf("opened on another venue's rate: %+v", out[:n])
+	}
+	if n := s.OnFundingRate(harvestRate("BITGET_FUTURES", 200), out); n != 0 {
+		t.Fatalf("opened below the entry rate: %+v", out[:n])
+	}
+
+	// Entry: spot long + perp short, sized to the risk engine's 500 USDT order cap
+	n := s.OnFundingRate(harvestRate("BITGET_FUTURES", 300), out)
+	if n != 2 {
+		t.Fatalf("expected both legs, got %d", n)
+	}
+	spot, perp := out[0], out[1]
+	if spot.Side != domain.SideBuy || spot.Market != domain.MarketSpot || spot.Exchange != "BITGET_SPOT" || spot.QtySats != 1_000_000 {
+		t.Errorf("unexpected spot leg: %+v", spot)
+	}
+	if perp.Side != domain.SideSell || perp.Market != domain.MarketFutures || perp.Exchange != "BITGET_FUTURES" || perp.QtySats != 1_000_000 {
+		t.Errorf("unexpected perp leg: %+v", perp)
+	}
+
+	// Held while the rate stays above exit; the pre-funding warning of an
+	// adverse prediction unwinds both legs
+	if n := s.OnFundingRate(harvestRate("BITGET_FUTURES", 100), out); n != 0 {
+		t.Fatalf("unwound above the exit rate: %+v", out[:n])
+	}
+	n = s.OnFundingSoon(harvestRate("BITGET_FUTURES", -50), out)
+	if n != 2 || out[0].Side != domain.SideSell || out[0].Market != domain.MarketSpot || out[1].Side != domain.SideBuy || out[1].Market != domain.MarketFutures {
+		t.Fatalf("expected both legs unwound, got %+v", out[:n])
+	}
+	if spot, perp := s.Held("BTC"); spot != 0 || perp != 0 {
+		t.Errorf("held %d/%d after unwinding", spot, perp)
+	}
+
+	// A rejected perp leg breaks the hedge: the spot leg is closed at the next update
+	if n := s.OnFundingRate(harvestRate("BITGET_FUTURES", 400), out); n != 2 {
+		t.Fatalf("expected re-entry, got %d", n)
+	}
+	s.OnOrderRejected(domain.OrderRejection{Order: domain.Order{Symbol: "BTC", Side: domain.SideSell, QtySats: 1_000_000}, Exchange: "BITGET_FUTURES"})
+	n = s.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: 50_000_000_000}, out)
+	if n != 1 || out[0].Side != domain.SideSell || out[0].Exchange != "BITGET_SPOT" || out[0].QtySats != 1_000_000 {
+		t.Fatalf("expected the spot leg closed, got %+v", out[:n])
+	}
+	if n := s.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: 50_000_000_000}, out); n != 0 {
+		t.Errorf("flat hedge should be left alone, got %+v", out[:n])
+	}
+}
+
+func TestFundingHarvestStrategy_SetParam(t *testing.T) {
+	s := strategy.NewFundingHarvestStrategy(strategy.FundingHarvestConfig{
+		Symbols: []string{"BTC"}, SpotExchange: "BITGET_SPOT", PerpExchange: "BITGET_FUTURES",
+		EntryRateMicros: 300, NotionalMicros: 1_000_000,
+	})
+	for _, tc := range []struct {
+		key   string
+		value int64
+	}{{strategy.ParamExitRate, 300}, {strategy.ParamEntryRate, -1}, {strategy.ParamNotional, 0}, {strategy.ParamPeriod, 5}} {
+		if err := s.SetParam(tc.key, tc.value); !errors.Is(err, strategy.ErrInvalidParam) {
+			t.Errorf("%s=%d: err = %v; want ErrInvalidParam", tc.key, tc.value, err)
+		}
+	}
+	if err := s.SetParam(strategy.ParamEntryRate, 100); err != nil {
+		t.Fatal(err)
+	}
+	out := make([]domain.Order, 2)
+	s.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: 100_000_000}, out)
+	if n := s.OnFundingRate(harvestRate("BITGET_FUTURES", 150), out); n != 2 {
+		t.Errorf("expected entry at the lowered rate, got %d", n)
+	}
+}
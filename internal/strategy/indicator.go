@@ -0,0 +1,38 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+)
+
+// signalQtySats is the size of the built-in indicator strategies' orders
+// (hardcoded for MVP, like SMACrossStrategy).
+const signalQtySats = 10000
+
+// emit writes a MARKET order for side at price to out[n] if there is room and
+// returns the new count.
+func emit(out []domain.Order, n int, symbol, side string, price int64) int {
+	if n >= len(out) {
+		return n
+	}
+	out[n] = domain.Order{
+		Symbol:      symbol,
+		Side:        side,
+		Type:        domain.OrderTypeMarket,
+		PriceMicros: price, // Reference only for a MARKET order
+		QtySats:     signalQtySats,
+		Status:      domain.OrderStatusNew,
+	}
+	return n + 1
+}
+
+// crossSide returns BUY when fast crossed above slow between two updates,
+// SELL when it crossed below, "" otherwise.
+func crossSide(prevFast, prevSlow, fast, slow int64) string {
+	switch {
+	case prevFast <= prevSlow && fast > slow:
+		return domain.SideBuy
+	case prevFast >= prevSlow && fast < slow:
+		return domain.SideSell
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+// feed pushes prices to strat and returns the side emitted per price ("" = none).
+func feed(strat strategy.Strategy, prices ...int64) []string {
+	sides := make([]string, len(prices))
+	out := make([]domain.Order, 1)
+	for i, p := range prices {
+		if strat.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: quant.PriceMicros(p)}, out) == 1 {
+			sides[i] = out[0].Side
+		}
+	}
+	return sides
+}
+
+// expectSides checks that exactly the given indexes emitted the given sides.
+func expectSides(t *testing.T, got []string, want map[int]string) {
+	t.Helper()
+	for i, side := range got {
+		if side != want[i] {
+			t.Errorf("price %d: got %q, want %q (all: %q)", i, side, want[i], got)
+		}
+	}
+}
+
+func TestEMACrossStrategy(t *testing.T) {
+	got := feed(strategy.NewEMACrossStrategy("BTC", 2, 4), 100, 100, 100, 100, 200, 210, 50, 40)
+	expectSides(t, got, map[int]string{4: domain.SideBuy, 6: domain.SideSell})
+}
+
+func TestRSIStrategy(t *testing.T) {
+	// Period 3: rises keep the RSI high, the first drop takes it under 30, a rally back over 70
+	got := feed(strategy.NewRSIStrategy("BTC", 3, 30, 70), 100, 101, 102, 103, 90, 80, 70, 100, 130, 160)
+	expectSides(t, got, map[int]string{4: domain.SideBuy, 7: domain.SideSell})
+}
+
+func TestMACDStrategy(t *testing.T) {
+	prices := []int64{100, 100, 100, 100, 100, 100, 200, 200, 50, 50}
+	got := feed(strategy.NewMACDStrategy("BTC", 2, 4, 2), prices...)
+	expectSides(t, got, map[int]string{6: domain.SideBuy, 8: domain.SideSell})
+}
+
+func TestBollingerStrategy(t *testing.T) {
+	// Window 4 at 2σ: a flat window has zero width, so any move breaks out
+	got := feed(strategy.NewBollingerStrategy("BTC", 4, 20_000), 100, 100, 100, 100, 101, 102, 100, 100, 100, 100, 90)
+	expectSides(t, got, map[int]string{4: domain.SideBuy, 10: domain.SideSell})
+}
+
+func TestBollingerStrategy_LargePrices(t *testing.T) {
+	// KRW BTC prices in micros: squared deviations would overflow int64 unscaled
+	base := int64(140_000_000) * 1_000_000
+	got := feed(strategy.NewBollingerStrategy("BTC", 3, 20_000), base, base+base/100, base-base/100, base*2)
+	expectSides(t, got, map[int]string{3: domain.SideBuy})
+}
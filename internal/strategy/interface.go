@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
 )
 
 // Strategy defines the interface for trading logic.
@@ -14,3 +15,113 @@ type Strategy interface {
 	// OnOrderUpdate is called when an order status changes (Filled, Canceled, etc).
 	OnOrderUpdate(order domain.Order)
 }
+
+// OrderBookHandler is optionally implemented by strategies that use depth
+// (e.g. slippage estimates via OrderBook.EstimateFill). The book is owned by the
+// engine: read it during the call only, never retain or modify it.
+type OrderBookHandler interface {
+	OnOrderBookUpdate(book *domain.OrderBook)
+}
+
+// TradeHandler is optionally implemented by strategies that consume the trade
+// tape (e.g. volume delta / CVD indicators) rather than only ticker snapshots.
+type TradeHandler interface {
+	OnTrade(trade domain.Trade)
+}
+
+// CandleHandler is optionally implemented by strategies that work on OHLCV bars.
+// It is called once per bar when the bar closes, and may emit orders like
+// OnMarketUpdate (same Zero-Alloc 'out' contract).
+type CandleHandler interface {
+	OnCandleClose(candle domain.Candle, out []domain.Order) int
+}
+
+// ContextHandler is optionally implemented by strategies that use low-frequency
+// context metrics (stablecoin supply, exchange netflow) as regime filters. It is
+// called once per new reading, typically daily.
+type ContextHandler interface {
+	OnContext(metric domain.ContextMetric)
+}
+
+// SentimentHandler is optionally implemented by long-horizon strategies that
+// scale exposure with market sentiment (Fear & Greed, funding). It is called once
+// per new reading.
+type SentimentHandler interface {
+	OnSentiment(sentiment domain.Sentiment)
+}
+
+// RejectionHandler is optionally implemented by strategies that react to venue
+// rejections (e.g. shrink size on RejectMinSize, back off on RejectRateLimited).
+type RejectionHandler interface {
+	OnOrderRejected(rej domain.OrderRejection)
+}
+
+// NoticeHandler is optionally implemented by strategies that react to exchange
+// announcements (e.g. stop buying a coin on DELISTING, flatten it on a WALLET
+// suspension that breaks arbitrage).
+type NoticeHandler interface {
+	OnNotice(notice domain.Notice)
+}
+
+// SignalHandler is optionally implemented by strategies that act on external
+// signals (TradingView alerts, custom scripts) posted to the signal webhook. It
+// may emit orders like OnMarketUpdate (same Zero-Alloc 'out' contract).
+type SignalHandler interface {
+	OnSignal(signal domain.Signal, out []domain.Order) int
+}
+
+// FundingHandler is optionally implemented by funding-sensitive strategies
+// (e.g. close a perp position rather than pay funding). It is called once per
+// funding epoch, the configured warning time before it, and may emit orders
+// like OnMarketUpdate (same Zero-Alloc 'out' contract).
+type FundingHandler interface {
+	OnFundingSoon(epoch domain.FundingEpoch, out []domain.Order) int
+}
+
+// FundingRateHandler is optionally implemented by strategies trading the
+// funding rate itself (e.g. delta-neutral carry). It is called for every new
+// or updated funding epoch of any venue, with the predicted rate, and may
+// emit orders like OnMarketUpdate (same Zero-Alloc 'out' contract).
+type FundingRateHandler interface {
+	OnFundingRate(epoch domain.FundingEpoch, out []domain.Order) int
+}
+
+// TimerHandler is optionally implemented by strategies with time-based logic
+// (bar close, countdowns, stale-data checks). It is called for every tick of
+// every configured timer, at the tick's scheduled time, and may emit orders
+// like OnMarketUpdate (same Zero-Alloc 'out' contract).
+type TimerHandler interface {
+	OnTimer(timer domain.Timer, out []domain.Order) int
+}
+
+// ClockAware is optionally implemented by strategies that need the current
+// time outside event timestamps (e.g. order expiry). The engine hands over its
+// clock; reading it instead of time.Now keeps backtests and replays
+// independent of wall time.
+type ClockAware interface {
+	SetClock(clock quant.Clock)
+}
+
+// BalanceAware is optionally implemented by strategies that budget against
+// the account (e.g. scheduled buys). The engine hands over its balance book,
+// synced from the venues; read it during strategy calls only, never modify it.
+type BalanceAware interface {
+	SetBalances(book *domain.BalanceBook)
+}
+
+// VenueHandler is optionally implemented by strategies that price orders for
+// a specific venue. MarketState merges every venue's updates of a symbol (a
+// KRW and a USDT price alternate); OnVenueUpdate is called right before
+// OnMarketUpdate with the venue the update came from.
+type VenueHandler interface {
+	OnVenueUpdate(exchange string, state domain.MarketState)
+}
+
+// Tunable is optionally implemented by strategies whose parameters can change
+// at runtime (CmdSetStrategyParam). Keys are the config names of the
+// parameters (e.g. "short_period"); an unknown key or a value the strategy
+// cannot run with returns an error wrapping ErrInvalidParam and changes
+// nothing. A change restarts the indicators' warm-up.
+type Tunable interface {
+	SetParam(key string, value int64) error
+}
@@ -0,0 +1,65 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/quant/indicators"
+	"crypto_go/pkg/safe"
+)
+
+// MACDStrategy buys when the MACD line (fast EMA - slow EMA) crosses above
+// its signal line (an EMA of the MACD) and sells when it crosses below.
+type MACDStrategy struct {
+	fast, slow, signal *indicators.EMA
+	prevMACD           int64
+	prevSignal         int64
+	symbol             string
+}
+
+// NewMACDStrategy creates a new instance (classic periods: 12, 26, 9).
+func NewMACDStrategy(symbol string, fastPeriod, slowPeriod, signalPeriod int) *MACDStrategy {
+	if fastPeriod <= 0 || fastPeriod >= slowPeriod || signalPeriod <= 0 {
+		panic("MACDStrategy: need 0 < fastPeriod < slowPeriod and signalPeriod > 0")
+	}
+	return &MACDStrategy{
+		fast:   indicators.NewEMA(fastPeriod),
+		slow:   indicators.NewEMA(slowPeriod),
+		signal: indicators.NewEMA(signalPeriod),
+		symbol: symbol,
+	}
+}
+
+// OnMarketUpdate updates the MACD and its signal line; the signal line starts
+// once the slow EMA has a full period and crosses count once it has one too.
+// Zero-Alloc.
+func (s *MACDStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol != s.symbol {
+		return 0
+	}
+	fast, slow := s.fast.Update(state.PriceMicros), s.slow.Update(state.PriceMicros)
+	if !s.slow.Ready() {
+		return 0
+	}
+	macd := safe.SafeSub(int64(fast), int64(slow))
+	wasReady := s.signal.Ready()
+	signal := int64(s.signal.Update(quant.PriceMicros(macd)))
+
+	n := 0
+	if wasReady {
+		if side := crossSide(s.prevMACD, s.prevSignal, macd, signal); side != "" {
+			n = emit(out, n, s.symbol, side, int64(state.PriceMicros))
+		}
+	}
+	s.prevMACD, s.prevSignal = macd, signal
+	return n
+}
+
+// OnOrderUpdate handles order updates (Empty for now)
+func (s *MACDStrategy) OnOrderUpdate(domain.Order) {}
+
+// MACDTemplate returns a Template producing MACD strategies with the given periods.
+func MACDTemplate(fastPeriod, slowPeriod, signalPeriod int) Template {
+	return func(symbol string) Strategy {
+		return NewMACDStrategy(symbol, fastPeriod, slowPeriod, signalPeriod)
+	}
+}
@@ -0,0 +1,200 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidParam is wrapped by Tunable.SetParam errors.
+var ErrInvalidParam = errors.New("invalid strategy parameter")
+
+// Parameter keys of the built-in strategies (strategy.watchlist config names).
+const (
+	ParamShortPeriod  = "short_period"
+	ParamLongPeriod   = "long_period"
+	ParamSignalPeriod = "signal_period"
+	ParamPeriod       = "period"
+	ParamLower        = "lower"
+	ParamUpper        = "upper"
+	ParamStdDevBps    = "std_dev_bps"
+	ParamEntryRate    = "entry_rate" // strategy.funding_harvest
+	ParamExitRate     = "exit_rate"
+	ParamNotional     = "notional"
+)
+
+func unknownParam(strategy, key string) error {
+	return fmt.Errorf("%w: %s has no parameter %q", ErrInvalidParam, strategy, key)
+}
+
+func badParam(strategy, rule string) error {
+	return fmt.Errorf("%w: %s needs %s", ErrInvalidParam, strategy, rule)
+}
+
+// SetParam changes short_period or long_period and restarts the averages.
+func (s *SMACrossStrategy) SetParam(key string, value int64) error {
+	short, long := s.short.Period(), s.long.Period()
+	switch key {
+	case ParamShortPeriod:
+		short = int(value)
+	case ParamLongPeriod:
+		long = int(value)
+	default:
+		return unknownParam("sma_cross", key)
+	}
+	if short <= 0 || short >= long {
+		return badParam("sma_cross", "0 < short_period < long_period")
+	}
+	*s = *NewSMACrossStrategy(s.symbol, short, long)
+	return nil
+}
+
+// SetParam changes short_period or long_period and restarts the averages.
+func (s *EMACrossStrategy) SetParam(key string, value int64) error {
+	short, long := s.short.Period(), s.long.Period()
+	switch key {
+	case ParamShortPeriod:
+		short = int(value)
+	case ParamLongPeriod:
+		long = int(value)
+	default:
+		return unknownParam("ema_cross", key)
+	}
+	if short <= 0 || short >= long {
+		return badParam("ema_cross", "0 < short_period < long_period")
+	}
+	*s = *NewEMACrossStrategy(s.symbol, short, long)
+	return nil
+}
+
+// SetParam changes short_period (fast EMA), long_period (slow EMA) or
+// signal_period and restarts the averages.
+func (s *MACDStrategy) SetParam(key string, value int64) error {
+	fast, slow, signal := s.fast.Period(), s.slow.Period(), s.signal.Period()
+	switch key {
+	case ParamShortPeriod:
+		fast = int(value)
+	case ParamLongPeriod:
+		slow = int(value)
+	case ParamSignalPeriod:
+		signal = int(value)
+	default:
+		return unknownParam("macd", key)
+	}
+	if fast <= 0 || fast >= slow || signal <= 0 {
+		return badParam("macd", "0 < short_period < long_period and signal_period > 0")
+	}
+	*s = *NewMACDStrategy(s.symbol, fast, slow, signal)
+	return nil
+}
+
+// SetParam changes period, lower or upper (RSI points). A level change
+// keeps the averages; a period change restarts them.
+func (s *RSIStrategy) SetParam(key string, value int64) error {
+	period, lower, upper := s.rsi.Period(), s.lower/100, s.upper/100
+	switch key {
+	case ParamPeriod:
+		period = int(value)
+	case ParamLower:
+		lower = value
+	case ParamUpper:
+		upper = value
+	default:
+		return unknownParam("rsi", key)
+	}
+	if period <= 0 || lower <= 0 || lower >= upper || upper >= 100 {
+		return badParam("rsi", "period > 0 and 0 < lower < upper < 100")
+	}
+	if period != s.rsi.Period() {
+		*s = *NewRSIStrategy(s.symbol, period, lower, upper)
+		return nil
+	}
+	s.lower, s.upper = lower*100, upper*100
+	return nil
+}
+
+// SetParam changes period or std_dev_bps. A width change keeps the window;
+// a period change restarts it.
+func (s *BollingerStrategy) SetParam(key string, value int64) error {
+	period, kBps := s.band.Period(), s.kBps
+	switch key {
+	case ParamPeriod:
+		period = int(value)
+	case ParamStdDevBps:
+		kBps = value
+	default:
+		return unknownParam("bollinger", key)
+	}
+	if period < 2 || kBps <= 0 {
+		return badParam("bollinger", "period >= 2 and std_dev_bps > 0")
+	}
+	if period != s.band.Period() {
+		*s = *NewBollingerStrategy(s.symbol, period, kBps)
+		return nil
+	}
+	s.kBps = kBps
+	return nil
+}
+
+// SetParam changes entry_rate, exit_rate or notional. Open hedges are kept;
+// they unwind by the new exit rate.
+func (s *FundingHarvestStrategy) SetParam(key string, value int64) error {
+	cfg := s.cfg
+	switch key {
+	case ParamEntryRate:
+		cfg.EntryRateMicros = value
+	case ParamExitRate:
+		cfg.ExitRateMicros = value
+	case ParamNotional:
+		cfg.NotionalMicros = value
+	default:
+		return unknownParam("funding_harvest", key)
+	}
+	if cfg.ExitRateMicros >= cfg.EntryRateMicros || cfg.NotionalMicros <= 0 {
+		return badParam("funding_harvest", "exit_rate < entry_rate and notional > 0")
+	}
+	s.cfg = cfg
+	return nil
+}
+
+// SetParam applies the change to every instance. Instances share the
+// template's parameters, so they accept or reject a change alike.
+func (w *WatchlistStrategy) SetParam(key string, value int64) error {
+	for _, symbol := range w.Symbols() {
+		t, ok := w.instances[symbol].(Tunable)
+		if !ok {
+			return fmt.Errorf("%w: strategy of %s is not tunable", ErrInvalidParam, symbol)
+		}
+		if err := t.SetParam(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetParam changes parameter key of the strategy registered under name; key
+// is "name.key" (e.g. "btc_rsi.upper").
+func (r *StrategyRegistry) SetParam(key string, value int64) error {
+	name, param, ok := strings.Cut(key, ".")
+	if !ok {
+		return fmt.Errorf("%w: %q is not <strategy>.<parameter>", ErrInvalidParam, key)
+	}
+	e := r.lookup(name)
+	if e == nil {
+		return fmt.Errorf("%w: no strategy %q", ErrInvalidParam, name)
+	}
+	t, ok := e.strat.(Tunable)
+	if !ok {
+		return fmt.Errorf("%w: strategy %q is not tunable", ErrInvalidParam, name)
+	}
+	return t.SetParam(param, value)
+}
+
+// SetParam forwards the change to the inner strategy.
+func (b *BarStrategy) SetParam(key string, value int64) error {
+	t, ok := b.inner.(Tunable)
+	if !ok {
+		return fmt.Errorf("%w: bar strategy's inner strategy is not tunable", ErrInvalidParam)
+	}
+	return t.SetParam(key, value)
+}
@@ -0,0 +1,60 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"errors"
+	"testing"
+)
+
+func TestSetParam(t *testing.T) {
+	rsi := strategy.NewRSIStrategy("BTC", 3, 30, 70)
+	for _, tc := range []struct {
+		key   string
+		value int64
+	}{{strategy.ParamLower, 80}, {strategy.ParamUpper, 100}, {strategy.ParamPeriod, 0}, {strategy.ParamShortPeriod, 5}} {
+		if err := rsi.SetParam(tc.key, tc.value); !errors.Is(err, strategy.ErrInvalidParam) {
+			t.Errorf("rsi %s=%d: err = %v; want ErrInvalidParam", tc.key, tc.value, err)
+		}
+	}
+	// Unchanged by the refused values: the usual crossings still fire
+	got := feed(rsi, 100, 101, 102, 103, 90, 80, 70, 100, 130, 160)
+	expectSides(t, got, map[int]string{4: domain.SideBuy, 7: domain.SideSell})
+
+	// A period change restarts the EMAs
+	ema := strategy.NewEMACrossStrategy("BTC", 5, 10)
+	if err := ema.SetParam(strategy.ParamShortPeriod, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := ema.SetParam(strategy.ParamLongPeriod, 4); err != nil {
+		t.Fatal(err)
+	}
+	got = feed(ema, 100, 100, 100, 100, 200, 210, 50, 40)
+	expectSides(t, got, map[int]string{4: domain.SideBuy, 6: domain.SideSell})
+}
+
+func TestSetParam_Composites(t *testing.T) {
+	w := strategy.NewWatchlistStrategy(strategy.BollingerTemplate(20, 20_000), []string{"BTC", "ETH"}, nil)
+	if err := w.SetParam(strategy.ParamPeriod, 1); !errors.Is(err, strategy.ErrInvalidParam) {
+		t.Errorf("period 1: err = %v; want ErrInvalidParam", err)
+	}
+	if err := w.SetParam(strategy.ParamStdDevBps, 10_000); err != nil {
+		t.Error(err)
+	}
+
+	reg := strategy.NewStrategyRegistry()
+	if err := reg.Register("rsi", strategy.NewRSIStrategy("BTC", 14, 30, 70)); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register("manual", script()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SetParam("rsi.upper", 80); err != nil {
+		t.Error(err)
+	}
+	for _, key := range []string{"upper", "manual.upper", "sma.upper"} {
+		if err := reg.SetParam(key, 80); !errors.Is(err, strategy.ErrInvalidParam) {
+			t.Errorf("%s: err = %v; want ErrInvalidParam", key, err)
+		}
+	}
+}
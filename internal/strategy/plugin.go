@@ -0,0 +1,100 @@
+package strategy
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"plugin"
+	"slices"
+	"sync"
+)
+
+// Symbols a strategy plugin exports (package main, built with
+// go build -buildmode=plugin against this module):
+//
+//	var Type = "momentum"                                  // Type name used in the strategies list
+//	var Defaults = map[string]int64{"period": 10}          // Every parameter New accepts
+//	func New(symbol string, params map[string]int64) (strategy.Strategy, error)
+const (
+	PluginSymbolType     = "Type"
+	PluginSymbolDefaults = "Defaults"
+	PluginSymbolNew      = "New"
+)
+
+var (
+	pluginsMu sync.Mutex
+	plugins   = map[string]string{} // Loaded plugin path → type
+)
+
+// LoadPlugins opens every *.so in dir, in name order, and registers the
+// strategy type each one exports. A missing dir is not an error. Loading is
+// idempotent: a plugin already loaded is skipped. Go plugins only load on
+// Linux/macOS with cgo and must be built with the same Go toolchain and
+// module versions as the binary.
+func LoadPlugins(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	var loaded []string
+	for _, path := range paths {
+		if typ, ok := plugins[path]; ok {
+			loaded = append(loaded, typ)
+			continue
+		}
+		p, err := plugin.Open(path)
+		if err != nil {
+			return loaded, fmt.Errorf("strategy plugin %s: %w", path, err)
+		}
+		typ, err := registerPlugin(p.Lookup)
+		if err != nil {
+			return loaded, fmt.Errorf("strategy plugin %s: %w", path, err)
+		}
+		plugins[path] = typ
+		loaded = append(loaded, typ)
+		slog.Info("Strategy plugin loaded", slog.String("path", path), slog.String("type", typ))
+	}
+	return loaded, nil
+}
+
+// registerPlugin checks the exported symbols and registers the type.
+func registerPlugin(lookup func(string) (plugin.Symbol, error)) (string, error) {
+	sym, err := lookup(PluginSymbolType)
+	if err != nil {
+		return "", err
+	}
+	typ, ok := sym.(*string)
+	if !ok || *typ == "" {
+		return "", fmt.Errorf("%s must be a non-empty string variable", PluginSymbolType)
+	}
+
+	defaults := map[string]int64{}
+	if sym, err := lookup(PluginSymbolDefaults); err == nil {
+		d, ok := sym.(*map[string]int64)
+		if !ok {
+			return "", fmt.Errorf("%s must be a map[string]int64 variable", PluginSymbolDefaults)
+		}
+		defaults = *d
+	}
+
+	sym, err = lookup(PluginSymbolNew)
+	if err != nil {
+		return "", err
+	}
+	build, ok := sym.(func(string, map[string]int64) (Strategy, error))
+	if !ok {
+		return "", fmt.Errorf("%s must be func(symbol string, params map[string]int64) (strategy.Strategy, error), got %T", PluginSymbolNew, sym)
+	}
+	if err := registerType(*typ, defaults, build); err != nil {
+		return "", err
+	}
+	return *typ, nil
+}
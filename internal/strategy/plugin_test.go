@@ -0,0 +1,72 @@
+package strategy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"plugin"
+	"testing"
+
+	"crypto_go/internal/domain"
+)
+
+type noopStrategy struct{}
+
+func (noopStrategy) OnMarketUpdate(domain.MarketState, []domain.Order) int { return 0 }
+func (noopStrategy) OnOrderUpdate(domain.Order)                            {}
+
+// fakePlugin serves exported symbols like plugin.Plugin.Lookup.
+type fakePlugin map[string]plugin.Symbol
+
+func (f fakePlugin) Lookup(name string) (plugin.Symbol, error) {
+	if sym, ok := f[name]; ok {
+		return sym, nil
+	}
+	return nil, errors.New("symbol " + name + " not found")
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	typ := "plugin_test"
+	defaults := map[string]int64{"period": 3}
+	build := func(string, map[string]int64) (Strategy, error) { return noopStrategy{}, nil }
+	t.Cleanup(func() { delete(types, typ) })
+
+	p := fakePlugin{PluginSymbolType: &typ, PluginSymbolDefaults: &defaults, PluginSymbolNew: build}
+	if got, err := registerPlugin(p.Lookup); err != nil || got != typ {
+		t.Fatalf("registerPlugin = %q, %v", got, err)
+	}
+	if _, err := New(typ, "BTC", map[string]int64{"period": 5}); err != nil {
+		t.Errorf("New: %v", err)
+	}
+	if _, err := New(typ, "BTC", map[string]int64{"lower": 5}); !errors.Is(err, ErrInvalidParam) {
+		t.Errorf("unknown param: err = %v; want ErrInvalidParam", err)
+	}
+	if _, err := registerPlugin(p.Lookup); err == nil {
+		t.Error("expected error for a duplicate type")
+	}
+
+	other := "plugin_test_bad"
+	for name, bad := range map[string]fakePlugin{
+		"no New":       {PluginSymbolType: &other},
+		"wrong New":    {PluginSymbolType: &other, PluginSymbolNew: func(string) Strategy { return noopStrategy{} }},
+		"string Type":  {PluginSymbolType: other, PluginSymbolNew: build},
+		"bad Defaults": {PluginSymbolType: &other, PluginSymbolDefaults: &typ, PluginSymbolNew: build},
+	} {
+		if _, err := registerPlugin(bad.Lookup); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadPlugins(t *testing.T) {
+	if got, err := LoadPlugins(filepath.Join(t.TempDir(), "missing")); err != nil || len(got) != 0 {
+		t.Errorf("missing dir = %v, %v; want nothing", got, err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.so"), []byte("not a plugin"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPlugins(dir); err == nil {
+		t.Error("expected error for an invalid plugin file")
+	}
+}
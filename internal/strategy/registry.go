@@ -0,0 +1,277 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// registryBufSize bounds the orders one registered strategy may emit per call.
+const registryBufSize = 16
+
+// registered is one strategy of a StrategyRegistry.
+type registered struct {
+	name    string
+	strat   Strategy
+	symbols map[string]bool // nil = every symbol
+}
+
+// wants reports whether symbol is one the strategy trades.
+func (e *registered) wants(symbol string) bool {
+	return e.symbols == nil || e.symbols[symbol]
+}
+
+// StrategyRegistry runs several independent strategies (different symbols or
+// parameters) in one engine. Each is registered under a unique name with the
+// symbols it trades and only sees the market data, bars, books, trades,
+// signals and funding of those. Every order it emits is tagged with its name
+// (Order.Strategy); order updates and rejections go back to the owner, while
+// orders without one (operator, reduce-only flatten) reach every strategy.
+// Strategies are called in registration order, so a replay emits the same
+// orders. Not goroutine-safe (hotpath only); register before the engine runs.
+type StrategyRegistry struct {
+	entries []*registered
+	scratch [registryBufSize]domain.Order // Reused child output buffer (Zero-Alloc)
+	dropped uint64                        // Orders lost because out was full
+}
+
+// NewStrategyRegistry creates an empty registry.
+func NewStrategyRegistry() *StrategyRegistry {
+	return &StrategyRegistry{}
+}
+
+// Register adds strat under name, trading symbols (none = every symbol).
+func (r *StrategyRegistry) Register(name string, strat Strategy, symbols ...string) error {
+	if name == "" || strat == nil {
+		return fmt.Errorf("strategy registry: need a name and a strategy")
+	}
+	if r.lookup(name) != nil {
+		return fmt.Errorf("strategy registry: duplicate strategy %q", name)
+	}
+	e := &registered{name: name, strat: strat}
+	if len(symbols) > 0 {
+		e.symbols = make(map[string]bool, len(symbols))
+		for _, s := range symbols {
+			e.symbols[s] = true
+		}
+	}
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+// Names returns the registered strategy names in registration order.
+func (r *StrategyRegistry) Names() []string {
+	names := make([]string, len(r.entries))
+	for i, e := range r.entries {
+		names[i] = e.name
+	}
+	return names
+}
+
+// Lookup returns the strategy registered under name.
+func (r *StrategyRegistry) Lookup(name string) (Strategy, bool) {
+	if e := r.lookup(name); e != nil {
+		return e.strat, true
+	}
+	return nil, false
+}
+
+// Dropped returns how many emitted orders did not fit the engine's buffer.
+func (r *StrategyRegistry) Dropped() uint64 {
+	return r.dropped
+}
+
+func (r *StrategyRegistry) lookup(name string) *registered {
+	for _, e := range r.entries {
+		if e.name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// collect appends the count orders e wrote to r.scratch to out[n:], tagged
+// with e's name, and returns the new length of out.
+func (r *StrategyRegistry) collect(e *registered, count int, out []domain.Order, n int) int {
+	for i := 0; i < count; i++ {
+		if n == len(out) {
+			r.dropped += uint64(count - i)
+			slog.Warn("STRATEGY_ORDERS_DROPPED", slog.String("strategy", e.name), slog.Int("dropped", count-i))
+			return n
+		}
+		out[n] = r.scratch[i]
+		out[n].Strategy = e.name
+		n++
+	}
+	return n
+}
+
+// OnMarketUpdate feeds state to every strategy trading its symbol.
+func (r *StrategyRegistry) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	n := 0
+	for _, e := range r.entries {
+		if e.wants(state.Symbol) {
+			n = r.collect(e, e.strat.OnMarketUpdate(state, r.scratch[:]), out, n)
+		}
+	}
+	return n
+}
+
+// OnOrderUpdate forwards the update to the strategy that owns the order, or
+// to every strategy for an order none of them emitted.
+func (r *StrategyRegistry) OnOrderUpdate(order domain.Order) {
+	if e := r.lookup(order.Strategy); e != nil {
+		e.strat.OnOrderUpdate(order)
+		return
+	}
+	for _, e := range r.entries {
+		e.strat.OnOrderUpdate(order)
+	}
+}
+
+// OnOrderRejected forwards the rejection like OnOrderUpdate, to strategies
+// that handle them.
+func (r *StrategyRegistry) OnOrderRejected(rej domain.OrderRejection) {
+	if e := r.lookup(rej.Order.Strategy); e != nil {
+		if h, ok := e.strat.(RejectionHandler); ok {
+			h.OnOrderRejected(rej)
+		}
+		return
+	}
+	for _, e := range r.entries {
+		if h, ok := e.strat.(RejectionHandler); ok {
+			h.OnOrderRejected(rej)
+		}
+	}
+}
+
+// OnOrderBookUpdate forwards the book to depth-aware strategies trading its symbol.
+func (r *StrategyRegistry) OnOrderBookUpdate(book *domain.OrderBook) {
+	for _, e := range r.entries {
+		if h, ok := e.strat.(OrderBookHandler); ok && e.wants(book.Symbol) {
+			h.OnOrderBookUpdate(book)
+		}
+	}
+}
+
+// OnVenueUpdate forwards the venue's update to venue-aware strategies trading its symbol.
+func (r *StrategyRegistry) OnVenueUpdate(exchange string, state domain.MarketState) {
+	for _, e := range r.entries {
+		if h, ok := e.strat.(VenueHandler); ok && e.wants(state.Symbol) {
+			h.OnVenueUpdate(exchange, state)
+		}
+	}
+}
+
+// OnTrade forwards the trade to tape-aware strategies trading its symbol.
+func (r *StrategyRegistry) OnTrade(trade domain.Trade) {
+	for _, e := range r.entries {
+		if h, ok := e.strat.(TradeHandler); ok && e.wants(trade.Symbol) {
+			h.OnTrade(trade)
+		}
+	}
+}
+
+// OnCandleClose feeds the bar to bar-based strategies trading its symbol.
+func (r *StrategyRegistry) OnCandleClose(candle domain.Candle, out []domain.Order) int {
+	n := 0
+	for _, e := range r.entries {
+		if h, ok := e.strat.(CandleHandler); ok && e.wants(candle.Symbol) {
+			n = r.collect(e, h.OnCandleClose(candle, r.scratch[:]), out, n)
+		}
+	}
+	return n
+}
+
+// OnSignal forwards an external signal to strategies trading its symbol.
+func (r *StrategyRegistry) OnSignal(signal domain.Signal, out []domain.Order) int {
+	n := 0
+	for _, e := range r.entries {
+		if h, ok := e.strat.(SignalHandler); ok && e.wants(signal.Symbol) {
+			n = r.collect(e, h.OnSignal(signal, r.scratch[:]), out, n)
+		}
+	}
+	return n
+}
+
+// OnFundingSoon forwards the epoch to funding-sensitive strategies trading its symbol.
+func (r *StrategyRegistry) OnFundingSoon(epoch domain.FundingEpoch, out []domain.Order) int {
+	n := 0
+	for _, e := range r.entries {
+		if h, ok := e.strat.(FundingHandler); ok && e.wants(epoch.Symbol) {
+			n = r.collect(e, h.OnFundingSoon(epoch, r.scratch[:]), out, n)
+		}
+	}
+	return n
+}
+
+// OnFundingRate forwards the epoch to funding-rate strategies trading its symbol.
+func (r *StrategyRegistry) OnFundingRate(epoch domain.FundingEpoch, out []domain.Order) int {
+	n := 0
+	for _, e := range r.entries {
+		if h, ok := e.strat.(FundingRateHandler); ok && e.wants(epoch.Symbol) {
+			n = r.collect(e, h.OnFundingRate(epoch, r.scratch[:]), out, n)
+		}
+	}
+	return n
+}
+
+// OnTimer forwards the tick to every timer-driven strategy.
+func (r *StrategyRegistry) OnTimer(timer domain.Timer, out []domain.Order) int {
+	n := 0
+	for _, e := range r.entries {
+		if h, ok := e.strat.(TimerHandler); ok {
+			n = r.collect(e, h.OnTimer(timer, r.scratch[:]), out, n)
+		}
+	}
+	return n
+}
+
+// OnNotice forwards the notice to strategies trading a symbol it names, or to
+// all of them for a notice naming none.
+func (r *StrategyRegistry) OnNotice(notice domain.Notice) {
+	for _, e := range r.entries {
+		h, ok := e.strat.(NoticeHandler)
+		if ok && (len(notice.Symbols) == 0 || slices.ContainsFunc(notice.Symbols, e.wants)) {
+			h.OnNotice(notice)
+		}
+	}
+}
+
+// OnContext forwards the reading to every strategy that uses context metrics.
+func (r *StrategyRegistry) OnContext(metric domain.ContextMetric) {
+	for _, e := range r.entries {
+		if h, ok := e.strat.(ContextHandler); ok {
+			h.OnContext(metric)
+		}
+	}
+}
+
+// OnSentiment forwards the reading to every sentiment-aware strategy.
+func (r *StrategyRegistry) OnSentiment(sentiment domain.Sentiment) {
+	for _, e := range r.entries {
+		if h, ok := e.strat.(SentimentHandler); ok {
+			h.OnSentiment(sentiment)
+		}
+	}
+}
+
+// SetBalances hands the engine balance book to every balance-aware strategy.
+func (r *StrategyRegistry) SetBalances(book *domain.BalanceBook) {
+	for _, e := range r.entries {
+		if a, ok := e.strat.(BalanceAware); ok {
+			a.SetBalances(book)
+		}
+	}
+}
+
+// SetClock hands the engine clock to every clock-aware strategy.
+func (r *StrategyRegistry) SetClock(clock quant.Clock) {
+	for _, e := range r.entries {
+		if a, ok := e.strat.(ClockAware); ok {
+			a.SetClock(clock)
+		}
+	}
+}
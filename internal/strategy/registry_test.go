@@ -0,0 +1,60 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"testing"
+)
+
+// orderRecorder is a scripted strategy that remembers its order updates.
+type orderRecorder struct {
+	scriptedStrategy
+	updates []string
+}
+
+func (r *orderRecorder) OnOrderUpdate(o domain.Order) { r.updates = append(r.updates, o.ID) }
+
+func TestStrategyRegistry_RoutesBySymbol(t *testing.T) {
+	btc := &orderRecorder{scriptedStrategy: *script("BUY")}
+	all := &orderRecorder{scriptedStrategy: *script("SELL")}
+	reg := strategy.NewStrategyRegistry()
+	if err := reg.Register("btc", btc, "BTC"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register("all", all); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register("btc", all); err == nil {
+		t.Error("a duplicate name must be refused")
+	}
+
+	out := make([]domain.Order, 4)
+	n := reg.OnMarketUpdate(domain.MarketState{Symbol: "BTC"}, out)
+	if n != 2 || out[0].Strategy != "btc" || out[0].Side != domain.SideBuy || out[1].Strategy != "all" {
+		t.Fatalf("BTC update: %d %+v; want btc's BUY then all's SELL", n, out[:n])
+	}
+	n = reg.OnMarketUpdate(domain.MarketState{Symbol: "ETH"}, out)
+	if n != 1 || out[0].Strategy != "all" || btc.i != 1 {
+		t.Errorf("ETH update: %d %+v; btc must not see it", n, out[:n])
+	}
+
+	reg.OnOrderUpdate(domain.Order{ID: "o1", Strategy: "btc"})
+	reg.OnOrderUpdate(domain.Order{ID: "manual"})
+	if len(btc.updates) != 2 || len(all.updates) != 1 || all.updates[0] != "manual" {
+		t.Errorf("updates btc=%v all=%v; want o1 to btc only, manual to both", btc.updates, all.updates)
+	}
+}
+
+func TestStrategyRegistry_FullBuffer(t *testing.T) {
+	reg := strategy.NewStrategyRegistry()
+	reg.Register("a", script("BUY"))
+	reg.Register("b", script("SELL"))
+
+	out := make([]domain.Order, 1)
+	if n := reg.OnMarketUpdate(domain.MarketState{Symbol: "BTC"}, out); n != 1 || out[0].Strategy != "a" {
+		t.Fatalf("got %d %+v; want a's order only", n, out[0])
+	}
+	if reg.Dropped() != 1 {
+		t.Errorf("dropped = %d; want 1", reg.Dropped())
+	}
+}
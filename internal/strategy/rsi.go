@@ -0,0 +1,67 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/quant/indicators"
+)
+
+// RSIStrategy trades Wilder's Relative Strength Index against two
+// thresholds: it buys when the RSI falls below lower (oversold) and sells
+// when it rises above upper (overbought), once per crossing.
+type RSIStrategy struct {
+	rsi     *indicators.RSI
+	prevRSI int64 // Basis points (0..10000); -1 = none yet
+	lower   int64 // Basis points
+	upper   int64 // Basis points
+	symbol  string
+}
+
+// NewRSIStrategy creates a strategy over period price changes; lower and
+// upper are RSI levels in points (e.g. 30 and 70).
+func NewRSIStrategy(symbol string, period int, lower, upper int64) *RSIStrategy {
+	if period <= 0 || lower <= 0 || lower >= upper || upper >= 100 {
+		panic("RSIStrategy: need period > 0 and 0 < lower < upper < 100")
+	}
+	return &RSIStrategy{
+		rsi:     indicators.NewRSI(period),
+		prevRSI: -1,
+		lower:   lower * 100,
+		upper:   upper * 100,
+		symbol:  symbol,
+	}
+}
+
+// OnMarketUpdate updates the RSI and emits when it crosses a threshold.
+// Zero-Alloc.
+func (s *RSIStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol != s.symbol {
+		return 0
+	}
+	rsi := s.rsi.Update(state.PriceMicros)
+	if !s.rsi.Ready() {
+		return 0
+	}
+	prev := s.prevRSI
+	s.prevRSI = rsi
+	if prev < 0 {
+		return 0
+	}
+	price := int64(state.PriceMicros)
+	switch {
+	case prev >= s.lower && rsi < s.lower:
+		return emit(out, 0, s.symbol, domain.SideBuy, price)
+	case prev <= s.upper && rsi > s.upper:
+		return emit(out, 0, s.symbol, domain.SideSell, price)
+	}
+	return 0
+}
+
+// OnOrderUpdate handles order updates (Empty for now)
+func (s *RSIStrategy) OnOrderUpdate(domain.Order) {}
+
+// RSITemplate returns a Template producing RSI strategies with the given period and levels.
+func RSITemplate(period int, lower, upper int64) Template {
+	return func(symbol string) Strategy {
+		return NewRSIStrategy(symbol, period, lower, upper)
+	}
+}
@@ -0,0 +1,411 @@
+// Package script runs strategies written as small line-based scripts instead
+// of Go code, for quick prototyping:
+//
+//	# RSI mean reversion
+//	param period = 14
+//	let r = rsi(period)
+//	let trend = sma(50)
+//	when r < 30 && price > trend: emit_action(BUY, 0.001)
+//	when r > 70: emit_action(SELL, 0.001)
+//
+// param declares an integer parameter (overridable from config params and
+// SET_STRATEGY_PARAM). let binds an indicator over the symbol's price: sma,
+// ema and stddev in price units, rsi in points (0-100); its argument is a
+// period literal or param. when takes a condition (pkg/expr) over price, qty
+// (total quantity in coins), bid and ask (best levels of the symbol's last
+// order book update), funding (predicted funding rate in percent), funding_in
+// (seconds to the next funding), premium (kimchi premium in percent, Upbit vs
+// Bitget spot), the params, the indicators and prev.<name>, the value at the
+// previous tick, and emits a MARKET order when the condition starts to hold.
+// Rules are evaluated on market updates only. An indicator is unknown until
+// its window is full, the other inputs until they were seen, and a condition
+// reading an unknown value does not hold.
+//
+// Scripts are sandboxed by construction: no loops, no I/O, no state other
+// than the declared indicators, and every tick costs one evaluation per rule.
+package script
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"crypto_go/internal/analytics"
+	"crypto_go/internal/domain"
+	"crypto_go/internal/event"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/expr"
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/quant/indicators"
+)
+
+// ErrInvalid is wrapped by the errors of Parse.
+var ErrInvalid = errors.New("invalid strategy script")
+
+// Built-in variables.
+const (
+	varPrice     = "price"
+	varQty       = "qty"
+	varBid       = "bid"
+	varAsk       = "ask"
+	varFunding   = "funding"
+	varFundingIn = "funding_in"
+	varPremium   = "premium"
+	prefix       = "prev."
+)
+
+// latched are the built-ins read from other events than market updates; they
+// are taken over at the next market update so prev.<name> stays per tick.
+var latched = []struct{ name, prev string }{
+	{varBid, prefix + varBid},
+	{varAsk, prefix + varAsk},
+	{varFunding, prefix + varFunding},
+	{varPremium, prefix + varPremium},
+}
+
+var (
+	identRe = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	paramRe = regexp.MustCompile(`^param\s+(\S+)\s*=\s*(-?[0-9]+)$`)
+	letRe   = regexp.MustCompile(`^let\s+(\S+)\s*=\s*([a-z]+)\(\s*([a-z0-9_]+)\s*\)$`)
+	emitRe  = regexp.MustCompile(`(?i)^emit_action\(\s*(BUY|SELL)\s*,\s*([0-9]*\.?[0-9]+)\s*\)$`)
+)
+
+// maxParam bounds param values so they stay in range as six-decimal values.
+const maxParam = 1e12
+
+// minPeriods are the indicator functions and their smallest window.
+var minPeriods = map[string]int64{"sma": 1, "ema": 1, "rsi": 1, "stddev": 2}
+
+type param struct {
+	name  string
+	value int64
+}
+
+type binding struct {
+	name, fn string
+	arg      string // Param name or period literal
+}
+
+type rule struct {
+	when *expr.Expr
+	side string
+	qty  quant.QtySats
+}
+
+// Program is a parsed script. Build instances with New.
+type Program struct {
+	name   string
+	params []param
+	lets   []binding
+	rules  []rule
+}
+
+// Load parses the script file at path; its base name (without extension)
+// names the program.
+func Load(path string) (*Program, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), string(src))
+}
+
+// Parse parses script source src.
+func Parse(name, src string) (*Program, error) {
+	p := &Program{name: name}
+	declared := map[string]bool{varPrice: true, varQty: true, varFundingIn: true}
+	for _, l := range latched {
+		declared[l.name] = true
+	}
+	for i, line := range strings.Split(src, "\n") {
+		if j := strings.IndexByte(line, '#'); j >= 0 {
+			line = line[:j]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("%w: %s:%d: %s", ErrInvalid, name, i+1, fmt.Sprintf(format, args...))
+		}
+		declare := func(ident string) error {
+			if !identRe.MatchString(ident) {
+				return fail("invalid name %q", ident)
+			}
+			if declared[ident] || ident == "prev" {
+				return fail("%q is already defined", ident)
+			}
+			declared[ident] = true
+			return nil
+		}
+
+		switch keyword := strings.Fields(line)[0]; keyword {
+		case "param":
+			m := paramRe.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fail("want: param <name> = <integer>")
+			}
+			if err := declare(m[1]); err != nil {
+				return nil, err
+			}
+			value, err := strconv.ParseInt(m[2], 10, 64)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			p.params = append(p.params, param{name: m[1], value: value})
+		case "let":
+			m := letRe.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fail("want: let <name> = <sma|ema|rsi|stddev>(<period>)")
+			}
+			if _, ok := minPeriods[m[2]]; !ok {
+				return nil, fail("unknown indicator %q (want sma, ema, rsi or stddev)", m[2])
+			}
+			if _, err := strconv.ParseInt(m[3], 10, 64); err != nil && !slices.ContainsFunc(p.params, func(pr param) bool { return pr.name == m[3] }) {
+				return nil, fail("period %q is neither a number nor a param", m[3])
+			}
+			if err := declare(m[1]); err != nil {
+				return nil, err
+			}
+			p.lets = append(p.lets, binding{name: m[1], fn: m[2], arg: m[3]})
+		case "when":
+			cond, action, ok := cut(strings.TrimPrefix(line, "when"))
+			if !ok {
+				return nil, fail("want: when <condition>: emit_action(BUY|SELL, <qty>)")
+			}
+			m := emitRe.FindStringSubmatch(action)
+			if m == nil {
+				return nil, fail("want emit_action(BUY|SELL, <qty>), got %q", action)
+			}
+			qty := quant.ToQtySatsStr(m[2])
+			if qty <= 0 {
+				return nil, fail("quantity must be positive")
+			}
+			when, err := expr.Compile(cond)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			for _, v := range when.Vars() {
+				if !declared[strings.TrimPrefix(v, prefix)] {
+					return nil, fail("unknown variable %q", v)
+				}
+			}
+			p.rules = append(p.rules, rule{when: when, side: strings.ToUpper(m[1]), qty: qty})
+		default:
+			return nil, fail("unknown statement %q (want param, let or when)", keyword)
+		}
+	}
+	if len(p.rules) == 0 {
+		return nil, fmt.Errorf("%w: %s has no when rule", ErrInvalid, name)
+	}
+	return p, nil
+}
+
+// cut splits a when line at the colon before its action.
+func cut(s string) (cond, action string, ok bool) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), true
+}
+
+// Name returns the program name.
+func (p *Program) Name() string {
+	return p.name
+}
+
+// Params returns the declared params and their default values.
+func (p *Program) Params() map[string]int64 {
+	params := make(map[string]int64, len(p.params))
+	for _, pr := range p.params {
+		params[pr.name] = pr.value
+	}
+	return params
+}
+
+// indicator is a bound let: update feeds a price and returns the value in
+// expression units and whether the window is full.
+type indicator struct {
+	name, prev string
+	update     func(quant.PriceMicros) (int64, bool)
+}
+
+// Strategy runs one program instance on one symbol.
+type Strategy struct {
+	prog    *Program
+	symbol  string
+	params  map[string]int64
+	inds    []indicator
+	vars    expr.Map
+	latest  expr.Map // Last bid, ask, funding and premium (absent = unknown)
+	premium *analytics.PremiumTracker
+	holding []bool // Per rule: the condition held and its order was emitted
+}
+
+// New builds an instance on symbol. params override the declared defaults;
+// an unknown key or an invalid period wraps strategy.ErrInvalidParam.
+func (p *Program) New(symbol string, params map[string]int64) (*Strategy, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("script %s: symbol is required", p.name)
+	}
+	merged := p.Params()
+	for key, value := range params {
+		if _, ok := merged[key]; !ok {
+			return nil, fmt.Errorf("%w: script %s has no parameter %q", strategy.ErrInvalidParam, p.name, key)
+		}
+		merged[key] = value
+	}
+
+	s := &Strategy{prog: p, symbol: symbol, params: merged, vars: make(expr.Map), latest: make(expr.Map),
+		premium: analytics.NewPremiumTracker(0, 0), holding: make([]bool, len(p.rules))}
+	for key, value := range merged {
+		if value > maxParam || value < -maxParam {
+			return nil, fmt.Errorf("%w: script %s: %s must be within ±%d", strategy.ErrInvalidParam, p.name, key, int64(maxParam))
+		}
+		s.vars[key] = value * quant.PriceScale
+	}
+	for _, b := range p.lets {
+		period, ok := merged[b.arg]
+		if !ok {
+			period, _ = strconv.ParseInt(b.arg, 10, 64)
+		}
+		if period < minPeriods[b.fn] || period > 1<<20 {
+			return nil, fmt.Errorf("%w: script %s: %s(%s) needs a period of at least %d (got %d)", strategy.ErrInvalidParam, p.name, b.fn, b.arg, minPeriods[b.fn], period)
+		}
+		s.inds = append(s.inds, indicator{name: b.name, prev: prefix + b.name, update: newIndicator(b.fn, int(period))})
+	}
+	return s, nil
+}
+
+func newIndicator(fn string, period int) func(quant.PriceMicros) (int64, bool) {
+	switch fn {
+	case "sma":
+		ind := indicators.NewSMA(period)
+		return func(p quant.PriceMicros) (int64, bool) { return int64(ind.Update(p)), ind.Ready() }
+	case "ema":
+		ind := indicators.NewEMA(period)
+		return func(p quant.PriceMicros) (int64, bool) { return int64(ind.Update(p)), ind.Ready() }
+	case "rsi":
+		ind := indicators.NewRSI(period)
+		return func(p quant.PriceMicros) (int64, bool) { return ind.Update(p) * 10_000, ind.Ready() } // bps → points with six decimals
+	default: // stddev
+		ind := indicators.NewStdDev(period)
+		return func(p quant.PriceMicros) (int64, bool) { return int64(ind.Update(p)), ind.Ready() }
+	}
+}
+
+// OnMarketUpdate updates the variables and emits the orders of the rules
+// whose condition started to hold.
+func (s *Strategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	if state.Symbol != s.symbol {
+		return 0
+	}
+	s.set(varPrice, prefix+varPrice, int64(state.PriceMicros), true)
+	s.set(varQty, prefix+varQty, int64(state.TotalQtySats)/100, true) // Sats → coins with six decimals
+	// µs → seconds with six decimals
+	s.set(varFundingIn, prefix+varFundingIn, int64(state.FundingInMicros), state.NextFundingUnixM != 0)
+	for _, l := range latched {
+		value, ok := s.latest[l.name]
+		s.set(l.name, l.prev, value, ok)
+	}
+	for _, ind := range s.inds {
+		value, ready := ind.update(state.PriceMicros)
+		s.set(ind.name, ind.prev, value, ready)
+	}
+
+	n := 0
+	for i, r := range s.prog.rules {
+		holds := r.when.Eval(s.vars)
+		if !holds {
+			s.holding[i] = false
+			continue
+		}
+		// A condition whose order did not fit out stays armed for the next tick
+		if !s.holding[i] && n < len(out) {
+			out[n] = domain.Order{
+				Symbol:      s.symbol,
+				Side:        r.side,
+				Type:        domain.OrderTypeMarket,
+				PriceMicros: int64(state.PriceMicros),
+				QtySats:     int64(r.qty),
+				Status:      "NEW",
+			}
+			n++
+			s.holding[i] = true
+		}
+	}
+	return n
+}
+
+// OnOrderBookUpdate takes the best bid and ask of the symbol's book.
+func (s *Strategy) OnOrderBookUpdate(book *domain.OrderBook) {
+	if book.Symbol != s.symbol {
+		return
+	}
+	s.latch(varBid, book.BestBid)
+	s.latch(varAsk, book.BestAsk)
+}
+
+func (s *Strategy) latch(name string, best func() (domain.BookLevel, bool)) {
+	if level, ok := best(); ok {
+		s.latest[name] = int64(level.PriceMicros)
+	} else {
+		delete(s.latest, name)
+	}
+}
+
+// OnFundingRate takes the predicted funding rate of the symbol. It emits
+// nothing: rules run on market updates.
+func (s *Strategy) OnFundingRate(epoch domain.FundingEpoch, _ []domain.Order) int {
+	if epoch.Symbol == s.symbol {
+		s.latest[varFunding] = epoch.RateMicros * 100 // Rate → percent with six decimals
+	}
+	return 0
+}
+
+// OnVenueUpdate feeds the premium tracker with the venue prices and FX rates.
+func (s *Strategy) OnVenueUpdate(exchange string, state domain.MarketState) {
+	e := event.MarketUpdateEvent{Exchange: exchange, Symbol: state.Symbol, PriceMicros: state.PriceMicros}
+	e.Ts = state.LastUpdateUnixM
+	if sample, ok := s.premium.Observe(&e); ok && sample.Symbol == s.symbol {
+		s.latest[varPremium] = sample.PremiumMicros * 100 // 1% = 10,000 → percent with six decimals
+	}
+}
+
+// set moves the current value of name to prev and stores value (unknown
+// unless ok).
+func (s *Strategy) set(name, prev string, value int64, ok bool) {
+	if cur, had := s.vars[name]; had {
+		s.vars[prev] = cur
+	} else {
+		delete(s.vars, prev)
+	}
+	if ok {
+		s.vars[name] = value
+	} else {
+		delete(s.vars, name)
+	}
+}
+
+// OnOrderUpdate handles order updates (Empty for now)
+func (s *Strategy) OnOrderUpdate(domain.Order) {}
+
+// SetParam changes a declared param and restarts the indicators.
+func (s *Strategy) SetParam(key string, value int64) error {
+	params := maps.Clone(s.params)
+	params[key] = value
+	next, err := s.prog.New(s.symbol, params)
+	if err != nil {
+		return err
+	}
+	next.latest, next.premium = s.latest, s.premium // Market inputs do not depend on params
+	*s = *next
+	return nil
+}
@@ -0,0 +1,173 @@
+package script
+
+import (
+	"errors"
+	"testing"
+
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+)
+
+const crossScript = `
+# SMA cross with a floor
+param short = 2
+param long = 4
+let fast = sma(short)
+let slow = sma(long)
+when fast > slow && price > 50: emit_action(BUY, 0.0001)
+when fast < slow: emit_action(sell, 0.0001)   # case-insensitive side
+`
+
+func feed(t *testing.T, s *Strategy, prices ...int64) map[int]string {
+	t.Helper()
+	sides := make(map[int]string)
+	out := make([]domain.Order, 4)
+	for i, p := range prices {
+		s.OnMarketUpdate(domain.MarketState{Symbol: "ETH", PriceMicros: quant.PriceMicros(p * quant.PriceScale)}, out)
+		if n := s.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: quant.PriceMicros(p * quant.PriceScale)}, out); n > 0 {
+			sides[i] = out[0].Side
+			if out[0].QtySats != 10_000 || out[0].Type != domain.OrderTypeMarket {
+				t.Fatalf("unexpected order %+v", out[0])
+			}
+		}
+	}
+	return sides
+}
+
+func TestScript(t *testing.T) {
+	prog, err := Parse("cross", crossScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := prog.New("BTC", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The rules fire when their condition starts to hold, once per crossing
+	got := feed(t, s, 100, 100, 100, 100, 200, 210, 220, 50, 40, 30)
+	if len(got) != 2 || got[4] != domain.SideBuy || got[7] != domain.SideSell {
+		t.Errorf("unexpected orders %v", got)
+	}
+
+	// SetParam rebuilds the indicators
+	if err := s.SetParam("long", 3); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]int64{"long": 0, "lower": 5} {
+		if err := s.SetParam(key, value); !errors.Is(err, strategy.ErrInvalidParam) {
+			t.Errorf("%s=%d: err = %v; want ErrInvalidParam", key, value, err)
+		}
+	}
+	if s.params["long"] != 3 {
+		t.Errorf("a refused change must keep the params, got %v", s.params)
+	}
+}
+
+func TestScript_PrevAndRSI(t *testing.T) {
+	prog, err := Parse("dip", `
+let r = rsi(2)
+when price < prev.price - 10 && r < 50: emit_action(BUY, 0.0001)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := prog.New("BTC", nil)
+	if got := feed(t, s, 100, 105, 110, 90, 95, 70); len(got) != 2 || got[3] != domain.SideBuy || got[5] != domain.SideBuy {
+		t.Errorf("unexpected orders %v", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, src := range []string{
+		"",             // no rule
+		"param x = 1",  // no rule
+		"print(price)", // unknown statement
+		"let a = macd(3)\nwhen a > 1: emit_action(BUY, 1)", // unknown indicator
+		"let a = sma(n)\nwhen a > 1: emit_action(BUY, 1)",  // undeclared period param
+		"param price = 1\nwhen price > 1: emit_action(BUY, 1)",
+		"when volume > 1: emit_action(BUY, 1)", // unknown variable
+		"when prev > 1: emit_action(BUY, 1)",   // prev alone
+		"when price > 1: emit_action(HOLD, 1)", // bad side
+		"when price > 1: emit_action(BUY, 0)",  // zero qty
+		"when price >: emit_action(BUY, 1)",    // bad condition
+		"when price > 1 emit_action(BUY, 1)",   // no colon
+	} {
+		if _, err := Parse("bad", src); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%q: err = %v; want ErrInvalid", src, err)
+		}
+	}
+
+	prog, err := Parse("p", "param n = 1\nlet s = stddev(n)\nwhen s > 1: emit_action(BUY, 1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prog.New("BTC", nil); !errors.Is(err, strategy.ErrInvalidParam) {
+		t.Errorf("stddev(1): err = %v; want ErrInvalidParam", err)
+	}
+	if _, err := prog.New("BTC", map[string]int64{"n": 2e12}); !errors.Is(err, strategy.ErrInvalidParam) {
+		t.Errorf("huge param: err = %v; want ErrInvalidParam", err)
+	}
+}
+
+func TestScript_FullBufferKeepsSignal(t *testing.T) {
+	prog, err := Parse("up", "when price > 10: emit_action(BUY, 0.0001)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := prog.New("BTC", nil)
+	state := domain.MarketState{Symbol: "BTC", PriceMicros: 20 * quant.PriceScale}
+	if n := s.OnMarketUpdate(state, nil); n != 0 {
+		t.Fatalf("emitted %d orders into a full buffer", n)
+	}
+	out := make([]domain.Order, 1)
+	if n := s.OnMarketUpdate(state, out); n != 1 {
+		t.Fatal("a signal a full buffer dropped must fire at the next tick")
+	}
+	if n := s.OnMarketUpdate(state, out); n != 0 {
+		t.Error("an emitted signal must not repeat while the condition holds")
+	}
+}
+
+func TestScript_BookFundingPremium(t *testing.T) {
+	prog, err := Parse("inputs", `
+when ask - bid < 2 && funding > 0.01 && funding_in < 600: emit_action(BUY, 0.0001)
+when premium > 5 && prev.premium < 5: emit_action(SELL, 0.0001)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := prog.New("BTC", nil)
+	out := make([]domain.Order, 2)
+	tick := func(ts int64) int {
+		return s.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: 100 * quant.PriceScale,
+			LastUpdateUnixM: quant.TimeStamp(ts), NextFundingUnixM: 1, FundingInMicros: 300 * quant.PriceScale}, out)
+	}
+	venue := func(exchange, symbol string, price, ts int64) {
+		s.OnVenueUpdate(exchange, domain.MarketState{Symbol: symbol, PriceMicros: quant.PriceMicros(price * quant.PriceScale), LastUpdateUnixM: quant.TimeStamp(ts)})
+	}
+
+	if n := tick(1); n != 0 {
+		t.Fatal("unknown inputs must not hold")
+	}
+	book := domain.NewOrderBook("BITGET_SPOT", "BTC")
+	book.Apply(true, []domain.BookLevel{{PriceMicros: 99 * quant.PriceScale, QtySats: 1}}, []domain.BookLevel{{PriceMicros: 100 * quant.PriceScale, QtySats: 1}}, 2)
+	s.OnOrderBookUpdate(book)
+	s.OnFundingRate(domain.FundingEpoch{Symbol: "ETH", RateMicros: 1_000}, nil)
+	if n := tick(3); n != 0 {
+		t.Fatal("funding of another symbol must not count")
+	}
+	s.OnFundingRate(domain.FundingEpoch{Symbol: "BTC", RateMicros: 200}, nil) // 0.02%
+	if n := tick(4); n != 1 || out[0].Side != domain.SideBuy {
+		t.Fatalf("got %d orders; want the BUY on a tight book and high funding", n)
+	}
+
+	venue("FX", "USD/KRW", 1_400, 5)
+	venue("BITGET_SPOT", "BTC", 70_000, 5)
+	venue("UPBIT", "BTC", 97_020_000, 5) // -1%
+	tick(6)
+	venue("UPBIT", "BTC", 103_000_000, 7) // ~5.1%
+	if n := tick(8); n != 1 || out[0].Side != domain.SideSell {
+		t.Fatalf("got %d orders; want the SELL on the premium crossing 5%%", n)
+	}
+}
@@ -0,0 +1,142 @@
+package strategy
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/pkg/safe"
+	"sort"
+)
+
+// Template builds one strategy instance bound to symbol.
+type Template func(symbol string) Strategy
+
+// SMACrossTemplate returns a Template producing SMA cross strategies with the given periods.
+func SMACrossTemplate(shortPeriod, longPeriod int) Template {
+	return func(symbol string) Strategy {
+		return NewSMACrossStrategy(symbol, shortPeriod, longPeriod)
+	}
+}
+
+// RiskBudget caps the total notional (price * qty, quote currency micros) that all
+// instances of a watchlist may hold at once. BUY signals reserve notional, SELL
+// signals release what their symbol reserved. Not goroutine-safe (hotpath only).
+type RiskBudget struct {
+	maxNotional int64 // 0 = unlimited
+	used        int64
+	bySymbol    map[string]int64
+}
+
+// NewRiskBudget creates a budget of maxNotional micros (0 = unlimited).
+func NewRiskBudget(maxNotional int64) *RiskBudget {
+	return &RiskBudget{maxNotional: maxNotional, bySymbol: make(map[string]int64)}
+}
+
+// Used returns the currently reserved notional.
+func (b *RiskBudget) Used() int64 {
+	return b.used
+}
+
+// Reserved returns the notional held by symbol.
+func (b *RiskBudget) Reserved(symbol string) int64 {
+	return b.bySymbol[symbol]
+}
+
+// Allow applies order to the budget. Returns false (and changes nothing)
+// when a BUY would exceed the limit.
+func (b *RiskBudget) Allow(order *domain.Order) bool {
+	notional := safe.SafeDiv(safe.SafeMul(order.PriceMicros, order.QtySats), 100_000_000)
+
+	switch order.Side {
+	case domain.SideBuy:
+		next := safe.SafeAdd(b.used, notional)
+		if b.maxNotional > 0 && next > b.maxNotional {
+			return false
+		}
+		b.used = next
+		b.bySymbol[order.Symbol] = safe.SafeAdd(b.bySymbol[order.Symbol], notional)
+	case domain.SideSell:
+		held := b.bySymbol[order.Symbol]
+		released := notional
+		if released > held {
+			released = held // Never release another symbol's reservation
+		}
+		b.used = safe.SafeSub(b.used, released)
+		b.bySymbol[order.Symbol] = safe.SafeSub(held, released)
+	}
+	return true
+}
+
+// WatchlistStrategy runs one instance of a Template per watched symbol and
+// routes each market update to the instance for its symbol. All instances
+// share a single RiskBudget, so adding symbols never multiplies exposure.
+type WatchlistStrategy struct {
+	instances map[string]Strategy
+	budget    *RiskBudget
+}
+
+// NewWatchlistStrategy instantiates tmpl for every symbol (duplicates are ignored).
+// budget may be nil for no shared limit.
+func NewWatchlistStrategy(tmpl Template, symbols []string, budget *RiskBudget) *WatchlistStrategy {
+	w := &WatchlistStrategy{
+		instances: make(map[string]Strategy, len(symbols)),
+		budget:    budget,
+	}
+	for _, symbol := range symbols {
+		if _, ok := w.instances[symbol]; !ok {
+			w.instances[symbol] = tmpl(symbol)
+		}
+	}
+	return w
+}
+
+// Symbols returns the watched symbols in sorted order.
+func (w *WatchlistStrategy) Symbols() []string {
+	symbols := make([]string, 0, len(w.instances))
+	for symbol := range w.instances {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// OnMarketUpdate forwards to the symbol's instance and drops signals the budget rejects.
+// Zero-Alloc: a map lookup plus in-place compaction of out.
+func (w *WatchlistStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	inst, ok := w.instances[state.Symbol]
+	if !ok {
+		return 0
+	}
+
+	count := inst.OnMarketUpdate(state, out)
+	if w.budget == nil {
+		return count
+	}
+
+	kept := 0
+	for i := 0; i < count; i++ {
+		if w.budget.Allow(&out[i]) {
+			out[kept] = out[i]
+			kept++
+		}
+	}
+	return kept
+}
+
+// OnOrderRejected returns a rejected entry's reservation to the shared budget
+// and forwards the rejection to the symbol's instance if it handles them.
+func (w *WatchlistStrategy) OnOrderRejected(rej domain.OrderRejection) {
+	if w.budget != nil && rej.Order.Side == domain.SideBuy {
+		release := rej.Order
+		release.Side = domain.SideSell
+		w.budget.Allow(&release)
+	}
+	if h, ok := w.instances[rej.Order.Symbol].(RejectionHandler); ok {
+		h.OnOrderRejected(rej)
+	}
+}
+
+// OnOrderUpdate forwards to the instance owning the order's symbol.
+func (w *WatchlistStrategy) OnOrderUpdate(order domain.Order) {
+	if inst, ok := w.instances[order.Symbol]; ok {
+		inst.OnOrderUpdate(order)
+	}
+}
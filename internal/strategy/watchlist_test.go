@@ -0,0 +1,96 @@
+package strategy_test
+
+import (
+	"crypto_go/internal/domain"
+	"crypto_go/internal/strategy"
+	"crypto_go/pkg/quant"
+	"testing"
+)
+
+// echoStrategy emits one order per update: BUY on positive prices, SELL on negative.
+type echoStrategy struct{ symbol string }
+
+func (e *echoStrategy) OnMarketUpdate(state domain.MarketState, out []domain.Order) int {
+	side, price := domain.SideBuy, int64(state.PriceMicros)
+	if price < 0 {
+		side, price = domain.SideSell, -price
+	}
+	out[0] = domain.Order{Symbol: e.symbol, Side: side, PriceMicros: price, QtySats: 100_000_000}
+	return 1
+}
+
+func (e *echoStrategy) OnOrderUpdate(domain.Order) {}
+
+func TestWatchlistStrategy_RoutesPerSymbol(t *testing.T) {
+	w := strategy.NewWatchlistStrategy(strategy.SMACrossTemplate(3, 5), []string{"BTC", "ETH", "BTC"}, nil)
+	if got := w.Symbols(); len(got) != 2 || got[0] != "BTC" || got[1] != "ETH" {
+		t.Fatalf("expected [BTC ETH], got %v", got)
+	}
+
+	out := make([]domain.Order, 1)
+	push := func(symbol string, price int64) int {
+		return w.OnMarketUpdate(domain.MarketState{Symbol: symbol, PriceMicros: quant.PriceMicros(price)}, out)
+	}
+
+	// Each symbol keeps its own history: ETH's flat prices never disturb BTC's cross
+	for i := 0; i < 5; i++ {
+		push("BTC", 100)
+		push("ETH", 100)
+	}
+	if n := push("BTC", 200); n != 1 || out[0].Symbol != "BTC" || out[0].Side != domain.SideBuy {
+		t.Errorf("expected BTC BUY, got %d %+v", n, out[0])
+	}
+	if n := push("XRP", 200); n != 0 {
+		t.Errorf("unwatched symbol produced %d orders", n)
+	}
+}
+
+func TestWatchlistStrategy_SharedRiskBudget(t *testing.T) {
+	budget := strategy.NewRiskBudget(250)
+	w := strategy.NewWatchlistStrategy(func(symbol string) strategy.Strategy {
+		return &echoStrategy{symbol: symbol}
+	}, []string{"BTC", "ETH"}, budget)
+
+	out := make([]domain.Order, 1)
+	push := func(symbol string, price int64) int {
+		return w.OnMarketUpdate(domain.MarketState{Symbol: symbol, PriceMicros: quant.PriceMicros(price)}, out)
+	}
+
+	if push("BTC", 100) != 1 || push("ETH", 100) != 1 {
+		t.Fatal("expected both buys within budget")
+	}
+	// 200 used: a third buy of 100 on either symbol exceeds 250
+	if push("ETH", 100) != 0 {
+		t.Error("expected buy beyond shared budget to be dropped")
+	}
+	if budget.Used() != 200 {
+		t.Errorf("expected 200 used, got %d", budget.Used())
+	}
+
+	// A sell only releases its own symbol's reservation
+	if push("BTC", -500) != 1 {
+		t.Fatal("expected sell to pass")
+	}
+	if budget.Used() != 100 || budget.Reserved("BTC") != 0 || budget.Reserved("ETH") != 100 {
+		t.Errorf("unexpected budget after sell: used=%d btc=%d eth=%d", budget.Used(), budget.Reserved("BTC"), budget.Reserved("ETH"))
+	}
+	if push("ETH", 100) != 1 {
+		t.Error("expected buy to pass after budget release")
+	}
+}
+
+func TestWatchlistStrategy_RejectionReleasesBudget(t *testing.T) {
+	budget := strategy.NewRiskBudget(100)
+	w := strategy.NewWatchlistStrategy(func(symbol string) strategy.Strategy {
+		return &echoStrategy{symbol: symbol}
+	}, []string{"BTC"}, budget)
+
+	out := make([]domain.Order, 1)
+	if w.OnMarketUpdate(domain.MarketState{Symbol: "BTC", PriceMicros: 100}, out) != 1 {
+		t.Fatal("expected buy within budget")
+	}
+	w.OnOrderRejected(domain.OrderRejection{Order: out[0], Reason: domain.RejectMinSize})
+	if budget.Used() != 0 {
+		t.Errorf("rejected entry must release its reservation, used=%d", budget.Used())
+	}
+}
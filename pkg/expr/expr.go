@@ -0,0 +1,416 @@
+// Package expr compiles small fixed-point conditions over named variables,
+// shared by alert rules and strategy scripts.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// ErrSyntax is wrapped by the errors of Compile.
+var ErrSyntax = errors.New("invalid expression")
+
+// Vars resolves the variables of an expression to fixed-point values with six
+// decimals (like PriceMicros). ok = false means no value yet.
+type Vars interface {
+	Lookup(name string) (value int64, ok bool)
+}
+
+// Map is a Vars backed by a map.
+type Map map[string]int64
+
+func (m Map) Lookup(name string) (int64, bool) {
+	x, ok := m[name]
+	return x, ok
+}
+
+// Expr is a compiled condition, e.g.
+//
+//	premium.BTC >= 5 && price.UPBIT.BTC < 80000000
+//
+// Numbers are decimals, compared as six-decimal fixed point. Operators: + -
+// (on numbers), < <= > >= == != (numbers to a condition), ! && || and
+// parentheses (on conditions). A condition reading a variable without a value
+// is unknown: && and || only decide on known operands, and an unknown result
+// does not fire.
+type Expr struct {
+	src  string
+	root node
+	vars []string
+}
+
+// tri is a three-valued condition: a variable may have no value yet.
+type tri int8
+
+const (
+	unknown tri = iota
+	no
+	yes
+)
+
+type node interface {
+	num(v Vars) (int64, bool) // Numeric nodes
+	cond(v Vars) tri          // Condition nodes
+	isCond() bool
+}
+
+// Compile parses src; the result must be a condition.
+func Compile(src string) (*Expr, error) {
+	p := &parser{src: src}
+	p.next()
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+	if !root.isCond() {
+		return nil, fmt.Errorf("%w: %q is a number, not a condition", ErrSyntax, src)
+	}
+	return &Expr{src: src, root: root, vars: p.vars}, nil
+}
+
+// Eval reports whether the condition holds. Unknown counts as false.
+func (e *Expr) Eval(v Vars) bool {
+	return e.root.cond(v) == yes
+}
+
+// Vars returns the variable names the expression reads, in order of appearance.
+func (e *Expr) Vars() []string {
+	return e.vars
+}
+
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Nodes
+
+type numLit int64
+
+func (n numLit) num(Vars) (int64, bool) { return int64(n), true }
+func (n numLit) cond(Vars) tri          { return unknown }
+func (n numLit) isCond() bool           { return false }
+
+type varRef string
+
+func (n varRef) num(v Vars) (int64, bool) { return v.Lookup(string(n)) }
+func (n varRef) cond(Vars) tri            { return unknown }
+func (n varRef) isCond() bool             { return false }
+
+type arith struct {
+	op   byte // '+', '-'
+	l, r node
+}
+
+func (n arith) num(v Vars) (int64, bool) {
+	l, ok1 := n.l.num(v)
+	r, ok2 := n.r.num(v)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	if n.op == '-' {
+		return safe.SafeSub(l, r), true
+	}
+	return safe.SafeAdd(l, r), true
+}
+func (n arith) cond(Vars) tri { return unknown }
+func (n arith) isCond() bool  { return false }
+
+type compare struct {
+	op   string
+	l, r node
+}
+
+func (n compare) num(Vars) (int64, bool) { return 0, false }
+func (n compare) isCond() bool           { return true }
+func (n compare) cond(v Vars) tri {
+	l, ok1 := n.l.num(v)
+	r, ok2 := n.r.num(v)
+	if !ok1 || !ok2 {
+		return unknown
+	}
+	var holds bool
+	switch n.op {
+	case "<":
+		holds = l < r
+	case "<=":
+		holds = l <= r
+	case ">":
+		holds = l > r
+	case ">=":
+		holds = l >= r
+	case "==":
+		holds = l == r
+	case "!=":
+		holds = l != r
+	}
+	if holds {
+		return yes
+	}
+	return no
+}
+
+type logic struct {
+	and  bool
+	l, r node
+}
+
+func (n logic) num(Vars) (int64, bool) { return 0, false }
+func (n logic) isCond() bool           { return true }
+func (n logic) cond(v Vars) tri {
+	l, r := n.l.cond(v), n.r.cond(v)
+	decisive, other := no, yes // && is decided by a false operand
+	if !n.and {
+		decisive, other = yes, no
+	}
+	switch {
+	case l == decisive || r == decisive:
+		return decisive
+	case l == other && r == other:
+		return other
+	}
+	return unknown
+}
+
+type not struct{ x node }
+
+func (n not) num(Vars) (int64, bool) { return 0, false }
+func (n not) isCond() bool           { return true }
+func (n not) cond(v Vars) tri {
+	switch n.x.cond(v) {
+	case yes:
+		return no
+	case no:
+		return yes
+	}
+	return unknown
+}
+
+// Parser: or := and {"||" and}; and := unary {"&&" unary};
+// unary := "!" unary | cmp; cmp := sum [op sum]; sum := term {("+"|"-") term};
+// term := number | name | "-" term | "(" or ")".
+
+type tokKind uint8
+
+const (
+	tokEOF tokKind = iota
+	tokNum
+	tokName
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	src  string
+	pos  int
+	tok  token
+	vars []string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at column %d of %q", ErrSyntax, fmt.Sprintf(format, args...), p.tok.pos+1, p.src)
+}
+
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c >= '0' && c <= '9', c == '.', c == '/':
+		return !first
+	}
+	return false
+}
+
+// next reads the following token into p.tok.
+func (p *parser) next() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	c := p.src[p.pos]
+	switch {
+	case c >= '0' && c <= '9':
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.' || p.src[p.pos] == '_') {
+			p.pos++
+		}
+		p.tok = token{kind: tokNum, text: p.src[start:p.pos], pos: start}
+	case isNameByte(c, true):
+		for p.pos < len(p.src) && isNameByte(p.src[p.pos], false) {
+			p.pos++
+		}
+		p.tok = token{kind: tokName, text: p.src[start:p.pos], pos: start}
+	default:
+		for _, op := range []string{"<=", ">=", "==", "!=", "&&", "||", "<", ">", "!", "+", "-", "(", ")"} {
+			if strings.HasPrefix(p.src[p.pos:], op) {
+				p.pos += len(op)
+				p.tok = token{kind: tokOp, text: op, pos: start}
+				return
+			}
+		}
+		p.pos++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	}
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	if p.tok.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if p.tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) or() (node, error) {
+	return p.logical(false)
+}
+
+func (p *parser) logical(and bool) (node, error) {
+	sub, op := p.logicalAnd, "||"
+	if and {
+		sub, op = p.unary, "&&"
+	}
+	l, err := sub()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp(op) {
+		if !l.isCond() {
+			return nil, p.errorf("%s needs conditions", op)
+		}
+		p.next()
+		r, err := sub()
+		if err != nil {
+			return nil, err
+		}
+		if !r.isCond() {
+			return nil, p.errorf("%s needs conditions", op)
+		}
+		l = logic{and: and, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) logicalAnd() (node, error) {
+	return p.logical(true)
+}
+
+func (p *parser) unary() (node, error) {
+	if p.isOp("!") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if !x.isCond() {
+			return nil, p.errorf("! needs a condition")
+		}
+		return not{x}, nil
+	}
+	return p.compare()
+}
+
+func (p *parser) compare() (node, error) {
+	l, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp("<", "<=", ">", ">=", "==", "!=") {
+		return l, nil
+	}
+	op := p.tok.text
+	if l.isCond() {
+		return nil, p.errorf("%s needs numbers", op)
+	}
+	p.next()
+	r, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if r.isCond() {
+		return nil, p.errorf("%s needs numbers", op)
+	}
+	return compare{op: op, l: l, r: r}, nil
+}
+
+func (p *parser) sum() (node, error) {
+	l, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+", "-") {
+		op := p.tok.text[0]
+		if l.isCond() {
+			return nil, p.errorf("%c needs numbers", op)
+		}
+		p.next()
+		r, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if r.isCond() {
+			return nil, p.errorf("%c needs numbers", op)
+		}
+		l = arith{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) term() (node, error) {
+	switch {
+	case p.tok.kind == tokNum:
+		text := strings.ReplaceAll(p.tok.text, "_", "")
+		if strings.Count(text, ".") > 1 || strings.HasSuffix(text, ".") {
+			return nil, p.errorf("invalid number %q", p.tok.text)
+		}
+		p.next()
+		return numLit(quant.ToPriceMicrosStr(text)), nil
+	case p.tok.kind == tokName:
+		name := p.tok.text
+		p.vars = append(p.vars, name)
+		p.next()
+		return varRef(name), nil
+	case p.isOp("-"):
+		p.next()
+		x, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if x.isCond() {
+			return nil, p.errorf("- needs a number")
+		}
+		return arith{op: '-', l: numLit(0), r: x}, nil
+	case p.isOp("("):
+		p.next()
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOp(")") {
+			return nil, p.errorf("missing )")
+		}
+		p.next()
+		return x, nil
+	case p.tok.kind == tokEOF:
+		return nil, p.errorf("unexpected end")
+	}
+	return nil, p.errorf("unexpected %q", p.tok.text)
+}
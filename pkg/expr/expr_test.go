@@ -0,0 +1,59 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	vars := Map{"premium.BTC": 5_200_000, "price.UPBIT.BTC": 79_000_000_000_000, "price.FX.USD/KRW": 1_400_000_000}
+	cases := []struct {
+		src  string
+		want bool
+	}{
+		{"premium.BTC >= 5", true},
+		{"premium.BTC >= 5.3", false},
+		{"premium.BTC >= 5 && price.UPBIT.BTC < 80_000_000", true},
+		{"!(premium.BTC > 5) || price.FX.USD/KRW == 1400", true},
+		{"price.UPBIT.BTC - 78000000 > 999999.5", true},
+		{"-premium.BTC < -5", true},
+		// No value for ETH: unknown, and || decides only on known operands
+		{"premium.ETH > 1", false},
+		{"!(premium.ETH > 1)", false},
+		{"premium.ETH > 1 || premium.BTC > 5", true},
+		{"premium.ETH > 1 && premium.BTC > 5", false},
+		{"premium.ETH > 1 && premium.BTC > 6", false},
+	}
+	for _, c := range cases {
+		e, err := Compile(c.src)
+		if err != nil {
+			t.Errorf("%q: %v", c.src, err)
+			continue
+		}
+		if got := e.Eval(vars); got != c.want {
+			t.Errorf("%q = %v, want %v", c.src, got, c.want)
+		}
+	}
+
+	e, _ := Compile("premium.BTC > 1 && price.UPBIT.BTC > 0")
+	if v := e.Vars(); len(v) != 2 || v[0] != "premium.BTC" || v[1] != "price.UPBIT.BTC" {
+		t.Errorf("unexpected vars %v", v)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"premium.BTC",      // A number, not a condition
+		"premium.BTC > ",   // Missing operand
+		"(premium.BTC > 1", // Missing )
+		"premium.BTC > 1 && 2",
+		"premium.BTC > 1 > 2",
+		"premium.BTC * 2 > 1",
+		"1.2.3 > 1",
+	} {
+		if _, err := Compile(src); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: expected a syntax error, got %v", src, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package quant
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of "now" for the engine and strategies. Live runs use
+// RealClock; replay and backtests use a SimClock that follows the event
+// timestamps, so their results never depend on wall time.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock reads the wall clock.
+type RealClock struct{}
+
+// Now returns time.Now().
+func (RealClock) Now() time.Time { return time.Now() }
+
+// SimClock is a manually driven clock (safe for concurrent use). It only
+// moves when Set or Advance is called, never on its own.
+type SimClock struct {
+	us atomic.Int64
+}
+
+// NewSimClock creates a clock stopped at ts.
+func NewSimClock(ts TimeStamp) *SimClock {
+	c := &SimClock{}
+	c.us.Store(int64(ts))
+	return c
+}
+
+// Now returns the simulated time (UTC).
+func (c *SimClock) Now() time.Time { return time.UnixMicro(c.us.Load()).UTC() }
+
+// Set moves the clock to ts. Earlier timestamps are ignored, so an
+// out-of-order event never turns time back.
+func (c *SimClock) Set(ts TimeStamp) {
+	for {
+		cur := c.us.Load()
+		if int64(ts) <= cur || c.us.CompareAndSwap(cur, int64(ts)) {
+			return
+		}
+	}
+}
+
+// Advance moves the clock forward by d.
+func (c *SimClock) Advance(d time.Duration) {
+	c.us.Add(d.Microseconds())
+}
+
+// Stamp returns the clock's current time as a TimeStamp.
+func Stamp(c Clock) TimeStamp {
+	return TimeStamp(c.Now().UnixMicro())
+}
@@ -0,0 +1,22 @@
+package quant
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimClock(t *testing.T) {
+	c := NewSimClock(1_000_000)
+	if got := Stamp(c); got != 1_000_000 {
+		t.Fatalf("Stamp = %d; want 1000000", got)
+	}
+	c.Set(5_000_000)
+	c.Set(2_000_000) // Out of order: ignored
+	if got := Stamp(c); got != 5_000_000 {
+		t.Errorf("Set must not turn time back: %d", got)
+	}
+	c.Advance(1500 * time.Millisecond)
+	if got := c.Now(); !got.Equal(time.UnixMicro(6_500_000)) || got.Location() != time.UTC {
+		t.Errorf("Now = %v; want 6.5s after the epoch in UTC", got)
+	}
+}
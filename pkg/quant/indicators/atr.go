@@ -0,0 +1,63 @@
+package indicators
+
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// ATR is Wilder's Average True Range over bars. The true range is the
+// widest of high-low and the gaps from the previous close; the first bar has
+// no previous close and counts high-low.
+type ATR struct {
+	value     int64
+	prevClose int64
+	period    int
+	count     int // Bars seen, up to period
+}
+
+// NewATR creates an ATR over period bars. Panics if period <= 0.
+func NewATR(period int) *ATR {
+	if period <= 0 {
+		panic("indicators.ATR: need period > 0")
+	}
+	return &ATR{period: period}
+}
+
+// Update folds one bar into the average and returns it (meaningful once Ready).
+func (a *ATR) Update(high, low, close quant.PriceMicros) quant.PriceMicros {
+	tr := safe.SafeSub(int64(high), int64(low))
+	if a.count > 0 {
+		tr = max(tr, abs(safe.SafeSub(int64(high), a.prevClose)), abs(safe.SafeSub(int64(low), a.prevClose)))
+	}
+	a.prevClose = int64(close)
+
+	n := int64(a.period)
+	if a.count < a.period {
+		// Seed: simple average of the first period ranges
+		a.value = safe.SafeAdd(a.value, tr)
+		a.count++
+		if a.count == a.period {
+			a.value /= n
+		}
+		return a.Value()
+	}
+	a.value = safe.SafeDiv(safe.SafeAdd(safe.SafeMul(a.value, n-1), tr), n)
+	return a.Value()
+}
+
+// Value returns the average true range (0 until Ready).
+func (a *ATR) Value() quant.PriceMicros {
+	if !a.Ready() {
+		return 0
+	}
+	return quant.PriceMicros(a.value)
+}
+
+// Ready reports whether period bars have been seen.
+func (a *ATR) Ready() bool { return a.count >= a.period }
+
+// Period returns the smoothing period.
+func (a *ATR) Period() int { return a.period }
+
+// Reset forgets every bar.
+func (a *ATR) Reset() { a.value, a.prevClose, a.count = 0, 0, 0 }
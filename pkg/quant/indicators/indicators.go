@@ -0,0 +1,133 @@
+// Package indicators provides incremental technical indicators over
+// quant.PriceMicros for composing strategies. Every Update is O(1) and
+// allocation-free; window buffers are allocated once by the constructor.
+// Integer math throughout (pkg/safe), so results are deterministic across
+// replays. Indicators are not safe for concurrent use.
+package indicators
+
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// SMA is a simple moving average over the last period values.
+type SMA struct {
+	sum    int64
+	window []quant.PriceMicros // Ring buffer
+	head   int
+	count  int
+}
+
+// NewSMA creates an average over period values. Panics if period <= 0.
+func NewSMA(period int) *SMA {
+	if period <= 0 {
+		panic("indicators.SMA: need period > 0")
+	}
+	return &SMA{window: make([]quant.PriceMicros, period)}
+}
+
+// Update adds v, evicting the oldest value once the window is full, and
+// returns the average of the values in the window.
+func (s *SMA) Update(v quant.PriceMicros) quant.PriceMicros {
+	if s.count == len(s.window) {
+		s.sum = safe.SafeSub(s.sum, int64(s.window[s.head]))
+	} else {
+		s.count++
+	}
+	s.window[s.head] = v
+	s.sum = safe.SafeAdd(s.sum, int64(v))
+	s.head = (s.head + 1) % len(s.window)
+	return s.Value()
+}
+
+// Value returns the average of the values seen so far in the window (0 before any).
+func (s *SMA) Value() quant.PriceMicros {
+	if s.count == 0 {
+		return 0
+	}
+	return quant.PriceMicros(s.sum / int64(s.count))
+}
+
+// Ready reports whether the window is full.
+func (s *SMA) Ready() bool { return s.count == len(s.window) }
+
+// Period returns the window length.
+func (s *SMA) Period() int { return len(s.window) }
+
+// Reset empties the window.
+func (s *SMA) Reset() {
+	clear(s.window)
+	s.sum, s.head, s.count = 0, 0, 0
+}
+
+// EMA is an exponential moving average with smoothing 2/(period+1), seeded
+// with the first value. The truncation bias stays below one micro per step.
+type EMA struct {
+	value  int64
+	period int64
+	count  int
+}
+
+// NewEMA creates an average with the given period. Panics if period <= 0.
+func NewEMA(period int) *EMA {
+	if period <= 0 {
+		panic("indicators.EMA: need period > 0")
+	}
+	return &EMA{period: int64(period)}
+}
+
+// Update folds v into the average and returns it.
+func (e *EMA) Update(v quant.PriceMicros) quant.PriceMicros {
+	if e.count == 0 {
+		e.value = int64(v)
+	} else {
+		e.value = safe.SafeAdd(e.value, safe.SafeMulDiv(safe.SafeSub(int64(v), e.value), 2, e.period+1))
+	}
+	if e.count < int(e.period) {
+		e.count++
+	}
+	return quant.PriceMicros(e.value)
+}
+
+// Value returns the current average (0 before any value).
+func (e *EMA) Value() quant.PriceMicros { return quant.PriceMicros(e.value) }
+
+// Ready reports whether the average has seen a full period of values.
+func (e *EMA) Ready() bool { return e.count >= int(e.period) }
+
+// Period returns the smoothing period.
+func (e *EMA) Period() int { return int(e.period) }
+
+// Reset forgets every value.
+func (e *EMA) Reset() { e.value, e.count = 0, 0 }
+
+// isqrt returns floor(sqrt(v)) for v >= 0 (Newton's method, no floats).
+func isqrt(v int64) int64 {
+	if v < 2 {
+		return v
+	}
+	x := v
+	y := (x + 1) / 2
+	for y < x {
+		x = y
+		y = (x + v/x) / 2
+	}
+	return x
+}
+
+// floorDiv returns a/b rounded toward negative infinity (b > 0).
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// abs returns |v|.
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,148 @@
+package indicators
+
+import (
+	"crypto_go/pkg/quant"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSMA(t *testing.T) {
+	s := NewSMA(3)
+	for i, c := range []struct {
+		v, want int64
+		ready   bool
+	}{{10, 10, false}, {20, 15, false}, {30, 20, true}, {40, 30, true}, {50, 40, true}} {
+		if got := s.Update(quant.PriceMicros(c.v)); int64(got) != c.want || s.Ready() != c.ready {
+			t.Errorf("step %d: got %d ready=%v, want %d ready=%v", i, got, s.Ready(), c.want, c.ready)
+		}
+	}
+	s.Reset()
+	if s.Value() != 0 || s.Ready() || s.Update(7) != 7 {
+		t.Error("Reset should empty the window")
+	}
+}
+
+func TestEMA(t *testing.T) {
+	e := NewEMA(3) // Smoothing 1/2
+	for i, c := range []struct{ v, want int64 }{{100, 100}, {200, 150}, {200, 175}, {100, 138}} {
+		if got := e.Update(quant.PriceMicros(c.v)); int64(got) != c.want {
+			t.Errorf("step %d: got %d, want %d", i, got, c.want)
+		}
+		if e.Ready() != (i >= 2) {
+			t.Errorf("step %d: Ready() = %v", i, e.Ready())
+		}
+	}
+}
+
+func TestRSI(t *testing.T) {
+	r := NewRSI(2)
+	r.Update(100)
+	if r.Update(110); r.Ready() {
+		t.Fatal("ready after one change")
+	}
+	// Seed: gains 10, 0 and losses 0, 5 → 5 / (5+2)
+	if got := r.Update(105); !r.Ready() || got != 7142 {
+		t.Errorf("seeded RSI = %d (ready=%v), want 7142", got, r.Ready())
+	}
+	// Wilder: gain (5+0)/2=2, loss (2+15)/2=8
+	if got := r.Update(90); got != 2000 {
+		t.Errorf("smoothed RSI = %d, want 2000", got)
+	}
+
+	flat := NewRSI(3)
+	for range 5 {
+		flat.Update(100)
+	}
+	if flat.Value() != 5000 {
+		t.Errorf("flat RSI = %d, want 5000", flat.Value())
+	}
+}
+
+func TestATR(t *testing.T) {
+	a := NewATR(2)
+	if a.Update(110, 90, 100); a.Ready() {
+		t.Fatal("ready after one bar")
+	}
+	// Second range: the gap from the previous close (115-100) beats high-low
+	if got := a.Update(115, 105, 112); got != 17 {
+		t.Errorf("seeded ATR = %d, want 17", got)
+	}
+	if got := a.Update(120, 110, 118); got != 13 {
+		t.Errorf("smoothed ATR = %d, want 13", got)
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	s := NewStdDev(8)
+	for _, v := range []quant.PriceMicros{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.Update(v)
+	}
+	if s.Value() != 2 || s.Mean() != 5 || !s.Ready() {
+		t.Errorf("got σ=%d mean=%d ready=%v, want 2, 5, true", s.Value(), s.Mean(), s.Ready())
+	}
+}
+
+func TestStdDev_MatchesReference(t *testing.T) {
+	// A KRW BTC random walk in micros: squares of raw values overflow int64
+	const period = 20
+	rng := rand.New(rand.NewSource(1))
+	s := NewStdDev(period)
+	price := int64(140_000_000) * quant.PriceScale
+	var window []float64
+	for i := range 2000 {
+		price += (rng.Int63n(2001) - 1000) * price / 100_000
+		got := s.Update(quant.PriceMicros(price))
+		if window = append(window, float64(price)); len(window) > period {
+			window = window[1:]
+		}
+		if len(window) < 2 {
+			continue
+		}
+		var mean, sq float64
+		for _, v := range window {
+			mean += v
+		}
+		mean /= float64(len(window))
+		for _, v := range window {
+			sq += (v - mean) * (v - mean)
+		}
+		want := math.Sqrt(sq / float64(len(window)))
+		// Quantization: within a few quanta (price/1e6) of the float result
+		if tol := float64(price)/1e5 + 1; math.Abs(float64(got)-want) > tol {
+			t.Fatalf("step %d: σ=%d, reference %.0f", i, got, want)
+		}
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	v := NewVWAP(2)
+	v.Update(100*quant.PriceScale, quant.QtyScale)
+	if got := v.Update(200*quant.PriceScale, 3*quant.QtyScale); got != 175*quant.PriceScale {
+		t.Errorf("VWAP = %s, want 175", got)
+	}
+	if v.Update(300*quant.PriceScale, 0); v.Volume() != 4*quant.QtyScale {
+		t.Error("a zero-quantity trade should be ignored")
+	}
+	// Evicts the first trade: (200*3 + 300*1) / 4
+	if got := v.Update(300*quant.PriceScale, quant.QtyScale); got != 225*quant.PriceScale {
+		t.Errorf("VWAP = %s, want 225", got)
+	}
+}
+
+func TestUpdate_ZeroAlloc(t *testing.T) {
+	sma, ema, rsi, atr, sd, vwap := NewSMA(20), NewEMA(20), NewRSI(14), NewATR(14), NewStdDev(20), NewVWAP(50)
+	p := quant.PriceMicros(95_000_000 * quant.PriceScale)
+	allocs := testing.AllocsPerRun(1000, func() {
+		p += 1000
+		sma.Update(p)
+		ema.Update(p)
+		rsi.Update(p)
+		atr.Update(p+500, p-500, p)
+		sd.Update(p)
+		vwap.Update(p, 1000)
+	})
+	if allocs != 0 {
+		t.Errorf("Update allocated %.1f times per run", allocs)
+	}
+}
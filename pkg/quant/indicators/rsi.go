@@ -0,0 +1,83 @@
+package indicators
+
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// RSI is Wilder's Relative Strength Index in basis points (0..10_000). The
+// first value only sets the reference price; the averages are seeded with the
+// simple mean of the first period changes, then Wilder-smoothed.
+type RSI struct {
+	avgGain   int64
+	avgLoss   int64
+	prevPrice int64
+	period    int
+	count     int // Price changes seen, up to period; -1 before the first price
+}
+
+// NewRSI creates an RSI over period price changes. Panics if period <= 0.
+func NewRSI(period int) *RSI {
+	if period <= 0 {
+		panic("indicators.RSI: need period > 0")
+	}
+	return &RSI{period: period, count: -1}
+}
+
+// Update folds the change from the previous price into the averages and
+// returns the RSI (meaningful once Ready).
+func (r *RSI) Update(price quant.PriceMicros) int64 {
+	p := int64(price)
+	if r.count < 0 {
+		r.prevPrice, r.count = p, 0
+		return r.Value()
+	}
+
+	var gain, loss int64
+	if change := safe.SafeSub(p, r.prevPrice); change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+	r.prevPrice = p
+
+	n := int64(r.period)
+	if r.count < r.period {
+		// Seed: simple average of the first period changes
+		r.avgGain = safe.SafeAdd(r.avgGain, gain)
+		r.avgLoss = safe.SafeAdd(r.avgLoss, loss)
+		r.count++
+		if r.count == r.period {
+			r.avgGain /= n
+			r.avgLoss /= n
+		}
+		return r.Value()
+	}
+	// Wilder smoothing
+	r.avgGain = safe.SafeDiv(safe.SafeAdd(safe.SafeMul(r.avgGain, n-1), gain), n)
+	r.avgLoss = safe.SafeDiv(safe.SafeAdd(safe.SafeMul(r.avgLoss, n-1), loss), n)
+	return r.Value()
+}
+
+// Value returns the RSI in basis points: 5000 for a flat market or until Ready.
+func (r *RSI) Value() int64 {
+	if !r.Ready() {
+		return 5000
+	}
+	total := safe.SafeAdd(r.avgGain, r.avgLoss)
+	if total == 0 {
+		return 5000
+	}
+	return safe.SafeMulDiv(r.avgGain, 10_000, total)
+}
+
+// Ready reports whether period price changes have been seen.
+func (r *RSI) Ready() bool { return r.count >= r.period }
+
+// Period returns the smoothing period.
+func (r *RSI) Period() int { return r.period }
+
+// Reset forgets every price.
+func (r *RSI) Reset() {
+	r.avgGain, r.avgLoss, r.prevPrice, r.count = 0, 0, 0, -1
+}
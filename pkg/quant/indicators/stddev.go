@@ -0,0 +1,95 @@
+package indicators
+
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// StdDev is the rolling population standard deviation (and mean) of the last
+// period values. To keep sums of squares inside int64 at any price, values are
+// quantized to about six significant digits of the first value and tracked
+// as deviations from a running anchor near the mean; the deviation is exact
+// to that resolution. The resolution is fixed until Reset, so a series that
+// moves by orders of magnitude should be reset.
+type StdDev struct {
+	sum    int64 // Raw values, for an exact mean
+	qSum   int64 // Quantized deviations from anchor
+	qSumSq int64
+	anchor int64 // Quantized
+	scale  int64 // Micros per quantum
+	window []quant.PriceMicros
+	head   int
+	count  int
+}
+
+// NewStdDev creates a deviation over period values. Panics if period < 2.
+func NewStdDev(period int) *StdDev {
+	if period < 2 {
+		panic("indicators.StdDev: need period >= 2")
+	}
+	return &StdDev{window: make([]quant.PriceMicros, period)}
+}
+
+// Update adds v, evicting the oldest value once the window is full, and
+// returns the deviation of the window.
+func (s *StdDev) Update(v quant.PriceMicros) quant.PriceMicros {
+	if s.count == 0 {
+		s.scale = abs(int64(v))/1_000_000 + 1
+		s.anchor = floorDiv(int64(v), s.scale)
+	}
+	if s.count == len(s.window) {
+		old := s.window[s.head]
+		d := floorDiv(int64(old), s.scale) - s.anchor
+		s.sum = safe.SafeSub(s.sum, int64(old))
+		s.qSum = safe.SafeSub(s.qSum, d)
+		s.qSumSq = safe.SafeSub(s.qSumSq, safe.SafeMul(d, d))
+	} else {
+		s.count++
+	}
+	d := floorDiv(int64(v), s.scale) - s.anchor
+	s.sum = safe.SafeAdd(s.sum, int64(v))
+	s.qSum = safe.SafeAdd(s.qSum, d)
+	s.qSumSq = safe.SafeAdd(s.qSumSq, safe.SafeMul(d, d))
+	s.window[s.head] = v
+	s.head = (s.head + 1) % len(s.window)
+
+	// Move the anchor to the quantized mean: Σ(d-k)² = Σd² - 2kΣd + nk²
+	n := int64(s.count)
+	if k := s.qSum / n; k != 0 {
+		s.qSumSq = safe.SafeAdd(safe.SafeSub(s.qSumSq, safe.SafeMul(2*k, s.qSum)), safe.SafeMul(n, safe.SafeMul(k, k)))
+		s.qSum = safe.SafeSub(s.qSum, safe.SafeMul(n, k))
+		s.anchor += k
+	}
+	return s.Value()
+}
+
+// Value returns the population standard deviation of the window (0 before
+// two values).
+func (s *StdDev) Value() quant.PriceMicros {
+	if s.count < 2 {
+		return 0
+	}
+	n := int64(s.count)
+	variance := safe.SafeSub(s.qSumSq, safe.SafeMulDiv(s.qSum, s.qSum, n)) / n
+	return quant.PriceMicros(safe.SafeMul(isqrt(max(variance, 0)), s.scale))
+}
+
+// Mean returns the exact mean of the window (0 before any value).
+func (s *StdDev) Mean() quant.PriceMicros {
+	if s.count == 0 {
+		return 0
+	}
+	return quant.PriceMicros(s.sum / int64(s.count))
+}
+
+// Ready reports whether the window is full.
+func (s *StdDev) Ready() bool { return s.count == len(s.window) }
+
+// Period returns the window length.
+func (s *StdDev) Period() int { return len(s.window) }
+
+// Reset empties the window and picks a new resolution on the next value.
+func (s *StdDev) Reset() {
+	clear(s.window)
+	s.sum, s.qSum, s.qSumSq, s.anchor, s.scale, s.head, s.count = 0, 0, 0, 0, 0, 0, 0
+}
@@ -0,0 +1,72 @@
+package indicators
+
+import (
+	"crypto_go/pkg/quant"
+	"crypto_go/pkg/safe"
+)
+
+// VWAP is the volume-weighted average price of the last period trades.
+type VWAP struct {
+	notional int64       // Quote micros
+	qty      int64       // Sats
+	trades   []vwapTrade // Ring buffer
+	head     int
+	count    int
+}
+
+type vwapTrade struct {
+	notional int64
+	qty      int64
+}
+
+// NewVWAP creates an average over period trades. Panics if period <= 0.
+func NewVWAP(period int) *VWAP {
+	if period <= 0 {
+		panic("indicators.VWAP: need period > 0")
+	}
+	return &VWAP{trades: make([]vwapTrade, period)}
+}
+
+// Update adds a trade of qty at price, evicting the oldest once the window is
+// full, and returns the average. Non-positive quantities are ignored.
+func (v *VWAP) Update(price quant.PriceMicros, qty quant.QtySats) quant.PriceMicros {
+	if qty <= 0 {
+		return v.Value()
+	}
+	if v.count == len(v.trades) {
+		old := v.trades[v.head]
+		v.notional = safe.SafeSub(v.notional, old.notional)
+		v.qty = safe.SafeSub(v.qty, old.qty)
+	} else {
+		v.count++
+	}
+	t := vwapTrade{notional: safe.SafeMulDiv(int64(price), int64(qty), quant.QtyScale), qty: int64(qty)}
+	v.trades[v.head] = t
+	v.notional = safe.SafeAdd(v.notional, t.notional)
+	v.qty = safe.SafeAdd(v.qty, t.qty)
+	v.head = (v.head + 1) % len(v.trades)
+	return v.Value()
+}
+
+// Value returns the average price of the window (0 before any trade).
+func (v *VWAP) Value() quant.PriceMicros {
+	if v.qty == 0 {
+		return 0
+	}
+	return quant.PriceMicros(safe.SafeMulDiv(v.notional, quant.QtyScale, v.qty))
+}
+
+// Volume returns the quantity traded in the window.
+func (v *VWAP) Volume() quant.QtySats { return quant.QtySats(v.qty) }
+
+// Ready reports whether the window is full.
+func (v *VWAP) Ready() bool { return v.count == len(v.trades) }
+
+// Period returns the window length in trades.
+func (v *VWAP) Period() int { return len(v.trades) }
+
+// Reset empties the window (e.g. at a session boundary).
+func (v *VWAP) Reset() {
+	clear(v.trades)
+	v.notional, v.qty, v.head, v.count = 0, 0, 0, 0
+}
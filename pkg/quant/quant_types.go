@@ -96,6 +96,10 @@ func parseFixedPoint(s string, precision int) int64 {
 		return 0
 	}
 	for i := 0; i < precision; i++ {
+		if intPart > math.MaxInt64/10 || intPart < math.MinInt64/10 {
+			slog.Warn("parseFixedPoint: value out of range", "input", s)
+			return 0
+		}
 		intPart *= 10
 	}
 
@@ -108,7 +112,8 @@ func parseFixedPoint(s string, precision int) int64 {
 	if len(fracStr) > precision {
 		fracStr = fracStr[:precision]
 	}
-	fracPart, err2 := strconv.ParseInt(fracStr, 10, 64)
+	fracU, err2 := strconv.ParseUint(fracStr, 10, 64) // Digits only: "1.-5" is not a number
+	fracPart := int64(fracU)                          // At most precision digits: fits
 	if err2 != nil {
 		slog.Warn("parseFixedPoint: invalid fraction part", "input", s, "error", err2)
 		return intPart
@@ -121,7 +126,15 @@ func parseFixedPoint(s string, precision int) int64 {
 
 	// 3. Handle Negative
 	if strings.HasPrefix(parts[0], "-") {
+		if intPart < math.MinInt64+fracPart {
+			slog.Warn("parseFixedPoint: value out of range", "input", s)
+			return 0
+		}
 		return intPart - fracPart
 	}
+	if intPart > math.MaxInt64-fracPart {
+		slog.Warn("parseFixedPoint: value out of range", "input", s)
+		return 0
+	}
 	return intPart + fracPart
 }
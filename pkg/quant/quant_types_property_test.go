@@ -0,0 +1,122 @@
+package quant
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// exactFloat bounds values whose String() is exact: it formats through float64,
+// which holds 15 significant decimal digits.
+const exactFloat = 1_000_000_000_000_000
+
+func boundedConfig(bound int64) *quick.Config {
+	return &quick.Config{
+		MaxCount: 5000,
+		Values: func(args []reflect.Value, r *rand.Rand) {
+			for i := range args {
+				v := r.Int63n(bound) >> r.Intn(53) // Every magnitude, not just huge ones
+				if r.Intn(2) == 0 {
+					v = -v
+				}
+				args[i] = reflect.ValueOf(v)
+			}
+		},
+	}
+}
+
+// fixed formats v scaled by 10^precision without floats, e.g. (-1500000, 6) = "-1.500000".
+func fixed(v int64, precision int) string {
+	sign := ""
+	u := uint64(v)
+	if v < 0 {
+		sign, u = "-", uint64(-v)
+	}
+	scale := uint64(math.Pow10(precision))
+	frac := strconv.FormatUint(u%scale, 10)
+	return sign + strconv.FormatUint(u/scale, 10) + "." + strings.Repeat("0", precision-len(frac)) + frac
+}
+
+func TestProperty_PriceStringRoundTrip(t *testing.T) {
+	roundTrip := func(v int64) bool {
+		p := PriceMicros(v)
+		return ToPriceMicrosStr(p.String()) == p && ToPriceMicrosStr(fixed(v, 6)) == p
+	}
+	if err := quick.Check(roundTrip, boundedConfig(exactFloat)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_QtyStringRoundTrip(t *testing.T) {
+	roundTrip := func(v int64) bool {
+		q := QtySats(v)
+		return ToQtySatsStr(q.String()) == q && ToQtySatsStr(fixed(v, 8)) == q
+	}
+	if err := quick.Check(roundTrip, boundedConfig(exactFloat)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_FullRangeRoundTrip(t *testing.T) {
+	// Without floats every int64 survives, boundaries included
+	roundTrip := func(v int64) bool {
+		return ToPriceMicrosStr(fixed(v, 6)) == PriceMicros(v) && ToQtySatsStr(fixed(v, 8)) == QtySats(v)
+	}
+	if err := quick.Check(roundTrip, boundedConfig(math.MaxInt64)); err != nil {
+		t.Error(err)
+	}
+	for _, v := range []int64{math.MaxInt64, math.MinInt64 + 1} {
+		if !roundTrip(v) {
+			t.Errorf("boundary %d did not round-trip", v)
+		}
+	}
+}
+
+func TestProperty_NegationIsSymmetric(t *testing.T) {
+	symmetric := func(v int64) bool {
+		s := fixed(v, 6)
+		if v < 0 {
+			s = s[1:]
+		}
+		return ToPriceMicrosStr("-"+s) == -ToPriceMicrosStr(s)
+	}
+	if err := quick.Check(symmetric, boundedConfig(math.MaxInt64)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_ExtraDigitsTruncate(t *testing.T) {
+	// Digits beyond the precision are cut, never rounded: toward zero on both signs
+	truncates := func(v int64, extra uint8) bool {
+		s := fixed(v, 6) + strconv.Itoa(int(extra%10))
+		return ToPriceMicrosStr(s) == PriceMicros(v)
+	}
+	if err := quick.Check(truncates, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseFixedPoint_OutOfRange(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"9223372036854.775807", math.MaxInt64},
+		{"-9223372036854.775808", math.MinInt64},
+		{"9223372036854.775808", 0},  // One micro past MaxInt64
+		{"-9223372036854.775809", 0}, // One micro past MinInt64
+		{"10000000000000", 0},        // Integer part overflows while scaling
+		{"-10000000000000", 0},
+		{"0.-5", 0}, // Signed fraction is not a number
+		{"1.+5", 1_000_000},
+	}
+	for _, tt := range tests {
+		if got := int64(ToPriceMicrosStr(tt.input)); got != tt.want {
+			t.Errorf("ToPriceMicrosStr(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Package ring provides a bounded lock-free single-producer/single-consumer
+// queue for hand-offs on the hotpath.
+package ring
+
+import (
+	"sync/atomic"
+)
+
+// cacheLine is the padding unit keeping the producer's and the consumer's
+// counters on separate cache lines (64 bytes on amd64/arm64).
+const cacheLine = 64
+
+type pad [cacheLine]byte
+
+// SPSC is a bounded FIFO for exactly one producer goroutine and one consumer
+// goroutine at a time. Push and Pop never block or allocate; a full ring
+// refuses the value. Handing either side to another goroutine needs its own
+// happens-before edge (e.g. the old goroutine exits and is waited on).
+type SPSC[T any] struct {
+	_    pad
+	head atomic.Uint64 // Next slot to pop; written by the consumer only
+	_    pad
+	tail atomic.Uint64 // Next slot to push; written by the producer only
+	_    pad
+
+	// Each side caches the other's counter and reloads it only when the ring
+	// looks full (producer) or empty (consumer).
+	headCache uint64 // Producer's view of head
+	_         pad
+	tailCache uint64 // Consumer's view of tail
+	_         pad
+
+	mask  uint64
+	slots []T
+}
+
+// NewSPSC creates a ring holding at least size values (rounded up to a power of two).
+func NewSPSC[T any](size int) *SPSC[T] {
+	n := uint64(1)
+	for n < uint64(max(size, 1)) {
+		n <<= 1
+	}
+	return &SPSC[T]{mask: n - 1, slots: make([]T, n)}
+}
+
+// Push appends v. Returns false if the ring is full. Producer only.
+func (r *SPSC[T]) Push(v T) bool {
+	tail := r.tail.Load()
+	if tail-r.headCache > r.mask {
+		r.headCache = r.head.Load()
+		if tail-r.headCache > r.mask {
+			return false
+		}
+	}
+	r.slots[tail&r.mask] = v
+	r.tail.Store(tail + 1)
+	return true
+}
+
+// Pop removes the oldest value. Returns false if the ring is empty. Consumer only.
+func (r *SPSC[T]) Pop() (T, bool) {
+	var zero T
+	head := r.head.Load()
+	if head == r.tailCache {
+		r.tailCache = r.tail.Load()
+		if head == r.tailCache {
+			return zero, false
+		}
+	}
+	i := head & r.mask
+	v := r.slots[i]
+	r.slots[i] = zero // Drop the reference for the GC
+	r.head.Store(head + 1)
+	return v, true
+}
+
+// Len returns the number of queued values. Exact only on a quiescent ring.
+func (r *SPSC[T]) Len() int {
+	head := r.head.Load() // Before tail: tail never falls behind a head read earlier
+	return int(r.tail.Load() - head)
+}
+
+// Cap returns the ring's capacity.
+func (r *SPSC[T]) Cap() int {
+	return len(r.slots)
+}
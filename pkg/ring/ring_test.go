@@ -0,0 +1,171 @@
+package ring
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSPSC_FIFOAndBounds(t *testing.T) {
+	r := NewSPSC[int](3)
+	if r.Cap() != 4 {
+		t.Fatalf("Cap() = %d; want 4 (rounded up)", r.Cap())
+	}
+	if _, ok := r.Pop(); ok {
+		t.Error("Pop on an empty ring should fail")
+	}
+
+	// Wrap around several times
+	next := 0
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 4; i++ {
+			if !r.Push(round*4 + i) {
+				t.Fatalf("Push %d refused on a ring with room", round*4+i)
+			}
+		}
+		if r.Push(-1) {
+			t.Fatal("Push on a full ring should fail")
+		}
+		if r.Len() != 4 {
+			t.Errorf("Len() = %d; want 4", r.Len())
+		}
+		for i := 0; i < 4; i++ {
+			v, ok := r.Pop()
+			if !ok || v != next {
+				t.Fatalf("Pop() = %d, %v; want %d", v, ok, next)
+			}
+			next++
+		}
+	}
+}
+
+func TestSPSC_ReleasesPopped(t *testing.T) {
+	r := NewSPSC[*int](2)
+	v := 1
+	r.Push(&v)
+	r.Pop()
+	if r.slots[0] != nil {
+		t.Error("a popped slot should not keep its value reachable")
+	}
+}
+
+func TestSPSC_Concurrent(t *testing.T) {
+	const n = 200_000
+	r := NewSPSC[int](64)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := 0; i < n; {
+			if r.Push(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	})
+
+	for want := 0; want < n; {
+		v, ok := r.Pop()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		if v != want {
+			t.Fatalf("Pop() = %d; want %d", v, want)
+		}
+		want++
+	}
+	wg.Wait()
+}
+
+// The benchmarks hand b.N values from one goroutine to another, the way a
+// gateway feeds the sequencer, through the ring and through a buffered channel
+// of the same size.
+
+const benchSize = 1024
+
+func BenchmarkSPSC_Handoff(b *testing.B) {
+	r := NewSPSC[*int](benchSize)
+	v := new(int)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := 0; i < b.N; {
+			if r.Push(v) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	})
+	for i := 0; i < b.N; {
+		if _, ok := r.Pop(); ok {
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+	wg.Wait()
+}
+
+func BenchmarkChannel_Handoff(b *testing.B) {
+	ch := make(chan *int, benchSize)
+	v := new(int)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := 0; i < b.N; i++ {
+			ch <- v
+		}
+	})
+	for i := 0; i < b.N; i++ {
+		<-ch
+	}
+	wg.Wait()
+}
+
+// Burst: the producer fills the queue, then the consumer drains it, as when a
+// burst of ticks arrives while the sequencer is busy.
+
+func BenchmarkSPSC_Burst(b *testing.B) {
+	r := NewSPSC[*int](benchSize)
+	v := new(int)
+	b.ReportAllocs()
+	for b.Loop() {
+		for r.Push(v) {
+		}
+		for {
+			if _, ok := r.Pop(); !ok {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkChannel_Burst(b *testing.B) {
+	ch := make(chan *int, benchSize)
+	v := new(int)
+	b.ReportAllocs()
+	for b.Loop() {
+	fill:
+		for {
+			select {
+			case ch <- v:
+			default:
+				break fill
+			}
+		}
+	drain:
+		for {
+			select {
+			case <-ch:
+			default:
+				break drain
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package safe
 
 import (
 	"math"
+	"math/bits"
 )
 
 // SafeAdd performs int64 addition and panics on overflow/underflow.
@@ -60,3 +61,37 @@ func SafeDiv(a, b int64) int64 {
 	}
 	return a / b
 }
+
+// SafeMulDiv computes a * b / c with a 128-bit intermediate, truncating toward zero.
+// Use it for ratios of scaled values whose product overflows int64 (e.g. KRW prices
+// in micros times PriceScale). Panics on division by zero or if the result overflows.
+func SafeMulDiv(a, b, c int64) int64 {
+	if c == 0 {
+		panic("CORE_SAFE_DIV_BY_ZERO")
+	}
+	neg := (a < 0) != (b < 0) != (c < 0)
+	hi, lo := bits.Mul64(absU64(a), absU64(b))
+	divisor := absU64(c)
+	if hi >= divisor {
+		panic("CORE_SAFE_MULDIV_OVERFLOW")
+	}
+	q, _ := bits.Div64(hi, lo, divisor)
+
+	if neg {
+		if q > 1<<63 {
+			panic("CORE_SAFE_MULDIV_OVERFLOW")
+		}
+		return -int64(q)
+	}
+	if q > math.MaxInt64 {
+		panic("CORE_SAFE_MULDIV_OVERFLOW")
+	}
+	return int64(q)
+}
+
+func absU64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1 // MinInt64 safe
+	}
+	return uint64(v)
+}
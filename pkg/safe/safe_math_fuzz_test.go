@@ -58,3 +58,20 @@ func FuzzSafeDiv(f *testing.F) {
 		_ = SafeDiv(a, b)
 	})
 }
+
+// FuzzSafeMulDiv checks SafeMulDiv against plain int64 math where that cannot overflow.
+func FuzzSafeMulDiv(f *testing.F) {
+	f.Add(int64(6), int64(7), int64(3))
+	f.Add(int64(-100), int64(1000000), int64(7))
+	f.Add(int64(100000000000000), int64(1000000), int64(99000000000000))
+
+	f.Fuzz(func(t *testing.T, a, b, c int64) {
+		defer func() { recover() }() // Div by zero / overflow panics are expected
+		got := SafeMulDiv(a, b, c)
+		if a > -1<<31 && a < 1<<31 && b > -1<<31 && b < 1<<31 && c != 0 {
+			if want := a * b / c; got != want {
+				t.Errorf("SafeMulDiv(%d, %d, %d) = %d, want %d", a, b, c, got, want)
+			}
+		}
+	})
+}
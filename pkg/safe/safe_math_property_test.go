@@ -0,0 +1,107 @@
+package safe
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// edgyInt64 draws from the boundaries as often as from the whole range: plain
+// uniform int64s almost never land near MaxInt64 or zero.
+func edgyInt64(r *rand.Rand) int64 {
+	switch r.Intn(4) {
+	case 0:
+		return []int64{0, 1, -1, math.MaxInt64, math.MinInt64, math.MaxInt64 - 1, math.MinInt64 + 1}[r.Intn(7)]
+	case 1:
+		return r.Int63n(2_000_001) - 1_000_000 // Small values, both signs
+	case 2:
+		return int64(r.Uint64()) >> r.Intn(64) // Every magnitude
+	default:
+		return int64(r.Uint64())
+	}
+}
+
+var propertyConfig = &quick.Config{
+	MaxCount: 5000,
+	Values: func(args []reflect.Value, r *rand.Rand) {
+		for i := range args {
+			args[i] = reflect.ValueOf(edgyInt64(r))
+		}
+	},
+}
+
+// exact runs op and reports its result, or ok=false if it panicked.
+func exact(op func() int64) (v int64, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return op(), true
+}
+
+// fits reports whether x is representable as int64.
+func fits(x *big.Int) bool {
+	return x.IsInt64()
+}
+
+func TestProperty_AddSubMatchBigInt(t *testing.T) {
+	add := func(a, b int64) bool {
+		want := new(big.Int).Add(big.NewInt(a), big.NewInt(b))
+		got, ok := exact(func() int64 { return SafeAdd(a, b) })
+		return ok == fits(want) && (!ok || got == want.Int64())
+	}
+	if err := quick.Check(add, propertyConfig); err != nil {
+		t.Error("SafeAdd:", err)
+	}
+
+	sub := func(a, b int64) bool {
+		want := new(big.Int).Sub(big.NewInt(a), big.NewInt(b))
+		got, ok := exact(func() int64 { return SafeSub(a, b) })
+		return ok == fits(want) && (!ok || got == want.Int64())
+	}
+	if err := quick.Check(sub, propertyConfig); err != nil {
+		t.Error("SafeSub:", err)
+	}
+}
+
+func TestProperty_MulMatchesBigInt(t *testing.T) {
+	mul := func(a, b int64) bool {
+		want := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+		got, ok := exact(func() int64 { return SafeMul(a, b) })
+		return ok == fits(want) && (!ok || got == want.Int64())
+	}
+	if err := quick.Check(mul, propertyConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_MulDivMatchesBigInt(t *testing.T) {
+	// a*b/c truncated toward zero, like Go's integer division (big.Int.Quo)
+	mulDiv := func(a, b, c int64) bool {
+		got, ok := exact(func() int64 { return SafeMulDiv(a, b, c) })
+		if c == 0 {
+			return !ok
+		}
+		want := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+		want.Quo(want, big.NewInt(c))
+		return ok == fits(want) && (!ok || got == want.Int64())
+	}
+	if err := quick.Check(mulDiv, propertyConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_AddSubInverse(t *testing.T) {
+	// Whenever a+b does not overflow, subtracting b gives a back
+	inverse := func(a, b int64) bool {
+		sum, ok := exact(func() int64 { return SafeAdd(a, b) })
+		return !ok || SafeSub(sum, b) == a
+	}
+	if err := quick.Check(inverse, propertyConfig); err != nil {
+		t.Error(err)
+	}
+}
@@ -49,6 +49,15 @@ func TestMathPanic(t *testing.T) {
 		SafeAdd(math.MaxInt64, 1)
 	})
 
+	t.Run("MulDiv Overflow", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Should have panicked")
+			}
+		}()
+		SafeMulDiv(math.MaxInt64, 4, 2)
+	})
+
 	t.Run("Div By Zero", func(t *testing.T) {
 		defer func() {
 			if r := recover(); r == nil {
@@ -58,3 +67,18 @@ func TestMathPanic(t *testing.T) {
 		SafeDiv(10, 0)
 	})
 }
+
+func TestSafeMulDiv(t *testing.T) {
+	cases := []struct{ a, b, c, want int64 }{
+		{100_000_000_000_000, 1_000_000, 98_000_000_000_000, 1_020_408}, // 1e20 intermediate
+		{-7, 3, 2, -10},
+		{7, -3, -2, 10},
+		{math.MinInt64, 1, 1, math.MinInt64},
+		{math.MaxInt64, 3, 3, math.MaxInt64},
+	}
+	for _, c := range cases {
+		if got := SafeMulDiv(c.a, c.b, c.c); got != c.want {
+			t.Errorf("SafeMulDiv(%d, %d, %d) = %d, want %d", c.a, c.b, c.c, got, c.want)
+		}
+	}
+}